@@ -0,0 +1,204 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const (
+	RegisterAppComputeUnits   = 1
+	SetAppCreatorComputeUnits = 1
+)
+
+var (
+	ErrAppNameSize   = errors.New("app name size is out of range")
+	ErrAppExists     = errors.New("app already exists")
+	ErrAppNotFound   = errors.New("app not found")
+	ErrNotAppAdmin   = errors.New("actor is not the app admin")
+	ErrNotAppCreator = errors.New("actor may not create assets in the app")
+
+	_ chain.Action = (*RegisterApp)(nil)
+	_ chain.Action = (*SetAppCreator)(nil)
+)
+
+// RegisterApp registers the app [Name] with the actor as its admin. Assets
+// created in it with [CreateAsset] get IDs of their own, so teams sharing a
+// chain do not collide, and only the admin and the creators it allows with
+// [SetAppCreator] can create them.
+type RegisterApp struct {
+	Name string `serialize:"true" json:"name"`
+}
+
+func (*RegisterApp) GetTypeID() uint8 {
+	return mconsts.RegisterAppID
+}
+
+func (r *RegisterApp) StateKeys(codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.AppKey(storage.AppID(r.Name))): state.Read | state.Allocate | state.Write,
+		string(storage.HaltKey()):                     state.Read,
+	}
+}
+
+func (r *RegisterApp) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, r, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if len(r.Name) == 0 || len(r.Name) > storage.MaxAppNameSize {
+		return nil, ErrAppNameSize
+	}
+	app := storage.AppID(r.Name)
+	_, exists, err := storage.GetAppAdmin(ctx, mu, app)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("%w: %s", ErrAppExists, r.Name)
+	}
+	if err := storage.SetAppAdmin(ctx, mu, app, actor); err != nil {
+		return nil, err
+	}
+	return &RegisterAppResult{App: app}, nil
+}
+
+func (*RegisterApp) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.RegisterAppID, RegisterAppComputeUnits)
+}
+
+func (*RegisterApp) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*RegisterAppResult)(nil)
+
+type RegisterAppResult struct {
+	App ids.ID `serialize:"true" json:"app"`
+}
+
+func (*RegisterAppResult) GetTypeID() uint8 {
+	return mconsts.RegisterAppID
+}
+
+// SetAppCreator allows [Creator] to create assets in [App], or stops it.
+// Only the admin of the app can send it. Assets already created stay.
+type SetAppCreator struct {
+	App     ids.ID        `serialize:"true" json:"app"`
+	Creator codec.Address `serialize:"true" json:"creator"`
+	Allowed bool          `serialize:"true" json:"allowed"`
+}
+
+func (*SetAppCreator) GetTypeID() uint8 {
+	return mconsts.SetAppCreatorID
+}
+
+func (s *SetAppCreator) StateKeys(codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.AppKey(s.App)):                   state.Read,
+		string(storage.AppCreatorKey(s.App, s.Creator)): state.All,
+		string(storage.HaltKey()):                       state.Read,
+	}
+}
+
+func (s *SetAppCreator) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, s, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	admin, err := getAppAdmin(ctx, mu, s.App)
+	if err != nil {
+		return nil, err
+	}
+	if actor != admin {
+		return nil, ErrNotAppAdmin
+	}
+	if err := storage.SetAppCreator(ctx, mu, s.App, s.Creator, s.Allowed); err != nil {
+		return nil, err
+	}
+	return &SetAppCreatorResult{Creator: s.Creator, Allowed: s.Allowed}, nil
+}
+
+func (*SetAppCreator) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.SetAppCreatorID, SetAppCreatorComputeUnits)
+}
+
+func (*SetAppCreator) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*SetAppCreatorResult)(nil)
+
+type SetAppCreatorResult struct {
+	Creator codec.Address `serialize:"true" json:"creator"`
+	Allowed bool          `serialize:"true" json:"allowed"`
+}
+
+func (*SetAppCreatorResult) GetTypeID() uint8 {
+	return mconsts.SetAppCreatorID
+}
+
+func getAppAdmin(ctx context.Context, im state.Immutable, app ids.ID) (codec.Address, error) {
+	admin, ok, err := storage.GetAppAdmin(ctx, im, app)
+	if err != nil {
+		return codec.EmptyAddress, err
+	}
+	if !ok {
+		return codec.EmptyAddress, fmt.Errorf("%w: %s", ErrAppNotFound, app)
+	}
+	return admin, nil
+}
+
+// checkAppCreator rejects [creator] unless it is the admin of [app] or one
+// of the creators the admin allowed.
+func checkAppCreator(ctx context.Context, im state.Immutable, app ids.ID, creator codec.Address) error {
+	admin, err := getAppAdmin(ctx, im, app)
+	if err != nil {
+		return err
+	}
+	if creator == admin {
+		return nil
+	}
+	allowed, err := storage.IsAppCreator(ctx, im, app, creator)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrNotAppCreator
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestRegisterAppAction(t *testing.T) {
+	admin := codectest.NewRandomAddress()
+	app := storage.AppID("team")
+	require.NotEqual(t, app, storage.AppID("team2"))
+
+	// registered has [app] with [admin] as its admin.
+	registered := func() state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetAppAdmin(context.Background(), store, app, admin))
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:   "Register",
+			Actor:  admin,
+			Action: &RegisterApp{Name: "team"},
+			State:  chaintest.NewInMemoryStore(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				got, exists, err := storage.GetAppAdmin(ctx, store, app)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, admin, got)
+			},
+			ExpectedOutputs: &RegisterAppResult{App: app},
+		},
+		{
+			// Names are first come, first served.
+			Name:        "Taken",
+			Actor:       codectest.NewRandomAddress(),
+			Action:      &RegisterApp{Name: "team"},
+			State:       registered(),
+			ExpectedErr: ErrAppExists,
+		},
+		{
+			Name:        "EmptyName",
+			Actor:       admin,
+			Action:      &RegisterApp{},
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: ErrAppNameSize,
+		},
+		{
+			Name:        "NameTooLong",
+			Actor:       admin,
+			Action:      &RegisterApp{Name: strings.Repeat("a", storage.MaxAppNameSize+1)},
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: ErrAppNameSize,
+		},
+	}
+
+	runActionTests(t, tests)
+}
+
+func TestSetAppCreatorAction(t *testing.T) {
+	admin := codectest.NewRandomAddress()
+	creator := codectest.NewRandomAddress()
+	app := storage.AppID("team")
+
+	// registered has [app] with [admin] as its admin, and [creator] allowed
+	// to create assets in it.
+	registered := func() state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetAppAdmin(ctx, store, app, admin))
+		require.NoError(t, storage.SetAppCreator(ctx, store, app, creator, true))
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:   "Allow",
+			Actor:  admin,
+			Action: &SetAppCreator{App: app, Creator: creator, Allowed: true},
+			State: func() state.Mutable {
+				store := chaintest.NewInMemoryStore()
+				require.NoError(t, storage.SetAppAdmin(context.Background(), store, app, admin))
+				return store
+			}(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				allowed, err := storage.IsAppCreator(ctx, store, app, creator)
+				require.NoError(t, err)
+				require.True(t, allowed)
+			},
+			ExpectedOutputs: &SetAppCreatorResult{Creator: creator, Allowed: true},
+		},
+		{
+			Name:   "Remove",
+			Actor:  admin,
+			Action: &SetAppCreator{App: app, Creator: creator},
+			State:  registered(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				allowed, err := storage.IsAppCreator(ctx, store, app, creator)
+				require.NoError(t, err)
+				require.False(t, allowed)
+			},
+			ExpectedOutputs: &SetAppCreatorResult{Creator: creator},
+		},
+		{
+			// Creators cannot allow others.
+			Name:        "NotAdmin",
+			Actor:       creator,
+			Action:      &SetAppCreator{App: app, Creator: codectest.NewRandomAddress(), Allowed: true},
+			State:       registered(),
+			ExpectedErr: ErrNotAppAdmin,
+		},
+		{
+			Name:        "AppNotFound",
+			Actor:       admin,
+			Action:      &SetAppCreator{App: storage.AppID("other"), Creator: creator, Allowed: true},
+			State:       registered(),
+			ExpectedErr: ErrAppNotFound,
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -0,0 +1,175 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const (
+	ApproveComputeUnits      = 1
+	TransferFromComputeUnits = 1
+)
+
+var (
+	_ chain.Action = (*Approve)(nil)
+	_ chain.Action = (*TransferFrom)(nil)
+)
+
+// Approve lets [Spender] transfer up to [Value] of the actor's balance with
+// [TransferFrom]. It replaces any previous allowance; zero revokes it.
+type Approve struct {
+	Spender codec.Address `serialize:"true" json:"spender"`
+	Value   uint64        `serialize:"true" json:"value"`
+}
+
+func (*Approve) GetTypeID() uint8 {
+	return mconsts.ApproveID
+}
+
+func (a *Approve) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.AllowanceKey(actor, a.Spender)): state.All,
+		string(storage.HaltKey()):                      state.Read,
+	}
+}
+
+func (a *Approve) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, a, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if err := storage.SetAllowance(ctx, mu, actor, a.Spender, a.Value); err != nil {
+		return nil, err
+	}
+	return &ApproveResult{
+		Allowance: a.Value,
+	}, nil
+}
+
+func (*Approve) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.ApproveID, ApproveComputeUnits)
+}
+
+func (*Approve) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*ApproveResult)(nil)
+
+type ApproveResult struct {
+	Allowance uint64 `serialize:"true" json:"allowance"`
+}
+
+func (*ApproveResult) GetTypeID() uint8 {
+	return mconsts.ApproveID
+}
+
+// TransferFrom moves [Value] from [From] to [To] on behalf of [From], spending
+// the allowance [From] granted the actor with [Approve].
+type TransferFrom struct {
+	From  codec.Address `serialize:"true" json:"from"`
+	To    codec.Address `serialize:"true" json:"to"`
+	Value uint64        `serialize:"true" json:"value"`
+}
+
+func (*TransferFrom) GetTypeID() uint8 {
+	return mconsts.TransferFromID
+}
+
+func (t *TransferFrom) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.AllowanceKey(t.From, actor)): state.Read | state.Write,
+		string(storage.BalanceKey(t.From)):          state.Read | state.Write,
+		string(storage.BalanceKey(t.To)):            state.All,
+		string(storage.ActiveProposalKey()):         state.Read,
+		string(storage.HaltKey()):                   state.Read,
+	}
+}
+
+func (t *TransferFrom) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, t, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if t.Value == 0 {
+		return nil, ErrOutputValueZero
+	}
+	allowance, err := storage.ConsumeAllowance(ctx, mu, t.From, actor, t.Value)
+	if err != nil {
+		return nil, err
+	}
+	senderBalance, err := storage.SubBalance(ctx, mu, t.From, t.Value)
+	if err != nil {
+		return nil, err
+	}
+	receiverBalance, err := storage.AddBalance(ctx, mu, t.To, t.Value, true)
+	if err != nil {
+		return nil, err
+	}
+	if senderBalance, err = settleDust(ctx, r, mu, t.From, senderBalance, receiverBalance); err != nil {
+		return nil, err
+	}
+	return &TransferFromResult{
+		SenderBalance:   senderBalance,
+		ReceiverBalance: receiverBalance,
+		Allowance:       allowance,
+	}, nil
+}
+
+func (*TransferFrom) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.TransferFromID, TransferFromComputeUnits)
+}
+
+func (*TransferFrom) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (t *TransferFrom) EmitEvents(_ codec.Address, _ codec.Typed, e Emitter) {
+	e.Emit(&TransferEvent{From: t.From, To: t.To, Asset: storage.NativeAsset, Amount: t.Value})
+}
+
+var _ codec.Typed = (*TransferFromResult)(nil)
+
+type TransferFromResult struct {
+	SenderBalance   uint64 `serialize:"true" json:"sender_balance"`
+	ReceiverBalance uint64 `serialize:"true" json:"receiver_balance"`
+	// Allowance is what the actor may still spend from the sender.
+	Allowance uint64 `serialize:"true" json:"allowance"`
+}
+
+func (*TransferFromResult) GetTypeID() uint8 {
+	return mconsts.TransferFromID
+}
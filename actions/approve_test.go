@@ -0,0 +1,80 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestTransferFromAction(t *testing.T) {
+	owner := codectest.NewRandomAddress()
+	spender := codectest.NewRandomAddress()
+	to := codectest.NewRandomAddress()
+
+	approved := func(allowance uint64) state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(ctx, store, owner, 10))
+		require.NoError(t, storage.SetAllowance(ctx, store, owner, spender, allowance))
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "NoAllowance",
+			Actor: spender,
+			Action: &TransferFrom{
+				From:  owner,
+				To:    to,
+				Value: 1,
+			},
+			State:       approved(0),
+			ExpectedErr: storage.ErrInsufficientAllowance,
+		},
+		{
+			Name:  "AllowanceExceeded",
+			Actor: spender,
+			Action: &TransferFrom{
+				From:  owner,
+				To:    to,
+				Value: 5,
+			},
+			State:       approved(4),
+			ExpectedErr: storage.ErrInsufficientAllowance,
+		},
+		{
+			Name:  "SpendAllowance",
+			Actor: spender,
+			Action: &TransferFrom{
+				From:  owner,
+				To:    to,
+				Value: 3,
+			},
+			State: approved(4),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				allowance, err := storage.GetAllowance(ctx, store, owner, spender)
+				require.NoError(t, err)
+				require.Equal(t, uint64(1), allowance)
+				balance, err := storage.GetBalance(ctx, store, to)
+				require.NoError(t, err)
+				require.Equal(t, uint64(3), balance)
+			},
+			ExpectedOutputs: &TransferFromResult{
+				SenderBalance:   7,
+				ReceiverBalance: 3,
+				Allowance:       1,
+			},
+		},
+	}
+
+	runActionTests(t, tests)
+}
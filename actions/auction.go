@@ -0,0 +1,396 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const AuctionComputeUnits = 1
+
+var (
+	ErrAuctionNotFound  = errors.New("auction not found")
+	ErrAuctionMismatch  = errors.New("auction does not match")
+	ErrAuctionEnded     = errors.New("auction has ended")
+	ErrAuctionNotEnded  = errors.New("auction has not ended")
+	ErrBidTooLow        = errors.New("bid must reach the minimum and beat the top bid")
+	ErrExpiresInAuction = errors.New("asset expires before the auction ends")
+
+	_ chain.Action = (*CreateAuction)(nil)
+	_ chain.Action = (*PlaceBid)(nil)
+	_ chain.Action = (*SettleAuction)(nil)
+)
+
+// CreateAuction moves [Asset] from the actor into an English auction, held
+// at storage.AuctionAddress of the asset until it is settled.
+type CreateAuction struct {
+	Asset  ids.ID `serialize:"true" json:"asset"`
+	MinBid uint64 `serialize:"true" json:"min_bid"`
+	// End is the last timestamp, in milliseconds, at which bids are taken.
+	// After it, anyone can settle the auction with [SettleAuction].
+	End int64 `serialize:"true" json:"end"`
+}
+
+func (*CreateAuction) GetTypeID() uint8 {
+	return mconsts.CreateAuctionID
+}
+
+func (c *CreateAuction) StateKeys(actor codec.Address) state.Keys {
+	keys := state.Keys{string(storage.AuctionKey(c.Asset)): state.Allocate | state.Write}
+	addItemKeys(keys, c.Asset, actor, storage.AuctionAddress(c.Asset))
+	addTransferHookKeys(keys, actor, c.Asset)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (c *CreateAuction) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, c, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if c.End < timestamp {
+		return nil, ErrDeadlineInThePast
+	}
+	owner, err := storage.GetAssetOwner(ctx, mu, c.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if owner != actor {
+		return nil, ErrAssetNotOwned
+	}
+	control, err := storage.GetAssetControl(ctx, mu, c.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if control.Frozen {
+		return nil, ErrAssetFrozen
+	}
+	if control.Soulbound {
+		return nil, ErrAssetSoulbound
+	}
+	if control.Expired(timestamp) {
+		return nil, ErrAssetExpired
+	}
+	if control.Expired(c.End) {
+		return nil, ErrExpiresInAuction
+	}
+	if _, err := runTransferHook(ctx, r, mu, actor, c.Asset); err != nil {
+		return nil, err
+	}
+	custody := storage.AuctionAddress(c.Asset)
+	if err := storage.ChangeAssetOwner(ctx, mu, c.Asset, custody); err != nil {
+		return nil, err
+	}
+	if err := storage.SetAuction(ctx, mu, c.Asset, &storage.Auction{
+		Seller: actor,
+		End:    c.End,
+		MinBid: c.MinBid,
+	}); err != nil {
+		return nil, err
+	}
+	return &CreateAuctionResult{Custody: custody}, nil
+}
+
+func (*CreateAuction) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.CreateAuctionID, AuctionComputeUnits)
+}
+
+func (*CreateAuction) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (c *CreateAuction) EmitEvents(actor codec.Address, _ codec.Typed, e Emitter) {
+	e.Emit(&OwnershipEvent{Asset: c.Asset, From: actor, To: storage.AuctionAddress(c.Asset)})
+}
+
+var _ codec.Typed = (*CreateAuctionResult)(nil)
+
+type CreateAuctionResult struct {
+	// Custody holds the asset and the top bid until settlement.
+	Custody codec.Address `serialize:"true" json:"custody"`
+}
+
+func (*CreateAuctionResult) GetTypeID() uint8 {
+	return mconsts.CreateAuctionID
+}
+
+// PlaceBid locks [Amount] of the actor's native tokens as the top bid of the
+// auction of [Asset], refunding the previous top bid. It must reach the
+// minimum bid and beat the top bid.
+type PlaceBid struct {
+	Asset  ids.ID `serialize:"true" json:"asset"`
+	Amount uint64 `serialize:"true" json:"amount"`
+	// PreviousBidder must match the top bidder of the auction, or be empty
+	// if there is none, so the refund can be declared in [StateKeys].
+	PreviousBidder codec.Address `serialize:"true" json:"previous_bidder"`
+}
+
+func (*PlaceBid) GetTypeID() uint8 {
+	return mconsts.PlaceBidID
+}
+
+func (p *PlaceBid) StateKeys(actor codec.Address) state.Keys {
+	keys := state.Keys{string(storage.AuctionKey(p.Asset)): state.Read | state.Write}
+	custody := storage.AuctionAddress(p.Asset)
+	addLegKeys(keys, storage.SwapLeg{Asset: storage.NativeAsset}, actor, custody)
+	if p.PreviousBidder != codec.EmptyAddress {
+		addLegKeys(keys, storage.SwapLeg{Asset: storage.NativeAsset}, custody, p.PreviousBidder)
+	}
+	addBlockKeys(keys, p.Asset, actor)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (p *PlaceBid) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, p, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if p.Amount == 0 {
+		return nil, ErrOutputValueZero
+	}
+	// A bidder the asset's blocklist names could not receive it.
+	if err := checkNotBlocked(ctx, mu, p.Asset, actor); err != nil {
+		return nil, err
+	}
+	a, err := getAuction(ctx, mu, p.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if a.End < timestamp {
+		return nil, ErrAuctionEnded
+	}
+	if a.Bidder != p.PreviousBidder {
+		return nil, ErrAuctionMismatch
+	}
+	if p.Amount < a.MinBid || (a.Bidder != codec.EmptyAddress && p.Amount <= a.Bid) {
+		return nil, ErrBidTooLow
+	}
+	custody := storage.AuctionAddress(p.Asset)
+	if a.Bidder != codec.EmptyAddress {
+		if err := storage.MoveLeg(ctx, mu, custody, a.Bidder, storage.SwapLeg{Asset: storage.NativeAsset, Amount: a.Bid}); err != nil {
+			return nil, err
+		}
+	}
+	if err := storage.MoveLeg(ctx, mu, actor, custody, storage.SwapLeg{Asset: storage.NativeAsset, Amount: p.Amount}); err != nil {
+		return nil, err
+	}
+	refunded := a.Bid
+	a.Bidder, a.Bid = actor, p.Amount
+	if err := storage.SetAuction(ctx, mu, p.Asset, a); err != nil {
+		return nil, err
+	}
+	return &PlaceBidResult{Refunded: refunded}, nil
+}
+
+func (*PlaceBid) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.PlaceBidID, AuctionComputeUnits)
+}
+
+func (*PlaceBid) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (p *PlaceBid) EmitEvents(actor codec.Address, output codec.Typed, e Emitter) {
+	custody := storage.AuctionAddress(p.Asset)
+	if r, ok := output.(*PlaceBidResult); ok && r.Refunded > 0 {
+		e.Emit(&TransferEvent{From: custody, To: p.PreviousBidder, Asset: storage.NativeAsset, Amount: r.Refunded})
+	}
+	e.Emit(&TransferEvent{From: actor, To: custody, Asset: storage.NativeAsset, Amount: p.Amount})
+}
+
+var _ codec.Typed = (*PlaceBidResult)(nil)
+
+type PlaceBidResult struct {
+	// Refunded is the previous top bid, returned to its bidder.
+	Refunded uint64 `serialize:"true" json:"refunded"`
+}
+
+func (*PlaceBidResult) GetTypeID() uint8 {
+	return mconsts.PlaceBidID
+}
+
+// SettleAuction closes the auction of [Asset] once its end has passed. The
+// asset goes to the top bidder and the top bid to the seller, less the
+// asset's royalty on it, or the asset back to the seller if there was no
+// bid. Anyone can send it.
+type SettleAuction struct {
+	Asset ids.ID `serialize:"true" json:"asset"`
+	// Seller and Bidder must match the auction, so the records settlement
+	// moves can be declared in [StateKeys]. Bidder is empty if there was no
+	// bid.
+	Seller codec.Address `serialize:"true" json:"seller"`
+	Bidder codec.Address `serialize:"true" json:"bidder"`
+	// RoyaltyPayee must be the royalty payee of [Asset] whenever a royalty
+	// is due on the top bid.
+	RoyaltyPayee codec.Address `serialize:"true" json:"royalty_payee"`
+}
+
+func (*SettleAuction) GetTypeID() uint8 {
+	return mconsts.SettleAuctionID
+}
+
+func (s *SettleAuction) StateKeys(codec.Address) state.Keys {
+	keys := state.Keys{string(storage.AuctionKey(s.Asset)): state.Read | state.Write}
+	custody := storage.AuctionAddress(s.Asset)
+	addItemKeys(keys, s.Asset, custody, s.winner())
+	if s.Bidder != codec.EmptyAddress {
+		addLegKeys(keys, storage.SwapLeg{Asset: storage.NativeAsset}, custody, s.Seller)
+		keys.Add(string(storage.BalanceKey(s.RoyaltyPayee)), state.All)
+	}
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+// winner is who receives the asset.
+func (s *SettleAuction) winner() codec.Address {
+	if s.Bidder == codec.EmptyAddress {
+		return s.Seller
+	}
+	return s.Bidder
+}
+
+func (s *SettleAuction) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, s, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	a, err := getAuction(ctx, mu, s.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if a.Seller != s.Seller || a.Bidder != s.Bidder {
+		return nil, ErrAuctionMismatch
+	}
+	if a.End >= timestamp {
+		return nil, ErrAuctionNotEnded
+	}
+	// Like refunds, settlement skips transfer hooks, freezes and expiry, so
+	// nothing set while the asset was auctioned can strand it or the bid.
+	custody := storage.AuctionAddress(s.Asset)
+	result := &SettleAuctionResult{Winner: s.winner(), Paid: a.Bid}
+	if a.Bid > 0 {
+		// The top bid is the sale price, so it owes the royalty a priced
+		// AssetTransfer would.
+		control, err := storage.GetAssetControl(ctx, mu, s.Asset)
+		if err != nil {
+			return nil, err
+		}
+		result.Royalty = Royalty(a.Bid, control.RoyaltyBasisPoints)
+		if result.Royalty > 0 {
+			if s.RoyaltyPayee != control.RoyaltyPayee {
+				return nil, ErrWrongRoyaltyPayee
+			}
+			if err := storage.MoveLeg(ctx, mu, custody, control.RoyaltyPayee, storage.SwapLeg{Asset: storage.NativeAsset, Amount: result.Royalty}); err != nil {
+				return nil, err
+			}
+			result.RoyaltyPayee = control.RoyaltyPayee
+		}
+		if err := storage.MoveLeg(ctx, mu, custody, a.Seller, storage.SwapLeg{Asset: storage.NativeAsset, Amount: a.Bid - result.Royalty}); err != nil {
+			return nil, err
+		}
+	}
+	if err := storage.ChangeAssetOwner(ctx, mu, s.Asset, result.Winner); err != nil {
+		return nil, err
+	}
+	if err := storage.DeleteAuction(ctx, mu, s.Asset); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (*SettleAuction) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.SettleAuctionID, AuctionComputeUnits)
+}
+
+func (*SettleAuction) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (s *SettleAuction) EmitEvents(_ codec.Address, output codec.Typed, e Emitter) {
+	r, ok := output.(*SettleAuctionResult)
+	if !ok {
+		return
+	}
+	custody := storage.AuctionAddress(s.Asset)
+	if r.Royalty > 0 {
+		e.Emit(&TransferEvent{From: custody, To: r.RoyaltyPayee, Asset: storage.NativeAsset, Amount: r.Royalty})
+	}
+	if r.Paid > r.Royalty {
+		e.Emit(&TransferEvent{From: custody, To: s.Seller, Asset: storage.NativeAsset, Amount: r.Paid - r.Royalty})
+	}
+	e.Emit(&OwnershipEvent{Asset: s.Asset, From: custody, To: r.Winner})
+}
+
+var _ codec.Typed = (*SettleAuctionResult)(nil)
+
+type SettleAuctionResult struct {
+	// Winner received the asset: the top bidder, or the seller if there was
+	// no bid.
+	Winner codec.Address `serialize:"true" json:"winner"`
+	// Paid is the top bid. Royalty of it went to RoyaltyPayee and the rest
+	// to the seller.
+	Paid         uint64        `serialize:"true" json:"paid"`
+	Royalty      uint64        `serialize:"true" json:"royalty"`
+	RoyaltyPayee codec.Address `serialize:"true" json:"royalty_payee"`
+}
+
+func (*SettleAuctionResult) GetTypeID() uint8 {
+	return mconsts.SettleAuctionID
+}
+
+// getAuction returns the auction of [asset], failing if there is none.
+func getAuction(ctx context.Context, im state.Immutable, asset ids.ID) (*storage.Auction, error) {
+	a, exists, err := storage.GetAuction(ctx, im, asset)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrAuctionNotFound
+	}
+	return a, nil
+}
@@ -0,0 +1,276 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestAuctionActions(t *testing.T) {
+	seller := codectest.NewRandomAddress()
+	alice := codectest.NewRandomAddress()
+	bob := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+	custody := storage.AuctionAddress(asset)
+
+	held := func() state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.CreateAsset(context.Background(), store, asset, seller))
+		return store
+	}
+	// auctioned has [asset] in an auction ending at 100 with a minimum bid
+	// of 10, and 50 for each of [alice] and [bob]. If [bidder] is set, it
+	// holds the top bid of [bid].
+	auctioned := func(bidder codec.Address, bid uint64) state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.CreateAsset(ctx, store, asset, custody))
+		require.NoError(t, storage.SetAuction(ctx, store, asset, &storage.Auction{
+			Seller: seller,
+			End:    100,
+			MinBid: 10,
+			Bidder: bidder,
+			Bid:    bid,
+		}))
+		require.NoError(t, storage.SetBalance(ctx, store, alice, 50))
+		require.NoError(t, storage.SetBalance(ctx, store, bob, 50))
+		if bid > 0 {
+			require.NoError(t, storage.SetBalance(ctx, store, custody, bid))
+		}
+		return store
+	}
+	// withRoyalty is [auctioned] for an asset with a 2.5% royalty to
+	// [payee].
+	payee := codectest.NewRandomAddress()
+	withRoyalty := func(bidder codec.Address, bid uint64) state.Mutable {
+		store := auctioned(bidder, bid)
+		require.NoError(t, storage.SetAssetRoyalty(context.Background(), store, asset, 250, payee))
+		return store
+	}
+	balances := func(ctx context.Context, t *testing.T, store state.Mutable, want map[codec.Address]uint64) {
+		for addr, amount := range want {
+			balance, err := storage.GetBalance(ctx, store, addr)
+			require.NoError(t, err)
+			require.Equal(t, amount, balance)
+		}
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:      "Create",
+			Actor:     seller,
+			Action:    &CreateAuction{Asset: asset, MinBid: 10, End: 100},
+			State:     held(),
+			Timestamp: 50,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				owner, err := storage.GetAssetOwner(ctx, store, asset)
+				require.NoError(t, err)
+				require.Equal(t, custody, owner)
+				a, exists, err := storage.GetAuction(ctx, store, asset)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.Auction{Seller: seller, End: 100, MinBid: 10}, a)
+			},
+			ExpectedOutputs: &CreateAuctionResult{Custody: custody},
+		},
+		{
+			Name:        "CreateNotOwned",
+			Actor:       alice,
+			Action:      &CreateAuction{Asset: asset, MinBid: 10, End: 100},
+			State:       held(),
+			ExpectedErr: ErrAssetNotOwned,
+		},
+		{
+			Name:        "CreateEndInThePast",
+			Actor:       seller,
+			Action:      &CreateAuction{Asset: asset, MinBid: 10, End: 100},
+			State:       held(),
+			Timestamp:   101,
+			ExpectedErr: ErrDeadlineInThePast,
+		},
+		{
+			Name:   "CreateFrozen",
+			Actor:  seller,
+			Action: &CreateAuction{Asset: asset, MinBid: 10, End: 100},
+			State: func() state.Mutable {
+				store := held()
+				require.NoError(t, storage.SetAssetFrozen(context.Background(), store, asset, true))
+				return store
+			}(),
+			ExpectedErr: ErrAssetFrozen,
+		},
+		{
+			Name:   "CreateExpiresFirst",
+			Actor:  seller,
+			Action: &CreateAuction{Asset: asset, MinBid: 10, End: 100},
+			State: func() state.Mutable {
+				store := chaintest.NewInMemoryStore()
+				require.NoError(t, storage.CreateAssetWithControl(context.Background(), store, asset, storage.AssetControl{Minter: seller, Expiry: 99}, seller))
+				return store
+			}(),
+			ExpectedErr: ErrExpiresInAuction,
+		},
+		{
+			Name:      "FirstBid",
+			Actor:     alice,
+			Action:    &PlaceBid{Asset: asset, Amount: 10},
+			State:     auctioned(codec.EmptyAddress, 0),
+			Timestamp: 100,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balances(ctx, t, store, map[codec.Address]uint64{alice: 40, custody: 10})
+				a, _, err := storage.GetAuction(ctx, store, asset)
+				require.NoError(t, err)
+				require.Equal(t, alice, a.Bidder)
+				require.Equal(t, uint64(10), a.Bid)
+			},
+			ExpectedOutputs: &PlaceBidResult{},
+		},
+		{
+			Name:   "Outbid",
+			Actor:  bob,
+			Action: &PlaceBid{Asset: asset, Amount: 25, PreviousBidder: alice},
+			State:  auctioned(alice, 20),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balances(ctx, t, store, map[codec.Address]uint64{alice: 70, bob: 25, custody: 25})
+			},
+			ExpectedOutputs: &PlaceBidResult{Refunded: 20},
+		},
+		{
+			Name:   "RaiseOwnBid",
+			Actor:  alice,
+			Action: &PlaceBid{Asset: asset, Amount: 30, PreviousBidder: alice},
+			State:  auctioned(alice, 20),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balances(ctx, t, store, map[codec.Address]uint64{alice: 40, custody: 30})
+			},
+			ExpectedOutputs: &PlaceBidResult{Refunded: 20},
+		},
+		{
+			Name:        "BelowMinimum",
+			Actor:       alice,
+			Action:      &PlaceBid{Asset: asset, Amount: 9},
+			State:       auctioned(codec.EmptyAddress, 0),
+			ExpectedErr: ErrBidTooLow,
+		},
+		{
+			Name:        "NotAboveTopBid",
+			Actor:       bob,
+			Action:      &PlaceBid{Asset: asset, Amount: 20, PreviousBidder: alice},
+			State:       auctioned(alice, 20),
+			ExpectedErr: ErrBidTooLow,
+		},
+		{
+			Name:        "StalePreviousBidder",
+			Actor:       bob,
+			Action:      &PlaceBid{Asset: asset, Amount: 25},
+			State:       auctioned(alice, 20),
+			ExpectedErr: ErrAuctionMismatch,
+		},
+		{
+			Name:        "BidAfterEnd",
+			Actor:       alice,
+			Action:      &PlaceBid{Asset: asset, Amount: 10},
+			State:       auctioned(codec.EmptyAddress, 0),
+			Timestamp:   101,
+			ExpectedErr: ErrAuctionEnded,
+		},
+		{
+			Name:        "BidNoAuction",
+			Actor:       alice,
+			Action:      &PlaceBid{Asset: asset, Amount: 10},
+			State:       held(),
+			ExpectedErr: ErrAuctionNotFound,
+		},
+		{
+			Name:      "Settle",
+			Actor:     bob,
+			Action:    &SettleAuction{Asset: asset, Seller: seller, Bidder: alice},
+			State:     auctioned(alice, 20),
+			Timestamp: 101,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				owner, err := storage.GetAssetOwner(ctx, store, asset)
+				require.NoError(t, err)
+				require.Equal(t, alice, owner)
+				balances(ctx, t, store, map[codec.Address]uint64{seller: 20, custody: 0})
+				_, exists, err := storage.GetAuction(ctx, store, asset)
+				require.NoError(t, err)
+				require.False(t, exists)
+			},
+			ExpectedOutputs: &SettleAuctionResult{Winner: alice, Paid: 20},
+		},
+		{
+			Name:      "SettlePaysRoyalty",
+			Actor:     bob,
+			Action:    &SettleAuction{Asset: asset, Seller: seller, Bidder: alice, RoyaltyPayee: payee},
+			State:     withRoyalty(alice, 400),
+			Timestamp: 101,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balances(ctx, t, store, map[codec.Address]uint64{seller: 390, payee: 10, custody: 0})
+			},
+			ExpectedOutputs: &SettleAuctionResult{Winner: alice, Paid: 400, Royalty: 10, RoyaltyPayee: payee},
+		},
+		{
+			Name:        "SettleWrongRoyaltyPayee",
+			Actor:       bob,
+			Action:      &SettleAuction{Asset: asset, Seller: seller, Bidder: alice, RoyaltyPayee: bob},
+			State:       withRoyalty(alice, 400),
+			Timestamp:   101,
+			ExpectedErr: ErrWrongRoyaltyPayee,
+		},
+		{
+			// The royalty on a bid of 20 rounds down to nothing, so no
+			// payee is needed.
+			Name:      "SettleRoyaltyRoundsDown",
+			Actor:     bob,
+			Action:    &SettleAuction{Asset: asset, Seller: seller, Bidder: alice},
+			State:     withRoyalty(alice, 20),
+			Timestamp: 101,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balances(ctx, t, store, map[codec.Address]uint64{seller: 20, payee: 0})
+			},
+			ExpectedOutputs: &SettleAuctionResult{Winner: alice, Paid: 20},
+		},
+		{
+			Name:      "SettleWithoutBids",
+			Actor:     bob,
+			Action:    &SettleAuction{Asset: asset, Seller: seller},
+			State:     auctioned(codec.EmptyAddress, 0),
+			Timestamp: 101,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				owner, err := storage.GetAssetOwner(ctx, store, asset)
+				require.NoError(t, err)
+				require.Equal(t, seller, owner)
+			},
+			ExpectedOutputs: &SettleAuctionResult{Winner: seller},
+		},
+		{
+			Name:        "SettleBeforeEnd",
+			Actor:       bob,
+			Action:      &SettleAuction{Asset: asset, Seller: seller, Bidder: alice},
+			State:       auctioned(alice, 20),
+			Timestamp:   100,
+			ExpectedErr: ErrAuctionNotEnded,
+		},
+		{
+			Name:        "SettleMismatch",
+			Actor:       bob,
+			Action:      &SettleAuction{Asset: asset, Seller: seller, Bidder: bob},
+			State:       auctioned(alice, 20),
+			Timestamp:   101,
+			ExpectedErr: ErrAuctionMismatch,
+		},
+	}
+
+	runActionTests(t, tests)
+}
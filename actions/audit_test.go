@@ -0,0 +1,41 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+
+	mchaintest "github.com/ava-labs/hypersdk-starter-kit/chaintest"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+)
+
+// audit checks the action tests of the package against the state keys
+// their actions declare.
+var audit = mchaintest.NewKeyAudit()
+
+// runActionTests runs [tests] like [chaintest.ActionTest.Run], through
+// [audit].
+func runActionTests(t *testing.T, tests []chaintest.ActionTest) {
+	for _, test := range tests {
+		audit.Run(context.Background(), t, test)
+	}
+}
+
+// TestMain fails the package if an action declares a permission that none
+// of its tests needed. An action's paths are spread over several tests, so
+// this is only checked when they all run.
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if code == 0 && flag.Lookup("test.run").Value.String() == "" {
+		for _, excess := range audit.Excess() {
+			fmt.Fprintln(os.Stderr, "key audit:", excess)
+			code = 1
+		}
+	}
+	os.Exit(code)
+}
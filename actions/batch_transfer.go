@@ -0,0 +1,153 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const MaxBatchTransfers = 64
+
+var (
+	ErrEmptyBatch                 = errors.New("batch is empty")
+	ErrBatchTooLarge              = errors.New("batch is too large")
+	_                chain.Action = (*BatchTransfer)(nil)
+)
+
+type BatchTransferEntry struct {
+	To    codec.Address `serialize:"true" json:"to"`
+	Value uint64        `serialize:"true" json:"value"`
+}
+
+// BatchTransfer sends native tokens to up to [MaxBatchTransfers] recipients.
+// Either every transfer is applied or none are.
+type BatchTransfer struct {
+	Transfers []BatchTransferEntry `serialize:"true" json:"transfers"`
+}
+
+func (*BatchTransfer) GetTypeID() uint8 {
+	return mconsts.BatchTransferID
+}
+
+func (b *BatchTransfer) StateKeys(actor codec.Address) state.Keys {
+	keys := make(state.Keys, len(b.Transfers)+1)
+	for _, t := range b.Transfers {
+		keys.Add(string(storage.BalanceKey(t.To)), state.All)
+	}
+	keys.Add(string(storage.BalanceKey(actor)), state.Read|state.Write)
+	keys.Add(string(storage.ActiveProposalKey()), state.Read)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (b *BatchTransfer) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, b, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if len(b.Transfers) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	if len(b.Transfers) > MaxBatchTransfers {
+		return nil, ErrBatchTooLarge
+	}
+	var (
+		total uint64
+		err   error
+	)
+	for _, t := range b.Transfers {
+		if t.Value == 0 {
+			return nil, ErrOutputValueZero
+		}
+		total, err = smath.Add(total, t.Value)
+		if err != nil {
+			return nil, storage.ErrInvalidBalance
+		}
+	}
+	senderBalance, err := storage.SubBalance(ctx, mu, actor, total)
+	if err != nil {
+		return nil, err
+	}
+	receiverBalances := make([]uint64, len(b.Transfers))
+	for i, t := range b.Transfers {
+		receiverBalances[i], err = storage.AddBalance(ctx, mu, t.To, t.Value, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range b.Transfers {
+		if t.To == actor {
+			// The actor was credited after being debited.
+			senderBalance, err = storage.GetBalance(ctx, mu, actor)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	// A recipient paid more than once only ends with its last balance.
+	final := make(map[codec.Address]uint64, len(b.Transfers))
+	for i, t := range b.Transfers {
+		final[t.To] = receiverBalances[i]
+	}
+	finalBalances := make([]uint64, 0, len(final))
+	for _, balance := range final {
+		finalBalances = append(finalBalances, balance)
+	}
+	if senderBalance, err = settleDust(ctx, r, mu, actor, senderBalance, finalBalances...); err != nil {
+		return nil, err
+	}
+	return &BatchTransferResult{
+		SenderBalance:    senderBalance,
+		ReceiverBalances: receiverBalances,
+	}, nil
+}
+
+func (b *BatchTransfer) ComputeUnits(r chain.Rules) uint64 {
+	return uint64(len(b.Transfers)) * baseComputeUnits(r, mconsts.BatchTransferID, TransferComputeUnits)
+}
+
+func (*BatchTransfer) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (b *BatchTransfer) EmitEvents(actor codec.Address, _ codec.Typed, e Emitter) {
+	for _, t := range b.Transfers {
+		e.Emit(&TransferEvent{From: actor, To: t.To, Asset: storage.NativeAsset, Amount: t.Value})
+	}
+}
+
+var _ codec.Typed = (*BatchTransferResult)(nil)
+
+type BatchTransferResult struct {
+	SenderBalance uint64 `serialize:"true" json:"sender_balance"`
+	// ReceiverBalances[i] is the balance of Transfers[i].To after its transfer.
+	ReceiverBalances []uint64 `serialize:"true" json:"receiver_balances"`
+}
+
+func (*BatchTransferResult) GetTypeID() uint8 {
+	return mconsts.BatchTransferID
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestBatchTransferAction(t *testing.T) {
+	a := codectest.NewRandomAddress()
+	b := codectest.NewRandomAddress()
+
+	funded := func(balance uint64) state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(context.Background(), store, codec.EmptyAddress, balance))
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:        "Empty",
+			Actor:       codec.EmptyAddress,
+			Action:      &BatchTransfer{},
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: ErrEmptyBatch,
+		},
+		{
+			Name:  "TooLarge",
+			Actor: codec.EmptyAddress,
+			Action: &BatchTransfer{
+				Transfers: make([]BatchTransferEntry, MaxBatchTransfers+1),
+			},
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: ErrBatchTooLarge,
+		},
+		{
+			Name:  "TotalOverflow",
+			Actor: codec.EmptyAddress,
+			Action: &BatchTransfer{
+				Transfers: []BatchTransferEntry{
+					{To: a, Value: math.MaxUint64},
+					{To: b, Value: 1},
+				},
+			},
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: storage.ErrInvalidBalance,
+		},
+		{
+			Name:  "NotEnoughBalance",
+			Actor: codec.EmptyAddress,
+			Action: &BatchTransfer{
+				Transfers: []BatchTransferEntry{
+					{To: a, Value: 2},
+					{To: b, Value: 2},
+				},
+			},
+			State:       funded(3),
+			ExpectedErr: storage.ErrInvalidBalance,
+		},
+		{
+			Name:  "Airdrop",
+			Actor: codec.EmptyAddress,
+			Action: &BatchTransfer{
+				Transfers: []BatchTransferEntry{
+					{To: a, Value: 1},
+					{To: b, Value: 2},
+					{To: a, Value: 3},
+				},
+			},
+			State: funded(10),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, a)
+				require.NoError(t, err)
+				require.Equal(t, uint64(4), balance)
+				balance, err = storage.GetBalance(ctx, store, b)
+				require.NoError(t, err)
+				require.Equal(t, uint64(2), balance)
+			},
+			ExpectedOutputs: &BatchTransferResult{
+				SenderBalance:    4,
+				ReceiverBalances: []uint64{1, 2, 4},
+			},
+		},
+	}
+
+	runActionTests(t, tests)
+}
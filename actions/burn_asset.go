@@ -0,0 +1,110 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const BurnAssetComputeUnits = 1
+
+var _ chain.Action = (*BurnAsset)(nil)
+
+type BurnAsset struct {
+	// Asset is permanently destroyed. Only its owner may burn it.
+	Asset ids.ID `serialize:"true" json:"asset"`
+}
+
+// GetTypeID implements chain.Action.
+func (*BurnAsset) GetTypeID() uint8 {
+	return mconsts.BurnAssetID
+}
+
+// StateKeys implements chain.Action.
+func (b *BurnAsset) StateKeys(actor codec.Address) state.Keys {
+	key := storage.AssetKey(b.Asset)
+	keys := storage.DeletionStateKeys(key)
+	keys.Add(string(key), state.Read|state.Write)
+	keys.Add(string(storage.OwnedAssetKey(actor, b.Asset)), state.Write)
+	keys.Add(string(storage.LockerKey(b.Asset)), state.Read)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+// Execute implements chain.Action.
+func (b *BurnAsset) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, b, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	owner, err := storage.GetAssetOwner(ctx, mu, b.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if owner != actor {
+		return nil, ErrAssetNotOwned
+	}
+	// Burning a locker would strand its content.
+	_, isLocker, err := storage.GetLocker(ctx, mu, b.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if isLocker {
+		return nil, ErrAssetIsLocker
+	}
+	//statekeys:ignore OwnedAssetKey the owner was checked to be the actor
+	if err := storage.DeleteAsset(ctx, mu, b.Asset); err != nil {
+		return nil, err
+	}
+	return &BurnAssetResult{
+		Asset:         b.Asset,
+		PreviousOwner: owner,
+	}, nil
+}
+
+// ComputeUnits implements chain.Action.
+func (*BurnAsset) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.BurnAssetID, BurnAssetComputeUnits)
+}
+
+// ValidRange implements chain.Action.
+func (*BurnAsset) ValidRange(chain.Rules) (int64, int64) {
+	return -1, -1
+}
+
+func (b *BurnAsset) EmitEvents(_ codec.Address, output codec.Typed, e Emitter) {
+	if r, ok := output.(*BurnAssetResult); ok {
+		e.Emit(&OwnershipEvent{Asset: b.Asset, From: r.PreviousOwner})
+	}
+}
+
+var _ codec.Typed = (*BurnAssetResult)(nil)
+
+type BurnAssetResult struct {
+	Asset         ids.ID        `serialize:"true" json:"asset"`
+	PreviousOwner codec.Address `serialize:"true" json:"previousOwner"`
+}
+
+func (*BurnAssetResult) GetTypeID() uint8 {
+	return mconsts.BurnAssetID
+}
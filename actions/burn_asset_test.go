@@ -0,0 +1,78 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestBurnAssetAction(t *testing.T) {
+	owner := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+
+	// [storage.Delete] needs the chain height to tombstone the asset.
+	withAsset := func() state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, store.Insert(ctx, chain.HeightKey(storage.HeightKey()), binary.BigEndian.AppendUint64(nil, 9)))
+		require.NoError(t, storage.CreateAsset(ctx, store, asset, owner))
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "NotFound",
+			Actor: codec.EmptyAddress,
+			Action: &BurnAsset{
+				Asset: asset,
+			},
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: storage.ErrAssetNotFound,
+		},
+		{
+			Name:  "NotOwner",
+			Actor: codectest.NewRandomAddress(),
+			Action: &BurnAsset{
+				Asset: asset,
+			},
+			State:       withAsset(),
+			ExpectedErr: ErrAssetNotOwned,
+		},
+		{
+			Name:  "Burn",
+			Actor: owner,
+			Action: &BurnAsset{
+				Asset: asset,
+			},
+			State: withAsset(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				_, err := store.GetValue(ctx, storage.AssetKey(asset))
+				require.ErrorIs(t, err, database.ErrNotFound)
+				height, ok, err := storage.GetTombstone(ctx, store, storage.AssetKey(asset))
+				require.NoError(t, err)
+				require.True(t, ok)
+				require.Equal(t, uint64(10), height)
+			},
+			ExpectedOutputs: &BurnAssetResult{
+				Asset:         asset,
+				PreviousOwner: owner,
+			},
+		},
+	}
+
+	runActionTests(t, tests)
+}
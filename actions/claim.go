@@ -0,0 +1,170 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const (
+	ClaimComputeUnits = 1
+
+	// ClaimRentPerByteHour applies when the rules do not set
+	// [ClaimRentPerByteHourRule].
+	ClaimRentPerByteHour = 1
+
+	// MaxClaimLifetime bounds how far ahead a claim can expire, in
+	// milliseconds.
+	MaxClaimLifetime = 30 * 24 * claimRentPeriod
+
+	// claimRentPeriod is the period rent is charged for, in milliseconds.
+	claimRentPeriod = 60 * 60 * 1000
+)
+
+// ClaimRentPerByteHourRule is the key of the rent charged for every byte of
+// a claim's key and value, for every started hour until it expires, read
+// with chain.Rules.FetchCustom.
+const ClaimRentPerByteHourRule = "claimRentPerByteHour"
+
+var (
+	ErrClaimKeySize   = errors.New("claim key size is out of range")
+	ErrClaimValueSize = errors.New("claim value is too large")
+	ErrClaimExpiry    = errors.New("claim expiry is out of range")
+	ErrClaimNotFound  = errors.New("claim not found")
+
+	_ chain.Action = (*SetClaim)(nil)
+)
+
+// SetClaim stores [Value] under [Key] in the actor's claims until [Expiry],
+// burning the rent for its lifetime. Setting a claim again replaces it and
+// charges rent for the new lifetime. An empty [Value] removes the claim.
+type SetClaim struct {
+	Key   []byte `serialize:"true" json:"key"`
+	Value []byte `serialize:"true" json:"value"`
+	// Expiry is the timestamp, in milliseconds, after which the claim reads
+	// as absent. It is ignored when removing a claim.
+	Expiry int64 `serialize:"true" json:"expiry"`
+}
+
+func (*SetClaim) GetTypeID() uint8 {
+	return mconsts.SetClaimID
+}
+
+func (s *SetClaim) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.ClaimKey(actor, s.Key)): state.All,
+		string(storage.BalanceKey(actor)):      state.Read | state.Write,
+		string(storage.ActiveProposalKey()):    state.Read,
+		string(storage.HaltKey()):              state.Read,
+	}
+}
+
+func (s *SetClaim) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, s, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if len(s.Key) == 0 || len(s.Key) > storage.MaxClaimKeySize {
+		return nil, ErrClaimKeySize
+	}
+	if len(s.Value) > storage.MaxClaimValueSize {
+		return nil, ErrClaimValueSize
+	}
+	if len(s.Value) == 0 {
+		if err := storage.DeleteClaim(ctx, mu, actor, s.Key); err != nil {
+			return nil, err
+		}
+		return &SetClaimResult{}, nil
+	}
+	if s.Expiry <= timestamp || s.Expiry-timestamp > MaxClaimLifetime {
+		return nil, ErrClaimExpiry
+	}
+	rent, err := ClaimRent(r, len(s.Key)+len(s.Value), s.Expiry-timestamp)
+	if err != nil {
+		return nil, err
+	}
+	result := &SetClaimResult{Expiry: s.Expiry, Rent: rent}
+	if rent > 0 {
+		if result.Balance, err = storage.SubBalance(ctx, mu, actor, rent); err != nil {
+			return nil, err
+		}
+	} else if result.Balance, err = storage.GetBalance(ctx, mu, actor); err != nil {
+		return nil, err
+	}
+	if err := storage.SetClaim(ctx, mu, actor, s.Key, &storage.Claim{
+		Value:  s.Value,
+		Expiry: s.Expiry,
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (*SetClaim) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.SetClaimID, ClaimComputeUnits)
+}
+
+func (*SetClaim) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*SetClaimResult)(nil)
+
+type SetClaimResult struct {
+	// Expiry is zero when the claim was removed.
+	Expiry  int64  `serialize:"true" json:"expiry"`
+	Rent    uint64 `serialize:"true" json:"rent"`
+	Balance uint64 `serialize:"true" json:"balance"`
+}
+
+func (*SetClaimResult) GetTypeID() uint8 {
+	return mconsts.SetClaimID
+}
+
+// ClaimRules returns the claim rent of [r], per byte and started hour.
+func ClaimRules(r chain.Rules) uint64 {
+	rent := uint64(ClaimRentPerByteHour)
+	if r == nil {
+		return rent
+	}
+	if v, ok := r.FetchCustom(ClaimRentPerByteHourRule); ok {
+		if n, ok := v.(uint64); ok {
+			rent = n
+		}
+	}
+	return rent
+}
+
+// ClaimRent returns the rent of a claim of [size] bytes that expires in
+// [lifetime] milliseconds.
+func ClaimRent(r chain.Rules, size int, lifetime int64) (uint64, error) {
+	periods := uint64((lifetime + claimRentPeriod - 1) / claimRentPeriod)
+	byteRent, err := smath.Mul(uint64(size), ClaimRules(r))
+	if err != nil {
+		return 0, err
+	}
+	return smath.Mul(byteRent, periods)
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestSetClaimAction(t *testing.T) {
+	owner := codectest.NewRandomAddress()
+	key := []byte("profile")
+	const hour = claimRentPeriod
+
+	// account funds [owner] with 1,000 and has a claim expiring at 2 hours.
+	account := func() state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(ctx, store, owner, 1_000))
+		require.NoError(t, storage.SetClaim(ctx, store, owner, key, &storage.Claim{Value: []byte("old"), Expiry: 2 * hour}))
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:        "EmptyKey",
+			Actor:       owner,
+			Action:      &SetClaim{Value: []byte("v"), Expiry: hour},
+			State:       account(),
+			ExpectedErr: ErrClaimKeySize,
+		},
+		{
+			Name:        "ValueTooLarge",
+			Actor:       owner,
+			Action:      &SetClaim{Key: key, Value: make([]byte, storage.MaxClaimValueSize+1), Expiry: hour},
+			State:       account(),
+			ExpectedErr: ErrClaimValueSize,
+		},
+		{
+			Name:        "Expired",
+			Actor:       owner,
+			Action:      &SetClaim{Key: key, Value: []byte("v"), Expiry: 10},
+			Timestamp:   10,
+			State:       account(),
+			ExpectedErr: ErrClaimExpiry,
+		},
+		{
+			Name:        "TooLong",
+			Actor:       owner,
+			Action:      &SetClaim{Key: key, Value: []byte("v"), Expiry: MaxClaimLifetime + 1},
+			State:       account(),
+			ExpectedErr: ErrClaimExpiry,
+		},
+		{
+			Name:        "InsufficientRent",
+			Actor:       owner,
+			Action:      &SetClaim{Key: key, Value: make([]byte, 100), Expiry: 10 * hour},
+			State:       account(),
+			ExpectedErr: storage.ErrInvalidBalance,
+		},
+		{
+			// 12 bytes for 3 started hours.
+			Name:   "Replace",
+			Actor:  owner,
+			Action: &SetClaim{Key: key, Value: []byte("hello"), Expiry: 2*hour + 1},
+			State:  account(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				claim, exists, err := storage.GetClaim(ctx, store, owner, key)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.Claim{Value: []byte("hello"), Expiry: 2*hour + 1}, claim)
+			},
+			ExpectedOutputs: &SetClaimResult{Expiry: 2*hour + 1, Rent: 36, Balance: 964},
+		},
+		{
+			// 7 bytes for 1 hour.
+			Name:   "New",
+			Actor:  owner,
+			Action: &SetClaim{Key: []byte("name"), Value: []byte("bob"), Expiry: hour},
+			State:  account(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				claim, exists, err := storage.GetClaim(ctx, store, owner, []byte("name"))
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.Claim{Value: []byte("bob"), Expiry: hour}, claim)
+			},
+			ExpectedOutputs: &SetClaimResult{Expiry: hour, Rent: 7, Balance: 993},
+		},
+		{
+			Name:   "Remove",
+			Actor:  owner,
+			Action: &SetClaim{Key: key},
+			State:  account(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				_, exists, err := storage.GetClaim(ctx, store, owner, key)
+				require.NoError(t, err)
+				require.False(t, exists)
+			},
+			ExpectedOutputs: &SetClaimResult{},
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import "github.com/ava-labs/hypersdk/chain"
+
+// ComputeUnitsRule is the key of the per-action compute unit costs, read
+// with chain.Rules.FetchCustom. Its value is a map from action type ID to
+// the base cost of that action, replacing the *ComputeUnits constant of the
+// action. Costs that depend on the action's size, such as memo bytes or
+// transfer hooks, are added on top.
+const ComputeUnitsRule = "computeUnits"
+
+// baseComputeUnits returns the base cost [r] sets for actions of [typeID],
+// or [units] if it sets none.
+func baseComputeUnits(r chain.Rules, typeID uint8, units uint64) uint64 {
+	if r == nil {
+		return units
+	}
+	v, ok := r.FetchCustom(ComputeUnitsRule)
+	if !ok {
+		return units
+	}
+	costs, ok := v.(map[uint8]uint64)
+	if !ok {
+		return units
+	}
+	if cost, ok := costs[typeID]; ok {
+		return cost
+	}
+	return units
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/genesis"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+// costRules makes transfers cost 10, swap legs 3 and lowers the reason bound
+// to 4 bytes.
+type costRules struct {
+	*genesis.Rules
+}
+
+func (*costRules) FetchCustom(key string) (any, bool) {
+	switch key {
+	case ComputeUnitsRule:
+		return map[uint8]uint64{
+			mconsts.TransferID:      10,
+			mconsts.BatchTransferID: 10,
+			mconsts.AcceptSwapID:    3,
+		}, true
+	case MaxReasonSizeRule:
+		return 4, true
+	default:
+		return nil, false
+	}
+}
+
+func TestComputeUnitsRule(t *testing.T) {
+	require := require.New(t)
+	r := &costRules{Rules: genesis.NewDefaultRules()}
+	to := codectest.NewRandomAddress()
+
+	require.Equal(uint64(10), (&Transfer{To: to, Value: 1}).ComputeUnits(r))
+	require.Equal(uint64(10)+ReceiptComputeUnits, (&Transfer{To: to, Value: 1, Receipt: ids.GenerateTestID()}).ComputeUnits(r))
+	require.Equal(uint64(20), (&BatchTransfer{Transfers: []BatchTransferEntry{{To: to, Value: 1}, {To: to, Value: 2}}}).ComputeUnits(r))
+
+	// One native leg on each side, so no transfer hooks.
+	native := []storage.SwapLeg{{Asset: storage.NativeAsset, Amount: 1}}
+	require.Equal(uint64(6), (&AcceptSwap{Offer: native, Want: native}).ComputeUnits(r))
+
+	// Actions the rules leave out keep their constant.
+	require.Equal(uint64(MintAssetComputeUnits), (&MintAsset{}).ComputeUnits(r))
+	require.Equal(uint64(TransferComputeUnits), (&Transfer{To: to, Value: 1}).ComputeUnits(nil))
+}
+
+func TestMaxReasonSizeRule(t *testing.T) {
+	actor := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+	r := &costRules{Rules: genesis.NewDefaultRules()}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:        "ReasonTooLarge",
+			Actor:       actor,
+			Action:      &AssetTransfer{Recipient: codectest.NewRandomAddress(), Asset: asset, Reason: "gifts"},
+			Rules:       r,
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: ErrReasonTooLarge,
+		},
+		{
+			// The default bound applies when the rules set none.
+			Name:        "DefaultBound",
+			Actor:       actor,
+			Action:      &AssetTransfer{Recipient: codectest.NewRandomAddress(), Asset: asset, Reason: strings.Repeat("a", MaxReasonSize+1)},
+			Rules:       genesis.NewDefaultRules(),
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: ErrReasonTooLarge,
+		},
+	}
+
+	runActionTests(t, tests)
+}
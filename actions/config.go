@@ -0,0 +1,136 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// ConfigKind is the type of the values of a config entry.
+type ConfigKind uint8
+
+const (
+	ConfigUint64 ConfigKind = iota
+	ConfigAddress
+)
+
+func (k ConfigKind) String() string {
+	switch k {
+	case ConfigUint64:
+		return "uint64"
+	case ConfigAddress:
+		return "address"
+	default:
+		return "unknown"
+	}
+}
+
+// ConfigEntry describes a tunable of the config registry.
+type ConfigEntry struct {
+	Kind ConfigKind
+	// Max bounds the values governance can set for a [ConfigUint64] entry.
+	Max uint64
+	// Default returns the value in effect while governance has set none,
+	// taken from the rules.
+	Default func(chain.Rules) []byte
+}
+
+// Config is the registry of tunables, keyed by their rule keys. A passed
+// governance proposal sets them with [storage.SetConfigValue], and a value
+// set in state takes precedence over the rules.
+//
+// Actions that read an entry must declare its [storage.ConfigKey] with
+// [state.Read].
+var Config = map[string]ConfigEntry{
+	MaxMemoSizeRule: {
+		Kind: ConfigUint64,
+		Max:  MaxGovernedMemoSize,
+		Default: func(r chain.Rules) []byte {
+			maxSize, _ := MemoRules(r)
+			return uint64Config(uint64(max(maxSize, 0)))
+		},
+	},
+	StakingRewardRateRule: {
+		Kind: ConfigUint64,
+		Max:  10_000,
+		Default: func(r chain.Rules) []byte {
+			rate, _ := StakingRules(r)
+			return uint64Config(rate)
+		},
+	},
+	SweepBountyRule: {
+		Kind: ConfigUint64,
+		Max:  MaxSweepBounty,
+		Default: func(r chain.Rules) []byte {
+			return uint64Config(SweepRules(r))
+		},
+	},
+	MaintenanceAddressRule: {
+		Kind: ConfigAddress,
+		Default: func(r chain.Rules) []byte {
+			addr := MaintenanceRules(r)
+			return addr[:]
+		},
+	},
+}
+
+// ConfigValue returns the value of config entry [key] in [im], or its
+// default in [r] if governance has set none.
+func ConfigValue(ctx context.Context, r chain.Rules, im state.Immutable, key string) ([]byte, error) {
+	entry, ok := Config[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrParameterNotGoverned, key)
+	}
+	v, exists, err := storage.GetConfig(ctx, im, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return entry.Default(r), nil
+	}
+	return v, nil
+}
+
+// DecodeConfigAddress decodes the value of a [ConfigAddress] entry.
+func DecodeConfigAddress(v []byte) (codec.Address, error) {
+	if len(v) != codec.AddressLen {
+		return codec.EmptyAddress, fmt.Errorf("%w: %d bytes is not an address", storage.ErrInvalidConfig, len(v))
+	}
+	return codec.Address(v), nil
+}
+
+func configUint64(ctx context.Context, r chain.Rules, im state.Immutable, key string) (uint64, error) {
+	v, err := ConfigValue(ctx, r, im, key)
+	if err != nil {
+		return 0, err
+	}
+	return storage.DecodeConfigUint64(v)
+}
+
+func configAddress(ctx context.Context, r chain.Rules, im state.Immutable, key string) (codec.Address, error) {
+	v, err := ConfigValue(ctx, r, im, key)
+	if err != nil {
+		return codec.EmptyAddress, err
+	}
+	return DecodeConfigAddress(v)
+}
+
+func uint64Config(n uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, n)
+}
+
+// encode returns the config value [c] sets.
+func (e ConfigEntry) encode(c storage.ParameterChange) []byte {
+	if e.Kind == ConfigAddress {
+		return c.Address[:]
+	}
+	return uint64Config(c.Value)
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// Conflicting actions in the same block, such as two fills of the same order
+// or two transfers of the same asset, always resolve the same way on every
+// node: the transactions of a block execute in their order in the block,
+// and the actions of a transaction in their order in it. Transactions that
+// declare a common key never run concurrently, so the first action to reach
+// a record wins and the ones after it see what it left.
+//
+// The losers fail with a typed error:
+//   - An accepted or refunded swap, and a filled or cancelled order, fail
+//     the actions after them with [ErrAlreadyTaken].
+//   - A fill for more than an earlier fill left fails with
+//     [ErrFillExceedsOrder].
+//   - A transferred or burned asset fails later transfers by its previous
+//     owner with [ErrAssetNotOwned].
+//
+// The block builder picks the order of a block, in the order transactions
+// reached its mempool.
+
+var ErrAlreadyTaken = errors.New("already taken by an earlier action")
+
+// addTakenKeys declares the keys [storage.Delete] touches when removing
+// [key], and reading its tombstone for [takenOr].
+func addTakenKeys(keys state.Keys, key []byte) {
+	for k, permissions := range storage.DeletionStateKeys(key) {
+		keys.Add(k, permissions)
+	}
+	keys.Add(string(storage.TombstoneKey(key)), state.Read)
+}
+
+// takenOr returns [ErrAlreadyTaken] if [key] is missing because an action
+// deleted it, or else [notFound].
+func takenOr(ctx context.Context, im state.Immutable, key []byte, notFound error) error {
+	height, deleted, err := storage.GetTombstone(ctx, im, key)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return notFound
+	}
+	return fmt.Errorf("%w: removed at height %d", ErrAlreadyTaken, height)
+}
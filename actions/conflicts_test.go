@@ -0,0 +1,179 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// contender is an action of a transaction racing for a record.
+type contender struct {
+	actor  codec.Address
+	action chain.Action
+}
+
+// TestSameBlockConflicts runs each pair of conflicting actions in both
+// orders, as consecutive actions of a block: the first always wins and the
+// second fails with the same typed error.
+func TestSameBlockConflicts(t *testing.T) {
+	maker := codectest.NewRandomAddress()
+	takers := []codec.Address{codectest.NewRandomAddress(), codectest.NewRandomAddress()}
+	counterparty := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+	orderID := ids.GenerateTestID()
+	swapID := ids.GenerateTestID()
+	native := storage.NativeAsset
+	offer := []storage.SwapLeg{{Asset: native, Amount: 10}}
+	want := []storage.SwapLeg{{Asset: asset, Amount: 3}}
+
+	// accepted sets the height of the parent of the block, for tombstones.
+	accepted := func() state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, store.Insert(context.Background(), chain.HeightKey(storage.HeightKey()), binary.BigEndian.AppendUint64(nil, 9)))
+		return store
+	}
+	// listed has an order from [maker] with 4 of [asset] left for 10 native
+	// tokens, and [takers] funded to fill it.
+	listed := func() state.Mutable {
+		ctx := context.Background()
+		store := accepted()
+		require.NoError(t, storage.SetOrder(ctx, store, asset, native, orderID, &storage.Order{
+			Maker:      maker,
+			SellAmount: 4,
+			BuyAmount:  10,
+			Remaining:  4,
+		}))
+		for _, taker := range takers {
+			require.NoError(t, storage.SetBalance(ctx, store, taker, 100))
+		}
+		return store
+	}
+	// proposed has a swap open to [counterparty], who holds enough to
+	// accept it twice.
+	proposed := func() state.Mutable {
+		ctx := context.Background()
+		store := accepted()
+		require.NoError(t, storage.SetBalance(ctx, store, storage.EscrowAddress(swapID), 10))
+		require.NoError(t, storage.SetAssetBalance(ctx, store, counterparty, asset, 6))
+		require.NoError(t, storage.SetSwap(ctx, store, swapID, &storage.Swap{
+			Proposer:     maker,
+			Counterparty: counterparty,
+			Expiry:       100,
+			Offer:        offer,
+			Want:         want,
+		}))
+		return store
+	}
+	// owned has [asset] owned by [maker].
+	owned := func() state.Mutable {
+		store := accepted()
+		require.NoError(t, storage.CreateAsset(context.Background(), store, asset, maker))
+		return store
+	}
+	fill := func(taker codec.Address, amount uint64) contender {
+		return contender{taker, &FillOrder{
+			OrderID:   orderID,
+			SellAsset: asset,
+			BuyAsset:  native,
+			Maker:     maker,
+			Amount:    amount,
+		}}
+	}
+	accept := contender{counterparty, &AcceptSwap{SwapID: swapID, Proposer: maker, Offer: offer, Want: want}}
+
+	races := []struct {
+		name      string
+		state     func() state.Mutable
+		contender [2]contender
+		loserErr  error
+	}{
+		{
+			name:      "FillsOfTheWholeOrder",
+			state:     listed,
+			contender: [2]contender{fill(takers[0], 4), fill(takers[1], 4)},
+			loserErr:  ErrAlreadyTaken,
+		},
+		{
+			name:      "FillsOfMoreThanIsLeft",
+			state:     listed,
+			contender: [2]contender{fill(takers[0], 3), fill(takers[1], 3)},
+			loserErr:  ErrFillExceedsOrder,
+		},
+		{
+			name:  "FillAndCancel",
+			state: listed,
+			contender: [2]contender{
+				fill(takers[0], 4),
+				{maker, &CancelOrder{OrderID: orderID, SellAsset: asset, BuyAsset: native}},
+			},
+			loserErr: ErrAlreadyTaken,
+		},
+		{
+			name:      "AcceptsOfTheSameSwap",
+			state:     proposed,
+			contender: [2]contender{accept, accept},
+			loserErr:  ErrAlreadyTaken,
+		},
+		{
+			name:  "TransfersOfTheSameAsset",
+			state: owned,
+			contender: [2]contender{
+				{maker, &AssetTransfer{Recipient: takers[0], Asset: asset}},
+				{maker, &AssetTransfer{Recipient: takers[1], Asset: asset}},
+			},
+			loserErr: ErrAssetNotOwned,
+		},
+	}
+
+	for _, race := range races {
+		for _, order := range [][2]int{{0, 1}, {1, 0}} {
+			ctx := context.Background()
+			winner, loser := race.contender[order[0]], race.contender[order[1]]
+			store := race.state()
+			_, err := winner.action.Execute(ctx, nil, store, 0, winner.actor, ids.Empty)
+			require.NoError(t, err)
+
+			test := chaintest.ActionTest{
+				Name:        race.name,
+				Actor:       loser.actor,
+				Action:      loser.action,
+				State:       store,
+				ExpectedErr: race.loserErr,
+			}
+			audit.Run(ctx, t, test)
+		}
+	}
+
+	// A record that never existed is not taken.
+	tests := []chaintest.ActionTest{
+		{
+			Name:        "FillUnknownOrder",
+			Actor:       takers[0],
+			Action:      fill(takers[0], 4).action,
+			State:       accepted(),
+			ExpectedErr: ErrOrderNotFound,
+		},
+		{
+			Name:        "AcceptUnknownSwap",
+			Actor:       counterparty,
+			Action:      accept.action,
+			State:       accepted(),
+			ExpectedErr: ErrSwapNotFound,
+		},
+	}
+
+	runActionTests(t, tests)
+}
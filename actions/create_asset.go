@@ -0,0 +1,125 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const CreateAssetComputeUnits = 1
+
+var _ chain.Action = (*CreateAsset)(nil)
+
+// CreateAsset creates an asset owned by the actor under
+// storage.AssetID(actor, Nonce), so clients know its ID before the
+// transaction is accepted and no other actor can create it first. Assets
+// created in an [App] are under storage.AppAssetID(App, actor, Nonce)
+// instead.
+type CreateAsset struct {
+	// Nonce distinguishes the assets of one creator.
+	Nonce uint64 `serialize:"true" json:"nonce"`
+
+	// Soulbound assets can never be transferred, only burned by their
+	// owner.
+	Soulbound bool `serialize:"true" json:"soulbound"`
+
+	// Expiry is the last timestamp, in milliseconds, at which the asset can
+	// be transferred, or zero if it never expires.
+	Expiry int64 `serialize:"true" json:"expiry"`
+
+	// App is the app registered with [RegisterApp] to create the asset in,
+	// or empty to create it outside of any app. Only the admin of the app
+	// and the creators it allows can create assets in it.
+	App ids.ID `serialize:"true" json:"app"`
+}
+
+func (*CreateAsset) GetTypeID() uint8 {
+	return mconsts.CreateAssetID
+}
+
+func (c *CreateAsset) StateKeys(actor codec.Address) state.Keys {
+	asset := c.asset(actor)
+	keys := state.Keys{
+		string(storage.AssetKey(asset)):                       state.Read | state.Allocate | state.Write,
+		string(storage.OwnedAssetKey(actor, asset)):           state.Allocate | state.Write,
+		string(storage.TombstoneKey(storage.AssetKey(asset))): state.Read,
+		string(storage.HaltKey()):                             state.Read,
+	}
+	if c.App != ids.Empty {
+		keys.Add(string(storage.AppKey(c.App)), state.Read)
+		keys.Add(string(storage.AppCreatorKey(c.App, actor)), state.Read)
+		keys.Add(string(storage.AppAssetKey(c.App, asset)), state.Allocate|state.Write)
+	}
+	return keys
+}
+
+func (c *CreateAsset) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, c, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	asset := c.asset(actor)
+	if c.App != ids.Empty {
+		if err := checkAppCreator(ctx, mu, c.App, actor); err != nil {
+			return nil, err
+		}
+		if err := storage.AddAppAsset(ctx, mu, c.App, asset); err != nil {
+			return nil, err
+		}
+	}
+	if err := createAsset(ctx, mu, timestamp, asset, actor, c.Soulbound, c.Expiry); err != nil {
+		return nil, err
+	}
+	return &CreateAssetResult{Asset: asset}, nil
+}
+
+func (*CreateAsset) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.CreateAssetID, CreateAssetComputeUnits)
+}
+
+func (*CreateAsset) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (c *CreateAsset) EmitEvents(actor codec.Address, _ codec.Typed, e Emitter) {
+	e.Emit(&OwnershipEvent{Asset: c.asset(actor), To: actor})
+}
+
+// asset is the ID of the asset [actor] creates.
+func (c *CreateAsset) asset(actor codec.Address) ids.ID {
+	if c.App != ids.Empty {
+		return storage.AppAssetID(c.App, actor, c.Nonce)
+	}
+	return storage.AssetID(actor, c.Nonce)
+}
+
+var _ codec.Typed = (*CreateAssetResult)(nil)
+
+type CreateAssetResult struct {
+	Asset ids.ID `serialize:"true" json:"asset"`
+}
+
+func (*CreateAssetResult) GetTypeID() uint8 {
+	return mconsts.CreateAssetID
+}
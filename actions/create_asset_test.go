@@ -0,0 +1,119 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestCreateAssetAction(t *testing.T) {
+	actor := codectest.NewRandomAddress()
+	other := codectest.NewRandomAddress()
+	asset := storage.AssetID(actor, 1)
+	require.NotEqual(t, asset, storage.AssetID(actor, 2))
+	require.NotEqual(t, asset, storage.AssetID(other, 1))
+
+	app := storage.AppID("team")
+	appAsset := storage.AppAssetID(app, other, 1)
+	require.NotEqual(t, asset, storage.AppAssetID(app, actor, 1))
+	require.NotEqual(t, appAsset, storage.AppAssetID(storage.AppID("other"), other, 1))
+
+	// registered has [app] with [actor] as its admin, and [other] allowed
+	// to create assets in it if [allowed].
+	registered := func(allowed bool) state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetAppAdmin(ctx, store, app, actor))
+		require.NoError(t, storage.SetAppCreator(ctx, store, app, other, allowed))
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:   "Create",
+			Actor:  actor,
+			Action: &CreateAsset{Nonce: 1},
+			State:  chaintest.NewInMemoryStore(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				owner, err := storage.GetAssetOwner(ctx, store, asset)
+				require.NoError(t, err)
+				require.Equal(t, actor, owner)
+				_, err = store.GetValue(ctx, storage.OwnedAssetKey(actor, asset))
+				require.NoError(t, err)
+			},
+			ExpectedOutputs: &CreateAssetResult{Asset: asset},
+		},
+		{
+			Name:   "CreateSoulbound",
+			Actor:  actor,
+			Action: &CreateAsset{Nonce: 1, Soulbound: true},
+			State:  chaintest.NewInMemoryStore(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				// The flag survives later rewrites of the asset.
+				require.NoError(t, storage.SetAssetRoyalty(ctx, store, asset, 0, other))
+				control, err := storage.GetAssetControl(ctx, store, asset)
+				require.NoError(t, err)
+				require.Equal(t, storage.AssetControl{Minter: actor, Soulbound: true}, control)
+			},
+			ExpectedOutputs: &CreateAssetResult{Asset: asset},
+		},
+		{
+			Name:   "AlreadyExists",
+			Actor:  actor,
+			Action: &CreateAsset{Nonce: 1},
+			State: func() state.Mutable {
+				store := chaintest.NewInMemoryStore()
+				require.NoError(t, storage.CreateAsset(context.Background(), store, asset, actor))
+				return store
+			}(),
+			ExpectedErr: storage.ErrAssetExists,
+		},
+		{
+			Name:   "CreateInApp",
+			Actor:  other,
+			Action: &CreateAsset{Nonce: 1, App: app},
+			State:  registered(true),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				owner, err := storage.GetAssetOwner(ctx, store, appAsset)
+				require.NoError(t, err)
+				require.Equal(t, other, owner)
+				_, err = store.GetValue(ctx, storage.AppAssetKey(app, appAsset))
+				require.NoError(t, err)
+			},
+			ExpectedOutputs: &CreateAssetResult{Asset: appAsset},
+		},
+		{
+			// The admin needs no entry in the creators.
+			Name:            "CreateInAppAsAdmin",
+			Actor:           actor,
+			Action:          &CreateAsset{Nonce: 1, App: app},
+			State:           registered(false),
+			ExpectedOutputs: &CreateAssetResult{Asset: storage.AppAssetID(app, actor, 1)},
+		},
+		{
+			Name:        "NotAppCreator",
+			Actor:       other,
+			Action:      &CreateAsset{Nonce: 1, App: app},
+			State:       registered(false),
+			ExpectedErr: ErrNotAppCreator,
+		},
+		{
+			Name:        "AppNotFound",
+			Actor:       actor,
+			Action:      &CreateAsset{Nonce: 1, App: storage.AppID("other")},
+			State:       registered(true),
+			ExpectedErr: ErrAppNotFound,
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// Keys of the dust rules, read with chain.Rules.FetchCustom.
+const (
+	// DustThresholdRule is the smallest nonzero native balance a transfer
+	// may leave either party with. Zero disables the check.
+	DustThresholdRule = "dustThreshold"
+
+	// DustPolicyRule is what happens to a sender a transfer would leave
+	// below [DustThresholdRule].
+	DustPolicyRule = "dustPolicy"
+)
+
+// Dust policies.
+const (
+	// DustReject fails the transfer.
+	DustReject uint8 = iota
+	// DustBurn burns the sender's remaining balance, removing the account.
+	// Recipients left below the threshold still fail the transfer.
+	DustBurn
+)
+
+var ErrDustBalance = errors.New("balance would be below the dust threshold")
+
+// DustRules returns the dust threshold and policy of [r].
+func DustRules(r chain.Rules) (uint64, uint8) {
+	var (
+		threshold uint64
+		policy    = DustReject
+	)
+	if r == nil {
+		return threshold, policy
+	}
+	if v, ok := r.FetchCustom(DustThresholdRule); ok {
+		if n, ok := v.(uint64); ok {
+			threshold = n
+		}
+	}
+	if v, ok := r.FetchCustom(DustPolicyRule); ok {
+		if p, ok := v.(uint8); ok {
+			policy = p
+		}
+	}
+	return threshold, policy
+}
+
+// isDust reports whether [balance] is nonzero and below [threshold].
+func isDust(balance uint64, threshold uint64) bool {
+	return balance > 0 && balance < threshold
+}
+
+// settleDust applies the dust policy of [r] to a transfer that left
+// [sender] with [senderBalance] and its recipients with
+// [receiverBalances]. It returns the sender's balance after the policy.
+func settleDust(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	sender codec.Address,
+	senderBalance uint64,
+	receiverBalances ...uint64,
+) (uint64, error) {
+	threshold, policy := DustRules(r)
+	if threshold == 0 {
+		return senderBalance, nil
+	}
+	for _, balance := range receiverBalances {
+		if isDust(balance, threshold) {
+			return 0, ErrDustBalance
+		}
+	}
+	if !isDust(senderBalance, threshold) {
+		return senderBalance, nil
+	}
+	if policy != DustBurn {
+		return 0, ErrDustBalance
+	}
+	return storage.SubBalance(ctx, mu, sender, senderBalance)
+}
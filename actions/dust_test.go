@@ -0,0 +1,130 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// dustRules sets a dust threshold of 100.
+type dustRules struct {
+	*genesis.Rules
+	policy uint8
+}
+
+func (r *dustRules) FetchCustom(key string) (any, bool) {
+	switch key {
+	case DustThresholdRule:
+		return uint64(100), true
+	case DustPolicyRule:
+		return r.policy, true
+	default:
+		return nil, false
+	}
+}
+
+func TestDustPolicy(t *testing.T) {
+	sender := codectest.NewRandomAddress()
+	owner := codectest.NewRandomAddress()
+	to := codectest.NewRandomAddress()
+	other := codectest.NewRandomAddress()
+	reject := &dustRules{Rules: genesis.NewDefaultRules(), policy: DustReject}
+	burn := &dustRules{Rules: genesis.NewDefaultRules(), policy: DustBurn}
+
+	// funded gives [sender] 1,000 and lets it spend 1,000 of [owner]'s
+	// 1,000.
+	funded := func() state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(ctx, store, sender, 1_000))
+		require.NoError(t, storage.SetBalance(ctx, store, owner, 1_000))
+		require.NoError(t, storage.SetAllowance(ctx, store, owner, sender, 1_000))
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:        "RecipientDust",
+			Actor:       sender,
+			Action:      &Transfer{To: to, Value: 99},
+			Rules:       burn,
+			State:       funded(),
+			ExpectedErr: ErrDustBalance,
+		},
+		{
+			Name:        "SenderDust",
+			Actor:       sender,
+			Action:      &Transfer{To: to, Value: 950},
+			Rules:       reject,
+			State:       funded(),
+			ExpectedErr: ErrDustBalance,
+		},
+		{
+			Name:   "SenderEmptied",
+			Actor:  sender,
+			Action: &Transfer{To: to, Value: 1_000},
+			Rules:  reject,
+			State:  funded(),
+			ExpectedOutputs: &TransferResult{
+				SenderBalance:   0,
+				ReceiverBalance: 1_000,
+			},
+		},
+		{
+			Name:   "SenderDustBurned",
+			Actor:  sender,
+			Action: &Transfer{To: to, Value: 950},
+			Rules:  burn,
+			State:  funded(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, sender)
+				require.NoError(t, err)
+				require.Zero(t, balance)
+			},
+			ExpectedOutputs: &TransferResult{
+				SenderBalance:   0,
+				ReceiverBalance: 950,
+			},
+		},
+		{
+			Name:        "TransferFromDust",
+			Actor:       sender,
+			Action:      &TransferFrom{From: owner, To: to, Value: 950},
+			Rules:       reject,
+			State:       funded(),
+			ExpectedErr: ErrDustBalance,
+		},
+		{
+			// [to] ends with 100 even though its first credit is dust.
+			Name:   "BatchRepeatedRecipient",
+			Actor:  sender,
+			Action: &BatchTransfer{Transfers: []BatchTransferEntry{{To: to, Value: 50}, {To: other, Value: 200}, {To: to, Value: 50}}},
+			Rules:  reject,
+			State:  funded(),
+			ExpectedOutputs: &BatchTransferResult{
+				SenderBalance:    700,
+				ReceiverBalances: []uint64{50, 200, 100},
+			},
+		},
+		{
+			Name:        "BatchRecipientDust",
+			Actor:       sender,
+			Action:      &BatchTransfer{Transfers: []BatchTransferEntry{{To: to, Value: 50}, {To: other, Value: 200}}},
+			Rules:       reject,
+			State:       funded(),
+			ExpectedErr: ErrDustBalance,
+		},
+	}
+
+	runActionTests(t, tests)
+}
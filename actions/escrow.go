@@ -0,0 +1,316 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const EscrowComputeUnits = 1
+
+var (
+	ErrSelfEscrow        = errors.New("counterparty must not be the payer")
+	ErrEscrowExists      = errors.New("escrow already exists")
+	ErrEscrowNotFound    = errors.New("escrow not found")
+	ErrEscrowExpired     = errors.New("escrow deadline has passed")
+	ErrEscrowNotExpired  = errors.New("escrow deadline has not passed")
+	ErrEscrowMismatch    = errors.New("escrow does not match")
+	ErrNotEscrowPayer    = errors.New("actor is not the escrow payer")
+	ErrDeadlineInThePast = errors.New("deadline must not be before the block timestamp")
+
+	_ chain.Action = (*OpenEscrow)(nil)
+	_ chain.Action = (*ReleaseEscrow)(nil)
+	_ chain.Action = (*RefundEscrow)(nil)
+)
+
+// OpenEscrow locks [Amount] of the actor's native tokens for [Counterparty]
+// until the actor releases them or [Deadline] passes. The escrow ID is
+// storage.EscrowID(actor, Nonce).
+type OpenEscrow struct {
+	// Nonce distinguishes the escrows of one payer. Reusing the nonce of an
+	// open escrow fails.
+	Nonce        uint64        `serialize:"true" json:"nonce"`
+	Counterparty codec.Address `serialize:"true" json:"counterparty"`
+	Amount       uint64        `serialize:"true" json:"amount"`
+	// Deadline is the last timestamp, in milliseconds, at which the escrow
+	// can be released. After it, anyone can return the funds with
+	// [RefundEscrow].
+	Deadline int64 `serialize:"true" json:"deadline"`
+}
+
+func (*OpenEscrow) GetTypeID() uint8 {
+	return mconsts.OpenEscrowID
+}
+
+func (o *OpenEscrow) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.BalanceKey(actor)):                           state.Read | state.Write,
+		string(storage.EscrowKey(storage.EscrowID(actor, o.Nonce))): state.All,
+		string(storage.ActiveProposalKey()):                         state.Read,
+		string(storage.HaltKey()):                                   state.Read,
+	}
+}
+
+func (o *OpenEscrow) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, o, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if o.Amount == 0 {
+		return nil, ErrOutputValueZero
+	}
+	if o.Counterparty == actor {
+		return nil, ErrSelfEscrow
+	}
+	if o.Deadline < timestamp {
+		return nil, ErrDeadlineInThePast
+	}
+	escrowID := storage.EscrowID(actor, o.Nonce)
+	_, exists, err := storage.GetEscrow(ctx, mu, escrowID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrEscrowExists
+	}
+	balance, err := storage.SubBalance(ctx, mu, actor, o.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.SetEscrow(ctx, mu, escrowID, &storage.Escrow{
+		Payer:        actor,
+		Counterparty: o.Counterparty,
+		Amount:       o.Amount,
+		Deadline:     o.Deadline,
+	}); err != nil {
+		return nil, err
+	}
+	return &OpenEscrowResult{
+		EscrowID:      escrowID,
+		SenderBalance: balance,
+	}, nil
+}
+
+func (*OpenEscrow) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.OpenEscrowID, EscrowComputeUnits)
+}
+
+func (*OpenEscrow) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*OpenEscrowResult)(nil)
+
+type OpenEscrowResult struct {
+	EscrowID      ids.ID `serialize:"true" json:"escrow_id"`
+	SenderBalance uint64 `serialize:"true" json:"sender_balance"`
+}
+
+func (*OpenEscrowResult) GetTypeID() uint8 {
+	return mconsts.OpenEscrowID
+}
+
+// ReleaseEscrow pays an escrow to its counterparty. Only the payer can
+// release, and only until the deadline.
+type ReleaseEscrow struct {
+	EscrowID ids.ID `serialize:"true" json:"escrow_id"`
+	// Counterparty must match the escrow, so its balance can be declared in
+	// [StateKeys].
+	Counterparty codec.Address `serialize:"true" json:"counterparty"`
+}
+
+func (*ReleaseEscrow) GetTypeID() uint8 {
+	return mconsts.ReleaseEscrowID
+}
+
+func (r *ReleaseEscrow) StateKeys(codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.EscrowKey(r.EscrowID)):      state.Read | state.Write,
+		string(storage.BalanceKey(r.Counterparty)): state.All,
+		string(storage.ActiveProposalKey()):        state.Read,
+		string(storage.HaltKey()):                  state.Read,
+	}
+}
+
+func (r *ReleaseEscrow) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, r, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	escrow, err := openEscrow(ctx, mu, r.EscrowID)
+	if err != nil {
+		return nil, err
+	}
+	if escrow.Payer != actor {
+		return nil, ErrNotEscrowPayer
+	}
+	if escrow.Counterparty != r.Counterparty {
+		return nil, ErrEscrowMismatch
+	}
+	if escrow.Deadline < timestamp {
+		return nil, ErrEscrowExpired
+	}
+	balance, err := closeEscrow(ctx, mu, r.EscrowID, escrow, escrow.Counterparty)
+	if err != nil {
+		return nil, err
+	}
+	return &ReleaseEscrowResult{
+		Amount:          escrow.Amount,
+		ReceiverBalance: balance,
+	}, nil
+}
+
+func (*ReleaseEscrow) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.ReleaseEscrowID, EscrowComputeUnits)
+}
+
+func (*ReleaseEscrow) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*ReleaseEscrowResult)(nil)
+
+type ReleaseEscrowResult struct {
+	Amount          uint64 `serialize:"true" json:"amount"`
+	ReceiverBalance uint64 `serialize:"true" json:"receiver_balance"`
+}
+
+func (*ReleaseEscrowResult) GetTypeID() uint8 {
+	return mconsts.ReleaseEscrowID
+}
+
+// RefundEscrow returns an escrow to its payer once the deadline has passed.
+// Anyone can submit it, since the funds can only go back to the payer.
+type RefundEscrow struct {
+	EscrowID ids.ID `serialize:"true" json:"escrow_id"`
+	// Payer must match the escrow, so its balance can be declared in
+	// [StateKeys].
+	Payer codec.Address `serialize:"true" json:"payer"`
+}
+
+func (*RefundEscrow) GetTypeID() uint8 {
+	return mconsts.RefundEscrowID
+}
+
+func (r *RefundEscrow) StateKeys(codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.EscrowKey(r.EscrowID)): state.Read | state.Write,
+		string(storage.BalanceKey(r.Payer)):   state.All,
+		string(storage.ActiveProposalKey()):   state.Read,
+		string(storage.HaltKey()):             state.Read,
+	}
+}
+
+func (r *RefundEscrow) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, r, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	escrow, err := openEscrow(ctx, mu, r.EscrowID)
+	if err != nil {
+		return nil, err
+	}
+	if escrow.Payer != r.Payer {
+		return nil, ErrEscrowMismatch
+	}
+	if escrow.Deadline >= timestamp {
+		return nil, ErrEscrowNotExpired
+	}
+	balance, err := closeEscrow(ctx, mu, r.EscrowID, escrow, escrow.Payer)
+	if err != nil {
+		return nil, err
+	}
+	return &RefundEscrowResult{
+		Amount:          escrow.Amount,
+		ReceiverBalance: balance,
+	}, nil
+}
+
+func (*RefundEscrow) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.RefundEscrowID, EscrowComputeUnits)
+}
+
+func (*RefundEscrow) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*RefundEscrowResult)(nil)
+
+type RefundEscrowResult struct {
+	Amount          uint64 `serialize:"true" json:"amount"`
+	ReceiverBalance uint64 `serialize:"true" json:"receiver_balance"`
+}
+
+func (*RefundEscrowResult) GetTypeID() uint8 {
+	return mconsts.RefundEscrowID
+}
+
+func openEscrow(ctx context.Context, im state.Immutable, escrowID ids.ID) (*storage.Escrow, error) {
+	escrow, exists, err := storage.GetEscrow(ctx, im, escrowID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrEscrowNotFound
+	}
+	return escrow, nil
+}
+
+// closeEscrow removes [escrow] and credits its amount to [to], returning the
+// new balance of [to].
+func closeEscrow(
+	ctx context.Context,
+	mu state.Mutable,
+	escrowID ids.ID,
+	escrow *storage.Escrow,
+	to codec.Address,
+) (uint64, error) {
+	if err := storage.DeleteEscrow(ctx, mu, escrowID); err != nil {
+		return 0, err
+	}
+	return storage.AddBalance(ctx, mu, to, escrow.Amount, true)
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestEscrowActions(t *testing.T) {
+	payer := codectest.NewRandomAddress()
+	counterparty := codectest.NewRandomAddress()
+	escrowID := storage.EscrowID(payer, 1)
+
+	funded := func() state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(context.Background(), store, payer, 10))
+		return store
+	}
+	// open has 10 in escrow for [counterparty] until timestamp 100.
+	open := func() state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetEscrow(context.Background(), store, escrowID, &storage.Escrow{
+			Payer:        payer,
+			Counterparty: counterparty,
+			Amount:       10,
+			Deadline:     100,
+		}))
+		return store
+	}
+	// paid checks that the escrow is gone and [to] holds its amount.
+	paid := func(to codec.Address) func(context.Context, *testing.T, state.Mutable) {
+		return func(ctx context.Context, t *testing.T, store state.Mutable) {
+			balance, err := storage.GetBalance(ctx, store, to)
+			require.NoError(t, err)
+			require.Equal(t, uint64(10), balance)
+			_, exists, err := storage.GetEscrow(ctx, store, escrowID)
+			require.NoError(t, err)
+			require.False(t, exists)
+		}
+	}
+	openAction := &OpenEscrow{
+		Nonce:        1,
+		Counterparty: counterparty,
+		Amount:       10,
+		Deadline:     100,
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "SelfEscrow",
+			Actor: payer,
+			Action: &OpenEscrow{
+				Nonce:        1,
+				Counterparty: payer,
+				Amount:       10,
+				Deadline:     100,
+			},
+			State:       funded(),
+			ExpectedErr: ErrSelfEscrow,
+		},
+		{
+			Name:        "DeadlineInThePast",
+			Actor:       payer,
+			Action:      openAction,
+			Timestamp:   101,
+			State:       funded(),
+			ExpectedErr: ErrDeadlineInThePast,
+		},
+		{
+			Name:        "EscrowExists",
+			Actor:       payer,
+			Action:      openAction,
+			State:       open(),
+			ExpectedErr: ErrEscrowExists,
+		},
+		{
+			Name:   "Open",
+			Actor:  payer,
+			Action: openAction,
+			State:  funded(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				escrow, exists, err := storage.GetEscrow(ctx, store, escrowID)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, payer, escrow.Payer)
+				require.Equal(t, uint64(10), escrow.Amount)
+			},
+			ExpectedOutputs: &OpenEscrowResult{
+				EscrowID:      escrowID,
+				SenderBalance: 0,
+			},
+		},
+		{
+			Name:        "ReleaseByCounterparty",
+			Actor:       counterparty,
+			Action:      &ReleaseEscrow{EscrowID: escrowID, Counterparty: counterparty},
+			State:       open(),
+			ExpectedErr: ErrNotEscrowPayer,
+		},
+		{
+			Name:        "ReleaseMismatch",
+			Actor:       payer,
+			Action:      &ReleaseEscrow{EscrowID: escrowID, Counterparty: payer},
+			State:       open(),
+			ExpectedErr: ErrEscrowMismatch,
+		},
+		{
+			Name:        "ReleaseAfterDeadline",
+			Actor:       payer,
+			Action:      &ReleaseEscrow{EscrowID: escrowID, Counterparty: counterparty},
+			Timestamp:   101,
+			State:       open(),
+			ExpectedErr: ErrEscrowExpired,
+		},
+		{
+			Name:      "Release",
+			Actor:     payer,
+			Action:    &ReleaseEscrow{EscrowID: escrowID, Counterparty: counterparty},
+			Timestamp: 100,
+			State:     open(),
+			Assertion: paid(counterparty),
+			ExpectedOutputs: &ReleaseEscrowResult{
+				Amount:          10,
+				ReceiverBalance: 10,
+			},
+		},
+		{
+			Name:        "RefundBeforeDeadline",
+			Actor:       counterparty,
+			Action:      &RefundEscrow{EscrowID: escrowID, Payer: payer},
+			Timestamp:   100,
+			State:       open(),
+			ExpectedErr: ErrEscrowNotExpired,
+		},
+		{
+			Name:      "Refund",
+			Actor:     counterparty,
+			Action:    &RefundEscrow{EscrowID: escrowID, Payer: payer},
+			Timestamp: 101,
+			State:     open(),
+			Assertion: paid(payer),
+			ExpectedOutputs: &RefundEscrowResult{
+				Amount:          10,
+				ReceiverBalance: 10,
+			},
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// Kinds of [Event].
+const (
+	TransferEventKind  = "transfer"
+	OwnershipEventKind = "ownership"
+)
+
+var (
+	_ Event = (*TransferEvent)(nil)
+	_ Event = (*OwnershipEvent)(nil)
+
+	_ EventSource = (*Transfer)(nil)
+	_ EventSource = (*TransferFrom)(nil)
+	_ EventSource = (*BatchTransfer)(nil)
+	_ EventSource = (*TransferAsset)(nil)
+	_ EventSource = (*AssetTransfer)(nil)
+	_ EventSource = (*MintAsset)(nil)
+	_ EventSource = (*BurnAsset)(nil)
+	_ EventSource = (*ProposeMultisigTx)(nil)
+	_ EventSource = (*ApproveMultisigTx)(nil)
+	_ EventSource = (*ExecuteFromSmartAccount)(nil)
+	_ EventSource = (*FillOrder)(nil)
+	_ EventSource = (*RedeemVoucher)(nil)
+	_ EventSource = (*CreateStablecoin)(nil)
+)
+
+// Event is something an action did that indexers follow, such as moving
+// tokens. Events are not stored in state: the VM logs them for each
+// successful action of an accepted block.
+type Event interface {
+	// Kind is one of the event kinds, such as [TransferEventKind].
+	Kind() string
+	// Addresses and Assets are what the event is filtered by.
+	Addresses() []codec.Address
+	Assets() []ids.ID
+}
+
+// Emitter collects the events of an action.
+type Emitter interface {
+	Emit(Event)
+}
+
+// Events is an [Emitter] keeping events in the order they were emitted.
+type Events []Event
+
+func (e *Events) Emit(event Event) {
+	*e = append(*e, event)
+}
+
+// EventSource is implemented by actions that emit events. EmitEvents is
+// called with the actor and output of a successful Execute, and emits the
+// events of that execution in order.
+//
+// hypersdk passes its own context to Execute, so an emitter cannot reach
+// it there. Events are derived from the output instead, which every node
+// agrees on.
+type EventSource interface {
+	EmitEvents(actor codec.Address, output codec.Typed, e Emitter)
+}
+
+// TransferEvent is [Amount] of [Asset] moved from [From] to [To]. [Asset]
+// is [storage.NativeAsset] for the native token.
+type TransferEvent struct {
+	From   codec.Address `json:"from"`
+	To     codec.Address `json:"to"`
+	Asset  ids.ID        `json:"asset"`
+	Amount uint64        `json:"amount"`
+}
+
+func (*TransferEvent) Kind() string {
+	return TransferEventKind
+}
+
+func (t *TransferEvent) Addresses() []codec.Address {
+	return []codec.Address{t.From, t.To}
+}
+
+func (t *TransferEvent) Assets() []ids.ID {
+	return []ids.ID{t.Asset}
+}
+
+// OwnershipEvent is the unique [Asset] passing from [From] to [To]. [From]
+// is empty when it is minted and [To] when it is burned.
+type OwnershipEvent struct {
+	Asset ids.ID        `json:"asset"`
+	From  codec.Address `json:"from"`
+	To    codec.Address `json:"to"`
+	// Reason is the reason given by an [AssetTransfer], if any.
+	Reason string `json:"reason,omitempty"`
+}
+
+func (*OwnershipEvent) Kind() string {
+	return OwnershipEventKind
+}
+
+func (o *OwnershipEvent) Addresses() []codec.Address {
+	addrs := make([]codec.Address, 0, 2)
+	for _, addr := range []codec.Address{o.From, o.To} {
+		if addr != codec.EmptyAddress {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+func (o *OwnershipEvent) Assets() []ids.ID {
+	return []ids.ID{o.Asset}
+}
+
+// emitMultisigTx emits the events of [tx] made from [account], as run by
+// [multisigAction].
+func emitMultisigTx(account codec.Address, tx storage.MultisigTx, e Emitter) {
+	if tx.Asset == storage.NativeAsset {
+		e.Emit(&TransferEvent{From: account, To: tx.To, Asset: storage.NativeAsset, Amount: tx.Value})
+		return
+	}
+	e.Emit(&OwnershipEvent{Asset: tx.Asset, From: account, To: tx.To})
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+func TestEmitEvents(t *testing.T) {
+	actor := codectest.NewRandomAddress()
+	to := codectest.NewRandomAddress()
+	payee := codectest.NewRandomAddress()
+	multisig := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+	pay := storage.MultisigTx{To: to, Asset: storage.NativeAsset, Value: 4}
+
+	tests := []struct {
+		name     string
+		source   EventSource
+		output   codec.Typed
+		expected Events
+	}{
+		{
+			name:   "BatchTransfer",
+			source: &BatchTransfer{Transfers: []BatchTransferEntry{{To: to, Value: 1}, {To: payee, Value: 2}}},
+			output: &BatchTransferResult{},
+			expected: Events{
+				&TransferEvent{From: actor, To: to, Asset: storage.NativeAsset, Amount: 1},
+				&TransferEvent{From: actor, To: payee, Asset: storage.NativeAsset, Amount: 2},
+			},
+		},
+		{
+			name:   "AssetTransferWithRoyalty",
+			source: &AssetTransfer{Recipient: to, Asset: asset, Price: 100, RoyaltyPayee: payee},
+			output: &AssetTransferResult{OldOwner: actor, NewOwner: to, Price: 100, Royalty: 5, RoyaltyPayee: payee},
+			expected: Events{
+				&OwnershipEvent{Asset: asset, From: actor, To: to},
+				&TransferEvent{From: actor, To: payee, Asset: storage.NativeAsset, Amount: 5},
+			},
+		},
+		{
+			name:   "SettleAuctionWithRoyalty",
+			source: &SettleAuction{Asset: asset, Seller: actor, Bidder: to, RoyaltyPayee: payee},
+			output: &SettleAuctionResult{Winner: to, Paid: 100, Royalty: 5, RoyaltyPayee: payee},
+			expected: Events{
+				&TransferEvent{From: storage.AuctionAddress(asset), To: payee, Asset: storage.NativeAsset, Amount: 5},
+				&TransferEvent{From: storage.AuctionAddress(asset), To: actor, Asset: storage.NativeAsset, Amount: 95},
+				&OwnershipEvent{Asset: asset, From: storage.AuctionAddress(asset), To: to},
+			},
+		},
+		{
+			name: "OnboardAccount",
+			source: &OnboardAccount{
+				PublicKey: make([]byte, ed25519.PublicKeyLen),
+				Value:     3,
+				Assets:    []OnboardAsset{{Asset: asset, Value: 2}},
+			},
+			output: &OnboardAccountResult{},
+			expected: Events{
+				&TransferEvent{From: actor, To: auth.NewED25519Address(ed25519.EmptyPublicKey), Asset: storage.NativeAsset, Amount: 3},
+				&TransferEvent{From: actor, To: auth.NewED25519Address(ed25519.EmptyPublicKey), Asset: asset, Amount: 2},
+			},
+		},
+		{
+			name:   "TransferWithReceipt",
+			source: &Transfer{To: to, Value: 1, Receipt: asset},
+			output: &TransferResult{},
+			expected: Events{
+				&TransferEvent{From: actor, To: to, Asset: storage.NativeAsset, Amount: 1},
+				&OwnershipEvent{Asset: asset, To: actor},
+			},
+		},
+		{
+			name:   "RedeemVoucher",
+			source: &RedeemVoucher{},
+			output: &RedeemVoucherResult{Asset: asset, Creator: payee, Owner: actor, Price: 6},
+			expected: Events{
+				&OwnershipEvent{Asset: asset, To: actor},
+				&TransferEvent{From: actor, To: payee, Asset: storage.NativeAsset, Amount: 6},
+			},
+		},
+		{
+			name:     "BurnAsset",
+			source:   &BurnAsset{Asset: asset},
+			output:   &BurnAssetResult{Asset: asset, PreviousOwner: actor},
+			expected: Events{&OwnershipEvent{Asset: asset, From: actor}},
+		},
+		{
+			name:   "PendingMultisigTx",
+			source: &ApproveMultisigTx{Multisig: multisig, Tx: pay},
+			output: &ApproveMultisigTxResult{Approvals: 1},
+		},
+		{
+			name:     "ExecutedMultisigTx",
+			source:   &ApproveMultisigTx{Multisig: multisig, Tx: pay},
+			output:   &ApproveMultisigTxResult{Approvals: 2, Executed: true},
+			expected: Events{&TransferEvent{From: multisig, To: to, Asset: storage.NativeAsset, Amount: 4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events Events
+			tt.source.EmitEvents(actor, tt.output, &events)
+			require.Equal(t, tt.expected, events)
+		})
+	}
+
+	// Minted and burned assets are only filtered by their owner.
+	require.Equal(t, []codec.Address{actor}, (&OwnershipEvent{Asset: asset, To: actor}).Addresses())
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const FreezeAssetComputeUnits = 1
+
+var (
+	ErrNotAssetMinter = errors.New("actor did not mint the asset")
+	ErrAssetFrozen    = errors.New("asset is frozen")
+
+	_ chain.Action = (*FreezeAsset)(nil)
+	_ chain.Action = (*UnfreezeAsset)(nil)
+)
+
+// FreezeAsset stops [Asset] from being transferred until it is unfrozen.
+// Only the original minter may freeze an asset, even if it has changed hands.
+type FreezeAsset struct {
+	Asset ids.ID `serialize:"true" json:"asset"`
+}
+
+func (*FreezeAsset) GetTypeID() uint8 {
+	return mconsts.FreezeAssetID
+}
+
+func (f *FreezeAsset) StateKeys(codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.AssetKey(f.Asset)): state.Read | state.Write,
+		string(storage.HaltKey()):         state.Read,
+	}
+}
+
+func (f *FreezeAsset) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, f, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if err := setFrozen(ctx, mu, f.Asset, actor, true); err != nil {
+		return nil, err
+	}
+	return &FreezeAssetResult{Asset: f.Asset}, nil
+}
+
+func (*FreezeAsset) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.FreezeAssetID, FreezeAssetComputeUnits)
+}
+
+func (*FreezeAsset) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*FreezeAssetResult)(nil)
+
+type FreezeAssetResult struct {
+	Asset ids.ID `serialize:"true" json:"asset"`
+}
+
+func (*FreezeAssetResult) GetTypeID() uint8 {
+	return mconsts.FreezeAssetID
+}
+
+// UnfreezeAsset lets a frozen [Asset] be transferred again. Only the
+// original minter may unfreeze an asset.
+type UnfreezeAsset struct {
+	Asset ids.ID `serialize:"true" json:"asset"`
+}
+
+func (*UnfreezeAsset) GetTypeID() uint8 {
+	return mconsts.UnfreezeAssetID
+}
+
+func (u *UnfreezeAsset) StateKeys(codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.AssetKey(u.Asset)): state.Read | state.Write,
+		string(storage.HaltKey()):         state.Read,
+	}
+}
+
+func (u *UnfreezeAsset) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, u, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if err := setFrozen(ctx, mu, u.Asset, actor, false); err != nil {
+		return nil, err
+	}
+	return &UnfreezeAssetResult{Asset: u.Asset}, nil
+}
+
+func (*UnfreezeAsset) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.UnfreezeAssetID, FreezeAssetComputeUnits)
+}
+
+func (*UnfreezeAsset) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*UnfreezeAssetResult)(nil)
+
+type UnfreezeAssetResult struct {
+	Asset ids.ID `serialize:"true" json:"asset"`
+}
+
+func (*UnfreezeAssetResult) GetTypeID() uint8 {
+	return mconsts.UnfreezeAssetID
+}
+
+func setFrozen(
+	ctx context.Context,
+	mu state.Mutable,
+	assetID ids.ID,
+	actor codec.Address,
+	frozen bool,
+) error {
+	control, err := storage.GetAssetControl(ctx, mu, assetID)
+	if err != nil {
+		return err
+	}
+	if control.Minter != actor {
+		return ErrNotAssetMinter
+	}
+	return storage.SetAssetFrozen(ctx, mu, assetID, frozen)
+}
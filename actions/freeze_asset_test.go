@@ -0,0 +1,107 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestFreezeAssetAction(t *testing.T) {
+	minter := codectest.NewRandomAddress()
+	holder := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+
+	// held returns state where [minter] minted [asset] and handed it to
+	// [holder].
+	held := func(frozen bool) state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.CreateAsset(ctx, store, asset, minter))
+		require.NoError(t, storage.ChangeAssetOwner(ctx, store, asset, holder))
+		require.NoError(t, storage.SetAssetFrozen(ctx, store, asset, frozen))
+		return store
+	}
+	frozen := func(want bool) func(context.Context, *testing.T, state.Mutable) {
+		return func(ctx context.Context, t *testing.T, store state.Mutable) {
+			control, err := storage.GetAssetControl(ctx, store, asset)
+			require.NoError(t, err)
+			require.Equal(t, want, control.Frozen)
+			require.Equal(t, minter, control.Minter)
+		}
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:        "AssetNotFound",
+			Actor:       minter,
+			Action:      &FreezeAsset{Asset: asset},
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: storage.ErrAssetNotFound,
+		},
+		{
+			Name:        "HolderCannotFreeze",
+			Actor:       holder,
+			Action:      &FreezeAsset{Asset: asset},
+			State:       held(false),
+			ExpectedErr: ErrNotAssetMinter,
+		},
+		{
+			Name:            "MinterFreezes",
+			Actor:           minter,
+			Action:          &FreezeAsset{Asset: asset},
+			State:           held(false),
+			Assertion:       frozen(true),
+			ExpectedOutputs: &FreezeAssetResult{Asset: asset},
+		},
+		{
+			Name:        "HolderCannotUnfreeze",
+			Actor:       holder,
+			Action:      &UnfreezeAsset{Asset: asset},
+			State:       held(true),
+			ExpectedErr: ErrNotAssetMinter,
+		},
+		{
+			Name:            "MinterUnfreezes",
+			Actor:           minter,
+			Action:          &UnfreezeAsset{Asset: asset},
+			State:           held(true),
+			Assertion:       frozen(false),
+			ExpectedOutputs: &UnfreezeAssetResult{Asset: asset},
+		},
+		{
+			Name:  "FrozenTransfer",
+			Actor: holder,
+			Action: &AssetTransfer{
+				Recipient: minter,
+				Asset:     asset,
+			},
+			State:       held(true),
+			ExpectedErr: ErrAssetFrozen,
+		},
+		{
+			Name:  "UnfrozenTransfer",
+			Actor: holder,
+			Action: &AssetTransfer{
+				Recipient: minter,
+				Asset:     asset,
+			},
+			State: held(false),
+			ExpectedOutputs: &AssetTransferResult{
+				OldOwner: holder,
+				NewOwner: minter,
+			},
+		},
+	}
+
+	runActionTests(t, tests)
+}
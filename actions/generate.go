@@ -0,0 +1,6 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+//go:generate go run ../cmd/statekeys -actions . -storage ../storage
@@ -0,0 +1,442 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const (
+	GovernanceComputeUnits = 1
+
+	// GovernanceVotingPeriod, GovernanceQuorum and GovernanceDeposit apply
+	// when the rules do not set [GovernanceVotingPeriodRule],
+	// [GovernanceQuorumRule] and [GovernanceDepositRule].
+	GovernanceVotingPeriod = 1_000
+	GovernanceQuorum       = 1_000_000_000_000
+	GovernanceDeposit      = 1_000_000_000
+
+	// MaxGovernedMemoSize bounds the memo size governance can set.
+	MaxGovernedMemoSize = 1_024
+)
+
+// Keys of the governance rules, read with chain.Rules.FetchCustom.
+const (
+	// GovernanceVotingPeriodRule is the number of blocks a proposal is open
+	// for votes.
+	GovernanceVotingPeriodRule = "governanceVotingPeriod"
+	// GovernanceQuorumRule is the native balance that must vote on a
+	// proposal for it to pass.
+	GovernanceQuorumRule = "governanceQuorum"
+	// GovernanceDepositRule is the native balance a proposer bonds to
+	// create a proposal. It is refunded if the proposal passes, and burned
+	// otherwise.
+	GovernanceDepositRule = "governanceDeposit"
+)
+
+var (
+	ErrVotingOpen           = errors.New("proposal voting is still open")
+	ErrVotingClosed         = errors.New("proposal voting has ended")
+	ErrProposalExecuted     = errors.New("proposal already executed")
+	ErrAlreadyVoted         = errors.New("actor already voted")
+	ErrNoVotingPower        = errors.New("actor held no balance when the proposal was created")
+	ErrParameterNotGoverned = errors.New("parameter is not governed")
+	ErrParameterOutOfRange  = errors.New("parameter value out of range")
+	ErrDuplicateParameter   = errors.New("parameter changed twice")
+	ErrParameterKind        = errors.New("parameter value of the wrong kind")
+	ErrProposalDeposit      = errors.New("proposer cannot bond the proposal deposit")
+
+	_ chain.Action = (*CreateProposal)(nil)
+	_ chain.Action = (*Vote)(nil)
+	_ chain.Action = (*ExecuteProposal)(nil)
+)
+
+// CreateProposal opens a proposal to apply [Changes], voted on with the
+// native balances held when it is created. Only one proposal is open for
+// votes at a time. The proposer bonds the deposit of the rules, which is
+// not counted in its votes.
+type CreateProposal struct {
+	// ProposalID is chosen by the proposer and must not have been used.
+	ProposalID ids.ID                    `serialize:"true" json:"proposal_id"`
+	Text       string                    `serialize:"true" json:"text"`
+	Changes    []storage.ParameterChange `serialize:"true" json:"changes"`
+}
+
+func (*CreateProposal) GetTypeID() uint8 {
+	return mconsts.CreateProposalID
+}
+
+func (c *CreateProposal) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.BalanceKey(actor)):                   state.Read | state.Write,
+		string(storage.ActiveProposalKey()):                 state.All,
+		string(storage.GovernanceProposalKey(c.ProposalID)): state.All,
+		string(chain.HeightKey(storage.HeightKey())):        state.Read,
+	}
+}
+
+func (c *CreateProposal) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, c, actor)
+	defer span.End()
+
+	if err := verifyParameterChanges(c.Changes); err != nil {
+		return nil, err
+	}
+	parent, err := storage.GetHeight(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	height := parent + 1
+	active, exists, err := storage.GetActiveProposal(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	if exists && height <= active.VotingEnd {
+		return nil, ErrVotingOpen
+	}
+	_, exists, err = storage.GetGovernanceProposal(ctx, mu, c.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrProposalExists
+	}
+	votingPeriod, _, deposit := GovernanceRules(r)
+	votingEnd, err := smath.Add(height, votingPeriod-1)
+	if err != nil {
+		return nil, err
+	}
+	// The deposit leaves the balance before the snapshot is taken.
+	if _, err := storage.SubBalance(ctx, mu, actor, deposit); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrProposalDeposit, err)
+	}
+	proposal := &storage.GovernanceProposal{
+		Proposer:       actor,
+		Deposit:        deposit,
+		SnapshotHeight: height,
+		VotingEnd:      votingEnd,
+		Text:           c.Text,
+		Changes:        c.Changes,
+	}
+	if err := storage.SetGovernanceProposal(ctx, mu, c.ProposalID, proposal); err != nil {
+		return nil, err
+	}
+	if err := storage.SetActiveProposal(ctx, mu, &storage.ActiveProposal{
+		ProposalID:     c.ProposalID,
+		SnapshotHeight: height,
+		VotingEnd:      votingEnd,
+	}); err != nil {
+		return nil, err
+	}
+	return &CreateProposalResult{
+		SnapshotHeight: height,
+		VotingEnd:      votingEnd,
+	}, nil
+}
+
+func (*CreateProposal) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.CreateProposalID, GovernanceComputeUnits)
+}
+
+func (*CreateProposal) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*CreateProposalResult)(nil)
+
+type CreateProposalResult struct {
+	SnapshotHeight uint64 `serialize:"true" json:"snapshot_height"`
+	VotingEnd      uint64 `serialize:"true" json:"voting_end"`
+}
+
+func (*CreateProposalResult) GetTypeID() uint8 {
+	return mconsts.CreateProposalID
+}
+
+// Vote casts the native balance the actor held when the proposal was
+// created for or against it. A vote cannot be changed.
+type Vote struct {
+	ProposalID ids.ID `serialize:"true" json:"proposal_id"`
+	Support    bool   `serialize:"true" json:"support"`
+}
+
+func (*Vote) GetTypeID() uint8 {
+	return mconsts.VoteID
+}
+
+func (v *Vote) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.GovernanceProposalKey(v.ProposalID)):    state.Read | state.Write,
+		string(storage.GovernanceVoteKey(v.ProposalID, actor)): state.All,
+		string(storage.BalanceKey(actor)):                      state.Read,
+		string(chain.HeightKey(storage.HeightKey())):           state.Read,
+	}
+}
+
+func (v *Vote) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, v, actor)
+	defer span.End()
+
+	proposal, exists, err := storage.GetGovernanceProposal(ctx, mu, v.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrProposalNotFound
+	}
+	parent, err := storage.GetHeight(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	if parent+1 > proposal.VotingEnd {
+		return nil, ErrVotingClosed
+	}
+	_, exists, err = storage.GetGovernanceVote(ctx, mu, v.ProposalID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrAlreadyVoted
+	}
+	// The proposal has been active since it was created, as no other can be
+	// created before its voting ends.
+	weight, err := storage.GetSnapshotBalance(ctx, mu, actor, proposal.SnapshotHeight)
+	if err != nil {
+		return nil, err
+	}
+	if weight == 0 {
+		return nil, ErrNoVotingPower
+	}
+	tally := &proposal.No
+	if v.Support {
+		tally = &proposal.Yes
+	}
+	if *tally, err = smath.Add(*tally, weight); err != nil {
+		return nil, err
+	}
+	if err := storage.SetGovernanceVote(ctx, mu, v.ProposalID, actor, &storage.GovernanceVote{
+		Support: v.Support,
+		Weight:  weight,
+	}); err != nil {
+		return nil, err
+	}
+	if err := storage.SetGovernanceProposal(ctx, mu, v.ProposalID, proposal); err != nil {
+		return nil, err
+	}
+	return &VoteResult{
+		Weight: weight,
+		Yes:    proposal.Yes,
+		No:     proposal.No,
+	}, nil
+}
+
+func (*Vote) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.VoteID, GovernanceComputeUnits)
+}
+
+func (*Vote) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*VoteResult)(nil)
+
+type VoteResult struct {
+	Weight uint64 `serialize:"true" json:"weight"`
+	Yes    uint64 `serialize:"true" json:"yes"`
+	No     uint64 `serialize:"true" json:"no"`
+}
+
+func (*VoteResult) GetTypeID() uint8 {
+	return mconsts.VoteID
+}
+
+// ExecuteProposal closes a proposal once its voting has ended. If the votes
+// reached the quorum and most of them supported it, its changes are applied
+// and the proposer's deposit is refunded. Anyone can execute a proposal.
+type ExecuteProposal struct {
+	ProposalID ids.ID `serialize:"true" json:"proposal_id"`
+	// Proposer must match the proposal, so the balance its deposit is
+	// refunded to can be declared in [StateKeys].
+	Proposer codec.Address `serialize:"true" json:"proposer"`
+}
+
+func (*ExecuteProposal) GetTypeID() uint8 {
+	return mconsts.ExecuteProposalID
+}
+
+func (e *ExecuteProposal) StateKeys(codec.Address) state.Keys {
+	keys := state.Keys{
+		string(storage.GovernanceProposalKey(e.ProposalID)): state.Read | state.Write,
+		string(storage.ActiveProposalKey()):                 state.Read | state.Write,
+		string(storage.BalanceKey(e.Proposer)):              state.All,
+		string(chain.HeightKey(storage.HeightKey())):        state.Read,
+	}
+	// The changes are only known once the proposal is read.
+	for key := range Config {
+		keys.Add(string(storage.ConfigKey(key)), state.Allocate|state.Write)
+	}
+	return keys
+}
+
+func (e *ExecuteProposal) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, e, actor)
+	defer span.End()
+
+	proposal, exists, err := storage.GetGovernanceProposal(ctx, mu, e.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrProposalNotFound
+	}
+	if proposal.Executed {
+		return nil, ErrProposalExecuted
+	}
+	if proposal.Proposer != e.Proposer {
+		return nil, ErrProposalMismatch
+	}
+	parent, err := storage.GetHeight(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	if parent+1 <= proposal.VotingEnd {
+		return nil, ErrVotingOpen
+	}
+	_, quorum, _ := GovernanceRules(r)
+	turnout, err := smath.Add(proposal.Yes, proposal.No)
+	if err != nil {
+		return nil, err
+	}
+	proposal.Executed = true
+	proposal.Passed = turnout >= quorum && proposal.Yes > proposal.No
+	if proposal.Passed {
+		for _, c := range proposal.Changes {
+			if err := storage.SetConfigValue(ctx, mu, c.Parameter, Config[c.Parameter].encode(c)); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := storage.AddBalance(ctx, mu, proposal.Proposer, proposal.Deposit, true); err != nil {
+			return nil, err
+		}
+	}
+	// The record is kept so that its ID and votes cannot be reused.
+	if err := storage.SetGovernanceProposal(ctx, mu, e.ProposalID, proposal); err != nil {
+		return nil, err
+	}
+	active, exists, err := storage.GetActiveProposal(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	if exists && active.ProposalID == e.ProposalID {
+		if err := storage.DeleteActiveProposal(ctx, mu); err != nil {
+			return nil, err
+		}
+	}
+	return &ExecuteProposalResult{
+		Passed: proposal.Passed,
+		Yes:    proposal.Yes,
+		No:     proposal.No,
+	}, nil
+}
+
+func (*ExecuteProposal) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.ExecuteProposalID, GovernanceComputeUnits)
+}
+
+func (*ExecuteProposal) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*ExecuteProposalResult)(nil)
+
+type ExecuteProposalResult struct {
+	Passed bool   `serialize:"true" json:"passed"`
+	Yes    uint64 `serialize:"true" json:"yes"`
+	No     uint64 `serialize:"true" json:"no"`
+}
+
+func (*ExecuteProposalResult) GetTypeID() uint8 {
+	return mconsts.ExecuteProposalID
+}
+
+// GovernanceRules returns the voting period, quorum and proposal deposit of
+// [r].
+func GovernanceRules(r chain.Rules) (uint64, uint64, uint64) {
+	votingPeriod, quorum, deposit := uint64(GovernanceVotingPeriod), uint64(GovernanceQuorum), uint64(GovernanceDeposit)
+	if r == nil {
+		return votingPeriod, quorum, deposit
+	}
+	if v, ok := r.FetchCustom(GovernanceVotingPeriodRule); ok {
+		if n, ok := v.(uint64); ok && n > 0 {
+			votingPeriod = n
+		}
+	}
+	if v, ok := r.FetchCustom(GovernanceQuorumRule); ok {
+		if n, ok := v.(uint64); ok {
+			quorum = n
+		}
+	}
+	if v, ok := r.FetchCustom(GovernanceDepositRule); ok {
+		if n, ok := v.(uint64); ok {
+			deposit = n
+		}
+	}
+	return votingPeriod, quorum, deposit
+}
+
+func verifyParameterChanges(changes []storage.ParameterChange) error {
+	seen := make(map[string]bool, len(changes))
+	for _, c := range changes {
+		entry, ok := Config[c.Parameter]
+		switch {
+		case !ok:
+			return fmt.Errorf("%w: %q", ErrParameterNotGoverned, c.Parameter)
+		case entry.Kind == ConfigUint64 && c.Address != codec.EmptyAddress,
+			entry.Kind == ConfigAddress && c.Value != 0:
+			return fmt.Errorf("%w: %s takes a %s", ErrParameterKind, c.Parameter, entry.Kind)
+		case entry.Kind == ConfigUint64 && c.Value > entry.Max:
+			return fmt.Errorf("%w: %s=%d exceeds %d", ErrParameterOutOfRange, c.Parameter, c.Value, entry.Max)
+		case seen[c.Parameter]:
+			return fmt.Errorf("%w: %s", ErrDuplicateParameter, c.Parameter)
+		}
+		seen[c.Parameter] = true
+	}
+	return nil
+}
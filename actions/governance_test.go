@@ -0,0 +1,392 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// governanceRules sets the governance rules read through FetchCustom.
+type governanceRules struct {
+	*genesis.Rules
+	votingPeriod uint64
+	quorum       uint64
+	deposit      uint64
+}
+
+func (r *governanceRules) FetchCustom(key string) (any, bool) {
+	switch key {
+	case GovernanceVotingPeriodRule:
+		return r.votingPeriod, true
+	case GovernanceQuorumRule:
+		return r.quorum, true
+	case GovernanceDepositRule:
+		return r.deposit, true
+	default:
+		return nil, false
+	}
+}
+
+func TestGovernanceActions(t *testing.T) {
+	proposer := codectest.NewRandomAddress()
+	holder := codectest.NewRandomAddress()
+	seller := codectest.NewRandomAddress()
+	buyer := codectest.NewRandomAddress()
+	proposalID := ids.GenerateTestID()
+	// Proposals are open for 10 blocks, pass with 1,000 votes and bond 100.
+	// The parent is at height 29, so the actions run at height 30.
+	rules := &governanceRules{Rules: genesis.NewDefaultRules(), votingPeriod: 10, quorum: 1_000, deposit: 100}
+	change := storage.ParameterChange{Parameter: MaxMemoSizeRule, Value: 512}
+
+	// setup has a proposal created at height 25 and open until [votingEnd]
+	// if it is non-zero, with [yes] votes in favor. [holder] held 800 when
+	// it was created and kept it, [seller] sold all of its 1,000 to [buyer]
+	// afterwards. [proposer] holds 150, less the deposit of the proposal.
+	setup := func(votingEnd uint64, yes uint64) state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, store.Insert(ctx, chain.HeightKey(storage.HeightKey()), binary.BigEndian.AppendUint64(nil, 29)))
+		require.NoError(t, storage.SetBalance(ctx, store, proposer, 150))
+		require.NoError(t, storage.SetBalance(ctx, store, holder, 800))
+		require.NoError(t, storage.SetBalance(ctx, store, seller, 1_000))
+		if votingEnd == 0 {
+			return store
+		}
+		_, err := storage.SubBalance(ctx, store, proposer, 100)
+		require.NoError(t, err)
+		require.NoError(t, storage.SetGovernanceProposal(ctx, store, proposalID, &storage.GovernanceProposal{
+			Proposer:       proposer,
+			Deposit:        100,
+			SnapshotHeight: 25,
+			VotingEnd:      votingEnd,
+			Yes:            yes,
+			Changes:        []storage.ParameterChange{change},
+		}))
+		require.NoError(t, storage.SetActiveProposal(ctx, store, &storage.ActiveProposal{
+			ProposalID:     proposalID,
+			SnapshotHeight: 25,
+			VotingEnd:      votingEnd,
+		}))
+		_, err = storage.SubBalance(ctx, store, seller, 1_000)
+		require.NoError(t, err)
+		_, err = storage.AddBalance(ctx, store, buyer, 1_000, true)
+		require.NoError(t, err)
+		return store
+	}
+	requireProposal := func(ctx context.Context, t *testing.T, store state.Mutable) *storage.GovernanceProposal {
+		proposal, exists, err := storage.GetGovernanceProposal(ctx, store, proposalID)
+		require.NoError(t, err)
+		require.True(t, exists)
+		return proposal
+	}
+	requireBalance := func(ctx context.Context, t *testing.T, store state.Mutable, addr codec.Address, expected uint64) {
+		balance, err := storage.GetBalance(ctx, store, addr)
+		require.NoError(t, err)
+		require.Equal(t, expected, balance)
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "NotGoverned",
+			Actor: proposer,
+			Action: &CreateProposal{
+				ProposalID: proposalID,
+				Changes:    []storage.ParameterChange{{Parameter: MemoBytesPerComputeUnitRule, Value: 1}},
+			},
+			Rules:       rules,
+			State:       setup(0, 0),
+			ExpectedErr: ErrParameterNotGoverned,
+		},
+		{
+			Name:  "OutOfRange",
+			Actor: proposer,
+			Action: &CreateProposal{
+				ProposalID: proposalID,
+				Changes:    []storage.ParameterChange{{Parameter: StakingRewardRateRule, Value: 10_001}},
+			},
+			Rules:       rules,
+			State:       setup(0, 0),
+			ExpectedErr: ErrParameterOutOfRange,
+		},
+		{
+			Name:  "WrongKind",
+			Actor: proposer,
+			Action: &CreateProposal{
+				ProposalID: proposalID,
+				Changes:    []storage.ParameterChange{{Parameter: MaintenanceAddressRule, Value: 1}},
+			},
+			Rules:       rules,
+			State:       setup(0, 0),
+			ExpectedErr: ErrParameterKind,
+		},
+		{
+			Name:  "DuplicateParameter",
+			Actor: proposer,
+			Action: &CreateProposal{
+				ProposalID: proposalID,
+				Changes:    []storage.ParameterChange{change, change},
+			},
+			Rules:       rules,
+			State:       setup(0, 0),
+			ExpectedErr: ErrDuplicateParameter,
+		},
+		{
+			Name:        "CreateWhileVotingOpen",
+			Actor:       proposer,
+			Action:      &CreateProposal{ProposalID: ids.GenerateTestID()},
+			Rules:       rules,
+			State:       setup(30, 0),
+			ExpectedErr: ErrVotingOpen,
+		},
+		{
+			Name:        "CreateExisting",
+			Actor:       proposer,
+			Action:      &CreateProposal{ProposalID: proposalID},
+			Rules:       rules,
+			State:       setup(29, 0),
+			ExpectedErr: ErrProposalExists,
+		},
+		{
+			Name:  "Create",
+			Actor: proposer,
+			Action: &CreateProposal{
+				ProposalID: proposalID,
+				Text:       "raise the memo size",
+				Changes:    []storage.ParameterChange{change},
+			},
+			Rules: rules,
+			State: setup(0, 0),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				require.Equal(t, &storage.GovernanceProposal{
+					Proposer:       proposer,
+					Deposit:        100,
+					SnapshotHeight: 30,
+					VotingEnd:      39,
+					Text:           "raise the memo size",
+					Changes:        []storage.ParameterChange{change},
+				}, requireProposal(ctx, t, store))
+				active, exists, err := storage.GetActiveProposal(ctx, store)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.ActiveProposal{ProposalID: proposalID, SnapshotHeight: 30, VotingEnd: 39}, active)
+				requireBalance(ctx, t, store, proposer, 50)
+			},
+			ExpectedOutputs: &CreateProposalResult{SnapshotHeight: 30, VotingEnd: 39},
+		},
+		{
+			// A proposer that cannot bond the deposit cannot open a
+			// proposal, whatever it proposes.
+			Name:   "CreateUnderBonded",
+			Actor:  proposer,
+			Action: &CreateProposal{ProposalID: proposalID, Text: "raise the memo size"},
+			Rules:  rules,
+			State: func() state.Mutable {
+				store := setup(0, 0)
+				_, err := storage.SubBalance(context.Background(), store, proposer, 51)
+				require.NoError(t, err)
+				return store
+			}(),
+			ExpectedErr: ErrProposalDeposit,
+		},
+		{
+			Name:        "VoteNotFound",
+			Actor:       holder,
+			Action:      &Vote{ProposalID: ids.GenerateTestID(), Support: true},
+			Rules:       rules,
+			State:       setup(30, 0),
+			ExpectedErr: ErrProposalNotFound,
+		},
+		{
+			Name:        "VoteClosed",
+			Actor:       holder,
+			Action:      &Vote{ProposalID: proposalID, Support: true},
+			Rules:       rules,
+			State:       setup(29, 0),
+			ExpectedErr: ErrVotingClosed,
+		},
+		{
+			Name:   "Vote",
+			Actor:  holder,
+			Action: &Vote{ProposalID: proposalID, Support: false},
+			Rules:  rules,
+			State:  setup(30, 300),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				require.Equal(t, uint64(800), requireProposal(ctx, t, store).No)
+				vote, exists, err := storage.GetGovernanceVote(ctx, store, proposalID, holder)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.GovernanceVote{Weight: 800}, vote)
+			},
+			ExpectedOutputs: &VoteResult{Weight: 800, Yes: 300, No: 800},
+		},
+		{
+			// The balance sold after the proposal was created still votes.
+			Name:            "VoteWithSoldBalance",
+			Actor:           seller,
+			Action:          &Vote{ProposalID: proposalID, Support: true},
+			Rules:           rules,
+			State:           setup(30, 0),
+			ExpectedOutputs: &VoteResult{Weight: 1_000, Yes: 1_000},
+		},
+		{
+			// The balance bought after the proposal was created does not.
+			Name:        "VoteWithBoughtBalance",
+			Actor:       buyer,
+			Action:      &Vote{ProposalID: proposalID, Support: true},
+			Rules:       rules,
+			State:       setup(30, 0),
+			ExpectedErr: ErrNoVotingPower,
+		},
+		{
+			Name:   "VoteTwice",
+			Actor:  holder,
+			Action: &Vote{ProposalID: proposalID, Support: true},
+			Rules:  rules,
+			State: func() state.Mutable {
+				store := setup(30, 0)
+				require.NoError(t, storage.SetGovernanceVote(context.Background(), store, proposalID, holder, &storage.GovernanceVote{Weight: 800}))
+				return store
+			}(),
+			ExpectedErr: ErrAlreadyVoted,
+		},
+		{
+			Name:        "ExecuteWhileVotingOpen",
+			Actor:       holder,
+			Action:      &ExecuteProposal{ProposalID: proposalID, Proposer: proposer},
+			Rules:       rules,
+			State:       setup(30, 1_000),
+			ExpectedErr: ErrVotingOpen,
+		},
+		{
+			Name:   "ExecuteBelowQuorum",
+			Actor:  holder,
+			Action: &ExecuteProposal{ProposalID: proposalID, Proposer: proposer},
+			Rules:  rules,
+			State:  setup(29, 999),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				_, exists, err := storage.GetConfigUint64(ctx, store, MaxMemoSizeRule)
+				require.NoError(t, err)
+				require.False(t, exists)
+				// The deposit of a failed proposal is burned.
+				requireBalance(ctx, t, store, proposer, 50)
+			},
+			ExpectedOutputs: &ExecuteProposalResult{Yes: 999},
+		},
+		{
+			Name:   "Execute",
+			Actor:  holder,
+			Action: &ExecuteProposal{ProposalID: proposalID, Proposer: proposer},
+			Rules:  rules,
+			State:  setup(29, 1_000),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				proposal := requireProposal(ctx, t, store)
+				require.True(t, proposal.Executed)
+				require.True(t, proposal.Passed)
+				value, exists, err := storage.GetConfigUint64(ctx, store, MaxMemoSizeRule)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, uint64(512), value)
+				_, exists, err = storage.GetActiveProposal(ctx, store)
+				require.NoError(t, err)
+				require.False(t, exists)
+				requireBalance(ctx, t, store, proposer, 150)
+			},
+			ExpectedOutputs: &ExecuteProposalResult{Passed: true, Yes: 1_000},
+		},
+		{
+			// The refund opens the balance of a proposer that spent the rest.
+			Name:   "ExecuteRefundsEmptyBalance",
+			Actor:  holder,
+			Action: &ExecuteProposal{ProposalID: proposalID, Proposer: proposer},
+			Rules:  rules,
+			State: func() state.Mutable {
+				store := setup(29, 1_000)
+				require.NoError(t, store.Remove(context.Background(), storage.BalanceKey(proposer)))
+				return store
+			}(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireBalance(ctx, t, store, proposer, 100)
+			},
+			ExpectedOutputs: &ExecuteProposalResult{Passed: true, Yes: 1_000},
+		},
+		{
+			// Proposals set addresses of system roles too.
+			Name:   "ExecuteAddress",
+			Actor:  holder,
+			Action: &ExecuteProposal{ProposalID: proposalID, Proposer: proposer},
+			Rules:  rules,
+			State: func() state.Mutable {
+				ctx := context.Background()
+				store := setup(29, 1_000)
+				proposal, _, err := storage.GetGovernanceProposal(ctx, store, proposalID)
+				require.NoError(t, err)
+				proposal.Changes = append(proposal.Changes, storage.ParameterChange{Parameter: MaintenanceAddressRule, Address: proposer})
+				require.NoError(t, storage.SetGovernanceProposal(ctx, store, proposalID, proposal))
+				return store
+			}(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				require.Len(t, requireProposal(ctx, t, store).Changes, 2)
+				maintainer, err := configAddress(ctx, rules, store, MaintenanceAddressRule)
+				require.NoError(t, err)
+				require.Equal(t, proposer, maintainer)
+				memoSize, err := configUint64(ctx, rules, store, MaxMemoSizeRule)
+				require.NoError(t, err)
+				require.Equal(t, uint64(512), memoSize)
+			},
+			ExpectedOutputs: &ExecuteProposalResult{Passed: true, Yes: 1_000},
+		},
+		{
+			Name:        "ExecuteWrongProposer",
+			Actor:       holder,
+			Action:      &ExecuteProposal{ProposalID: proposalID, Proposer: holder},
+			Rules:       rules,
+			State:       setup(29, 1_000),
+			ExpectedErr: ErrProposalMismatch,
+		},
+		{
+			Name:   "ExecuteTwice",
+			Actor:  holder,
+			Action: &ExecuteProposal{ProposalID: proposalID, Proposer: proposer},
+			Rules:  rules,
+			State: func() state.Mutable {
+				ctx := context.Background()
+				store := setup(29, 1_000)
+				proposal, _, err := storage.GetGovernanceProposal(ctx, store, proposalID)
+				require.NoError(t, err)
+				proposal.Executed = true
+				require.NoError(t, storage.SetGovernanceProposal(ctx, store, proposalID, proposal))
+				return store
+			}(),
+			ExpectedErr: ErrProposalExecuted,
+		},
+		{
+			// The memo size set by governance replaces the one in the rules.
+			Name:   "GovernedMemoSize",
+			Actor:  holder,
+			Action: &Transfer{To: buyer, Value: 1, Memo: make([]byte, 512)},
+			Rules:  rules,
+			State: func() state.Mutable {
+				store := setup(0, 0)
+				require.NoError(t, storage.SetConfigUint64(context.Background(), store, MaxMemoSizeRule, 512))
+				return store
+			}(),
+			ExpectedOutputs: &TransferResult{SenderBalance: 799, ReceiverBalance: 1},
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -0,0 +1,222 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const HaltComputeUnits = 1
+
+var (
+	ErrNoSecurityCouncil  = errors.New("chain has no security council")
+	ErrChainAlreadyHalted = errors.New("chain is already halted")
+	ErrChainNotHalted     = errors.New("chain is not halted")
+	ErrHaltMismatch       = errors.New("chain is halted for another incident")
+
+	_ chain.Action = (*HaltChain)(nil)
+	_ chain.Action = (*ResumeChain)(nil)
+)
+
+// HaltChain adds the actor's approval to halt the chain for [Incident]. The
+// chain halts in the action that brings approvals to the security council
+// threshold. From then on, only transactions paid for by council members
+// are executed, until the council resumes it with [ResumeChain].
+type HaltChain struct {
+	// Incident is chosen by the council to identify the incident. Members
+	// approve a halt by naming the same incident.
+	Incident ids.ID `serialize:"true" json:"incident"`
+}
+
+func (*HaltChain) GetTypeID() uint8 {
+	return mconsts.HaltChainID
+}
+
+func (h *HaltChain) StateKeys(codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.SecurityCouncilKey()):        state.Read,
+		string(storage.HaltKey()):                   state.Read | state.Allocate | state.Write,
+		string(storage.HaltProposalKey(h.Incident)): state.All,
+	}
+}
+
+func (h *HaltChain) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, h, actor)
+	defer span.End()
+
+	council, index, err := securityCouncilMember(ctx, mu, actor)
+	if err != nil {
+		return nil, err
+	}
+	_, halted, err := storage.GetHalt(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	if halted {
+		return nil, ErrChainAlreadyHalted
+	}
+	approvals, err := storage.GetHaltProposal(ctx, mu, h.Incident)
+	if err != nil {
+		return nil, err
+	}
+	if approvals&(1<<index) != 0 {
+		return nil, ErrAlreadyApproved
+	}
+	approvals |= 1 << index
+	count := bits.OnesCount16(approvals)
+	if count < int(council.Threshold) {
+		if err := storage.SetHaltProposal(ctx, mu, h.Incident, approvals); err != nil {
+			return nil, err
+		}
+		return &HaltChainResult{Approvals: uint8(count)}, nil
+	}
+	if err := storage.DeleteHaltProposal(ctx, mu, h.Incident); err != nil {
+		return nil, err
+	}
+	if err := storage.SetHalt(ctx, mu, &storage.Halt{Incident: h.Incident, HaltedAt: timestamp}); err != nil {
+		return nil, err
+	}
+	return &HaltChainResult{Approvals: uint8(count), Halted: true}, nil
+}
+
+func (*HaltChain) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.HaltChainID, HaltComputeUnits)
+}
+
+func (*HaltChain) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*HaltChainResult)(nil)
+
+type HaltChainResult struct {
+	Approvals uint8 `serialize:"true" json:"approvals"`
+	Halted    bool  `serialize:"true" json:"halted"`
+}
+
+func (*HaltChainResult) GetTypeID() uint8 {
+	return mconsts.HaltChainID
+}
+
+// ResumeChain adds the actor's approval to resume the chain. The chain
+// resumes in the action that brings approvals to the security council
+// threshold.
+type ResumeChain struct {
+	// Incident must match the incident the chain was halted for, so an
+	// approval cannot carry over to a later halt.
+	Incident ids.ID `serialize:"true" json:"incident"`
+}
+
+func (*ResumeChain) GetTypeID() uint8 {
+	return mconsts.ResumeChainID
+}
+
+func (*ResumeChain) StateKeys(codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.SecurityCouncilKey()): state.Read,
+		string(storage.HaltKey()):            state.Read | state.Write,
+	}
+}
+
+func (r *ResumeChain) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, r, actor)
+	defer span.End()
+
+	council, index, err := securityCouncilMember(ctx, mu, actor)
+	if err != nil {
+		return nil, err
+	}
+	halt, halted, err := storage.GetHalt(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	if !halted {
+		return nil, ErrChainNotHalted
+	}
+	if halt.Incident != r.Incident {
+		return nil, ErrHaltMismatch
+	}
+	if halt.ResumeApprovals&(1<<index) != 0 {
+		return nil, ErrAlreadyApproved
+	}
+	halt.ResumeApprovals |= 1 << index
+	count := bits.OnesCount16(halt.ResumeApprovals)
+	if count < int(council.Threshold) {
+		if err := storage.SetHalt(ctx, mu, halt); err != nil {
+			return nil, err
+		}
+		return &ResumeChainResult{Approvals: uint8(count)}, nil
+	}
+	if err := storage.DeleteHalt(ctx, mu); err != nil {
+		return nil, err
+	}
+	return &ResumeChainResult{Approvals: uint8(count), Resumed: true}, nil
+}
+
+func (*ResumeChain) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.ResumeChainID, HaltComputeUnits)
+}
+
+func (*ResumeChain) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*ResumeChainResult)(nil)
+
+type ResumeChainResult struct {
+	Approvals uint8 `serialize:"true" json:"approvals"`
+	Resumed   bool  `serialize:"true" json:"resumed"`
+}
+
+func (*ResumeChainResult) GetTypeID() uint8 {
+	return mconsts.ResumeChainID
+}
+
+// securityCouncilMember returns the security council and the position of
+// [actor] in it.
+func securityCouncilMember(
+	ctx context.Context,
+	im state.Immutable,
+	actor codec.Address,
+) (*storage.Council, int, error) {
+	council, err := storage.GetSecurityCouncil(ctx, im)
+	if err != nil {
+		return nil, 0, err
+	}
+	if council == nil {
+		return nil, 0, ErrNoSecurityCouncil
+	}
+	index := council.Index(actor)
+	if index < 0 {
+		return nil, 0, ErrNotCouncilMember
+	}
+	return council, index, nil
+}
@@ -0,0 +1,203 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestHaltActions(t *testing.T) {
+	members := []codec.Address{
+		codectest.NewRandomAddress(),
+		codectest.NewRandomAddress(),
+		codectest.NewRandomAddress(),
+	}
+	outsider := codectest.NewRandomAddress()
+	incident := ids.GenerateTestID()
+
+	// security sets up a 2-of-3 security council, with [approvals] already
+	// given to halting for [incident], and the chain halted when [halt] is
+	// set.
+	security := func(approvals uint16, halt *storage.Halt) state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(ctx, store, outsider, 10))
+		require.NoError(t, storage.SetBalance(ctx, store, members[0], 10))
+		require.NoError(t, storage.SetSecurityCouncil(ctx, store, &storage.Council{
+			Members:   members,
+			Threshold: 2,
+		}))
+		if approvals != 0 {
+			require.NoError(t, storage.SetHaltProposal(ctx, store, incident, approvals))
+		}
+		if halt != nil {
+			require.NoError(t, storage.SetHalt(ctx, store, halt))
+		}
+		return store
+	}
+	rules := &governanceRules{Rules: genesis.NewDefaultRules(), votingPeriod: 10, quorum: 1, deposit: 5}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:        "NoSecurityCouncil",
+			Actor:       members[0],
+			Action:      &HaltChain{Incident: incident},
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: ErrNoSecurityCouncil,
+		},
+		{
+			Name:        "NotCouncilMember",
+			Actor:       outsider,
+			Action:      &HaltChain{Incident: incident},
+			State:       security(0, nil),
+			ExpectedErr: ErrNotCouncilMember,
+		},
+		{
+			Name:   "ApproveHalt",
+			Actor:  members[1],
+			Action: &HaltChain{Incident: incident},
+			State:  security(0, nil),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				approvals, err := storage.GetHaltProposal(ctx, store, incident)
+				require.NoError(t, err)
+				require.Equal(t, uint16(0b010), approvals)
+				_, halted, err := storage.GetHalt(ctx, store)
+				require.NoError(t, err)
+				require.False(t, halted)
+				require.NoError(t, storage.CheckNotHalted(ctx, store))
+			},
+			ExpectedOutputs: &HaltChainResult{Approvals: 1},
+		},
+		{
+			Name:        "AlreadyApproved",
+			Actor:       members[1],
+			Action:      &HaltChain{Incident: incident},
+			State:       security(0b010, nil),
+			ExpectedErr: ErrAlreadyApproved,
+		},
+		{
+			Name:      "Halt",
+			Actor:     members[0],
+			Action:    &HaltChain{Incident: incident},
+			State:     security(0b010, nil),
+			Timestamp: 10,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				require := require.New(t)
+				halt, halted, err := storage.GetHalt(ctx, store)
+				require.NoError(err)
+				require.True(halted)
+				require.Equal(&storage.Halt{Incident: incident, HaltedAt: 10}, halt)
+				approvals, err := storage.GetHaltProposal(ctx, store, incident)
+				require.NoError(err)
+				require.Zero(approvals)
+
+				require.ErrorIs(storage.CheckNotHalted(ctx, store), storage.ErrChainHalted)
+			},
+			ExpectedOutputs: &HaltChainResult{Approvals: 2, Halted: true},
+		},
+		{
+			Name:        "AlreadyHalted",
+			Actor:       members[0],
+			Action:      &HaltChain{Incident: ids.GenerateTestID()},
+			State:       security(0, &storage.Halt{Incident: incident}),
+			ExpectedErr: ErrChainAlreadyHalted,
+		},
+		{
+			// Council members are halted like everyone else outside of
+			// governance.
+			Name:        "HaltedTransfer",
+			Actor:       members[0],
+			Action:      &Transfer{To: outsider, Value: 1},
+			State:       security(0, &storage.Halt{Incident: incident}),
+			ExpectedErr: storage.ErrChainHalted,
+		},
+		{
+			// Governance runs for everyone while halted.
+			Name:   "HaltedCreateProposal",
+			Actor:  outsider,
+			Action: &CreateProposal{ProposalID: incident},
+			Rules:  rules,
+			State: func() state.Mutable {
+				store := security(0, &storage.Halt{Incident: incident})
+				require.NoError(t, store.Insert(context.Background(), chain.HeightKey(storage.HeightKey()), binary.BigEndian.AppendUint64(nil, 29)))
+				return store
+			}(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, outsider)
+				require.NoError(t, err)
+				require.Equal(t, uint64(5), balance)
+			},
+			ExpectedOutputs: &CreateProposalResult{SnapshotHeight: 30, VotingEnd: 39},
+		},
+		{
+			Name:        "ResumeNotHalted",
+			Actor:       members[0],
+			Action:      &ResumeChain{Incident: incident},
+			State:       security(0, nil),
+			ExpectedErr: ErrChainNotHalted,
+		},
+		{
+			Name:        "ResumeMismatch",
+			Actor:       members[0],
+			Action:      &ResumeChain{Incident: ids.GenerateTestID()},
+			State:       security(0, &storage.Halt{Incident: incident}),
+			ExpectedErr: ErrHaltMismatch,
+		},
+		{
+			Name:        "ResumeNotCouncilMember",
+			Actor:       outsider,
+			Action:      &ResumeChain{Incident: incident},
+			State:       security(0, &storage.Halt{Incident: incident}),
+			ExpectedErr: ErrNotCouncilMember,
+		},
+		{
+			Name:   "ApproveResume",
+			Actor:  members[2],
+			Action: &ResumeChain{Incident: incident},
+			State:  security(0, &storage.Halt{Incident: incident, HaltedAt: 10}),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				halt, halted, err := storage.GetHalt(ctx, store)
+				require.NoError(t, err)
+				require.True(t, halted)
+				require.Equal(t, uint16(0b100), halt.ResumeApprovals)
+			},
+			ExpectedOutputs: &ResumeChainResult{Approvals: 1},
+		},
+		{
+			Name:        "ResumeAlreadyApproved",
+			Actor:       members[2],
+			Action:      &ResumeChain{Incident: incident},
+			State:       security(0, &storage.Halt{Incident: incident, ResumeApprovals: 0b100}),
+			ExpectedErr: ErrAlreadyApproved,
+		},
+		{
+			Name:   "Resume",
+			Actor:  members[0],
+			Action: &ResumeChain{Incident: incident},
+			State:  security(0, &storage.Halt{Incident: incident, ResumeApprovals: 0b100}),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				_, halted, err := storage.GetHalt(ctx, store)
+				require.NoError(t, err)
+				require.False(t, halted)
+				require.NoError(t, storage.CheckNotHalted(ctx, store))
+			},
+			ExpectedOutputs: &ResumeChainResult{Approvals: 2, Resumed: true},
+		},
+	}
+
+	runActionTests(t, tests)
+}
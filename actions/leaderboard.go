@@ -0,0 +1,188 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const LeaderboardComputeUnits = 1
+
+var (
+	ErrLeaderboardExists      = errors.New("leaderboard already exists")
+	ErrLeaderboardNotFound    = errors.New("leaderboard not found")
+	ErrLeaderboardExpired     = errors.New("leaderboard has expired")
+	ErrInvalidLeaderboardSize = errors.New("leaderboard size must be between 1 and 16")
+
+	_ chain.Action = (*CreateLeaderboard)(nil)
+	_ chain.Action = (*SubmitScore)(nil)
+)
+
+// CreateLeaderboard opens a leaderboard keeping the best [Size] scores
+// submitted until [Expiry]. Its ID is storage.LeaderboardID(actor, Nonce).
+type CreateLeaderboard struct {
+	// Nonce distinguishes the leaderboards of one creator.
+	Nonce uint64 `serialize:"true" json:"nonce"`
+	// Size is how many entries the board keeps, at most
+	// [storage.MaxLeaderboardSize].
+	Size uint8 `serialize:"true" json:"size"`
+	// Expiry is the last timestamp, in milliseconds, at which scores can be
+	// submitted. The board stays readable after it.
+	Expiry int64 `serialize:"true" json:"expiry"`
+}
+
+func (*CreateLeaderboard) GetTypeID() uint8 {
+	return mconsts.CreateLeaderboardID
+}
+
+func (c *CreateLeaderboard) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.LeaderboardKey(storage.LeaderboardID(actor, c.Nonce))): state.All,
+		string(storage.HaltKey()): state.Read,
+	}
+}
+
+func (c *CreateLeaderboard) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, c, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if c.Size == 0 || c.Size > storage.MaxLeaderboardSize {
+		return nil, ErrInvalidLeaderboardSize
+	}
+	if c.Expiry < timestamp {
+		return nil, ErrDeadlineInThePast
+	}
+	boardID := storage.LeaderboardID(actor, c.Nonce)
+	_, exists, err := storage.GetLeaderboard(ctx, mu, boardID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrLeaderboardExists
+	}
+	if err := storage.SetLeaderboard(ctx, mu, boardID, &storage.Leaderboard{
+		Creator: actor,
+		Size:    c.Size,
+		Expiry:  c.Expiry,
+	}); err != nil {
+		return nil, err
+	}
+	return &CreateLeaderboardResult{BoardID: boardID}, nil
+}
+
+func (*CreateLeaderboard) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.CreateLeaderboardID, LeaderboardComputeUnits)
+}
+
+func (*CreateLeaderboard) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*CreateLeaderboardResult)(nil)
+
+type CreateLeaderboardResult struct {
+	BoardID ids.ID `serialize:"true" json:"board_id"`
+}
+
+func (*CreateLeaderboardResult) GetTypeID() uint8 {
+	return mconsts.CreateLeaderboardID
+}
+
+// SubmitScore submits [Score] for the actor to [BoardID]. The board keeps
+// the actor's best score if it ranks among its entries, dropping the
+// lowest entry once full. Equal scores rank by who submitted first.
+type SubmitScore struct {
+	BoardID ids.ID `serialize:"true" json:"board_id"`
+	Score   uint64 `serialize:"true" json:"score"`
+}
+
+func (*SubmitScore) GetTypeID() uint8 {
+	return mconsts.SubmitScoreID
+}
+
+func (s *SubmitScore) StateKeys(codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.LeaderboardKey(s.BoardID)): state.Read | state.Write,
+		string(storage.HaltKey()):                 state.Read,
+	}
+}
+
+func (s *SubmitScore) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, s, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	board, exists, err := storage.GetLeaderboard(ctx, mu, s.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrLeaderboardNotFound
+	}
+	if board.Expiry < timestamp {
+		return nil, ErrLeaderboardExpired
+	}
+	rank, changed := board.Submit(actor, s.Score, timestamp)
+	if changed {
+		if err := storage.SetLeaderboard(ctx, mu, s.BoardID, board); err != nil {
+			return nil, err
+		}
+	}
+	return &SubmitScoreResult{Rank: uint8(rank), Recorded: changed}, nil
+}
+
+func (*SubmitScore) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.SubmitScoreID, LeaderboardComputeUnits)
+}
+
+func (*SubmitScore) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*SubmitScoreResult)(nil)
+
+type SubmitScoreResult struct {
+	// Rank is the position of the actor's entry from 1, or 0 if the actor
+	// is not on the board.
+	Rank uint8 `serialize:"true" json:"rank"`
+	// Recorded is whether this score entered the board.
+	Recorded bool `serialize:"true" json:"recorded"`
+}
+
+func (*SubmitScoreResult) GetTypeID() uint8 {
+	return mconsts.SubmitScoreID
+}
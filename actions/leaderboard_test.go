@@ -0,0 +1,180 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestLeaderboardActions(t *testing.T) {
+	creator := codectest.NewRandomAddress()
+	alice := codectest.NewRandomAddress()
+	bob := codectest.NewRandomAddress()
+	carol := codectest.NewRandomAddress()
+	boardID := storage.LeaderboardID(creator, 1)
+
+	// board is a state holding a board of size 2 with [entries].
+	board := func(entries ...storage.LeaderboardEntry) state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetLeaderboard(context.Background(), store, boardID, &storage.Leaderboard{
+			Creator: creator,
+			Size:    2,
+			Expiry:  100,
+			Entries: entries,
+		}))
+		return store
+	}
+	requireEntries := func(ctx context.Context, t *testing.T, store state.Mutable, expected ...storage.LeaderboardEntry) {
+		l, exists, err := storage.GetLeaderboard(ctx, store, boardID)
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, expected, l.Entries)
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:   "Create",
+			Actor:  creator,
+			Action: &CreateLeaderboard{Nonce: 1, Size: 2, Expiry: 100},
+			State:  chaintest.NewInMemoryStore(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				l, exists, err := storage.GetLeaderboard(ctx, store, boardID)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.Leaderboard{Creator: creator, Size: 2, Expiry: 100, Entries: []storage.LeaderboardEntry{}}, l)
+			},
+			ExpectedOutputs: &CreateLeaderboardResult{BoardID: boardID},
+		},
+		{
+			Name:        "CreateTooLarge",
+			Actor:       creator,
+			Action:      &CreateLeaderboard{Nonce: 1, Size: storage.MaxLeaderboardSize + 1, Expiry: 100},
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: ErrInvalidLeaderboardSize,
+		},
+		{
+			Name:        "CreateExpired",
+			Actor:       creator,
+			Action:      &CreateLeaderboard{Nonce: 1, Size: 2, Expiry: 100},
+			State:       chaintest.NewInMemoryStore(),
+			Timestamp:   101,
+			ExpectedErr: ErrDeadlineInThePast,
+		},
+		{
+			Name:        "CreateExisting",
+			Actor:       creator,
+			Action:      &CreateLeaderboard{Nonce: 1, Size: 2, Expiry: 100},
+			State:       board(),
+			ExpectedErr: ErrLeaderboardExists,
+		},
+		{
+			Name:      "SubmitFirst",
+			Actor:     alice,
+			Action:    &SubmitScore{BoardID: boardID, Score: 5},
+			State:     board(),
+			Timestamp: 10,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireEntries(ctx, t, store, storage.LeaderboardEntry{Player: alice, Score: 5, Timestamp: 10})
+			},
+			ExpectedOutputs: &SubmitScoreResult{Rank: 1, Recorded: true},
+		},
+		{
+			Name:      "SubmitTieRanksLater",
+			Actor:     bob,
+			Action:    &SubmitScore{BoardID: boardID, Score: 5},
+			State:     board(storage.LeaderboardEntry{Player: alice, Score: 5, Timestamp: 10}),
+			Timestamp: 20,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireEntries(ctx, t, store,
+					storage.LeaderboardEntry{Player: alice, Score: 5, Timestamp: 10},
+					storage.LeaderboardEntry{Player: bob, Score: 5, Timestamp: 20},
+				)
+			},
+			ExpectedOutputs: &SubmitScoreResult{Rank: 2, Recorded: true},
+		},
+		{
+			Name:   "SubmitDropsLowest",
+			Actor:  carol,
+			Action: &SubmitScore{BoardID: boardID, Score: 7},
+			State: board(
+				storage.LeaderboardEntry{Player: alice, Score: 9, Timestamp: 10},
+				storage.LeaderboardEntry{Player: bob, Score: 5, Timestamp: 20},
+			),
+			Timestamp: 30,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireEntries(ctx, t, store,
+					storage.LeaderboardEntry{Player: alice, Score: 9, Timestamp: 10},
+					storage.LeaderboardEntry{Player: carol, Score: 7, Timestamp: 30},
+				)
+			},
+			ExpectedOutputs: &SubmitScoreResult{Rank: 2, Recorded: true},
+		},
+		{
+			Name:   "SubmitBelowFullBoard",
+			Actor:  carol,
+			Action: &SubmitScore{BoardID: boardID, Score: 5},
+			State: board(
+				storage.LeaderboardEntry{Player: alice, Score: 9, Timestamp: 10},
+				storage.LeaderboardEntry{Player: bob, Score: 5, Timestamp: 20},
+			),
+			Timestamp:       30,
+			ExpectedOutputs: &SubmitScoreResult{},
+		},
+		{
+			Name:   "SubmitImprovesOwnEntry",
+			Actor:  bob,
+			Action: &SubmitScore{BoardID: boardID, Score: 12},
+			State: board(
+				storage.LeaderboardEntry{Player: alice, Score: 9, Timestamp: 10},
+				storage.LeaderboardEntry{Player: bob, Score: 5, Timestamp: 20},
+			),
+			Timestamp: 30,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireEntries(ctx, t, store,
+					storage.LeaderboardEntry{Player: bob, Score: 12, Timestamp: 30},
+					storage.LeaderboardEntry{Player: alice, Score: 9, Timestamp: 10},
+				)
+			},
+			ExpectedOutputs: &SubmitScoreResult{Rank: 1, Recorded: true},
+		},
+		{
+			Name:   "SubmitNotImproving",
+			Actor:  alice,
+			Action: &SubmitScore{BoardID: boardID, Score: 9},
+			State: board(
+				storage.LeaderboardEntry{Player: alice, Score: 9, Timestamp: 10},
+			),
+			Timestamp: 30,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireEntries(ctx, t, store, storage.LeaderboardEntry{Player: alice, Score: 9, Timestamp: 10})
+			},
+			ExpectedOutputs: &SubmitScoreResult{Rank: 1},
+		},
+		{
+			Name:        "SubmitExpired",
+			Actor:       alice,
+			Action:      &SubmitScore{BoardID: boardID, Score: 5},
+			State:       board(),
+			Timestamp:   101,
+			ExpectedErr: ErrLeaderboardExpired,
+		},
+		{
+			Name:        "SubmitMissing",
+			Actor:       alice,
+			Action:      &SubmitScore{BoardID: storage.LeaderboardID(creator, 2), Score: 5},
+			State:       board(),
+			ExpectedErr: ErrLeaderboardNotFound,
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -0,0 +1,422 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+// LockerComputeUnits is charged per locker action, and per leg and item it
+// moves.
+const LockerComputeUnits = 1
+
+var (
+	ErrLockerNotFound  = errors.New("locker not found")
+	ErrLockerFull      = errors.New("locker holds too many assets")
+	ErrLockerMismatch  = errors.New("locker content does not match")
+	ErrLockerInLocker  = errors.New("locker cannot hold itself")
+	ErrInvalidDeposit  = errors.New("deposit must move distinct assets of non-zero amounts")
+	ErrNotLockerHolder = errors.New("actor does not hold the locker")
+	ErrAssetIsLocker   = errors.New("asset is a locker and must be unbundled")
+
+	_ chain.Action = (*CreateLocker)(nil)
+	_ chain.Action = (*DepositToLocker)(nil)
+	_ chain.Action = (*UnbundleLocker)(nil)
+)
+
+// CreateLocker creates an empty locker held by the actor. The locker is an
+// asset under storage.LockerID(actor, Nonce), transferred like any other,
+// and its content is held at storage.LockerAddress of that ID.
+type CreateLocker struct {
+	// Nonce distinguishes the lockers of one creator.
+	Nonce uint64 `serialize:"true" json:"nonce"`
+}
+
+func (*CreateLocker) GetTypeID() uint8 {
+	return mconsts.CreateLockerID
+}
+
+func (c *CreateLocker) StateKeys(actor codec.Address) state.Keys {
+	locker := storage.LockerID(actor, c.Nonce)
+	return state.Keys{
+		string(storage.AssetKey(locker)):                       state.Read | state.Allocate | state.Write,
+		string(storage.OwnedAssetKey(actor, locker)):           state.Allocate | state.Write,
+		string(storage.TombstoneKey(storage.AssetKey(locker))): state.Read,
+		string(storage.LockerKey(locker)):                      state.Allocate | state.Write,
+		string(storage.HaltKey()):                              state.Read,
+	}
+}
+
+func (c *CreateLocker) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, c, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	locker := storage.LockerID(actor, c.Nonce)
+	if err := storage.CreateAsset(ctx, mu, locker, actor); err != nil {
+		return nil, err
+	}
+	if err := storage.SetLocker(ctx, mu, locker, &storage.Locker{}); err != nil {
+		return nil, err
+	}
+	return &CreateLockerResult{Locker: locker, Custody: storage.LockerAddress(locker)}, nil
+}
+
+func (*CreateLocker) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.CreateLockerID, LockerComputeUnits)
+}
+
+func (*CreateLocker) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (c *CreateLocker) EmitEvents(actor codec.Address, _ codec.Typed, e Emitter) {
+	e.Emit(&OwnershipEvent{Asset: storage.LockerID(actor, c.Nonce), To: actor})
+}
+
+var _ codec.Typed = (*CreateLockerResult)(nil)
+
+type CreateLockerResult struct {
+	Locker  ids.ID        `serialize:"true" json:"locker"`
+	Custody codec.Address `serialize:"true" json:"custody"`
+}
+
+func (*CreateLockerResult) GetTypeID() uint8 {
+	return mconsts.CreateLockerID
+}
+
+// DepositToLocker moves [Legs] and [Items] from the actor into [Locker],
+// which the actor must hold. Legs of assets the locker already has are
+// added to them.
+type DepositToLocker struct {
+	Locker ids.ID            `serialize:"true" json:"locker"`
+	Legs   []storage.SwapLeg `serialize:"true" json:"legs"`
+	// Items are non-fungible assets the actor owns. Frozen and soulbound
+	// assets cannot be deposited.
+	Items []ids.ID `serialize:"true" json:"items"`
+}
+
+func (*DepositToLocker) GetTypeID() uint8 {
+	return mconsts.DepositToLockerID
+}
+
+func (d *DepositToLocker) StateKeys(actor codec.Address) state.Keys {
+	keys := state.Keys{
+		string(storage.LockerKey(d.Locker)): state.Read | state.Write,
+		string(storage.AssetKey(d.Locker)):  state.Read,
+	}
+	custody := storage.LockerAddress(d.Locker)
+	for _, leg := range d.Legs {
+		addLegKeys(keys, leg, actor, custody)
+	}
+	addLegHookKeys(keys, actor, d.Legs)
+	for _, item := range d.Items {
+		addItemKeys(keys, item, actor, custody)
+		addTransferHookKeys(keys, actor, item)
+	}
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (d *DepositToLocker) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, d, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if !validDeposit(d.Legs, d.Items) {
+		return nil, ErrInvalidDeposit
+	}
+	if slices.Contains(d.Items, d.Locker) {
+		return nil, ErrLockerInLocker
+	}
+	l, err := heldLocker(ctx, mu, d.Locker, actor)
+	if err != nil {
+		return nil, err
+	}
+	for _, leg := range d.Legs {
+		i := slices.IndexFunc(l.Legs, func(held storage.SwapLeg) bool { return held.Asset == leg.Asset })
+		if i < 0 {
+			l.Legs = append(l.Legs, leg)
+			continue
+		}
+		if l.Legs[i].Amount, err = smath.Add(l.Legs[i].Amount, leg.Amount); err != nil {
+			return nil, err
+		}
+	}
+	l.Items = append(l.Items, d.Items...)
+	if len(l.Legs) > storage.MaxLockerLegs || len(l.Items) > storage.MaxLockerItems {
+		return nil, ErrLockerFull
+	}
+
+	custody := storage.LockerAddress(d.Locker)
+	if err := runLegHooks(ctx, r, mu, actor, d.Legs); err != nil {
+		return nil, err
+	}
+	if err := moveLegs(ctx, mu, actor, custody, d.Legs); err != nil {
+		return nil, err
+	}
+	for _, item := range d.Items {
+		owner, err := storage.GetAssetOwner(ctx, mu, item)
+		if err != nil {
+			return nil, err
+		}
+		if owner != actor {
+			return nil, ErrAssetNotOwned
+		}
+		control, err := storage.GetAssetControl(ctx, mu, item)
+		if err != nil {
+			return nil, err
+		}
+		if control.Frozen {
+			return nil, ErrAssetFrozen
+		}
+		if control.Soulbound {
+			return nil, ErrAssetSoulbound
+		}
+		if control.Expired(timestamp) {
+			return nil, ErrAssetExpired
+		}
+		if _, err := runTransferHook(ctx, r, mu, actor, item); err != nil {
+			return nil, err
+		}
+		if err := storage.ChangeAssetOwner(ctx, mu, item, custody); err != nil {
+			return nil, err
+		}
+	}
+	if err := storage.SetLocker(ctx, mu, d.Locker, l); err != nil {
+		return nil, err
+	}
+	return &DepositToLockerResult{Legs: uint8(len(l.Legs)), Items: uint8(len(l.Items))}, nil
+}
+
+func (d *DepositToLocker) ComputeUnits(r chain.Rules) uint64 {
+	return uint64(len(d.Legs)+len(d.Items))*LockerComputeUnits +
+		baseComputeUnits(r, mconsts.DepositToLockerID, LockerComputeUnits)
+}
+
+func (*DepositToLocker) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (d *DepositToLocker) EmitEvents(actor codec.Address, _ codec.Typed, e Emitter) {
+	custody := storage.LockerAddress(d.Locker)
+	for _, leg := range d.Legs {
+		e.Emit(&TransferEvent{From: actor, To: custody, Asset: leg.Asset, Amount: leg.Amount})
+	}
+	for _, item := range d.Items {
+		e.Emit(&OwnershipEvent{Asset: item, From: actor, To: custody})
+	}
+}
+
+var _ codec.Typed = (*DepositToLockerResult)(nil)
+
+type DepositToLockerResult struct {
+	// Legs and Items count the content of the locker after the deposit.
+	Legs  uint8 `serialize:"true" json:"legs"`
+	Items uint8 `serialize:"true" json:"items"`
+}
+
+func (*DepositToLockerResult) GetTypeID() uint8 {
+	return mconsts.DepositToLockerID
+}
+
+// UnbundleLocker releases the content of [Locker] to the actor, who must
+// hold it, and burns the locker.
+type UnbundleLocker struct {
+	Locker ids.ID `serialize:"true" json:"locker"`
+	// Legs and Items must match the content of the locker, in order, so the
+	// records it moves can be declared in [StateKeys].
+	Legs  []storage.SwapLeg `serialize:"true" json:"legs"`
+	Items []ids.ID          `serialize:"true" json:"items"`
+}
+
+func (*UnbundleLocker) GetTypeID() uint8 {
+	return mconsts.UnbundleLockerID
+}
+
+func (u *UnbundleLocker) StateKeys(actor codec.Address) state.Keys {
+	assetKey := storage.AssetKey(u.Locker)
+	keys := storage.DeletionStateKeys(assetKey)
+	keys.Add(string(assetKey), state.Read|state.Write)
+	keys.Add(string(storage.OwnedAssetKey(actor, u.Locker)), state.Write)
+	keys.Add(string(storage.LockerKey(u.Locker)), state.Read|state.Write)
+	custody := storage.LockerAddress(u.Locker)
+	for _, leg := range u.Legs {
+		addLegKeys(keys, leg, custody, actor)
+	}
+	for _, item := range u.Items {
+		addItemKeys(keys, item, custody, actor)
+	}
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (u *UnbundleLocker) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, u, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	l, err := heldLocker(ctx, mu, u.Locker, actor)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Equal(l.Legs, u.Legs) || !slices.Equal(l.Items, u.Items) {
+		return nil, ErrLockerMismatch
+	}
+	// Like refunds, releases skip transfer hooks and freezes, so a lock set
+	// while the assets were in the locker cannot strand them.
+	custody := storage.LockerAddress(u.Locker)
+	if err := moveLegs(ctx, mu, custody, actor, l.Legs); err != nil {
+		return nil, err
+	}
+	for _, item := range l.Items {
+		if err := storage.ChangeAssetOwner(ctx, mu, item, actor); err != nil {
+			return nil, err
+		}
+	}
+	if err := storage.DeleteLocker(ctx, mu, u.Locker); err != nil {
+		return nil, err
+	}
+	//statekeys:ignore OwnedAssetKey the holder was checked to be the actor
+	if err := storage.DeleteAsset(ctx, mu, u.Locker); err != nil {
+		return nil, err
+	}
+	return &UnbundleLockerResult{Custody: custody}, nil
+}
+
+func (u *UnbundleLocker) ComputeUnits(r chain.Rules) uint64 {
+	return uint64(len(u.Legs)+len(u.Items))*LockerComputeUnits +
+		baseComputeUnits(r, mconsts.UnbundleLockerID, LockerComputeUnits)
+}
+
+func (*UnbundleLocker) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (u *UnbundleLocker) EmitEvents(actor codec.Address, _ codec.Typed, e Emitter) {
+	custody := storage.LockerAddress(u.Locker)
+	for _, leg := range u.Legs {
+		e.Emit(&TransferEvent{From: custody, To: actor, Asset: leg.Asset, Amount: leg.Amount})
+	}
+	for _, item := range u.Items {
+		e.Emit(&OwnershipEvent{Asset: item, From: custody, To: actor})
+	}
+	e.Emit(&OwnershipEvent{Asset: u.Locker, From: actor})
+}
+
+var _ codec.Typed = (*UnbundleLockerResult)(nil)
+
+type UnbundleLockerResult struct {
+	// Custody is the address the content was released from.
+	Custody codec.Address `serialize:"true" json:"custody"`
+}
+
+func (*UnbundleLockerResult) GetTypeID() uint8 {
+	return mconsts.UnbundleLockerID
+}
+
+// validDeposit reports whether [legs] and [items] move something, with
+// non-zero amounts of distinct assets and distinct items.
+func validDeposit(legs []storage.SwapLeg, items []ids.ID) bool {
+	if len(legs) == 0 && len(items) == 0 {
+		return false
+	}
+	if len(legs) > storage.MaxLockerLegs || len(items) > storage.MaxLockerItems {
+		return false
+	}
+	for i, leg := range legs {
+		if leg.Amount == 0 {
+			return false
+		}
+		for _, prev := range legs[:i] {
+			if prev.Asset == leg.Asset {
+				return false
+			}
+		}
+	}
+	for i, item := range items {
+		if slices.Contains(items[:i], item) {
+			return false
+		}
+	}
+	return true
+}
+
+// addItemKeys declares the keys needed to hand the non-fungible [item] from
+// [from] to [to].
+func addItemKeys(keys state.Keys, item ids.ID, from, to codec.Address) {
+	keys.Add(string(storage.AssetKey(item)), state.Read|state.Write)
+	keys.Add(string(storage.OwnedAssetKey(from, item)), state.Write)
+	keys.Add(string(storage.OwnedAssetKey(to, item)), state.Allocate|state.Write)
+}
+
+// heldLocker returns the content of [lockerID], checking that [holder]
+// holds it.
+func heldLocker(
+	ctx context.Context,
+	im state.Immutable,
+	lockerID ids.ID,
+	holder codec.Address,
+) (*storage.Locker, error) {
+	l, exists, err := storage.GetLocker(ctx, im, lockerID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrLockerNotFound
+	}
+	owner, err := storage.GetAssetOwner(ctx, im, lockerID)
+	if err != nil {
+		return nil, err
+	}
+	if owner != holder {
+		return nil, ErrNotLockerHolder
+	}
+	return l, nil
+}
@@ -0,0 +1,231 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestLockerActions(t *testing.T) {
+	creator := codectest.NewRandomAddress()
+	buyer := codectest.NewRandomAddress()
+	locker := storage.LockerID(creator, 1)
+	custody := storage.LockerAddress(locker)
+	token := ids.GenerateTestID()
+	item := ids.GenerateTestID()
+	badge := ids.GenerateTestID()
+
+	legs := []storage.SwapLeg{{Asset: storage.NativeAsset, Amount: 10}, {Asset: token, Amount: 3}}
+	items := []ids.ID{item}
+
+	// funded has an empty locker held by [holder], and gives [creator] the
+	// native token, [token] and [item].
+	funded := func(holder codec.Address) state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.CreateAsset(ctx, store, locker, holder))
+		require.NoError(t, storage.SetLocker(ctx, store, locker, &storage.Locker{}))
+		require.NoError(t, storage.SetBalance(ctx, store, creator, 20))
+		require.NoError(t, storage.SetAssetBalance(ctx, store, creator, token, 5))
+		require.NoError(t, storage.CreateAsset(ctx, store, item, creator))
+		return store
+	}
+	// filled has [legs] and [items] in a locker held by [holder].
+	filled := func(holder codec.Address) state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, store.Insert(ctx, chain.HeightKey(storage.HeightKey()), binary.BigEndian.AppendUint64(nil, 9)))
+		require.NoError(t, storage.CreateAsset(ctx, store, locker, holder))
+		require.NoError(t, storage.SetLocker(ctx, store, locker, &storage.Locker{Legs: legs, Items: items}))
+		require.NoError(t, storage.SetBalance(ctx, store, custody, 10))
+		require.NoError(t, storage.SetAssetBalance(ctx, store, custody, token, 3))
+		require.NoError(t, storage.CreateAsset(ctx, store, item, custody))
+		return store
+	}
+	requireLocker := func(ctx context.Context, t *testing.T, store state.Mutable, expected *storage.Locker) {
+		l, exists, err := storage.GetLocker(ctx, store, locker)
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, expected, l)
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:   "Create",
+			Actor:  creator,
+			Action: &CreateLocker{Nonce: 1},
+			State:  chaintest.NewInMemoryStore(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				owner, err := storage.GetAssetOwner(ctx, store, locker)
+				require.NoError(t, err)
+				require.Equal(t, creator, owner)
+				requireLocker(ctx, t, store, &storage.Locker{Legs: []storage.SwapLeg{}, Items: []ids.ID{}})
+			},
+			ExpectedOutputs: &CreateLockerResult{Locker: locker, Custody: custody},
+		},
+		{
+			Name:        "CreateExisting",
+			Actor:       creator,
+			Action:      &CreateLocker{Nonce: 1},
+			State:       funded(creator),
+			ExpectedErr: storage.ErrAssetExists,
+		},
+		{
+			Name:   "Deposit",
+			Actor:  creator,
+			Action: &DepositToLocker{Locker: locker, Legs: legs, Items: items},
+			State:  funded(creator),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireLocker(ctx, t, store, &storage.Locker{Legs: legs, Items: items})
+				balance, err := storage.GetBalance(ctx, store, custody)
+				require.NoError(t, err)
+				require.Equal(t, uint64(10), balance)
+				balance, err = storage.GetAssetBalance(ctx, store, custody, token)
+				require.NoError(t, err)
+				require.Equal(t, uint64(3), balance)
+				owner, err := storage.GetAssetOwner(ctx, store, item)
+				require.NoError(t, err)
+				require.Equal(t, custody, owner)
+			},
+			ExpectedOutputs: &DepositToLockerResult{Legs: 2, Items: 1},
+		},
+		{
+			Name:   "DepositAddsToLegs",
+			Actor:  creator,
+			Action: &DepositToLocker{Locker: locker, Legs: []storage.SwapLeg{{Asset: storage.NativeAsset, Amount: 4}}},
+			State: func() state.Mutable {
+				store := filled(creator)
+				require.NoError(t, storage.SetBalance(context.Background(), store, creator, 4))
+				return store
+			}(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireLocker(ctx, t, store, &storage.Locker{
+					Legs:  []storage.SwapLeg{{Asset: storage.NativeAsset, Amount: 14}, {Asset: token, Amount: 3}},
+					Items: items,
+				})
+			},
+			ExpectedOutputs: &DepositToLockerResult{Legs: 2, Items: 1},
+		},
+		{
+			Name:        "DepositNotHolder",
+			Actor:       creator,
+			Action:      &DepositToLocker{Locker: locker, Legs: legs},
+			State:       funded(buyer),
+			ExpectedErr: ErrNotLockerHolder,
+		},
+		{
+			Name:        "DepositItself",
+			Actor:       creator,
+			Action:      &DepositToLocker{Locker: locker, Items: []ids.ID{locker}},
+			State:       funded(creator),
+			ExpectedErr: ErrLockerInLocker,
+		},
+		{
+			Name:        "DepositNotOwnedItem",
+			Actor:       creator,
+			Action:      &DepositToLocker{Locker: locker, Items: []ids.ID{ids.GenerateTestID()}},
+			State:       funded(creator),
+			ExpectedErr: ErrAssetNotOwned,
+		},
+		{
+			Name:   "DepositSoulbound",
+			Actor:  creator,
+			Action: &DepositToLocker{Locker: locker, Items: []ids.ID{badge}},
+			State: func() state.Mutable {
+				store := funded(creator)
+				require.NoError(t, storage.CreateSoulboundAsset(context.Background(), store, badge, creator))
+				return store
+			}(),
+			ExpectedErr: ErrAssetSoulbound,
+		},
+		{
+			Name:        "DepositNothing",
+			Actor:       creator,
+			Action:      &DepositToLocker{Locker: locker},
+			State:       funded(creator),
+			ExpectedErr: ErrInvalidDeposit,
+		},
+		{
+			Name:   "DepositFull",
+			Actor:  creator,
+			Action: &DepositToLocker{Locker: locker, Legs: []storage.SwapLeg{{Asset: ids.GenerateTestID(), Amount: 1}}},
+			State: func() state.Mutable {
+				store := funded(creator)
+				full := make([]storage.SwapLeg, storage.MaxLockerLegs)
+				for i := range full {
+					full[i] = storage.SwapLeg{Asset: ids.GenerateTestID(), Amount: 1}
+				}
+				require.NoError(t, storage.SetLocker(context.Background(), store, locker, &storage.Locker{Legs: full}))
+				return store
+			}(),
+			ExpectedErr: ErrLockerFull,
+		},
+		{
+			Name:        "DepositMissingLocker",
+			Actor:       creator,
+			Action:      &DepositToLocker{Locker: ids.GenerateTestID(), Legs: legs},
+			State:       funded(creator),
+			ExpectedErr: ErrLockerNotFound,
+		},
+		{
+			Name:   "UnbundleByNewHolder",
+			Actor:  buyer,
+			Action: &UnbundleLocker{Locker: locker, Legs: legs, Items: items},
+			State:  filled(buyer),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, buyer)
+				require.NoError(t, err)
+				require.Equal(t, uint64(10), balance)
+				balance, err = storage.GetAssetBalance(ctx, store, buyer, token)
+				require.NoError(t, err)
+				require.Equal(t, uint64(3), balance)
+				owner, err := storage.GetAssetOwner(ctx, store, item)
+				require.NoError(t, err)
+				require.Equal(t, buyer, owner)
+				_, exists, err := storage.GetLocker(ctx, store, locker)
+				require.NoError(t, err)
+				require.False(t, exists)
+				_, err = store.GetValue(ctx, storage.AssetKey(locker))
+				require.ErrorIs(t, err, database.ErrNotFound)
+			},
+			ExpectedOutputs: &UnbundleLockerResult{Custody: custody},
+		},
+		{
+			Name:        "UnbundleNotHolder",
+			Actor:       creator,
+			Action:      &UnbundleLocker{Locker: locker, Legs: legs, Items: items},
+			State:       filled(buyer),
+			ExpectedErr: ErrNotLockerHolder,
+		},
+		{
+			Name:        "UnbundleMismatch",
+			Actor:       buyer,
+			Action:      &UnbundleLocker{Locker: locker, Legs: legs[:1], Items: items},
+			State:       filled(buyer),
+			ExpectedErr: ErrLockerMismatch,
+		},
+		{
+			Name:        "BurnLocker",
+			Actor:       buyer,
+			Action:      &BurnAsset{Asset: locker},
+			State:       filled(buyer),
+			ExpectedErr: ErrAssetIsLocker,
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -0,0 +1,232 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const (
+	MaintenanceComputeUnits = 1
+
+	// MaxEpochStakers bounds the stakers a [ProcessEpoch] settles.
+	MaxEpochStakers = 64
+)
+
+// MaintenanceAddressRule is the key of the address allowed to send
+// maintenance actions, read from the [Config] registry and else with
+// chain.Rules.FetchCustom. Maintenance actions are rejected when neither
+// sets one.
+const MaintenanceAddressRule = "maintenanceAddress"
+
+var (
+	ErrNotMaintainer    = errors.New("actor is not the maintenance address")
+	ErrTooManyStakers   = errors.New("too many stakers")
+	ErrDuplicateStaker  = errors.New("duplicate staker")
+	ErrCompactionHeight = errors.New("compaction height must increase and be accepted")
+
+	_ chain.Action = (*ProcessEpoch)(nil)
+	_ chain.Action = (*MarkCompaction)(nil)
+)
+
+// ProcessEpoch settles the rewards of [Stakers] up to the current epoch, as
+// if each of them had touched its stake. Stakes accrue lazily, so this is a
+// fallback for stakes left untouched across a change of the reward rate.
+type ProcessEpoch struct {
+	Stakers []codec.Address `serialize:"true" json:"stakers"`
+}
+
+func (*ProcessEpoch) GetTypeID() uint8 {
+	return mconsts.ProcessEpochID
+}
+
+func (p *ProcessEpoch) StateKeys(codec.Address) state.Keys {
+	keys := state.Keys{
+		string(chain.HeightKey(storage.HeightKey())):      state.Read,
+		string(storage.ConfigKey(StakingRewardRateRule)):  state.Read,
+		string(storage.ConfigKey(MaintenanceAddressRule)): state.Read,
+	}
+	for _, staker := range p.Stakers {
+		keys.Add(string(storage.StakeKey(staker)), state.Read|state.Write)
+	}
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (p *ProcessEpoch) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, p, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if err := checkMaintainer(ctx, r, mu, actor); err != nil {
+		return nil, err
+	}
+	if len(p.Stakers) > MaxEpochStakers {
+		return nil, ErrTooManyStakers
+	}
+	parent, err := storage.GetHeight(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	result := &ProcessEpochResult{Epoch: StakingEpoch(r, parent+1)}
+	seen := make(map[codec.Address]bool, len(p.Stakers))
+	for _, staker := range p.Stakers {
+		if seen[staker] {
+			return nil, ErrDuplicateStaker
+		}
+		seen[staker] = true
+		stake, exists, err := accrue(ctx, r, mu, staker)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		if err := storage.SetStake(ctx, mu, staker, stake); err != nil {
+			return nil, err
+		}
+		result.Settled++
+	}
+	return result, nil
+}
+
+func (*ProcessEpoch) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.ProcessEpochID, MaintenanceComputeUnits)
+}
+
+func (*ProcessEpoch) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*ProcessEpochResult)(nil)
+
+type ProcessEpochResult struct {
+	Epoch uint64 `serialize:"true" json:"epoch"`
+	// Settled counts the stakers that had a stake.
+	Settled uint8 `serialize:"true" json:"settled"`
+}
+
+func (*ProcessEpochResult) GetTypeID() uint8 {
+	return mconsts.ProcessEpochID
+}
+
+// MarkCompaction records that indexes may drop history below [Height]. The
+// marker only moves forward, and never past the accepted chain.
+type MarkCompaction struct {
+	Height uint64 `serialize:"true" json:"height"`
+}
+
+func (*MarkCompaction) GetTypeID() uint8 {
+	return mconsts.MarkCompactionID
+}
+
+func (*MarkCompaction) StateKeys(codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.CompactionKey()):                   state.All,
+		string(chain.HeightKey(storage.HeightKey())):      state.Read,
+		string(storage.ConfigKey(MaintenanceAddressRule)): state.Read,
+		string(storage.HaltKey()):                         state.Read,
+	}
+}
+
+func (m *MarkCompaction) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, m, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if err := checkMaintainer(ctx, r, mu, actor); err != nil {
+		return nil, err
+	}
+	parent, err := storage.GetHeight(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := storage.GetCompactionHeight(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	if m.Height <= previous || m.Height > parent {
+		return nil, ErrCompactionHeight
+	}
+	if err := storage.SetCompactionHeight(ctx, mu, m.Height); err != nil {
+		return nil, err
+	}
+	return &MarkCompactionResult{Previous: previous, Height: m.Height}, nil
+}
+
+func (*MarkCompaction) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.MarkCompactionID, MaintenanceComputeUnits)
+}
+
+func (*MarkCompaction) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*MarkCompactionResult)(nil)
+
+type MarkCompactionResult struct {
+	Previous uint64 `serialize:"true" json:"previous"`
+	Height   uint64 `serialize:"true" json:"height"`
+}
+
+func (*MarkCompactionResult) GetTypeID() uint8 {
+	return mconsts.MarkCompactionID
+}
+
+// MaintenanceRules returns the maintenance address of [r], which is empty
+// if it has none. Governance can replace it through the [Config] registry.
+func MaintenanceRules(r chain.Rules) codec.Address {
+	if r == nil {
+		return codec.EmptyAddress
+	}
+	if v, ok := r.FetchCustom(MaintenanceAddressRule); ok {
+		if addr, ok := v.(codec.Address); ok {
+			return addr
+		}
+	}
+	return codec.EmptyAddress
+}
+
+func checkMaintainer(ctx context.Context, r chain.Rules, im state.Immutable, actor codec.Address) error {
+	maintainer, err := configAddress(ctx, r, im, MaintenanceAddressRule)
+	if err != nil {
+		return err
+	}
+	if maintainer == codec.EmptyAddress || actor != maintainer {
+		return ErrNotMaintainer
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// maintenanceRules sets the maintenance address and a 10 block epoch.
+type maintenanceRules struct {
+	*genesis.Rules
+	maintainer codec.Address
+}
+
+func (r *maintenanceRules) FetchCustom(key string) (any, bool) {
+	switch key {
+	case MaintenanceAddressRule:
+		return r.maintainer, true
+	case StakingEpochLengthRule:
+		return uint64(10), true
+	default:
+		return nil, false
+	}
+}
+
+func TestMaintenanceActions(t *testing.T) {
+	maintainer := codectest.NewRandomAddress()
+	staker := codectest.NewRandomAddress()
+	idle := codectest.NewRandomAddress()
+	rules := &maintenanceRules{Rules: genesis.NewDefaultRules(), maintainer: maintainer}
+
+	// node has [staker] staking 10,000 since epoch 1 and a compaction marker
+	// at 20. The parent is at height 49, so the actions run in epoch 5.
+	node := func() state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, store.Insert(ctx, chain.HeightKey(storage.HeightKey()), binary.BigEndian.AppendUint64(nil, 49)))
+		require.NoError(t, storage.SetStake(ctx, store, staker, &storage.Stake{Amount: 10_000, Epoch: 1}))
+		require.NoError(t, storage.SetCompactionHeight(ctx, store, 20))
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:        "NotMaintainer",
+			Actor:       staker,
+			Action:      &ProcessEpoch{Stakers: []codec.Address{staker}},
+			Rules:       rules,
+			State:       node(),
+			ExpectedErr: ErrNotMaintainer,
+		},
+		{
+			Name:        "MaintenanceDisabled",
+			Actor:       codec.EmptyAddress,
+			Action:      &MarkCompaction{Height: 30},
+			Rules:       &maintenanceRules{Rules: genesis.NewDefaultRules()},
+			State:       node(),
+			ExpectedErr: ErrNotMaintainer,
+		},
+		{
+			// The maintenance address set by governance replaces the rules'.
+			Name:   "GovernedMaintainer",
+			Actor:  staker,
+			Action: &MarkCompaction{Height: 30},
+			Rules:  rules,
+			State: func() state.Mutable {
+				store := node()
+				require.NoError(t, storage.SetConfigValue(context.Background(), store, MaintenanceAddressRule, staker[:]))
+				return store
+			}(),
+			ExpectedOutputs: &MarkCompactionResult{Previous: 20, Height: 30},
+		},
+		{
+			Name:        "DuplicateStaker",
+			Actor:       maintainer,
+			Action:      &ProcessEpoch{Stakers: []codec.Address{staker, staker}},
+			Rules:       rules,
+			State:       node(),
+			ExpectedErr: ErrDuplicateStaker,
+		},
+		{
+			Name:   "ProcessEpoch",
+			Actor:  maintainer,
+			Action: &ProcessEpoch{Stakers: []codec.Address{staker, idle}},
+			Rules:  rules,
+			State:  node(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				stake, exists, err := storage.GetStake(ctx, store, staker)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.Stake{Amount: 10_000, Rewards: 40, Epoch: 5}, stake)
+				_, exists, err = storage.GetStake(ctx, store, idle)
+				require.NoError(t, err)
+				require.False(t, exists)
+			},
+			ExpectedOutputs: &ProcessEpochResult{Epoch: 5, Settled: 1},
+		},
+		{
+			Name:        "CompactionBackwards",
+			Actor:       maintainer,
+			Action:      &MarkCompaction{Height: 20},
+			Rules:       rules,
+			State:       node(),
+			ExpectedErr: ErrCompactionHeight,
+		},
+		{
+			Name:        "CompactionNotAccepted",
+			Actor:       maintainer,
+			Action:      &MarkCompaction{Height: 50},
+			Rules:       rules,
+			State:       node(),
+			ExpectedErr: ErrCompactionHeight,
+		},
+		{
+			Name:   "MarkCompaction",
+			Actor:  maintainer,
+			Action: &MarkCompaction{Height: 49},
+			Rules:  rules,
+			State:  node(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				height, err := storage.GetCompactionHeight(ctx, store)
+				require.NoError(t, err)
+				require.Equal(t, uint64(49), height)
+			},
+			ExpectedOutputs: &MarkCompactionResult{Previous: 20, Height: 49},
+		},
+		{
+			Name:   "FirstCompaction",
+			Actor:  maintainer,
+			Action: &MarkCompaction{Height: 30},
+			Rules:  rules,
+			State: func() state.Mutable {
+				store := node()
+				require.NoError(t, store.Remove(context.Background(), storage.CompactionKey()))
+				return store
+			}(),
+			ExpectedOutputs: &MarkCompactionResult{Height: 30},
+		},
+	}
+
+	runActionTests(t, tests)
+}
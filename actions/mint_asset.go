@@ -0,0 +1,119 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const MintAssetComputeUnits = 1
+
+var _ chain.Action = (*MintAsset)(nil)
+
+// MintAsset creates an asset under an ID chosen by the caller. Prefer
+// [CreateAsset], whose ID is derived from the actor and cannot be taken by
+// someone else first.
+type MintAsset struct {
+	// Asset is created with the actor as its owner.
+	Asset ids.ID `serialize:"true" json:"asset"`
+
+	// Soulbound assets can never be transferred, only burned by their
+	// owner.
+	Soulbound bool `serialize:"true" json:"soulbound"`
+
+	// Expiry is the last timestamp, in milliseconds, at which the asset can
+	// be transferred, or zero if it never expires.
+	Expiry int64 `serialize:"true" json:"expiry"`
+}
+
+// GetTypeID implements chain.Action.
+func (*MintAsset) GetTypeID() uint8 {
+	return mconsts.MintAssetID
+}
+
+// StateKeys implements chain.Action.
+func (m *MintAsset) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.AssetKey(m.Asset)):                       state.Read | state.Allocate | state.Write,
+		string(storage.OwnedAssetKey(actor, m.Asset)):           state.Allocate | state.Write,
+		string(storage.TombstoneKey(storage.AssetKey(m.Asset))): state.Read,
+		string(storage.HaltKey()):                               state.Read,
+	}
+}
+
+// Execute implements chain.Action.
+func (m *MintAsset) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, m, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if err := createAsset(ctx, mu, timestamp, m.Asset, actor, m.Soulbound, m.Expiry); err != nil {
+		return nil, err
+	}
+	return &MintAssetResult{
+		Asset: m.Asset,
+		Owner: actor,
+	}, nil
+}
+
+// ComputeUnits implements chain.Action.
+func (*MintAsset) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.MintAssetID, MintAssetComputeUnits)
+}
+
+// ValidRange implements chain.Action.
+func (*MintAsset) ValidRange(chain.Rules) (int64, int64) {
+	return -1, -1
+}
+
+func (m *MintAsset) EmitEvents(actor codec.Address, _ codec.Typed, e Emitter) {
+	e.Emit(&OwnershipEvent{Asset: m.Asset, To: actor})
+}
+
+// createAsset creates [asset] for [actor], which is its minter and owner.
+func createAsset(
+	ctx context.Context,
+	mu state.Mutable,
+	timestamp int64,
+	asset ids.ID,
+	actor codec.Address,
+	soulbound bool,
+	expiry int64,
+) error {
+	c := storage.AssetControl{Minter: actor, Soulbound: soulbound, Expiry: expiry}
+	if c.Expired(timestamp) {
+		return ErrAssetExpired
+	}
+	return storage.CreateAssetWithControl(ctx, mu, asset, c, actor)
+}
+
+var _ codec.Typed = (*MintAssetResult)(nil)
+
+type MintAssetResult struct {
+	Asset ids.ID        `serialize:"true" json:"asset"`
+	Owner codec.Address `serialize:"true" json:"owner"`
+}
+
+func (*MintAssetResult) GetTypeID() uint8 {
+	return mconsts.MintAssetID
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestMintAssetAction(t *testing.T) {
+	actor := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "AlreadyExists",
+			Actor: actor,
+			Action: &MintAsset{
+				Asset: asset,
+			},
+			State: func() state.Mutable {
+				store := chaintest.NewInMemoryStore()
+				require.NoError(t, storage.CreateAsset(context.Background(), store, asset, codec.EmptyAddress))
+				return store
+			}(),
+			ExpectedErr: storage.ErrAssetExists,
+		},
+		{
+			Name:  "Mint",
+			Actor: actor,
+			Action: &MintAsset{
+				Asset: asset,
+			},
+			State: chaintest.NewInMemoryStore(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				owner, err := storage.GetAssetOwner(ctx, store, asset)
+				require.NoError(t, err)
+				require.Equal(t, actor, owner)
+			},
+			ExpectedOutputs: &MintAssetResult{
+				Asset: asset,
+				Owner: actor,
+			},
+		},
+	}
+
+	runActionTests(t, tests)
+}
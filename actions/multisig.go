@@ -0,0 +1,351 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const MultisigComputeUnits = 1
+
+var (
+	ErrMultisigExists    = errors.New("multisig already exists")
+	ErrMultisigNotFound  = errors.New("multisig not found")
+	ErrNotMultisigSigner = errors.New("actor is not a multisig signer")
+	ErrInvalidMultisigTx = errors.New("asset transfers carry no value")
+
+	_ chain.Action = (*CreateMultisig)(nil)
+	_ chain.Action = (*ProposeMultisigTx)(nil)
+	_ chain.Action = (*ApproveMultisigTx)(nil)
+)
+
+// CreateMultisig registers an M-of-N signer set under a new address derived
+// from the actor and [Nonce]. Funds sent to that address only leave through
+// transactions approved by [Threshold] of [Signers].
+type CreateMultisig struct {
+	Nonce     uint64          `serialize:"true" json:"nonce"`
+	Signers   []codec.Address `serialize:"true" json:"signers"`
+	Threshold uint8           `serialize:"true" json:"threshold"`
+}
+
+func (*CreateMultisig) GetTypeID() uint8 {
+	return mconsts.CreateMultisigID
+}
+
+func (c *CreateMultisig) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.MultisigKey(storage.MultisigAddress(actor, c.Nonce))): state.All,
+		string(storage.HaltKey()): state.Read,
+	}
+}
+
+func (c *CreateMultisig) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, c, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	multisig := storage.MultisigAddress(actor, c.Nonce)
+	_, exists, err := storage.GetMultisig(ctx, mu, multisig)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrMultisigExists
+	}
+	if err := storage.SetMultisig(ctx, mu, multisig, &storage.Multisig{
+		Signers:   c.Signers,
+		Threshold: c.Threshold,
+	}); err != nil {
+		return nil, err
+	}
+	return &CreateMultisigResult{Multisig: multisig}, nil
+}
+
+func (*CreateMultisig) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.CreateMultisigID, MultisigComputeUnits)
+}
+
+func (*CreateMultisig) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*CreateMultisigResult)(nil)
+
+type CreateMultisigResult struct {
+	Multisig codec.Address `serialize:"true" json:"multisig"`
+}
+
+func (*CreateMultisigResult) GetTypeID() uint8 {
+	return mconsts.CreateMultisigID
+}
+
+// ProposeMultisigTx opens a proposal for [Multisig] to make [Tx], approved
+// by the proposing signer. [Tx] runs as a [Transfer] or [AssetTransfer] from
+// the multisig in the action that brings approvals to its threshold.
+type ProposeMultisigTx struct {
+	Multisig codec.Address `serialize:"true" json:"multisig"`
+	// ProposalID is chosen by the proposer and must not be pending.
+	ProposalID ids.ID             `serialize:"true" json:"proposal_id"`
+	Tx         storage.MultisigTx `serialize:"true" json:"tx"`
+	// Expiry is the last timestamp, in milliseconds, at which the proposal
+	// can be approved.
+	Expiry int64 `serialize:"true" json:"expiry"`
+}
+
+func (*ProposeMultisigTx) GetTypeID() uint8 {
+	return mconsts.ProposeMultisigTxID
+}
+
+func (p *ProposeMultisigTx) StateKeys(codec.Address) state.Keys {
+	return multisigTxStateKeys(p.Multisig, p.ProposalID, p.Tx, state.All)
+}
+
+func (p *ProposeMultisigTx) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	actionID ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, p, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if p.Tx.Asset != storage.NativeAsset && p.Tx.Value != 0 {
+		return nil, ErrInvalidMultisigTx
+	}
+	if p.Expiry < timestamp {
+		return nil, ErrProposalExpired
+	}
+	multisig, index, err := multisigSigner(ctx, mu, p.Multisig, actor)
+	if err != nil {
+		return nil, err
+	}
+	_, exists, err := storage.GetMultisigProposal(ctx, mu, p.Multisig, p.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrProposalExists
+	}
+	proposal := &storage.MultisigProposal{
+		Tx:        p.Tx,
+		Expiry:    p.Expiry,
+		Approvals: 1 << index,
+	}
+	executed, err := recordMultisigApproval(ctx, r, mu, timestamp, actionID, p.Multisig, multisig, p.ProposalID, proposal)
+	if err != nil {
+		return nil, err
+	}
+	return &ProposeMultisigTxResult{
+		Approvals: uint8(proposal.ApprovalCount()),
+		Executed:  executed,
+	}, nil
+}
+
+func (p *ProposeMultisigTx) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.ProposeMultisigTxID, MultisigComputeUnits) + multisigAction(p.Tx).ComputeUnits(r)
+}
+
+func (*ProposeMultisigTx) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (p *ProposeMultisigTx) EmitEvents(_ codec.Address, output codec.Typed, e Emitter) {
+	if r, ok := output.(*ProposeMultisigTxResult); ok && r.Executed {
+		emitMultisigTx(p.Multisig, p.Tx, e)
+	}
+}
+
+var _ codec.Typed = (*ProposeMultisigTxResult)(nil)
+
+type ProposeMultisigTxResult struct {
+	Approvals uint8 `serialize:"true" json:"approvals"`
+	Executed  bool  `serialize:"true" json:"executed"`
+}
+
+func (*ProposeMultisigTxResult) GetTypeID() uint8 {
+	return mconsts.ProposeMultisigTxID
+}
+
+// ApproveMultisigTx adds the actor's approval to a pending proposal of
+// [Multisig].
+type ApproveMultisigTx struct {
+	Multisig   codec.Address `serialize:"true" json:"multisig"`
+	ProposalID ids.ID        `serialize:"true" json:"proposal_id"`
+	// Tx must match the transaction of the proposal, so the keys it touches
+	// can be declared in [StateKeys].
+	Tx storage.MultisigTx `serialize:"true" json:"tx"`
+}
+
+func (*ApproveMultisigTx) GetTypeID() uint8 {
+	return mconsts.ApproveMultisigTxID
+}
+
+func (a *ApproveMultisigTx) StateKeys(codec.Address) state.Keys {
+	// The proposal already exists.
+	return multisigTxStateKeys(a.Multisig, a.ProposalID, a.Tx, state.Read|state.Write)
+}
+
+func (a *ApproveMultisigTx) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	actionID ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, a, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	multisig, index, err := multisigSigner(ctx, mu, a.Multisig, actor)
+	if err != nil {
+		return nil, err
+	}
+	proposal, exists, err := storage.GetMultisigProposal(ctx, mu, a.Multisig, a.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrProposalNotFound
+	}
+	if proposal.Tx != a.Tx {
+		return nil, ErrProposalMismatch
+	}
+	if proposal.Expiry < timestamp {
+		return nil, ErrProposalExpired
+	}
+	if proposal.Approvals&(1<<index) != 0 {
+		return nil, ErrAlreadyApproved
+	}
+	proposal.Approvals |= 1 << index
+	executed, err := recordMultisigApproval(ctx, r, mu, timestamp, actionID, a.Multisig, multisig, a.ProposalID, proposal)
+	if err != nil {
+		return nil, err
+	}
+	return &ApproveMultisigTxResult{
+		Approvals: uint8(proposal.ApprovalCount()),
+		Executed:  executed,
+	}, nil
+}
+
+func (a *ApproveMultisigTx) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.ApproveMultisigTxID, MultisigComputeUnits) + multisigAction(a.Tx).ComputeUnits(r)
+}
+
+func (*ApproveMultisigTx) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (a *ApproveMultisigTx) EmitEvents(_ codec.Address, output codec.Typed, e Emitter) {
+	if r, ok := output.(*ApproveMultisigTxResult); ok && r.Executed {
+		emitMultisigTx(a.Multisig, a.Tx, e)
+	}
+}
+
+var _ codec.Typed = (*ApproveMultisigTxResult)(nil)
+
+type ApproveMultisigTxResult struct {
+	Approvals uint8 `serialize:"true" json:"approvals"`
+	Executed  bool  `serialize:"true" json:"executed"`
+}
+
+func (*ApproveMultisigTxResult) GetTypeID() uint8 {
+	return mconsts.ApproveMultisigTxID
+}
+
+// multisigAction is the action [tx] runs as, with the multisig as actor.
+func multisigAction(tx storage.MultisigTx) chain.Action {
+	if tx.Asset == storage.NativeAsset {
+		return &Transfer{To: tx.To, Value: tx.Value}
+	}
+	return &AssetTransfer{Recipient: tx.To, Asset: tx.Asset}
+}
+
+func multisigTxStateKeys(multisig codec.Address, proposalID ids.ID, tx storage.MultisigTx, proposal state.Permissions) state.Keys {
+	keys := multisigAction(tx).StateKeys(multisig)
+	keys.Add(string(storage.MultisigKey(multisig)), state.Read)
+	keys.Add(string(storage.MultisigProposalKey(multisig, proposalID)), proposal)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+// multisigSigner returns the signer set of [multisig] and the position of
+// [actor] in it.
+func multisigSigner(
+	ctx context.Context,
+	im state.Immutable,
+	multisig codec.Address,
+	actor codec.Address,
+) (*storage.Multisig, int, error) {
+	m, exists, err := storage.GetMultisig(ctx, im, multisig)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !exists {
+		return nil, 0, ErrMultisigNotFound
+	}
+	index := m.Index(actor)
+	if index < 0 {
+		return nil, 0, ErrNotMultisigSigner
+	}
+	return m, index, nil
+}
+
+// recordMultisigApproval stores [proposal], or runs its transaction and
+// removes it once it reaches the threshold of [m]. It returns whether the
+// transaction ran.
+func recordMultisigApproval(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actionID ids.ID,
+	multisig codec.Address,
+	m *storage.Multisig,
+	proposalID ids.ID,
+	proposal *storage.MultisigProposal,
+) (bool, error) {
+	if proposal.ApprovalCount() < int(m.Threshold) {
+		return false, storage.SetMultisigProposal(ctx, mu, multisig, proposalID, proposal)
+	}
+	if err := storage.DeleteMultisigProposal(ctx, mu, multisig, proposalID); err != nil {
+		return false, err
+	}
+	if _, err := multisigAction(proposal.Tx).Execute(ctx, r, mu, timestamp, multisig, actionID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
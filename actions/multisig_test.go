@@ -0,0 +1,239 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestMultisigActions(t *testing.T) {
+	signers := []codec.Address{
+		codectest.NewRandomAddress(),
+		codectest.NewRandomAddress(),
+		codectest.NewRandomAddress(),
+	}
+	outsider := codectest.NewRandomAddress()
+	to := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+	proposalID := ids.GenerateTestID()
+	multisig := storage.MultisigAddress(signers[0], 1)
+	pay := storage.MultisigTx{To: to, Asset: storage.NativeAsset, Value: 4}
+
+	// wallet is a funded 2-of-3 multisig owning [asset], with [approvals]
+	// already given to a proposal making [tx] when non-zero.
+	wallet := func(tx storage.MultisigTx, approvals uint16) state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(ctx, store, multisig, 10))
+		require.NoError(t, storage.CreateAsset(ctx, store, asset, multisig))
+		require.NoError(t, storage.SetMultisig(ctx, store, multisig, &storage.Multisig{
+			Signers:   signers,
+			Threshold: 2,
+		}))
+		if approvals != 0 {
+			require.NoError(t, storage.SetMultisigProposal(ctx, store, multisig, proposalID, &storage.MultisigProposal{
+				Tx:        tx,
+				Expiry:    100,
+				Approvals: approvals,
+			}))
+		}
+		return store
+	}
+	requireBalance := func(ctx context.Context, t *testing.T, store state.Mutable, addr codec.Address, expected uint64) {
+		balance, err := storage.GetBalance(ctx, store, addr)
+		require.NoError(t, err)
+		require.Equal(t, expected, balance)
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "Create",
+			Actor: signers[0],
+			Action: &CreateMultisig{
+				Nonce:     1,
+				Signers:   signers,
+				Threshold: 2,
+			},
+			State: chaintest.NewInMemoryStore(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				m, exists, err := storage.GetMultisig(ctx, store, multisig)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.Multisig{Signers: signers, Threshold: 2}, m)
+			},
+			ExpectedOutputs: &CreateMultisigResult{Multisig: multisig},
+		},
+		{
+			Name:        "CreateExisting",
+			Actor:       signers[0],
+			Action:      &CreateMultisig{Nonce: 1, Signers: signers, Threshold: 1},
+			State:       wallet(pay, 0),
+			ExpectedErr: ErrMultisigExists,
+		},
+		{
+			Name:        "CreateThresholdTooHigh",
+			Actor:       signers[0],
+			Action:      &CreateMultisig{Nonce: 2, Signers: signers, Threshold: 4},
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: storage.ErrInvalidMultisig,
+		},
+		{
+			Name:  "NotSigner",
+			Actor: outsider,
+			Action: &ProposeMultisigTx{
+				Multisig:   multisig,
+				ProposalID: proposalID,
+				Tx:         pay,
+				Expiry:     100,
+			},
+			State:       wallet(pay, 0),
+			ExpectedErr: ErrNotMultisigSigner,
+		},
+		{
+			Name:  "ProposeNotFound",
+			Actor: signers[0],
+			Action: &ProposeMultisigTx{
+				Multisig:   storage.MultisigAddress(signers[0], 2),
+				ProposalID: proposalID,
+				Tx:         pay,
+				Expiry:     100,
+			},
+			State:       wallet(pay, 0),
+			ExpectedErr: ErrMultisigNotFound,
+		},
+		{
+			Name:  "ProposeAssetWithValue",
+			Actor: signers[0],
+			Action: &ProposeMultisigTx{
+				Multisig:   multisig,
+				ProposalID: proposalID,
+				Tx:         storage.MultisigTx{To: to, Asset: asset, Value: 1},
+				Expiry:     100,
+			},
+			State:       wallet(pay, 0),
+			ExpectedErr: ErrInvalidMultisigTx,
+		},
+		{
+			Name:  "Propose",
+			Actor: signers[1],
+			Action: &ProposeMultisigTx{
+				Multisig:   multisig,
+				ProposalID: proposalID,
+				Tx:         pay,
+				Expiry:     100,
+			},
+			State: wallet(pay, 0),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				proposal, exists, err := storage.GetMultisigProposal(ctx, store, multisig, proposalID)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, uint16(0b010), proposal.Approvals)
+				requireBalance(ctx, t, store, multisig, 10)
+			},
+			ExpectedOutputs: &ProposeMultisigTxResult{Approvals: 1},
+		},
+		{
+			// A multisig of threshold 1 pays on the proposal.
+			Name:  "ProposeExecutes",
+			Actor: signers[1],
+			Action: &ProposeMultisigTx{
+				Multisig:   multisig,
+				ProposalID: proposalID,
+				Tx:         pay,
+				Expiry:     100,
+			},
+			State: func() state.Mutable {
+				store := wallet(pay, 0)
+				require.NoError(t, storage.SetMultisig(context.Background(), store, multisig, &storage.Multisig{
+					Signers:   signers,
+					Threshold: 1,
+				}))
+				return store
+			}(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				_, exists, err := storage.GetMultisigProposal(ctx, store, multisig, proposalID)
+				require.NoError(t, err)
+				require.False(t, exists)
+				requireBalance(ctx, t, store, to, 4)
+			},
+			ExpectedOutputs: &ProposeMultisigTxResult{Approvals: 1, Executed: true},
+		},
+		{
+			Name:  "ProposeExisting",
+			Actor: signers[1],
+			Action: &ProposeMultisigTx{
+				Multisig:   multisig,
+				ProposalID: proposalID,
+				Tx:         pay,
+				Expiry:     100,
+			},
+			State:       wallet(pay, 0b001),
+			ExpectedErr: ErrProposalExists,
+		},
+		{
+			Name:        "ApproveMismatch",
+			Actor:       signers[1],
+			Action:      &ApproveMultisigTx{Multisig: multisig, ProposalID: proposalID, Tx: storage.MultisigTx{To: outsider, Value: 4}},
+			State:       wallet(pay, 0b001),
+			ExpectedErr: ErrProposalMismatch,
+		},
+		{
+			Name:        "ApproveTwice",
+			Actor:       signers[0],
+			Action:      &ApproveMultisigTx{Multisig: multisig, ProposalID: proposalID, Tx: pay},
+			State:       wallet(pay, 0b001),
+			ExpectedErr: ErrAlreadyApproved,
+		},
+		{
+			Name:        "ApproveExpired",
+			Actor:       signers[1],
+			Action:      &ApproveMultisigTx{Multisig: multisig, ProposalID: proposalID, Tx: pay},
+			Timestamp:   101,
+			State:       wallet(pay, 0b001),
+			ExpectedErr: ErrProposalExpired,
+		},
+		{
+			Name:   "ApproveTransfer",
+			Actor:  signers[2],
+			Action: &ApproveMultisigTx{Multisig: multisig, ProposalID: proposalID, Tx: pay},
+			State:  wallet(pay, 0b001),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				_, exists, err := storage.GetMultisigProposal(ctx, store, multisig, proposalID)
+				require.NoError(t, err)
+				require.False(t, exists)
+				requireBalance(ctx, t, store, multisig, 6)
+				requireBalance(ctx, t, store, to, 4)
+			},
+			ExpectedOutputs: &ApproveMultisigTxResult{Approvals: 2, Executed: true},
+		},
+		{
+			Name:  "ApproveAssetTransfer",
+			Actor: signers[1],
+			Action: &ApproveMultisigTx{
+				Multisig:   multisig,
+				ProposalID: proposalID,
+				Tx:         storage.MultisigTx{To: to, Asset: asset},
+			},
+			State: wallet(storage.MultisigTx{To: to, Asset: asset}, 0b100),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				owner, err := storage.GetAssetOwner(ctx, store, asset)
+				require.NoError(t, err)
+				require.Equal(t, to, owner)
+			},
+			ExpectedOutputs: &ApproveMultisigTxResult{Approvals: 2, Executed: true},
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -0,0 +1,242 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const (
+	OnboardAccountComputeUnits = 1
+
+	// MaxOnboardAssets bounds the starter assets of one onboarding.
+	MaxOnboardAssets = 8
+)
+
+// Claim keys the name and profile of an onboarded account are stored under.
+const (
+	NameClaimKey    = "name"
+	ProfileClaimKey = "profile"
+)
+
+var (
+	ErrInvalidPublicKey = errors.New("invalid ed25519 public key")
+	ErrAccountExists    = errors.New("account already exists")
+	ErrTooManyAssets    = errors.New("too many starter assets")
+
+	_ chain.Action = (*OnboardAccount)(nil)
+)
+
+// OnboardAsset is an amount of a fungible starter asset.
+type OnboardAsset struct {
+	Asset ids.ID `serialize:"true" json:"asset"`
+	Value uint64 `serialize:"true" json:"value"`
+}
+
+// OnboardAccount creates the account of a new user in one action. The
+// actor, its sponsor, funds it with [Value] of the native token and up to
+// [MaxOnboardAssets] starter [Assets], and may set its [Name] and [Profile]
+// claims, paying their rent. Either all of it is applied or none is.
+type OnboardAccount struct {
+	// PublicKey is the ed25519 public key of the new user. The account is
+	// the address derived from it, and must hold no native balance yet.
+	PublicKey []byte `serialize:"true" json:"public_key"`
+
+	// Value is the native balance the account starts with.
+	Value uint64 `serialize:"true" json:"value"`
+
+	Assets []OnboardAsset `serialize:"true" json:"assets"`
+
+	// Name and Profile are stored as the account's [NameClaimKey] and
+	// [ProfileClaimKey] claims, which it can replace or remove later.
+	// Empty values set no claim.
+	Name    []byte `serialize:"true" json:"name"`
+	Profile []byte `serialize:"true" json:"profile"`
+
+	// ClaimExpiry is when the claims expire, in milliseconds. It is ignored
+	// without claims.
+	ClaimExpiry int64 `serialize:"true" json:"claim_expiry"`
+}
+
+func (*OnboardAccount) GetTypeID() uint8 {
+	return mconsts.OnboardAccountID
+}
+
+// Account returns the address of the onboarded account.
+func (o *OnboardAccount) Account() codec.Address {
+	var pk ed25519.PublicKey
+	copy(pk[:], o.PublicKey)
+	return auth.NewED25519Address(pk)
+}
+
+// claims returns the claims [o] sets, by key.
+func (o *OnboardAccount) claims() map[string][]byte {
+	claims := make(map[string][]byte, 2)
+	if len(o.Name) > 0 {
+		claims[NameClaimKey] = o.Name
+	}
+	if len(o.Profile) > 0 {
+		claims[ProfileClaimKey] = o.Profile
+	}
+	return claims
+}
+
+func (o *OnboardAccount) StateKeys(actor codec.Address) state.Keys {
+	account := o.Account()
+	keys := state.Keys{
+		string(storage.BalanceKey(actor)):   state.Read | state.Write,
+		string(storage.BalanceKey(account)): state.All,
+		string(storage.ActiveProposalKey()): state.Read,
+	}
+	for _, a := range o.Assets {
+		keys.Add(string(storage.AssetBalanceKey(actor, a.Asset)), state.Read|state.Write)
+		keys.Add(string(storage.AssetBalanceKey(account, a.Asset)), state.All)
+		keys.Add(string(storage.StablecoinBlockKey(a.Asset, actor)), state.Read)
+		keys.Add(string(storage.StablecoinBlockKey(a.Asset, account)), state.Read)
+		addTransferHookKeys(keys, actor, a.Asset)
+	}
+	for key := range o.claims() {
+		keys.Add(string(storage.ClaimKey(account, []byte(key))), state.All)
+	}
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (o *OnboardAccount) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, o, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if len(o.PublicKey) != ed25519.PublicKeyLen {
+		return nil, ErrInvalidPublicKey
+	}
+	if o.Value == 0 {
+		return nil, ErrOutputValueZero
+	}
+	if len(o.Assets) > MaxOnboardAssets {
+		return nil, ErrTooManyAssets
+	}
+	account := o.Account()
+	balance, err := storage.GetBalance(ctx, mu, account)
+	if err != nil {
+		return nil, err
+	}
+	if balance > 0 {
+		return nil, ErrAccountExists
+	}
+
+	claims := o.claims()
+	result := &OnboardAccountResult{Account: account}
+	if len(claims) > 0 && (o.ClaimExpiry <= timestamp || o.ClaimExpiry-timestamp > MaxClaimLifetime) {
+		return nil, ErrClaimExpiry
+	}
+	for key, value := range claims {
+		if len(value) > storage.MaxClaimValueSize {
+			return nil, ErrClaimValueSize
+		}
+		rent, err := ClaimRent(r, len(key)+len(value), o.ClaimExpiry-timestamp)
+		if err != nil {
+			return nil, err
+		}
+		if result.Rent, err = smath.Add(result.Rent, rent); err != nil {
+			return nil, err
+		}
+		if err := storage.SetClaim(ctx, mu, account, []byte(key), &storage.Claim{
+			Value:  value,
+			Expiry: o.ClaimExpiry,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, a := range o.Assets {
+		if a.Value == 0 {
+			return nil, ErrOutputValueZero
+		}
+		if _, err := runTransferHook(ctx, r, mu, actor, a.Asset); err != nil {
+			return nil, err
+		}
+		if err := checkNotBlocked(ctx, mu, a.Asset, actor, account); err != nil {
+			return nil, err
+		}
+		if _, err := storage.SubAssetBalance(ctx, mu, actor, a.Asset, a.Value); err != nil {
+			return nil, err
+		}
+		if _, err := storage.AddAssetBalance(ctx, mu, account, a.Asset, a.Value, true); err != nil {
+			return nil, err
+		}
+	}
+
+	total, err := smath.Add(o.Value, result.Rent)
+	if err != nil {
+		return nil, err
+	}
+	sponsorBalance, err := storage.SubBalance(ctx, mu, actor, total)
+	if err != nil {
+		return nil, err
+	}
+	accountBalance, err := storage.AddBalance(ctx, mu, account, o.Value, true)
+	if err != nil {
+		return nil, err
+	}
+	if result.SponsorBalance, err = settleDust(ctx, r, mu, actor, sponsorBalance, accountBalance); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (o *OnboardAccount) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.OnboardAccountID, OnboardAccountComputeUnits) +
+		uint64(len(o.Assets)*TransferAssetComputeUnits) +
+		uint64(len(o.claims())*ClaimComputeUnits)
+}
+
+func (*OnboardAccount) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (o *OnboardAccount) EmitEvents(actor codec.Address, _ codec.Typed, e Emitter) {
+	account := o.Account()
+	e.Emit(&TransferEvent{From: actor, To: account, Asset: storage.NativeAsset, Amount: o.Value})
+	for _, a := range o.Assets {
+		e.Emit(&TransferEvent{From: actor, To: account, Asset: a.Asset, Amount: a.Value})
+	}
+}
+
+var _ codec.Typed = (*OnboardAccountResult)(nil)
+
+type OnboardAccountResult struct {
+	Account codec.Address `serialize:"true" json:"account"`
+	// Rent is the claim rent the sponsor paid on top of the native value.
+	Rent           uint64 `serialize:"true" json:"rent"`
+	SponsorBalance uint64 `serialize:"true" json:"sponsor_balance"`
+}
+
+func (*OnboardAccountResult) GetTypeID() uint8 {
+	return mconsts.OnboardAccountID
+}
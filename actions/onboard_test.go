@@ -0,0 +1,142 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestOnboardAccountAction(t *testing.T) {
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(t, err)
+	pk := priv.PublicKey()
+	account := auth.NewED25519Address(pk)
+	sponsor := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+	hour := int64(claimRentPeriod)
+
+	// funded is a state where the sponsor holds 100 native tokens and 10 of
+	// [asset], and the new account holds [existing] native tokens.
+	funded := func(existing uint64) state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(ctx, store, sponsor, 100))
+		_, err := storage.AddAssetBalance(ctx, store, sponsor, asset, 10, true)
+		require.NoError(t, err)
+		if existing > 0 {
+			require.NoError(t, storage.SetBalance(ctx, store, account, existing))
+		}
+		return store
+	}
+	requireBalances := func(ctx context.Context, t *testing.T, store state.Mutable, addr codec.Address, native uint64, fungible uint64) {
+		balance, err := storage.GetBalance(ctx, store, addr)
+		require.NoError(t, err)
+		require.Equal(t, native, balance)
+		balance, err = storage.GetAssetBalance(ctx, store, addr, asset)
+		require.NoError(t, err)
+		require.Equal(t, fungible, balance)
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:   "Onboard",
+			Actor:  sponsor,
+			Action: &OnboardAccount{PublicKey: pk[:], Value: 20, Assets: []OnboardAsset{{Asset: asset, Value: 4}}},
+			State:  funded(0),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireBalances(ctx, t, store, account, 20, 4)
+				requireBalances(ctx, t, store, sponsor, 80, 6)
+			},
+			ExpectedOutputs: &OnboardAccountResult{Account: account, SponsorBalance: 80},
+		},
+		{
+			Name:  "OnboardWithClaims",
+			Actor: sponsor,
+			Action: &OnboardAccount{
+				PublicKey:   pk[:],
+				Value:       20,
+				Name:        []byte("alice"),
+				Profile:     []byte("hi"),
+				ClaimExpiry: hour,
+			},
+			State: funded(0),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireBalances(ctx, t, store, sponsor, 62, 10)
+				name, exists, err := storage.GetClaim(ctx, store, account, []byte(NameClaimKey))
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.Claim{Value: []byte("alice"), Expiry: hour}, name)
+				profile, exists, err := storage.GetClaim(ctx, store, account, []byte(ProfileClaimKey))
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.Claim{Value: []byte("hi"), Expiry: hour}, profile)
+			},
+			// One hour of rent on 9 bytes for each claim.
+			ExpectedOutputs: &OnboardAccountResult{Account: account, Rent: 18, SponsorBalance: 62},
+		},
+		{
+			Name:        "ExistingAccount",
+			Actor:       sponsor,
+			Action:      &OnboardAccount{PublicKey: pk[:], Value: 20},
+			State:       funded(1),
+			ExpectedErr: ErrAccountExists,
+		},
+		{
+			Name:        "InvalidPublicKey",
+			Actor:       sponsor,
+			Action:      &OnboardAccount{PublicKey: pk[:4], Value: 20},
+			State:       funded(0),
+			ExpectedErr: ErrInvalidPublicKey,
+		},
+		{
+			Name:        "NoValue",
+			Actor:       sponsor,
+			Action:      &OnboardAccount{PublicKey: pk[:]},
+			State:       funded(0),
+			ExpectedErr: ErrOutputValueZero,
+		},
+		{
+			Name:        "TooManyAssets",
+			Actor:       sponsor,
+			Action:      &OnboardAccount{PublicKey: pk[:], Value: 20, Assets: make([]OnboardAsset, MaxOnboardAssets+1)},
+			State:       funded(0),
+			ExpectedErr: ErrTooManyAssets,
+		},
+		{
+			Name:        "ClaimsWithoutExpiry",
+			Actor:       sponsor,
+			Action:      &OnboardAccount{PublicKey: pk[:], Value: 20, Name: []byte("alice")},
+			State:       funded(0),
+			ExpectedErr: ErrClaimExpiry,
+		},
+		{
+			Name:        "InsufficientAsset",
+			Actor:       sponsor,
+			Action:      &OnboardAccount{PublicKey: pk[:], Value: 20, Assets: []OnboardAsset{{Asset: asset, Value: 11}}},
+			State:       funded(0),
+			ExpectedErr: storage.ErrInvalidBalance,
+		},
+		{
+			Name:        "InsufficientBalance",
+			Actor:       sponsor,
+			Action:      &OnboardAccount{PublicKey: pk[:], Value: 101},
+			State:       funded(0),
+			ExpectedErr: storage.ErrInvalidBalance,
+		},
+	}
+
+	runActionTests(t, tests)
+}
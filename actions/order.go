@@ -0,0 +1,406 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const OrderComputeUnits = 1
+
+var (
+	ErrSameAsset        = errors.New("must trade two distinct assets")
+	ErrOrderSequence    = errors.New("order sequence is not the next one")
+	ErrOrderNotFound    = errors.New("order not found")
+	ErrOrderMismatch    = errors.New("order does not match")
+	ErrNotOrderMaker    = errors.New("actor is not the order maker")
+	ErrFillExceedsOrder = errors.New("fill exceeds the remaining order")
+
+	_ chain.Action = (*CreateOrder)(nil)
+	_ chain.Action = (*FillOrder)(nil)
+	_ chain.Action = (*CancelOrder)(nil)
+)
+
+// CreateOrder moves [SellAmount] of [SellAsset] from the actor into a new
+// order, to be sold at the price of [BuyAmount] of [BuyAsset].
+type CreateOrder struct {
+	// Sequence must be the next value of the actor's
+	// [storage.OrderSequenceKey], so the ID of the new order,
+	// [storage.OrderID], can be declared in [StateKeys].
+	Sequence   uint64 `serialize:"true" json:"sequence"`
+	SellAsset  ids.ID `serialize:"true" json:"sell_asset"`
+	SellAmount uint64 `serialize:"true" json:"sell_amount"`
+	BuyAsset   ids.ID `serialize:"true" json:"buy_asset"`
+	BuyAmount  uint64 `serialize:"true" json:"buy_amount"`
+}
+
+func (*CreateOrder) GetTypeID() uint8 {
+	return mconsts.CreateOrderID
+}
+
+func (c *CreateOrder) StateKeys(actor codec.Address) state.Keys {
+	keys := state.Keys{
+		string(storage.OrderKey(c.SellAsset, c.BuyAsset, storage.OrderID(actor, c.Sequence))): state.All,
+		string(storage.OrderSequenceKey(actor)):                                               state.All,
+		string(storage.LegBalanceKey(actor, c.sell())):                                        state.Read | state.Write,
+		string(storage.ActiveProposalKey()):                                                   state.Read,
+	}
+	addHookKey(keys, actor, c.SellAsset)
+	addBlockKeys(keys, c.SellAsset, actor)
+	addBlockKeys(keys, c.BuyAsset, actor)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (c *CreateOrder) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, c, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if c.SellAmount == 0 || c.BuyAmount == 0 {
+		return nil, ErrOutputValueZero
+	}
+	if c.SellAsset == c.BuyAsset {
+		return nil, ErrSameAsset
+	}
+	if err := checkNotBlocked(ctx, mu, c.SellAsset, actor); err != nil {
+		return nil, err
+	}
+	if err := checkNotBlocked(ctx, mu, c.BuyAsset, actor); err != nil {
+		return nil, err
+	}
+	sequence, err := storage.NextSequence(ctx, mu, storage.OrderSequenceKey(actor))
+	if err != nil {
+		return nil, err
+	}
+	if sequence != c.Sequence {
+		return nil, ErrOrderSequence
+	}
+	orderID := storage.OrderID(actor, sequence)
+	if err := runHook(ctx, r, mu, actor, c.SellAsset); err != nil {
+		return nil, err
+	}
+	balance, err := storage.SubLeg(ctx, mu, actor, c.sell())
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.SetOrder(ctx, mu, c.SellAsset, c.BuyAsset, orderID, &storage.Order{
+		Maker:      actor,
+		SellAmount: c.SellAmount,
+		BuyAmount:  c.BuyAmount,
+		Remaining:  c.SellAmount,
+	}); err != nil {
+		return nil, err
+	}
+	return &CreateOrderResult{OrderID: orderID, SellerBalance: balance}, nil
+}
+
+func (c *CreateOrder) sell() storage.SwapLeg {
+	return storage.SwapLeg{Asset: c.SellAsset, Amount: c.SellAmount}
+}
+
+func (c *CreateOrder) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.CreateOrderID, OrderComputeUnits)
+}
+
+func (*CreateOrder) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*CreateOrderResult)(nil)
+
+type CreateOrderResult struct {
+	OrderID ids.ID `serialize:"true" json:"order_id"`
+	// SellerBalance is the maker's balance of the sold asset after escrow.
+	SellerBalance uint64 `serialize:"true" json:"seller_balance"`
+}
+
+func (*CreateOrderResult) GetTypeID() uint8 {
+	return mconsts.CreateOrderID
+}
+
+// FillOrder buys [Amount] of the sold asset from an order, paying the maker
+// at the order's price in one step. The payment is rounded up, so partial
+// fills never sell below the price.
+type FillOrder struct {
+	OrderID   ids.ID `serialize:"true" json:"order_id"`
+	SellAsset ids.ID `serialize:"true" json:"sell_asset"`
+	BuyAsset  ids.ID `serialize:"true" json:"buy_asset"`
+	// Maker must match the order, so the balance it pays can be declared in
+	// [StateKeys].
+	Maker  codec.Address `serialize:"true" json:"maker"`
+	Amount uint64        `serialize:"true" json:"amount"`
+	// Receipt, when not empty, is minted to the actor as a receipt of the
+	// fill. See [mintReceipt].
+	Receipt ids.ID `serialize:"true" json:"receipt"`
+}
+
+func (*FillOrder) GetTypeID() uint8 {
+	return mconsts.FillOrderID
+}
+
+func (f *FillOrder) StateKeys(actor codec.Address) state.Keys {
+	keys := state.Keys{string(storage.OrderKey(f.SellAsset, f.BuyAsset, f.OrderID)): state.Read | state.Write}
+	addTakenKeys(keys, storage.OrderKey(f.SellAsset, f.BuyAsset, f.OrderID))
+	addLegKeys(keys, storage.SwapLeg{Asset: f.BuyAsset}, actor, f.Maker)
+	keys.Add(string(storage.LegBalanceKey(actor, storage.SwapLeg{Asset: f.SellAsset})), state.All)
+	keys.Add(string(storage.ActiveProposalKey()), state.Read)
+	addHookKey(keys, actor, f.SellAsset)
+	addHookKey(keys, actor, f.BuyAsset)
+	addReceiptKeys(keys, actor, f.Receipt)
+	addBlockKeys(keys, f.SellAsset, actor, f.Maker)
+	addBlockKeys(keys, f.BuyAsset, actor, f.Maker)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (f *FillOrder) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	actionID ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, f, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if f.Amount == 0 {
+		return nil, ErrOutputValueZero
+	}
+	order, err := getOrder(ctx, mu, f.SellAsset, f.BuyAsset, f.OrderID, f.Maker)
+	if err != nil {
+		return nil, err
+	}
+	if f.Amount > order.Remaining {
+		return nil, ErrFillExceedsOrder
+	}
+	if err := checkNotBlocked(ctx, mu, f.SellAsset, actor, order.Maker); err != nil {
+		return nil, err
+	}
+	if err := checkNotBlocked(ctx, mu, f.BuyAsset, actor, order.Maker); err != nil {
+		return nil, err
+	}
+	payment := OrderPayment(order, f.Amount)
+	if err := runHook(ctx, r, mu, actor, f.BuyAsset); err != nil {
+		return nil, err
+	}
+	if err := storage.MoveLeg(ctx, mu, actor, order.Maker, storage.SwapLeg{Asset: f.BuyAsset, Amount: payment}); err != nil {
+		return nil, err
+	}
+	if err := runHook(ctx, r, mu, actor, f.SellAsset); err != nil {
+		return nil, err
+	}
+	if _, err := storage.AddLeg(ctx, mu, actor, storage.SwapLeg{Asset: f.SellAsset, Amount: f.Amount}); err != nil {
+		return nil, err
+	}
+	order.Remaining -= f.Amount
+	if order.Remaining == 0 {
+		err = storage.DeleteOrder(ctx, mu, f.SellAsset, f.BuyAsset, f.OrderID)
+	} else {
+		err = storage.SetOrder(ctx, mu, f.SellAsset, f.BuyAsset, f.OrderID, order)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := mintReceipt(ctx, mu, f.Receipt, actor, mconsts.FillOrderID, timestamp, actionID); err != nil {
+		return nil, err
+	}
+	return &FillOrderResult{
+		Maker:     order.Maker,
+		Bought:    f.Amount,
+		Paid:      payment,
+		Remaining: order.Remaining,
+	}, nil
+}
+
+func (f *FillOrder) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.FillOrderID, OrderComputeUnits) + receiptComputeUnits(f.Receipt)
+}
+
+func (*FillOrder) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (f *FillOrder) EmitEvents(actor codec.Address, _ codec.Typed, e Emitter) {
+	emitReceipt(f.Receipt, actor, e)
+}
+
+var _ codec.Typed = (*FillOrderResult)(nil)
+
+type FillOrderResult struct {
+	Maker codec.Address `serialize:"true" json:"maker"`
+	// Bought is the amount of the sold asset received by the actor.
+	Bought uint64 `serialize:"true" json:"bought"`
+	// Paid is the amount of the bought asset paid to the maker.
+	Paid      uint64 `serialize:"true" json:"paid"`
+	Remaining uint64 `serialize:"true" json:"remaining"`
+}
+
+func (*FillOrderResult) GetTypeID() uint8 {
+	return mconsts.FillOrderID
+}
+
+// CancelOrder returns the remaining escrow of an order to its maker.
+type CancelOrder struct {
+	OrderID   ids.ID `serialize:"true" json:"order_id"`
+	SellAsset ids.ID `serialize:"true" json:"sell_asset"`
+	BuyAsset  ids.ID `serialize:"true" json:"buy_asset"`
+}
+
+func (*CancelOrder) GetTypeID() uint8 {
+	return mconsts.CancelOrderID
+}
+
+func (c *CancelOrder) StateKeys(actor codec.Address) state.Keys {
+	keys := state.Keys{
+		string(storage.OrderKey(c.SellAsset, c.BuyAsset, c.OrderID)):              state.Read | state.Write,
+		string(storage.LegBalanceKey(actor, storage.SwapLeg{Asset: c.SellAsset})): state.All,
+	}
+	if c.SellAsset == storage.NativeAsset {
+		keys.Add(string(storage.ActiveProposalKey()), state.Read)
+	}
+	addTakenKeys(keys, storage.OrderKey(c.SellAsset, c.BuyAsset, c.OrderID))
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (c *CancelOrder) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, c, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	order, exists, err := storage.GetOrder(ctx, mu, c.SellAsset, c.BuyAsset, c.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, takenOr(ctx, mu, storage.OrderKey(c.SellAsset, c.BuyAsset, c.OrderID), ErrOrderNotFound)
+	}
+	if order.Maker != actor {
+		return nil, ErrNotOrderMaker
+	}
+	// Cancellations skip transfer hooks, so a lock set while the order was
+	// open cannot strand the escrow.
+	balance, err := storage.AddLeg(ctx, mu, actor, storage.SwapLeg{Asset: c.SellAsset, Amount: order.Remaining})
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.DeleteOrder(ctx, mu, c.SellAsset, c.BuyAsset, c.OrderID); err != nil {
+		return nil, err
+	}
+	return &CancelOrderResult{
+		Refunded:      order.Remaining,
+		SellerBalance: balance,
+	}, nil
+}
+
+func (*CancelOrder) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.CancelOrderID, OrderComputeUnits)
+}
+
+func (*CancelOrder) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*CancelOrderResult)(nil)
+
+type CancelOrderResult struct {
+	Refunded      uint64 `serialize:"true" json:"refunded"`
+	SellerBalance uint64 `serialize:"true" json:"seller_balance"`
+}
+
+func (*CancelOrderResult) GetTypeID() uint8 {
+	return mconsts.CancelOrderID
+}
+
+// OrderPayment returns what buying [amount] from [order] costs, rounded up.
+// [amount] must not exceed [order.SellAmount].
+func OrderPayment(order *storage.Order, amount uint64) uint64 {
+	// [amount] <= SellAmount keeps the quotient within 64 bits.
+	hi, lo := bits.Mul64(amount, order.BuyAmount)
+	payment, rem := bits.Div64(hi, lo, order.SellAmount)
+	if rem > 0 {
+		payment++
+	}
+	return payment
+}
+
+// getOrder returns the order [orderID] of the pair, checking that it was
+// made by [maker].
+func getOrder(
+	ctx context.Context,
+	im state.Immutable,
+	sellAsset ids.ID,
+	buyAsset ids.ID,
+	orderID ids.ID,
+	maker codec.Address,
+) (*storage.Order, error) {
+	order, exists, err := storage.GetOrder(ctx, im, sellAsset, buyAsset, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, takenOr(ctx, im, storage.OrderKey(sellAsset, buyAsset, orderID), ErrOrderNotFound)
+	}
+	if order.Maker != maker {
+		return nil, ErrOrderMismatch
+	}
+	return order, nil
+}
+
+// addHookKey declares the transfer hook of [asset] if it is fungible.
+func addHookKey(keys state.Keys, payer codec.Address, asset ids.ID) {
+	if asset != storage.NativeAsset {
+		addTransferHookKeys(keys, payer, asset)
+	}
+}
+
+// runHook runs the transfer hook of [asset] if it is fungible, charging
+// [payer] for it.
+func runHook(ctx context.Context, r chain.Rules, mu state.Mutable, payer codec.Address, asset ids.ID) error {
+	if asset == storage.NativeAsset {
+		return nil
+	}
+	_, err := runTransferHook(ctx, r, mu, payer, asset)
+	return err
+}
@@ -0,0 +1,192 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestOrderActions(t *testing.T) {
+	maker := codectest.NewRandomAddress()
+	taker := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+	orderID := storage.OrderID(maker, 0)
+	native := storage.NativeAsset
+
+	funded := func() state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetAssetBalance(context.Background(), store, maker, asset, 10))
+		return store
+	}
+	// open has an order from [maker] selling 10 of [asset] for 25 native
+	// tokens, of which 4 are left. [taker] holds 100 native tokens.
+	open := func() state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, store.Insert(ctx, chain.HeightKey(storage.HeightKey()), binary.BigEndian.AppendUint64(nil, 9)))
+		require.NoError(t, storage.SetOrder(ctx, store, asset, native, orderID, &storage.Order{
+			Maker:      maker,
+			SellAmount: 10,
+			BuyAmount:  25,
+			Remaining:  4,
+		}))
+		require.NoError(t, storage.SetBalance(ctx, store, taker, 100))
+		return store
+	}
+	createAction := &CreateOrder{
+		Sequence:   0,
+		SellAsset:  asset,
+		SellAmount: 10,
+		BuyAsset:   native,
+		BuyAmount:  25,
+	}
+	fillAction := func(amount uint64) *FillOrder {
+		return &FillOrder{
+			OrderID:   orderID,
+			SellAsset: asset,
+			BuyAsset:  native,
+			Maker:     maker,
+			Amount:    amount,
+		}
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "SameAsset",
+			Actor: maker,
+			Action: &CreateOrder{
+				SellAsset:  asset,
+				SellAmount: 10,
+				BuyAsset:   asset,
+				BuyAmount:  25,
+			},
+			State:       funded(),
+			ExpectedErr: ErrSameAsset,
+		},
+		{
+			Name:   "StaleSequence",
+			Actor:  maker,
+			Action: createAction,
+			State: func() state.Mutable {
+				store := funded()
+				_, err := storage.NextSequence(context.Background(), store, storage.OrderSequenceKey(maker))
+				require.NoError(t, err)
+				return store
+			}(),
+			ExpectedErr: ErrOrderSequence,
+		},
+		{
+			Name:   "Create",
+			Actor:  maker,
+			Action: createAction,
+			State:  funded(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				order, exists, err := storage.GetOrder(ctx, store, asset, native, orderID)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, uint64(10), order.Remaining)
+				balance, err := storage.GetAssetBalance(ctx, store, maker, asset)
+				require.NoError(t, err)
+				require.Zero(t, balance)
+				next, err := storage.PeekSequence(ctx, store, storage.OrderSequenceKey(maker))
+				require.NoError(t, err)
+				require.Equal(t, uint64(1), next)
+			},
+			ExpectedOutputs: &CreateOrderResult{OrderID: orderID, SellerBalance: 0},
+		},
+		{
+			Name:        "FillExceedsOrder",
+			Actor:       taker,
+			Action:      fillAction(5),
+			State:       open(),
+			ExpectedErr: ErrFillExceedsOrder,
+		},
+		{
+			Name:  "WrongMaker",
+			Actor: taker,
+			Action: &FillOrder{
+				OrderID:   orderID,
+				SellAsset: asset,
+				BuyAsset:  native,
+				Maker:     taker,
+				Amount:    1,
+			},
+			State:       open(),
+			ExpectedErr: ErrOrderMismatch,
+		},
+		{
+			// 3 units at 2.5 each cost 7.5, which rounds up to 8.
+			Name:   "PartialFill",
+			Actor:  taker,
+			Action: fillAction(3),
+			State:  open(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, maker)
+				require.NoError(t, err)
+				require.Equal(t, uint64(8), balance)
+				balance, err = storage.GetAssetBalance(ctx, store, taker, asset)
+				require.NoError(t, err)
+				require.Equal(t, uint64(3), balance)
+				order, _, err := storage.GetOrder(ctx, store, asset, native, orderID)
+				require.NoError(t, err)
+				require.Equal(t, uint64(1), order.Remaining)
+			},
+			ExpectedOutputs: &FillOrderResult{
+				Maker:     maker,
+				Bought:    3,
+				Paid:      8,
+				Remaining: 1,
+			},
+		},
+		{
+			Name:   "FullFill",
+			Actor:  taker,
+			Action: fillAction(4),
+			State:  open(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				_, exists, err := storage.GetOrder(ctx, store, asset, native, orderID)
+				require.NoError(t, err)
+				require.False(t, exists)
+			},
+			ExpectedOutputs: &FillOrderResult{
+				Maker:     maker,
+				Bought:    4,
+				Paid:      10,
+				Remaining: 0,
+			},
+		},
+		{
+			Name:        "CancelNotMaker",
+			Actor:       taker,
+			Action:      &CancelOrder{OrderID: orderID, SellAsset: asset, BuyAsset: native},
+			State:       open(),
+			ExpectedErr: ErrNotOrderMaker,
+		},
+		{
+			Name:   "Cancel",
+			Actor:  maker,
+			Action: &CancelOrder{OrderID: orderID, SellAsset: asset, BuyAsset: native},
+			State:  open(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				_, exists, err := storage.GetOrder(ctx, store, asset, native, orderID)
+				require.NoError(t, err)
+				require.False(t, exists)
+			},
+			ExpectedOutputs: &CancelOrderResult{Refunded: 4, SellerBalance: 4},
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -0,0 +1,203 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+// Permit transfers verify an ed25519 signature on top of a transfer.
+const PermitTransferComputeUnits = 5
+
+var (
+	ErrInvalidPermitSignature = errors.New("invalid permit signature")
+	ErrPermitExpired          = errors.New("permit deadline has passed")
+	ErrPermitUsed             = errors.New("permit already used")
+
+	_ chain.Action = (*PermitTransfer)(nil)
+	_ codec.Typed  = (*PermitTransferResult)(nil)
+)
+
+// Permit is signed off-chain by the owner of [Asset] to let anyone move it
+// to [Recipient] until [Deadline]. Each [Nonce] can be used once per owner,
+// in any order.
+type Permit struct {
+	Asset     ids.ID        `serialize:"true" json:"asset"`
+	Recipient codec.Address `serialize:"true" json:"recipient"`
+	// Deadline is the last timestamp, in milliseconds, at which the permit
+	// can be used.
+	Deadline int64  `serialize:"true" json:"deadline"`
+	Nonce    uint64 `serialize:"true" json:"nonce"`
+}
+
+// Digest returns the bytes an owner signs to issue [p] on [chainID]. The
+// action type separates permits from vouchers signed with the same key.
+func (p *Permit) Digest(chainID ids.ID) ([]byte, error) {
+	b, err := chain.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	digest := make([]byte, 0, ids.IDLen+1+len(b))
+	digest = append(digest, chainID[:]...)
+	digest = append(digest, mconsts.PermitTransferID)
+	return append(digest, b...), nil
+}
+
+// PermitTransfer moves an asset on behalf of its owner, who authorized it
+// with a signed [Permit]. Marketplaces send it to settle listings without
+// holding the asset. The actor only pays the fee.
+type PermitTransfer struct {
+	Permit Permit `serialize:"true" json:"permit"`
+
+	// Owner is the ed25519 public key that signed [Permit]. The asset must
+	// be held by the address derived from it.
+	Owner []byte `serialize:"true" json:"owner"`
+
+	// Signature over [Permit.Digest].
+	Signature []byte `serialize:"true" json:"signature"`
+}
+
+func (*PermitTransfer) GetTypeID() uint8 {
+	return mconsts.PermitTransferID
+}
+
+func (p *PermitTransfer) owner() (ed25519.PublicKey, codec.Address) {
+	var pk ed25519.PublicKey
+	copy(pk[:], p.Owner)
+	return pk, auth.NewED25519Address(pk)
+}
+
+// OwnerAddress is the address derived from [Owner], which must hold the
+// asset.
+func (p *PermitTransfer) OwnerAddress() codec.Address {
+	_, owner := p.owner()
+	return owner
+}
+
+func (p *PermitTransfer) StateKeys(actor codec.Address) state.Keys {
+	_, owner := p.owner()
+	keys := state.Keys{
+		string(storage.AssetKey(p.Permit.Asset)):             state.Read | state.Write,
+		string(storage.OwnedAssetKey(owner, p.Permit.Asset)): state.Write,
+		string(storage.PermitKey(owner, p.Permit.Nonce)):     state.All,
+	}
+	addTransferHookKeys(keys, actor, p.Permit.Asset)
+	keys.Add(string(storage.OwnedAssetKey(p.Permit.Recipient, p.Permit.Asset)), state.Allocate|state.Write)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (p *PermitTransfer) Execute(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, p, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if p.Permit.Deadline < timestamp {
+		return nil, ErrPermitExpired
+	}
+	if len(p.Owner) != ed25519.PublicKeyLen || len(p.Signature) != ed25519.SignatureLen {
+		return nil, ErrInvalidPermitSignature
+	}
+	pk, owner := p.owner()
+	digest, err := p.Permit.Digest(rules.GetChainID())
+	if err != nil {
+		return nil, err
+	}
+	var sig ed25519.Signature
+	copy(sig[:], p.Signature)
+	if !ed25519.Verify(digest, pk, sig) {
+		return nil, ErrInvalidPermitSignature
+	}
+	used, err := storage.IsPermitUsed(ctx, mu, owner, p.Permit.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if used {
+		return nil, ErrPermitUsed
+	}
+
+	oldOwner, err := storage.GetAssetOwner(ctx, mu, p.Permit.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if oldOwner != owner {
+		return nil, ErrAssetNotOwned
+	}
+	control, err := storage.GetAssetControl(ctx, mu, p.Permit.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if control.Frozen {
+		return nil, ErrAssetFrozen
+	}
+	if control.Soulbound {
+		return nil, ErrAssetSoulbound
+	}
+	if control.Expired(timestamp) {
+		return nil, ErrAssetExpired
+	}
+	notify, err := runTransferHook(ctx, rules, mu, actor, p.Permit.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.UsePermit(ctx, mu, owner, p.Permit.Nonce); err != nil {
+		return nil, err
+	}
+	if err := storage.ChangeAssetOwner(ctx, mu, p.Permit.Asset, p.Permit.Recipient); err != nil {
+		return nil, err
+	}
+	return &PermitTransferResult{
+		OldOwner: owner,
+		NewOwner: p.Permit.Recipient,
+		Notify:   notify,
+	}, nil
+}
+
+func (*PermitTransfer) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.PermitTransferID, PermitTransferComputeUnits)
+}
+
+func (*PermitTransfer) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (p *PermitTransfer) EmitEvents(_ codec.Address, output codec.Typed, e Emitter) {
+	if r, ok := output.(*PermitTransferResult); ok {
+		e.Emit(&OwnershipEvent{Asset: p.Permit.Asset, From: r.OldOwner, To: r.NewOwner})
+	}
+}
+
+type PermitTransferResult struct {
+	OldOwner codec.Address `serialize:"true" json:"old_owner"`
+	NewOwner codec.Address `serialize:"true" json:"new_owner"`
+	// Notify is the target of the asset's HookNotify hook, if it has one.
+	Notify codec.Address `serialize:"true" json:"notify"`
+}
+
+func (*PermitTransferResult) GetTypeID() uint8 {
+	return mconsts.PermitTransferID
+}
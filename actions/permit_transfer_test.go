@@ -0,0 +1,174 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestPermitTransferAction(t *testing.T) {
+	rules := genesis.NewDefaultRules()
+	rules.ChainID = ids.GenerateTestID()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	owner := auth.NewED25519Address(pub)
+	marketplace := codectest.NewRandomAddress()
+	buyer := codectest.NewRandomAddress()
+
+	permit := Permit{
+		Asset:     ids.GenerateTestID(),
+		Recipient: buyer,
+		Deadline:  100,
+		Nonce:     7,
+	}
+	signed := func(p Permit, chainID ids.ID) *PermitTransfer {
+		digest, err := p.Digest(chainID)
+		require.NoError(t, err)
+		sig := ed25519.Sign(digest, priv)
+		return &PermitTransfer{Permit: p, Owner: pub[:], Signature: sig[:]}
+	}
+	held := func() state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.CreateAsset(context.Background(), store, permit.Asset, owner))
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:      "Transfer",
+			Actor:     marketplace,
+			Action:    signed(permit, rules.ChainID),
+			Rules:     rules,
+			State:     held(),
+			Timestamp: 100,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				o, err := storage.GetAssetOwner(ctx, store, permit.Asset)
+				require.NoError(t, err)
+				require.Equal(t, buyer, o)
+				used, err := storage.IsPermitUsed(ctx, store, owner, permit.Nonce)
+				require.NoError(t, err)
+				require.True(t, used)
+			},
+			ExpectedOutputs: &PermitTransferResult{OldOwner: owner, NewOwner: buyer},
+		},
+		{
+			// The relayer pays for the hook run.
+			Name:      "NotifiedTransfer",
+			Actor:     marketplace,
+			Action:    signed(permit, rules.ChainID),
+			Rules:     rules,
+			Timestamp: 100,
+			State: func() state.Mutable {
+				ctx := context.Background()
+				store := held()
+				require.NoError(t, storage.SetTransferHook(ctx, store, permit.Asset, &storage.TransferHook{
+					Registrar: owner,
+					Kind:      storage.HookNotify,
+					Target:    owner,
+				}))
+				require.NoError(t, storage.SetBalance(ctx, store, marketplace, TransferHookNotifyFee))
+				return store
+			}(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, marketplace)
+				require.NoError(t, err)
+				require.Zero(t, balance)
+			},
+			ExpectedOutputs: &PermitTransferResult{OldOwner: owner, NewOwner: buyer, Notify: owner},
+		},
+		{
+			Name:        "PastDeadline",
+			Actor:       marketplace,
+			Action:      signed(permit, rules.ChainID),
+			Rules:       rules,
+			State:       held(),
+			Timestamp:   101,
+			ExpectedErr: ErrPermitExpired,
+		},
+		{
+			Name:        "WrongChain",
+			Actor:       marketplace,
+			Action:      signed(permit, ids.GenerateTestID()),
+			Rules:       rules,
+			State:       held(),
+			ExpectedErr: ErrInvalidPermitSignature,
+		},
+		{
+			Name:  "AlteredRecipient",
+			Actor: marketplace,
+			Action: func() *PermitTransfer {
+				p := signed(permit, rules.ChainID)
+				p.Permit.Recipient = marketplace
+				return p
+			}(),
+			Rules:       rules,
+			State:       held(),
+			ExpectedErr: ErrInvalidPermitSignature,
+		},
+		{
+			Name:   "Used",
+			Actor:  marketplace,
+			Action: signed(permit, rules.ChainID),
+			Rules:  rules,
+			State: func() state.Mutable {
+				store := held()
+				require.NoError(t, storage.UsePermit(context.Background(), store, owner, permit.Nonce))
+				return store
+			}(),
+			ExpectedErr: ErrPermitUsed,
+		},
+		{
+			Name:   "NoLongerHeld",
+			Actor:  marketplace,
+			Action: signed(permit, rules.ChainID),
+			Rules:  rules,
+			State: func() state.Mutable {
+				store := held()
+				require.NoError(t, storage.ChangeAssetOwner(context.Background(), store, permit.Asset, marketplace))
+				return store
+			}(),
+			ExpectedErr: ErrAssetNotOwned,
+		},
+		{
+			Name:   "Frozen",
+			Actor:  marketplace,
+			Action: signed(permit, rules.ChainID),
+			Rules:  rules,
+			State: func() state.Mutable {
+				store := held()
+				require.NoError(t, storage.SetAssetFrozen(context.Background(), store, permit.Asset, true))
+				return store
+			}(),
+			ExpectedErr: ErrAssetFrozen,
+		},
+		{
+			Name:   "Soulbound",
+			Actor:  marketplace,
+			Action: signed(permit, rules.ChainID),
+			Rules:  rules,
+			State: func() state.Mutable {
+				store := chaintest.NewInMemoryStore()
+				require.NoError(t, storage.CreateSoulboundAsset(context.Background(), store, permit.Asset, owner))
+				return store
+			}(),
+			ExpectedErr: ErrAssetSoulbound,
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -0,0 +1,596 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const (
+	PoolComputeUnits = 1
+
+	// PoolFeeBasisPoints of every swap input stay in the pool, paying its
+	// liquidity providers.
+	PoolFeeBasisPoints = 30
+
+	// MinimumLiquidity shares are issued to nobody when a pool is created,
+	// so the pool can never be drained and a share never becomes too
+	// expensive to mint.
+	MinimumLiquidity = 1_000
+)
+
+var (
+	ErrUnsortedPair          = errors.New("pool assets must be distinct and in ascending order")
+	ErrPoolExists            = errors.New("pool already exists")
+	ErrPoolNotFound          = errors.New("pool not found")
+	ErrInsufficientLiquidity = errors.New("insufficient initial liquidity")
+	ErrBelowMinimum          = errors.New("result is below the requested minimum")
+
+	_ chain.Action = (*CreatePool)(nil)
+	_ chain.Action = (*AddLiquidity)(nil)
+	_ chain.Action = (*RemoveLiquidity)(nil)
+	_ chain.Action = (*Swap)(nil)
+)
+
+// CreatePool opens the pool of [AssetA] and [AssetB] with the actor's
+// deposit, which sets the initial price. [AssetA] must sort before
+// [AssetB]; use [storage.NativeAsset] for the native token.
+type CreatePool struct {
+	AssetA  ids.ID `serialize:"true" json:"asset_a"`
+	AssetB  ids.ID `serialize:"true" json:"asset_b"`
+	AmountA uint64 `serialize:"true" json:"amount_a"`
+	AmountB uint64 `serialize:"true" json:"amount_b"`
+}
+
+func (*CreatePool) GetTypeID() uint8 {
+	return mconsts.CreatePoolID
+}
+
+func (c *CreatePool) StateKeys(actor codec.Address) state.Keys {
+	keys := state.Keys{
+		string(storage.PoolKey(c.AssetA, c.AssetB)):              state.All,
+		string(storage.PoolSharesKey(c.AssetA, c.AssetB, actor)): state.All,
+	}
+	addDepositKeys(keys, actor, c.AssetA, c.AssetB)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (c *CreatePool) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, c, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if c.AmountA == 0 || c.AmountB == 0 {
+		return nil, ErrOutputValueZero
+	}
+	if !storage.SortedPair(c.AssetA, c.AssetB) {
+		return nil, ErrUnsortedPair
+	}
+	_, exists, err := storage.GetPool(ctx, mu, c.AssetA, c.AssetB)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrPoolExists
+	}
+	shares := InitialShares(c.AmountA, c.AmountB)
+	if shares <= MinimumLiquidity {
+		return nil, ErrInsufficientLiquidity
+	}
+	if err := deposit(ctx, r, mu, actor, c.AssetA, c.AmountA, c.AssetB, c.AmountB); err != nil {
+		return nil, err
+	}
+	if err := storage.SetPool(ctx, mu, c.AssetA, c.AssetB, &storage.Pool{
+		ReserveA: c.AmountA,
+		ReserveB: c.AmountB,
+		Shares:   shares,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := storage.AddPoolShares(ctx, mu, c.AssetA, c.AssetB, actor, shares-MinimumLiquidity); err != nil {
+		return nil, err
+	}
+	return &CreatePoolResult{Shares: shares - MinimumLiquidity}, nil
+}
+
+func (c *CreatePool) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.CreatePoolID, PoolComputeUnits)
+}
+
+func (*CreatePool) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*CreatePoolResult)(nil)
+
+type CreatePoolResult struct {
+	// Shares are the LP shares issued to the actor.
+	Shares uint64 `serialize:"true" json:"shares"`
+}
+
+func (*CreatePoolResult) GetTypeID() uint8 {
+	return mconsts.CreatePoolID
+}
+
+// AddLiquidity deposits up to [MaxAmountA] and [MaxAmountB] into a pool at
+// its current price, in exchange for LP shares. Only the amounts matching
+// the price are taken.
+type AddLiquidity struct {
+	AssetA     ids.ID `serialize:"true" json:"asset_a"`
+	AssetB     ids.ID `serialize:"true" json:"asset_b"`
+	MaxAmountA uint64 `serialize:"true" json:"max_amount_a"`
+	MaxAmountB uint64 `serialize:"true" json:"max_amount_b"`
+	// MinShares fails the deposit if the price moved against the actor.
+	MinShares uint64 `serialize:"true" json:"min_shares"`
+}
+
+func (*AddLiquidity) GetTypeID() uint8 {
+	return mconsts.AddLiquidityID
+}
+
+func (a *AddLiquidity) StateKeys(actor codec.Address) state.Keys {
+	keys := state.Keys{
+		string(storage.PoolKey(a.AssetA, a.AssetB)):              state.Read | state.Write,
+		string(storage.PoolSharesKey(a.AssetA, a.AssetB, actor)): state.All,
+	}
+	addDepositKeys(keys, actor, a.AssetA, a.AssetB)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (a *AddLiquidity) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, a, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	pool, err := getPool(ctx, mu, a.AssetA, a.AssetB)
+	if err != nil {
+		return nil, err
+	}
+	sharesA, err := mulDiv(a.MaxAmountA, pool.Shares, pool.ReserveA)
+	if err != nil {
+		return nil, err
+	}
+	sharesB, err := mulDiv(a.MaxAmountB, pool.Shares, pool.ReserveB)
+	if err != nil {
+		return nil, err
+	}
+	shares := min(sharesA, sharesB)
+	if shares == 0 {
+		return nil, ErrOutputValueZero
+	}
+	if shares < a.MinShares {
+		return nil, ErrBelowMinimum
+	}
+	// Rounding up keeps the value of existing shares from being diluted.
+	// The amounts cannot exceed the maximums [shares] was derived from.
+	amountA := mulDivUp(shares, pool.ReserveA, pool.Shares)
+	amountB := mulDivUp(shares, pool.ReserveB, pool.Shares)
+	if pool.ReserveA, err = smath.Add(pool.ReserveA, amountA); err != nil {
+		return nil, err
+	}
+	if pool.ReserveB, err = smath.Add(pool.ReserveB, amountB); err != nil {
+		return nil, err
+	}
+	if pool.Shares, err = smath.Add(pool.Shares, shares); err != nil {
+		return nil, err
+	}
+	if err := deposit(ctx, r, mu, actor, a.AssetA, amountA, a.AssetB, amountB); err != nil {
+		return nil, err
+	}
+	if err := storage.SetPool(ctx, mu, a.AssetA, a.AssetB, pool); err != nil {
+		return nil, err
+	}
+	total, err := storage.AddPoolShares(ctx, mu, a.AssetA, a.AssetB, actor, shares)
+	if err != nil {
+		return nil, err
+	}
+	return &AddLiquidityResult{
+		AmountA:     amountA,
+		AmountB:     amountB,
+		Shares:      shares,
+		TotalShares: total,
+	}, nil
+}
+
+func (a *AddLiquidity) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.AddLiquidityID, PoolComputeUnits)
+}
+
+func (*AddLiquidity) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*AddLiquidityResult)(nil)
+
+type AddLiquidityResult struct {
+	// AmountA and AmountB are what was actually deposited.
+	AmountA uint64 `serialize:"true" json:"amount_a"`
+	AmountB uint64 `serialize:"true" json:"amount_b"`
+	Shares  uint64 `serialize:"true" json:"shares"`
+	// TotalShares is the actor's share count after the deposit.
+	TotalShares uint64 `serialize:"true" json:"total_shares"`
+}
+
+func (*AddLiquidityResult) GetTypeID() uint8 {
+	return mconsts.AddLiquidityID
+}
+
+// RemoveLiquidity redeems [Shares] of the actor's LP shares for their part of
+// both reserves.
+type RemoveLiquidity struct {
+	AssetA ids.ID `serialize:"true" json:"asset_a"`
+	AssetB ids.ID `serialize:"true" json:"asset_b"`
+	Shares uint64 `serialize:"true" json:"shares"`
+	// MinAmountA and MinAmountB fail the withdrawal if the price moved
+	// against the actor.
+	MinAmountA uint64 `serialize:"true" json:"min_amount_a"`
+	MinAmountB uint64 `serialize:"true" json:"min_amount_b"`
+}
+
+func (*RemoveLiquidity) GetTypeID() uint8 {
+	return mconsts.RemoveLiquidityID
+}
+
+func (r *RemoveLiquidity) StateKeys(actor codec.Address) state.Keys {
+	keys := state.Keys{
+		string(storage.PoolKey(r.AssetA, r.AssetB)):                            state.Read | state.Write,
+		string(storage.PoolSharesKey(r.AssetA, r.AssetB, actor)):               state.Read | state.Write,
+		string(storage.LegBalanceKey(actor, storage.SwapLeg{Asset: r.AssetA})): state.All,
+		string(storage.LegBalanceKey(actor, storage.SwapLeg{Asset: r.AssetB})): state.All,
+		string(storage.ActiveProposalKey()):                                    state.Read,
+	}
+	addBlockKeys(keys, r.AssetA, actor)
+	addBlockKeys(keys, r.AssetB, actor)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (r *RemoveLiquidity) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, r, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if r.Shares == 0 {
+		return nil, ErrOutputValueZero
+	}
+	if err := checkNotBlocked(ctx, mu, r.AssetA, actor); err != nil {
+		return nil, err
+	}
+	if err := checkNotBlocked(ctx, mu, r.AssetB, actor); err != nil {
+		return nil, err
+	}
+	pool, err := getPool(ctx, mu, r.AssetA, r.AssetB)
+	if err != nil {
+		return nil, err
+	}
+	remaining, err := storage.SubPoolShares(ctx, mu, r.AssetA, r.AssetB, actor, r.Shares)
+	if err != nil {
+		return nil, err
+	}
+	// The provider's shares never exceed the pool's, so neither quotient
+	// overflows, and the locked shares keep both reserves above zero.
+	amountA, _ := mulDiv(r.Shares, pool.ReserveA, pool.Shares)
+	amountB, _ := mulDiv(r.Shares, pool.ReserveB, pool.Shares)
+	if amountA < r.MinAmountA || amountB < r.MinAmountB {
+		return nil, ErrBelowMinimum
+	}
+	pool.ReserveA -= amountA
+	pool.ReserveB -= amountB
+	pool.Shares -= r.Shares
+	if err := storage.SetPool(ctx, mu, r.AssetA, r.AssetB, pool); err != nil {
+		return nil, err
+	}
+	// Withdrawals skip transfer hooks, like order cancellations, so a lock
+	// set after the deposit cannot strand the liquidity.
+	for _, leg := range []storage.SwapLeg{{Asset: r.AssetA, Amount: amountA}, {Asset: r.AssetB, Amount: amountB}} {
+		if leg.Amount == 0 {
+			continue
+		}
+		if _, err := storage.AddLeg(ctx, mu, actor, leg); err != nil {
+			return nil, err
+		}
+	}
+	return &RemoveLiquidityResult{
+		AmountA:         amountA,
+		AmountB:         amountB,
+		RemainingShares: remaining,
+	}, nil
+}
+
+func (*RemoveLiquidity) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.RemoveLiquidityID, PoolComputeUnits)
+}
+
+func (*RemoveLiquidity) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*RemoveLiquidityResult)(nil)
+
+type RemoveLiquidityResult struct {
+	AmountA         uint64 `serialize:"true" json:"amount_a"`
+	AmountB         uint64 `serialize:"true" json:"amount_b"`
+	RemainingShares uint64 `serialize:"true" json:"remaining_shares"`
+}
+
+func (*RemoveLiquidityResult) GetTypeID() uint8 {
+	return mconsts.RemoveLiquidityID
+}
+
+// Swap sells [AmountIn] of [AssetIn] to the pool of the pair for [AssetOut],
+// at the constant-product price after the [PoolFeeBasisPoints] fee. Use
+// [ProposeSwap] to trade with a specific counterparty instead.
+type Swap struct {
+	AssetIn  ids.ID `serialize:"true" json:"asset_in"`
+	AssetOut ids.ID `serialize:"true" json:"asset_out"`
+	AmountIn uint64 `serialize:"true" json:"amount_in"`
+	// MinAmountOut fails the swap if the price moved against the actor.
+	MinAmountOut uint64 `serialize:"true" json:"min_amount_out"`
+}
+
+func (*Swap) GetTypeID() uint8 {
+	return mconsts.SwapID
+}
+
+func (s *Swap) StateKeys(actor codec.Address) state.Keys {
+	assetA, assetB := s.pair()
+	keys := state.Keys{
+		string(storage.PoolKey(assetA, assetB)):                                  state.Read | state.Write,
+		string(storage.LegBalanceKey(actor, storage.SwapLeg{Asset: s.AssetIn})):  state.Read | state.Write,
+		string(storage.LegBalanceKey(actor, storage.SwapLeg{Asset: s.AssetOut})): state.All,
+		string(storage.ActiveProposalKey()):                                      state.Read,
+	}
+	addHookKey(keys, actor, s.AssetIn)
+	addHookKey(keys, actor, s.AssetOut)
+	addBlockKeys(keys, s.AssetIn, actor)
+	addBlockKeys(keys, s.AssetOut, actor)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (s *Swap) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, s, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if s.AmountIn == 0 {
+		return nil, ErrOutputValueZero
+	}
+	if s.AssetIn == s.AssetOut {
+		return nil, ErrSameAsset
+	}
+	if err := checkNotBlocked(ctx, mu, s.AssetIn, actor); err != nil {
+		return nil, err
+	}
+	if err := checkNotBlocked(ctx, mu, s.AssetOut, actor); err != nil {
+		return nil, err
+	}
+	assetA, assetB := s.pair()
+	pool, err := getPool(ctx, mu, assetA, assetB)
+	if err != nil {
+		return nil, err
+	}
+	reserveIn, reserveOut := &pool.ReserveA, &pool.ReserveB
+	if s.AssetIn != assetA {
+		reserveIn, reserveOut = reserveOut, reserveIn
+	}
+	amountOut, err := SwapOutput(*reserveIn, *reserveOut, s.AmountIn)
+	if err != nil {
+		return nil, err
+	}
+	if amountOut == 0 {
+		return nil, ErrOutputValueZero
+	}
+	if amountOut < s.MinAmountOut {
+		return nil, ErrBelowMinimum
+	}
+	if *reserveIn, err = smath.Add(*reserveIn, s.AmountIn); err != nil {
+		return nil, err
+	}
+	*reserveOut -= amountOut
+	if err := runHook(ctx, r, mu, actor, s.AssetIn); err != nil {
+		return nil, err
+	}
+	if _, err := storage.SubLeg(ctx, mu, actor, storage.SwapLeg{Asset: s.AssetIn, Amount: s.AmountIn}); err != nil {
+		return nil, err
+	}
+	if err := runHook(ctx, r, mu, actor, s.AssetOut); err != nil {
+		return nil, err
+	}
+	if _, err := storage.AddLeg(ctx, mu, actor, storage.SwapLeg{Asset: s.AssetOut, Amount: amountOut}); err != nil {
+		return nil, err
+	}
+	if err := storage.SetPool(ctx, mu, assetA, assetB, pool); err != nil {
+		return nil, err
+	}
+	return &SwapResult{
+		AmountOut:  amountOut,
+		ReserveIn:  *reserveIn,
+		ReserveOut: *reserveOut,
+	}, nil
+}
+
+// pair returns the assets of the swap in pool order.
+func (s *Swap) pair() (ids.ID, ids.ID) {
+	if storage.SortedPair(s.AssetIn, s.AssetOut) {
+		return s.AssetIn, s.AssetOut
+	}
+	return s.AssetOut, s.AssetIn
+}
+
+func (s *Swap) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.SwapID, PoolComputeUnits)
+}
+
+func (*Swap) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*SwapResult)(nil)
+
+type SwapResult struct {
+	AmountOut uint64 `serialize:"true" json:"amount_out"`
+	// ReserveIn and ReserveOut are the pool reserves after the swap.
+	ReserveIn  uint64 `serialize:"true" json:"reserve_in"`
+	ReserveOut uint64 `serialize:"true" json:"reserve_out"`
+}
+
+func (*SwapResult) GetTypeID() uint8 {
+	return mconsts.SwapID
+}
+
+// InitialShares returns the shares a pool is created with: the geometric
+// mean of the deposit, so the share value does not depend on the price.
+func InitialShares(amountA uint64, amountB uint64) uint64 {
+	product := new(big.Int).Mul(new(big.Int).SetUint64(amountA), new(big.Int).SetUint64(amountB))
+	// The square root of a 128-bit product fits in 64 bits.
+	return product.Sqrt(product).Uint64()
+}
+
+// SwapOutput returns what selling [amountIn] to reserves of [reserveIn] and
+// [reserveOut] pays, after the fee. Reserves must be non-zero.
+func SwapOutput(reserveIn uint64, reserveOut uint64, amountIn uint64) (uint64, error) {
+	// The fee is taken from the input before pricing, so it stays in the
+	// pool.
+	effective, err := mulDiv(amountIn, 10_000-PoolFeeBasisPoints, 10_000)
+	if err != nil {
+		return 0, err
+	}
+	denominator, err := smath.Add(reserveIn, effective)
+	if err != nil {
+		return 0, err
+	}
+	// out = reserveOut * effective / (reserveIn + effective), which is
+	// always less than [reserveOut].
+	return mulDiv(effective, reserveOut, denominator)
+}
+
+// mulDiv returns x*y/z rounded down, computed in 128 bits.
+func mulDiv(x uint64, y uint64, z uint64) (uint64, error) {
+	hi, lo := bits.Mul64(x, y)
+	if hi >= z {
+		return 0, smath.ErrOverflow
+	}
+	q, _ := bits.Div64(hi, lo, z)
+	return q, nil
+}
+
+// mulDivUp is [mulDiv] rounded up. The caller must ensure the result fits.
+func mulDivUp(x uint64, y uint64, z uint64) uint64 {
+	hi, lo := bits.Mul64(x, y)
+	q, rem := bits.Div64(hi, lo, z)
+	if rem > 0 {
+		q++
+	}
+	return q
+}
+
+// getPool returns the pool of the pair, failing if there is none.
+func getPool(ctx context.Context, im state.Immutable, assetA ids.ID, assetB ids.ID) (*storage.Pool, error) {
+	pool, exists, err := storage.GetPool(ctx, im, assetA, assetB)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrPoolNotFound
+	}
+	return pool, nil
+}
+
+// addDepositKeys declares the balances and hooks a deposit of both assets
+// touches.
+func addDepositKeys(keys state.Keys, actor codec.Address, assetA ids.ID, assetB ids.ID) {
+	for _, asset := range []ids.ID{assetA, assetB} {
+		keys.Add(string(storage.LegBalanceKey(actor, storage.SwapLeg{Asset: asset})), state.Read|state.Write)
+		addHookKey(keys, actor, asset)
+		addBlockKeys(keys, asset, actor)
+	}
+	keys.Add(string(storage.ActiveProposalKey()), state.Read)
+}
+
+// deposit moves both amounts from [actor] into a pool's reserves, which are
+// held by the pool record.
+func deposit(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	actor codec.Address,
+	assetA ids.ID,
+	amountA uint64,
+	assetB ids.ID,
+	amountB uint64,
+) error {
+	legs := []storage.SwapLeg{{Asset: assetA, Amount: amountA}, {Asset: assetB, Amount: amountB}}
+	if err := checkLegsNotBlocked(ctx, mu, legs, actor); err != nil {
+		return err
+	}
+	if err := runLegHooks(ctx, r, mu, actor, legs); err != nil {
+		return err
+	}
+	for _, leg := range legs {
+		if _, err := storage.SubLeg(ctx, mu, actor, leg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestPoolActions(t *testing.T) {
+	provider := codectest.NewRandomAddress()
+	newcomer := codectest.NewRandomAddress()
+	// The native asset sorts first, so it is always asset A.
+	native := storage.NativeAsset
+	asset := ids.GenerateTestID()
+
+	// funded holds 1M native tokens and 4M of [asset] for [provider].
+	funded := func() state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(ctx, store, provider, 1_000_000))
+		require.NoError(t, storage.SetAssetBalance(ctx, store, provider, asset, 4_000_000))
+		return store
+	}
+	// open is [funded] with a pool at a price of 4 [asset] per native token,
+	// in which [provider] holds 2,000 shares.
+	open := func() state.Mutable {
+		ctx := context.Background()
+		store := funded()
+		require.NoError(t, storage.SetPool(ctx, store, native, asset, &storage.Pool{
+			ReserveA: 1_000_000,
+			ReserveB: 4_000_000,
+			Shares:   2_000_000,
+		}))
+		_, err := storage.AddPoolShares(ctx, store, native, asset, provider, 2_000)
+		require.NoError(t, err)
+		return store
+	}
+	// joined is [open] with [newcomer] holding [native] and [asset] of
+	// [amounts], and no shares.
+	joined := func(amounts ...uint64) state.Mutable {
+		ctx := context.Background()
+		store := open()
+		if amounts[0] > 0 {
+			require.NoError(t, storage.SetBalance(ctx, store, newcomer, amounts[0]))
+		}
+		require.NoError(t, storage.SetAssetBalance(ctx, store, newcomer, asset, amounts[1]))
+		return store
+	}
+	createAction := &CreatePool{
+		AssetA:  native,
+		AssetB:  asset,
+		AmountA: 1_000_000,
+		AmountB: 4_000_000,
+	}
+	swapAction := func(minOut uint64) *Swap {
+		return &Swap{
+			AssetIn:      asset,
+			AssetOut:     native,
+			AmountIn:     40_000,
+			MinAmountOut: minOut,
+		}
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "UnsortedPair",
+			Actor: provider,
+			Action: &CreatePool{
+				AssetA:  asset,
+				AssetB:  native,
+				AmountA: 4_000_000,
+				AmountB: 1_000_000,
+			},
+			State:       funded(),
+			ExpectedErr: ErrUnsortedPair,
+		},
+		{
+			Name:  "InsufficientLiquidity",
+			Actor: provider,
+			Action: &CreatePool{
+				AssetA:  native,
+				AssetB:  asset,
+				AmountA: 100,
+				AmountB: 10_000,
+			},
+			State:       funded(),
+			ExpectedErr: ErrInsufficientLiquidity,
+		},
+		{
+			Name:        "PoolExists",
+			Actor:       provider,
+			Action:      createAction,
+			State:       open(),
+			ExpectedErr: ErrPoolExists,
+		},
+		{
+			Name:   "CreatePool",
+			Actor:  provider,
+			Action: createAction,
+			State:  funded(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				pool, exists, err := storage.GetPool(ctx, store, native, asset)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.Pool{ReserveA: 1_000_000, ReserveB: 4_000_000, Shares: 2_000_000}, pool)
+				balance, err := storage.GetBalance(ctx, store, provider)
+				require.NoError(t, err)
+				require.Zero(t, balance)
+			},
+			ExpectedOutputs: &CreatePoolResult{Shares: 2_000_000 - MinimumLiquidity},
+		},
+		{
+			Name:  "AddLiquidityBelowMinimum",
+			Actor: provider,
+			Action: &AddLiquidity{
+				AssetA:     native,
+				AssetB:     asset,
+				MaxAmountA: 1_000,
+				MaxAmountB: 5_000,
+				MinShares:  2_001,
+			},
+			State:       open(),
+			ExpectedErr: ErrBelowMinimum,
+		},
+		{
+			// Only the 4,000 of [asset] matching 1,000 native tokens are
+			// taken.
+			Name:  "AddLiquidity",
+			Actor: provider,
+			Action: &AddLiquidity{
+				AssetA:     native,
+				AssetB:     asset,
+				MaxAmountA: 1_000,
+				MaxAmountB: 5_000,
+				MinShares:  2_000,
+			},
+			State: open(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				pool, _, err := storage.GetPool(ctx, store, native, asset)
+				require.NoError(t, err)
+				require.Equal(t, &storage.Pool{ReserveA: 1_001_000, ReserveB: 4_004_000, Shares: 2_002_000}, pool)
+				balance, err := storage.GetAssetBalance(ctx, store, provider, asset)
+				require.NoError(t, err)
+				require.Equal(t, uint64(3_996_000), balance)
+			},
+			ExpectedOutputs: &AddLiquidityResult{
+				AmountA:     1_000,
+				AmountB:     4_000,
+				Shares:      2_000,
+				TotalShares: 4_000,
+			},
+		},
+		{
+			Name:  "AddLiquidityNewProvider",
+			Actor: newcomer,
+			Action: &AddLiquidity{
+				AssetA:     native,
+				AssetB:     asset,
+				MaxAmountA: 1_000,
+				MaxAmountB: 4_000,
+				MinShares:  2_000,
+			},
+			State: joined(1_000, 4_000),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				shares, err := storage.GetPoolShares(ctx, store, native, asset, newcomer)
+				require.NoError(t, err)
+				require.Equal(t, uint64(2_000), shares)
+			},
+			ExpectedOutputs: &AddLiquidityResult{
+				AmountA:     1_000,
+				AmountB:     4_000,
+				Shares:      2_000,
+				TotalShares: 2_000,
+			},
+		},
+		{
+			Name:  "RemoveMoreThanHeld",
+			Actor: provider,
+			Action: &RemoveLiquidity{
+				AssetA: native,
+				AssetB: asset,
+				Shares: 2_001,
+			},
+			State:       open(),
+			ExpectedErr: storage.ErrInvalidBalance,
+		},
+		{
+			Name:  "RemoveLiquidity",
+			Actor: provider,
+			Action: &RemoveLiquidity{
+				AssetA:     native,
+				AssetB:     asset,
+				Shares:     2_000,
+				MinAmountA: 1_000,
+				MinAmountB: 4_000,
+			},
+			State: open(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				pool, _, err := storage.GetPool(ctx, store, native, asset)
+				require.NoError(t, err)
+				require.Equal(t, &storage.Pool{ReserveA: 999_000, ReserveB: 3_996_000, Shares: 1_998_000}, pool)
+				shares, err := storage.GetPoolShares(ctx, store, native, asset, provider)
+				require.NoError(t, err)
+				require.Zero(t, shares)
+			},
+			ExpectedOutputs: &RemoveLiquidityResult{
+				AmountA:         1_000,
+				AmountB:         4_000,
+				RemainingShares: 0,
+			},
+		},
+		{
+			// The withdrawal opens both balances of [newcomer].
+			Name:  "RemoveLiquidityToNewBalances",
+			Actor: newcomer,
+			Action: &RemoveLiquidity{
+				AssetA: native,
+				AssetB: asset,
+				Shares: 2_000,
+			},
+			State: func() state.Mutable {
+				ctx := context.Background()
+				store := open()
+				_, err := storage.AddPoolShares(ctx, store, native, asset, newcomer, 2_000)
+				require.NoError(t, err)
+				return store
+			}(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetAssetBalance(ctx, store, newcomer, asset)
+				require.NoError(t, err)
+				require.Equal(t, uint64(4_000), balance)
+			},
+			ExpectedOutputs: &RemoveLiquidityResult{
+				AmountA: 1_000,
+				AmountB: 4_000,
+			},
+		},
+		{
+			Name:        "SwapPoolNotFound",
+			Actor:       provider,
+			Action:      swapAction(0),
+			State:       funded(),
+			ExpectedErr: ErrPoolNotFound,
+		},
+		{
+			Name:        "SwapBelowMinimum",
+			Actor:       provider,
+			Action:      swapAction(9_872),
+			State:       open(),
+			ExpectedErr: ErrBelowMinimum,
+		},
+		{
+			// 40,000 less the fee is 39,880, which buys
+			// 1,000,000 * 39,880 / 4,039,880 native tokens.
+			Name:   "Swap",
+			Actor:  provider,
+			Action: swapAction(9_871),
+			State:  open(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				pool, _, err := storage.GetPool(ctx, store, native, asset)
+				require.NoError(t, err)
+				require.Equal(t, &storage.Pool{ReserveA: 990_129, ReserveB: 4_040_000, Shares: 2_000_000}, pool)
+				balance, err := storage.GetBalance(ctx, store, provider)
+				require.NoError(t, err)
+				require.Equal(t, uint64(1_009_871), balance)
+			},
+			ExpectedOutputs: &SwapResult{
+				AmountOut:  9_871,
+				ReserveIn:  4_040_000,
+				ReserveOut: 990_129,
+			},
+		},
+		{
+			// The swap opens the native balance of [newcomer].
+			Name:   "SwapToNewBalance",
+			Actor:  newcomer,
+			Action: swapAction(9_871),
+			State:  joined(0, 40_000),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, newcomer)
+				require.NoError(t, err)
+				require.Equal(t, uint64(9_871), balance)
+			},
+			ExpectedOutputs: &SwapResult{
+				AmountOut:  9_871,
+				ReserveIn:  4_040_000,
+				ReserveOut: 990_129,
+			},
+		},
+	}
+
+	runActionTests(t, tests)
+}
+
+func TestSwapOutput(t *testing.T) {
+	require := require.New(t)
+
+	out, err := SwapOutput(math.MaxUint64/2, math.MaxUint64, math.MaxUint64/2)
+	require.NoError(err)
+	require.Less(out, uint64(math.MaxUint64/2))
+
+	_, err = SwapOutput(math.MaxUint64, 1, math.MaxUint64)
+	require.Error(err)
+
+	require.Equal(uint64(math.MaxUint64), InitialShares(math.MaxUint64, math.MaxUint64))
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const (
+	ReceiptComputeUnits = 1
+
+	// ReceiptName is the name of every receipt asset.
+	ReceiptName = "Receipt"
+)
+
+// addReceiptKeys declares the keys [mintReceipt] writes, if [receipt] is
+// set.
+func addReceiptKeys(keys state.Keys, actor codec.Address, receipt ids.ID) {
+	if receipt == ids.Empty {
+		return
+	}
+	keys.Add(string(storage.AssetKey(receipt)), state.Read|state.Allocate|state.Write)
+	keys.Add(string(storage.OwnedAssetKey(actor, receipt)), state.Allocate|state.Write)
+	keys.Add(string(storage.TombstoneKey(storage.AssetKey(receipt))), state.Read)
+	keys.Add(string(storage.ReceiptKey(receipt)), state.Allocate|state.Write)
+}
+
+func receiptComputeUnits(receipt ids.ID) uint64 {
+	if receipt == ids.Empty {
+		return 0
+	}
+	return ReceiptComputeUnits
+}
+
+// mintReceipt mints [receipt], a single-supply asset owned by [actor],
+// recording the action [actionID] of type [typeID]. It does nothing if
+// [receipt] is not set.
+//
+// Actions only see their action ID, so that is what the receipt references.
+// The txID is the one of the actor's transactions whose
+// chain.CreateActionID matches it.
+func mintReceipt(
+	ctx context.Context,
+	mu state.Mutable,
+	receipt ids.ID,
+	actor codec.Address,
+	typeID uint8,
+	timestamp int64,
+	actionID ids.ID,
+) error {
+	if receipt == ids.Empty {
+		return nil
+	}
+	if err := storage.CreateAsset(ctx, mu, receipt, actor); err != nil {
+		return err
+	}
+	if err := storage.SetAssetMetadata(ctx, mu, receipt, storage.AssetMetadata{
+		Name:        ReceiptName,
+		TotalSupply: 1,
+	}); err != nil {
+		return err
+	}
+	return storage.SetReceipt(ctx, mu, receipt, &storage.Receipt{
+		ActionID:  actionID,
+		TypeID:    typeID,
+		Timestamp: timestamp,
+	})
+}
+
+// emitReceipt emits the minting of [receipt] to [actor], if it is set.
+func emitReceipt(receipt ids.ID, actor codec.Address, e Emitter) {
+	if receipt == ids.Empty {
+		return
+	}
+	e.Emit(&OwnershipEvent{Asset: receipt, To: actor})
+}
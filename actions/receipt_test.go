@@ -0,0 +1,133 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+func TestReceipts(t *testing.T) {
+	actor := codectest.NewRandomAddress()
+	to := codectest.NewRandomAddress()
+	maker := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+	orderID := ids.GenerateTestID()
+	receipt := ids.GenerateTestID()
+	actionID := ids.GenerateTestID()
+
+	// funded gives [actor] 100 native tokens and [maker] an order selling
+	// 10 of [asset] for 20 native tokens.
+	funded := func() state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(ctx, store, actor, 100))
+		require.NoError(t, storage.SetOrder(ctx, store, asset, storage.NativeAsset, orderID, &storage.Order{
+			Maker:      maker,
+			SellAmount: 10,
+			BuyAmount:  20,
+			Remaining:  10,
+		}))
+		return store
+	}
+	minted := func(typeID uint8) func(context.Context, *testing.T, state.Mutable) {
+		return func(ctx context.Context, t *testing.T, store state.Mutable) {
+			require := require.New(t)
+			owner, err := storage.GetAssetOwner(ctx, store, receipt)
+			require.NoError(err)
+			require.Equal(actor, owner)
+			metadata, err := storage.GetAssetMetadata(ctx, store, receipt)
+			require.NoError(err)
+			require.Equal(storage.AssetMetadata{Name: ReceiptName, TotalSupply: 1}, metadata)
+			r, exists, err := storage.GetReceipt(ctx, store, receipt)
+			require.NoError(err)
+			require.True(exists)
+			require.Equal(&storage.Receipt{ActionID: actionID, TypeID: typeID, Timestamp: 1_000}, r)
+		}
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:      "Transfer",
+			Actor:     actor,
+			Action:    &Transfer{To: to, Value: 10, Receipt: receipt},
+			State:     funded(),
+			Timestamp: 1_000,
+			ActionID:  actionID,
+			Assertion: minted(mconsts.TransferID),
+			ExpectedOutputs: &TransferResult{
+				SenderBalance:   90,
+				ReceiverBalance: 10,
+			},
+		},
+		{
+			Name:   "NoReceipt",
+			Actor:  actor,
+			Action: &Transfer{To: to, Value: 10},
+			State:  funded(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				_, exists, err := storage.GetReceipt(ctx, store, ids.Empty)
+				require.NoError(t, err)
+				require.False(t, exists)
+			},
+			ExpectedOutputs: &TransferResult{
+				SenderBalance:   90,
+				ReceiverBalance: 10,
+			},
+		},
+		{
+			Name:   "ReceiptExists",
+			Actor:  actor,
+			Action: &Transfer{To: to, Value: 10, Receipt: receipt},
+			State: func() state.Mutable {
+				store := funded()
+				require.NoError(t, storage.CreateAsset(context.Background(), store, receipt, maker))
+				return store
+			}(),
+			ExpectedErr: storage.ErrAssetExists,
+		},
+		{
+			Name:  "FillOrder",
+			Actor: actor,
+			Action: &FillOrder{
+				OrderID:   orderID,
+				SellAsset: asset,
+				BuyAsset:  storage.NativeAsset,
+				Maker:     maker,
+				Amount:    5,
+				Receipt:   receipt,
+			},
+			State:     funded(),
+			Timestamp: 1_000,
+			ActionID:  actionID,
+			Assertion: minted(mconsts.FillOrderID),
+			ExpectedOutputs: &FillOrderResult{
+				Maker:     maker,
+				Bought:    5,
+				Paid:      10,
+				Remaining: 5,
+			},
+		},
+	}
+
+	runActionTests(t, tests)
+}
+
+func TestReceiptComputeUnits(t *testing.T) {
+	require := require.New(t)
+	plain := &Transfer{Value: 1}
+	withReceipt := &Transfer{Value: 1, Receipt: ids.GenerateTestID()}
+	require.Equal(plain.ComputeUnits(nil)+ReceiptComputeUnits, withReceipt.ComputeUnits(nil))
+	require.Contains(withReceipt.StateKeys(codectest.NewRandomAddress()), string(storage.ReceiptKey(withReceipt.Receipt)))
+}
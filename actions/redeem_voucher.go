@@ -0,0 +1,188 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const (
+	// Voucher redemption verifies an ed25519 signature on top of a transfer.
+	RedeemVoucherComputeUnits = 5
+	MaxTokenURISize           = storage.MaxAssetURISize
+	MaxRoyaltyBasisPoints     = 10_000
+)
+
+var (
+	ErrTokenURITooLarge                     = errors.New("token URI is too large")
+	ErrRoyaltyTooLarge                      = errors.New("royalty exceeds 100%")
+	ErrInvalidVoucherSignature              = errors.New("invalid voucher signature")
+	_                          chain.Action = (*RedeemVoucher)(nil)
+	_                          codec.Typed  = (*RedeemVoucherResult)(nil)
+)
+
+// Voucher is signed off-chain by a creator to let the first buyer mint
+// [Asset] for [Price]. Nothing is written on-chain until it is redeemed.
+type Voucher struct {
+	Asset              ids.ID `serialize:"true" json:"asset"`
+	TokenURI           string `serialize:"true" json:"tokenURI"`
+	Price              uint64 `serialize:"true" json:"price"`
+	RoyaltyBasisPoints uint16 `serialize:"true" json:"royaltyBasisPoints"`
+}
+
+// Digest returns the bytes a creator signs to issue [v] on [chainID]. The
+// action type separates vouchers from permits signed with the same key.
+func (v *Voucher) Digest(chainID ids.ID) ([]byte, error) {
+	b, err := chain.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	digest := make([]byte, 0, ids.IDLen+1+len(b))
+	digest = append(digest, chainID[:]...)
+	digest = append(digest, mconsts.RedeemVoucherID)
+	return append(digest, b...), nil
+}
+
+type RedeemVoucher struct {
+	Voucher Voucher `serialize:"true" json:"voucher"`
+
+	// Creator is the ed25519 public key that signed [Voucher]. The creator is
+	// paid at the address derived from it.
+	Creator []byte `serialize:"true" json:"creator"`
+
+	// Signature over [Voucher.Digest].
+	Signature []byte `serialize:"true" json:"signature"`
+}
+
+func (*RedeemVoucher) GetTypeID() uint8 {
+	return mconsts.RedeemVoucherID
+}
+
+func (r *RedeemVoucher) creator() (ed25519.PublicKey, codec.Address) {
+	var pk ed25519.PublicKey
+	copy(pk[:], r.Creator)
+	return pk, auth.NewED25519Address(pk)
+}
+
+func (r *RedeemVoucher) StateKeys(actor codec.Address) state.Keys {
+	_, creator := r.creator()
+	return state.Keys{
+		string(storage.AssetKey(r.Voucher.Asset)):                       state.All,
+		string(storage.OwnedAssetKey(actor, r.Voucher.Asset)):           state.Allocate | state.Write,
+		string(storage.TombstoneKey(storage.AssetKey(r.Voucher.Asset))): state.Read,
+		string(storage.BalanceKey(actor)):                               state.Read | state.Write,
+		string(storage.BalanceKey(creator)):                             state.All,
+		string(storage.ActiveProposalKey()):                             state.Read,
+		string(storage.HaltKey()):                                       state.Read,
+	}
+}
+
+func (r *RedeemVoucher) Execute(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, r, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if len(r.Voucher.TokenURI) > MaxTokenURISize {
+		return nil, ErrTokenURITooLarge
+	}
+	if r.Voucher.RoyaltyBasisPoints > MaxRoyaltyBasisPoints {
+		return nil, ErrRoyaltyTooLarge
+	}
+	if len(r.Creator) != ed25519.PublicKeyLen || len(r.Signature) != ed25519.SignatureLen {
+		return nil, ErrInvalidVoucherSignature
+	}
+	pk, creator := r.creator()
+	digest, err := r.Voucher.Digest(rules.GetChainID())
+	if err != nil {
+		return nil, err
+	}
+	var sig ed25519.Signature
+	copy(sig[:], r.Signature)
+	if !ed25519.Verify(digest, pk, sig) {
+		return nil, ErrInvalidVoucherSignature
+	}
+
+	if err := storage.CreateAsset(ctx, mu, r.Voucher.Asset, actor); err != nil {
+		return nil, err
+	}
+	if err := storage.SetAssetMetadata(ctx, mu, r.Voucher.Asset, storage.AssetMetadata{
+		URI:         r.Voucher.TokenURI,
+		TotalSupply: 1,
+	}); err != nil {
+		return nil, err
+	}
+	if r.Voucher.RoyaltyBasisPoints > 0 {
+		if err := storage.SetAssetRoyalty(ctx, mu, r.Voucher.Asset, r.Voucher.RoyaltyBasisPoints, creator); err != nil {
+			return nil, err
+		}
+	}
+	if r.Voucher.Price > 0 {
+		if _, err := storage.SubBalance(ctx, mu, actor, r.Voucher.Price); err != nil {
+			return nil, err
+		}
+		if _, err := storage.AddBalance(ctx, mu, creator, r.Voucher.Price, true); err != nil {
+			return nil, err
+		}
+	}
+	return &RedeemVoucherResult{
+		Asset:              r.Voucher.Asset,
+		Creator:            creator,
+		Owner:              actor,
+		Price:              r.Voucher.Price,
+		TokenURI:           r.Voucher.TokenURI,
+		RoyaltyBasisPoints: r.Voucher.RoyaltyBasisPoints,
+	}, nil
+}
+
+func (*RedeemVoucher) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.RedeemVoucherID, RedeemVoucherComputeUnits)
+}
+
+func (*RedeemVoucher) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (*RedeemVoucher) EmitEvents(actor codec.Address, output codec.Typed, e Emitter) {
+	res := output.(*RedeemVoucherResult)
+	e.Emit(&OwnershipEvent{Asset: res.Asset, To: actor})
+	if res.Price > 0 {
+		e.Emit(&TransferEvent{From: actor, To: res.Creator, Asset: storage.NativeAsset, Amount: res.Price})
+	}
+}
+
+type RedeemVoucherResult struct {
+	Asset              ids.ID        `serialize:"true" json:"asset"`
+	Creator            codec.Address `serialize:"true" json:"creator"`
+	Owner              codec.Address `serialize:"true" json:"owner"`
+	Price              uint64        `serialize:"true" json:"price"`
+	TokenURI           string        `serialize:"true" json:"tokenURI"`
+	RoyaltyBasisPoints uint16        `serialize:"true" json:"royaltyBasisPoints"`
+}
+
+func (*RedeemVoucherResult) GetTypeID() uint8 {
+	return mconsts.RedeemVoucherID
+}
@@ -0,0 +1,168 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestRedeemVoucherAction(t *testing.T) {
+	rules := genesis.NewDefaultRules()
+	rules.ChainID = ids.GenerateTestID()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	creator := auth.NewED25519Address(pub)
+
+	voucher := Voucher{
+		Asset:              ids.GenerateTestID(),
+		TokenURI:           "ipfs://token",
+		Price:              10,
+		RoyaltyBasisPoints: 250,
+	}
+	sign := func(v Voucher, chainID ids.ID) []byte {
+		digest, err := v.Digest(chainID)
+		require.NoError(t, err)
+		sig := ed25519.Sign(digest, priv)
+		return sig[:]
+	}
+	funded := func() state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(context.Background(), store, codec.EmptyAddress, 10))
+		return store
+	}
+
+	redeem := &RedeemVoucher{
+		Voucher:   voucher,
+		Creator:   pub[:],
+		Signature: sign(voucher, rules.ChainID),
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "RoyaltyTooLarge",
+			Actor: codec.EmptyAddress,
+			Action: &RedeemVoucher{
+				Voucher: Voucher{RoyaltyBasisPoints: MaxRoyaltyBasisPoints + 1},
+			},
+			Rules:       rules,
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: ErrRoyaltyTooLarge,
+		},
+		{
+			Name:  "WrongChain",
+			Actor: codec.EmptyAddress,
+			Action: &RedeemVoucher{
+				Voucher:   voucher,
+				Creator:   pub[:],
+				Signature: sign(voucher, ids.GenerateTestID()),
+			},
+			Rules:       rules,
+			State:       funded(),
+			ExpectedErr: ErrInvalidVoucherSignature,
+		},
+		{
+			Name:  "AlreadyMinted",
+			Actor: codec.EmptyAddress,
+			Action: &RedeemVoucher{
+				Voucher:   voucher,
+				Creator:   pub[:],
+				Signature: sign(voucher, rules.ChainID),
+			},
+			Rules: rules,
+			State: func() state.Mutable {
+				store := funded()
+				require.NoError(t, storage.CreateAsset(context.Background(), store, voucher.Asset, creator))
+				return store
+			}(),
+			ExpectedErr: storage.ErrAssetExists,
+		},
+		{
+			// A voucher cannot be redeemed again once its asset is burned.
+			Name:   "RedeemAfterBurn",
+			Actor:  codec.EmptyAddress,
+			Action: redeem,
+			Rules:  rules,
+			State: func() state.Mutable {
+				ctx := context.Background()
+				store := funded()
+				require.NoError(t, store.Insert(ctx, chain.HeightKey(storage.HeightKey()), binary.BigEndian.AppendUint64(nil, 1)))
+				_, err := redeem.Execute(ctx, rules, store, 0, codec.EmptyAddress, ids.Empty)
+				require.NoError(t, err)
+				_, err = (&BurnAsset{Asset: voucher.Asset}).Execute(ctx, rules, store, 0, codec.EmptyAddress, ids.Empty)
+				require.NoError(t, err)
+				require.NoError(t, storage.SetBalance(ctx, store, codec.EmptyAddress, 10))
+				return store
+			}(),
+			ExpectedErr: storage.ErrAssetBurned,
+		},
+		{
+			Name:  "NotEnoughBalance",
+			Actor: codec.EmptyAddress,
+			Action: &RedeemVoucher{
+				Voucher:   voucher,
+				Creator:   pub[:],
+				Signature: sign(voucher, rules.ChainID),
+			},
+			Rules: rules,
+			State: func() state.Mutable {
+				store := chaintest.NewInMemoryStore()
+				require.NoError(t, storage.SetBalance(context.Background(), store, codec.EmptyAddress, 1))
+				return store
+			}(),
+			ExpectedErr: storage.ErrInvalidBalance,
+		},
+		{
+			Name:  "Redeem",
+			Actor: codec.EmptyAddress,
+			Action: &RedeemVoucher{
+				Voucher:   voucher,
+				Creator:   pub[:],
+				Signature: sign(voucher, rules.ChainID),
+			},
+			Rules: rules,
+			State: funded(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				owner, err := storage.GetAssetOwner(ctx, store, voucher.Asset)
+				require.NoError(t, err)
+				require.Equal(t, codec.EmptyAddress, owner)
+				metadata, err := storage.GetAssetMetadata(ctx, store, voucher.Asset)
+				require.NoError(t, err)
+				require.Equal(t, voucher.TokenURI, metadata.URI)
+				control, err := storage.GetAssetControl(ctx, store, voucher.Asset)
+				require.NoError(t, err)
+				require.Equal(t, uint16(250), control.RoyaltyBasisPoints)
+				require.Equal(t, creator, control.RoyaltyPayee)
+				balance, err := storage.GetBalance(ctx, store, creator)
+				require.NoError(t, err)
+				require.Equal(t, uint64(10), balance)
+			},
+			ExpectedOutputs: &RedeemVoucherResult{
+				Asset:              voucher.Asset,
+				Creator:            creator,
+				Owner:              codec.EmptyAddress,
+				Price:              10,
+				TokenURI:           voucher.TokenURI,
+				RoyaltyBasisPoints: 250,
+			},
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -0,0 +1,203 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const SessionComputeUnits = 1
+
+var (
+	ErrInvalidSessionKey    = errors.New("session key must be another address")
+	ErrNoSessionActionTypes = errors.New("session allows no action types")
+	ErrTooManyActionTypes   = errors.New("too many session action types")
+	ErrDuplicateActionType  = errors.New("duplicate session action type")
+	ErrSessionExpired       = errors.New("session expiry has passed")
+	ErrSessionExists        = errors.New("session already exists")
+	ErrSessionNotFound      = errors.New("session not found")
+	ErrSessionActionType    = errors.New("sessions cannot manage sessions")
+
+	_ chain.Action = (*AuthorizeSessionKey)(nil)
+	_ chain.Action = (*RevokeSessionKey)(nil)
+)
+
+// AuthorizeSessionKey records that [Key] may send [ActionTypes] on the
+// actor's behalf, spending at most [SpendCap] native tokens, until
+// [Expiry]. Dapps read it to check their authorization status.
+type AuthorizeSessionKey struct {
+	// SessionID is chosen by the dapp requesting the session.
+	SessionID   ids.ID        `serialize:"true" json:"session_id"`
+	Key         codec.Address `serialize:"true" json:"key"`
+	ActionTypes []uint8       `serialize:"true" json:"action_types"`
+	SpendCap    uint64        `serialize:"true" json:"spend_cap"`
+	Expiry      int64         `serialize:"true" json:"expiry"`
+}
+
+func (*AuthorizeSessionKey) GetTypeID() uint8 {
+	return mconsts.AuthorizeSessionKeyID
+}
+
+func (a *AuthorizeSessionKey) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.SessionKey(actor, a.SessionID)): state.All,
+		string(storage.HaltKey()):                      state.Read,
+	}
+}
+
+func (a *AuthorizeSessionKey) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, a, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if a.Key == codec.EmptyAddress || a.Key == actor {
+		return nil, ErrInvalidSessionKey
+	}
+	if err := VerifySessionActionTypes(a.ActionTypes); err != nil {
+		return nil, err
+	}
+	if a.Expiry <= timestamp {
+		return nil, ErrSessionExpired
+	}
+	_, exists, err := storage.GetSession(ctx, mu, actor, a.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrSessionExists
+	}
+	if err := storage.SetSession(ctx, mu, actor, a.SessionID, &storage.Session{
+		Key:         a.Key,
+		Expiry:      a.Expiry,
+		SpendCap:    a.SpendCap,
+		ActionTypes: a.ActionTypes,
+	}); err != nil {
+		return nil, err
+	}
+	return &AuthorizeSessionKeyResult{Expiry: a.Expiry}, nil
+}
+
+func (*AuthorizeSessionKey) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.AuthorizeSessionKeyID, SessionComputeUnits)
+}
+
+func (*AuthorizeSessionKey) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*AuthorizeSessionKeyResult)(nil)
+
+type AuthorizeSessionKeyResult struct {
+	Expiry int64 `serialize:"true" json:"expiry"`
+}
+
+func (*AuthorizeSessionKeyResult) GetTypeID() uint8 {
+	return mconsts.AuthorizeSessionKeyID
+}
+
+// RevokeSessionKey removes one of the actor's sessions before it expires.
+type RevokeSessionKey struct {
+	SessionID ids.ID `serialize:"true" json:"session_id"`
+}
+
+func (*RevokeSessionKey) GetTypeID() uint8 {
+	return mconsts.RevokeSessionKeyID
+}
+
+func (r *RevokeSessionKey) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.SessionKey(actor, r.SessionID)): state.Read | state.Write,
+		string(storage.HaltKey()):                      state.Read,
+	}
+}
+
+func (r *RevokeSessionKey) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, r, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	session, exists, err := storage.GetSession(ctx, mu, actor, r.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrSessionNotFound
+	}
+	if err := storage.DeleteSession(ctx, mu, actor, r.SessionID); err != nil {
+		return nil, err
+	}
+	return &RevokeSessionKeyResult{Key: session.Key}, nil
+}
+
+func (*RevokeSessionKey) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.RevokeSessionKeyID, SessionComputeUnits)
+}
+
+func (*RevokeSessionKey) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*RevokeSessionKeyResult)(nil)
+
+type RevokeSessionKeyResult struct {
+	Key codec.Address `serialize:"true" json:"key"`
+}
+
+func (*RevokeSessionKeyResult) GetTypeID() uint8 {
+	return mconsts.RevokeSessionKeyID
+}
+
+// VerifySessionActionTypes checks that [actionTypes] is a non-empty set of
+// action type IDs that does not include the session actions themselves.
+func VerifySessionActionTypes(actionTypes []uint8) error {
+	if len(actionTypes) == 0 {
+		return ErrNoSessionActionTypes
+	}
+	if len(actionTypes) > storage.MaxSessionActionTypes {
+		return ErrTooManyActionTypes
+	}
+	seen := make(map[uint8]bool, len(actionTypes))
+	for _, t := range actionTypes {
+		if t == mconsts.AuthorizeSessionKeyID || t == mconsts.RevokeSessionKeyID {
+			return ErrSessionActionType
+		}
+		if seen[t] {
+			return ErrDuplicateActionType
+		}
+		seen[t] = true
+	}
+	return nil
+}
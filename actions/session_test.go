@@ -0,0 +1,133 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+func TestSessionActions(t *testing.T) {
+	owner := codectest.NewRandomAddress()
+	key := codectest.NewRandomAddress()
+	existing := ids.GenerateTestID()
+	sessionID := ids.GenerateTestID()
+	session := &storage.Session{
+		Key:         key,
+		Expiry:      1_000,
+		SpendCap:    500,
+		ActionTypes: []uint8{mconsts.TransferID},
+	}
+
+	// account has [existing] authorized for [key].
+	account := func() state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetSession(context.Background(), store, owner, existing, session))
+		return store
+	}
+	authorize := func(actionTypes ...uint8) *AuthorizeSessionKey {
+		return &AuthorizeSessionKey{
+			SessionID:   sessionID,
+			Key:         key,
+			ActionTypes: actionTypes,
+			SpendCap:    500,
+			Expiry:      1_000,
+		}
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:        "OwnKey",
+			Actor:       owner,
+			Action:      &AuthorizeSessionKey{SessionID: sessionID, Key: owner, ActionTypes: []uint8{mconsts.TransferID}, Expiry: 1_000},
+			State:       account(),
+			ExpectedErr: ErrInvalidSessionKey,
+		},
+		{
+			Name:        "NoActionTypes",
+			Actor:       owner,
+			Action:      authorize(),
+			State:       account(),
+			ExpectedErr: ErrNoSessionActionTypes,
+		},
+		{
+			Name:        "DuplicateActionType",
+			Actor:       owner,
+			Action:      authorize(mconsts.TransferID, mconsts.TransferID),
+			State:       account(),
+			ExpectedErr: ErrDuplicateActionType,
+		},
+		{
+			Name:        "SessionActionType",
+			Actor:       owner,
+			Action:      authorize(mconsts.AuthorizeSessionKeyID),
+			State:       account(),
+			ExpectedErr: ErrSessionActionType,
+		},
+		{
+			Name:        "Expired",
+			Actor:       owner,
+			Action:      authorize(mconsts.TransferID),
+			Timestamp:   1_000,
+			State:       account(),
+			ExpectedErr: ErrSessionExpired,
+		},
+		{
+			Name:        "Exists",
+			Actor:       owner,
+			Action:      &AuthorizeSessionKey{SessionID: existing, Key: key, ActionTypes: []uint8{mconsts.TransferID}, Expiry: 1_000},
+			State:       account(),
+			ExpectedErr: ErrSessionExists,
+		},
+		{
+			Name:   "Authorize",
+			Actor:  owner,
+			Action: authorize(mconsts.TransferID, mconsts.SwapID),
+			State:  account(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				stored, exists, err := storage.GetSession(ctx, store, owner, sessionID)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.Session{
+					Key:         key,
+					Expiry:      1_000,
+					SpendCap:    500,
+					ActionTypes: []uint8{mconsts.TransferID, mconsts.SwapID},
+				}, stored)
+			},
+			ExpectedOutputs: &AuthorizeSessionKeyResult{Expiry: 1_000},
+		},
+		{
+			Name:        "RevokeMissing",
+			Actor:       owner,
+			Action:      &RevokeSessionKey{SessionID: sessionID},
+			State:       account(),
+			ExpectedErr: ErrSessionNotFound,
+		},
+		{
+			Name:   "Revoke",
+			Actor:  owner,
+			Action: &RevokeSessionKey{SessionID: existing},
+			State:  account(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				_, exists, err := storage.GetSession(ctx, store, owner, existing)
+				require.NoError(t, err)
+				require.False(t, exists)
+			},
+			ExpectedOutputs: &RevokeSessionKeyResult{Key: key},
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const SetNotificationPrefsComputeUnits = 1
+
+var (
+	ErrUnknownEventKind              = errors.New("unknown event kind")
+	_                   chain.Action = (*SetNotificationPrefs)(nil)
+)
+
+// SetNotificationPrefs opts the actor into having the events in [EventMask]
+// pushed to [Target]. An empty mask opts out.
+type SetNotificationPrefs struct {
+	// Target is the hash of a webhook URL or the public key of a subscriber.
+	Target ids.ID `serialize:"true" json:"target"`
+
+	// EventMask is a combination of the storage.Notify* event kinds.
+	EventMask uint64 `serialize:"true" json:"eventMask"`
+}
+
+func (*SetNotificationPrefs) GetTypeID() uint8 {
+	return mconsts.SetNotificationPrefsID
+}
+
+func (*SetNotificationPrefs) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.NotificationKey(actor)): state.All,
+		string(storage.HaltKey()):              state.Read,
+	}
+}
+
+func (s *SetNotificationPrefs) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, s, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if s.EventMask&^storage.NotifyAll != 0 {
+		return nil, ErrUnknownEventKind
+	}
+	if err := storage.SetNotificationPrefs(ctx, mu, actor, storage.NotificationPrefs{
+		Target:    s.Target,
+		EventMask: s.EventMask,
+	}); err != nil {
+		return nil, err
+	}
+	return &SetNotificationPrefsResult{
+		Target:    s.Target,
+		EventMask: s.EventMask,
+	}, nil
+}
+
+func (*SetNotificationPrefs) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.SetNotificationPrefsID, SetNotificationPrefsComputeUnits)
+}
+
+func (*SetNotificationPrefs) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*SetNotificationPrefsResult)(nil)
+
+type SetNotificationPrefsResult struct {
+	Target    ids.ID `serialize:"true" json:"target"`
+	EventMask uint64 `serialize:"true" json:"eventMask"`
+}
+
+func (*SetNotificationPrefsResult) GetTypeID() uint8 {
+	return mconsts.SetNotificationPrefsID
+}
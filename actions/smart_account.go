@@ -0,0 +1,261 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const SmartAccountComputeUnits = 1
+
+var (
+	ErrSmartAccountExists    = errors.New("smart account already exists")
+	ErrSmartAccountNotFound  = errors.New("smart account not found")
+	ErrNotSmartAccountSigner = errors.New("actor is neither an owner nor a session of the smart account")
+	ErrSessionAssetTransfer  = errors.New("sessions only make native transfers")
+	ErrSpendLimit            = errors.New("smart account spending limit exceeded")
+
+	_ chain.Action = (*DeploySmartAccount)(nil)
+	_ chain.Action = (*ExecuteFromSmartAccount)(nil)
+)
+
+// DeploySmartAccount registers [Policy] under a new address derived from
+// the actor and [Nonce]. Funds sent to that address only leave through
+// [ExecuteFromSmartAccount], as the policy allows.
+type DeploySmartAccount struct {
+	Nonce uint64 `serialize:"true" json:"nonce"`
+	// Policy is a [storage.SmartAccountPolicy] encoded by its Bytes method.
+	Policy []byte `serialize:"true" json:"policy"`
+}
+
+func (*DeploySmartAccount) GetTypeID() uint8 {
+	return mconsts.DeploySmartAccountID
+}
+
+func (d *DeploySmartAccount) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.SmartAccountKey(storage.SmartAccountAddress(actor, d.Nonce))): state.All,
+		string(storage.HaltKey()): state.Read,
+	}
+}
+
+func (d *DeploySmartAccount) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, d, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	policy, err := storage.ParseSmartAccountPolicy(d.Policy)
+	if err != nil {
+		return nil, err
+	}
+	account := storage.SmartAccountAddress(actor, d.Nonce)
+	_, exists, err := storage.GetSmartAccount(ctx, mu, account)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrSmartAccountExists
+	}
+	if err := storage.SetSmartAccount(ctx, mu, account, &storage.SmartAccount{
+		Policy: policy,
+		Spent:  make([]uint64, len(policy.Sessions)),
+	}); err != nil {
+		return nil, err
+	}
+	return &DeploySmartAccountResult{Account: account}, nil
+}
+
+func (*DeploySmartAccount) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.DeploySmartAccountID, SmartAccountComputeUnits)
+}
+
+func (*DeploySmartAccount) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*DeploySmartAccountResult)(nil)
+
+type DeploySmartAccountResult struct {
+	Account codec.Address `serialize:"true" json:"account"`
+}
+
+func (*DeploySmartAccountResult) GetTypeID() uint8 {
+	return mconsts.DeploySmartAccountID
+}
+
+// ExecuteFromSmartAccount makes [Tx] from [Account] as its policy allows.
+// A session key of the account runs a native transfer at once, within its
+// cap. An owner approves the request [RequestID] to make [Tx], opening it
+// if it is not pending, and [Tx] runs as a [Transfer] or [AssetTransfer]
+// from the account in the action that brings approvals to the threshold.
+//
+// It replaces the multisig actions for new accounts: pending requests are
+// stored and approved like multisig proposals.
+type ExecuteFromSmartAccount struct {
+	Account codec.Address `serialize:"true" json:"account"`
+	// RequestID is chosen by the owner opening the request. Sessions leave
+	// it empty.
+	RequestID ids.ID             `serialize:"true" json:"request_id"`
+	Tx        storage.MultisigTx `serialize:"true" json:"tx"`
+	// Expiry is the last timestamp, in milliseconds, at which a request
+	// opened by this action can be approved. Later approvals must repeat it.
+	Expiry int64 `serialize:"true" json:"expiry"`
+}
+
+func (*ExecuteFromSmartAccount) GetTypeID() uint8 {
+	return mconsts.ExecuteFromSmartAccountID
+}
+
+func (e *ExecuteFromSmartAccount) StateKeys(codec.Address) state.Keys {
+	keys := multisigAction(e.Tx).StateKeys(e.Account)
+	keys.Add(string(storage.SmartAccountKey(e.Account)), state.Read|state.Write)
+	keys.Add(string(storage.MultisigProposalKey(e.Account, e.RequestID)), state.All)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (e *ExecuteFromSmartAccount) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	actionID ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, e, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if e.Tx.Asset != storage.NativeAsset && e.Tx.Value != 0 {
+		return nil, ErrInvalidMultisigTx
+	}
+	account, exists, err := storage.GetSmartAccount(ctx, mu, e.Account)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrSmartAccountNotFound
+	}
+	policy := account.Policy
+	if policy.MaxSpend != 0 && e.Tx.Value > policy.MaxSpend {
+		return nil, ErrSpendLimit
+	}
+	if index := policy.Owner(actor); index >= 0 {
+		return e.approve(ctx, r, mu, timestamp, actionID, policy, index)
+	}
+	index := policy.Session(actor)
+	if index < 0 {
+		return nil, ErrNotSmartAccountSigner
+	}
+	session := policy.Sessions[index]
+	if session.Expiry < timestamp {
+		return nil, ErrSessionExpired
+	}
+	if e.Tx.Asset != storage.NativeAsset {
+		return nil, ErrSessionAssetTransfer
+	}
+	spent, err := smath.Add(account.Spent[index], e.Tx.Value)
+	if err != nil || spent > session.SpendCap {
+		return nil, ErrSpendLimit
+	}
+	account.Spent[index] = spent
+	if err := storage.SetSmartAccount(ctx, mu, e.Account, account); err != nil {
+		return nil, err
+	}
+	if _, err := multisigAction(e.Tx).Execute(ctx, r, mu, timestamp, e.Account, actionID); err != nil {
+		return nil, err
+	}
+	return &ExecuteFromSmartAccountResult{Executed: true}, nil
+}
+
+// approve adds the approval of owner [index] to the request of [e].
+func (e *ExecuteFromSmartAccount) approve(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actionID ids.ID,
+	policy *storage.SmartAccountPolicy,
+	index int,
+) (codec.Typed, error) {
+	request, exists, err := storage.GetMultisigProposal(ctx, mu, e.Account, e.RequestID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !exists:
+		request = &storage.MultisigProposal{Tx: e.Tx, Expiry: e.Expiry}
+	case request.Tx != e.Tx || request.Expiry != e.Expiry:
+		return nil, ErrProposalMismatch
+	case request.Approvals&(1<<index) != 0:
+		return nil, ErrAlreadyApproved
+	}
+	if request.Expiry < timestamp {
+		return nil, ErrProposalExpired
+	}
+	request.Approvals |= 1 << index
+	executed, err := recordMultisigApproval(ctx, r, mu, timestamp, actionID, e.Account, &storage.Multisig{
+		Signers:   policy.Owners,
+		Threshold: policy.Threshold,
+	}, e.RequestID, request)
+	if err != nil {
+		return nil, err
+	}
+	return &ExecuteFromSmartAccountResult{
+		Approvals: uint8(request.ApprovalCount()),
+		Executed:  executed,
+	}, nil
+}
+
+func (e *ExecuteFromSmartAccount) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.ExecuteFromSmartAccountID, SmartAccountComputeUnits) + multisigAction(e.Tx).ComputeUnits(r)
+}
+
+func (*ExecuteFromSmartAccount) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (e *ExecuteFromSmartAccount) EmitEvents(_ codec.Address, output codec.Typed, em Emitter) {
+	if r, ok := output.(*ExecuteFromSmartAccountResult); ok && r.Executed {
+		emitMultisigTx(e.Account, e.Tx, em)
+	}
+}
+
+var _ codec.Typed = (*ExecuteFromSmartAccountResult)(nil)
+
+type ExecuteFromSmartAccountResult struct {
+	// Approvals is 0 when a session made the transaction.
+	Approvals uint8 `serialize:"true" json:"approvals"`
+	Executed  bool  `serialize:"true" json:"executed"`
+}
+
+func (*ExecuteFromSmartAccountResult) GetTypeID() uint8 {
+	return mconsts.ExecuteFromSmartAccountID
+}
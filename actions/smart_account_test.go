@@ -0,0 +1,218 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestSmartAccountActions(t *testing.T) {
+	owners := []codec.Address{
+		codectest.NewRandomAddress(),
+		codectest.NewRandomAddress(),
+	}
+	sessionKey := codectest.NewRandomAddress()
+	outsider := codectest.NewRandomAddress()
+	to := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+	requestID := ids.GenerateTestID()
+	account := storage.SmartAccountAddress(owners[0], 1)
+	pay := storage.MultisigTx{To: to, Asset: storage.NativeAsset, Value: 4}
+	policy := &storage.SmartAccountPolicy{
+		Owners:    owners,
+		Threshold: 2,
+		MaxSpend:  8,
+		Sessions:  []storage.SmartAccountSession{{Key: sessionKey, Expiry: 100, SpendCap: 6}},
+	}
+
+	// wallet is a funded account owning [asset] whose session has spent
+	// [spent], with [approvals] already given to a request making [pay]
+	// when non-zero.
+	wallet := func(spent uint64, approvals uint16) state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(ctx, store, account, 20))
+		require.NoError(t, storage.CreateAsset(ctx, store, asset, account))
+		require.NoError(t, storage.SetSmartAccount(ctx, store, account, &storage.SmartAccount{
+			Policy: policy,
+			Spent:  []uint64{spent},
+		}))
+		if approvals != 0 {
+			require.NoError(t, storage.SetMultisigProposal(ctx, store, account, requestID, &storage.MultisigProposal{
+				Tx:        pay,
+				Expiry:    100,
+				Approvals: approvals,
+			}))
+		}
+		return store
+	}
+	requireBalance := func(ctx context.Context, t *testing.T, store state.Mutable, addr codec.Address, expected uint64) {
+		balance, err := storage.GetBalance(ctx, store, addr)
+		require.NoError(t, err)
+		require.Equal(t, expected, balance)
+	}
+	execute := func(tx storage.MultisigTx) *ExecuteFromSmartAccount {
+		return &ExecuteFromSmartAccount{Account: account, RequestID: requestID, Tx: tx, Expiry: 100}
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:   "Deploy",
+			Actor:  owners[0],
+			Action: &DeploySmartAccount{Nonce: 1, Policy: policy.Bytes()},
+			State:  chaintest.NewInMemoryStore(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				a, exists, err := storage.GetSmartAccount(ctx, store, account)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.SmartAccount{Policy: policy, Spent: []uint64{0}}, a)
+			},
+			ExpectedOutputs: &DeploySmartAccountResult{Account: account},
+		},
+		{
+			Name:        "DeployExisting",
+			Actor:       owners[0],
+			Action:      &DeploySmartAccount{Nonce: 1, Policy: policy.Bytes()},
+			State:       wallet(0, 0),
+			ExpectedErr: ErrSmartAccountExists,
+		},
+		{
+			Name:  "DeploySessionOwner",
+			Actor: owners[0],
+			Action: &DeploySmartAccount{Nonce: 2, Policy: (&storage.SmartAccountPolicy{
+				Owners:    owners,
+				Threshold: 1,
+				Sessions:  []storage.SmartAccountSession{{Key: owners[1]}},
+			}).Bytes()},
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: storage.ErrInvalidSmartAccount,
+		},
+		{
+			Name:        "DeployMalformed",
+			Actor:       owners[0],
+			Action:      &DeploySmartAccount{Nonce: 2, Policy: append(policy.Bytes(), 0)},
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: storage.ErrInvalidSmartAccount,
+		},
+		{
+			Name:        "NotSigner",
+			Actor:       outsider,
+			Action:      execute(pay),
+			State:       wallet(0, 0),
+			ExpectedErr: ErrNotSmartAccountSigner,
+		},
+		{
+			Name:        "NotFound",
+			Actor:       owners[0],
+			Action:      &ExecuteFromSmartAccount{Account: storage.SmartAccountAddress(owners[0], 2), Tx: pay},
+			State:       wallet(0, 0),
+			ExpectedErr: ErrSmartAccountNotFound,
+		},
+		{
+			Name:        "AboveMaxSpend",
+			Actor:       owners[0],
+			Action:      execute(storage.MultisigTx{To: to, Asset: storage.NativeAsset, Value: 9}),
+			State:       wallet(0, 0),
+			ExpectedErr: ErrSpendLimit,
+		},
+		{
+			Name:   "SessionTransfer",
+			Actor:  sessionKey,
+			Action: execute(pay),
+			State:  wallet(2, 0),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				a, _, err := storage.GetSmartAccount(ctx, store, account)
+				require.NoError(t, err)
+				require.Equal(t, []uint64{6}, a.Spent)
+				requireBalance(ctx, t, store, account, 16)
+				requireBalance(ctx, t, store, to, 4)
+			},
+			ExpectedOutputs: &ExecuteFromSmartAccountResult{Executed: true},
+		},
+		{
+			Name:        "SessionAboveCap",
+			Actor:       sessionKey,
+			Action:      execute(pay),
+			State:       wallet(3, 0),
+			ExpectedErr: ErrSpendLimit,
+		},
+		{
+			Name:        "SessionExpired",
+			Actor:       sessionKey,
+			Action:      execute(pay),
+			Timestamp:   101,
+			State:       wallet(0, 0),
+			ExpectedErr: ErrSessionExpired,
+		},
+		{
+			Name:        "SessionAssetTransfer",
+			Actor:       sessionKey,
+			Action:      execute(storage.MultisigTx{To: to, Asset: asset}),
+			State:       wallet(0, 0),
+			ExpectedErr: ErrSessionAssetTransfer,
+		},
+		{
+			Name:   "OpenRequest",
+			Actor:  owners[1],
+			Action: execute(pay),
+			State:  wallet(0, 0),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				request, exists, err := storage.GetMultisigProposal(ctx, store, account, requestID)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, uint16(0b10), request.Approvals)
+				requireBalance(ctx, t, store, account, 20)
+			},
+			ExpectedOutputs: &ExecuteFromSmartAccountResult{Approvals: 1},
+		},
+		{
+			Name:        "ApproveTwice",
+			Actor:       owners[0],
+			Action:      execute(pay),
+			State:       wallet(0, 0b01),
+			ExpectedErr: ErrAlreadyApproved,
+		},
+		{
+			Name:        "ApproveMismatch",
+			Actor:       owners[1],
+			Action:      execute(storage.MultisigTx{To: outsider, Asset: storage.NativeAsset, Value: 4}),
+			State:       wallet(0, 0b01),
+			ExpectedErr: ErrProposalMismatch,
+		},
+		{
+			Name:        "ApproveExpired",
+			Actor:       owners[1],
+			Action:      execute(pay),
+			Timestamp:   101,
+			State:       wallet(0, 0b01),
+			ExpectedErr: ErrProposalExpired,
+		},
+		{
+			Name:   "ApproveTransfer",
+			Actor:  owners[1],
+			Action: execute(pay),
+			State:  wallet(0, 0b01),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				_, exists, err := storage.GetMultisigProposal(ctx, store, account, requestID)
+				require.NoError(t, err)
+				require.False(t, exists)
+				requireBalance(ctx, t, store, account, 16)
+				requireBalance(ctx, t, store, to, 4)
+			},
+			ExpectedOutputs: &ExecuteFromSmartAccountResult{Approvals: 2, Executed: true},
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -0,0 +1,583 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const (
+	CreateStablecoinComputeUnits     = 1
+	AttestReservesComputeUnits       = 1
+	MintStablecoinComputeUnits       = 1
+	BurnStablecoinComputeUnits       = 1
+	SetStablecoinBlockedComputeUnits = 1
+	SetStablecoinPausedComputeUnits  = 1
+)
+
+var (
+	ErrNotStablecoin       = errors.New("asset is not a stablecoin")
+	ErrEmptyStablecoinRole = errors.New("stablecoin role must be an address")
+	ErrNotIssuer           = errors.New("actor is not the stablecoin issuer")
+	ErrNotAttestor         = errors.New("actor is not the stablecoin attestor")
+	ErrNotComplianceRole   = errors.New("actor is not the stablecoin compliance officer")
+	ErrCannotPause         = errors.New("actor may not pause the stablecoin")
+	ErrStablecoinPaused    = errors.New("stablecoin is paused")
+	ErrAccountBlocked      = errors.New("account is on the stablecoin blocklist")
+	ErrExceedsReserves     = errors.New("supply would exceed attested reserves")
+
+	_ chain.Action = (*CreateStablecoin)(nil)
+	_ chain.Action = (*AttestReserves)(nil)
+	_ chain.Action = (*MintStablecoin)(nil)
+	_ chain.Action = (*BurnStablecoin)(nil)
+	_ chain.Action = (*SetStablecoinBlocked)(nil)
+	_ chain.Action = (*SetStablecoinPaused)(nil)
+)
+
+// CreateStablecoin creates [Asset] as a fungible asset with no supply,
+// issued by the actor. Only the issuer mints and burns it, only up to the
+// reserves [Attestor] reports, and [Compliance] keeps its blocklist.
+// Holders move it with [TransferAsset].
+type CreateStablecoin struct {
+	Asset      ids.ID        `serialize:"true" json:"asset"`
+	Name       string        `serialize:"true" json:"name"`
+	Symbol     string        `serialize:"true" json:"symbol"`
+	Decimals   uint8         `serialize:"true" json:"decimals"`
+	URI        string        `serialize:"true" json:"uri"`
+	Attestor   codec.Address `serialize:"true" json:"attestor"`
+	Compliance codec.Address `serialize:"true" json:"compliance"`
+}
+
+func (*CreateStablecoin) GetTypeID() uint8 {
+	return mconsts.CreateStablecoinID
+}
+
+func (c *CreateStablecoin) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.AssetKey(c.Asset)):                       state.Read | state.Allocate | state.Write,
+		string(storage.OwnedAssetKey(actor, c.Asset)):           state.Allocate | state.Write,
+		string(storage.TombstoneKey(storage.AssetKey(c.Asset))): state.Read,
+		string(storage.StablecoinKey(c.Asset)):                  state.Allocate | state.Write,
+		string(storage.HaltKey()):                               state.Read,
+	}
+}
+
+func (c *CreateStablecoin) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, c, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if c.Attestor == codec.EmptyAddress || c.Compliance == codec.EmptyAddress {
+		return nil, ErrEmptyStablecoinRole
+	}
+	if err := storage.CreateAsset(ctx, mu, c.Asset, actor); err != nil {
+		return nil, err
+	}
+	if err := storage.SetAssetMetadata(ctx, mu, c.Asset, storage.AssetMetadata{
+		Name:     c.Name,
+		Symbol:   c.Symbol,
+		Decimals: c.Decimals,
+		URI:      c.URI,
+	}); err != nil {
+		return nil, err
+	}
+	if err := storage.SetStablecoin(ctx, mu, c.Asset, &storage.Stablecoin{
+		Issuer:     actor,
+		Attestor:   c.Attestor,
+		Compliance: c.Compliance,
+	}); err != nil {
+		return nil, err
+	}
+	return &CreateStablecoinResult{Asset: c.Asset, Issuer: actor}, nil
+}
+
+func (*CreateStablecoin) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.CreateStablecoinID, CreateStablecoinComputeUnits)
+}
+
+func (*CreateStablecoin) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (c *CreateStablecoin) EmitEvents(actor codec.Address, _ codec.Typed, e Emitter) {
+	e.Emit(&OwnershipEvent{Asset: c.Asset, To: actor})
+}
+
+var _ codec.Typed = (*CreateStablecoinResult)(nil)
+
+type CreateStablecoinResult struct {
+	Asset  ids.ID        `serialize:"true" json:"asset"`
+	Issuer codec.Address `serialize:"true" json:"issuer"`
+}
+
+func (*CreateStablecoinResult) GetTypeID() uint8 {
+	return mconsts.CreateStablecoinID
+}
+
+// AttestReserves records the reserves backing [Asset], as found by the
+// attestor's report. Reserves below the supply stop minting until the
+// issuer burns down to them or reserves are topped up.
+type AttestReserves struct {
+	Asset ids.ID `serialize:"true" json:"asset"`
+	// Reserves are in base units of [Asset].
+	Reserves uint64 `serialize:"true" json:"reserves"`
+	// Report is the hash of the off-chain report behind the attestation.
+	Report ids.ID `serialize:"true" json:"report"`
+}
+
+func (*AttestReserves) GetTypeID() uint8 {
+	return mconsts.AttestReservesID
+}
+
+func (a *AttestReserves) StateKeys(codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.StablecoinKey(a.Asset)): state.Read | state.Write,
+		string(storage.AssetKey(a.Asset)):      state.Read,
+		string(storage.HaltKey()):              state.Read,
+	}
+}
+
+func (a *AttestReserves) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, a, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	s, err := getStablecoin(ctx, mu, a.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if actor != s.Attestor {
+		return nil, ErrNotAttestor
+	}
+	m, err := storage.GetAssetMetadata(ctx, mu, a.Asset)
+	if err != nil {
+		return nil, err
+	}
+	s.Reserves, s.Report, s.AttestedAt = a.Reserves, a.Report, timestamp
+	if err := storage.SetStablecoin(ctx, mu, a.Asset, s); err != nil {
+		return nil, err
+	}
+	return &AttestReservesResult{Supply: m.TotalSupply, Reserves: a.Reserves}, nil
+}
+
+func (*AttestReserves) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.AttestReservesID, AttestReservesComputeUnits)
+}
+
+func (*AttestReserves) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*AttestReservesResult)(nil)
+
+type AttestReservesResult struct {
+	Supply   uint64 `serialize:"true" json:"supply"`
+	Reserves uint64 `serialize:"true" json:"reserves"`
+}
+
+func (*AttestReservesResult) GetTypeID() uint8 {
+	return mconsts.AttestReservesID
+}
+
+// MintStablecoin issues [Value] of [Asset] to [To]. Only the issuer may
+// mint, and only while the stablecoin is not paused and the new supply is
+// within the attested reserves.
+type MintStablecoin struct {
+	Asset ids.ID        `serialize:"true" json:"asset"`
+	To    codec.Address `serialize:"true" json:"to"`
+	Value uint64        `serialize:"true" json:"value"`
+}
+
+func (*MintStablecoin) GetTypeID() uint8 {
+	return mconsts.MintStablecoinID
+}
+
+func (m *MintStablecoin) StateKeys(codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.StablecoinKey(m.Asset)):            state.Read,
+		string(storage.StablecoinBlockKey(m.Asset, m.To)): state.Read,
+		string(storage.AssetKey(m.Asset)):                 state.Read | state.Write,
+		string(storage.AssetBalanceKey(m.To, m.Asset)):    state.All,
+		string(storage.HaltKey()):                         state.Read,
+	}
+}
+
+func (m *MintStablecoin) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, m, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if m.Value == 0 {
+		return nil, ErrOutputValueZero
+	}
+	s, err := getStablecoin(ctx, mu, m.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if actor != s.Issuer {
+		return nil, ErrNotIssuer
+	}
+	if s.Paused {
+		return nil, ErrStablecoinPaused
+	}
+	if err := checkNotBlocked(ctx, mu, m.Asset, m.To); err != nil {
+		return nil, err
+	}
+	metadata, err := storage.GetAssetMetadata(ctx, mu, m.Asset)
+	if err != nil {
+		return nil, err
+	}
+	supply, err := smath.Add(metadata.TotalSupply, m.Value)
+	if err != nil || supply > s.Reserves {
+		return nil, fmt.Errorf("%w: %d + %d > %d", ErrExceedsReserves, metadata.TotalSupply, m.Value, s.Reserves)
+	}
+	metadata.TotalSupply = supply
+	if err := storage.SetAssetMetadata(ctx, mu, m.Asset, metadata); err != nil {
+		return nil, err
+	}
+	balance, err := storage.AddAssetBalance(ctx, mu, m.To, m.Asset, m.Value, true)
+	if err != nil {
+		return nil, err
+	}
+	return &MintStablecoinResult{Supply: supply, Balance: balance}, nil
+}
+
+func (*MintStablecoin) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.MintStablecoinID, MintStablecoinComputeUnits)
+}
+
+func (*MintStablecoin) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*MintStablecoinResult)(nil)
+
+type MintStablecoinResult struct {
+	Supply uint64 `serialize:"true" json:"supply"`
+	// Balance is the balance of [MintStablecoin.To] after the mint.
+	Balance uint64 `serialize:"true" json:"balance"`
+}
+
+func (*MintStablecoinResult) GetTypeID() uint8 {
+	return mconsts.MintStablecoinID
+}
+
+// BurnStablecoin destroys [Value] of [Asset] from the issuer's balance, as
+// when holders redeem by transferring to the issuer.
+type BurnStablecoin struct {
+	Asset ids.ID `serialize:"true" json:"asset"`
+	Value uint64 `serialize:"true" json:"value"`
+}
+
+func (*BurnStablecoin) GetTypeID() uint8 {
+	return mconsts.BurnStablecoinID
+}
+
+func (b *BurnStablecoin) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.StablecoinKey(b.Asset)):          state.Read,
+		string(storage.AssetKey(b.Asset)):               state.Read | state.Write,
+		string(storage.AssetBalanceKey(actor, b.Asset)): state.Read | state.Write,
+		string(storage.HaltKey()):                       state.Read,
+	}
+}
+
+func (b *BurnStablecoin) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, b, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if b.Value == 0 {
+		return nil, ErrOutputValueZero
+	}
+	s, err := getStablecoin(ctx, mu, b.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if actor != s.Issuer {
+		return nil, ErrNotIssuer
+	}
+	balance, err := storage.SubAssetBalance(ctx, mu, actor, b.Asset, b.Value)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := storage.GetAssetMetadata(ctx, mu, b.Asset)
+	if err != nil {
+		return nil, err
+	}
+	// The issuer's balance is part of the supply, so this cannot underflow.
+	metadata.TotalSupply -= b.Value
+	if err := storage.SetAssetMetadata(ctx, mu, b.Asset, metadata); err != nil {
+		return nil, err
+	}
+	return &BurnStablecoinResult{Supply: metadata.TotalSupply, Balance: balance}, nil
+}
+
+func (*BurnStablecoin) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.BurnStablecoinID, BurnStablecoinComputeUnits)
+}
+
+func (*BurnStablecoin) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*BurnStablecoinResult)(nil)
+
+type BurnStablecoinResult struct {
+	Supply uint64 `serialize:"true" json:"supply"`
+	// Balance is the balance of the issuer after the burn.
+	Balance uint64 `serialize:"true" json:"balance"`
+}
+
+func (*BurnStablecoinResult) GetTypeID() uint8 {
+	return mconsts.BurnStablecoinID
+}
+
+// SetStablecoinBlocked adds [Account] to the blocklist of [Asset], or
+// removes it. Blocked accounts cannot send, receive or be minted the
+// stablecoin with [TransferAsset] or [MintStablecoin].
+type SetStablecoinBlocked struct {
+	Asset   ids.ID        `serialize:"true" json:"asset"`
+	Account codec.Address `serialize:"true" json:"account"`
+	Blocked bool          `serialize:"true" json:"blocked"`
+}
+
+func (*SetStablecoinBlocked) GetTypeID() uint8 {
+	return mconsts.SetStablecoinBlockedID
+}
+
+func (s *SetStablecoinBlocked) StateKeys(codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.StablecoinKey(s.Asset)):                 state.Read,
+		string(storage.StablecoinBlockKey(s.Asset, s.Account)): state.All,
+		string(storage.HaltKey()):                              state.Read,
+	}
+}
+
+func (s *SetStablecoinBlocked) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, s, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	coin, err := getStablecoin(ctx, mu, s.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if actor != coin.Compliance {
+		return nil, ErrNotComplianceRole
+	}
+	if err := storage.SetStablecoinBlocked(ctx, mu, s.Asset, s.Account, s.Blocked); err != nil {
+		return nil, err
+	}
+	return &SetStablecoinBlockedResult{Account: s.Account, Blocked: s.Blocked}, nil
+}
+
+func (*SetStablecoinBlocked) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.SetStablecoinBlockedID, SetStablecoinBlockedComputeUnits)
+}
+
+func (*SetStablecoinBlocked) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*SetStablecoinBlockedResult)(nil)
+
+type SetStablecoinBlockedResult struct {
+	Account codec.Address `serialize:"true" json:"account"`
+	Blocked bool          `serialize:"true" json:"blocked"`
+}
+
+func (*SetStablecoinBlockedResult) GetTypeID() uint8 {
+	return mconsts.SetStablecoinBlockedID
+}
+
+// SetStablecoinPaused stops every transfer of [Asset], and minting, or
+// resumes them. The issuer and the compliance officer may both pause.
+type SetStablecoinPaused struct {
+	Asset  ids.ID `serialize:"true" json:"asset"`
+	Paused bool   `serialize:"true" json:"paused"`
+}
+
+func (*SetStablecoinPaused) GetTypeID() uint8 {
+	return mconsts.SetStablecoinPausedID
+}
+
+func (s *SetStablecoinPaused) StateKeys(codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.StablecoinKey(s.Asset)): state.Read | state.Write,
+		string(storage.HaltKey()):              state.Read,
+	}
+}
+
+func (s *SetStablecoinPaused) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, s, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	coin, err := getStablecoin(ctx, mu, s.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if actor != coin.Issuer && actor != coin.Compliance {
+		return nil, ErrCannotPause
+	}
+	coin.Paused = s.Paused
+	if err := storage.SetStablecoin(ctx, mu, s.Asset, coin); err != nil {
+		return nil, err
+	}
+	return &SetStablecoinPausedResult{Paused: s.Paused}, nil
+}
+
+func (*SetStablecoinPaused) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.SetStablecoinPausedID, SetStablecoinPausedComputeUnits)
+}
+
+func (*SetStablecoinPaused) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*SetStablecoinPausedResult)(nil)
+
+type SetStablecoinPausedResult struct {
+	Paused bool `serialize:"true" json:"paused"`
+}
+
+func (*SetStablecoinPausedResult) GetTypeID() uint8 {
+	return mconsts.SetStablecoinPausedID
+}
+
+func getStablecoin(ctx context.Context, im state.Immutable, assetID ids.ID) (*storage.Stablecoin, error) {
+	s, ok, err := storage.GetStablecoin(ctx, im, assetID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotStablecoin, assetID)
+	}
+	return s, nil
+}
+
+// checkNotBlocked rejects [accounts] on the blocklist of [assetID]. Only
+// stablecoins have blocklists, so accounts of other assets pass.
+func checkNotBlocked(ctx context.Context, im state.Immutable, assetID ids.ID, accounts ...codec.Address) error {
+	if assetID == storage.NativeAsset {
+		return nil
+	}
+	for _, account := range accounts {
+		blocked, err := storage.IsStablecoinBlocked(ctx, im, assetID, account)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return fmt.Errorf("%w: %s", ErrAccountBlocked, account)
+		}
+	}
+	return nil
+}
+
+// addBlockKeys declares the blocklist entries of [accounts] that
+// [checkNotBlocked] reads for [assetID].
+func addBlockKeys(keys state.Keys, assetID ids.ID, accounts ...codec.Address) {
+	if assetID == storage.NativeAsset {
+		return
+	}
+	for _, account := range accounts {
+		keys.Add(string(storage.StablecoinBlockKey(assetID, account)), state.Read)
+	}
+}
+
+// addLegBlockKeys is [addBlockKeys] for the asset of every leg.
+func addLegBlockKeys(keys state.Keys, legs []storage.SwapLeg, accounts ...codec.Address) {
+	for _, leg := range legs {
+		addBlockKeys(keys, leg.Asset, accounts...)
+	}
+}
+
+// checkLegsNotBlocked is [checkNotBlocked] for the asset of every leg.
+func checkLegsNotBlocked(ctx context.Context, im state.Immutable, legs []storage.SwapLeg, accounts ...codec.Address) error {
+	for _, leg := range legs {
+		if err := checkNotBlocked(ctx, im, leg.Asset, accounts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
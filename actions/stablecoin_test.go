@@ -0,0 +1,408 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestStablecoin(t *testing.T) {
+	issuer := codectest.NewRandomAddress()
+	attestor := codectest.NewRandomAddress()
+	compliance := codectest.NewRandomAddress()
+	holder := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+	report := ids.GenerateTestID()
+
+	// issued has [holder] hold 10 of a stablecoin with [reserves] of backing,
+	// and the issuer hold 5.
+	issued := func(reserves uint64, paused bool, blocked ...codec.Address) state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.CreateAsset(ctx, store, asset, issuer))
+		require.NoError(t, storage.SetAssetMetadata(ctx, store, asset, storage.AssetMetadata{Symbol: "MUSD", TotalSupply: 15}))
+		require.NoError(t, storage.SetAssetBalance(ctx, store, holder, asset, 10))
+		require.NoError(t, storage.SetAssetBalance(ctx, store, issuer, asset, 5))
+		require.NoError(t, storage.SetStablecoin(ctx, store, asset, &storage.Stablecoin{
+			Issuer:     issuer,
+			Attestor:   attestor,
+			Compliance: compliance,
+			Paused:     paused,
+			Reserves:   reserves,
+		}))
+		for _, account := range blocked {
+			require.NoError(t, storage.SetStablecoinBlocked(ctx, store, asset, account, true))
+		}
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "Create",
+			Actor: issuer,
+			Action: &CreateStablecoin{
+				Asset:      asset,
+				Symbol:     "MUSD",
+				Decimals:   6,
+				Attestor:   attestor,
+				Compliance: compliance,
+			},
+			State: chaintest.NewInMemoryStore(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				s, ok, err := storage.GetStablecoin(ctx, store, asset)
+				require.NoError(t, err)
+				require.True(t, ok)
+				require.Equal(t, &storage.Stablecoin{Issuer: issuer, Attestor: attestor, Compliance: compliance}, s)
+			},
+			ExpectedOutputs: &CreateStablecoinResult{Asset: asset, Issuer: issuer},
+		},
+		{
+			Name:  "CreateWithoutAttestor",
+			Actor: issuer,
+			Action: &CreateStablecoin{
+				Asset:      asset,
+				Compliance: compliance,
+			},
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: ErrEmptyStablecoinRole,
+		},
+		{
+			Name:  "Attest",
+			Actor: attestor,
+			Action: &AttestReserves{
+				Asset:    asset,
+				Reserves: 100,
+				Report:   report,
+			},
+			State:     issued(15, false),
+			Timestamp: 1_000,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				s, _, err := storage.GetStablecoin(ctx, store, asset)
+				require.NoError(t, err)
+				require.Equal(t, uint64(100), s.Reserves)
+				require.Equal(t, report, s.Report)
+				require.Equal(t, int64(1_000), s.AttestedAt)
+			},
+			ExpectedOutputs: &AttestReservesResult{Supply: 15, Reserves: 100},
+		},
+		{
+			Name:  "OnlyAttestorAttests",
+			Actor: issuer,
+			Action: &AttestReserves{
+				Asset:    asset,
+				Reserves: 100,
+			},
+			State:       issued(15, false),
+			ExpectedErr: ErrNotAttestor,
+		},
+		{
+			Name:  "NotStablecoin",
+			Actor: issuer,
+			Action: &MintStablecoin{
+				Asset: ids.GenerateTestID(),
+				To:    holder,
+				Value: 1,
+			},
+			State:       issued(15, false),
+			ExpectedErr: ErrNotStablecoin,
+		},
+		{
+			Name:  "MintWithinReserves",
+			Actor: issuer,
+			Action: &MintStablecoin{
+				Asset: asset,
+				To:    holder,
+				Value: 5,
+			},
+			State: issued(20, false),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				m, err := storage.GetAssetMetadata(ctx, store, asset)
+				require.NoError(t, err)
+				require.Equal(t, uint64(20), m.TotalSupply)
+			},
+			ExpectedOutputs: &MintStablecoinResult{Supply: 20, Balance: 15},
+		},
+		{
+			Name:  "MintToNewHolder",
+			Actor: issuer,
+			Action: &MintStablecoin{
+				Asset: asset,
+				To:    attestor,
+				Value: 5,
+			},
+			State:           issued(20, false),
+			ExpectedOutputs: &MintStablecoinResult{Supply: 20, Balance: 5},
+		},
+		{
+			Name:  "MintExceedsReserves",
+			Actor: issuer,
+			Action: &MintStablecoin{
+				Asset: asset,
+				To:    holder,
+				Value: 6,
+			},
+			State:       issued(20, false),
+			ExpectedErr: ErrExceedsReserves,
+		},
+		{
+			Name:  "OnlyIssuerMints",
+			Actor: attestor,
+			Action: &MintStablecoin{
+				Asset: asset,
+				To:    holder,
+				Value: 1,
+			},
+			State:       issued(20, false),
+			ExpectedErr: ErrNotIssuer,
+		},
+		{
+			Name:  "MintToBlocked",
+			Actor: issuer,
+			Action: &MintStablecoin{
+				Asset: asset,
+				To:    holder,
+				Value: 1,
+			},
+			State:       issued(20, false, holder),
+			ExpectedErr: ErrAccountBlocked,
+		},
+		{
+			Name:  "MintPaused",
+			Actor: issuer,
+			Action: &MintStablecoin{
+				Asset: asset,
+				To:    holder,
+				Value: 1,
+			},
+			State:       issued(20, true),
+			ExpectedErr: ErrStablecoinPaused,
+		},
+		{
+			Name:  "Burn",
+			Actor: issuer,
+			Action: &BurnStablecoin{
+				Asset: asset,
+				Value: 5,
+			},
+			State:           issued(15, false),
+			ExpectedOutputs: &BurnStablecoinResult{Supply: 10, Balance: 0},
+		},
+		{
+			Name:  "Block",
+			Actor: compliance,
+			Action: &SetStablecoinBlocked{
+				Asset:   asset,
+				Account: holder,
+				Blocked: true,
+			},
+			State: issued(15, false),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				blocked, err := storage.IsStablecoinBlocked(ctx, store, asset, holder)
+				require.NoError(t, err)
+				require.True(t, blocked)
+			},
+			ExpectedOutputs: &SetStablecoinBlockedResult{Account: holder, Blocked: true},
+		},
+		{
+			Name:  "OnlyComplianceBlocks",
+			Actor: issuer,
+			Action: &SetStablecoinBlocked{
+				Asset:   asset,
+				Account: holder,
+				Blocked: true,
+			},
+			State:       issued(15, false),
+			ExpectedErr: ErrNotComplianceRole,
+		},
+		{
+			Name:  "TransferFromBlocked",
+			Actor: holder,
+			Action: &TransferAsset{
+				To:    issuer,
+				Asset: asset,
+				Value: 1,
+			},
+			State:       issued(15, false, holder),
+			ExpectedErr: ErrAccountBlocked,
+		},
+		{
+			Name:  "TransferToBlocked",
+			Actor: issuer,
+			Action: &TransferAsset{
+				To:    holder,
+				Asset: asset,
+				Value: 1,
+			},
+			State:       issued(15, false, holder),
+			ExpectedErr: ErrAccountBlocked,
+		},
+		{
+			Name:  "Pause",
+			Actor: compliance,
+			Action: &SetStablecoinPaused{
+				Asset:  asset,
+				Paused: true,
+			},
+			State:           issued(15, false),
+			ExpectedOutputs: &SetStablecoinPausedResult{Paused: true},
+		},
+		{
+			Name:  "HolderCannotPause",
+			Actor: holder,
+			Action: &SetStablecoinPaused{
+				Asset:  asset,
+				Paused: true,
+			},
+			State:       issued(15, false),
+			ExpectedErr: ErrCannotPause,
+		},
+		{
+			Name:  "TransferPaused",
+			Actor: holder,
+			Action: &TransferAsset{
+				To:    issuer,
+				Asset: asset,
+				Value: 1,
+			},
+			State:       issued(15, true),
+			ExpectedErr: ErrStablecoinPaused,
+		},
+		{
+			Name:  "SwapPaused",
+			Actor: holder,
+			Action: &ProposeSwap{
+				SwapID:       ids.GenerateTestID(),
+				Counterparty: issuer,
+				Offer:        []storage.SwapLeg{{Asset: asset, Amount: 1}},
+				Want:         []storage.SwapLeg{{Asset: storage.NativeAsset, Amount: 1}},
+				Expiry:       10_000,
+			},
+			State:       issued(15, true),
+			ExpectedErr: ErrStablecoinPaused,
+		},
+	}
+
+	runActionTests(t, tests)
+}
+
+func TestStablecoinBlockedTrades(t *testing.T) {
+	holder := codectest.NewRandomAddress()
+	blocked := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+	native := storage.NativeAsset
+	swapID := ids.GenerateTestID()
+	offer := []storage.SwapLeg{{Asset: asset, Amount: 1}}
+	want := []storage.SwapLeg{{Asset: native, Amount: 1}}
+
+	// listed has [blocked] on the blocklist of the stablecoin [asset], an
+	// order and a swap it made, both to be taken by [holder].
+	listed := func() state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetStablecoin(ctx, store, asset, &storage.Stablecoin{Issuer: holder}))
+		require.NoError(t, storage.SetStablecoinBlocked(ctx, store, asset, blocked, true))
+		require.NoError(t, storage.SetOrder(ctx, store, asset, native, storage.OrderID(blocked, 0), &storage.Order{
+			Maker:      blocked,
+			SellAmount: 1,
+			BuyAmount:  1,
+			Remaining:  1,
+		}))
+		require.NoError(t, storage.SetSwap(ctx, store, swapID, &storage.Swap{
+			Proposer:     blocked,
+			Counterparty: holder,
+			Expiry:       1,
+			Offer:        offer,
+			Want:         want,
+		}))
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:        "CreateOrderBlockedMaker",
+			Actor:       blocked,
+			Action:      &CreateOrder{SellAsset: native, SellAmount: 1, BuyAsset: asset, BuyAmount: 1},
+			State:       listed(),
+			ExpectedErr: ErrAccountBlocked,
+		},
+		{
+			Name:  "FillOrderBlockedMaker",
+			Actor: holder,
+			Action: &FillOrder{
+				OrderID:   storage.OrderID(blocked, 0),
+				SellAsset: asset,
+				BuyAsset:  native,
+				Maker:     blocked,
+				Amount:    1,
+			},
+			State:       listed(),
+			ExpectedErr: ErrAccountBlocked,
+		},
+		{
+			Name:  "ProposeSwapBlockedCounterparty",
+			Actor: holder,
+			Action: &ProposeSwap{
+				SwapID:       ids.GenerateTestID(),
+				Counterparty: blocked,
+				Offer:        want,
+				Want:         offer,
+				Expiry:       1,
+			},
+			State:       listed(),
+			ExpectedErr: ErrAccountBlocked,
+		},
+		{
+			Name:  "AcceptSwapBlockedProposer",
+			Actor: holder,
+			Action: &AcceptSwap{
+				SwapID:   swapID,
+				Proposer: blocked,
+				Offer:    offer,
+				Want:     want,
+			},
+			State:       listed(),
+			ExpectedErr: ErrAccountBlocked,
+		},
+		{
+			Name:        "PoolSwap",
+			Actor:       blocked,
+			Action:      &Swap{AssetIn: native, AmountIn: 1, AssetOut: asset},
+			State:       listed(),
+			ExpectedErr: ErrAccountBlocked,
+		},
+		{
+			Name:        "CreatePool",
+			Actor:       blocked,
+			Action:      &CreatePool{AssetA: native, AssetB: asset, AmountA: 10_000, AmountB: 10_000},
+			State:       listed(),
+			ExpectedErr: ErrAccountBlocked,
+		},
+		{
+			Name:        "RemoveLiquidity",
+			Actor:       blocked,
+			Action:      &RemoveLiquidity{AssetA: native, AssetB: asset, Shares: 1},
+			State:       listed(),
+			ExpectedErr: ErrAccountBlocked,
+		},
+		{
+			Name:        "PlaceBid",
+			Actor:       blocked,
+			Action:      &PlaceBid{Asset: asset, Amount: 1},
+			State:       listed(),
+			ExpectedErr: ErrAccountBlocked,
+		},
+	}
+
+	runActionTests(t, tests)
+}
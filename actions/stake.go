@@ -0,0 +1,395 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const (
+	StakeComputeUnits = 1
+
+	// StakingRewardRate and StakingEpochLength apply when the rules do not
+	// set [StakingRewardRateRule] and [StakingEpochLengthRule].
+	StakingRewardRate  = 10
+	StakingEpochLength = 1_000
+)
+
+// Keys of the staking rules, read with chain.Rules.FetchCustom.
+const (
+	// StakingRewardRateRule is the reward paid on a stake each epoch, in
+	// basis points.
+	StakingRewardRateRule = "stakingRewardRate"
+	// StakingEpochLengthRule is the number of blocks in an epoch.
+	StakingEpochLengthRule = "stakingEpochLength"
+)
+
+var (
+	ErrStakeNotFound     = errors.New("stake not found")
+	ErrInsufficientStake = errors.New("amount exceeds stake")
+	ErrNoRewards         = errors.New("no rewards to claim")
+
+	_ chain.Action = (*Stake)(nil)
+	_ chain.Action = (*Unstake)(nil)
+	_ chain.Action = (*ClaimRewards)(nil)
+)
+
+// Stake locks [Amount] of the actor's native tokens. The stake earns the
+// reward rate for every epoch it is held in full, starting with the epoch
+// after the one it is staked in.
+type Stake struct {
+	Amount uint64 `serialize:"true" json:"amount"`
+}
+
+func (*Stake) GetTypeID() uint8 {
+	return mconsts.StakeID
+}
+
+func (*Stake) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.BalanceKey(actor)):                state.Read | state.Write,
+		string(storage.StakeKey(actor)):                  state.All,
+		string(chain.HeightKey(storage.HeightKey())):     state.Read,
+		string(storage.ActiveProposalKey()):              state.Read,
+		string(storage.ConfigKey(StakingRewardRateRule)): state.Read,
+		string(storage.HaltKey()):                        state.Read,
+	}
+}
+
+func (s *Stake) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, s, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if s.Amount == 0 {
+		return nil, ErrOutputValueZero
+	}
+	stake, _, err := accrue(ctx, r, mu, actor)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := storage.SubBalance(ctx, mu, actor, s.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if stake.Pending, err = smath.Add(stake.Pending, s.Amount); err != nil {
+		return nil, err
+	}
+	if err := storage.SetStake(ctx, mu, actor, stake); err != nil {
+		return nil, err
+	}
+	return &StakeResult{
+		Staked:  stake.Staked(),
+		Rewards: stake.Rewards,
+		Balance: balance,
+	}, nil
+}
+
+func (*Stake) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.StakeID, StakeComputeUnits)
+}
+
+func (*Stake) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*StakeResult)(nil)
+
+type StakeResult struct {
+	Staked uint64 `serialize:"true" json:"staked"`
+	// Rewards have accrued and are not claimed yet.
+	Rewards uint64 `serialize:"true" json:"rewards"`
+	Balance uint64 `serialize:"true" json:"balance"`
+}
+
+func (*StakeResult) GetTypeID() uint8 {
+	return mconsts.StakeID
+}
+
+// Unstake returns [Amount] of the actor's stake to its balance, taking the
+// stake that is not earning yet first. Rewards accrued so far are kept until
+// claimed; the current epoch, not held in full, earns nothing.
+type Unstake struct {
+	Amount uint64 `serialize:"true" json:"amount"`
+}
+
+func (*Unstake) GetTypeID() uint8 {
+	return mconsts.UnstakeID
+}
+
+func (*Unstake) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.StakeKey(actor)):                  state.Read | state.Write,
+		string(storage.BalanceKey(actor)):                state.All,
+		string(chain.HeightKey(storage.HeightKey())):     state.Read,
+		string(storage.ActiveProposalKey()):              state.Read,
+		string(storage.ConfigKey(StakingRewardRateRule)): state.Read,
+		string(storage.HaltKey()):                        state.Read,
+	}
+}
+
+func (u *Unstake) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, u, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if u.Amount == 0 {
+		return nil, ErrOutputValueZero
+	}
+	stake, exists, err := accrue(ctx, r, mu, actor)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrStakeNotFound
+	}
+	if u.Amount > stake.Staked() {
+		return nil, ErrInsufficientStake
+	}
+	pending := min(stake.Pending, u.Amount)
+	stake.Pending -= pending
+	stake.Amount -= u.Amount - pending
+	if err := putStake(ctx, mu, actor, stake); err != nil {
+		return nil, err
+	}
+	balance, err := storage.AddBalance(ctx, mu, actor, u.Amount, true)
+	if err != nil {
+		return nil, err
+	}
+	return &UnstakeResult{
+		Staked:  stake.Staked(),
+		Rewards: stake.Rewards,
+		Balance: balance,
+	}, nil
+}
+
+func (*Unstake) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.UnstakeID, StakeComputeUnits)
+}
+
+func (*Unstake) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*UnstakeResult)(nil)
+
+type UnstakeResult struct {
+	Staked  uint64 `serialize:"true" json:"staked"`
+	Rewards uint64 `serialize:"true" json:"rewards"`
+	Balance uint64 `serialize:"true" json:"balance"`
+}
+
+func (*UnstakeResult) GetTypeID() uint8 {
+	return mconsts.UnstakeID
+}
+
+// ClaimRewards mints the rewards accrued by the actor's stake to its
+// balance.
+type ClaimRewards struct{}
+
+func (*ClaimRewards) GetTypeID() uint8 {
+	return mconsts.ClaimRewardsID
+}
+
+func (*ClaimRewards) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.StakeKey(actor)):                  state.Read | state.Write,
+		string(storage.BalanceKey(actor)):                state.All,
+		string(chain.HeightKey(storage.HeightKey())):     state.Read,
+		string(storage.ActiveProposalKey()):              state.Read,
+		string(storage.ConfigKey(StakingRewardRateRule)): state.Read,
+		string(storage.HaltKey()):                        state.Read,
+	}
+}
+
+func (a *ClaimRewards) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, a, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	stake, _, err := accrue(ctx, r, mu, actor)
+	if err != nil {
+		return nil, err
+	}
+	if stake.Rewards == 0 {
+		return nil, ErrNoRewards
+	}
+	rewards := stake.Rewards
+	stake.Rewards = 0
+	if err := putStake(ctx, mu, actor, stake); err != nil {
+		return nil, err
+	}
+	balance, err := storage.AddBalance(ctx, mu, actor, rewards, true)
+	if err != nil {
+		return nil, err
+	}
+	return &ClaimRewardsResult{
+		Rewards: rewards,
+		Balance: balance,
+	}, nil
+}
+
+func (*ClaimRewards) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.ClaimRewardsID, StakeComputeUnits)
+}
+
+func (*ClaimRewards) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*ClaimRewardsResult)(nil)
+
+type ClaimRewardsResult struct {
+	Rewards uint64 `serialize:"true" json:"rewards"`
+	Balance uint64 `serialize:"true" json:"balance"`
+}
+
+func (*ClaimRewardsResult) GetTypeID() uint8 {
+	return mconsts.ClaimRewardsID
+}
+
+// StakingRules returns the reward rate and epoch length of [r].
+func StakingRules(r chain.Rules) (uint64, uint64) {
+	rate, epochLength := uint64(StakingRewardRate), uint64(StakingEpochLength)
+	if r == nil {
+		return rate, epochLength
+	}
+	if v, ok := r.FetchCustom(StakingRewardRateRule); ok {
+		if n, ok := v.(uint64); ok {
+			rate = n
+		}
+	}
+	if v, ok := r.FetchCustom(StakingEpochLengthRule); ok {
+		if n, ok := v.(uint64); ok && n > 0 {
+			epochLength = n
+		}
+	}
+	return rate, epochLength
+}
+
+// StakingEpoch returns the epoch of the block at [height] under [r].
+func StakingEpoch(r chain.Rules, height uint64) uint64 {
+	_, epochLength := StakingRules(r)
+	return height / epochLength
+}
+
+// PendingRewards returns the rewards of [stake] once it is accrued up to
+// [epoch] at [rate] basis points per epoch held in full. It does not modify
+// [stake].
+func PendingRewards(rate uint64, stake *storage.Stake, epoch uint64) (uint64, error) {
+	// A longer epoch in new rules can move the epoch back, which delays
+	// rewards until the recorded epoch is reached again.
+	if epoch <= stake.Epoch {
+		return stake.Rewards, nil
+	}
+	// The amount was held through the epochs since [stake.Epoch] began, the
+	// pending stake only through those after it.
+	rewards := stake.Rewards
+	for _, held := range []struct{ amount, epochs uint64 }{
+		{stake.Amount, epoch - stake.Epoch},
+		{stake.Pending, epoch - stake.Epoch - 1},
+	} {
+		basisPoints, err := smath.Mul(rate, held.epochs)
+		if err != nil {
+			return 0, err
+		}
+		reward, err := mulDiv(held.amount, basisPoints, 10_000)
+		if err != nil {
+			return 0, err
+		}
+		if rewards, err = smath.Add(rewards, reward); err != nil {
+			return 0, err
+		}
+	}
+	return rewards, nil
+}
+
+// accrue returns the stake of [actor] with its rewards accrued up to the
+// epoch of the executing block, at the reward rate governance set if any.
+// If there is no stake, an empty one starting at that epoch is returned.
+func accrue(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	actor codec.Address,
+) (*storage.Stake, bool, error) {
+	parent, err := storage.GetHeight(ctx, mu)
+	if err != nil {
+		return nil, false, err
+	}
+	epoch := StakingEpoch(r, parent+1)
+	stake, exists, err := storage.GetStake(ctx, mu, actor)
+	if err != nil {
+		return nil, false, err
+	}
+	if !exists {
+		return &storage.Stake{Epoch: epoch}, false, nil
+	}
+	rate, err := configUint64(ctx, r, mu, StakingRewardRateRule)
+	if err != nil {
+		return nil, false, err
+	}
+	if stake.Rewards, err = PendingRewards(rate, stake, epoch); err != nil {
+		return nil, false, err
+	}
+	if epoch > stake.Epoch {
+		// The pending stake was held through the start of [epoch].
+		stake.Amount += stake.Pending
+		stake.Pending = 0
+		stake.Epoch = epoch
+	}
+	return stake, true, nil
+}
+
+// putStake stores [stake], or removes it once nothing is left in it.
+func putStake(ctx context.Context, mu state.Mutable, actor codec.Address, stake *storage.Stake) error {
+	if stake.Staked() == 0 && stake.Rewards == 0 {
+		return storage.DeleteStake(ctx, mu, actor)
+	}
+	return storage.SetStake(ctx, mu, actor, stake)
+}
@@ -0,0 +1,255 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// stakingRules sets the staking rules read through FetchCustom.
+type stakingRules struct {
+	*genesis.Rules
+	rate        uint64
+	epochLength uint64
+}
+
+func (r *stakingRules) FetchCustom(key string) (any, bool) {
+	switch key {
+	case StakingRewardRateRule:
+		return r.rate, true
+	case StakingEpochLengthRule:
+		return r.epochLength, true
+	default:
+		return nil, false
+	}
+}
+
+func TestStakeActions(t *testing.T) {
+	staker := codectest.NewRandomAddress()
+	// Stakes earn 1% per epoch of 10 blocks. The parent is at height 29, so
+	// the actions run in epoch 3.
+	rules := &stakingRules{Rules: genesis.NewDefaultRules(), rate: 100, epochLength: 10}
+
+	// funded holds 1M native tokens for [staker], and a stake of 100,000
+	// recorded at [epoch] if it is non-zero.
+	funded := func(epoch uint64) state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, store.Insert(ctx, chain.HeightKey(storage.HeightKey()), binary.BigEndian.AppendUint64(nil, 29)))
+		require.NoError(t, storage.SetBalance(ctx, store, staker, 1_000_000))
+		if epoch > 0 {
+			require.NoError(t, storage.SetStake(ctx, store, staker, &storage.Stake{Amount: 100_000, Epoch: epoch}))
+		}
+		return store
+	}
+	// staked is [funded] with no native balance left to [staker].
+	staked := func(epoch uint64) state.Mutable {
+		store := funded(epoch)
+		require.NoError(t, store.Remove(context.Background(), storage.BalanceKey(staker)))
+		return store
+	}
+	requireStake := func(ctx context.Context, t *testing.T, store state.Mutable, want *storage.Stake) {
+		stake, exists, err := storage.GetStake(ctx, store, staker)
+		require.NoError(t, err)
+		require.Equal(t, want != nil, exists)
+		require.Equal(t, want, stake)
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:        "StakeZero",
+			Actor:       staker,
+			Action:      &Stake{},
+			Rules:       rules,
+			State:       funded(0),
+			ExpectedErr: ErrOutputValueZero,
+		},
+		{
+			Name:        "StakeMoreThanBalance",
+			Actor:       staker,
+			Action:      &Stake{Amount: 1_000_001},
+			Rules:       rules,
+			State:       funded(0),
+			ExpectedErr: storage.ErrInvalidBalance,
+		},
+		{
+			Name:   "Stake",
+			Actor:  staker,
+			Action: &Stake{Amount: 600_000},
+			Rules:  rules,
+			State:  funded(0),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireStake(ctx, t, store, &storage.Stake{Pending: 600_000, Epoch: 3})
+			},
+			ExpectedOutputs: &StakeResult{Staked: 600_000, Balance: 400_000},
+		},
+		{
+			// The stake is accrued for epochs 1 and 2 before it grows. The
+			// new stake earns from epoch 4.
+			Name:   "StakeMore",
+			Actor:  staker,
+			Action: &Stake{Amount: 600_000},
+			Rules:  rules,
+			State:  funded(1),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireStake(ctx, t, store, &storage.Stake{Amount: 100_000, Pending: 600_000, Rewards: 2_000, Epoch: 3})
+			},
+			ExpectedOutputs: &StakeResult{Staked: 700_000, Rewards: 2_000, Balance: 400_000},
+		},
+		{
+			Name:        "UnstakeNotFound",
+			Actor:       staker,
+			Action:      &Unstake{Amount: 1},
+			Rules:       rules,
+			State:       funded(0),
+			ExpectedErr: ErrStakeNotFound,
+		},
+		{
+			Name:        "UnstakeMoreThanStaked",
+			Actor:       staker,
+			Action:      &Unstake{Amount: 100_001},
+			Rules:       rules,
+			State:       funded(1),
+			ExpectedErr: ErrInsufficientStake,
+		},
+		{
+			Name:   "Unstake",
+			Actor:  staker,
+			Action: &Unstake{Amount: 100_000},
+			Rules:  rules,
+			State:  funded(1),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireStake(ctx, t, store, &storage.Stake{Rewards: 2_000, Epoch: 3})
+			},
+			ExpectedOutputs: &UnstakeResult{Rewards: 2_000, Balance: 1_100_000},
+		},
+		{
+			// Nothing has accrued within the epoch of the stake.
+			Name:   "UnstakeAll",
+			Actor:  staker,
+			Action: &Unstake{Amount: 100_000},
+			Rules:  rules,
+			State:  funded(3),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireStake(ctx, t, store, nil)
+			},
+			ExpectedOutputs: &UnstakeResult{Balance: 1_100_000},
+		},
+		{
+			// The stake opens the balance it returns to.
+			Name:   "UnstakeToEmptyBalance",
+			Actor:  staker,
+			Action: &Unstake{Amount: 100_000},
+			Rules:  rules,
+			State:  staked(1),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireStake(ctx, t, store, &storage.Stake{Rewards: 2_000, Epoch: 3})
+			},
+			ExpectedOutputs: &UnstakeResult{Rewards: 2_000, Balance: 100_000},
+		},
+		{
+			Name:        "NoRewards",
+			Actor:       staker,
+			Action:      &ClaimRewards{},
+			Rules:       rules,
+			State:       funded(3),
+			ExpectedErr: ErrNoRewards,
+		},
+		{
+			Name:   "ClaimRewards",
+			Actor:  staker,
+			Action: &ClaimRewards{},
+			Rules:  rules,
+			State:  funded(1),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireStake(ctx, t, store, &storage.Stake{Amount: 100_000, Epoch: 3})
+				balance, err := storage.GetBalance(ctx, store, staker)
+				require.NoError(t, err)
+				require.Equal(t, uint64(1_002_000), balance)
+			},
+			ExpectedOutputs: &ClaimRewardsResult{Rewards: 2_000, Balance: 1_002_000},
+		},
+		{
+			Name:   "ClaimRewardsToEmptyBalance",
+			Actor:  staker,
+			Action: &ClaimRewards{},
+			Rules:  rules,
+			State:  staked(1),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				requireStake(ctx, t, store, &storage.Stake{Amount: 100_000, Epoch: 3})
+			},
+			ExpectedOutputs: &ClaimRewardsResult{Rewards: 2_000, Balance: 2_000},
+		},
+	}
+
+	runActionTests(t, tests)
+}
+
+func TestStakeEpochBoundary(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	staker := codectest.NewRandomAddress()
+	rules := &stakingRules{Rules: genesis.NewDefaultRules(), rate: 100, epochLength: 10}
+	store := chaintest.NewInMemoryStore()
+	require.NoError(storage.SetBalance(ctx, store, staker, 1_000_000))
+	// execute runs [action] in the block after [parent].
+	execute := func(parent uint64, action chain.Action) codec.Typed {
+		require.NoError(store.Insert(ctx, chain.HeightKey(storage.HeightKey()), binary.BigEndian.AppendUint64(nil, parent)))
+		output, err := action.Execute(ctx, rules, store, 0, staker, ids.Empty)
+		require.NoError(err)
+		return output
+	}
+
+	// A stake made in the last block of epoch 1 and withdrawn in the first
+	// of epoch 2 was not held through an epoch, so it earns nothing.
+	execute(18, &Stake{Amount: 500_000})
+	require.Equal(&UnstakeResult{Balance: 1_000_000}, execute(19, &Unstake{Amount: 500_000}))
+	_, exists, err := storage.GetStake(ctx, store, staker)
+	require.NoError(err)
+	require.False(exists)
+
+	// Topping up just before a boundary only pays on the stake held through
+	// the epoch before it.
+	execute(20, &Stake{Amount: 100_000})
+	execute(38, &Stake{Amount: 400_000})
+	require.Equal(&ClaimRewardsResult{Rewards: 1_000, Balance: 501_000}, execute(39, &ClaimRewards{}))
+	require.Equal(&UnstakeResult{Balance: 1_001_000}, execute(39, &Unstake{Amount: 500_000}))
+}
+
+func TestPendingRewards(t *testing.T) {
+	require := require.New(t)
+	stake := &storage.Stake{Amount: 100_000, Pending: 50_000, Rewards: 5, Epoch: 4}
+
+	// The pending stake misses epoch 4, which it was not held through.
+	rewards, err := PendingRewards(100, stake, 6)
+	require.NoError(err)
+	require.Equal(uint64(2_505), rewards)
+
+	// An earlier epoch pays nothing more.
+	rewards, err = PendingRewards(100, stake, 2)
+	require.NoError(err)
+	require.Equal(uint64(5), rewards)
+
+	_, err = PendingRewards(100, stake, math.MaxUint64)
+	require.Error(err)
+
+	rate, epochLength := StakingRules(nil)
+	require.Equal(uint64(StakingRewardRate), rate)
+	require.Equal(uint64(StakingEpochLength), epochLength)
+	require.Equal(uint64(2), StakingEpoch(nil, 2*StakingEpochLength+1))
+}
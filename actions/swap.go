@@ -0,0 +1,426 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+// SwapLegComputeUnits is charged per leg moved by a swap action.
+const SwapLegComputeUnits = 1
+
+var (
+	ErrInvalidSwapLegs    = errors.New("swap sides must have 1 to 4 legs of distinct assets")
+	ErrSelfSwap           = errors.New("counterparty must not be the proposer")
+	ErrSwapExists         = errors.New("swap already exists")
+	ErrSwapNotFound       = errors.New("swap not found")
+	ErrSwapExpired        = errors.New("swap expired")
+	ErrSwapNotExpired     = errors.New("swap has not expired")
+	ErrSwapMismatch       = errors.New("swap does not match")
+	ErrNotSwapCounterpart = errors.New("actor is not the swap counterparty")
+
+	_ chain.Action = (*ProposeSwap)(nil)
+	_ chain.Action = (*AcceptSwap)(nil)
+	_ chain.Action = (*RefundSwap)(nil)
+)
+
+// ProposeSwap moves [Offer] from the actor into the escrow of [SwapID], to
+// be exchanged for [Want] if [Counterparty] accepts before [Expiry].
+type ProposeSwap struct {
+	// SwapID is chosen by the proposer and must not be open.
+	SwapID       ids.ID            `serialize:"true" json:"swap_id"`
+	Counterparty codec.Address     `serialize:"true" json:"counterparty"`
+	Offer        []storage.SwapLeg `serialize:"true" json:"offer"`
+	Want         []storage.SwapLeg `serialize:"true" json:"want"`
+	// Expiry is the last timestamp, in milliseconds, at which the swap can
+	// be accepted. After it, anyone can return the offer with [RefundSwap].
+	Expiry int64 `serialize:"true" json:"expiry"`
+}
+
+func (*ProposeSwap) GetTypeID() uint8 {
+	return mconsts.ProposeSwapID
+}
+
+func (p *ProposeSwap) StateKeys(actor codec.Address) state.Keys {
+	keys := state.Keys{string(storage.SwapKey(p.SwapID)): state.All}
+	escrow := storage.EscrowAddress(p.SwapID)
+	for _, leg := range p.Offer {
+		addLegKeys(keys, leg, actor, escrow)
+	}
+	addLegHookKeys(keys, actor, p.Offer)
+	addLegBlockKeys(keys, p.Offer, actor, p.Counterparty)
+	addLegBlockKeys(keys, p.Want, actor, p.Counterparty)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (p *ProposeSwap) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, p, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if !validLegs(p.Offer) || !validLegs(p.Want) {
+		return nil, ErrInvalidSwapLegs
+	}
+	if p.Counterparty == actor {
+		return nil, ErrSelfSwap
+	}
+	if p.Expiry < timestamp {
+		return nil, ErrSwapExpired
+	}
+	if err := checkLegsNotBlocked(ctx, mu, p.Offer, actor, p.Counterparty); err != nil {
+		return nil, err
+	}
+	if err := checkLegsNotBlocked(ctx, mu, p.Want, actor, p.Counterparty); err != nil {
+		return nil, err
+	}
+	_, exists, err := storage.GetSwap(ctx, mu, p.SwapID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrSwapExists
+	}
+	escrow := storage.EscrowAddress(p.SwapID)
+	if err := runLegHooks(ctx, r, mu, actor, p.Offer); err != nil {
+		return nil, err
+	}
+	if err := moveLegs(ctx, mu, actor, escrow, p.Offer); err != nil {
+		return nil, err
+	}
+	if err := storage.SetSwap(ctx, mu, p.SwapID, &storage.Swap{
+		Proposer:     actor,
+		Counterparty: p.Counterparty,
+		Expiry:       p.Expiry,
+		Offer:        p.Offer,
+		Want:         p.Want,
+	}); err != nil {
+		return nil, err
+	}
+	return &ProposeSwapResult{
+		Escrow: escrow,
+		Expiry: p.Expiry,
+	}, nil
+}
+
+func (p *ProposeSwap) ComputeUnits(r chain.Rules) uint64 {
+	return uint64(len(p.Offer)) * baseComputeUnits(r, mconsts.ProposeSwapID, SwapLegComputeUnits)
+}
+
+func (*ProposeSwap) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*ProposeSwapResult)(nil)
+
+type ProposeSwapResult struct {
+	Escrow codec.Address `serialize:"true" json:"escrow"`
+	Expiry int64         `serialize:"true" json:"expiry"`
+}
+
+func (*ProposeSwapResult) GetTypeID() uint8 {
+	return mconsts.ProposeSwapID
+}
+
+// AcceptSwap pays [Want] from the actor to the proposer and releases the
+// escrowed [Offer] to the actor, in one step. Only the named counterparty
+// can accept, and only until the expiry.
+type AcceptSwap struct {
+	SwapID ids.ID `serialize:"true" json:"swap_id"`
+	// Proposer, Offer and Want must match the swap, so the balances it moves
+	// can be declared in [StateKeys].
+	Proposer codec.Address     `serialize:"true" json:"proposer"`
+	Offer    []storage.SwapLeg `serialize:"true" json:"offer"`
+	Want     []storage.SwapLeg `serialize:"true" json:"want"`
+}
+
+func (*AcceptSwap) GetTypeID() uint8 {
+	return mconsts.AcceptSwapID
+}
+
+func (a *AcceptSwap) StateKeys(actor codec.Address) state.Keys {
+	keys := state.Keys{string(storage.SwapKey(a.SwapID)): state.Read | state.Write}
+	addTakenKeys(keys, storage.SwapKey(a.SwapID))
+	for _, leg := range a.Offer {
+		addLegKeys(keys, leg, storage.EscrowAddress(a.SwapID), actor)
+	}
+	for _, leg := range a.Want {
+		addLegKeys(keys, leg, actor, a.Proposer)
+	}
+	addLegHookKeys(keys, actor, a.Offer)
+	addLegHookKeys(keys, actor, a.Want)
+	addLegBlockKeys(keys, a.Offer, actor, a.Proposer)
+	addLegBlockKeys(keys, a.Want, actor, a.Proposer)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (a *AcceptSwap) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, a, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	swap, err := openSwap(ctx, mu, a.SwapID, a.Proposer, a.Offer)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Equal(swap.Want, a.Want) {
+		return nil, ErrSwapMismatch
+	}
+	if swap.Counterparty != actor {
+		return nil, ErrNotSwapCounterpart
+	}
+	if swap.Expiry < timestamp {
+		return nil, ErrSwapExpired
+	}
+	if err := checkLegsNotBlocked(ctx, mu, swap.Offer, actor, swap.Proposer); err != nil {
+		return nil, err
+	}
+	if err := checkLegsNotBlocked(ctx, mu, swap.Want, actor, swap.Proposer); err != nil {
+		return nil, err
+	}
+	if err := runLegHooks(ctx, r, mu, actor, swap.Want); err != nil {
+		return nil, err
+	}
+	if err := moveLegs(ctx, mu, actor, swap.Proposer, swap.Want); err != nil {
+		return nil, err
+	}
+	if err := runLegHooks(ctx, r, mu, actor, swap.Offer); err != nil {
+		return nil, err
+	}
+	if err := moveLegs(ctx, mu, storage.EscrowAddress(a.SwapID), actor, swap.Offer); err != nil {
+		return nil, err
+	}
+	if err := storage.DeleteSwap(ctx, mu, a.SwapID); err != nil {
+		return nil, err
+	}
+	return &AcceptSwapResult{
+		Proposer:     swap.Proposer,
+		Counterparty: actor,
+	}, nil
+}
+
+func (a *AcceptSwap) ComputeUnits(r chain.Rules) uint64 {
+	return uint64(len(a.Offer)+len(a.Want)) * baseComputeUnits(r, mconsts.AcceptSwapID, SwapLegComputeUnits)
+}
+
+func (*AcceptSwap) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*AcceptSwapResult)(nil)
+
+type AcceptSwapResult struct {
+	Proposer     codec.Address `serialize:"true" json:"proposer"`
+	Counterparty codec.Address `serialize:"true" json:"counterparty"`
+}
+
+func (*AcceptSwapResult) GetTypeID() uint8 {
+	return mconsts.AcceptSwapID
+}
+
+// RefundSwap returns the escrowed offer of an expired swap to its proposer.
+// Anyone can submit it, since the funds can only go back to the proposer.
+type RefundSwap struct {
+	SwapID ids.ID `serialize:"true" json:"swap_id"`
+	// Proposer and Offer must match the swap, so the balances it moves can
+	// be declared in [StateKeys].
+	Proposer codec.Address     `serialize:"true" json:"proposer"`
+	Offer    []storage.SwapLeg `serialize:"true" json:"offer"`
+}
+
+func (*RefundSwap) GetTypeID() uint8 {
+	return mconsts.RefundSwapID
+}
+
+func (r *RefundSwap) StateKeys(codec.Address) state.Keys {
+	keys := state.Keys{string(storage.SwapKey(r.SwapID)): state.Read | state.Write}
+	addTakenKeys(keys, storage.SwapKey(r.SwapID))
+	for _, leg := range r.Offer {
+		addLegKeys(keys, leg, storage.EscrowAddress(r.SwapID), r.Proposer)
+	}
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (r *RefundSwap) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, r, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	swap, err := openSwap(ctx, mu, r.SwapID, r.Proposer, r.Offer)
+	if err != nil {
+		return nil, err
+	}
+	if swap.Expiry >= timestamp {
+		return nil, ErrSwapNotExpired
+	}
+	// Refunds skip transfer hooks, so a lock set while the swap was open
+	// cannot strand the offer in escrow.
+	if err := moveLegs(ctx, mu, storage.EscrowAddress(r.SwapID), swap.Proposer, swap.Offer); err != nil {
+		return nil, err
+	}
+	if err := storage.DeleteSwap(ctx, mu, r.SwapID); err != nil {
+		return nil, err
+	}
+	return &RefundSwapResult{Proposer: swap.Proposer}, nil
+}
+
+func (r *RefundSwap) ComputeUnits(rules chain.Rules) uint64 {
+	return uint64(len(r.Offer)) * baseComputeUnits(rules, mconsts.RefundSwapID, SwapLegComputeUnits)
+}
+
+func (*RefundSwap) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*RefundSwapResult)(nil)
+
+type RefundSwapResult struct {
+	Proposer codec.Address `serialize:"true" json:"proposer"`
+}
+
+func (*RefundSwapResult) GetTypeID() uint8 {
+	return mconsts.RefundSwapID
+}
+
+// validLegs reports whether [legs] is a non-empty side of at most
+// [storage.MaxSwapLegs] non-zero amounts of distinct assets.
+func validLegs(legs []storage.SwapLeg) bool {
+	if len(legs) == 0 || len(legs) > storage.MaxSwapLegs {
+		return false
+	}
+	for i, leg := range legs {
+		if leg.Amount == 0 {
+			return false
+		}
+		for _, prev := range legs[:i] {
+			if prev.Asset == leg.Asset {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// addLegKeys declares the keys needed to move [leg] from [from] to [to].
+func addLegKeys(keys state.Keys, leg storage.SwapLeg, from, to codec.Address) {
+	keys.Add(string(storage.LegBalanceKey(from, leg)), state.Read|state.Write)
+	keys.Add(string(storage.LegBalanceKey(to, leg)), state.All)
+	if leg.Asset == storage.NativeAsset {
+		keys.Add(string(storage.ActiveProposalKey()), state.Read)
+	}
+}
+
+// addLegHookKeys declares the keys [runLegHooks] needs for [legs].
+func addLegHookKeys(keys state.Keys, payer codec.Address, legs []storage.SwapLeg) {
+	for _, leg := range legs {
+		if leg.Asset != storage.NativeAsset {
+			addTransferHookKeys(keys, payer, leg.Asset)
+		}
+	}
+}
+
+// runLegHooks runs the transfer hook of each fungible asset of [legs],
+// charging [payer] for them.
+func runLegHooks(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	payer codec.Address,
+	legs []storage.SwapLeg,
+) error {
+	for _, leg := range legs {
+		if leg.Asset == storage.NativeAsset {
+			continue
+		}
+		if _, err := runTransferHook(ctx, r, mu, payer, leg.Asset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveLegs transfers every leg from [from] to [to]. Callers run the
+// transfer hooks of the legs first, unless the move skips them.
+func moveLegs(
+	ctx context.Context,
+	mu state.Mutable,
+	from codec.Address,
+	to codec.Address,
+	legs []storage.SwapLeg,
+) error {
+	for _, leg := range legs {
+		if err := storage.MoveLeg(ctx, mu, from, to, leg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openSwap returns the swap under [swapID], checking that it was proposed
+// by [proposer] with [offer].
+func openSwap(
+	ctx context.Context,
+	im state.Immutable,
+	swapID ids.ID,
+	proposer codec.Address,
+	offer []storage.SwapLeg,
+) (*storage.Swap, error) {
+	swap, exists, err := storage.GetSwap(ctx, im, swapID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, takenOr(ctx, im, storage.SwapKey(swapID), ErrSwapNotFound)
+	}
+	if swap.Proposer != proposer || !slices.Equal(swap.Offer, offer) {
+		return nil, ErrSwapMismatch
+	}
+	return swap, nil
+}
@@ -0,0 +1,197 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestSwapActions(t *testing.T) {
+	proposer := codectest.NewRandomAddress()
+	counterparty := codectest.NewRandomAddress()
+	swapID := ids.GenerateTestID()
+	asset := ids.GenerateTestID()
+	escrow := storage.EscrowAddress(swapID)
+
+	offer := []storage.SwapLeg{{Asset: storage.NativeAsset, Amount: 10}}
+	want := []storage.SwapLeg{{Asset: asset, Amount: 3}}
+
+	// funded gives [proposer] the offer and [counterparty] the want.
+	funded := func() state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(ctx, store, proposer, 10))
+		require.NoError(t, storage.SetAssetBalance(ctx, store, counterparty, asset, 5))
+		return store
+	}
+	// open has the swap proposed, with the offer in escrow.
+	open := func() state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, store.Insert(ctx, chain.HeightKey(storage.HeightKey()), binary.BigEndian.AppendUint64(nil, 9)))
+		require.NoError(t, storage.SetBalance(ctx, store, escrow, 10))
+		require.NoError(t, storage.SetAssetBalance(ctx, store, counterparty, asset, 5))
+		require.NoError(t, storage.SetSwap(ctx, store, swapID, &storage.Swap{
+			Proposer:     proposer,
+			Counterparty: counterparty,
+			Expiry:       100,
+			Offer:        offer,
+			Want:         want,
+		}))
+		return store
+	}
+	propose := &ProposeSwap{
+		SwapID:       swapID,
+		Counterparty: counterparty,
+		Offer:        offer,
+		Want:         want,
+		Expiry:       100,
+	}
+	accept := &AcceptSwap{
+		SwapID:   swapID,
+		Proposer: proposer,
+		Offer:    offer,
+		Want:     want,
+	}
+	refund := &RefundSwap{
+		SwapID:   swapID,
+		Proposer: proposer,
+		Offer:    offer,
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "DuplicateLegs",
+			Actor: proposer,
+			Action: &ProposeSwap{
+				SwapID:       swapID,
+				Counterparty: counterparty,
+				Offer:        offer,
+				Want:         append(want, want...),
+				Expiry:       100,
+			},
+			State:       funded(),
+			ExpectedErr: ErrInvalidSwapLegs,
+		},
+		{
+			Name:        "ProposeExists",
+			Actor:       proposer,
+			Action:      propose,
+			State:       open(),
+			ExpectedErr: ErrSwapExists,
+		},
+		{
+			Name:      "ProposeEscrowsOffer",
+			Actor:     proposer,
+			Action:    propose,
+			State:     funded(),
+			Timestamp: 100,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, proposer)
+				require.NoError(t, err)
+				require.Zero(t, balance)
+				balance, err = storage.GetBalance(ctx, store, escrow)
+				require.NoError(t, err)
+				require.Equal(t, uint64(10), balance)
+				swap, exists, err := storage.GetSwap(ctx, store, swapID)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, want, swap.Want)
+			},
+			ExpectedOutputs: &ProposeSwapResult{
+				Escrow: escrow,
+				Expiry: 100,
+			},
+		},
+		{
+			Name:        "AcceptNotCounterparty",
+			Actor:       proposer,
+			Action:      accept,
+			State:       open(),
+			ExpectedErr: ErrNotSwapCounterpart,
+		},
+		{
+			Name:  "AcceptMismatch",
+			Actor: counterparty,
+			Action: &AcceptSwap{
+				SwapID:   swapID,
+				Proposer: proposer,
+				Offer:    offer,
+				Want:     []storage.SwapLeg{{Asset: asset, Amount: 1}},
+			},
+			State:       open(),
+			ExpectedErr: ErrSwapMismatch,
+		},
+		{
+			Name:        "AcceptExpired",
+			Actor:       counterparty,
+			Action:      accept,
+			State:       open(),
+			Timestamp:   101,
+			ExpectedErr: ErrSwapExpired,
+		},
+		{
+			Name:      "AcceptExchanges",
+			Actor:     counterparty,
+			Action:    accept,
+			State:     open(),
+			Timestamp: 100,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, counterparty)
+				require.NoError(t, err)
+				require.Equal(t, uint64(10), balance)
+				balance, err = storage.GetAssetBalance(ctx, store, proposer, asset)
+				require.NoError(t, err)
+				require.Equal(t, uint64(3), balance)
+				balance, err = storage.GetBalance(ctx, store, escrow)
+				require.NoError(t, err)
+				require.Zero(t, balance)
+				_, exists, err := storage.GetSwap(ctx, store, swapID)
+				require.NoError(t, err)
+				require.False(t, exists)
+			},
+			ExpectedOutputs: &AcceptSwapResult{
+				Proposer:     proposer,
+				Counterparty: counterparty,
+			},
+		},
+		{
+			Name:        "RefundNotExpired",
+			Actor:       counterparty,
+			Action:      refund,
+			State:       open(),
+			Timestamp:   100,
+			ExpectedErr: ErrSwapNotExpired,
+		},
+		{
+			Name:      "RefundReturnsOffer",
+			Actor:     counterparty,
+			Action:    refund,
+			State:     open(),
+			Timestamp: 101,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, proposer)
+				require.NoError(t, err)
+				require.Equal(t, uint64(10), balance)
+				_, exists, err := storage.GetSwap(ctx, store, swapID)
+				require.NoError(t, err)
+				require.False(t, exists)
+			},
+			ExpectedOutputs: &RefundSwapResult{Proposer: proposer},
+		},
+	}
+
+	runActionTests(t, tests)
+}
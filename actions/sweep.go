@@ -0,0 +1,213 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const (
+	SweepComputeUnits = 1
+
+	// MaxSweepAssets bounds the assets a [SweepExpiredAssets] deletes.
+	MaxSweepAssets = 16
+
+	// SweepBounty is the default bounty per swept asset, paid from
+	// [storage.SweepPoolAddress].
+	SweepBounty = 1_000
+	// MaxSweepBounty bounds the bounty governance can set.
+	MaxSweepBounty = 1_000_000
+)
+
+// SweepBountyRule is the key of the bounty paid per swept asset, read from
+// the [Config] registry and else with chain.Rules.FetchCustom.
+// [SweepBounty] applies when neither sets it.
+const SweepBountyRule = "sweepBounty"
+
+var (
+	ErrNothingToSweep  = errors.New("no assets to sweep")
+	ErrTooManySweeps   = errors.New("too many assets to sweep")
+	ErrDuplicateAsset  = errors.New("duplicate asset")
+	ErrAssetNotExpired = errors.New("asset has not expired")
+	ErrWrongAssetOwner = errors.New("asset owner does not match")
+	ErrAssetInLocker   = errors.New("asset is held by a locker")
+	ErrAssetInAuction  = errors.New("asset is held by an auction")
+
+	_ chain.Action = (*SweepExpiredAssets)(nil)
+)
+
+// ExpiredAsset is an asset to sweep with its current owner, so the owner's
+// reverse index can be declared in [SweepExpiredAssets.StateKeys].
+type ExpiredAsset struct {
+	Asset ids.ID        `serialize:"true" json:"asset"`
+	Owner codec.Address `serialize:"true" json:"owner"`
+}
+
+// SweepExpiredAssets deletes expired assets and pays the actor a bounty for
+// each from [storage.SweepPoolAddress]. Anyone can send it. When the pool
+// cannot cover the bounties, the actor gets what it holds.
+//
+// Assets held by a locker are left until the locker is unbundled, so its
+// content stays as listed, and assets held by an auction until it is
+// settled, so the top bid is not stranded.
+type SweepExpiredAssets struct {
+	Assets []ExpiredAsset `serialize:"true" json:"assets"`
+}
+
+func (*SweepExpiredAssets) GetTypeID() uint8 {
+	return mconsts.SweepExpiredAssetsID
+}
+
+func (s *SweepExpiredAssets) StateKeys(actor codec.Address) state.Keys {
+	keys := state.Keys{
+		string(storage.BalanceKey(storage.SweepPoolAddress)): state.Read | state.Write,
+		string(storage.BalanceKey(actor)):                    state.All,
+		string(storage.ConfigKey(SweepBountyRule)):           state.Read,
+		string(storage.ActiveProposalKey()):                  state.Read,
+	}
+	for _, e := range s.Assets {
+		key := storage.AssetKey(e.Asset)
+		for k, perm := range storage.DeletionStateKeys(key) {
+			keys.Add(k, perm)
+		}
+		keys.Add(string(key), state.Read|state.Write)
+		keys.Add(string(storage.OwnedAssetKey(e.Owner, e.Asset)), state.Write)
+	}
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (s *SweepExpiredAssets) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, s, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(s.Assets) == 0:
+		return nil, ErrNothingToSweep
+	case len(s.Assets) > MaxSweepAssets:
+		return nil, ErrTooManySweeps
+	}
+	seen := make(map[ids.ID]bool, len(s.Assets))
+	for _, e := range s.Assets {
+		if seen[e.Asset] {
+			return nil, ErrDuplicateAsset
+		}
+		seen[e.Asset] = true
+		control, err := storage.GetAssetControl(ctx, mu, e.Asset)
+		if err != nil {
+			return nil, err
+		}
+		if !control.Expired(timestamp) {
+			return nil, ErrAssetNotExpired
+		}
+		owner, err := storage.GetAssetOwner(ctx, mu, e.Asset)
+		if err != nil {
+			return nil, err
+		}
+		if owner != e.Owner {
+			return nil, ErrWrongAssetOwner
+		}
+		if storage.IsLockerAddress(owner) {
+			return nil, ErrAssetInLocker
+		}
+		if storage.IsAuctionAddress(owner) {
+			return nil, ErrAssetInAuction
+		}
+		//statekeys:ignore OwnedAssetKey the owner was checked to match
+		if err := storage.DeleteAsset(ctx, mu, e.Asset); err != nil {
+			return nil, err
+		}
+	}
+
+	bounty, err := configUint64(ctx, r, mu, SweepBountyRule)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := storage.GetBalance(ctx, mu, storage.SweepPoolAddress)
+	if err != nil {
+		return nil, err
+	}
+	// [MaxSweepAssets] times [MaxSweepBounty] fits in a uint64, but values
+	// set in the rules are not bounded.
+	owed := uint64(math.MaxUint64)
+	if bounty <= math.MaxUint64/uint64(len(s.Assets)) {
+		owed = bounty * uint64(len(s.Assets))
+	}
+	paid := min(owed, pool)
+	if paid > 0 {
+		if _, err := storage.SubBalance(ctx, mu, storage.SweepPoolAddress, paid); err != nil {
+			return nil, err
+		}
+		if _, err := storage.AddBalance(ctx, mu, actor, paid, true); err != nil {
+			return nil, err
+		}
+	}
+	return &SweepExpiredAssetsResult{Swept: uint8(len(s.Assets)), Bounty: paid}, nil
+}
+
+func (s *SweepExpiredAssets) ComputeUnits(r chain.Rules) uint64 {
+	return uint64(len(s.Assets))*SweepComputeUnits +
+		baseComputeUnits(r, mconsts.SweepExpiredAssetsID, SweepComputeUnits)
+}
+
+func (*SweepExpiredAssets) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (s *SweepExpiredAssets) EmitEvents(actor codec.Address, output codec.Typed, e Emitter) {
+	for _, a := range s.Assets {
+		e.Emit(&OwnershipEvent{Asset: a.Asset, From: a.Owner})
+	}
+	if r, ok := output.(*SweepExpiredAssetsResult); ok && r.Bounty > 0 {
+		e.Emit(&TransferEvent{From: storage.SweepPoolAddress, To: actor, Asset: storage.NativeAsset, Amount: r.Bounty})
+	}
+}
+
+var _ codec.Typed = (*SweepExpiredAssetsResult)(nil)
+
+type SweepExpiredAssetsResult struct {
+	Swept uint8 `serialize:"true" json:"swept"`
+	// Bounty is the amount paid to the actor.
+	Bounty uint64 `serialize:"true" json:"bounty"`
+}
+
+func (*SweepExpiredAssetsResult) GetTypeID() uint8 {
+	return mconsts.SweepExpiredAssetsID
+}
+
+// SweepRules returns the bounty per swept asset of [r].
+func SweepRules(r chain.Rules) uint64 {
+	if r == nil {
+		return SweepBounty
+	}
+	if v, ok := r.FetchCustom(SweepBountyRule); ok {
+		if n, ok := v.(uint64); ok {
+			return n
+		}
+	}
+	return SweepBounty
+}
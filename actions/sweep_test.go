@@ -0,0 +1,240 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestSweepExpiredAssets(t *testing.T) {
+	sweeper := codectest.NewRandomAddress()
+	owner := codectest.NewRandomAddress()
+	ticket := ids.GenerateTestID()
+	pass := ids.GenerateTestID()
+	permanent := ids.GenerateTestID()
+	locker := storage.LockerID(owner, 1)
+
+	// expiring has [ticket] and [pass] held by [owner], both expiring at 100,
+	// and [pool] in the sweep pool.
+	expiring := func(pool uint64) state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, store.Insert(ctx, chain.HeightKey(storage.HeightKey()), binary.BigEndian.AppendUint64(nil, 9)))
+		for _, asset := range []ids.ID{ticket, pass} {
+			require.NoError(t, storage.CreateAssetWithControl(ctx, store, asset, storage.AssetControl{Minter: owner, Expiry: 100}, owner))
+		}
+		require.NoError(t, storage.CreateAsset(ctx, store, permanent, owner))
+		if pool > 0 {
+			require.NoError(t, storage.SetBalance(ctx, store, storage.SweepPoolAddress, pool))
+		}
+		return store
+	}
+	both := []ExpiredAsset{{Asset: ticket, Owner: owner}, {Asset: pass, Owner: owner}}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:      "Sweep",
+			Actor:     sweeper,
+			Action:    &SweepExpiredAssets{Assets: both},
+			State:     expiring(5_000),
+			Timestamp: 101,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				for _, asset := range []ids.ID{ticket, pass} {
+					_, err := store.GetValue(ctx, storage.AssetKey(asset))
+					require.ErrorIs(t, err, database.ErrNotFound)
+					_, err = store.GetValue(ctx, storage.OwnedAssetKey(owner, asset))
+					require.ErrorIs(t, err, database.ErrNotFound)
+				}
+				_, err := store.GetValue(ctx, storage.OwnedAssetKey(owner, permanent))
+				require.NoError(t, err)
+				balance, err := storage.GetBalance(ctx, store, sweeper)
+				require.NoError(t, err)
+				require.Equal(t, uint64(2*SweepBounty), balance)
+				balance, err = storage.GetBalance(ctx, store, storage.SweepPoolAddress)
+				require.NoError(t, err)
+				require.Equal(t, uint64(5_000-2*SweepBounty), balance)
+			},
+			ExpectedOutputs: &SweepExpiredAssetsResult{Swept: 2, Bounty: 2 * SweepBounty},
+		},
+		{
+			Name:            "PoolShort",
+			Actor:           sweeper,
+			Action:          &SweepExpiredAssets{Assets: both},
+			State:           expiring(1_500),
+			Timestamp:       101,
+			ExpectedOutputs: &SweepExpiredAssetsResult{Swept: 2, Bounty: 1_500},
+		},
+		{
+			Name:            "PoolEmpty",
+			Actor:           sweeper,
+			Action:          &SweepExpiredAssets{Assets: both},
+			State:           expiring(0),
+			Timestamp:       101,
+			ExpectedOutputs: &SweepExpiredAssetsResult{Swept: 2},
+		},
+		{
+			Name:        "NotExpired",
+			Actor:       sweeper,
+			Action:      &SweepExpiredAssets{Assets: both},
+			State:       expiring(5_000),
+			Timestamp:   100,
+			ExpectedErr: ErrAssetNotExpired,
+		},
+		{
+			Name:        "NeverExpires",
+			Actor:       sweeper,
+			Action:      &SweepExpiredAssets{Assets: []ExpiredAsset{{Asset: permanent, Owner: owner}}},
+			State:       expiring(5_000),
+			Timestamp:   101,
+			ExpectedErr: ErrAssetNotExpired,
+		},
+		{
+			Name:        "WrongOwner",
+			Actor:       sweeper,
+			Action:      &SweepExpiredAssets{Assets: []ExpiredAsset{{Asset: ticket, Owner: sweeper}}},
+			State:       expiring(5_000),
+			Timestamp:   101,
+			ExpectedErr: ErrWrongAssetOwner,
+		},
+		{
+			Name:        "Duplicate",
+			Actor:       sweeper,
+			Action:      &SweepExpiredAssets{Assets: []ExpiredAsset{both[0], both[0]}},
+			State:       expiring(5_000),
+			Timestamp:   101,
+			ExpectedErr: ErrDuplicateAsset,
+		},
+		{
+			Name:        "Missing",
+			Actor:       sweeper,
+			Action:      &SweepExpiredAssets{Assets: []ExpiredAsset{{Asset: ids.GenerateTestID(), Owner: owner}}},
+			State:       expiring(5_000),
+			Timestamp:   101,
+			ExpectedErr: storage.ErrAssetNotFound,
+		},
+		{
+			Name:   "InLocker",
+			Actor:  sweeper,
+			Action: &SweepExpiredAssets{Assets: []ExpiredAsset{{Asset: ticket, Owner: storage.LockerAddress(locker)}}},
+			State: func() state.Mutable {
+				store := expiring(5_000)
+				require.NoError(t, storage.ChangeAssetOwner(context.Background(), store, ticket, storage.LockerAddress(locker)))
+				return store
+			}(),
+			Timestamp:   101,
+			ExpectedErr: ErrAssetInLocker,
+		},
+		{
+			Name:   "InAuction",
+			Actor:  sweeper,
+			Action: &SweepExpiredAssets{Assets: []ExpiredAsset{{Asset: ticket, Owner: storage.AuctionAddress(ticket)}}},
+			State: func() state.Mutable {
+				store := expiring(5_000)
+				require.NoError(t, storage.ChangeAssetOwner(context.Background(), store, ticket, storage.AuctionAddress(ticket)))
+				return store
+			}(),
+			Timestamp:   101,
+			ExpectedErr: ErrAssetInAuction,
+		},
+		{
+			Name:        "Nothing",
+			Actor:       sweeper,
+			Action:      &SweepExpiredAssets{},
+			State:       expiring(5_000),
+			ExpectedErr: ErrNothingToSweep,
+		},
+	}
+
+	runActionTests(t, tests)
+}
+
+func TestExpiredAssetTransfers(t *testing.T) {
+	owner := codectest.NewRandomAddress()
+	recipient := codectest.NewRandomAddress()
+	ticket := ids.GenerateTestID()
+	locker := storage.LockerID(owner, 1)
+
+	// held has [ticket], expiring at 100, and an empty locker held by [owner].
+	held := func() state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.CreateAssetWithControl(ctx, store, ticket, storage.AssetControl{Minter: owner, Expiry: 100}, owner))
+		require.NoError(t, storage.CreateAsset(ctx, store, locker, owner))
+		require.NoError(t, storage.SetLocker(ctx, store, locker, &storage.Locker{}))
+		return store
+	}
+	requireOwner := func(expected codec.Address) func(context.Context, *testing.T, state.Mutable) {
+		return func(ctx context.Context, t *testing.T, store state.Mutable) {
+			o, err := storage.GetAssetOwner(ctx, store, ticket)
+			require.NoError(t, err)
+			require.Equal(t, expected, o)
+		}
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:            "TransferAtExpiry",
+			Actor:           owner,
+			Action:          &AssetTransfer{Recipient: recipient, Asset: ticket},
+			State:           held(),
+			Timestamp:       100,
+			Assertion:       requireOwner(recipient),
+			ExpectedOutputs: &AssetTransferResult{OldOwner: owner, NewOwner: recipient},
+		},
+		{
+			Name:        "TransferExpired",
+			Actor:       owner,
+			Action:      &AssetTransfer{Recipient: recipient, Asset: ticket},
+			State:       held(),
+			Timestamp:   101,
+			ExpectedErr: ErrAssetExpired,
+		},
+		{
+			Name:        "DepositExpired",
+			Actor:       owner,
+			Action:      &DepositToLocker{Locker: locker, Items: []ids.ID{ticket}},
+			State:       held(),
+			Timestamp:   101,
+			ExpectedErr: ErrAssetExpired,
+		},
+		{
+			Name:      "MintExpiring",
+			Actor:     owner,
+			Action:    &MintAsset{Asset: ticket, Expiry: 100},
+			State:     chaintest.NewInMemoryStore(),
+			Timestamp: 50,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				// The expiry survives later rewrites of the asset.
+				require.NoError(t, storage.SetAssetFrozen(ctx, store, ticket, true))
+				control, err := storage.GetAssetControl(ctx, store, ticket)
+				require.NoError(t, err)
+				require.Equal(t, storage.AssetControl{Minter: owner, Frozen: true, Expiry: 100}, control)
+			},
+			ExpectedOutputs: &MintAssetResult{Asset: ticket, Owner: owner},
+		},
+		{
+			Name:        "CreateExpired",
+			Actor:       owner,
+			Action:      &CreateAsset{Nonce: 1, Expiry: 100},
+			State:       chaintest.NewInMemoryStore(),
+			Timestamp:   101,
+			ExpectedErr: ErrAssetExpired,
+		},
+	}
+
+	runActionTests(t, tests)
+}
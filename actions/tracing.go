@@ -0,0 +1,54 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/ava-labs/avalanchego/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+type tracerHolder struct {
+	trace.Tracer
+}
+
+var tracer atomic.Pointer[tracerHolder]
+
+// SetTracer traces every action executed with [t]. Each execution is a span
+// child of the span of the block or transaction that runs it. It is
+// process-wide; nil stops tracing.
+func SetTracer(t trace.Tracer) {
+	if t == nil {
+		tracer.Store(nil)
+		return
+	}
+	tracer.Store(&tracerHolder{t})
+}
+
+// startSpan starts the span of [action] executed by [actor]. The caller
+// must end it.
+func startSpan(ctx context.Context, action chain.Action, actor codec.Address) (context.Context, oteltrace.Span) {
+	t := trace.Noop
+	if h := tracer.Load(); h != nil {
+		t = h.Tracer
+	}
+	name := reflect.TypeOf(action).Elem().Name()
+	ctx, span := t.Start(ctx, "Action."+name)
+	if span.IsRecording() {
+		span.SetAttributes(
+			attribute.String("action", name),
+			attribute.String("actor", actor.String()),
+			attribute.Int("stateKeys", len(action.StateKeys(actor))),
+		)
+	}
+	return ctx, span
+}
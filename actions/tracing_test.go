@@ -0,0 +1,66 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+type testTracer struct {
+	oteltrace.Tracer
+}
+
+func (testTracer) Close() error {
+	return nil
+}
+
+func TestExecuteSpans(t *testing.T) {
+	require := require.New(t)
+	recorder := tracetest.NewSpanRecorder()
+	tr := testTracer{sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")}
+	SetTracer(tr)
+	storage.SetTracer(tr)
+	t.Cleanup(func() {
+		SetTracer(nil)
+		storage.SetTracer(nil)
+	})
+
+	actor := codectest.NewRandomAddress()
+	action := &CreateLeaderboard{Nonce: 1, Size: 2, Expiry: 100}
+	_, err := action.Execute(context.Background(), nil, chaintest.NewInMemoryStore(), 0, actor, ids.Empty)
+	require.NoError(err)
+
+	spans := recorder.Ended()
+	require.Len(spans, 4)
+	halt, read, insert, execute := spans[0], spans[1], spans[2], spans[3]
+	require.Equal("Action.CreateLeaderboard", execute.Name())
+	require.ElementsMatch([]attribute.KeyValue{
+		attribute.String("action", "CreateLeaderboard"),
+		attribute.String("actor", actor.String()),
+		attribute.Int("stateKeys", 2),
+	}, execute.Attributes())
+
+	require.Equal("Storage.GetValue", halt.Name())
+	require.Contains(halt.Attributes(), attribute.String("record", "halt"))
+	require.Equal(execute.SpanContext().SpanID(), halt.Parent().SpanID())
+	require.Equal("Storage.GetValue", read.Name())
+	require.Equal("Storage.Insert", insert.Name())
+	for _, s := range []sdktrace.ReadOnlySpan{read, insert} {
+		require.Equal(execute.SpanContext().SpanID(), s.Parent().SpanID())
+		require.Contains(s.Attributes(), attribute.String("record", "leaderboard"))
+	}
+}
@@ -19,7 +19,17 @@ import (
 
 const (
 	TransferComputeUnits = 1
-	MaxMemoSize          = 256
+
+	// MaxMemoSize and MemoBytesPerComputeUnit apply when the rules do not
+	// set [MaxMemoSizeRule] and [MemoBytesPerComputeUnitRule].
+	MaxMemoSize             = 256
+	MemoBytesPerComputeUnit = 64
+)
+
+// Keys of the memo rules, read with chain.Rules.FetchCustom.
+const (
+	MaxMemoSizeRule             = "maxMemoSize"
+	MemoBytesPerComputeUnitRule = "memoBytesPerComputeUnit"
 )
 
 var (
@@ -35,8 +45,13 @@ type Transfer struct {
 	// Amount are transferred to [To].
 	Value uint64 `serialize:"true" json:"value"`
 
-	// Optional message to accompany transaction.
+	// Optional message to accompany transaction. Every started
+	// [MemoBytesPerComputeUnit] bytes cost one more compute unit.
 	Memo []byte `serialize:"true" json:"memo"`
+
+	// Receipt, when not empty, is minted to the actor as a receipt of the
+	// transfer. See [mintReceipt].
+	Receipt ids.ID `serialize:"true" json:"receipt"`
 }
 
 func (*Transfer) GetTypeID() uint8 {
@@ -44,25 +59,37 @@ func (*Transfer) GetTypeID() uint8 {
 }
 
 func (t *Transfer) StateKeys(actor codec.Address) state.Keys {
-	return state.Keys{
-		string(storage.BalanceKey(actor)): state.Read | state.Write,
-		string(storage.BalanceKey(t.To)):  state.All,
+	keys := state.Keys{
+		string(storage.BalanceKey(actor)):   state.Read | state.Write,
+		string(storage.BalanceKey(t.To)):    state.All,
+		string(storage.ActiveProposalKey()): state.Read,
 	}
+	addMemoKeys(keys, t.Memo)
+	addReceiptKeys(keys, actor, t.Receipt)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
 }
 
 func (t *Transfer) Execute(
 	ctx context.Context,
-	_ chain.Rules,
+	r chain.Rules,
 	mu state.Mutable,
-	_ int64,
+	timestamp int64,
 	actor codec.Address,
-	_ ids.ID,
+	actionID ids.ID,
 ) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, t, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
 	if t.Value == 0 {
 		return nil, ErrOutputValueZero
 	}
-	if len(t.Memo) > MaxMemoSize {
-		return nil, ErrOutputMemoTooLarge
+	if err := checkMemo(ctx, r, mu, t.Memo); err != nil {
+		return nil, err
 	}
 	senderBalance, err := storage.SubBalance(ctx, mu, actor, t.Value)
 	if err != nil {
@@ -72,6 +99,12 @@ func (t *Transfer) Execute(
 	if err != nil {
 		return nil, err
 	}
+	if senderBalance, err = settleDust(ctx, r, mu, actor, senderBalance, receiverBalance); err != nil {
+		return nil, err
+	}
+	if err := mintReceipt(ctx, mu, t.Receipt, actor, mconsts.TransferID, timestamp, actionID); err != nil {
+		return nil, err
+	}
 
 	return &TransferResult{
 		SenderBalance:   senderBalance,
@@ -79,8 +112,8 @@ func (t *Transfer) Execute(
 	}, nil
 }
 
-func (*Transfer) ComputeUnits(chain.Rules) uint64 {
-	return TransferComputeUnits
+func (t *Transfer) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.TransferID, TransferComputeUnits) + memoComputeUnits(r, t.Memo) + receiptComputeUnits(t.Receipt)
 }
 
 func (*Transfer) ValidRange(chain.Rules) (int64, int64) {
@@ -88,6 +121,11 @@ func (*Transfer) ValidRange(chain.Rules) (int64, int64) {
 	return -1, -1
 }
 
+func (t *Transfer) EmitEvents(actor codec.Address, _ codec.Typed, e Emitter) {
+	e.Emit(&TransferEvent{From: actor, To: t.To, Asset: storage.NativeAsset, Amount: t.Value})
+	emitReceipt(t.Receipt, actor, e)
+}
+
 var _ codec.Typed = (*TransferResult)(nil)
 
 type TransferResult struct {
@@ -98,3 +136,54 @@ type TransferResult struct {
 func (*TransferResult) GetTypeID() uint8 {
 	return mconsts.TransferID // Common practice is to use the action ID
 }
+
+// MemoRules returns the memo bound and pricing of [r].
+func MemoRules(r chain.Rules) (int, uint64) {
+	maxSize, bytesPerUnit := MaxMemoSize, uint64(MemoBytesPerComputeUnit)
+	if r == nil {
+		return maxSize, bytesPerUnit
+	}
+	if v, ok := r.FetchCustom(MaxMemoSizeRule); ok {
+		if n, ok := v.(int); ok {
+			maxSize = n
+		}
+	}
+	if v, ok := r.FetchCustom(MemoBytesPerComputeUnitRule); ok {
+		if n, ok := v.(uint64); ok && n > 0 {
+			bytesPerUnit = n
+		}
+	}
+	return maxSize, bytesPerUnit
+}
+
+// checkMemo bounds [memo] by the memo size governance set, or by [r] if it
+// has set none.
+//
+// Callers must declare the keys of [addMemoKeys].
+func checkMemo(ctx context.Context, r chain.Rules, im state.Immutable, memo []byte) error {
+	if len(memo) == 0 {
+		return nil
+	}
+	limit, err := configUint64(ctx, r, im, MaxMemoSizeRule)
+	if err != nil {
+		return err
+	}
+	if uint64(len(memo)) > limit {
+		return ErrOutputMemoTooLarge
+	}
+	return nil
+}
+
+// addMemoKeys declares the keys [checkMemo] reads for [memo], which are
+// none for an empty memo.
+func addMemoKeys(keys state.Keys, memo []byte) {
+	if len(memo) > 0 {
+		keys.Add(string(storage.ConfigKey(MaxMemoSizeRule)), state.Read)
+	}
+}
+
+// memoComputeUnits charges one unit per started block of memo bytes.
+func memoComputeUnits(r chain.Rules, memo []byte) uint64 {
+	_, bytesPerUnit := MemoRules(r)
+	return (uint64(len(memo)) + bytesPerUnit - 1) / bytesPerUnit
+}
@@ -3,6 +3,7 @@ package actions
 import (
 	"context"
 	"errors"
+	"math/bits"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/hypersdk-starter-kit/consts"
@@ -15,13 +16,22 @@ import (
 
 const (
 	AssetTransferComputeUnits = 1
+	AssetRoyaltyComputeUnits  = 1
 	MaxReasonSize             = 256
 )
 
+// MaxReasonSizeRule is the key of the reason bound, read with
+// chain.Rules.FetchCustom. [MaxReasonSize] applies when the rules do not set
+// it.
+const MaxReasonSizeRule = "maxReasonSize"
+
 var (
-	ErrReasonTooLarge              = errors.New("reason is too large")
-	ErrAssetNotOwned               = errors.New("asset not owned")
-	_                 chain.Action = (*AssetTransfer)(nil)
+	ErrReasonTooLarge                 = errors.New("reason is too large")
+	ErrAssetNotOwned                  = errors.New("asset not owned")
+	ErrWrongRoyaltyPayee              = errors.New("wrong royalty payee")
+	ErrAssetSoulbound                 = errors.New("asset is soulbound")
+	ErrAssetExpired                   = errors.New("asset expired")
+	_                    chain.Action = (*AssetTransfer)(nil)
 )
 
 type AssetTransfer struct {
@@ -33,6 +43,15 @@ type AssetTransfer struct {
 
 	// Reason for transfer.
 	Reason string `serialize:"true" json:"reason"`
+
+	// Price is the sale price of [Asset], in native tokens. When it is not
+	// zero and the asset has a royalty, the royalty on [Price] is paid from
+	// the sender's balance to [RoyaltyPayee].
+	Price uint64 `serialize:"true" json:"price"`
+
+	// RoyaltyPayee must be the royalty payee of [Asset] whenever a royalty
+	// is due.
+	RoyaltyPayee codec.Address `serialize:"true" json:"royaltyPayee"`
 }
 
 // GetTypeID implements chain.Action.
@@ -42,9 +61,19 @@ func (a *AssetTransfer) GetTypeID() uint8 {
 
 // StateKeys implements chain.Action.
 func (a *AssetTransfer) StateKeys(actor codec.Address) state.Keys {
-	return state.Keys{
-		string(storage.AssetKey(a.Asset)): state.All,
+	keys := state.Keys{
+		string(storage.AssetKey(a.Asset)):             state.Read | state.Write,
+		string(storage.OwnedAssetKey(actor, a.Asset)): state.Write,
+	}
+	addTransferHookKeys(keys, actor, a.Asset)
+	keys.Add(string(storage.OwnedAssetKey(a.Recipient, a.Asset)), state.Allocate|state.Write)
+	if a.Price > 0 {
+		keys.Add(string(storage.BalanceKey(actor)), state.Read|state.Write)
+		keys.Add(string(storage.BalanceKey(a.RoyaltyPayee)), state.All)
+		keys.Add(string(storage.ActiveProposalKey()), state.Read)
 	}
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
 }
 
 var _ codec.Typed = (*AssetTransferResult)(nil)
@@ -52,6 +81,12 @@ var _ codec.Typed = (*AssetTransferResult)(nil)
 type AssetTransferResult struct {
 	OldOwner codec.Address `serialize:"true" json:"old_owner"`
 	NewOwner codec.Address `serialize:"true" json:"new_owner"`
+	// Notify is the target of the asset's HookNotify hook, if it has one.
+	Notify codec.Address `serialize:"true" json:"notify"`
+
+	Price        uint64        `serialize:"true" json:"price"`
+	Royalty      uint64        `serialize:"true" json:"royalty"`
+	RoyaltyPayee codec.Address `serialize:"true" json:"royalty_payee"`
 }
 
 func (*AssetTransferResult) GetTypeID() uint8 {
@@ -67,7 +102,14 @@ func (a *AssetTransfer) Execute(
 	actor codec.Address,
 	actionID ids.ID,
 ) (codec.Typed, error) {
-	if len(a.Reason) > MaxReasonSize {
+	ctx, span := startSpan(ctx, a, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if len(a.Reason) > ReasonRules(r) {
 		return nil, ErrReasonTooLarge
 	}
 	oldOwner, err := storage.GetAssetOwner(ctx, mu, a.Asset)
@@ -77,22 +119,95 @@ func (a *AssetTransfer) Execute(
 	if oldOwner != actor {
 		return nil, ErrAssetNotOwned
 	}
+	control, err := storage.GetAssetControl(ctx, mu, a.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if control.Frozen {
+		return nil, ErrAssetFrozen
+	}
+	if control.Soulbound {
+		return nil, ErrAssetSoulbound
+	}
+	if control.Expired(timestamp) {
+		return nil, ErrAssetExpired
+	}
+	notify, err := runTransferHook(ctx, r, mu, actor, a.Asset)
+	if err != nil {
+		return nil, err
+	}
+	royalty := Royalty(a.Price, control.RoyaltyBasisPoints)
+	if royalty > 0 {
+		if a.RoyaltyPayee != control.RoyaltyPayee {
+			return nil, ErrWrongRoyaltyPayee
+		}
+		if _, err := storage.SubBalance(ctx, mu, actor, royalty); err != nil {
+			return nil, err
+		}
+		if _, err := storage.AddBalance(ctx, mu, control.RoyaltyPayee, royalty, true); err != nil {
+			return nil, err
+		}
+	}
 	err = storage.ChangeAssetOwner(ctx, mu, a.Asset, a.Recipient)
 	if err != nil {
 		return nil, err
 	}
-	return &AssetTransferResult{
+	result := &AssetTransferResult{
 		OldOwner: oldOwner,
 		NewOwner: a.Recipient,
-	}, nil
+		Notify:   notify,
+		Price:    a.Price,
+		Royalty:  royalty,
+	}
+	if royalty > 0 {
+		result.RoyaltyPayee = control.RoyaltyPayee
+	}
+	return result, nil
+}
+
+// ReasonRules returns the bound of [r] on transfer reasons, in bytes.
+func ReasonRules(r chain.Rules) int {
+	if r == nil {
+		return MaxReasonSize
+	}
+	if v, ok := r.FetchCustom(MaxReasonSizeRule); ok {
+		if n, ok := v.(int); ok {
+			return n
+		}
+	}
+	return MaxReasonSize
+}
+
+// Royalty returns [basisPoints] of [price], rounded down. Basis points above
+// [MaxRoyaltyBasisPoints] are treated as [MaxRoyaltyBasisPoints].
+func Royalty(price uint64, basisPoints uint16) uint64 {
+	// Capping keeps the quotient within 64 bits.
+	hi, lo := bits.Mul64(price, uint64(min(basisPoints, MaxRoyaltyBasisPoints)))
+	royalty, _ := bits.Div64(hi, lo, MaxRoyaltyBasisPoints)
+	return royalty
 }
 
 // ComputeUnits implements chain.Action.
-func (a *AssetTransfer) ComputeUnits(chain.Rules) uint64 {
-	return AssetTransferComputeUnits
+func (a *AssetTransfer) ComputeUnits(r chain.Rules) uint64 {
+	units := baseComputeUnits(r, mconsts.AssetTransferID, AssetTransferComputeUnits)
+	if a.Price > 0 {
+		units += AssetRoyaltyComputeUnits
+	}
+	return units
 }
 
 // ValidRange implements chain.Action.
 func (a *AssetTransfer) ValidRange(chain.Rules) (start int64, end int64) {
 	return -1, -1
 }
+
+func (a *AssetTransfer) EmitEvents(_ codec.Address, output codec.Typed, e Emitter) {
+	r, ok := output.(*AssetTransferResult)
+	if !ok {
+		return
+	}
+	e.Emit(&OwnershipEvent{Asset: a.Asset, From: r.OldOwner, To: r.NewOwner, Reason: a.Reason})
+	if r.Royalty != 0 {
+		e.Emit(&TransferEvent{From: r.OldOwner, To: r.RoyaltyPayee, Asset: storage.NativeAsset, Amount: r.Royalty})
+	}
+}
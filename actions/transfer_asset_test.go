@@ -0,0 +1,222 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestAssetTransferAction(t *testing.T) {
+	owner := codectest.NewRandomAddress()
+	recipient := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+
+	owned := func() state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.CreateAsset(context.Background(), store, asset, owner))
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "NotOwned",
+			Actor: recipient,
+			Action: &AssetTransfer{
+				Recipient: recipient,
+				Asset:     asset,
+			},
+			State:       owned(),
+			ExpectedErr: ErrAssetNotOwned,
+		},
+		{
+			Name:  "MovesOwnerIndex",
+			Actor: owner,
+			Action: &AssetTransfer{
+				Recipient: recipient,
+				Asset:     asset,
+			},
+			State: owned(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				_, err := store.GetValue(ctx, storage.OwnedAssetKey(owner, asset))
+				require.ErrorIs(t, err, database.ErrNotFound)
+				_, err = store.GetValue(ctx, storage.OwnedAssetKey(recipient, asset))
+				require.NoError(t, err)
+			},
+			ExpectedOutputs: &AssetTransferResult{
+				OldOwner: owner,
+				NewOwner: recipient,
+			},
+		},
+		{
+			Name:  "Soulbound",
+			Actor: owner,
+			Action: &AssetTransfer{
+				Recipient: recipient,
+				Asset:     asset,
+			},
+			State: func() state.Mutable {
+				store := chaintest.NewInMemoryStore()
+				require.NoError(t, storage.CreateSoulboundAsset(context.Background(), store, asset, owner))
+				return store
+			}(),
+			ExpectedErr: ErrAssetSoulbound,
+		},
+	}
+
+	runActionTests(t, tests)
+}
+
+func TestAssetTransferRoyalty(t *testing.T) {
+	owner := codectest.NewRandomAddress()
+	recipient := codectest.NewRandomAddress()
+	payee := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+
+	withRoyalty := func() state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.CreateAsset(ctx, store, asset, owner))
+		require.NoError(t, storage.SetAssetRoyalty(ctx, store, asset, 250, payee))
+		require.NoError(t, storage.SetBalance(ctx, store, owner, 100))
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "WrongPayee",
+			Actor: owner,
+			Action: &AssetTransfer{
+				Recipient:    recipient,
+				Asset:        asset,
+				Price:        1_000,
+				RoyaltyPayee: recipient,
+			},
+			State:       withRoyalty(),
+			ExpectedErr: ErrWrongRoyaltyPayee,
+		},
+		{
+			Name:  "InsufficientBalance",
+			Actor: owner,
+			Action: &AssetTransfer{
+				Recipient:    recipient,
+				Asset:        asset,
+				Price:        100_000,
+				RoyaltyPayee: payee,
+			},
+			State:       withRoyalty(),
+			ExpectedErr: storage.ErrInvalidBalance,
+		},
+		{
+			Name:  "Unpriced",
+			Actor: owner,
+			Action: &AssetTransfer{
+				Recipient: recipient,
+				Asset:     asset,
+			},
+			State: withRoyalty(),
+			ExpectedOutputs: &AssetTransferResult{
+				OldOwner: owner,
+				NewOwner: recipient,
+			},
+		},
+		{
+			Name:  "PaysRoyalty",
+			Actor: owner,
+			Action: &AssetTransfer{
+				Recipient:    recipient,
+				Asset:        asset,
+				Price:        1_000,
+				RoyaltyPayee: payee,
+			},
+			State: withRoyalty(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, owner)
+				require.NoError(t, err)
+				require.Equal(t, uint64(75), balance)
+				balance, err = storage.GetBalance(ctx, store, payee)
+				require.NoError(t, err)
+				require.Equal(t, uint64(25), balance)
+			},
+			ExpectedOutputs: &AssetTransferResult{
+				OldOwner:     owner,
+				NewOwner:     recipient,
+				Price:        1_000,
+				Royalty:      25,
+				RoyaltyPayee: payee,
+			},
+		},
+	}
+
+	runActionTests(t, tests)
+}
+
+func TestRoyalty(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(uint64(25), Royalty(1_000, 250))
+	require.Equal(uint64(0), Royalty(39, 250))
+	require.Equal(uint64(math.MaxUint64), Royalty(math.MaxUint64, MaxRoyaltyBasisPoints))
+	require.Equal(uint64(math.MaxUint64), Royalty(math.MaxUint64, math.MaxUint16))
+}
+
+func TestAssetTransferStateKeys(t *testing.T) {
+	owner := codectest.NewRandomAddress()
+	recipient := codectest.NewRandomAddress()
+	payee := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+
+	withRoyalty := func() state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.CreateAsset(ctx, store, asset, owner))
+		require.NoError(t, storage.SetAssetRoyalty(ctx, store, asset, 250, payee))
+		require.NoError(t, storage.SetBalance(ctx, store, owner, 100))
+		return store
+	}
+
+	runActionTests(t, []chaintest.ActionTest{
+		{
+			Name:  "Unpriced",
+			Actor: owner,
+			Action: &AssetTransfer{
+				Recipient: recipient,
+				Asset:     asset,
+			},
+			State: withRoyalty(),
+			ExpectedOutputs: &AssetTransferResult{
+				OldOwner: owner,
+				NewOwner: recipient,
+			},
+		},
+		{
+			Name:  "PaysRoyalty",
+			Actor: owner,
+			Action: &AssetTransfer{
+				Recipient:    recipient,
+				Asset:        asset,
+				Price:        1_000,
+				RoyaltyPayee: payee,
+			},
+			State: withRoyalty(),
+			ExpectedOutputs: &AssetTransferResult{
+				OldOwner:     owner,
+				NewOwner:     recipient,
+				Price:        1_000,
+				Royalty:      25,
+				RoyaltyPayee: payee,
+			},
+		},
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const TransferAssetComputeUnits = 1
+
+var _ chain.Action = (*TransferAsset)(nil)
+
+// TransferAsset moves an amount of a fungible asset. Use [AssetTransfer] to
+// change the owner of a non-fungible asset.
+type TransferAsset struct {
+	// To is the recipient of the [Value].
+	To codec.Address `serialize:"true" json:"to"`
+
+	// Asset is the fungible asset being transferred.
+	Asset ids.ID `serialize:"true" json:"asset"`
+
+	// Amount of [Asset] transferred to [To].
+	Value uint64 `serialize:"true" json:"value"`
+
+	// Optional message to accompany transaction.
+	Memo []byte `serialize:"true" json:"memo"`
+}
+
+func (*TransferAsset) GetTypeID() uint8 {
+	return mconsts.TransferAssetID
+}
+
+func (t *TransferAsset) StateKeys(actor codec.Address) state.Keys {
+	keys := state.Keys{
+		string(storage.AssetBalanceKey(actor, t.Asset)):    state.Read | state.Write,
+		string(storage.AssetBalanceKey(t.To, t.Asset)):     state.All,
+		string(storage.StablecoinBlockKey(t.Asset, actor)): state.Read,
+		string(storage.StablecoinBlockKey(t.Asset, t.To)):  state.Read,
+	}
+	addMemoKeys(keys, t.Memo)
+	addTransferHookKeys(keys, actor, t.Asset)
+	keys.Add(string(storage.HaltKey()), state.Read)
+	return keys
+}
+
+func (t *TransferAsset) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, t, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if t.Value == 0 {
+		return nil, ErrOutputValueZero
+	}
+	if err := checkMemo(ctx, r, mu, t.Memo); err != nil {
+		return nil, err
+	}
+	notify, err := runTransferHook(ctx, r, mu, actor, t.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNotBlocked(ctx, mu, t.Asset, actor, t.To); err != nil {
+		return nil, err
+	}
+	senderBalance, err := storage.SubAssetBalance(ctx, mu, actor, t.Asset, t.Value)
+	if err != nil {
+		return nil, err
+	}
+	receiverBalance, err := storage.AddAssetBalance(ctx, mu, t.To, t.Asset, t.Value, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransferAssetResult{
+		SenderBalance:   senderBalance,
+		ReceiverBalance: receiverBalance,
+		Notify:          notify,
+	}, nil
+}
+
+func (t *TransferAsset) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.TransferAssetID, TransferAssetComputeUnits) + memoComputeUnits(r, t.Memo)
+}
+
+func (*TransferAsset) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func (t *TransferAsset) EmitEvents(actor codec.Address, _ codec.Typed, e Emitter) {
+	e.Emit(&TransferEvent{From: actor, To: t.To, Asset: t.Asset, Amount: t.Value})
+}
+
+var _ codec.Typed = (*TransferAssetResult)(nil)
+
+type TransferAssetResult struct {
+	SenderBalance   uint64 `serialize:"true" json:"sender_balance"`
+	ReceiverBalance uint64 `serialize:"true" json:"receiver_balance"`
+	// Notify is the target of the asset's HookNotify hook, if it has one.
+	Notify codec.Address `serialize:"true" json:"notify"`
+}
+
+func (*TransferAssetResult) GetTypeID() uint8 {
+	return mconsts.TransferAssetID
+}
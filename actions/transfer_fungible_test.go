@@ -0,0 +1,81 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestTransferAssetAction(t *testing.T) {
+	addr := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "ZeroTransfer",
+			Actor: codec.EmptyAddress,
+			Action: &TransferAsset{
+				To:    addr,
+				Asset: asset,
+			},
+			State:       chaintest.NewInMemoryStore(),
+			ExpectedErr: ErrOutputValueZero,
+		},
+		{
+			Name:  "OtherAsset",
+			Actor: codec.EmptyAddress,
+			Action: &TransferAsset{
+				To:    addr,
+				Asset: ids.GenerateTestID(),
+				Value: 1,
+			},
+			State: func() state.Mutable {
+				store := chaintest.NewInMemoryStore()
+				require.NoError(t, storage.SetAssetBalance(context.Background(), store, codec.EmptyAddress, asset, 1))
+				return store
+			}(),
+			ExpectedErr: storage.ErrInvalidAddress,
+		},
+		{
+			Name:  "SimpleTransfer",
+			Actor: codec.EmptyAddress,
+			Action: &TransferAsset{
+				To:    addr,
+				Asset: asset,
+				Value: 1,
+			},
+			State: func() state.Mutable {
+				store := chaintest.NewInMemoryStore()
+				require.NoError(t, storage.SetAssetBalance(context.Background(), store, codec.EmptyAddress, asset, 3))
+				require.NoError(t, storage.SetBalance(context.Background(), store, codec.EmptyAddress, 5))
+				return store
+			}(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				receiverBalance, err := storage.GetAssetBalance(ctx, store, addr, asset)
+				require.NoError(t, err)
+				require.Equal(t, uint64(1), receiverBalance)
+				// Native balances are untouched.
+				nativeBalance, err := storage.GetBalance(ctx, store, codec.EmptyAddress)
+				require.NoError(t, err)
+				require.Equal(t, uint64(5), nativeBalance)
+			},
+			ExpectedOutputs: &TransferAssetResult{
+				SenderBalance:   2,
+				ReceiverBalance: 1,
+			},
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -0,0 +1,214 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const (
+	SetTransferHookComputeUnits = 1
+
+	// TransferHookNotifyFee is what each run of a HookNotify hook charges,
+	// in native units.
+	TransferHookNotifyFee = 10
+
+	// DefaultTransferHookFeeCap bounds the fee of one hook run while the
+	// rules set no [TransferHookFeeCapRule].
+	DefaultTransferHookFeeCap = 100
+)
+
+// TransferHookFeeCapRule is the key of the hard cap on the fee of one hook
+// run, a uint64 in native units read with chain.Rules.FetchCustom.
+const TransferHookFeeCapRule = "transferHookFeeCap"
+
+var (
+	ErrUnknownHookKind  = errors.New("unknown hook kind")
+	ErrNotHookRegistrar = errors.New("actor did not register the hook")
+	ErrAssetLocked      = errors.New("asset transfers are locked")
+	ErrHookFeeCap       = errors.New("transfer hook fee exceeds the cap")
+
+	_ chain.Action = (*SetTransferHook)(nil)
+)
+
+// SetTransferHook registers a built-in policy run on every transfer of
+// [Asset]. The asset owner registers the first hook; after that only the
+// registrar can replace or remove it, even if the asset changes hands.
+type SetTransferHook struct {
+	Asset ids.ID `serialize:"true" json:"asset"`
+
+	// Kind is one of the storage.Hook* policies. HookNone removes the hook.
+	Kind uint8 `serialize:"true" json:"kind"`
+
+	// Target is the address notified by HookNotify.
+	Target codec.Address `serialize:"true" json:"target"`
+}
+
+func (*SetTransferHook) GetTypeID() uint8 {
+	return mconsts.SetTransferHookID
+}
+
+func (s *SetTransferHook) StateKeys(codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.AssetKey(s.Asset)):        state.Read,
+		string(storage.TransferHookKey(s.Asset)): state.All,
+		string(storage.HaltKey()):                state.Read,
+	}
+}
+
+func (s *SetTransferHook) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, s, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if s.Kind > storage.HookLock {
+		return nil, ErrUnknownHookKind
+	}
+	hook, err := storage.GetTransferHook(ctx, mu, s.Asset)
+	if err != nil {
+		return nil, err
+	}
+	if hook != nil {
+		if hook.Registrar != actor {
+			return nil, ErrNotHookRegistrar
+		}
+	} else {
+		owner, err := storage.GetAssetOwner(ctx, mu, s.Asset)
+		if err != nil {
+			return nil, err
+		}
+		if owner != actor {
+			return nil, ErrAssetNotOwned
+		}
+	}
+	if err := storage.SetTransferHook(ctx, mu, s.Asset, &storage.TransferHook{
+		Registrar: actor,
+		Kind:      s.Kind,
+		Target:    s.Target,
+	}); err != nil {
+		return nil, err
+	}
+	return &SetTransferHookResult{
+		Kind:   s.Kind,
+		Target: s.Target,
+	}, nil
+}
+
+func (*SetTransferHook) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.SetTransferHookID, SetTransferHookComputeUnits)
+}
+
+func (*SetTransferHook) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*SetTransferHookResult)(nil)
+
+type SetTransferHookResult struct {
+	Kind   uint8         `serialize:"true" json:"kind"`
+	Target codec.Address `serialize:"true" json:"target"`
+}
+
+func (*SetTransferHookResult) GetTypeID() uint8 {
+	return mconsts.SetTransferHookID
+}
+
+// TransferHookRules returns the cap [r] sets on the fee of one hook run.
+func TransferHookRules(r chain.Rules) uint64 {
+	if r == nil {
+		return DefaultTransferHookFeeCap
+	}
+	if v, ok := r.FetchCustom(TransferHookFeeCapRule); ok {
+		if n, ok := v.(uint64); ok {
+			return n
+		}
+	}
+	return DefaultTransferHookFeeCap
+}
+
+// transferHookFee returns what a run of a hook of [kind] charges.
+func transferHookFee(kind uint8) uint64 {
+	if kind == storage.HookNotify {
+		return TransferHookNotifyFee
+	}
+	return 0
+}
+
+// addTransferHookKeys declares the keys [runTransferHook] reads for
+// [assetID], and the balance it charges [payer] from.
+func addTransferHookKeys(keys state.Keys, payer codec.Address, assetID ids.ID) {
+	keys.Add(string(storage.TransferHookKey(assetID)), state.Read)
+	keys.Add(string(storage.StablecoinKey(assetID)), state.Read)
+	keys.Add(string(storage.BalanceKey(payer)), state.Read|state.Write)
+	keys.Add(string(storage.ActiveProposalKey()), state.Read)
+}
+
+// runTransferHook applies the hook of [assetID] to a transfer, and rejects
+// transfers of a paused stablecoin. It returns the address to notify, or
+// [codec.EmptyAddress].
+//
+// Compute units are set before execution, so they cannot depend on whether
+// the asset has a hook. A hook run instead charges [payer] the fee of its
+// policy, which is burned, and fails the transfer if that fee exceeds the
+// cap of [r]. Transfers of assets without a hook are not charged.
+func runTransferHook(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	payer codec.Address,
+	assetID ids.ID,
+) (codec.Address, error) {
+	s, ok, err := storage.GetStablecoin(ctx, mu, assetID)
+	if err != nil {
+		return codec.EmptyAddress, err
+	}
+	if ok && s.Paused {
+		return codec.EmptyAddress, ErrStablecoinPaused
+	}
+	hook, err := storage.GetTransferHook(ctx, mu, assetID)
+	if err != nil || hook == nil {
+		return codec.EmptyAddress, err
+	}
+	var notify codec.Address
+	switch hook.Kind {
+	case storage.HookNotify:
+		notify = hook.Target
+	case storage.HookLock:
+		return codec.EmptyAddress, ErrAssetLocked
+	default:
+		return codec.EmptyAddress, ErrUnknownHookKind
+	}
+	fee := transferHookFee(hook.Kind)
+	if feeCap := TransferHookRules(r); fee > feeCap {
+		return codec.EmptyAddress, fmt.Errorf("%w: fee=%d, cap=%d", ErrHookFeeCap, fee, feeCap)
+	}
+	if fee > 0 {
+		if _, err := storage.SubBalance(ctx, mu, payer, fee); err != nil {
+			return codec.EmptyAddress, err
+		}
+	}
+	return notify, nil
+}
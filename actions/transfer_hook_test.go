@@ -0,0 +1,255 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// hookFeeCapRules caps the fee of a hook run at [feeCap].
+type hookFeeCapRules struct {
+	*genesis.Rules
+	feeCap uint64
+}
+
+func (r *hookFeeCapRules) FetchCustom(key string) (any, bool) {
+	if key == TransferHookFeeCapRule {
+		return r.feeCap, true
+	}
+	return nil, false
+}
+
+func TestTransferHooks(t *testing.T) {
+	creator := codectest.NewRandomAddress()
+	holder := codectest.NewRandomAddress()
+	watcher := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+
+	// unhooked has [holder] own [asset] and 10 of it, and 100 native
+	// tokens.
+	unhooked := func() *chaintest.InMemoryStore {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.CreateAsset(ctx, store, asset, holder))
+		require.NoError(t, storage.SetAssetBalance(ctx, store, holder, asset, 10))
+		require.NoError(t, storage.SetBalance(ctx, store, holder, 100))
+		return store
+	}
+	// hooked adds a hook of [kind] set by [creator] to [unhooked].
+	hooked := func(kind uint8) state.Mutable {
+		ctx := context.Background()
+		store := unhooked()
+		require.NoError(t, storage.SetTransferHook(ctx, store, asset, &storage.TransferHook{
+			Registrar: creator,
+			Kind:      kind,
+			Target:    watcher,
+		}))
+		return store
+	}
+
+	// charged requires that [holder] paid for one run of a HookNotify hook.
+	charged := func(ctx context.Context, t *testing.T, store state.Mutable) {
+		balance, err := storage.GetBalance(ctx, store, holder)
+		require.NoError(t, err)
+		require.Equal(t, uint64(100-TransferHookNotifyFee), balance)
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "Register",
+			Actor: holder,
+			Action: &SetTransferHook{
+				Asset:  asset,
+				Kind:   storage.HookNotify,
+				Target: watcher,
+			},
+			State: unhooked(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				hook, err := storage.GetTransferHook(ctx, store, asset)
+				require.NoError(t, err)
+				require.Equal(t, &storage.TransferHook{Registrar: holder, Kind: storage.HookNotify, Target: watcher}, hook)
+			},
+			ExpectedOutputs: &SetTransferHookResult{
+				Kind:   storage.HookNotify,
+				Target: watcher,
+			},
+		},
+		{
+			Name:  "RegisterNotOwned",
+			Actor: holder,
+			Action: &SetTransferHook{
+				Asset: asset,
+				Kind:  storage.HookLock,
+			},
+			State: func() state.Mutable {
+				store := chaintest.NewInMemoryStore()
+				require.NoError(t, storage.CreateAsset(context.Background(), store, asset, creator))
+				return store
+			}(),
+			ExpectedErr: ErrAssetNotOwned,
+		},
+		{
+			Name:  "UnknownKind",
+			Actor: creator,
+			Action: &SetTransferHook{
+				Asset: asset,
+				Kind:  storage.HookLock + 1,
+			},
+			State:       hooked(storage.HookNotify),
+			ExpectedErr: ErrUnknownHookKind,
+		},
+		{
+			Name:  "OnlyRegistrarChangesHook",
+			Actor: holder,
+			Action: &SetTransferHook{
+				Asset: asset,
+				Kind:  storage.HookNone,
+			},
+			State:       hooked(storage.HookLock),
+			ExpectedErr: ErrNotHookRegistrar,
+		},
+		{
+			Name:  "LockedTransfer",
+			Actor: holder,
+			Action: &AssetTransfer{
+				Recipient: watcher,
+				Asset:     asset,
+			},
+			State:       hooked(storage.HookLock),
+			ExpectedErr: ErrAssetLocked,
+		},
+		{
+			Name:  "NotifiedTransfer",
+			Actor: holder,
+			Action: &TransferAsset{
+				To:    creator,
+				Asset: asset,
+				Value: 4,
+			},
+			State: hooked(storage.HookNotify),
+			// The hook run is charged to the sender.
+			Assertion: charged,
+			ExpectedOutputs: &TransferAssetResult{
+				SenderBalance:   6,
+				ReceiverBalance: 4,
+				Notify:          watcher,
+			},
+		},
+		{
+			// Every way the asset moves runs the hook.
+			Name:  "NotifiedAssetTransfer",
+			Actor: holder,
+			Action: &AssetTransfer{
+				Recipient: creator,
+				Asset:     asset,
+			},
+			State:     hooked(storage.HookNotify),
+			Assertion: charged,
+			ExpectedOutputs: &AssetTransferResult{
+				OldOwner: holder,
+				NewOwner: creator,
+				Notify:   watcher,
+			},
+		},
+		{
+			Name:            "NotifiedAuction",
+			Actor:           holder,
+			Action:          &CreateAuction{Asset: asset, MinBid: 10, End: 100},
+			State:           hooked(storage.HookNotify),
+			Assertion:       charged,
+			ExpectedOutputs: &CreateAuctionResult{Custody: storage.AuctionAddress(asset)},
+		},
+		{
+			Name:  "NotifiedOrder",
+			Actor: holder,
+			Action: &CreateOrder{
+				SellAsset:  asset,
+				SellAmount: 4,
+				BuyAsset:   storage.NativeAsset,
+				BuyAmount:  10,
+			},
+			State:     hooked(storage.HookNotify),
+			Assertion: charged,
+			ExpectedOutputs: &CreateOrderResult{
+				OrderID:       storage.OrderID(holder, 0),
+				SellerBalance: 6,
+			},
+		},
+		{
+			Name:  "UnhookedTransferIsFree",
+			Actor: holder,
+			Action: &TransferAsset{
+				To:    creator,
+				Asset: asset,
+				Value: 4,
+			},
+			State: unhooked(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, holder)
+				require.NoError(t, err)
+				require.Equal(t, uint64(100), balance)
+			},
+			ExpectedOutputs: &TransferAssetResult{
+				SenderBalance:   6,
+				ReceiverBalance: 4,
+			},
+		},
+		{
+			Name:  "HookFeeOverCap",
+			Actor: holder,
+			Action: &TransferAsset{
+				To:    creator,
+				Asset: asset,
+				Value: 4,
+			},
+			Rules:       &hookFeeCapRules{Rules: genesis.NewDefaultRules(), feeCap: TransferHookNotifyFee - 1},
+			State:       hooked(storage.HookNotify),
+			ExpectedErr: ErrHookFeeCap,
+		},
+		{
+			Name:  "HookFeeUnpaid",
+			Actor: holder,
+			Action: &TransferAsset{
+				To:    creator,
+				Asset: asset,
+				Value: 4,
+			},
+			State: func() state.Mutable {
+				store := hooked(storage.HookNotify)
+				require.NoError(t, storage.SetBalance(context.Background(), store, holder, TransferHookNotifyFee-1))
+				return store
+			}(),
+			ExpectedErr: storage.ErrInvalidBalance,
+		},
+		{
+			Name:  "RemoveHook",
+			Actor: creator,
+			Action: &SetTransferHook{
+				Asset: asset,
+				Kind:  storage.HookNone,
+			},
+			State: hooked(storage.HookLock),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				hook, err := storage.GetTransferHook(ctx, store, asset)
+				require.NoError(t, err)
+				require.Nil(t, hook)
+			},
+			ExpectedOutputs: &SetTransferHookResult{
+				Kind: storage.HookNone,
+			},
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/hypersdk/chain/chaintest"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/genesis"
 	"github.com/ava-labs/hypersdk/state"
 )
 
@@ -29,6 +30,7 @@ func TestTransferAction(t *testing.T) {
 				To:    codec.EmptyAddress,
 				Value: 0,
 			},
+			State:       chaintest.NewInMemoryStore(),
 			ExpectedErr: ErrOutputValueZero,
 		},
 		{
@@ -125,11 +127,90 @@ func TestTransferAction(t *testing.T) {
 		},
 	}
 
-	for _, tt := range tests {
-		tt.Run(context.Background(), t)
+	runActionTests(t, tests)
+}
+
+// memoRules sets the memo rules read through FetchCustom.
+type memoRules struct {
+	*genesis.Rules
+	maxSize      int
+	bytesPerUnit uint64
+}
+
+func (r *memoRules) FetchCustom(key string) (any, bool) {
+	switch key {
+	case MaxMemoSizeRule:
+		return r.maxSize, true
+	case MemoBytesPerComputeUnitRule:
+		return r.bytesPerUnit, true
+	default:
+		return nil, false
 	}
 }
 
+func TestTransferMemo(t *testing.T) {
+	addr := codectest.NewRandomAddress()
+	rules := &memoRules{Rules: genesis.NewDefaultRules(), maxSize: 8, bytesPerUnit: 4}
+	funded := func() state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(context.Background(), store, codec.EmptyAddress, 1))
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "DefaultMemoTooLarge",
+			Actor: codec.EmptyAddress,
+			Action: &Transfer{
+				To:    addr,
+				Value: 1,
+				Memo:  make([]byte, MaxMemoSize+1),
+			},
+			State:       funded(),
+			ExpectedErr: ErrOutputMemoTooLarge,
+		},
+		{
+			Name:  "RulesMemoTooLarge",
+			Actor: codec.EmptyAddress,
+			Action: &Transfer{
+				To:    addr,
+				Value: 1,
+				Memo:  make([]byte, 9),
+			},
+			Rules:       rules,
+			State:       funded(),
+			ExpectedErr: ErrOutputMemoTooLarge,
+		},
+		{
+			Name:  "RulesMemo",
+			Actor: codec.EmptyAddress,
+			Action: &Transfer{
+				To:    addr,
+				Value: 1,
+				Memo:  make([]byte, 8),
+			},
+			Rules: rules,
+			State: funded(),
+			ExpectedOutputs: &TransferResult{
+				SenderBalance:   0,
+				ReceiverBalance: 1,
+			},
+		},
+	}
+
+	runActionTests(t, tests)
+}
+
+func TestTransferMemoComputeUnits(t *testing.T) {
+	require := require.New(t)
+	rules := &memoRules{Rules: genesis.NewDefaultRules(), maxSize: 64, bytesPerUnit: 4}
+
+	require.Equal(uint64(TransferComputeUnits), (&Transfer{}).ComputeUnits(nil))
+	require.Equal(uint64(TransferComputeUnits+1), (&Transfer{Memo: make([]byte, MemoBytesPerComputeUnit)}).ComputeUnits(nil))
+	require.Equal(uint64(TransferComputeUnits+2), (&Transfer{Memo: make([]byte, MemoBytesPerComputeUnit+1)}).ComputeUnits(nil))
+	require.Equal(uint64(TransferComputeUnits+3), (&Transfer{Memo: make([]byte, 9)}).ComputeUnits(rules))
+}
+
 func BenchmarkSimpleTransfer(b *testing.B) {
 	setupRequire := require.New(b)
 	to := codec.CreateAddress(0, ids.GenerateTestID())
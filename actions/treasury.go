@@ -0,0 +1,276 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const TreasurySpendComputeUnits = 1
+
+var (
+	ErrNoTreasuryCouncil = errors.New("chain has no treasury council")
+	ErrNotCouncilMember  = errors.New("actor is not a council member")
+	ErrProposalExists    = errors.New("proposal already exists")
+	ErrProposalNotFound  = errors.New("proposal not found")
+	ErrProposalExpired   = errors.New("proposal expired")
+	ErrProposalMismatch  = errors.New("proposal does not match")
+	ErrAlreadyApproved   = errors.New("proposal already approved by actor")
+
+	_ chain.Action = (*ProposeTreasurySpend)(nil)
+	_ chain.Action = (*ApproveTreasurySpend)(nil)
+)
+
+// ProposeTreasurySpend opens a proposal to pay [Amount] of the treasury to
+// [To], approved by the proposing council member. The spend executes in the
+// action that brings approvals to the council threshold.
+type ProposeTreasurySpend struct {
+	// ProposalID is chosen by the proposer and must not be pending.
+	ProposalID ids.ID        `serialize:"true" json:"proposal_id"`
+	To         codec.Address `serialize:"true" json:"to"`
+	Amount     uint64        `serialize:"true" json:"amount"`
+	// Expiry is the last timestamp, in milliseconds, at which the proposal
+	// can be approved.
+	Expiry int64 `serialize:"true" json:"expiry"`
+}
+
+func (*ProposeTreasurySpend) GetTypeID() uint8 {
+	return mconsts.ProposeTreasurySpendID
+}
+
+func (p *ProposeTreasurySpend) StateKeys(codec.Address) state.Keys {
+	return treasurySpendStateKeys(p.ProposalID, p.To, state.All)
+}
+
+func (p *ProposeTreasurySpend) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, p, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if p.Amount == 0 {
+		return nil, ErrOutputValueZero
+	}
+	if p.Expiry < timestamp {
+		return nil, ErrProposalExpired
+	}
+	council, index, err := councilMember(ctx, mu, actor)
+	if err != nil {
+		return nil, err
+	}
+	_, exists, err := storage.GetTreasuryProposal(ctx, mu, p.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrProposalExists
+	}
+	proposal := &storage.TreasuryProposal{
+		To:        p.To,
+		Amount:    p.Amount,
+		Expiry:    p.Expiry,
+		Approvals: 1 << index,
+	}
+	executed, balance, err := recordApproval(ctx, mu, council, p.ProposalID, proposal)
+	if err != nil {
+		return nil, err
+	}
+	return &ProposeTreasurySpendResult{
+		Approvals:       uint8(proposal.ApprovalCount()),
+		Executed:        executed,
+		Amount:          p.Amount,
+		TreasuryBalance: balance,
+	}, nil
+}
+
+func (*ProposeTreasurySpend) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.ProposeTreasurySpendID, TreasurySpendComputeUnits)
+}
+
+func (*ProposeTreasurySpend) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*ProposeTreasurySpendResult)(nil)
+
+type ProposeTreasurySpendResult struct {
+	Approvals       uint8  `serialize:"true" json:"approvals"`
+	Executed        bool   `serialize:"true" json:"executed"`
+	Amount          uint64 `serialize:"true" json:"amount"`
+	TreasuryBalance uint64 `serialize:"true" json:"treasury_balance"`
+}
+
+func (*ProposeTreasurySpendResult) GetTypeID() uint8 {
+	return mconsts.ProposeTreasurySpendID
+}
+
+// ApproveTreasurySpend adds the actor's approval to a pending proposal.
+type ApproveTreasurySpend struct {
+	ProposalID ids.ID `serialize:"true" json:"proposal_id"`
+	// To must match the recipient of the proposal, so the recipient balance
+	// can be declared in [StateKeys].
+	To codec.Address `serialize:"true" json:"to"`
+}
+
+func (*ApproveTreasurySpend) GetTypeID() uint8 {
+	return mconsts.ApproveTreasurySpendID
+}
+
+func (a *ApproveTreasurySpend) StateKeys(codec.Address) state.Keys {
+	// The proposal already exists.
+	return treasurySpendStateKeys(a.ProposalID, a.To, state.Read|state.Write)
+}
+
+func (a *ApproveTreasurySpend) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, a, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	council, index, err := councilMember(ctx, mu, actor)
+	if err != nil {
+		return nil, err
+	}
+	proposal, exists, err := storage.GetTreasuryProposal(ctx, mu, a.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrProposalNotFound
+	}
+	if proposal.To != a.To {
+		return nil, ErrProposalMismatch
+	}
+	if proposal.Expiry < timestamp {
+		return nil, ErrProposalExpired
+	}
+	if proposal.Approvals&(1<<index) != 0 {
+		return nil, ErrAlreadyApproved
+	}
+	proposal.Approvals |= 1 << index
+	executed, balance, err := recordApproval(ctx, mu, council, a.ProposalID, proposal)
+	if err != nil {
+		return nil, err
+	}
+	return &ApproveTreasurySpendResult{
+		Approvals:       uint8(proposal.ApprovalCount()),
+		Executed:        executed,
+		Amount:          proposal.Amount,
+		TreasuryBalance: balance,
+	}, nil
+}
+
+func (*ApproveTreasurySpend) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.ApproveTreasurySpendID, TreasurySpendComputeUnits)
+}
+
+func (*ApproveTreasurySpend) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*ApproveTreasurySpendResult)(nil)
+
+type ApproveTreasurySpendResult struct {
+	Approvals       uint8  `serialize:"true" json:"approvals"`
+	Executed        bool   `serialize:"true" json:"executed"`
+	Amount          uint64 `serialize:"true" json:"amount"`
+	TreasuryBalance uint64 `serialize:"true" json:"treasury_balance"`
+}
+
+func (*ApproveTreasurySpendResult) GetTypeID() uint8 {
+	return mconsts.ApproveTreasurySpendID
+}
+
+func treasurySpendStateKeys(proposalID ids.ID, to codec.Address, proposal state.Permissions) state.Keys {
+	return state.Keys{
+		string(storage.TreasuryCouncilKey()):                state.Read,
+		string(storage.TreasuryProposalKey(proposalID)):     proposal,
+		string(storage.BalanceKey(storage.TreasuryAddress)): state.Read | state.Write,
+		string(storage.BalanceKey(to)):                      state.All,
+		string(storage.ActiveProposalKey()):                 state.Read,
+		string(storage.HaltKey()):                           state.Read,
+	}
+}
+
+// councilMember returns the council and the position of [actor] in it.
+func councilMember(
+	ctx context.Context,
+	im state.Immutable,
+	actor codec.Address,
+) (*storage.Council, int, error) {
+	council, err := storage.GetTreasuryCouncil(ctx, im)
+	if err != nil {
+		return nil, 0, err
+	}
+	if council == nil {
+		return nil, 0, ErrNoTreasuryCouncil
+	}
+	index := council.Index(actor)
+	if index < 0 {
+		return nil, 0, ErrNotCouncilMember
+	}
+	return council, index, nil
+}
+
+// recordApproval stores [proposal], or pays it out and removes it once it
+// reaches the council threshold. It returns whether the spend executed and
+// the treasury balance afterwards.
+func recordApproval(
+	ctx context.Context,
+	mu state.Mutable,
+	council *storage.Council,
+	proposalID ids.ID,
+	proposal *storage.TreasuryProposal,
+) (bool, uint64, error) {
+	if proposal.ApprovalCount() < int(council.Threshold) {
+		if err := storage.SetTreasuryProposal(ctx, mu, proposalID, proposal); err != nil {
+			return false, 0, err
+		}
+		balance, err := storage.GetBalance(ctx, mu, storage.TreasuryAddress)
+		return false, balance, err
+	}
+	if _, err := storage.SubBalance(ctx, mu, storage.TreasuryAddress, proposal.Amount); err != nil {
+		return false, 0, err
+	}
+	if _, err := storage.AddBalance(ctx, mu, proposal.To, proposal.Amount, true); err != nil {
+		return false, 0, err
+	}
+	if err := storage.DeleteTreasuryProposal(ctx, mu, proposalID); err != nil {
+		return false, 0, err
+	}
+	// Read back rather than use SubBalance's result, in case [proposal.To]
+	// is the treasury itself.
+	balance, err := storage.GetBalance(ctx, mu, storage.TreasuryAddress)
+	return true, balance, err
+}
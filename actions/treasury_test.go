@@ -0,0 +1,189 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestTreasurySpendActions(t *testing.T) {
+	members := []codec.Address{
+		codectest.NewRandomAddress(),
+		codectest.NewRandomAddress(),
+		codectest.NewRandomAddress(),
+	}
+	outsider := codectest.NewRandomAddress()
+	to := codectest.NewRandomAddress()
+	proposalID := ids.GenerateTestID()
+
+	// treasury funds a 2-of-3 council, with [approvals] already given to a
+	// proposal paying 4 to [to] when non-zero.
+	treasury := func(approvals uint16) state.Mutable {
+		ctx := context.Background()
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(ctx, store, storage.TreasuryAddress, 10))
+		require.NoError(t, storage.SetTreasuryCouncil(ctx, store, &storage.Council{
+			Members:   members,
+			Threshold: 2,
+		}))
+		if approvals != 0 {
+			require.NoError(t, storage.SetTreasuryProposal(ctx, store, proposalID, &storage.TreasuryProposal{
+				To:        to,
+				Amount:    4,
+				Expiry:    100,
+				Approvals: approvals,
+			}))
+		}
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "NotCouncilMember",
+			Actor: outsider,
+			Action: &ProposeTreasurySpend{
+				ProposalID: proposalID,
+				To:         to,
+				Amount:     4,
+				Expiry:     100,
+			},
+			State:       treasury(0),
+			ExpectedErr: ErrNotCouncilMember,
+		},
+		{
+			Name:  "Propose",
+			Actor: members[1],
+			Action: &ProposeTreasurySpend{
+				ProposalID: proposalID,
+				To:         to,
+				Amount:     4,
+				Expiry:     100,
+			},
+			State: treasury(0),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				proposal, exists, err := storage.GetTreasuryProposal(ctx, store, proposalID)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, uint16(0b010), proposal.Approvals)
+			},
+			ExpectedOutputs: &ProposeTreasurySpendResult{
+				Approvals:       1,
+				Amount:          4,
+				TreasuryBalance: 10,
+			},
+		},
+		{
+			// A council of threshold 1 pays on the proposal.
+			Name:  "ProposeExecutes",
+			Actor: members[1],
+			Action: &ProposeTreasurySpend{
+				ProposalID: proposalID,
+				To:         to,
+				Amount:     4,
+				Expiry:     100,
+			},
+			State: func() state.Mutable {
+				store := treasury(0)
+				require.NoError(t, storage.SetTreasuryCouncil(context.Background(), store, &storage.Council{
+					Members:   members,
+					Threshold: 1,
+				}))
+				return store
+			}(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				_, exists, err := storage.GetTreasuryProposal(ctx, store, proposalID)
+				require.NoError(t, err)
+				require.False(t, exists)
+				balance, err := storage.GetBalance(ctx, store, to)
+				require.NoError(t, err)
+				require.Equal(t, uint64(4), balance)
+			},
+			ExpectedOutputs: &ProposeTreasurySpendResult{
+				Approvals:       1,
+				Executed:        true,
+				Amount:          4,
+				TreasuryBalance: 6,
+			},
+		},
+		{
+			Name:  "ProposalExists",
+			Actor: members[1],
+			Action: &ProposeTreasurySpend{
+				ProposalID: proposalID,
+				To:         to,
+				Amount:     4,
+				Expiry:     100,
+			},
+			State:       treasury(0b001),
+			ExpectedErr: ErrProposalExists,
+		},
+		{
+			Name:  "AlreadyApproved",
+			Actor: members[0],
+			Action: &ApproveTreasurySpend{
+				ProposalID: proposalID,
+				To:         to,
+			},
+			State:       treasury(0b001),
+			ExpectedErr: ErrAlreadyApproved,
+		},
+		{
+			Name:  "WrongRecipient",
+			Actor: members[2],
+			Action: &ApproveTreasurySpend{
+				ProposalID: proposalID,
+				To:         outsider,
+			},
+			State:       treasury(0b001),
+			ExpectedErr: ErrProposalMismatch,
+		},
+		{
+			Name:  "Expired",
+			Actor: members[2],
+			Action: &ApproveTreasurySpend{
+				ProposalID: proposalID,
+				To:         to,
+			},
+			State:       treasury(0b001),
+			Timestamp:   101,
+			ExpectedErr: ErrProposalExpired,
+		},
+		{
+			Name:  "ApproveExecutes",
+			Actor: members[2],
+			Action: &ApproveTreasurySpend{
+				ProposalID: proposalID,
+				To:         to,
+			},
+			State:     treasury(0b001),
+			Timestamp: 100,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				_, exists, err := storage.GetTreasuryProposal(ctx, store, proposalID)
+				require.NoError(t, err)
+				require.False(t, exists)
+				balance, err := storage.GetBalance(ctx, store, to)
+				require.NoError(t, err)
+				require.Equal(t, uint64(4), balance)
+			},
+			ExpectedOutputs: &ApproveTreasurySpendResult{
+				Approvals:       2,
+				Executed:        true,
+				Amount:          4,
+				TreasuryBalance: 6,
+			},
+		},
+	}
+
+	runActionTests(t, tests)
+}
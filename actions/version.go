@@ -0,0 +1,111 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+var (
+	ErrNoVersions       = errors.New("no action versions")
+	ErrVersionMismatch  = errors.New("action versions have different type IDs")
+	ErrDuplicateVersion = errors.New("duplicate action version")
+	ErrUnknownVersion   = errors.New("unknown action version")
+)
+
+// Versioned is an action whose encoding starts with a version byte, so new
+// versions of it keep its type ID. Every version of an action returns the
+// same GetTypeID and its own GetVersion.
+//
+// Versioned actions implement chain.Marshaler with [MarshalVersioned] and
+// [VersionedSize], and are registered with [RegisterVersions]:
+//
+//	func (t *TransferV2) Marshal(p *codec.Packer) { MarshalVersioned(p, t) }
+//	func (t *TransferV2) Size() int               { return VersionedSize(t) }
+type Versioned interface {
+	chain.Action
+	GetVersion() uint8
+}
+
+// MarshalVersioned packs the version of [a] followed by its fields.
+func MarshalVersioned(p *codec.Packer, a Versioned) {
+	p.PackByte(a.GetVersion())
+	if err := codec.LinearCodec.MarshalInto(a, p.Packer); err != nil {
+		p.Packer.Add(err)
+	}
+}
+
+// VersionedSize is the size of [a] as packed by [MarshalVersioned].
+func VersionedSize(a Versioned) int {
+	size, err := codec.LinearCodec.Size(a)
+	if err != nil {
+		// Size cannot fail, so the error surfaces when [a] is marshaled.
+		return consts.ByteLen
+	}
+	return consts.ByteLen + size
+}
+
+// Versions decodes the versions of one action by their version byte.
+type Versions struct {
+	typeID uint8
+	latest Versioned
+	types  map[uint8]reflect.Type
+}
+
+// NewVersions returns the decoder of [versions], which must share a type
+// ID. The last version is the latest.
+func NewVersions(versions ...Versioned) (*Versions, error) {
+	if len(versions) == 0 {
+		return nil, ErrNoVersions
+	}
+	v := &Versions{
+		typeID: versions[0].GetTypeID(),
+		latest: versions[len(versions)-1],
+		types:  make(map[uint8]reflect.Type, len(versions)),
+	}
+	for _, version := range versions {
+		if version.GetTypeID() != v.typeID {
+			return nil, fmt.Errorf("%w: %d and %d", ErrVersionMismatch, v.typeID, version.GetTypeID())
+		}
+		if _, ok := v.types[version.GetVersion()]; ok {
+			return nil, fmt.Errorf("%w: %d of type %d", ErrDuplicateVersion, version.GetVersion(), v.typeID)
+		}
+		v.types[version.GetVersion()] = reflect.TypeOf(version).Elem()
+	}
+	return v, nil
+}
+
+// Decode unpacks the version byte and the action of that version.
+func (v *Versions) Decode(p *codec.Packer) (chain.Action, error) {
+	version := p.UnpackByte()
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	rt, ok := v.types[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d of type %d", ErrUnknownVersion, version, v.typeID)
+	}
+	action := reflect.New(rt).Interface().(Versioned)
+	if err := codec.LinearCodec.UnmarshalFrom(p.Packer, action); err != nil {
+		return nil, err
+	}
+	return action, nil
+}
+
+// RegisterVersions registers [versions] of one action with [parser], which
+// routes each encoded action to its version. Only the latest version is
+// listed in the parser's registered types, and so in the ABI.
+func RegisterVersions(parser *codec.TypeParser[chain.Action], versions ...Versioned) error {
+	v, err := NewVersions(versions...)
+	if err != nil {
+		return err
+	}
+	return parser.Register(v.latest, v.Decode)
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const tipTransferID uint8 = 250
+
+// tipTransferBase is the part of a chain.Action the test versions share.
+type tipTransferBase struct{}
+
+func (tipTransferBase) GetTypeID() uint8 {
+	return tipTransferID
+}
+
+func (tipTransferBase) StateKeys(codec.Address) state.Keys {
+	return state.Keys{}
+}
+
+func (tipTransferBase) Execute(context.Context, chain.Rules, state.Mutable, int64, codec.Address, ids.ID) (codec.Typed, error) {
+	return nil, nil
+}
+
+func (tipTransferBase) ComputeUnits(chain.Rules) uint64 {
+	return 1
+}
+
+func (tipTransferBase) ValidRange(chain.Rules) (int64, int64) {
+	return -1, -1
+}
+
+type tipTransferV1 struct {
+	tipTransferBase
+	Value uint64 `serialize:"true" json:"value"`
+	Memo  []byte `serialize:"true" json:"memo"`
+}
+
+func (*tipTransferV1) GetVersion() uint8 {
+	return 1
+}
+
+func (t *tipTransferV1) Marshal(p *codec.Packer) { MarshalVersioned(p, t) }
+func (t *tipTransferV1) Size() int               { return VersionedSize(t) }
+
+type tipTransferV2 struct {
+	tipTransferBase
+	Value uint64 `serialize:"true" json:"value"`
+	Memo  []byte `serialize:"true" json:"memo"`
+	Tip   uint64 `serialize:"true" json:"tip"`
+}
+
+func (*tipTransferV2) GetVersion() uint8 {
+	return 2
+}
+
+func (t *tipTransferV2) Marshal(p *codec.Packer) { MarshalVersioned(p, t) }
+func (t *tipTransferV2) Size() int               { return VersionedSize(t) }
+
+// otherVersion has another type ID.
+type otherVersion struct {
+	tipTransferV1
+}
+
+func (*otherVersion) GetTypeID() uint8 {
+	return tipTransferID + 1
+}
+
+func TestVersionedActions(t *testing.T) {
+	require := require.New(t)
+	parser := codec.NewTypeParser[chain.Action]()
+	require.NoError(RegisterVersions(parser, &tipTransferV1{}, &tipTransferV2{}))
+	require.Equal([]codec.Typed{&tipTransferV2{}}, parser.GetRegisteredTypes())
+
+	for _, action := range []Versioned{
+		&tipTransferV1{Value: 5, Memo: []byte("hi")},
+		&tipTransferV2{Value: 5, Memo: []byte("hi"), Tip: 2},
+	} {
+		b, err := chain.MarshalTyped(action)
+		require.NoError(err)
+		require.Equal([]byte{tipTransferID, action.GetVersion()}, b[:2])
+		require.Len(b, 1+action.(chain.Marshaler).Size())
+
+		decoded, err := parser.Unmarshal(codec.NewReader(b, consts.NetworkSizeLimit))
+		require.NoError(err)
+		require.Equal(action, decoded)
+	}
+
+	_, err := parser.Unmarshal(codec.NewReader([]byte{tipTransferID, 3}, consts.NetworkSizeLimit))
+	require.ErrorIs(err, ErrUnknownVersion)
+	_, err = parser.Unmarshal(codec.NewReader([]byte{tipTransferID}, consts.NetworkSizeLimit))
+	require.Error(err)
+}
+
+func TestNewVersions(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewVersions()
+	require.ErrorIs(err, ErrNoVersions)
+	_, err = NewVersions(&tipTransferV1{}, &tipTransferV1{})
+	require.ErrorIs(err, ErrDuplicateVersion)
+	_, err = NewVersions(&tipTransferV1{}, &otherVersion{})
+	require.ErrorIs(err, ErrVersionMismatch)
+
+	parser := codec.NewTypeParser[chain.Action]()
+	require.NoError(RegisterVersions(parser, &tipTransferV1{}))
+	require.ErrorIs(RegisterVersions(parser, &tipTransferV2{}), codec.ErrDuplicateItem)
+}
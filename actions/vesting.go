@@ -0,0 +1,200 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const VestingComputeUnits = 1
+
+var (
+	ErrVestingExists   = errors.New("vesting already exists")
+	ErrVestingNotFound = errors.New("vesting not found")
+	ErrVestingLocked   = errors.New("vesting has not been released")
+	ErrReleaseInPast   = errors.New("release must be after the block timestamp")
+
+	_ chain.Action = (*CreateVesting)(nil)
+	_ chain.Action = (*ClaimVesting)(nil)
+)
+
+// CreateVesting locks [Amount] of the actor's native tokens until
+// [Release], when [Beneficiary] can claim them with [ClaimVesting].
+type CreateVesting struct {
+	// VestingID is chosen by the creator and must not be pending for
+	// [Beneficiary].
+	VestingID   ids.ID        `serialize:"true" json:"vesting_id"`
+	Beneficiary codec.Address `serialize:"true" json:"beneficiary"`
+	Amount      uint64        `serialize:"true" json:"amount"`
+	// Release is the timestamp, in milliseconds, from which the amount can
+	// be claimed.
+	Release int64 `serialize:"true" json:"release"`
+}
+
+func (*CreateVesting) GetTypeID() uint8 {
+	return mconsts.CreateVestingID
+}
+
+func (c *CreateVesting) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.BalanceKey(actor)):                      state.Read | state.Write,
+		string(storage.VestingKey(c.Beneficiary, c.VestingID)): state.All,
+		string(storage.ActiveProposalKey()):                    state.Read,
+		string(storage.HaltKey()):                              state.Read,
+	}
+}
+
+func (c *CreateVesting) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, c, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	if c.Amount == 0 {
+		return nil, ErrOutputValueZero
+	}
+	if c.Release <= timestamp {
+		return nil, ErrReleaseInPast
+	}
+	_, exists, err := storage.GetVesting(ctx, mu, c.Beneficiary, c.VestingID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrVestingExists
+	}
+	balance, err := storage.SubBalance(ctx, mu, actor, c.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.SetVesting(ctx, mu, c.Beneficiary, c.VestingID, &storage.Vesting{
+		Creator: actor,
+		Amount:  c.Amount,
+		Release: c.Release,
+	}); err != nil {
+		return nil, err
+	}
+	return &CreateVestingResult{
+		SenderBalance: balance,
+		Release:       c.Release,
+	}, nil
+}
+
+func (*CreateVesting) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.CreateVestingID, VestingComputeUnits)
+}
+
+func (*CreateVesting) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*CreateVestingResult)(nil)
+
+type CreateVestingResult struct {
+	SenderBalance uint64 `serialize:"true" json:"sender_balance"`
+	Release       int64  `serialize:"true" json:"release"`
+}
+
+func (*CreateVestingResult) GetTypeID() uint8 {
+	return mconsts.CreateVestingID
+}
+
+// ClaimVesting credits a released vesting of the actor to its balance and
+// removes it from state.
+type ClaimVesting struct {
+	VestingID ids.ID `serialize:"true" json:"vesting_id"`
+}
+
+func (*ClaimVesting) GetTypeID() uint8 {
+	return mconsts.ClaimVestingID
+}
+
+func (c *ClaimVesting) StateKeys(actor codec.Address) state.Keys {
+	return state.Keys{
+		string(storage.VestingKey(actor, c.VestingID)): state.Read | state.Write,
+		string(storage.BalanceKey(actor)):              state.All,
+		string(storage.ActiveProposalKey()):            state.Read,
+		string(storage.HaltKey()):                      state.Read,
+	}
+}
+
+func (c *ClaimVesting) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	ctx, span := startSpan(ctx, c, actor)
+	defer span.End()
+
+	if err := storage.CheckNotHalted(ctx, mu); err != nil {
+		return nil, err
+	}
+
+	vesting, exists, err := storage.GetVesting(ctx, mu, actor, c.VestingID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrVestingNotFound
+	}
+	if timestamp < vesting.Release {
+		return nil, ErrVestingLocked
+	}
+	if err := storage.DeleteVesting(ctx, mu, actor, c.VestingID); err != nil {
+		return nil, err
+	}
+	balance, err := storage.AddBalance(ctx, mu, actor, vesting.Amount, true)
+	if err != nil {
+		return nil, err
+	}
+	return &ClaimVestingResult{
+		Creator:         vesting.Creator,
+		Amount:          vesting.Amount,
+		ReceiverBalance: balance,
+	}, nil
+}
+
+func (*ClaimVesting) ComputeUnits(r chain.Rules) uint64 {
+	return baseComputeUnits(r, mconsts.ClaimVestingID, VestingComputeUnits)
+}
+
+func (*ClaimVesting) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*ClaimVestingResult)(nil)
+
+type ClaimVestingResult struct {
+	Creator         codec.Address `serialize:"true" json:"creator"`
+	Amount          uint64        `serialize:"true" json:"amount"`
+	ReceiverBalance uint64        `serialize:"true" json:"receiver_balance"`
+}
+
+func (*ClaimVestingResult) GetTypeID() uint8 {
+	return mconsts.ClaimVestingID
+}
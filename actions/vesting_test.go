@@ -0,0 +1,121 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestVestingActions(t *testing.T) {
+	creator := codectest.NewRandomAddress()
+	beneficiary := codectest.NewRandomAddress()
+	vestingID := ids.GenerateTestID()
+
+	funded := func() state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(context.Background(), store, creator, 10))
+		return store
+	}
+	// pending has 10 vesting to [beneficiary] from timestamp 100.
+	pending := func() state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetVesting(context.Background(), store, beneficiary, vestingID, &storage.Vesting{
+			Creator: creator,
+			Amount:  10,
+			Release: 100,
+		}))
+		return store
+	}
+	create := &CreateVesting{
+		VestingID:   vestingID,
+		Beneficiary: beneficiary,
+		Amount:      10,
+		Release:     100,
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:        "ReleaseInPast",
+			Actor:       creator,
+			Action:      create,
+			Timestamp:   100,
+			State:       funded(),
+			ExpectedErr: ErrReleaseInPast,
+		},
+		{
+			Name:        "VestingExists",
+			Actor:       creator,
+			Action:      create,
+			State:       pending(),
+			ExpectedErr: ErrVestingExists,
+		},
+		{
+			Name:      "Create",
+			Actor:     creator,
+			Action:    create,
+			Timestamp: 50,
+			State:     funded(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, creator)
+				require.NoError(t, err)
+				require.Zero(t, balance)
+				vesting, exists, err := storage.GetVesting(ctx, store, beneficiary, vestingID)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.Vesting{Creator: creator, Amount: 10, Release: 100}, vesting)
+			},
+			ExpectedOutputs: &CreateVestingResult{
+				SenderBalance: 0,
+				Release:       100,
+			},
+		},
+		{
+			Name:        "ClaimLocked",
+			Actor:       beneficiary,
+			Action:      &ClaimVesting{VestingID: vestingID},
+			Timestamp:   99,
+			State:       pending(),
+			ExpectedErr: ErrVestingLocked,
+		},
+		{
+			Name:        "ClaimOtherBeneficiary",
+			Actor:       creator,
+			Action:      &ClaimVesting{VestingID: vestingID},
+			Timestamp:   100,
+			State:       pending(),
+			ExpectedErr: ErrVestingNotFound,
+		},
+		{
+			Name:      "Claim",
+			Actor:     beneficiary,
+			Action:    &ClaimVesting{VestingID: vestingID},
+			Timestamp: 100,
+			State:     pending(),
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, beneficiary)
+				require.NoError(t, err)
+				require.Equal(t, uint64(10), balance)
+				_, exists, err := storage.GetVesting(ctx, store, beneficiary, vestingID)
+				require.NoError(t, err)
+				require.False(t, exists)
+			},
+			ExpectedOutputs: &ClaimVestingResult{
+				Creator:         creator,
+				Amount:          10,
+				ReceiverBalance: 10,
+			},
+		},
+	}
+
+	runActionTests(t, tests)
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package chaintest checks actions against the state keys they declare.
+package chaintest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/cost"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ state.Mutable = (*AuditedState)(nil)
+
+// AuditedState records the permission each key needs under the rules
+// transaction state enforces: Read to get a key, Write to insert or remove
+// one, and Allocate as well to insert a key that does not exist.
+type AuditedState struct {
+	state.Mutable
+
+	used map[string]state.Permissions
+}
+
+func NewAuditedState(mu state.Mutable) *AuditedState {
+	return &AuditedState{
+		Mutable: mu,
+		used:    make(map[string]state.Permissions),
+	}
+}
+
+func (a *AuditedState) GetValue(ctx context.Context, key []byte) ([]byte, error) {
+	a.used[string(key)] |= state.Read
+	return a.Mutable.GetValue(ctx, key)
+}
+
+func (a *AuditedState) Insert(ctx context.Context, key []byte, value []byte) error {
+	_, err := a.Mutable.GetValue(ctx, key)
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		a.used[string(key)] |= state.Allocate | state.Write
+	case err != nil:
+		return err
+	default:
+		a.used[string(key)] |= state.Write
+	}
+	return a.Mutable.Insert(ctx, key, value)
+}
+
+func (a *AuditedState) Remove(ctx context.Context, key []byte) error {
+	a.used[string(key)] |= state.Write
+	return a.Mutable.Remove(ctx, key)
+}
+
+// Used returns the keys touched so far, with the permissions they needed.
+func (a *AuditedState) Used() state.Keys {
+	keys := make(state.Keys, len(a.used))
+	for k, perm := range a.used {
+		keys[k] = perm
+	}
+	return keys
+}
+
+// KeyAudit runs action tests against an [AuditedState].
+//
+// Every run fails if Execute touches a key, or needs a permission, that
+// StateKeys did not declare. A declaration that is too broad only shows
+// across runs, since one run takes a single path through Execute, so
+// [KeyAudit.RequireNoExcess] compares the declared and used permissions of
+// all successful runs, by action type and key prefix.
+type KeyAudit struct {
+	declared map[string]state.Permissions
+	used     map[string]state.Permissions
+}
+
+func NewKeyAudit() *KeyAudit {
+	return &KeyAudit{
+		declared: make(map[string]state.Permissions),
+		used:     make(map[string]state.Permissions),
+	}
+}
+
+// Run executes [test] like [chaintest.ActionTest.Run], then checks the keys
+// Execute touched against the ones its action declared for the actor.
+// [test.Assertion] reads the unaudited state.
+func (k *KeyAudit) Run(ctx context.Context, t *testing.T, test chaintest.ActionTest) {
+	t.Run(test.Name, func(t *testing.T) {
+		require := require.New(t)
+
+		audited := NewAuditedState(test.State)
+		output, err := test.Action.Execute(ctx, test.Rules, audited, test.Timestamp, test.Actor, test.ActionID)
+
+		require.ErrorIs(err, test.ExpectedErr)
+		require.Equal(test.ExpectedOutputs, output)
+
+		declared := test.Action.StateKeys(test.Actor)
+		used := audited.Used()
+		for _, key := range sortedKeys(used) {
+			if !declared[key].Has(used[key]) {
+				t.Errorf("%s key %x needs %s but declares %s",
+					storage.PrefixName([]byte(key)), key, cost.PermissionNames(used[key]), cost.PermissionNames(declared[key]))
+			}
+		}
+		if err == nil {
+			name := reflect.TypeOf(test.Action).Elem().Name()
+			for key, perm := range declared {
+				k.declared[name+" "+storage.PrefixName([]byte(key))] |= perm
+			}
+			for key, perm := range used {
+				k.used[name+" "+storage.PrefixName([]byte(key))] |= perm
+			}
+		}
+
+		if test.Assertion != nil {
+			test.Assertion(ctx, t, test.State)
+		}
+	})
+}
+
+// RequireNoExcess fails if [KeyAudit.Excess] reports any declaration.
+func (k *KeyAudit) RequireNoExcess(t *testing.T) {
+	for _, excess := range k.Excess() {
+		t.Error(excess)
+	}
+}
+
+// Excess describes each permission declared in successful runs of an
+// action type that none of its keys of the same prefix needed.
+func (k *KeyAudit) Excess() []string {
+	var excess []string
+	for _, name := range sortedKeys(k.declared) {
+		if perm := k.declared[name] &^ k.used[name]; perm != state.None {
+			excess = append(excess, fmt.Sprintf("%s keys declare %s but never need it", name, cost.PermissionNames(perm)))
+		}
+	}
+	return excess
+}
+
+func sortedKeys(m map[string]state.Permissions) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaintest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/cost"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/genesis"
+)
+
+// Cost executes [test] like [chaintest.ActionTest.Run] and returns the
+// cost of its action, logging each key so that a run with -v shows where
+// the units go. Tests without rules are costed under the default rules.
+func Cost(ctx context.Context, t *testing.T, test chaintest.ActionTest) *cost.Report {
+	var report *cost.Report
+	t.Run(test.Name, func(t *testing.T) {
+		require := require.New(t)
+
+		rules := test.Rules
+		if rules == nil {
+			rules = genesis.NewDefaultRules()
+		}
+		audited := NewAuditedState(test.State)
+		output, err := test.Action.Execute(ctx, rules, audited, test.Timestamp, test.Actor, test.ActionID)
+		require.ErrorIs(err, test.ExpectedErr)
+		require.Equal(test.ExpectedOutputs, output)
+
+		report, err = cost.New(ctx, rules, test.Action, test.Actor, audited.Used(), test.State)
+		require.NoError(err)
+		t.Logf("bandwidth %d compute %d reads %d allocates %d writes %d",
+			report.Bandwidth, report.Compute, report.Reads, report.Allocates, report.Writes)
+		for _, k := range report.Keys {
+			t.Logf("%s key %x declares %s uses %s, %d of %d chunks, %d units",
+				k.Record, []byte(k.Key), k.Declared, k.Used, k.Chunks, k.MaxChunks, k.Units())
+		}
+
+		if test.Assertion != nil {
+			test.Assertion(ctx, t, test.State)
+		}
+	})
+	return report
+}
@@ -0,0 +1,288 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package client is a typed Go client of a MorpheusVM node, for dapps and
+// services that integrate with the chain.
+//
+// Reads return the chain's own types. Writes take a chain.AuthFactory as
+// the signer, such as auth.NewED25519Factory, and wait until the node has
+// accepted the transaction:
+//
+//	cli := client.New("http://127.0.0.1:9650/ext/bc/morpheusvm")
+//	result, err := cli.TransferAsset(ctx, signer, assetID, to, "gift")
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/vm"
+	"github.com/ava-labs/hypersdk/api/indexer"
+	"github.com/ava-labs/hypersdk/api/jsonrpc"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/fees"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+const defaultPollInterval = 100 * time.Millisecond
+
+var (
+	ErrTxFailed     = errors.New("transaction failed")
+	ErrTooManyTxs   = errors.New("too many identical transactions in the validity window")
+	ErrInvalidValue = errors.New("invalid value")
+)
+
+type Option func(*Client)
+
+// WithFeeMargin raises the max fee of transactions [percent] above the fee
+// at the unit prices when they are built, so they are still included if
+// prices rise before then.
+func WithFeeMargin(percent uint64) Option {
+	return func(c *Client) {
+		c.feeMargin = percent
+	}
+}
+
+// WithPollInterval sets how often the client checks whether a submitted
+// transaction was accepted.
+func WithPollInterval(d time.Duration) Option {
+	return func(c *Client) {
+		c.pollInterval = d
+	}
+}
+
+// Client talks to one node. It is safe for concurrent use.
+type Client struct {
+	core    *jsonrpc.JSONRPCClient
+	vm      *vm.JSONRPCClient
+	indexer *indexer.Client
+
+	feeMargin    uint64
+	pollInterval time.Duration
+
+	lock   sync.Mutex
+	parser chain.Parser
+	// expiries holds the transactions built in the validity window, by the
+	// hash of their signer and contents, with their expiry.
+	expiries map[[sha256.Size]byte]int64
+}
+
+// New returns a client of the node serving the chain at [uri].
+func New(uri string, opts ...Option) *Client {
+	c := &Client{
+		core:         jsonrpc.NewJSONRPCClient(uri),
+		vm:           vm.NewJSONRPCClient(uri),
+		indexer:      indexer.NewClient(uri),
+		pollInterval: defaultPollInterval,
+		expiries:     make(map[[sha256.Size]byte]int64),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// VM returns the client of the MorpheusVM API, for the queries this package
+// does not wrap.
+func (c *Client) VM() *vm.JSONRPCClient {
+	return c.vm
+}
+
+// Core returns the client of the hypersdk core API.
+func (c *Client) Core() *jsonrpc.JSONRPCClient {
+	return c.core
+}
+
+func (c *Client) Balance(ctx context.Context, addr codec.Address) (uint64, error) {
+	return c.vm.Balance(ctx, addr)
+}
+
+func (c *Client) AssetBalance(ctx context.Context, addr codec.Address, asset ids.ID) (uint64, error) {
+	return c.vm.AssetBalance(ctx, addr, asset)
+}
+
+func (c *Client) AssetMetadata(ctx context.Context, asset ids.ID) (*vm.AssetMetadataReply, error) {
+	return c.vm.AssetMetadata(ctx, asset)
+}
+
+func (c *Client) AssetsByOwner(ctx context.Context, owner codec.Address) ([]ids.ID, error) {
+	return c.vm.AllAssetsByOwner(ctx, owner)
+}
+
+// Result is the outcome of an accepted transaction.
+type Result struct {
+	TxID    ids.ID
+	Success bool
+	Fee     uint64
+	// Outputs are the outputs of the actions, in order, when the
+	// transaction succeeded.
+	Outputs []codec.Typed
+}
+
+// Transfer sends [amount] native tokens to [to].
+func (c *Client) Transfer(ctx context.Context, signer chain.AuthFactory, to codec.Address, amount uint64, memo []byte) (*Result, error) {
+	return c.Send(ctx, signer, &actions.Transfer{To: to, Value: amount, Memo: memo})
+}
+
+// TransferAsset sends the asset [assetID] to [to], with [reason] on record.
+func (c *Client) TransferAsset(ctx context.Context, signer chain.AuthFactory, assetID ids.ID, to codec.Address, reason string) (*Result, error) {
+	return c.Send(ctx, signer, &actions.AssetTransfer{Recipient: to, Asset: assetID, Reason: reason})
+}
+
+// Simulate runs [acts] as [actor] against the current state without
+// submitting them.
+func (c *Client) Simulate(ctx context.Context, actor codec.Address, acts ...chain.Action) ([]jsonrpc.SimulateActionResult, error) {
+	return c.core.SimulateActions(ctx, acts, actor)
+}
+
+// SimulateCosts is [Client.Simulate] with the cost of each action, broken
+// down by fee dimension and state key.
+func (c *Client) SimulateCosts(ctx context.Context, actor codec.Address, acts ...chain.Action) ([]vm.SimulateCostResult, error) {
+	return c.vm.SimulateCosts(ctx, acts, actor)
+}
+
+// Send submits a transaction of [acts] signed by [signer] and waits until it
+// is accepted. A transaction accepted without success returns its [Result]
+// and [ErrTxFailed].
+func (c *Client) Send(ctx context.Context, signer chain.AuthFactory, acts ...chain.Action) (*Result, error) {
+	tx, err := c.Build(ctx, signer, acts...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.core.SubmitTx(ctx, tx.Bytes()); err != nil {
+		return nil, err
+	}
+	return c.Wait(ctx, tx.ID())
+}
+
+// Build returns a signed transaction of [acts] without submitting it. Its
+// max fee is the fee at the current unit prices, plus the fee margin, and
+// its expiry differs from that of any identical transaction the client
+// built in the validity window, so the two get different IDs.
+func (c *Client) Build(ctx context.Context, signer chain.AuthFactory, acts ...chain.Action) (*chain.Transaction, error) {
+	parser, err := c.getParser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	unitPrices, err := c.core.UnitPrices(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixMilli()
+	rules := parser.Rules(now)
+	units, err := chain.EstimateUnits(rules, acts, signer)
+	if err != nil {
+		return nil, err
+	}
+	fee, err := fees.MulSum(unitPrices, units)
+	if err != nil {
+		return nil, err
+	}
+	maxFee, err := addMargin(fee, c.feeMargin)
+	if err != nil {
+		return nil, err
+	}
+	base := &chain.Base{ChainID: rules.GetChainID(), MaxFee: maxFee}
+	unsigned, err := c.newTx(base, acts, signer.Address(), now, rules.GetValidityWindow())
+	if err != nil {
+		return nil, err
+	}
+	return unsigned.Sign(signer, parser.ActionCodec(), parser.AuthCodec())
+}
+
+// Wait returns the [Result] of [txID] once the node has accepted it.
+func (c *Client) Wait(ctx context.Context, txID ids.ID) (*Result, error) {
+	var (
+		resp  indexer.GetTxResponse
+		found bool
+	)
+	if err := jsonrpc.Wait(ctx, c.pollInterval, func(ctx context.Context) (bool, error) {
+		var err error
+		resp, found, err = c.indexer.GetTx(ctx, txID)
+		return found, err
+	}); err != nil {
+		return nil, err
+	}
+	parser, err := c.getParser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{TxID: txID, Success: resp.Success, Fee: resp.Fee}
+	for i, b := range resp.Outputs {
+		output, err := parser.OutputCodec().Unmarshal(codec.NewReader(b, consts.NetworkSizeLimit))
+		if err != nil {
+			return nil, fmt.Errorf("output %d of %s: %w", i, txID, err)
+		}
+		result.Outputs = append(result.Outputs, output)
+	}
+	if !result.Success {
+		return result, fmt.Errorf("%w: %s", ErrTxFailed, txID)
+	}
+	return result, nil
+}
+
+// getParser returns the parser of the chain's genesis, fetching it once.
+func (c *Client) getParser(ctx context.Context) (chain.Parser, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.parser != nil {
+		return c.parser, nil
+	}
+	parser, err := c.vm.Parser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.parser = parser
+	return parser, nil
+}
+
+// newTx returns an unsigned transaction of [base] and [acts] that expires at
+// the latest second in the validity window no identical transaction from
+// [actor] expires at. Transactions have no nonce, so an identical
+// transaction with the same expiry would have the same ID and be dropped as
+// a duplicate.
+func (c *Client) newTx(base *chain.Base, acts []chain.Action, actor codec.Address, now int64, validityWindow int64) (*chain.Transaction, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for key, expiry := range c.expiries {
+		if expiry <= now {
+			delete(c.expiries, key)
+		}
+	}
+	for expiry := now + validityWindow - (now+validityWindow)%consts.MillisecondsPerSecond; expiry > now; expiry -= consts.MillisecondsPerSecond {
+		b := *base
+		b.Timestamp = expiry
+		tx := chain.NewTx(&b, acts)
+		unsigned, err := tx.UnsignedBytes()
+		if err != nil {
+			return nil, err
+		}
+		key := sha256.Sum256(append(actor[:], unsigned...))
+		if _, ok := c.expiries[key]; !ok {
+			c.expiries[key] = expiry
+			return tx, nil
+		}
+	}
+	return nil, ErrTooManyTxs
+}
+
+// addMargin returns [fee] raised by [percent].
+func addMargin(fee uint64, percent uint64) (uint64, error) {
+	margin, err := smath.Mul(fee, percent)
+	if err != nil {
+		return 0, fmt.Errorf("%w: fee margin of %d%% on %d", ErrInvalidValue, percent, fee)
+	}
+	return smath.Add(fee, margin/100)
+}
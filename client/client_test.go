@@ -0,0 +1,67 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+)
+
+func TestAddMargin(t *testing.T) {
+	require := require.New(t)
+
+	fee, err := addMargin(1_000, 0)
+	require.NoError(err)
+	require.Equal(uint64(1_000), fee)
+	fee, err = addMargin(1_000, 25)
+	require.NoError(err)
+	require.Equal(uint64(1_250), fee)
+	_, err = addMargin(math.MaxUint64, 25)
+	require.ErrorIs(err, ErrInvalidValue)
+}
+
+func TestNewTxExpiries(t *testing.T) {
+	require := require.New(t)
+	c := New("http://127.0.0.1:9650")
+	actor, other := codectest.NewRandomAddress(), codectest.NewRandomAddress()
+	acts := []chain.Action{&actions.Transfer{To: codectest.NewRandomAddress(), Value: 1}}
+	const (
+		now    = int64(10_500)
+		window = int64(3_000)
+	)
+
+	// Identical transactions of an actor count down from the end of the
+	// window, one second apart.
+	var expiries []int64
+	for {
+		tx, err := c.newTx(&chain.Base{MaxFee: 1}, acts, actor, now, window)
+		if err != nil {
+			require.ErrorIs(err, ErrTooManyTxs)
+			break
+		}
+		expiries = append(expiries, tx.Base.Timestamp)
+	}
+	require.Equal([]int64{13_000, 12_000, 11_000}, expiries)
+
+	// Other actors and other contents are not affected.
+	tx, err := c.newTx(&chain.Base{MaxFee: 1}, acts, other, now, window)
+	require.NoError(err)
+	require.Equal(int64(13_000), tx.Base.Timestamp)
+	tx, err = c.newTx(&chain.Base{MaxFee: 2}, acts, actor, now, window)
+	require.NoError(err)
+	require.Equal(int64(13_000), tx.Base.Timestamp)
+
+	// Expired transactions are forgotten.
+	require.Len(c.expiries, 5)
+	tx, err = c.newTx(&chain.Base{MaxFee: 1}, acts, actor, 12_000, window)
+	require.NoError(err)
+	require.Equal(int64(15_000), tx.Base.Timestamp)
+	require.Len(c.expiries, 4)
+}
@@ -0,0 +1,21 @@
+# Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+# See the file LICENSE for licensing terms.
+
+"""A minimal Python client of MorpheusVM: it marshals actions by the VM's
+ABI, signs and submits transactions and decodes their results."""
+
+from .client import Client, RPCError
+from .codec import CodecError, Marshaler, Typed, address_from_public_key
+from .ed25519 import public_key
+from .transaction import Transaction
+
+__all__ = [
+    "Client",
+    "CodecError",
+    "Marshaler",
+    "RPCError",
+    "Transaction",
+    "Typed",
+    "address_from_public_key",
+    "public_key",
+]
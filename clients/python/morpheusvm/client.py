@@ -0,0 +1,156 @@
+# Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+# See the file LICENSE for licensing terms.
+
+"""A JSON-RPC client of a MorpheusVM chain."""
+
+import base64
+import json
+import time
+import urllib.request
+
+from . import ed25519, transaction
+from .codec import Marshaler, address_from_public_key, format_address
+
+CORE_ENDPOINT = "/coreapi"
+INDEXER_ENDPOINT = "/indexer"
+JSONRPC_ENDPOINT = "/morpheusapi"
+
+
+class RPCError(Exception):
+    """Raised for the errors the VM replies with."""
+
+
+class Client:
+    """Talks to the chain at [uri], such as
+    http://127.0.0.1:9650/ext/bc/morpheusvm."""
+
+    def __init__(self, uri, timeout=10):
+        self.uri = uri.rstrip("/")
+        self.timeout = timeout
+        self._marshaler = None
+        self._chain_id = None
+        self._next_id = 0
+
+    def _call(self, endpoint, method, params=None):
+        self._next_id += 1
+        body = json.dumps({"jsonrpc": "2.0", "id": self._next_id, "method": method, "params": params or {}})
+        req = urllib.request.Request(
+            self.uri + endpoint,
+            data=body.encode(),
+            headers={"Content-Type": "application/json"},
+        )
+        with urllib.request.urlopen(req, timeout=self.timeout) as resp:
+            reply = json.load(resp)
+        if reply.get("error"):
+            raise RPCError(reply["error"].get("message", reply["error"]))
+        return reply["result"]
+
+    def call(self, method, params=None):
+        """Calls a morpheusvm.* method, such as "balance"."""
+        return self._call(JSONRPC_ENDPOINT, "morpheusvm." + method, params)
+
+    def network(self):
+        return self._call(CORE_ENDPOINT, "hypersdk.network")
+
+    def chain_id(self):
+        if self._chain_id is None:
+            self._chain_id = self.network()["chainId"]
+        return self._chain_id
+
+    def abi(self):
+        return self._call(CORE_ENDPOINT, "hypersdk.getABI")["abi"]
+
+    def marshaler(self):
+        """Returns the marshaler of the VM's ABI, fetching it once."""
+        if self._marshaler is None:
+            self._marshaler = Marshaler(self.abi())
+        return self._marshaler
+
+    def unit_prices(self):
+        return self._call(CORE_ENDPOINT, "hypersdk.unitPrices")["unitPrices"]
+
+    def balance(self, address):
+        return self.call("balance", {"address": _address(address)})["amount"]
+
+    def balances(self, addresses):
+        """Returns the balances of [addresses], in order, read at one height."""
+        return self.call("balances", {"addresses": [_address(a) for a in addresses]})["amounts"]
+
+    def execute_actions(self, actor, actions):
+        """Runs [actions], (name, value) pairs, against the current state as
+        [actor] would, without submitting them, and returns their outputs."""
+        m = self.marshaler()
+        reply = self._call(
+            CORE_ENDPOINT,
+            "hypersdk.executeActions",
+            {
+                "actor": _address(actor),
+                "actions": [base64.b64encode(m.encode_action(n, v)).decode() for n, v in actions],
+            },
+        )
+        if reply.get("error"):
+            raise RPCError(reply["error"])
+        return [m.decode_output(base64.b64decode(o)) for o in reply["outputs"]]
+
+    def suggest_fee(self, actor, name, value):
+        """Returns the max fee the VM suggests for a transaction of the single
+        action [name] signed by the ed25519 key of [actor]."""
+        # A round trip through the codec gives the JSON form the VM decodes.
+        m = self.marshaler()
+        action = m.decode_action(m.encode_action(name, value)).value
+        return self.call("suggestFee", {"type": name, "action": action, "actor": _address(actor)})["maxFee"]
+
+    def sign(self, seed, actions, max_fee=None, timestamp=None):
+        """Signs a transaction of [actions] with the ed25519 key [seed].
+
+        Without a max fee, it is the sum of the fees suggested for each
+        action. It overestimates, as each includes the transaction overhead,
+        but only the fee used is charged.
+        """
+        actor = address_from_public_key(ed25519.public_key(seed))
+        if max_fee is None:
+            max_fee = sum(self.suggest_fee(actor, n, v) for n, v in actions)
+        return transaction.sign(self.marshaler(), seed, self.chain_id(), max_fee, actions, timestamp)
+
+    def submit(self, tx):
+        """Submits the signed transaction [tx] and returns its ID."""
+        return self._call(
+            CORE_ENDPOINT,
+            "hypersdk.submitTx",
+            {"tx": base64.b64encode(tx.bytes).decode()},
+        )["txId"]
+
+    def send_transaction(self, seed, actions, max_fee=None, wait=True):
+        """Signs and submits a transaction of [actions]. It returns the
+        result of [wait_for_tx] or, without waiting, the transaction."""
+        tx = self.sign(seed, actions, max_fee)
+        self.submit(tx)
+        return self.wait_for_tx(tx.id) if wait else tx
+
+    def get_tx(self, tx_id):
+        """Returns an accepted transaction with one decoded output per
+        action under "result", or None if the indexer has not seen it."""
+        try:
+            reply = self._call(INDEXER_ENDPOINT, "indexer.getTx", {"txId": tx_id})
+        except RPCError as e:
+            if "tx not found" in str(e):
+                return None
+            raise
+        m = self.marshaler()
+        reply["result"] = [m.decode_output(bytes.fromhex(o)) for o in reply.get("result") or []]
+        return reply
+
+    def wait_for_tx(self, tx_id, timeout=60, interval=0.5):
+        deadline = time.monotonic() + timeout
+        while True:
+            tx = self.get_tx(tx_id)
+            if tx is not None:
+                return tx
+            if time.monotonic() > deadline:
+                raise TimeoutError(f"transaction {tx_id} not accepted after {timeout}s")
+            time.sleep(interval)
+
+
+def _address(addr):
+    return addr if isinstance(addr, str) else format_address(addr)
+
@@ -0,0 +1,258 @@
+# Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+# See the file LICENSE for licensing terms.
+
+"""Marshals actions and outputs by the ABI the VM serves.
+
+Values are dicts in the JSON form the VM's API uses: addresses are 0x and
+66 hex digits, IDs are cb58 strings and byte slices are base64 strings.
+Encoding also takes bytes for addresses, IDs and byte slices.
+"""
+
+import base64
+import hashlib
+from typing import NamedTuple
+
+ADDRESS_LEN = 33
+ID_LEN = 32
+
+# Type byte of addresses of ed25519 keys.
+ED25519_ID = 0
+
+# First byte of asset records, which tags derived asset IDs.
+ASSET_PREFIX = 0x4
+
+# First byte of app records, which tags app IDs.
+APP_PREFIX = 0x21
+
+_INTS = {
+    "uint8": (1, False),
+    "uint16": (2, False),
+    "uint32": (4, False),
+    "uint64": (8, False),
+    "int8": (1, True),
+    "int16": (2, True),
+    "int32": (4, True),
+    "int64": (8, True),
+}
+
+_B58 = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+
+class CodecError(ValueError):
+    """Raised for values and bytes that do not match the ABI."""
+
+
+class Typed(NamedTuple):
+    """An action or output: the name of its type in the ABI, and its value."""
+
+    name: str
+    value: dict
+
+
+def cb58_encode(b):
+    """Encodes [b] as avalanchego does IDs: base58 with a 4-byte checksum."""
+    b = bytes(b) + hashlib.sha256(b).digest()[-4:]
+    n = int.from_bytes(b, "big")
+    out = ""
+    while n > 0:
+        n, r = divmod(n, 58)
+        out = _B58[r] + out
+    zeros = len(b) - len(b.lstrip(b"\0"))
+    return "1" * zeros + out
+
+
+def cb58_decode(s):
+    n = 0
+    for c in s:
+        i = _B58.find(c)
+        if i < 0:
+            raise CodecError(f"invalid cb58 character {c!r}")
+        n = n * 58 + i
+    zeros = len(s) - len(s.lstrip("1"))
+    b = b"\0" * zeros + (n.to_bytes((n.bit_length() + 7) // 8, "big") if n else b"")
+    if len(b) < 4 or hashlib.sha256(b[:-4]).digest()[-4:] != b[-4:]:
+        raise CodecError(f"bad cb58 checksum in {s!r}")
+    return b[:-4]
+
+
+def address_from_public_key(pub):
+    """Returns the address of the ed25519 public key [pub]."""
+    return bytes([ED25519_ID]) + hashlib.sha256(pub).digest()
+
+
+def asset_id(creator, nonce, app=None):
+    """Returns the ID of the asset [creator] creates with CreateAsset and
+    [nonce], in [app] if given, known before the transaction is accepted."""
+    scope = parse_id(app) if app is not None else b""
+    return hashlib.sha256(bytes([ASSET_PREFIX]) + scope + parse_address(creator) + nonce.to_bytes(8, "big")).digest()
+
+
+def app_id(name):
+    """Returns the ID of the app registered with RegisterApp under [name]."""
+    return hashlib.sha256(bytes([APP_PREFIX]) + name.encode()).digest()
+
+
+def format_address(addr):
+    return "0x" + bytes(addr).hex()
+
+
+def parse_address(addr):
+    """Parses an address given as bytes or as 66 hex digits, with or
+    without 0x."""
+    if isinstance(addr, str):
+        s = addr[2:] if addr.startswith("0x") else addr
+        try:
+            addr = bytes.fromhex(s)
+        except ValueError as e:
+            raise CodecError(f"invalid address {addr!r}") from e
+    if len(addr) != ADDRESS_LEN:
+        raise CodecError(f"address must be {ADDRESS_LEN} bytes, not {len(addr)}")
+    return bytes(addr)
+
+
+def parse_id(value):
+    """Parses an ID given as bytes or as a cb58 string."""
+    b = cb58_decode(value) if isinstance(value, str) else bytes(value)
+    if len(b) != ID_LEN:
+        raise CodecError(f"ID must be {ID_LEN} bytes, not {len(b)}")
+    return b
+
+
+class Reader:
+    """Reads values off bytes, front to back."""
+
+    def __init__(self, b):
+        self._b = bytes(b)
+        self.offset = 0
+
+    def read(self, n):
+        if self.offset + n > len(self._b):
+            raise CodecError(f"need {n} bytes at offset {self.offset}, have {self.remaining()}")
+        b = self._b[self.offset : self.offset + n]
+        self.offset += n
+        return b
+
+    def remaining(self):
+        return len(self._b) - self.offset
+
+    def empty(self):
+        return self.remaining() == 0
+
+
+class Marshaler:
+    """Encodes and decodes the actions and outputs of an ABI, as returned by
+    the hypersdk.getABI method."""
+
+    def __init__(self, abi):
+        self._types = {t["name"]: t["fields"] for t in abi["types"]}
+        self._actions = {a["name"]: a["id"] for a in abi["actions"]}
+        self._action_names = {a["id"]: a["name"] for a in abi["actions"]}
+        self._outputs = {o["name"]: o["id"] for o in abi["outputs"]}
+        self._output_names = {o["id"]: o["name"] for o in abi["outputs"]}
+
+    def action_names(self):
+        return list(self._actions)
+
+    def encode_action(self, name, value):
+        """Returns the type byte of action [name] followed by [value]."""
+        return self._encode_typed(self._actions, "action", name, value)
+
+    def encode_output(self, name, value):
+        return self._encode_typed(self._outputs, "output", name, value)
+
+    def decode_action(self, r):
+        """Decodes a typed action off a [Reader] or bytes."""
+        return self._decode_typed(self._action_names, "action", r)
+
+    def decode_output(self, r):
+        """Decodes a typed output, such as a transaction result, off a
+        [Reader] or bytes."""
+        return self._decode_typed(self._output_names, "output", r)
+
+    def _encode_typed(self, ids, kind, name, value):
+        if name not in ids:
+            raise CodecError(f"unknown {kind} {name!r}")
+        out = bytearray([ids[name]])
+        self._encode(out, name, value)
+        return bytes(out)
+
+    def _decode_typed(self, names, kind, r):
+        whole = not isinstance(r, Reader)
+        if whole:
+            r = Reader(r)
+        type_id = r.read(1)[0]
+        if type_id not in names:
+            raise CodecError(f"unknown {kind} type {type_id}")
+        value = self._decode(r, names[type_id])
+        if whole and not r.empty():
+            raise CodecError(f"{r.remaining()} extra bytes after {kind} {names[type_id]}")
+        return Typed(names[type_id], value)
+
+    def _encode(self, out, typ, value):
+        if typ in _INTS:
+            size, signed = _INTS[typ]
+            try:
+                out += int(value).to_bytes(size, "big", signed=signed)
+            except OverflowError as e:
+                raise CodecError(f"{value} does not fit {typ}") from e
+        elif typ == "bool":
+            out.append(1 if value else 0)
+        elif typ == "string":
+            b = value.encode()
+            out += len(b).to_bytes(2, "big") + b
+        elif typ == "Address":
+            out += parse_address(value)
+        elif typ == "ID":
+            out += parse_id(value)
+        elif typ == "[]uint8":
+            b = base64.b64decode(value) if isinstance(value, str) else bytes(value)
+            out += len(b).to_bytes(4, "big") + b
+        elif typ.startswith("[]"):
+            value = value or []
+            out += len(value).to_bytes(4, "big")
+            for v in value:
+                self._encode(out, typ[2:], v)
+        elif typ in self._types:
+            for field in self._types[typ]:
+                self._encode(out, field["type"], (value or {}).get(field["name"], _zero(field["type"])))
+        else:
+            raise CodecError(f"unknown type {typ!r}")
+
+    def _decode(self, r, typ):
+        if typ in _INTS:
+            size, signed = _INTS[typ]
+            return int.from_bytes(r.read(size), "big", signed=signed)
+        if typ == "bool":
+            return r.read(1) != b"\0"
+        if typ == "string":
+            return r.read(int.from_bytes(r.read(2), "big")).decode()
+        if typ == "Address":
+            return format_address(r.read(ADDRESS_LEN))
+        if typ == "ID":
+            return cb58_encode(r.read(ID_LEN))
+        if typ == "[]uint8":
+            return base64.b64encode(r.read(int.from_bytes(r.read(4), "big"))).decode()
+        if typ.startswith("[]"):
+            return [self._decode(r, typ[2:]) for _ in range(int.from_bytes(r.read(4), "big"))]
+        if typ in self._types:
+            return {f["name"]: self._decode(r, f["type"]) for f in self._types[typ]}
+        raise CodecError(f"unknown type {typ!r}")
+
+
+def _zero(typ):
+    """Returns the value fields left out of an action are encoded as."""
+    if typ in _INTS:
+        return 0
+    if typ == "bool":
+        return False
+    if typ == "string":
+        return ""
+    if typ == "Address":
+        return bytes(ADDRESS_LEN)
+    if typ == "ID":
+        return bytes(ID_LEN)
+    if typ == "[]uint8":
+        return b""
+    if typ.startswith("[]"):
+        return []
+    return {}
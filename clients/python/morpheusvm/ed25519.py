@@ -0,0 +1,95 @@
+# Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+# See the file LICENSE for licensing terms.
+
+"""Ed25519 signing, after the reference implementation of RFC 8032.
+
+It is pure Python so the client has no dependencies. It is not constant
+time: use it to sign from scripts and notebooks, not to guard keys in a
+service.
+"""
+
+import hashlib
+
+SEED_LEN = 32
+PUBLIC_KEY_LEN = 32
+SIGNATURE_LEN = 64
+
+_P = 2**255 - 19
+_L = 2**252 + 27742317777372353535851937790883648493
+_D = -121665 * pow(121666, _P - 2, _P) % _P
+_SQRT_M1 = pow(2, (_P - 1) // 4, _P)
+
+
+def _recover_x(y, sign):
+    x2 = (y * y - 1) * pow(_D * y * y + 1, _P - 2, _P)
+    x = pow(x2, (_P + 3) // 8, _P)
+    if (x * x - x2) % _P != 0:
+        x = x * _SQRT_M1 % _P
+    if x & 1 != sign:
+        x = _P - x
+    return x
+
+
+_GY = 4 * pow(5, _P - 2, _P) % _P
+_GX = _recover_x(_GY, 0)
+# Points are kept in extended coordinates (X, Y, Z, T).
+_G = (_GX, _GY, 1, _GX * _GY % _P)
+
+
+def _add(a, b):
+    x1, y1, z1, t1 = a
+    x2, y2, z2, t2 = b
+    a_ = (y1 - x1) * (y2 - x2) % _P
+    b_ = (y1 + x1) * (y2 + x2) % _P
+    c = 2 * t1 * t2 * _D % _P
+    d = 2 * z1 * z2 % _P
+    e, f, g, h = b_ - a_, d - c, d + c, b_ + a_
+    return (e * f % _P, g * h % _P, f * g % _P, e * h % _P)
+
+
+def _mul(s, point):
+    q = (0, 1, 1, 0)
+    while s > 0:
+        if s & 1:
+            q = _add(q, point)
+        point = _add(point, point)
+        s >>= 1
+    return q
+
+
+def _compress(point):
+    x, y, z, _ = point
+    zinv = pow(z, _P - 2, _P)
+    x, y = x * zinv % _P, y * zinv % _P
+    return int.to_bytes(y | ((x & 1) << 255), 32, "little")
+
+
+def _sha512_int(*parts):
+    return int.from_bytes(hashlib.sha512(b"".join(parts)).digest(), "little")
+
+
+def _expand(seed):
+    if len(seed) != SEED_LEN:
+        raise ValueError(f"ed25519 seed must be {SEED_LEN} bytes, not {len(seed)}")
+    h = hashlib.sha512(seed).digest()
+    a = int.from_bytes(h[:32], "little")
+    a &= (1 << 254) - 8
+    a |= 1 << 254
+    return a, h[32:]
+
+
+def public_key(seed):
+    """Returns the public key of the private key [seed]."""
+    a, _ = _expand(seed)
+    return _compress(_mul(a, _G))
+
+
+def sign(seed, msg):
+    """Signs [msg] with the private key [seed]."""
+    a, prefix = _expand(seed)
+    pub = _compress(_mul(a, _G))
+    r = _sha512_int(prefix, msg) % _L
+    r_point = _compress(_mul(r, _G))
+    h = _sha512_int(r_point, pub, msg) % _L
+    s = (r + h * a) % _L
+    return r_point + int.to_bytes(s, 32, "little")
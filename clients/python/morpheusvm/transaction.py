@@ -0,0 +1,90 @@
+# Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+# See the file LICENSE for licensing terms.
+
+"""Builds, signs and decodes transactions.
+
+A transaction is its base (timestamp, chain ID and max fee), its actions,
+each a type byte and the action, and its auth. Only ed25519 auth is
+supported: the type byte 0, the public key and a signature of everything
+before it.
+"""
+
+import hashlib
+import time
+from typing import List, NamedTuple
+
+from . import ed25519
+from .codec import ED25519_ID, ID_LEN, CodecError, Reader, Typed, address_from_public_key, cb58_encode, parse_id
+
+# The VM takes transactions whose timestamp is at most its validity window
+# ahead, 60 seconds by default, and in whole seconds.
+DEFAULT_VALIDITY_WINDOW_MS = 60_000
+
+
+class Transaction(NamedTuple):
+    timestamp: int
+    chain_id: str
+    max_fee: int
+    actions: List[Typed]
+    # The address of the signer.
+    actor: str
+    id: str
+    bytes: bytes
+
+
+def default_timestamp(validity_window_ms=DEFAULT_VALIDITY_WINDOW_MS):
+    """Returns the latest timestamp the VM currently takes, which gives a
+    transaction the longest time to be included."""
+    ms = int(time.time() * 1000) + validity_window_ms
+    return ms - ms % 1000
+
+
+def marshal_unsigned(marshaler, timestamp, chain_id, max_fee, actions):
+    """Returns the bytes a transaction signs: its base and [actions], a list
+    of (name, value) pairs."""
+    if timestamp % 1000 != 0:
+        raise CodecError(f"timestamp {timestamp} is not in whole seconds")
+    if len(actions) > 255:
+        raise CodecError(f"{len(actions)} actions do not fit a transaction")
+    out = bytearray()
+    out += timestamp.to_bytes(8, "big", signed=True)
+    out += parse_id(chain_id)
+    out += max_fee.to_bytes(8, "big")
+    out.append(len(actions))
+    for name, value in actions:
+        out += marshaler.encode_action(name, value)
+    return bytes(out)
+
+
+def sign(marshaler, seed, chain_id, max_fee, actions, timestamp=None):
+    """Signs a transaction of [actions] with the ed25519 key [seed]."""
+    if timestamp is None:
+        timestamp = default_timestamp()
+    unsigned = marshal_unsigned(marshaler, timestamp, chain_id, max_fee, actions)
+    tx = unsigned + bytes([ED25519_ID]) + ed25519.public_key(seed) + ed25519.sign(seed, unsigned)
+    return decode(marshaler, tx)
+
+
+def decode(marshaler, tx):
+    """Decodes the transaction bytes [tx]. The signature is not verified."""
+    r = Reader(tx)
+    timestamp = int.from_bytes(r.read(8), "big", signed=True)
+    chain_id = cb58_encode(r.read(ID_LEN))
+    max_fee = int.from_bytes(r.read(8), "big")
+    actions = [marshaler.decode_action(r) for _ in range(r.read(1)[0])]
+    auth = r.read(1)[0]
+    if auth != ED25519_ID:
+        raise CodecError(f"auth type {auth} is not ed25519")
+    pub = r.read(ed25519.PUBLIC_KEY_LEN)
+    r.read(ed25519.SIGNATURE_LEN)
+    if not r.empty():
+        raise CodecError(f"{r.remaining()} extra bytes after transaction")
+    return Transaction(
+        timestamp=timestamp,
+        chain_id=chain_id,
+        max_fee=max_fee,
+        actions=actions,
+        actor="0x" + address_from_public_key(pub).hex(),
+        id=cb58_encode(hashlib.sha256(tx).digest()),
+        bytes=bytes(tx),
+    )
@@ -0,0 +1,122 @@
+# Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+# See the file LICENSE for licensing terms.
+
+"""Checks the codec against the vectors of tests/vectors, which the Go codec
+produces."""
+
+import base64
+import json
+import pathlib
+import unittest
+
+from morpheusvm import ed25519, transaction
+from morpheusvm.codec import CodecError, Marshaler, address_from_public_key, app_id, asset_id, cb58_decode, cb58_encode
+
+VECTORS = pathlib.Path(__file__).resolve().parents[3] / "tests" / "vectors" / "testdata" / "vectors.json"
+
+
+def load():
+    with open(VECTORS) as f:
+        return json.load(f)
+
+
+class TestEd25519(unittest.TestCase):
+    def test_rfc8032(self):
+        # Test 1 of RFC 8032, section 7.1.
+        seed = bytes.fromhex("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+        self.assertEqual(
+            ed25519.public_key(seed).hex(),
+            "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+        )
+        self.assertEqual(
+            ed25519.sign(seed, b"").hex(),
+            "e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e06522490155"
+            "5fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b",
+        )
+
+
+class TestVectors(unittest.TestCase):
+    @classmethod
+    def setUpClass(cls):
+        cls.vectors = load()
+        cls.marshaler = Marshaler(cls.vectors["abi"])
+
+    def check_typed(self, vec, decode, encode):
+        b = bytes.fromhex(vec["bytes"])
+        typed = decode(b)
+        self.assertEqual(b[0], vec["typeId"])
+        self.assertEqual(typed.value, vec["value"])
+        self.assertEqual(encode(typed.name, vec["value"]).hex(), vec["bytes"])
+
+    def test_actions(self):
+        for vec in self.vectors["actions"]:
+            with self.subTest(vec["name"]):
+                self.check_typed(vec, self.marshaler.decode_action, self.marshaler.encode_action)
+
+    def test_outputs(self):
+        for vec in self.vectors["outputs"]:
+            with self.subTest(vec["name"]):
+                self.check_typed(vec, self.marshaler.decode_output, self.marshaler.encode_output)
+
+    def test_transactions(self):
+        for vec in self.vectors["transactions"]:
+            with self.subTest(vec["name"]):
+                seed = bytes.fromhex(vec["seed"])
+                decoded = [self.marshaler.decode_action(bytes.fromhex(a["bytes"])) for a in vec["actions"]]
+                tx = transaction.sign(
+                    self.marshaler,
+                    seed,
+                    vec["chainId"],
+                    vec["maxFee"],
+                    decoded,
+                    timestamp=vec["timestamp"],
+                )
+                self.assertEqual(tx.bytes.hex(), vec["bytes"])
+                self.assertEqual(tx.id, vec["id"])
+                self.assertEqual(tx.actor, "0x" + address_from_public_key(ed25519.public_key(seed)).hex())
+                self.assertEqual([a.value for a in tx.actions], [a["value"] for a in vec["actions"]])
+
+    def test_asset_id(self):
+        vec = next(v for v in self.vectors["keys"] if v["name"] == "AssetKey/derived")
+        key = bytes.fromhex(vec["bytes"])
+        self.assertEqual(asset_id(vec["value"]["creator"], vec["value"]["nonce"]), key[1:33])
+
+    def test_app_asset_id(self):
+        vec = next(v for v in self.vectors["keys"] if v["name"] == "AssetKey/app")
+        key = bytes.fromhex(vec["bytes"])
+        app = app_id(vec["value"]["appName"])
+        self.assertEqual(asset_id(vec["value"]["creator"], vec["value"]["nonce"], app), key[1:33])
+
+    def test_omitted_fields_are_zero(self):
+        zero = next(v for v in self.vectors["actions"] if v["name"] == "Transfer/zero")
+        self.assertEqual(self.marshaler.encode_action("Transfer", {}).hex(), zero["bytes"])
+
+    def test_bytes_inputs(self):
+        vec = next(v for v in self.vectors["actions"] if v["name"] == "Transfer")
+        value = dict(vec["value"])
+        value["to"] = bytes.fromhex(value["to"][2:])
+        value["memo"] = base64.b64decode(value["memo"])
+        self.assertEqual(self.marshaler.encode_action("Transfer", value).hex(), vec["bytes"])
+
+    def test_errors(self):
+        with self.assertRaises(CodecError):
+            self.marshaler.encode_action("NoSuchAction", {})
+        with self.assertRaises(CodecError):
+            self.marshaler.encode_action("Transfer", {"value": -1})
+        with self.assertRaises(CodecError):
+            self.marshaler.decode_action(bytes([0]))
+        with self.assertRaises(CodecError):
+            self.marshaler.decode_action(bytes.fromhex(self.vectors["actions"][0]["bytes"]) + b"\0")
+
+
+class TestCB58(unittest.TestCase):
+    def test_round_trip(self):
+        self.assertEqual(cb58_encode(bytes(32)), "11111111111111111111111111111111LpoYY")
+        b = bytes(range(32))
+        self.assertEqual(cb58_decode(cb58_encode(b)), b)
+        with self.assertRaises(CodecError):
+            cb58_decode("11111111111111111111111111111111LpoYZ")
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -5,12 +5,17 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/spf13/cobra"
 
 	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/vm"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/cli/prompt"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/utils"
 )
 
 var actionCmd = &cobra.Command{
@@ -35,29 +40,151 @@ var transferCmd = &cobra.Command{
 			return err
 		}
 
+		// Scripts pass the recipient and amount as flags and are not asked
+		// to confirm.
+		interactive := transferRecipient == "" || transferAmount == ""
+		if interactive {
+			if err := textOnly("action transfer without --recipient and --amount"); err != nil {
+				return err
+			}
+		}
+
 		// Select recipient
-		recipient, err := prompt.Address("recipient")
+		var recipient codec.Address
+		if transferRecipient != "" {
+			recipient, err = codec.StringToAddress(transferRecipient)
+		} else {
+			recipient, err = prompt.Address("recipient")
+		}
 		if err != nil {
 			return err
 		}
 
 		// Select amount
-		amount, err := prompt.Amount("amount", balance, nil)
+		var amount uint64
+		if transferAmount != "" {
+			amount, err = utils.ParseBalance(transferAmount)
+		} else {
+			amount, err = prompt.Amount("amount", balance, nil)
+		}
 		if err != nil {
 			return err
 		}
 
 		// Confirm action
-		cont, err := prompt.Continue()
-		if !cont || err != nil {
-			return err
+		if interactive {
+			cont, err := prompt.Continue()
+			if !cont || err != nil {
+				return err
+			}
 		}
 
 		// Generate transaction
-		_, _, err = sendAndWait(ctx, []chain.Action{&actions.Transfer{
+		success, txID, err := sendAndWait(ctx, []chain.Action{&actions.Transfer{
 			To:    recipient,
 			Value: amount,
 		}}, cli, bcli, ws, factory, true)
-		return err
+		if err != nil || !jsonOutput() {
+			return err
+		}
+		return printJSON(map[string]any{
+			"txID":      txID,
+			"success":   success,
+			"recipient": recipient,
+			"amount":    amount,
+		})
 	},
 }
+
+var assetTransferCmd = &cobra.Command{
+	Use: "asset-transfer",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, cli, bcli, ws, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Scripts pass the asset and recipient as flags and are not asked
+		// to confirm.
+		interactive := assetTransferAsset == "" || assetTransferTo == ""
+		if interactive {
+			if err := textOnly("action asset-transfer without --asset and --recipient"); err != nil {
+				return err
+			}
+		}
+
+		// Select asset
+		var asset ids.ID
+		if assetTransferAsset != "" {
+			asset, err = ids.FromString(assetTransferAsset)
+		} else {
+			asset, err = prompt.ID("asset")
+		}
+		if err != nil {
+			return err
+		}
+		info, err := bcli.AssetMetadata(ctx, asset)
+		if err != nil {
+			return err
+		}
+		if info.Owner != priv.Address {
+			return fmt.Errorf("%w: %s is owned by %s", actions.ErrAssetNotOwned, asset, info.Owner)
+		}
+
+		// Select recipient
+		var recipient codec.Address
+		if assetTransferTo != "" {
+			recipient, err = codec.StringToAddress(assetTransferTo)
+		} else {
+			recipient, err = prompt.Address("recipient")
+		}
+		if err != nil {
+			return err
+		}
+
+		var price uint64
+		if assetTransferPrice != "" {
+			price, err = utils.ParseBalance(assetTransferPrice)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Confirm action
+		if interactive {
+			cont, err := prompt.Continue()
+			if !cont || err != nil {
+				return err
+			}
+		}
+
+		action := newAssetTransfer(info, asset, recipient, assetTransferReason, price)
+		success, txID, err := sendAndWait(ctx, []chain.Action{action}, cli, bcli, ws, factory, true)
+		if err != nil || !jsonOutput() {
+			return err
+		}
+		return printJSON(map[string]any{
+			"txID":      txID,
+			"success":   success,
+			"asset":     asset,
+			"recipient": recipient,
+			"price":     price,
+		})
+	},
+}
+
+// newAssetTransfer builds the transfer of [asset] described by [info]. A
+// priced transfer must name the royalty payee of the asset.
+func newAssetTransfer(info *vm.AssetMetadataReply, asset ids.ID, recipient codec.Address, reason string, price uint64) *actions.AssetTransfer {
+	action := &actions.AssetTransfer{
+		Recipient: recipient,
+		Asset:     asset,
+		Reason:    reason,
+		Price:     price,
+	}
+	if price > 0 && info.Control.RoyaltyBasisPoints > 0 {
+		action.RoyaltyPayee = info.Control.RoyaltyPayee
+	}
+	return action
+}
@@ -0,0 +1,44 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk-starter-kit/vm"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+)
+
+func TestNewAssetTransfer(t *testing.T) {
+	payee := codectest.NewRandomAddress()
+	royalty := &vm.AssetMetadataReply{Control: storage.AssetControl{RoyaltyBasisPoints: 250, RoyaltyPayee: payee}}
+	tests := []struct {
+		name  string
+		info  *vm.AssetMetadataReply
+		price uint64
+		payee codec.Address
+	}{
+		{name: "PricedRoyalty", info: royalty, price: 10, payee: payee},
+		{name: "GiftRoyalty", info: royalty},
+		{name: "PricedNoRoyalty", info: &vm.AssetMetadataReply{}, price: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			asset := ids.GenerateTestID()
+			recipient := codectest.NewRandomAddress()
+			action := newAssetTransfer(tt.info, asset, recipient, "sale", tt.price)
+			require.Equal(asset, action.Asset)
+			require.Equal(recipient, action.Recipient)
+			require.Equal("sale", action.Reason)
+			require.Equal(tt.price, action.Price)
+			require.Equal(tt.payee, action.RoyaltyPayee)
+		})
+	}
+}
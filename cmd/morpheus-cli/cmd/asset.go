@@ -0,0 +1,117 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/hypersdk-starter-kit/vm"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+var assetCmd = &cobra.Command{
+	Use: "asset",
+	RunE: func(*cobra.Command, []string) error {
+		return ErrMissingSubcommand
+	},
+}
+
+var assetInfoCmd = &cobra.Command{
+	Use:   "info [asset ID]",
+	Short: "Prints the owner and metadata of an asset",
+	PreRunE: func(_ *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		asset, err := ids.FromString(args[0])
+		if err != nil {
+			return err
+		}
+		cli, err := defaultChainClient()
+		if err != nil {
+			return err
+		}
+		reply, err := cli.AssetMetadata(context.Background(), asset)
+		if err != nil {
+			return err
+		}
+		if jsonOutput() {
+			return printJSON(map[string]any{
+				"asset":    asset,
+				"owner":    reply.Owner,
+				"metadata": reply.Metadata,
+				"control":  reply.Control,
+			})
+		}
+		outf("{{yellow}}asset:{{/}} %s\n", asset)
+		outf("{{cyan}}owner:{{/}} %s\n", formatAddress(reply.Owner))
+		outf(
+			"{{cyan}}name:{{/}} %s {{cyan}}symbol:{{/}} %s {{cyan}}decimals:{{/}} %d {{cyan}}total supply:{{/}} %d\n",
+			reply.Metadata.Name,
+			reply.Metadata.Symbol,
+			reply.Metadata.Decimals,
+			reply.Metadata.TotalSupply,
+		)
+		if reply.Metadata.URI != "" {
+			outf("{{cyan}}uri:{{/}} %s\n", reply.Metadata.URI)
+		}
+		if reply.Control.Frozen {
+			outf("{{red}}frozen{{/}}\n")
+		}
+		return nil
+	},
+}
+
+var assetOwnedCmd = &cobra.Command{
+	Use:   "owned",
+	Short: "Lists the assets owned by the default key, or by --owner",
+	RunE: func(*cobra.Command, []string) error {
+		var (
+			owner codec.Address
+			err   error
+		)
+		if assetOwner != "" {
+			owner, err = codec.StringToAddress(assetOwner)
+		} else {
+			owner, _, err = handler.Root().GetDefaultKey(true)
+		}
+		if err != nil {
+			return err
+		}
+		cli, err := defaultChainClient()
+		if err != nil {
+			return err
+		}
+		assets, err := cli.AllAssetsByOwner(context.Background(), owner)
+		if err != nil {
+			return err
+		}
+		if jsonOutput() {
+			return printJSON(map[string]any{
+				"owner":  owner,
+				"assets": assets,
+			})
+		}
+		outf("{{yellow}}owner:{{/}} %s {{yellow}}assets:{{/}} %d\n", formatAddress(owner), len(assets))
+		for _, asset := range assets {
+			outf("%s\n", asset)
+		}
+		return nil
+	},
+}
+
+// defaultChainClient returns a client of the first URI of the default chain.
+func defaultChainClient() (*vm.JSONRPCClient, error) {
+	_, uris, err := handler.Root().GetDefaultChain(true)
+	if err != nil {
+		return nil, err
+	}
+	return vm.NewJSONRPCClient(uris[0]), nil
+}
@@ -4,7 +4,15 @@
 package cmd
 
 import (
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"strconv"
+
 	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/hypersdk-starter-kit/vm"
 )
 
 var chainCmd = &cobra.Command{
@@ -17,6 +25,9 @@ var chainCmd = &cobra.Command{
 var importChainCmd = &cobra.Command{
 	Use: "import",
 	RunE: func(_ *cobra.Command, _ []string) error {
+		if err := textOnly("chain import"); err != nil {
+			return err
+		}
 		return handler.Root().ImportChain()
 	},
 }
@@ -24,6 +35,9 @@ var importChainCmd = &cobra.Command{
 var setChainCmd = &cobra.Command{
 	Use: "set",
 	RunE: func(*cobra.Command, []string) error {
+		if err := textOnly("chain set"); err != nil {
+			return err
+		}
 		return handler.Root().SetDefaultChain()
 	},
 }
@@ -31,6 +45,9 @@ var setChainCmd = &cobra.Command{
 var chainInfoCmd = &cobra.Command{
 	Use: "info",
 	RunE: func(_ *cobra.Command, _ []string) error {
+		if jsonOutput() {
+			return handler.ChainInfo(context.Background())
+		}
 		return handler.Root().PrintChainInfo()
 	},
 }
@@ -38,6 +55,49 @@ var chainInfoCmd = &cobra.Command{
 var watchChainCmd = &cobra.Command{
 	Use: "watch",
 	RunE: func(_ *cobra.Command, args []string) error {
+		if err := textOnly("chain watch"); err != nil {
+			return err
+		}
 		return handler.Root().WatchChain(hideTxs)
 	},
 }
+
+var heatMapChainCmd = &cobra.Command{
+	Use:   "heatmap",
+	Short: "Prints the state key accesses counted by the node as CSV",
+	RunE: func(_ *cobra.Command, _ []string) error {
+		cli, err := defaultChainClient()
+		if err != nil {
+			return err
+		}
+		reply, err := cli.StateHeatMap(context.Background())
+		if err != nil {
+			return err
+		}
+		if jsonOutput() {
+			return printJSON(reply)
+		}
+		return writeHeatMapCSV(os.Stdout, reply.Cells)
+	},
+}
+
+// writeHeatMapCSV writes one row per cell of a state heat map to [w].
+func writeHeatMapCSV(w io.Writer, cells []*vm.HeatMapCell) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"prefix", "bucket", "reads", "allocates", "writes"}); err != nil {
+		return err
+	}
+	for _, cell := range cells {
+		if err := cw.Write([]string{
+			cell.Prefix,
+			strconv.FormatUint(uint64(cell.Bucket), 10),
+			strconv.FormatUint(cell.Reads, 10),
+			strconv.FormatUint(cell.Allocates, 10),
+			strconv.FormatUint(cell.Writes, 10),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -10,4 +10,6 @@ var (
 	ErrMissingSubcommand = errors.New("must specify a subcommand")
 	ErrInvalidAddress    = errors.New("invalid address")
 	ErrInvalidKeyType    = errors.New("invalid key type")
+	ErrNoJSONOutput      = errors.New("command does not support json output")
+	ErrSnapshotDrift     = errors.New("snapshots drifted")
 )
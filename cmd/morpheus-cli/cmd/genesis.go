@@ -10,6 +10,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
+	"github.com/ava-labs/hypersdk-starter-kit/vm"
 	"github.com/ava-labs/hypersdk/fees"
 	"github.com/ava-labs/hypersdk/genesis"
 )
@@ -69,10 +70,33 @@ var genGenesisCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if assetsFile != "" {
+			assets, err := os.ReadFile(assetsFile)
+			if err != nil {
+				return err
+			}
+			b, err = vm.WithAssets(b, assets)
+			if err != nil {
+				return err
+			}
+		}
+		if seedFile != "" {
+			seed, err := os.ReadFile(seedFile)
+			if err != nil {
+				return err
+			}
+			b, err = vm.WithSeed(b, seed)
+			if err != nil {
+				return err
+			}
+		}
 		if err := os.WriteFile(genesisFile, b, fsModeWrite); err != nil {
 			return err
 		}
 
+		if jsonOutput() {
+			return printJSON(map[string]string{"genesisFile": genesisFile})
+		}
 		color.Green("created genesis and saved to %s", genesisFile)
 		return nil
 	},
@@ -20,7 +20,6 @@ import (
 	"github.com/ava-labs/hypersdk/crypto/ed25519"
 	"github.com/ava-labs/hypersdk/crypto/secp256r1"
 	"github.com/ava-labs/hypersdk/pubsub"
-	"github.com/ava-labs/hypersdk/utils"
 )
 
 var _ cli.Controller = (*Controller)(nil)
@@ -92,19 +91,73 @@ func (*Handler) GetBalance(
 		return 0, err
 	}
 	if balance == 0 {
-		utils.Outf("{{red}}balance:{{/}} 0 %s\n", consts.Symbol)
-		utils.Outf("{{red}}please send funds to %s{{/}}\n", addr)
-		utils.Outf("{{red}}exiting...{{/}}\n")
+		outf("{{red}}balance:{{/}} %s\n", formatAmount(0))
+		outf("{{red}}please send funds to %s{{/}}\n", formatAddress(addr))
+		outf("{{red}}exiting...{{/}}\n")
 		return 0, nil
 	}
-	utils.Outf(
-		"{{yellow}}balance:{{/}} %s %s\n",
-		utils.FormatBalance(balance),
-		consts.Symbol,
-	)
+	outf("{{yellow}}balance:{{/}} %s\n", formatAmount(balance))
 	return balance, nil
 }
 
+type balanceOutput struct {
+	URI     string        `json:"uri"`
+	Address codec.Address `json:"address"`
+	Balance uint64        `json:"balance"`
+}
+
+// Balance prints the balance of the default key on the first URI of the
+// default chain, or on all of them with [checkAllChains].
+func (h *Handler) Balance(ctx context.Context, checkAllChains bool) error {
+	addr, _, err := h.h.GetDefaultKey(true)
+	if err != nil {
+		return err
+	}
+	_, uris, err := h.h.GetDefaultChain(true)
+	if err != nil {
+		return err
+	}
+	if !checkAllChains {
+		uris = uris[:1]
+	}
+	balances := make([]balanceOutput, 0, len(uris))
+	for _, uri := range uris {
+		balance, err := vm.NewJSONRPCClient(uri).Balance(ctx, addr)
+		if err != nil {
+			return err
+		}
+		balances = append(balances, balanceOutput{URI: uri, Address: addr, Balance: balance})
+		outf("{{yellow}}uri:{{/}} %s\n", uri)
+		outf(
+			"{{cyan}}address:{{/}} %s {{cyan}}balance:{{/}} %s\n",
+			formatAddress(addr),
+			formatAmount(balance),
+		)
+	}
+	if jsonOutput() {
+		return printJSON(balances)
+	}
+	return nil
+}
+
+// ChainInfo prints the network of the default chain as JSON.
+func (h *Handler) ChainInfo(ctx context.Context) error {
+	_, uris, err := h.h.GetDefaultChain(true)
+	if err != nil {
+		return err
+	}
+	networkID, subnetID, chainID, err := jsonrpc.NewJSONRPCClient(uris[0]).Network(ctx)
+	if err != nil {
+		return err
+	}
+	return printJSON(map[string]any{
+		"uri":       uris[0],
+		"networkID": networkID,
+		"subnetID":  subnetID,
+		"chainID":   chainID,
+	})
+}
+
 type Controller struct {
 	databasePath string
 }
@@ -4,6 +4,8 @@
 package cmd
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 
 	"github.com/ava-labs/hypersdk-starter-kit/auth"
@@ -36,9 +38,12 @@ var genKeyCmd = &cobra.Command{
 		if err := handler.h.StoreDefaultKey(priv.Address); err != nil {
 			return err
 		}
+		if jsonOutput() {
+			return printJSON(map[string]string{"address": priv.Address.String()})
+		}
 		utils.Outf(
 			"{{green}}created address:{{/}} %s",
-			priv.Address,
+			formatAddress(priv.Address),
 		)
 		return nil
 	},
@@ -63,9 +68,12 @@ var importKeyCmd = &cobra.Command{
 		if err := handler.h.StoreDefaultKey(priv.Address); err != nil {
 			return err
 		}
+		if jsonOutput() {
+			return printJSON(map[string]string{"address": priv.Address.String()})
+		}
 		utils.Outf(
 			"{{green}}imported address:{{/}} %s",
-			priv.Address,
+			formatAddress(priv.Address),
 		)
 		return nil
 	},
@@ -74,6 +82,9 @@ var importKeyCmd = &cobra.Command{
 var setKeyCmd = &cobra.Command{
 	Use: "set",
 	RunE: func(*cobra.Command, []string) error {
+		if err := textOnly("key set"); err != nil {
+			return err
+		}
 		return handler.Root().SetKey()
 	},
 }
@@ -81,6 +92,6 @@ var setKeyCmd = &cobra.Command{
 var balanceKeyCmd = &cobra.Command{
 	Use: "balance",
 	RunE: func(*cobra.Command, []string) error {
-		return handler.Root().Balance(checkAllChains)
+		return handler.Balance(context.Background(), checkAllChains)
 	},
 }
@@ -0,0 +1,150 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/hypersdk-starter-kit/consts"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+var (
+	outputFormat   string
+	displayLocale  string
+	denomination   string
+	shortAddresses bool
+)
+
+// numberFormat is how a locale groups digits and marks the decimal point.
+type numberFormat struct {
+	group   string
+	decimal string
+}
+
+// numberFormats are keyed by language. Unknown languages use "en".
+var numberFormats = map[string]numberFormat{
+	"en": {group: ",", decimal: "."},
+	"de": {group: ".", decimal: ","},
+	"es": {group: ".", decimal: ","},
+	"it": {group: ".", decimal: ","},
+	"nl": {group: ".", decimal: ","},
+	"pt": {group: ".", decimal: ","},
+	"fr": {group: "\u202f", decimal: ","},
+	"ru": {group: "\u00a0", decimal: ","},
+	"C":  {group: "", decimal: "."},
+}
+
+// denominations are the units amounts can be displayed in, by the number of
+// decimals they shift the base unit.
+var denominations = map[string]uint8{
+	consts.Symbol:       consts.Decimals,
+	"m" + consts.Symbol: consts.Decimals - 3,
+	"u" + consts.Symbol: consts.Decimals - 6,
+	"n" + consts.Symbol: 0,
+}
+
+func checkDisplayFlags() error {
+	if outputFormat != outputText && outputFormat != outputJSON {
+		return fmt.Errorf("%w: output %q", ErrInvalidArgs, outputFormat)
+	}
+	if _, ok := denominations[denomination]; !ok {
+		return fmt.Errorf("%w: denomination %q", ErrInvalidArgs, denomination)
+	}
+	return nil
+}
+
+func jsonOutput() bool {
+	return outputFormat == outputJSON
+}
+
+// localeFormat returns the number format of [--locale], falling back to the
+// LC_ALL, LC_NUMERIC and LANG environment variables.
+func localeFormat() numberFormat {
+	locale := displayLocale
+	for _, env := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if locale != "" {
+			break
+		}
+		locale = os.Getenv(env)
+	}
+	// "de_DE.UTF-8" and "de-DE" are both German.
+	lang, _, _ := strings.Cut(locale, ".")
+	lang, _, _ = strings.Cut(lang, "_")
+	lang, _, _ = strings.Cut(lang, "-")
+	if lang == "POSIX" {
+		lang = "C"
+	}
+	if f, ok := numberFormats[lang]; ok {
+		return f
+	}
+	return numberFormats["en"]
+}
+
+// formatAmount renders [amount] base units in [--denomination], followed by
+// the denomination.
+func formatAmount(amount uint64) string {
+	return formatAmountIn(amount, denominations[denomination], localeFormat()) + " " + denomination
+}
+
+func formatAmountIn(amount uint64, decimals uint8, f numberFormat) string {
+	digits := strconv.FormatUint(amount, 10)
+	if pad := int(decimals) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	whole, frac := digits[:len(digits)-int(decimals)], digits[len(digits)-int(decimals):]
+	var b strings.Builder
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(f.group)
+		}
+		b.WriteRune(d)
+	}
+	if frac = strings.TrimRight(frac, "0"); frac != "" {
+		b.WriteString(f.decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// formatAddress renders [addr], abbreviated with [--short-addresses].
+func formatAddress(addr codec.Address) string {
+	s := addr.String()
+	const keep = 8
+	if !shortAddresses || len(s) <= 2*keep+len(consts.HRP)+1 {
+		return s
+	}
+	return s[:len(consts.HRP)+1+keep] + "…" + s[len(s)-keep:]
+}
+
+// outf prints like [utils.Outf] unless the output is JSON.
+func outf(format string, args ...any) {
+	if !jsonOutput() {
+		utils.Outf(format, args...)
+	}
+}
+
+// printJSON writes [v] to stdout as a single line of JSON.
+func printJSON(v any) error {
+	return json.NewEncoder(os.Stdout).Encode(v)
+}
+
+// textOnly fails in JSON mode. It guards commands whose output is printed
+// by the hypersdk CLI or that prompt along the way.
+func textOnly(name string) error {
+	if jsonOutput() {
+		return fmt.Errorf("%w: %s", ErrNoJSONOutput, name)
+	}
+	return nil
+}
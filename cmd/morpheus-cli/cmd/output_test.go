@@ -0,0 +1,48 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   uint64
+		decimals uint8
+		locale   string
+		want     string
+	}{
+		{0, 9, "en", "0"},
+		{1, 9, "en", "0.000000001"},
+		{1_234_567_500_000_000, 9, "en", "1,234,567.5"},
+		{1_234_567_500_000_000, 9, "de", "1.234.567,5"},
+		{1_234_567_500_000_000, 9, "fr", "1\u202f234\u202f567,5"},
+		{1_234_567_500_000_000, 9, "C", "1234567.5"},
+		{1_234_567, 0, "en", "1,234,567"},
+		{123_000, 3, "en", "123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			require.Equal(t, tt.want, formatAmountIn(tt.amount, tt.decimals, numberFormats[tt.locale]))
+		})
+	}
+}
+
+func TestLocaleFormat(t *testing.T) {
+	require := require.New(t)
+
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_NUMERIC", "de_DE.UTF-8")
+	require.Equal(numberFormats["de"], localeFormat())
+
+	displayLocale = "fr-FR"
+	defer func() { displayLocale = "" }()
+	require.Equal(numberFormats["fr"], localeFormat())
+
+	displayLocale = "xx"
+	require.Equal(numberFormats["en"], localeFormat())
+}
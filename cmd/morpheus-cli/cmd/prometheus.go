@@ -18,6 +18,9 @@ var prometheusCmd = &cobra.Command{
 var generatePrometheusCmd = &cobra.Command{
 	Use: "generate",
 	RunE: func(_ *cobra.Command, args []string) error {
+		if err := textOnly("prometheus generate"); err != nil {
+			return err
+		}
 		return handler.Root().GeneratePrometheus(prometheusBaseURI, prometheusOpenBrowser, startPrometheus, prometheusFile, prometheusData)
 	},
 }
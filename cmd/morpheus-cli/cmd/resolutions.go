@@ -11,7 +11,6 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 
 	"github.com/ava-labs/hypersdk-starter-kit/actions"
-	"github.com/ava-labs/hypersdk-starter-kit/consts"
 	"github.com/ava-labs/hypersdk-starter-kit/vm"
 	"github.com/ava-labs/hypersdk/api/jsonrpc"
 	"github.com/ava-labs/hypersdk/api/ws"
@@ -48,14 +47,14 @@ func sendAndWait(
 			result = txResult
 			break
 		}
-		utils.Outf("{{yellow}}skipping unexpected transaction:{{/}} %s\n", tx.ID())
+		outf("{{yellow}}skipping unexpected transaction:{{/}} %s\n", tx.ID())
 	}
 	if printStatus {
 		status := "❌"
 		if result.Success {
 			status = "✅"
 		}
-		utils.Outf("%s {{yellow}}txID:{{/}} %s\n", status, tx.ID())
+		outf("%s {{yellow}}txID:{{/}} %s\n", status, tx.ID())
 	}
 	return result.Success, tx.ID(), nil
 }
@@ -64,14 +63,13 @@ func handleTx(tx *chain.Transaction, result *chain.Result) {
 	actor := tx.Auth.Actor()
 	if !result.Success {
 		utils.Outf(
-			"%s {{yellow}}%s{{/}} {{yellow}}actor:{{/}} %s {{yellow}}error:{{/}} [%s] {{yellow}}fee (max %.2f%%):{{/}} %s %s {{yellow}}consumed:{{/}} [%s]\n",
+			"%s {{yellow}}%s{{/}} {{yellow}}actor:{{/}} %s {{yellow}}error:{{/}} [%s] {{yellow}}fee (max %.2f%%):{{/}} %s {{yellow}}consumed:{{/}} [%s]\n",
 			"❌",
 			tx.ID(),
-			actor,
+			formatAddress(actor),
 			result.Error,
 			float64(result.Fee)/float64(tx.Base.MaxFee)*100,
-			utils.FormatBalance(result.Fee),
-			consts.Symbol,
+			formatAmount(result.Fee),
 			result.Units,
 		)
 		return
@@ -81,18 +79,17 @@ func handleTx(tx *chain.Transaction, result *chain.Result) {
 		var summaryStr string
 		switch act := action.(type) { //nolint:gocritic
 		case *actions.Transfer:
-			summaryStr = fmt.Sprintf("%s %s -> %s\n", utils.FormatBalance(act.Value), consts.Symbol, actor)
+			summaryStr = fmt.Sprintf("%s -> %s\n", formatAmount(act.Value), formatAddress(actor))
 		}
 		utils.Outf(
-			"%s {{yellow}}%s{{/}} {{yellow}}actor:{{/}} %s {{yellow}}summary (%s):{{/}} [%s] {{yellow}}fee (max %.2f%%):{{/}} %s %s {{yellow}}consumed:{{/}} [%s]\n",
+			"%s {{yellow}}%s{{/}} {{yellow}}actor:{{/}} %s {{yellow}}summary (%s):{{/}} [%s] {{yellow}}fee (max %.2f%%):{{/}} %s {{yellow}}consumed:{{/}} [%s]\n",
 			"✅",
 			tx.ID(),
-			actor,
+			formatAddress(actor),
 			reflect.TypeOf(action),
 			summaryStr,
 			float64(result.Fee)/float64(tx.Base.MaxFee)*100,
-			utils.FormatBalance(result.Fee),
-			consts.Symbol,
+			formatAmount(result.Fee),
 			result.Units,
 		)
 	}
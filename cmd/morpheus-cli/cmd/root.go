@@ -9,8 +9,9 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/ava-labs/hypersdk-starter-kit/consts"
+	"github.com/ava-labs/hypersdk-starter-kit/vm"
 	"github.com/ava-labs/hypersdk/cli"
-	"github.com/ava-labs/hypersdk/utils"
 )
 
 const (
@@ -28,6 +29,8 @@ var (
 	maxBlockUnits         []string
 	windowTargetUnits     []string
 	minBlockGap           int64
+	seedFile              string
+	assetsFile            string
 	hideTxs               bool
 	checkAllChains        bool
 	spamDefaults          bool
@@ -36,6 +39,16 @@ var (
 	prometheusFile        string
 	prometheusData        string
 	startPrometheus       bool
+	transferRecipient     string
+	transferAmount        string
+	assetTransferAsset    string
+	assetTransferTo       string
+	assetTransferReason   string
+	assetTransferPrice    string
+	assetOwner            string
+	snapshotTop           int
+	snapshotMinHeight     uint64
+	snapshotCheckHeight   bool
 
 	rootCmd = &cobra.Command{
 		Use:        "morpheus-cli",
@@ -51,8 +64,10 @@ func init() {
 		keyCmd,
 		chainCmd,
 		actionCmd,
+		assetCmd,
 		spamCmd,
 		prometheusCmd,
+		snapshotCmd,
 	)
 	rootCmd.PersistentFlags().StringVar(
 		&dbPath,
@@ -60,8 +75,35 @@ func init() {
 		defaultDatabase,
 		"path to database (will create it missing)",
 	)
+	rootCmd.PersistentFlags().StringVar(
+		&outputFormat,
+		"output",
+		outputText,
+		"output format (text/json)",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&displayLocale,
+		"locale",
+		"",
+		"locale of displayed numbers (defaults to LC_ALL, LC_NUMERIC or LANG)",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&denomination,
+		"denomination",
+		consts.Symbol,
+		"unit of displayed amounts ("+consts.Symbol+"/m"+consts.Symbol+"/u"+consts.Symbol+"/n"+consts.Symbol+")",
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&shortAddresses,
+		"short-addresses",
+		false,
+		"abbreviate displayed addresses",
+	)
 	rootCmd.PersistentPreRunE = func(*cobra.Command, []string) error {
-		utils.Outf("{{yellow}}database:{{/}} %s\n", dbPath)
+		if err := checkDisplayFlags(); err != nil {
+			return err
+		}
+		outf("{{yellow}}database:{{/}} %s\n", dbPath)
 		controller := NewController(dbPath)
 		root, err := cli.New(controller)
 		if err != nil {
@@ -106,6 +148,18 @@ func init() {
 		-1,
 		"minimum block gap (ms)",
 	)
+	genGenesisCmd.PersistentFlags().StringVar(
+		&seedFile,
+		"seed-file",
+		"",
+		"seed file of demo state created at genesis",
+	)
+	genGenesisCmd.PersistentFlags().StringVar(
+		&assetsFile,
+		"assets-file",
+		"",
+		"assets file of assets pre-minted at genesis",
+	)
 	genesisCmd.AddCommand(
 		genGenesisCmd,
 	)
@@ -136,11 +190,61 @@ func init() {
 		setChainCmd,
 		chainInfoCmd,
 		watchChainCmd,
+		heatMapChainCmd,
 	)
 
 	// actions
+	transferCmd.PersistentFlags().StringVar(
+		&transferRecipient,
+		"recipient",
+		"",
+		"recipient address (prompted for when empty)",
+	)
+	transferCmd.PersistentFlags().StringVar(
+		&transferAmount,
+		"amount",
+		"",
+		"amount in "+consts.Symbol+" (prompted for when empty)",
+	)
+	assetTransferCmd.PersistentFlags().StringVar(
+		&assetTransferAsset,
+		"asset",
+		"",
+		"asset ID (prompted for when empty)",
+	)
+	assetTransferCmd.PersistentFlags().StringVar(
+		&assetTransferTo,
+		"recipient",
+		"",
+		"recipient address (prompted for when empty)",
+	)
+	assetTransferCmd.PersistentFlags().StringVar(
+		&assetTransferReason,
+		"reason",
+		"",
+		"reason recorded with the transfer",
+	)
+	assetTransferCmd.PersistentFlags().StringVar(
+		&assetTransferPrice,
+		"price",
+		"",
+		"sale price in "+consts.Symbol+", on which the asset's royalty is paid",
+	)
 	actionCmd.AddCommand(
 		transferCmd,
+		assetTransferCmd,
+	)
+
+	// asset
+	assetOwnedCmd.PersistentFlags().StringVar(
+		&assetOwner,
+		"owner",
+		"",
+		"owner address (the default key when empty)",
+	)
+	assetCmd.AddCommand(
+		assetInfoCmd,
+		assetOwnedCmd,
 	)
 
 	runSpamCmd.PersistentFlags().BoolVar(
@@ -189,6 +293,30 @@ func init() {
 	prometheusCmd.AddCommand(
 		generatePrometheusCmd,
 	)
+
+	// snapshot
+	exportSnapshotCmd.PersistentFlags().IntVar(
+		&snapshotTop,
+		"top",
+		vm.DefaultSummaryTop,
+		"number of largest balances to hash",
+	)
+	exportSnapshotCmd.PersistentFlags().Uint64Var(
+		&snapshotMinHeight,
+		"min-height",
+		0,
+		"fail if the chain is below this height",
+	)
+	diffSnapshotCmd.PersistentFlags().BoolVar(
+		&snapshotCheckHeight,
+		"check-height",
+		false,
+		"also flag snapshots taken at different heights",
+	)
+	snapshotCmd.AddCommand(
+		exportSnapshotCmd,
+		diffSnapshotCmd,
+	)
 }
 
 func Execute() error {
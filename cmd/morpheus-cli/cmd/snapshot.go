@@ -0,0 +1,141 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk-starter-kit/vm"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use: "snapshot",
+	RunE: func(*cobra.Command, []string) error {
+		return ErrMissingSubcommand
+	},
+}
+
+var exportSnapshotCmd = &cobra.Command{
+	Use:   "export [snapshot file]",
+	Short: "Saves the economic summary of the default chain",
+	PreRunE: func(_ *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		return handler.ExportSnapshot(context.Background(), args[0], snapshotTop, snapshotMinHeight)
+	},
+}
+
+var diffSnapshotCmd = &cobra.Command{
+	Use:   "diff [expected snapshot file] [actual snapshot file]",
+	Short: "Fails if two snapshots of the same scenario drifted apart",
+	PreRunE: func(_ *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		expected, err := readSnapshot(args[0])
+		if err != nil {
+			return err
+		}
+		actual, err := readSnapshot(args[1])
+		if err != nil {
+			return err
+		}
+		drifts := diffSnapshots(expected, actual, snapshotCheckHeight)
+		if jsonOutput() {
+			if err := printJSON(map[string]any{"drifts": drifts}); err != nil {
+				return err
+			}
+		}
+		for _, d := range drifts {
+			outf("{{red}}%s:{{/}} expected %v, got %v\n", d.Field, d.Expected, d.Actual)
+		}
+		if len(drifts) > 0 {
+			return fmt.Errorf("%w: %d fields", ErrSnapshotDrift, len(drifts))
+		}
+		if !jsonOutput() {
+			color.Green("snapshots match")
+		}
+		return nil
+	},
+}
+
+// ExportSnapshot writes the economic summary of the first URI of the
+// default chain to [path].
+func (h *Handler) ExportSnapshot(ctx context.Context, path string, top int, minHeight uint64) error {
+	_, uris, err := h.h.GetDefaultChain(true)
+	if err != nil {
+		return err
+	}
+	summary, err := vm.NewJSONRPCClient(uris[0]).EconomicSummary(ctx, top, minHeight)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, b, fsModeWrite); err != nil {
+		return err
+	}
+	if jsonOutput() {
+		return printJSON(summary)
+	}
+	color.Green("saved snapshot at height %d to %s", summary.Height, path)
+	return nil
+}
+
+func readSnapshot(path string) (*storage.Summary, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var summary storage.Summary
+	if err := json.Unmarshal(b, &summary); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &summary, nil
+}
+
+type snapshotDrift struct {
+	Field    string `json:"field"`
+	Expected any    `json:"expected"`
+	Actual   any    `json:"actual"`
+}
+
+// diffSnapshots returns the fields of [actual] that differ from [expected],
+// named by their JSON keys. The height is only compared if [checkHeight],
+// as two runs of a scenario need not produce the same number of blocks.
+func diffSnapshots(expected *storage.Summary, actual *storage.Summary, checkHeight bool) []snapshotDrift {
+	drifts := []snapshotDrift{}
+	e, a := reflect.ValueOf(*expected), reflect.ValueOf(*actual)
+	for i := 0; i < e.NumField(); i++ {
+		field := e.Type().Field(i)
+		if field.Name == "Height" && !checkHeight {
+			continue
+		}
+		if !reflect.DeepEqual(e.Field(i).Interface(), a.Field(i).Interface()) {
+			drifts = append(drifts, snapshotDrift{
+				Field:    field.Tag.Get("json"),
+				Expected: e.Field(i).Interface(),
+				Actual:   a.Field(i).Interface(),
+			})
+		}
+	}
+	return drifts
+}
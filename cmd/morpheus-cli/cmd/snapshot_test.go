@@ -0,0 +1,30 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	require := require.New(t)
+
+	expected := &storage.Summary{Height: 10, Accounts: 3, Circulating: 1_000, TotalSupply: 1_200, Locked: 200}
+	require.Empty(diffSnapshots(expected, expected, true))
+
+	actual := *expected
+	actual.Height = 12
+	require.Empty(diffSnapshots(expected, &actual, false))
+	require.Equal([]snapshotDrift{{Field: "height", Expected: uint64(10), Actual: uint64(12)}}, diffSnapshots(expected, &actual, true))
+
+	actual.Locked, actual.TotalSupply = 150, 1_150
+	require.Equal([]snapshotDrift{
+		{Field: "locked", Expected: uint64(200), Actual: uint64(150)},
+		{Field: "totalSupply", Expected: uint64(1_200), Actual: uint64(1_150)},
+	}, diffSnapshots(expected, &actual, false))
+}
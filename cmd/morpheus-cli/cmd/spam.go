@@ -28,6 +28,9 @@ var runSpamCmd = &cobra.Command{
 		return auth.CheckKeyType(args[0])
 	},
 	RunE: func(_ *cobra.Command, args []string) error {
+		if err := textOnly("spam run"); err != nil {
+			return err
+		}
 		ctx := context.Background()
 		return handler.Root().Spam(ctx, &throughput.SpamHelper{KeyType: args[0]}, spamDefaults)
 	},
@@ -0,0 +1,174 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// replica-sync mirrors balances and assets into Redis by replaying the
+// state journal of a MorpheusVM node, one block per Redis transaction.
+//
+// The node must run with a non-zero journalWindow. The replica has to start
+// from a height the node still journals: either START_HEIGHT on a fresh
+// chain, or the height recorded by a previous run.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ava-labs/hypersdk-starter-kit/consts"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk-starter-kit/vm"
+	"github.com/ava-labs/hypersdk/api/jsonrpc"
+)
+
+const (
+	pollInterval = time.Second
+
+	heightKey     = "morpheus:height"
+	balancePrefix = "morpheus:balance:"
+	assetPrefix   = "morpheus:asset:"
+)
+
+var errGap = errors.New("journal no longer has the next height, reseed the replica")
+
+func main() {
+	rpcEndpoint := os.Getenv("RPC_ENDPOINT")
+	if rpcEndpoint == "" {
+		log.Fatalf("RPC_ENDPOINT is not set")
+	}
+	redisAddr := os.Getenv("REDIS_ADDR")
+	if redisAddr == "" {
+		redisAddr = "localhost:6379"
+	}
+	var startHeight uint64
+	if s := os.Getenv("START_HEIGHT"); s != "" {
+		h, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			log.Fatalf("failed to parse START_HEIGHT: %v", err)
+		}
+		startHeight = h
+	}
+
+	url := fmt.Sprintf("%s/ext/bc/%s", rpcEndpoint, consts.Name)
+	s := &syncer{
+		vmCli:  vm.NewJSONRPCClient(url),
+		sdkCli: jsonrpc.NewJSONRPCClient(url),
+		rdb:    redis.NewClient(&redis.Options{Addr: redisAddr}),
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	if err := s.run(ctx, startHeight); err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatalf("replica sync stopped: %v", err)
+	}
+}
+
+type syncer struct {
+	vmCli  *vm.JSONRPCClient
+	sdkCli *jsonrpc.JSONRPCClient
+	rdb    *redis.Client
+}
+
+func (s *syncer) run(ctx context.Context, startHeight uint64) error {
+	next := startHeight
+	last, err := s.rdb.Get(ctx, heightKey).Uint64()
+	switch {
+	case err == nil:
+		next = last + 1
+	case !errors.Is(err, redis.Nil):
+		return err
+	}
+	log.Printf("syncing from height %d\n", next)
+
+	for {
+		bj, err := s.vmCli.StateJournal(ctx, next)
+		if err != nil && !strings.Contains(err.Error(), vm.ErrJournalUnavailable.Error()) {
+			return err
+		}
+		if err != nil {
+			// The journal runs one block behind the chain, so [next] is only
+			// expected to be missing while it is the last accepted block.
+			_, accepted, _, aerr := s.sdkCli.Accepted(ctx)
+			if aerr != nil {
+				return aerr
+			}
+			if accepted > next+1 {
+				return fmt.Errorf("%w: height=%d, lastAccepted=%d", errGap, next, accepted)
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+		if err := s.apply(ctx, bj); err != nil {
+			return err
+		}
+		next++
+	}
+}
+
+// apply writes the mirrored changes of [bj] and advances [heightKey] in a
+// single MULTI/EXEC, so readers never observe a partially applied block.
+func (s *syncer) apply(ctx context.Context, bj *vm.BlockJournal) error {
+	_, err := s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, change := range bj.Changes {
+			if err := mirror(ctx, pipe, change); err != nil {
+				return fmt.Errorf("height %d: %w", bj.Height, err)
+			}
+		}
+		pipe.Set(ctx, heightKey, bj.Height, 0)
+		return nil
+	})
+	return err
+}
+
+func mirror(ctx context.Context, pipe redis.Pipeliner, change vm.JournalEntry) error {
+	if addr, ok := storage.ParseBalanceKey(change.Key); ok {
+		key := balancePrefix + addr.String()
+		if change.Deleted {
+			pipe.Del(ctx, key)
+			return nil
+		}
+		bal, err := storage.ParseBalance(change.Value)
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, key, bal, 0)
+		return nil
+	}
+	if assetID, ok := storage.ParseAssetKey(change.Key); ok {
+		key := assetPrefix + assetID.String()
+		pipe.Del(ctx, key)
+		if change.Deleted {
+			return nil
+		}
+		owner, m, c, err := storage.ParseAsset(change.Value)
+		if err != nil {
+			return err
+		}
+		pipe.HSet(ctx, key,
+			"owner", owner.String(),
+			"name", m.Name,
+			"symbol", m.Symbol,
+			"decimals", m.Decimals,
+			"uri", m.URI,
+			"totalSupply", m.TotalSupply,
+			"minter", c.Minter.String(),
+			"frozen", c.Frozen,
+			"royaltyBasisPoints", c.RoyaltyBasisPoints,
+			"royaltyPayee", c.RoyaltyPayee.String(),
+			"soulbound", c.Soulbound,
+			"expiry", c.Expiry,
+		)
+	}
+	return nil
+}
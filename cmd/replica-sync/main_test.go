@@ -0,0 +1,86 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk-starter-kit/vm"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+)
+
+// recordingPipe records the commands [mirror] queues instead of sending
+// them.
+type recordingPipe struct {
+	redis.Pipeliner
+	cmds [][]any
+}
+
+func (p *recordingPipe) Set(ctx context.Context, key string, value any, _ time.Duration) *redis.StatusCmd {
+	p.cmds = append(p.cmds, []any{"set", key, value})
+	return redis.NewStatusCmd(ctx)
+}
+
+func (p *recordingPipe) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	for _, key := range keys {
+		p.cmds = append(p.cmds, []any{"del", key})
+	}
+	return redis.NewIntCmd(ctx)
+}
+
+func (p *recordingPipe) HSet(ctx context.Context, key string, values ...any) *redis.IntCmd {
+	p.cmds = append(p.cmds, append([]any{"hset", key}, values...))
+	return redis.NewIntCmd(ctx)
+}
+
+func TestMirror(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	store := chaintest.NewInMemoryStore()
+	owner := codectest.NewRandomAddress()
+	assetID := ids.GenerateTestID()
+	control := storage.AssetControl{Minter: owner, Soulbound: true, Expiry: 1_000}
+	require.NoError(storage.SetBalance(ctx, store, owner, 7))
+	require.NoError(storage.CreateAssetWithControl(ctx, store, assetID, control, owner))
+	change := func(key []byte) vm.JournalEntry {
+		v, err := store.GetValue(ctx, key)
+		require.NoError(err)
+		return vm.JournalEntry{Key: key, Value: v}
+	}
+	mirrored := func(change vm.JournalEntry) [][]any {
+		pipe := &recordingPipe{}
+		require.NoError(mirror(ctx, pipe, change))
+		return pipe.cmds
+	}
+
+	balanceKey := balancePrefix + owner.String()
+	require.Equal([][]any{{"set", balanceKey, uint64(7)}}, mirrored(change(storage.BalanceKey(owner))))
+	require.Equal([][]any{{"del", balanceKey}}, mirrored(vm.JournalEntry{Key: storage.BalanceKey(owner), Deleted: true}))
+
+	// An asset hash is replaced as a whole, control fields included.
+	assetKey := assetPrefix + assetID.String()
+	cmds := mirrored(change(storage.AssetKey(assetID)))
+	require.Len(cmds, 2)
+	require.Equal([]any{"del", assetKey}, cmds[0])
+	fields := make(map[any]any)
+	for i := 2; i < len(cmds[1]); i += 2 {
+		fields[cmds[1][i]] = cmds[1][i+1]
+	}
+	require.Equal(owner.String(), fields["owner"])
+	require.Equal(owner.String(), fields["minter"])
+	require.Equal(true, fields["soulbound"])
+	require.Equal(int64(1_000), fields["expiry"])
+	require.Equal([][]any{{"del", assetKey}}, mirrored(vm.JournalEntry{Key: storage.AssetKey(assetID), Deleted: true}))
+
+	// Keys outside the mirrored prefixes are skipped.
+	require.Empty(mirrored(vm.JournalEntry{Key: storage.TombstoneKey(storage.AssetKey(assetID))}))
+}
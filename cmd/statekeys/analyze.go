@@ -0,0 +1,424 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	actionsPkg = "actions"
+	storagePkg = "storage"
+
+	// other stands for any address that is not the actor, and isActor for
+	// the actor once a use is described.
+	other   = -1
+	isActor = 0
+
+	// Positions of the actor parameter.
+	executeActor   = 4
+	stateKeysActor = 0
+
+	// ignoreDirective on the line of a call, or the line before it, drops
+	// the listed keys reached through that call. It is followed by a
+	// comma-separated list of key constructors and the reason, such as:
+	//
+	//	//statekeys:ignore OwnedAssetKey the owner was checked to be the actor
+	ignoreDirective = "//statekeys:ignore"
+)
+
+// skipped are storage helpers the analysis does not descend into, with the
+// reason why.
+var skipped = map[string]string{
+	// Delete only writes a tombstone under tombstoned prefixes, which
+	// actions declare with DeletionStateKeys.
+	"Delete": "conditional tombstone",
+}
+
+// use is a key constructor reached from a function, with the addresses it
+// is keyed by: a parameter position of that function, or [other].
+type use struct {
+	key   string
+	addrs []int
+}
+
+func (u use) id() string {
+	return fmt.Sprint(u.key, u.addrs)
+}
+
+// summary maps [use.id] to the use and the call it was reached through.
+type summary map[string]reached
+
+type reached struct {
+	use
+	via string
+}
+
+type function struct {
+	pkg    string
+	decl   *ast.FuncDecl
+	params []string
+}
+
+// Finding is a key touched by an action's Execute that its StateKeys does
+// not declare.
+type Finding struct {
+	Action string
+	Key    string
+	// Addrs describes the address arguments of the key, such as "actor".
+	Addrs []string
+	// Via is the call in Execute that reaches the key.
+	Via string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf(
+		"%s.Execute touches storage.%s(%s) through %s, which StateKeys does not declare",
+		f.Action, f.Key, strings.Join(f.Addrs, ", "), f.Via,
+	)
+}
+
+type analyzer struct {
+	fset         *token.FileSet
+	ignored      map[token.Position]map[string]bool
+	funcs        map[string]*function
+	constructors map[string][]int // address parameter positions
+	summaries    map[string]summary
+	visiting     map[string]bool
+}
+
+// parseDir parses the non-test Go files of [dir].
+func parseDir(fset *token.FileSet, dir string) ([]*ast.File, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// Analyze compares Execute and StateKeys of every action in [actions], which
+// reach state through the helpers in [storage].
+func Analyze(fset *token.FileSet, actions []*ast.File, storage []*ast.File) []Finding {
+	a := &analyzer{
+		fset:         fset,
+		ignored:      make(map[token.Position]map[string]bool),
+		funcs:        make(map[string]*function),
+		constructors: make(map[string][]int),
+		summaries:    make(map[string]summary),
+		visiting:     make(map[string]bool),
+	}
+	a.add(storagePkg, storage)
+	a.add(actionsPkg, actions)
+
+	var findings []Finding
+	for _, name := range a.actionTypes() {
+		execute := a.summarize(methodName(actionsPkg, name, "Execute"))
+		declared := a.summarize(methodName(actionsPkg, name, "StateKeys"))
+		want := make(map[string]bool)
+		for _, r := range declared {
+			want[describe(r.use, stateKeysActor).id()] = true
+		}
+		for _, r := range execute {
+			if d := describe(r.use, executeActor); !want[d.id()] {
+				findings = append(findings, Finding{
+					Action: name,
+					Key:    r.key,
+					Addrs:  addrNames(d.addrs),
+					Via:    r.via,
+				})
+			}
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].String() < findings[j].String()
+	})
+	return findings
+}
+
+func (a *analyzer) add(pkg string, files []*ast.File) {
+	for _, f := range files {
+		a.addDirectives(f)
+		for _, d := range f.Decls {
+			decl, ok := d.(*ast.FuncDecl)
+			if !ok || decl.Body == nil {
+				continue
+			}
+			fn := &function{pkg: pkg, decl: decl, params: paramNames(decl.Type.Params)}
+			a.funcs[funcName(pkg, decl)] = fn
+			if pkg == storagePkg && decl.Recv == nil && isConstructor(decl) {
+				a.constructors[decl.Name.Name] = addrParams(decl.Type.Params)
+			}
+		}
+	}
+}
+
+// addDirectives records the [ignoreDirective] comments of [f] under the
+// lines they apply to.
+func (a *analyzer) addDirectives(f *ast.File) {
+	for _, group := range f.Comments {
+		for _, c := range group.List {
+			fields := strings.Fields(c.Text)
+			if len(fields) < 2 || fields[0] != ignoreDirective {
+				continue
+			}
+			keys := make(map[string]bool)
+			for _, key := range strings.Split(fields[1], ",") {
+				keys[key] = true
+			}
+			pos := a.line(c.Pos())
+			a.ignored[pos] = keys
+			pos.Line++
+			a.ignored[pos] = keys
+		}
+	}
+}
+
+// line returns the position of the line of [pos].
+func (a *analyzer) line(pos token.Pos) token.Position {
+	p := a.fset.Position(pos)
+	return token.Position{Filename: p.Filename, Line: p.Line}
+}
+
+// actionTypes returns the types in the actions package with both an
+// Execute and a StateKeys method.
+func (a *analyzer) actionTypes() []string {
+	var names []string
+	for _, fn := range a.funcs {
+		if fn.pkg != actionsPkg || fn.decl.Recv == nil || fn.decl.Name.Name != "Execute" {
+			continue
+		}
+		recv := recvType(fn.decl)
+		if _, ok := a.funcs[methodName(actionsPkg, recv, "StateKeys")]; ok {
+			names = append(names, recv)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// summarize returns the keys reached from the function [name], keyed by its
+// own parameters.
+func (a *analyzer) summarize(name string) summary {
+	if s, ok := a.summaries[name]; ok {
+		return s
+	}
+	fn, ok := a.funcs[name]
+	// Recursive calls add nothing the outer call does not already reach.
+	if !ok || a.visiting[name] {
+		return nil
+	}
+	a.visiting[name] = true
+	defer delete(a.visiting, name)
+
+	s := make(summary)
+	ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		callee, label := a.resolve(fn, call)
+		if callee == "" {
+			return true
+		}
+		ignored := a.ignored[a.line(call.Pos())]
+		for _, r := range a.summarize(callee) {
+			if ignored[r.key] {
+				continue
+			}
+			u := use{key: r.key, addrs: make([]int, len(r.addrs))}
+			for i, p := range r.addrs {
+				u.addrs[i] = argParam(fn, call, p)
+			}
+			if _, ok := s[u.id()]; !ok {
+				s[u.id()] = reached{use: u, via: label}
+			}
+		}
+		return true
+	})
+	// A constructor that is not built from other constructors is a key of
+	// its own.
+	if fn.pkg == storagePkg && fn.decl.Recv == nil && len(s) == 0 {
+		if addrs, ok := a.constructors[fn.decl.Name.Name]; ok {
+			u := use{key: fn.decl.Name.Name, addrs: addrs}
+			s[u.id()] = reached{use: u, via: storagePkg + "." + u.key}
+		}
+	}
+	a.summaries[name] = s
+	return s
+}
+
+// resolve returns the function [call] in [fn] runs, if it is one of the
+// analyzed functions, and how to name it in findings.
+func (a *analyzer) resolve(fn *function, call *ast.CallExpr) (string, string) {
+	switch f := call.Fun.(type) {
+	case *ast.Ident:
+		if fn.pkg == storagePkg {
+			if _, ok := skipped[f.Name]; ok {
+				return "", ""
+			}
+		}
+		return fn.pkg + "." + f.Name, f.Name
+	case *ast.SelectorExpr:
+		x, ok := f.X.(*ast.Ident)
+		if !ok {
+			return "", ""
+		}
+		if fn.pkg == actionsPkg && x.Name == storagePkg {
+			if _, ok := skipped[f.Sel.Name]; ok {
+				return "", ""
+			}
+			return storagePkg + "." + f.Sel.Name, storagePkg + "." + f.Sel.Name
+		}
+		// Methods called on the receiver of [fn].
+		if fn.decl.Recv != nil && x.Name == recvName(fn.decl) {
+			recv := recvType(fn.decl)
+			return methodName(fn.pkg, recv, f.Sel.Name), x.Name + "." + f.Sel.Name
+		}
+	}
+	return "", ""
+}
+
+// argParam maps parameter [p] of the callee of [call] to a parameter of
+// [fn], or [other].
+func argParam(fn *function, call *ast.CallExpr, p int) int {
+	if p == other || p >= len(call.Args) {
+		return other
+	}
+	ident, ok := call.Args[p].(*ast.Ident)
+	if !ok || ident.Name == "_" {
+		return other
+	}
+	for i, name := range fn.params {
+		if name == ident.Name {
+			return i
+		}
+	}
+	return other
+}
+
+// describe marks the addresses of [u] that are the parameter [actor] as
+// [isActor], and all others as [other].
+func describe(u use, actor int) use {
+	d := use{key: u.key, addrs: make([]int, len(u.addrs))}
+	for i, p := range u.addrs {
+		d.addrs[i] = other
+		if p == actor {
+			d.addrs[i] = isActor
+		}
+	}
+	return d
+}
+
+func addrNames(addrs []int) []string {
+	names := make([]string, len(addrs))
+	for i, p := range addrs {
+		names[i] = "other"
+		if p == isActor {
+			names[i] = "actor"
+		}
+	}
+	return names
+}
+
+// isConstructor reports whether [decl] looks like a storage key
+// constructor: an exported "...Key" function returning a byte slice.
+func isConstructor(decl *ast.FuncDecl) bool {
+	name := decl.Name.Name
+	if !ast.IsExported(name) || !strings.HasSuffix(name, "Key") {
+		return false
+	}
+	results := decl.Type.Results
+	if results == nil || len(results.List) != 1 {
+		return false
+	}
+	t, ok := results.List[0].Type.(*ast.ArrayType)
+	if !ok || t.Len != nil {
+		return false
+	}
+	elt, ok := t.Elt.(*ast.Ident)
+	return ok && elt.Name == "byte"
+}
+
+// addrParams returns the positions of the codec.Address parameters.
+func addrParams(fields *ast.FieldList) []int {
+	var positions []int
+	i := 0
+	for _, field := range fields.List {
+		n := max(len(field.Names), 1)
+		if sel, ok := field.Type.(*ast.SelectorExpr); ok && sel.Sel.Name == "Address" {
+			if x, ok := sel.X.(*ast.Ident); ok && x.Name == "codec" {
+				for j := 0; j < n; j++ {
+					positions = append(positions, i+j)
+				}
+			}
+		}
+		i += n
+	}
+	return positions
+}
+
+func paramNames(fields *ast.FieldList) []string {
+	var names []string
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			names = append(names, "_")
+			continue
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+func funcName(pkg string, decl *ast.FuncDecl) string {
+	if decl.Recv == nil {
+		return pkg + "." + decl.Name.Name
+	}
+	return methodName(pkg, recvType(decl), decl.Name.Name)
+}
+
+func methodName(pkg string, recv string, name string) string {
+	return pkg + "." + recv + "." + name
+}
+
+func recvType(decl *ast.FuncDecl) string {
+	t := decl.Recv.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	if ident, ok := t.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+func recvName(decl *ast.FuncDecl) string {
+	if names := decl.Recv.List[0].Names; len(names) > 0 {
+		return names[0].Name
+	}
+	return "_"
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testStorage = `package storage
+
+func BalanceKey(addr codec.Address) []byte { return nil }
+
+func AssetKey(assetID ids.ID) []byte { return nil }
+
+func LegBalanceKey(addr codec.Address, leg SwapLeg) []byte {
+	if leg.Asset == NativeAsset {
+		return BalanceKey(addr)
+	}
+	return AssetKey(leg.Asset)
+}
+
+func AddBalance(ctx context.Context, mu state.Mutable, addr codec.Address, amount uint64) error {
+	return setBalance(ctx, mu, BalanceKey(addr), amount)
+}
+
+func DeleteAsset(ctx context.Context, mu state.Mutable, assetID ids.ID) error {
+	owner := getOwner(ctx, mu, assetID)
+	return AddBalance(ctx, mu, owner, 0)
+}
+`
+
+const testActions = `package actions
+
+func (t *Pay) StateKeys(actor codec.Address) state.Keys {
+	return t.keys(actor)
+}
+
+func (t *Pay) keys(from codec.Address) state.Keys {
+	return state.Keys{string(storage.LegBalanceKey(from, t.leg())): state.All}
+}
+
+func (t *Pay) Execute(ctx context.Context, _ chain.Rules, mu state.Mutable, _ int64, actor codec.Address, _ ids.ID) (codec.Typed, error) {
+	return nil, pay(ctx, mu, actor, t.To)
+}
+
+func pay(ctx context.Context, mu state.Mutable, from codec.Address, to codec.Address) error {
+	if err := storage.AddBalance(ctx, mu, from, 1); err != nil {
+		return err
+	}
+	return storage.AddBalance(ctx, mu, to, 1)
+}
+
+func (b *Burn) StateKeys(actor codec.Address) state.Keys {
+	return nil
+}
+
+func (b *Burn) Execute(ctx context.Context, _ chain.Rules, mu state.Mutable, _ int64, actor codec.Address, _ ids.ID) (codec.Typed, error) {
+	//statekeys:ignore BalanceKey the owner was checked to be the actor
+	return nil, storage.DeleteAsset(ctx, mu, b.Asset)
+}
+`
+
+func parseSource(t *testing.T, fset *token.FileSet, name string, src string) []*ast.File {
+	f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+	require.NoError(t, err)
+	return []*ast.File{f}
+}
+
+func TestAnalyze(t *testing.T) {
+	require := require.New(t)
+
+	fset := token.NewFileSet()
+	findings := Analyze(
+		fset,
+		parseSource(t, fset, "actions.go", testActions),
+		parseSource(t, fset, "storage.go", testStorage),
+	)
+	// The actor's balance is declared through two helpers, the recipient's
+	// is not, and the burn is ignored.
+	require.Equal([]Finding{{
+		Action: "Pay",
+		Key:    "BalanceKey",
+		Addrs:  []string{"other"},
+		Via:    "pay",
+	}}, findings)
+}
+
+// TestActions keeps the actions of this repository declaring their keys.
+func TestActions(t *testing.T) {
+	require := require.New(t)
+
+	fset := token.NewFileSet()
+	actions, err := parseDir(fset, "../../actions")
+	require.NoError(err)
+	storage, err := parseDir(fset, "../../storage")
+	require.NoError(err)
+	require.Empty(Analyze(fset, actions, storage))
+}
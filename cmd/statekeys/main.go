@@ -0,0 +1,50 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// statekeys checks that every action declares the state it touches. It
+// follows the storage helpers each Execute calls down to the key
+// constructors they use, and fails if StateKeys does not build the same
+// keys for the same addresses. Addresses are compared as "the actor" or
+// "another address", so a missing actor balance is caught but two
+// different recipients are not told apart.
+//
+// Addresses read from state are never the actor as far as statekeys can
+// tell. Where Execute checks that they are, the call is marked with a
+// "//statekeys:ignore <Key>[,<Key>] <reason>" comment.
+//
+// It runs with go generate in the actions package, or from the repository
+// root:
+//
+//	go run ./cmd/statekeys
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"log"
+	"os"
+)
+
+func main() {
+	actionsDir := flag.String("actions", "actions", "directory of the actions package")
+	storageDir := flag.String("storage", "storage", "directory of the storage package")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	actions, err := parseDir(fset, *actionsDir)
+	if err != nil {
+		log.Fatalf("failed to parse actions: %v", err)
+	}
+	storage, err := parseDir(fset, *storageDir)
+	if err != nil {
+		log.Fatalf("failed to parse storage: %v", err)
+	}
+	findings := Analyze(fset, actions, storage)
+	for _, f := range findings {
+		fmt.Fprintln(os.Stderr, f)
+	}
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
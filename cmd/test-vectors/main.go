@@ -0,0 +1,38 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// test-vectors regenerates the codec test vectors. Run it from the
+// repository root after changing an action, output or key layout:
+//
+//	go run ./cmd/test-vectors
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/ava-labs/hypersdk-starter-kit/tests/vectors"
+)
+
+func main() {
+	out := flag.String("out", "tests/vectors/testdata/vectors.json", "file to write the vectors to, or - for stdout")
+	flag.Parse()
+
+	v, err := vectors.Generate()
+	if err != nil {
+		log.Fatalf("failed to generate vectors: %v", err)
+	}
+	b, err := vectors.Marshal(v)
+	if err != nil {
+		log.Fatalf("failed to marshal vectors: %v", err)
+	}
+	if *out == "-" {
+		_, err = os.Stdout.Write(b)
+	} else {
+		err = os.WriteFile(*out, b, 0o600)
+	}
+	if err != nil {
+		log.Fatalf("failed to write vectors: %v", err)
+	}
+}
@@ -5,6 +5,72 @@ package consts
 
 const (
 	// Action TypeIDs
-	TransferID      uint8 = 0
-	AssetTransferID uint8 = 1
+	TransferID                uint8 = 0
+	AssetTransferID           uint8 = 1
+	RedeemVoucherID           uint8 = 2
+	MintAssetID               uint8 = 3
+	BurnAssetID               uint8 = 4
+	TransferAssetID           uint8 = 5
+	SetNotificationPrefsID    uint8 = 6
+	ApproveID                 uint8 = 7
+	TransferFromID            uint8 = 8
+	BatchTransferID           uint8 = 9
+	ProposeTreasurySpendID    uint8 = 10
+	ApproveTreasurySpendID    uint8 = 11
+	SetTransferHookID         uint8 = 12
+	ProposeSwapID             uint8 = 13
+	AcceptSwapID              uint8 = 14
+	RefundSwapID              uint8 = 15
+	FreezeAssetID             uint8 = 16
+	UnfreezeAssetID           uint8 = 17
+	CreateVestingID           uint8 = 18
+	ClaimVestingID            uint8 = 19
+	OpenEscrowID              uint8 = 20
+	ReleaseEscrowID           uint8 = 21
+	RefundEscrowID            uint8 = 22
+	CreateOrderID             uint8 = 23
+	FillOrderID               uint8 = 24
+	CancelOrderID             uint8 = 25
+	CreatePoolID              uint8 = 26
+	AddLiquidityID            uint8 = 27
+	RemoveLiquidityID         uint8 = 28
+	SwapID                    uint8 = 29
+	StakeID                   uint8 = 30
+	UnstakeID                 uint8 = 31
+	ClaimRewardsID            uint8 = 32
+	CreateProposalID          uint8 = 33
+	VoteID                    uint8 = 34
+	ExecuteProposalID         uint8 = 35
+	CreateMultisigID          uint8 = 36
+	ProposeMultisigTxID       uint8 = 37
+	ApproveMultisigTxID       uint8 = 38
+	ProcessEpochID            uint8 = 39
+	MarkCompactionID          uint8 = 40
+	SetClaimID                uint8 = 41
+	AuthorizeSessionKeyID     uint8 = 42
+	RevokeSessionKeyID        uint8 = 43
+	CreateStablecoinID        uint8 = 44
+	AttestReservesID          uint8 = 45
+	MintStablecoinID          uint8 = 46
+	BurnStablecoinID          uint8 = 47
+	SetStablecoinBlockedID    uint8 = 48
+	SetStablecoinPausedID     uint8 = 49
+	HaltChainID               uint8 = 50
+	ResumeChainID             uint8 = 51
+	DeploySmartAccountID      uint8 = 52
+	ExecuteFromSmartAccountID uint8 = 53
+	CreateLeaderboardID       uint8 = 54
+	SubmitScoreID             uint8 = 55
+	OnboardAccountID          uint8 = 56
+	CreateAssetID             uint8 = 57
+	CreateLockerID            uint8 = 58
+	DepositToLockerID         uint8 = 59
+	UnbundleLockerID          uint8 = 60
+	SweepExpiredAssetsID      uint8 = 61
+	PermitTransferID          uint8 = 62
+	CreateAuctionID           uint8 = 63
+	PlaceBidID                uint8 = 64
+	SettleAuctionID           uint8 = 65
+	RegisterAppID             uint8 = 66
+	SetAppCreatorID           uint8 = 67
 )
@@ -0,0 +1,169 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package cost breaks down the units an action adds to the fee of its
+// transaction, so that action developers can see what to trim.
+package cost
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/fees"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var ErrInvalidKey = errors.New("invalid state key")
+
+// Key is the cost of one state key of an action.
+type Key struct {
+	Key codec.Bytes `json:"key"`
+	// Record is the record type of [Key].
+	Record string `json:"record"`
+	// Declared is the permission StateKeys declares and Used the one
+	// Execute needed. A declared key is charged in full whatever its
+	// permission, so one that is never used is pure cost. A key used but
+	// not declared makes the transaction fail.
+	Declared string `json:"declared"`
+	Used     string `json:"used"`
+	// MaxChunks is the value size declared by [Key], which it is charged
+	// for, and Chunks the size of its value after Execute.
+	MaxChunks uint16 `json:"maxChunks"`
+	Chunks    uint16 `json:"chunks"`
+	Reads     uint64 `json:"reads"`
+	Allocates uint64 `json:"allocates"`
+	Writes    uint64 `json:"writes"`
+}
+
+// Units returns the storage units [k] is charged.
+func (k *Key) Units() uint64 {
+	return k.Reads + k.Allocates + k.Writes
+}
+
+// Report breaks down the units charged for an action. The transaction
+// around it adds its base compute units, its auth and the state keys of its
+// sponsor, and charges a key declared by several of its actions once.
+type Report struct {
+	// Bandwidth is the size of the action with its type ID.
+	Bandwidth uint64 `json:"bandwidth"`
+	Compute   uint64 `json:"compute"`
+	Reads     uint64 `json:"reads"`
+	Allocates uint64 `json:"allocates"`
+	Writes    uint64 `json:"writes"`
+	// Keys are most expensive first.
+	Keys []*Key `json:"keys"`
+}
+
+// Units returns the units of [r] by fee dimension.
+func (r *Report) Units() fees.Dimensions {
+	return fees.Dimensions{r.Bandwidth, r.Compute, r.Reads, r.Allocates, r.Writes}
+}
+
+// New reports the cost of [action] for [actor] under [rules]. [used] are
+// the keys Execute touched with the permissions they needed, and [im] the
+// state after Execute.
+func New(
+	ctx context.Context,
+	rules chain.Rules,
+	action chain.Action,
+	actor codec.Address,
+	used state.Keys,
+	im state.Immutable,
+) (*Report, error) {
+	size, err := chain.GetSize(action)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{
+		Bandwidth: consts.ByteLen + uint64(size),
+		Compute:   action.ComputeUnits(rules),
+	}
+
+	declared := action.StateKeys(actor)
+	all := make(map[string]struct{}, len(declared)+len(used))
+	for k := range declared {
+		all[k] = struct{}{}
+	}
+	for k := range used {
+		all[k] = struct{}{}
+	}
+	for k := range all {
+		maxChunks, ok := keys.MaxChunks([]byte(k))
+		if !ok {
+			return nil, ErrInvalidKey
+		}
+		chunks, err := valueChunks(ctx, im, []byte(k))
+		if err != nil {
+			return nil, err
+		}
+		perm, isDeclared := declared[k]
+		key := &Key{
+			Key:       codec.Bytes(k),
+			Record:    storage.PrefixName([]byte(k)),
+			Declared:  PermissionNames(perm),
+			Used:      PermissionNames(used[k]),
+			MaxChunks: maxChunks,
+			Chunks:    chunks,
+		}
+		// Undeclared keys are not charged, since the transaction fails.
+		if isDeclared {
+			key.Reads = rules.GetStorageKeyReadUnits() + uint64(maxChunks)*rules.GetStorageValueReadUnits()
+			key.Allocates = rules.GetStorageKeyAllocateUnits() + uint64(maxChunks)*rules.GetStorageValueAllocateUnits()
+			key.Writes = rules.GetStorageKeyWriteUnits() + uint64(maxChunks)*rules.GetStorageValueWriteUnits()
+		}
+		report.Reads += key.Reads
+		report.Allocates += key.Allocates
+		report.Writes += key.Writes
+		report.Keys = append(report.Keys, key)
+	}
+	sort.Slice(report.Keys, func(i, j int) bool {
+		a, b := report.Keys[i], report.Keys[j]
+		if a.Units() != b.Units() {
+			return a.Units() > b.Units()
+		}
+		return string(a.Key) < string(b.Key)
+	})
+	return report, nil
+}
+
+func valueChunks(ctx context.Context, im state.Immutable, key []byte) (uint16, error) {
+	v, err := im.GetValue(ctx, key)
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	chunks, _ := keys.NumChunks(v)
+	return chunks, nil
+}
+
+// PermissionNames names each permission bit set in [perm].
+func PermissionNames(perm state.Permissions) string {
+	var names []string
+	for _, p := range []struct {
+		bit  state.Permissions
+		name string
+	}{
+		{state.Read, "Read"},
+		{state.Allocate &^ state.Read, "Allocate"},
+		{state.Write &^ state.Read, "Write"},
+	} {
+		if perm&p.bit != 0 {
+			names = append(names, p.name)
+		}
+	}
+	if len(names) == 0 {
+		return "None"
+	}
+	return strings.Join(names, "|")
+}
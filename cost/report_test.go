@@ -0,0 +1,73 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cost_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/keys"
+
+	mchaintest "github.com/ava-labs/hypersdk-starter-kit/chaintest"
+)
+
+func TestTransferCost(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	actor := codectest.NewRandomAddress()
+	to := codectest.NewRandomAddress()
+	store := chaintest.NewInMemoryStore()
+	require.NoError(storage.SetBalance(ctx, store, actor, 10))
+	transfer := &actions.Transfer{To: to, Value: 1}
+
+	report := mchaintest.Cost(ctx, t, chaintest.ActionTest{
+		Name:            "Transfer",
+		Actor:           actor,
+		Action:          transfer,
+		State:           store,
+		ExpectedOutputs: &actions.TransferResult{SenderBalance: 9, ReceiverBalance: 1},
+	})
+	require.NotNil(report)
+
+	r := genesis.NewDefaultRules()
+	require.Equal(transfer.ComputeUnits(r), report.Compute)
+
+	// Every declared key is charged for its declared size.
+	declared := transfer.StateKeys(actor)
+	require.Len(report.Keys, len(declared))
+	var reads, allocates, writes uint64
+	for k := range declared {
+		maxChunks, ok := keys.MaxChunks([]byte(k))
+		require.True(ok)
+		reads += r.GetStorageKeyReadUnits() + uint64(maxChunks)*r.GetStorageValueReadUnits()
+		allocates += r.GetStorageKeyAllocateUnits() + uint64(maxChunks)*r.GetStorageValueAllocateUnits()
+		writes += r.GetStorageKeyWriteUnits() + uint64(maxChunks)*r.GetStorageValueWriteUnits()
+	}
+	require.Equal(reads, report.Reads)
+	require.Equal(allocates, report.Allocates)
+	require.Equal(writes, report.Writes)
+
+	byRecord := make(map[string]string)
+	for i, k := range report.Keys {
+		if i > 0 {
+			require.GreaterOrEqual(report.Keys[i-1].Units(), k.Units())
+		}
+		if string(k.Key) == string(storage.BalanceKey(to)) {
+			require.Equal("Read|Allocate|Write", k.Declared)
+			require.Equal("Read|Allocate|Write", k.Used)
+			require.Equal(uint16(1), k.Chunks)
+		}
+		byRecord[k.Record] = k.Used
+	}
+	// Without a memo, the memo size parameter is not declared.
+	require.NotContains(byRecord, storage.PrefixName(storage.ConfigKey(actions.MaxMemoSizeRule)))
+}
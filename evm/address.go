@@ -0,0 +1,170 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package evm maps the 20-byte addresses of EVM wallets, such as MetaMask,
+// onto MorpheusVM addresses.
+//
+// An EVM address A is the [codec.Address] of type [AddressTypeID] that holds
+// A in its last 20 bytes, after 12 zero bytes, as in ABI encoding. No auth
+// type signs for it yet, so such accounts can receive funds but not spend
+// them.
+package evm
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// AddressTypeID is the type byte of EVM addresses. The type bytes from
+	// 0xfd up are taken by multisig, escrow and treasury addresses, 0xfb by
+	// smart accounts, 0xfa by lockers, 0xf9 by the sweep pool and 0xf8 by
+	// auctions.
+	AddressTypeID uint8 = 0xfc
+
+	AddressLen = 20
+
+	// padding is the number of zero bytes between the type byte and the
+	// EVM address.
+	padding = codec.AddressLen - 1 - AddressLen
+)
+
+var (
+	ErrInvalidAddress = errors.New("invalid address")
+	ErrBadChecksum    = errors.New("bad EIP-55 checksum")
+)
+
+// Address is a 20-byte EVM address.
+type Address [AddressLen]byte
+
+// ToAddress returns the MorpheusVM address of [a].
+func (a Address) ToAddress() codec.Address {
+	var addr codec.Address
+	addr[0] = AddressTypeID
+	copy(addr[1+padding:], a[:])
+	return addr
+}
+
+// String returns [a] in its EIP-55 checksummed form.
+func (a Address) String() string {
+	lower := hex.EncodeToString(a[:])
+	hash := keccak256([]byte(lower))
+	b := []byte("0x" + lower)
+	for i := range lower {
+		// Letters whose nibble of the hash is 8 or more are upper case.
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if lower[i] >= 'a' && nibble&0xf >= 8 {
+			b[2+i] -= 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Address) UnmarshalText(text []byte) error {
+	parsed, err := ParseEVMAddress(string(text))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// FromAddress returns the EVM address [addr] maps, if it is one.
+func FromAddress(addr codec.Address) (Address, bool) {
+	var a Address
+	if !IsEVMAddress(addr) {
+		return a, false
+	}
+	copy(a[:], addr[1+padding:])
+	return a, true
+}
+
+// IsEVMAddress returns whether [addr] maps an EVM address.
+func IsEVMAddress(addr codec.Address) bool {
+	if addr[0] != AddressTypeID {
+		return false
+	}
+	for _, b := range addr[1 : 1+padding] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEVMString returns whether [s] has the form of an EVM address: 0x and 40
+// hex digits. Its checksum is not checked.
+func IsEVMString(s string) bool {
+	hexPart, ok := strings.CutPrefix(s, "0x")
+	if !ok || len(hexPart) != 2*AddressLen {
+		return false
+	}
+	_, err := hex.DecodeString(hexPart)
+	return err == nil
+}
+
+// ParseEVMAddress parses a 0x-prefixed EVM address. Mixed-case addresses
+// must have a valid EIP-55 checksum; all lower or upper case ones carry
+// none.
+func ParseEVMAddress(s string) (Address, error) {
+	var a Address
+	if !IsEVMString(s) {
+		return a, fmt.Errorf("%w: %q is not 0x and %d hex digits", ErrInvalidAddress, s, 2*AddressLen)
+	}
+	hexPart := s[2:]
+	if _, err := hex.Decode(a[:], []byte(hexPart)); err != nil {
+		return a, fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+	if hexPart != strings.ToLower(hexPart) && hexPart != strings.ToUpper(hexPart) && a.String() != s {
+		return a, fmt.Errorf("%w: %s", ErrBadChecksum, s)
+	}
+	return a, nil
+}
+
+// ParseAddress parses [s] as either a MorpheusVM address, 66 hex digits
+// with or without 0x, or an EVM address, 0x and 40 hex digits.
+func ParseAddress(s string) (codec.Address, error) {
+	if IsEVMString(s) {
+		a, err := ParseEVMAddress(s)
+		if err != nil {
+			return codec.EmptyAddress, err
+		}
+		return a.ToAddress(), nil
+	}
+	hexPart := strings.TrimPrefix(s, "0x")
+	if len(hexPart) != 2*codec.AddressLen {
+		return codec.EmptyAddress, fmt.Errorf("%w: %q is not %d hex digits, or 0x and %d", ErrInvalidAddress, s, 2*codec.AddressLen, 2*AddressLen)
+	}
+	b, err := hex.DecodeString(hexPart)
+	if err != nil {
+		return codec.EmptyAddress, fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+	return codec.Address(b), nil
+}
+
+// FormatAddress returns [addr] in EVM form if it maps an EVM address, and
+// in the 0x-prefixed MorpheusVM form of its JSON otherwise.
+func FormatAddress(addr codec.Address) string {
+	if a, ok := FromAddress(addr); ok {
+		return a.String()
+	}
+	return "0x" + addr.String()
+}
+
+func keccak256(b []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(b)
+	return h.Sum(nil)
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+)
+
+// Test cases of EIP-55.
+var checksummed = []string{
+	"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+	"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+	"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+}
+
+func TestChecksum(t *testing.T) {
+	require := require.New(t)
+
+	for _, s := range checksummed {
+		a, err := ParseEVMAddress(s)
+		require.NoError(err)
+		require.Equal(s, a.String())
+
+		_, err = ParseEVMAddress(strings.ToLower(s))
+		require.NoError(err)
+		_, err = ParseEVMAddress("0x" + strings.ToUpper(s[2:]))
+		require.NoError(err)
+	}
+
+	// Flipping the case of one letter breaks the checksum.
+	_, err := ParseEVMAddress("0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	require.ErrorIs(err, ErrBadChecksum)
+	_, err = ParseEVMAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA")
+	require.ErrorIs(err, ErrInvalidAddress)
+}
+
+func TestRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	// EVM form to MorpheusVM form and back.
+	a, err := ParseEVMAddress(checksummed[0])
+	require.NoError(err)
+	addr := a.ToAddress()
+	require.Equal(AddressTypeID, addr[0])
+	require.True(IsEVMAddress(addr))
+	back, ok := FromAddress(addr)
+	require.True(ok)
+	require.Equal(a, back)
+	require.Equal(checksummed[0], FormatAddress(addr))
+
+	parsed, err := ParseAddress(checksummed[0])
+	require.NoError(err)
+	require.Equal(addr, parsed)
+	parsed, err = ParseAddress("0x" + addr.String())
+	require.NoError(err)
+	require.Equal(addr, parsed)
+
+	// Other addresses keep their MorpheusVM form.
+	other := codectest.NewRandomAddress()
+	_, ok = FromAddress(other)
+	require.False(ok)
+	parsed, err = ParseAddress(FormatAddress(other))
+	require.NoError(err)
+	require.Equal(other, parsed)
+	parsed, err = ParseAddress(other.String())
+	require.NoError(err)
+	require.Equal(other, parsed)
+
+	// An address of the EVM type with non-zero padding maps no EVM address.
+	padded := addr
+	padded[1] = 1
+	require.False(IsEVMAddress(padded))
+
+	_, err = ParseAddress("0x1234")
+	require.ErrorIs(err, ErrInvalidAddress)
+	_, err = ParseAddress(strings.Repeat("z", 2*codec.AddressLen))
+	require.ErrorIs(err, ErrInvalidAddress)
+}
+
+func TestJSON(t *testing.T) {
+	require := require.New(t)
+
+	a, err := ParseEVMAddress(checksummed[1])
+	require.NoError(err)
+	b, err := json.Marshal(a)
+	require.NoError(err)
+	require.Equal(`"`+checksummed[1]+`"`, string(b))
+
+	var decoded Address
+	require.NoError(json.Unmarshal([]byte(`"`+strings.ToLower(checksummed[1])+`"`), &decoded))
+	require.Equal(a, decoded)
+	require.ErrorIs(json.Unmarshal([]byte(`"0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"`), &decoded), ErrBadChecksum)
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package proof checks the state proofs served by the getProof API method,
+// so that light clients can trust a state value given only a block header.
+// It depends on neither the VM nor its storage.
+package proof
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/ava-labs/avalanchego/proto/pb/sync"
+)
+
+var ErrInvalidProof = errors.New("invalid state proof")
+
+// Marshal encodes [proof], a range proof of a single key, as Verify and
+// the getProof method expect it.
+func Marshal(proof *merkledb.RangeProof) ([]byte, error) {
+	return proto.Marshal(proof.ToProto())
+}
+
+// Verify checks that [proof] proves the value of [key] in the state with
+// root [root], built with [branchFactor]. It returns the value of [key],
+// or false if [proof] proves that [key] does not exist.
+//
+// The root of the state after a block is committed to by the StateRoot of
+// its child, and the branch factor is set by the genesis.
+func Verify(
+	ctx context.Context,
+	root ids.ID,
+	key []byte,
+	proof []byte,
+	branchFactor merkledb.BranchFactor,
+) ([]byte, bool, error) {
+	if err := branchFactor.Valid(); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+	var pbProof pb.RangeProof
+	if err := proto.Unmarshal(proof, &pbProof); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+	var rangeProof merkledb.RangeProof
+	if err := rangeProof.UnmarshalProto(&pbProof); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+	if err := rangeProof.Verify(
+		ctx,
+		maybe.Some(key),
+		maybe.Some(key),
+		root,
+		merkledb.BranchFactorToTokenSize[branchFactor],
+		merkledb.DefaultHasher,
+	); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+	// Verify checks that the proof holds every key in [key, key].
+	switch {
+	case len(rangeProof.KeyValues) == 0:
+		return nil, false, nil
+	case len(rangeProof.KeyValues) == 1 && bytes.Equal(rangeProof.KeyValues[0].Key, key):
+		return rangeProof.KeyValues[0].Value, true, nil
+	default:
+		return nil, false, fmt.Errorf("%w: %d values", ErrInvalidProof, len(rangeProof.KeyValues))
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proof
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	db, err := merkledb.New(ctx, memdb.New(), merkledb.Config{
+		BranchFactor:                merkledb.BranchFactor16,
+		Hasher:                      merkledb.DefaultHasher,
+		HistoryLength:               16,
+		ValueNodeCacheSize:          units.MiB,
+		IntermediateNodeCacheSize:   units.MiB,
+		IntermediateWriteBufferSize: units.KiB,
+		IntermediateWriteBatchSize:  units.KiB,
+		Reg:                         prometheus.NewRegistry(),
+		TraceLevel:                  merkledb.InfoTrace,
+		Tracer:                      trace.Noop,
+	})
+	require.NoError(err)
+	require.NoError(db.Put([]byte("balance/a"), []byte{1}))
+	require.NoError(db.Put([]byte("balance/c"), []byte{3}))
+	root, err := db.GetMerkleRoot(ctx)
+	require.NoError(err)
+
+	prove := func(key string) []byte {
+		p, err := db.GetRangeProofAtRoot(ctx, root, maybe.Some([]byte(key)), maybe.Some([]byte(key)), 1)
+		require.NoError(err)
+		b, err := Marshal(p)
+		require.NoError(err)
+		return b
+	}
+
+	value, exists, err := Verify(ctx, root, []byte("balance/a"), prove("balance/a"), merkledb.BranchFactor16)
+	require.NoError(err)
+	require.True(exists)
+	require.Equal([]byte{1}, value)
+
+	// A key between two others is proven absent.
+	_, exists, err = Verify(ctx, root, []byte("balance/b"), prove("balance/b"), merkledb.BranchFactor16)
+	require.NoError(err)
+	require.False(exists)
+
+	// A proof only holds for its key and its root.
+	_, _, err = Verify(ctx, root, []byte("balance/c"), prove("balance/a"), merkledb.BranchFactor16)
+	require.ErrorIs(err, ErrInvalidProof)
+	_, _, err = Verify(ctx, ids.GenerateTestID(), []byte("balance/a"), prove("balance/a"), merkledb.BranchFactor16)
+	require.ErrorIs(err, ErrInvalidProof)
+	_, _, err = Verify(ctx, root, []byte("balance/a"), []byte{0xff}, merkledb.BranchFactor16)
+	require.ErrorIs(err, ErrInvalidProof)
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package screening checks transactions against an off-chain risk provider
+// before the node admits them.
+package screening
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/cache"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+const Namespace = "screening"
+
+var (
+	ErrFlagged     = errors.New("address is on a risk list")
+	ErrUnavailable = errors.New("screening provider unavailable")
+	ErrNoProvider  = errors.New("screening enabled without a provider url")
+)
+
+// Screener decides whether a transaction may be submitted to the mempool.
+type Screener interface {
+	Screen(ctx context.Context, tx *chain.Transaction) error
+}
+
+type Config struct {
+	Enabled bool `json:"enabled"`
+
+	// URL of the provider. It receives a POST with a [Request] for every
+	// address missing from the cache.
+	URL string `json:"url"`
+
+	// RiskLists are the provider lists an address is checked against.
+	RiskLists []string `json:"riskLists"`
+
+	Timeout   time.Duration `json:"timeout"`
+	CacheTTL  time.Duration `json:"cacheTTL"`
+	CacheSize int           `json:"cacheSize"`
+
+	// FailOpen admits transactions when the provider errors or times out.
+	// By default they are rejected with [ErrUnavailable].
+	FailOpen bool `json:"failOpen"`
+}
+
+func NewDefaultConfig() Config {
+	return Config{
+		Timeout:   2 * time.Second,
+		CacheTTL:  5 * time.Minute,
+		CacheSize: 65_536,
+	}
+}
+
+// Request is the body posted to the provider.
+type Request struct {
+	Address   codec.Address `json:"address"`
+	RiskLists []string      `json:"riskLists"`
+}
+
+// Response is the provider's verdict. [Lists] names the requested lists the
+// address is on; an empty list clears it.
+type Response struct {
+	Lists []string `json:"lists"`
+}
+
+type verdict struct {
+	lists   []string
+	expires time.Time
+}
+
+// HTTPScreener screens the actor and sponsor of each transaction with an
+// HTTP provider, caching verdicts for [Config.CacheTTL].
+type HTTPScreener struct {
+	config Config
+	client *http.Client
+	cache  *cache.LRU[codec.Address, verdict]
+	now    func() time.Time
+}
+
+func NewHTTPScreener(config Config) (*HTTPScreener, error) {
+	if config.URL == "" {
+		return nil, ErrNoProvider
+	}
+	return &HTTPScreener{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+		cache:  &cache.LRU[codec.Address, verdict]{Size: config.CacheSize},
+		now:    time.Now,
+	}, nil
+}
+
+func (s *HTTPScreener) Screen(ctx context.Context, tx *chain.Transaction) error {
+	addrs := []codec.Address{tx.Auth.Actor()}
+	if sponsor := tx.Auth.Sponsor(); sponsor != addrs[0] {
+		addrs = append(addrs, sponsor)
+	}
+	for _, addr := range addrs {
+		lists, err := s.lookup(ctx, addr)
+		if err != nil {
+			if s.config.FailOpen {
+				continue
+			}
+			return fmt.Errorf("%w: %w", ErrUnavailable, err)
+		}
+		if len(lists) > 0 {
+			return fmt.Errorf("%w: %s on %s", ErrFlagged, addr, strings.Join(lists, ","))
+		}
+	}
+	return nil
+}
+
+// lookup returns the risk lists [addr] is on. Provider failures are not
+// cached, so the next submission retries.
+func (s *HTTPScreener) lookup(ctx context.Context, addr codec.Address) ([]string, error) {
+	if v, ok := s.cache.Get(addr); ok && s.now().Before(v.expires) {
+		return v.lists, nil
+	}
+	body, err := json.Marshal(&Request{Address: addr, RiskLists: s.config.RiskLists})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("provider returned %s", resp.Status)
+	}
+	var r Response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, err
+	}
+	// Only act on lists the operator configured.
+	lists := slices.DeleteFunc(r.Lists, func(l string) bool {
+		return !slices.Contains(s.config.RiskLists, l)
+	})
+	s.cache.Put(addr, verdict{lists: lists, expires: s.now().Add(s.config.CacheTTL)})
+	return lists, nil
+}
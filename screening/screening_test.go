@@ -0,0 +1,91 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package screening
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+func newTx(t *testing.T) *chain.Transaction {
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(t, err)
+	return &chain.Transaction{Auth: &auth.ED25519{Signer: priv.PublicKey()}}
+}
+
+// provider returns a server that puts the addresses in [flagged] on the
+// "sanctions" and "unrequested" lists.
+func provider(t *testing.T, flagged map[codec.Address]bool, calls *int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		var req Request
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		var resp Response
+		if flagged[req.Address] {
+			resp.Lists = []string{"sanctions", "unrequested"}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(&resp))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testConfig(url string) Config {
+	config := NewDefaultConfig()
+	config.Enabled = true
+	config.URL = url
+	config.RiskLists = []string{"sanctions"}
+	return config
+}
+
+func TestHTTPScreener(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	clean, dirty := newTx(t), newTx(t)
+	var calls int
+	server := provider(t, map[codec.Address]bool{dirty.Auth.Actor(): true}, &calls)
+	s, err := NewHTTPScreener(testConfig(server.URL))
+	require.NoError(err)
+
+	require.NoError(s.Screen(ctx, clean))
+	require.NoError(s.Screen(ctx, clean))
+	require.Equal(1, calls)
+
+	err = s.Screen(ctx, dirty)
+	require.ErrorIs(err, ErrFlagged)
+	require.Contains(err.Error(), "sanctions")
+	require.NotContains(err.Error(), "unrequested")
+}
+
+func TestHTTPScreenerFailPolicy(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	tx := newTx(t)
+
+	config := testConfig(server.URL)
+	closed, err := NewHTTPScreener(config)
+	require.NoError(err)
+	require.ErrorIs(closed.Screen(ctx, tx), ErrUnavailable)
+
+	config.FailOpen = true
+	open, err := NewHTTPScreener(config)
+	require.NoError(err)
+	require.NoError(open.Screen(ctx, tx))
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// [allowancePrefix] + [owner] + [spender]
+func AllowanceKey(owner codec.Address, spender codec.Address) (k []byte) {
+	k = make([]byte, 1+2*codec.AddressLen+consts.Uint16Len)
+	k[0] = allowancePrefix
+	copy(k[1:], owner[:])
+	copy(k[1+codec.AddressLen:], spender[:])
+	binary.BigEndian.PutUint16(k[1+2*codec.AddressLen:], AllowanceChunks)
+	return
+}
+
+// GetAllowance returns how much of [owner]'s balance [spender] may transfer.
+func GetAllowance(
+	ctx context.Context,
+	im state.Immutable,
+	owner codec.Address,
+	spender codec.Address,
+) (uint64, error) {
+	allowance, _, err := innerGetBalance(getValue(ctx, im, AllowanceKey(owner, spender)))
+	return allowance, err
+}
+
+// Used to serve RPC queries
+func GetAllowanceFromState(
+	ctx context.Context,
+	f ReadState,
+	owner codec.Address,
+	spender codec.Address,
+) (uint64, error) {
+	values, errs := f(ctx, [][]byte{AllowanceKey(owner, spender)})
+	allowance, _, err := innerGetBalance(values[0], errs[0])
+	return allowance, err
+}
+
+// SetAllowance replaces the allowance of [spender] over [owner]'s balance. A
+// zero allowance removes the record.
+func SetAllowance(
+	ctx context.Context,
+	mu state.Mutable,
+	owner codec.Address,
+	spender codec.Address,
+	allowance uint64,
+) error {
+	k := AllowanceKey(owner, spender)
+	if allowance == 0 {
+		return Delete(ctx, mu, k)
+	}
+	return setBalance(ctx, mu, k, allowance)
+}
+
+// ConsumeAllowance deducts [amount] from the allowance of [spender] over
+// [owner]'s balance and returns what is left.
+func ConsumeAllowance(
+	ctx context.Context,
+	mu state.Mutable,
+	owner codec.Address,
+	spender codec.Address,
+	amount uint64,
+) (uint64, error) {
+	allowance, err := GetAllowance(ctx, mu, owner, spender)
+	if err != nil {
+		return 0, err
+	}
+	if allowance < amount {
+		return 0, fmt.Errorf(
+			"%w: (allowance=%d, owner=%v, spender=%v, amount=%d)",
+			ErrInsufficientAllowance,
+			allowance,
+			owner,
+			spender,
+			amount,
+		)
+	}
+	return allowance - amount, SetAllowance(ctx, mu, owner, spender, allowance-amount)
+}
@@ -0,0 +1,237 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+const MaxAppNameSize = 32
+
+const (
+	appAdmin   = 0x0
+	appCreator = 0x1
+	appAsset   = 0x2
+)
+
+// appEntryValue marks an entry of the creator list or asset index of an
+// app. The key carries all the information.
+var appEntryValue = []byte{1}
+
+// AppID is the ID of the app registered under [name]. Names are taken by
+// whoever registers them first.
+func AppID(name string) ids.ID {
+	b := make([]byte, 1+len(name))
+	b[0] = appPrefix
+	copy(b[1:], name)
+	return utils.ToID(b)
+}
+
+// AppAssetID is the ID of the asset [creator] creates with [nonce] in
+// [app]. The app is part of the preimage, so assets of different apps never
+// share an ID, nor with those [AssetID] derives.
+func AppAssetID(app ids.ID, creator codec.Address, nonce uint64) ids.ID {
+	b := make([]byte, 1+ids.IDLen+codec.AddressLen+consts.Uint64Len)
+	b[0] = assetPrefix
+	copy(b[1:], app[:])
+	copy(b[1+ids.IDLen:], creator[:])
+	binary.BigEndian.PutUint64(b[1+ids.IDLen+codec.AddressLen:], nonce)
+	return utils.ToID(b)
+}
+
+// [appPrefix] + [appAdmin] + [app]
+func AppKey(app ids.ID) (k []byte) {
+	k = make([]byte, 2+ids.IDLen+consts.Uint16Len)
+	k[0] = appPrefix
+	k[1] = appAdmin
+	copy(k[2:], app[:])
+	binary.BigEndian.PutUint16(k[2+ids.IDLen:], AppChunks)
+	return
+}
+
+// [appPrefix] + [appCreator] + [app] + [creator]
+//
+// Keys of one app share the prefix [appPrefix] + [appCreator] + [app] and
+// sort by creator, which is what [GetAppCreators] scans.
+func AppCreatorKey(app ids.ID, creator codec.Address) (k []byte) {
+	k = make([]byte, 2+ids.IDLen+codec.AddressLen+consts.Uint16Len)
+	k[0] = appPrefix
+	k[1] = appCreator
+	copy(k[2:], app[:])
+	copy(k[2+ids.IDLen:], creator[:])
+	binary.BigEndian.PutUint16(k[2+ids.IDLen+codec.AddressLen:], AppCreatorChunks)
+	return
+}
+
+// [appPrefix] + [appAsset] + [app] + [assetID]
+//
+// Keys of one app share the prefix [appPrefix] + [appAsset] + [app] and
+// sort by asset ID, which is what [GetAssetsByApp] scans.
+func AppAssetKey(app ids.ID, assetID ids.ID) (k []byte) {
+	k = make([]byte, 2+ids.IDLen+ids.IDLen+consts.Uint16Len)
+	k[0] = appPrefix
+	k[1] = appAsset
+	copy(k[2:], app[:])
+	copy(k[2+ids.IDLen:], assetID[:])
+	binary.BigEndian.PutUint16(k[2+2*ids.IDLen:], AppAssetChunks)
+	return
+}
+
+func appEntriesPrefix(entry byte, app ids.ID) []byte {
+	k := make([]byte, 2+ids.IDLen)
+	k[0] = appPrefix
+	k[1] = entry
+	copy(k[2:], app[:])
+	return k
+}
+
+// GetAppAdmin returns the admin of [app], if it is registered.
+func GetAppAdmin(
+	ctx context.Context,
+	im state.Immutable,
+	app ids.ID,
+) (codec.Address, bool, error) {
+	return innerGetAppAdmin(getValue(ctx, im, AppKey(app)))
+}
+
+// Used to serve RPC queries
+func GetAppAdminFromState(
+	ctx context.Context,
+	f ReadState,
+	app ids.ID,
+) (codec.Address, bool, error) {
+	values, errs := f(ctx, [][]byte{AppKey(app)})
+	return innerGetAppAdmin(values[0], errs[0])
+}
+
+func innerGetAppAdmin(v []byte, err error) (codec.Address, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return codec.EmptyAddress, false, nil
+	}
+	if err != nil {
+		return codec.EmptyAddress, false, err
+	}
+	admin, err := codec.ToAddress(v)
+	return admin, err == nil, err
+}
+
+// SetAppAdmin registers [app] with [admin], who decides who creates assets
+// in it.
+func SetAppAdmin(
+	ctx context.Context,
+	mu state.Mutable,
+	app ids.ID,
+	admin codec.Address,
+) error {
+	return insertValue(ctx, mu, AppKey(app), admin[:])
+}
+
+// IsAppCreator returns whether the admin of [app] allowed [creator] to
+// create assets in it.
+func IsAppCreator(
+	ctx context.Context,
+	im state.Immutable,
+	app ids.ID,
+	creator codec.Address,
+) (bool, error) {
+	return innerIsAppCreator(getValue(ctx, im, AppCreatorKey(app, creator)))
+}
+
+func innerIsAppCreator(_ []byte, err error) (bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// SetAppCreator adds [creator] to the creators of [app], or removes it.
+func SetAppCreator(
+	ctx context.Context,
+	mu state.Mutable,
+	app ids.ID,
+	creator codec.Address,
+	allowed bool,
+) error {
+	k := AppCreatorKey(app, creator)
+	if !allowed {
+		return Delete(ctx, mu, k)
+	}
+	return insertValue(ctx, mu, k, appEntryValue)
+}
+
+// AddAppAsset records [assetID] in the asset index of [app]. Entries are
+// kept once the asset is burned, as burning does not name the app.
+func AddAppAsset(
+	ctx context.Context,
+	mu state.Mutable,
+	app ids.ID,
+	assetID ids.ID,
+) error {
+	return insertValue(ctx, mu, AppAssetKey(app, assetID), appEntryValue)
+}
+
+// AppEntries iterates over the creators or assets of one app in key order.
+type AppEntries struct {
+	it database.Iterator
+}
+
+// GetAppCreators returns an iterator over the creators of [app] in [db],
+// starting at [start]. Pass [codec.EmptyAddress] to start from the first
+// one. Read them with [AppEntries.Creator].
+//
+// The iterator must be released once done.
+func GetAppCreators(db database.Iteratee, app ids.ID, start codec.Address) *AppEntries {
+	prefix := appEntriesPrefix(appCreator, app)
+	return &AppEntries{
+		it: db.NewIteratorWithStartAndPrefix(append(prefix, start[:]...), prefix),
+	}
+}
+
+// GetAssetsByApp returns an iterator over the assets created in [app] in
+// [db], starting at [start]. Pass [ids.Empty] to start from the first one.
+// Read them with [AppEntries.Asset].
+//
+// The iterator must be released once done.
+func GetAssetsByApp(db database.Iteratee, app ids.ID, start ids.ID) *AppEntries {
+	prefix := appEntriesPrefix(appAsset, app)
+	return &AppEntries{
+		it: db.NewIteratorWithStartAndPrefix(append(prefix, start[:]...), prefix),
+	}
+}
+
+func (a *AppEntries) Next() bool {
+	return a.it.Next()
+}
+
+// Creator returns the current creator of a [GetAppCreators] iterator. It
+// is only valid after Next returned true.
+func (a *AppEntries) Creator() codec.Address {
+	k := a.it.Key()
+	return codec.Address(k[2+ids.IDLen:])
+}
+
+// Asset returns the current asset of a [GetAssetsByApp] iterator. It is
+// only valid after Next returned true.
+func (a *AppEntries) Asset() ids.ID {
+	k := a.it.Key()
+	return ids.ID(k[2+ids.IDLen:])
+}
+
+func (a *AppEntries) Error() error {
+	return a.it.Error()
+}
+
+func (a *AppEntries) Release() {
+	a.it.Release()
+}
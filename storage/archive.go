@@ -0,0 +1,125 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// The archive is a database apart from state that keeps every version of
+// every key, so that state can be read at any height it covers.
+//
+// [len(key)] + [key] + [^height]
+//
+// The versions of a key share the prefix [len(key)] + [key] and sort from
+// the newest height down, so the first version at or after
+// [ArchiveKey(key, height)] is the one in effect at [height].
+func ArchiveKey(key []byte, height uint64) []byte {
+	k := archivePrefix(key)
+	return binary.BigEndian.AppendUint64(k, ^height)
+}
+
+func archivePrefix(key []byte) []byte {
+	k := make([]byte, consts.Uint16Len+len(key), consts.Uint16Len+len(key)+consts.Uint64Len)
+	binary.BigEndian.PutUint16(k, uint16(len(key)))
+	copy(k[consts.Uint16Len:], key)
+	return k
+}
+
+// archiveRangeKey would be the length of a key of [maxArchivedKeyLen]
+// bytes, which is not archived, so it is no version's prefix.
+var archiveRangeKey = []byte{0xff, 0xff}
+
+const maxArchivedKeyLen = 1<<16 - 1
+
+// Archived values are a byte marking whether the key exists, followed by
+// its value.
+const (
+	archiveDeleted byte = iota
+	archiveExists
+)
+
+// PutArchived records [value] as the version of [key] after the block at
+// [height], or its deletion if [deleted].
+func PutArchived(w database.KeyValueWriter, key []byte, height uint64, value []byte, deleted bool) error {
+	if len(key) >= maxArchivedKeyLen {
+		return fmt.Errorf("%w: %d bytes cannot be archived", ErrInvalidKey, len(key))
+	}
+	if deleted {
+		return w.Put(ArchiveKey(key, height), []byte{archiveDeleted})
+	}
+	return w.Put(ArchiveKey(key, height), append([]byte{archiveExists}, value...))
+}
+
+// GetArchiveRange returns the oldest and newest heights the archive covers,
+// if it covers any.
+func GetArchiveRange(db database.KeyValueReader) (uint64, uint64, bool, error) {
+	v, err := db.Get(archiveRangeKey)
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, 0, false, nil
+	}
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if len(v) != 2*consts.Uint64Len {
+		return 0, 0, false, fmt.Errorf("%w: archive range is %d bytes", ErrNotArchived, len(v))
+	}
+	return binary.BigEndian.Uint64(v), binary.BigEndian.Uint64(v[consts.Uint64Len:]), true, nil
+}
+
+// SetArchiveRange records that the archive covers every height from
+// [oldest] to [newest]. It must be written together with the versions of
+// [newest].
+func SetArchiveRange(w database.KeyValueWriter, oldest, newest uint64) error {
+	v := binary.BigEndian.AppendUint64(nil, oldest)
+	return w.Put(archiveRangeKey, binary.BigEndian.AppendUint64(v, newest))
+}
+
+// ReadArchived returns a [ReadState] serving values as they were after the
+// block at [height] was accepted, from the archive [db]. Reads fail with
+// [ErrNotArchived] if [height] is outside the range it covers.
+func ReadArchived(db database.Database, height uint64) ReadState {
+	return func(_ context.Context, keys [][]byte) ([][]byte, []error) {
+		values := make([][]byte, len(keys))
+		oldest, newest, ok, err := GetArchiveRange(db)
+		switch {
+		case err != nil:
+			return values, utils.Repeat(err, len(keys))
+		case !ok || height < oldest || height > newest:
+			err := fmt.Errorf("%w: height=%d, oldest=%d, newest=%d", ErrNotArchived, height, oldest, newest)
+			return values, utils.Repeat(err, len(keys))
+		}
+		errs := make([]error, len(keys))
+		for i, key := range keys {
+			values[i], errs[i] = getArchived(db, key, height)
+		}
+		return values, errs
+	}
+}
+
+func getArchived(db database.Iteratee, key []byte, height uint64) ([]byte, error) {
+	it := db.NewIteratorWithStartAndPrefix(ArchiveKey(key, height), archivePrefix(key))
+	defer it.Release()
+
+	if !it.Next() {
+		if err := it.Error(); err != nil {
+			return nil, err
+		}
+		return nil, database.ErrNotFound
+	}
+	v := it.Value()
+	if len(v) == 0 || v[0] != archiveExists {
+		return nil, database.ErrNotFound
+	}
+	return slices.Clone(v[1:]), nil
+}
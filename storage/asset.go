@@ -0,0 +1,291 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const (
+	MaxAssetNameSize   = 64
+	MaxAssetSymbolSize = 16
+	MaxAssetURISize    = 256
+
+	// Asset values are laid out as
+	//   [owner] + [name] + [symbol] + [decimals] + [uri] + [totalSupply] +
+	//   [minter] + [frozen] + [royaltyBasisPoints] + [royaltyPayee] +
+	//   [soulbound] + [expiry]
+	// Values holding only [owner] have empty metadata. Values written before
+	// minters were recorded end after [totalSupply]. In both cases the owner
+	// is taken to be the minter and the asset is not frozen. Values without a
+	// royalty end after [frozen], values of assets that are not soulbound
+	// end after [royaltyPayee] or before it, and values of assets that never
+	// expire end after [soulbound] or before it.
+	maxAssetValueSize = codec.AddressLen +
+		consts.Uint16Len + MaxAssetNameSize +
+		consts.Uint16Len + MaxAssetSymbolSize +
+		consts.ByteLen +
+		consts.Uint16Len + MaxAssetURISize +
+		consts.Uint64Len +
+		codec.AddressLen +
+		consts.BoolLen +
+		consts.Uint16Len +
+		codec.AddressLen +
+		consts.BoolLen +
+		consts.Int64Len
+)
+
+type AssetMetadata struct {
+	Name        string `json:"name"`
+	Symbol      string `json:"symbol"`
+	Decimals    uint8  `json:"decimals"`
+	URI         string `json:"uri"`
+	TotalSupply uint64 `json:"totalSupply"`
+}
+
+// AssetControl is the issuer state of an asset. Unlike the owner, the minter
+// never changes.
+type AssetControl struct {
+	Minter codec.Address `json:"minter"`
+	Frozen bool          `json:"frozen"`
+
+	// RoyaltyBasisPoints of the sale price of each priced transfer are paid
+	// to [RoyaltyPayee]. Zero means the asset has no royalty.
+	RoyaltyBasisPoints uint16        `json:"royaltyBasisPoints"`
+	RoyaltyPayee       codec.Address `json:"royaltyPayee"`
+
+	// Soulbound assets stay with their owner. They can be burned but not
+	// transferred. It is set when the asset is created and never changes.
+	Soulbound bool `json:"soulbound"`
+
+	// Expiry is the last timestamp, in milliseconds, at which the asset can
+	// be transferred. Expired assets can be swept by anyone. Zero means the
+	// asset never expires. It is set when the asset is created and never
+	// changes.
+	Expiry int64 `json:"expiry"`
+}
+
+// Expired reports whether an asset under [c] has expired at [timestamp].
+func (c AssetControl) Expired(timestamp int64) bool {
+	return c.Expiry != 0 && c.Expiry < timestamp
+}
+
+// Verify checks that [m] fits within the asset size limits.
+func (m *AssetMetadata) Verify() error {
+	switch {
+	case len(m.Name) > MaxAssetNameSize:
+		return fmt.Errorf("%w: name is %d bytes", ErrAssetMetadataTooLarge, len(m.Name))
+	case len(m.Symbol) > MaxAssetSymbolSize:
+		return fmt.Errorf("%w: symbol is %d bytes", ErrAssetMetadataTooLarge, len(m.Symbol))
+	case len(m.URI) > MaxAssetURISize:
+		return fmt.Errorf("%w: uri is %d bytes", ErrAssetMetadataTooLarge, len(m.URI))
+	default:
+		return nil
+	}
+}
+
+func packAsset(owner codec.Address, m AssetMetadata, c AssetControl) ([]byte, error) {
+	p := codec.NewWriter(maxAssetValueSize, maxAssetValueSize)
+	p.PackAddress(owner)
+	p.PackString(m.Name)
+	p.PackString(m.Symbol)
+	p.PackByte(m.Decimals)
+	p.PackString(m.URI)
+	p.PackUint64(m.TotalSupply)
+	p.PackFixedBytes(c.Minter[:])
+	p.PackBool(c.Frozen)
+	if c.RoyaltyBasisPoints > 0 || c.Soulbound || c.Expiry != 0 {
+		p.PackShort(c.RoyaltyBasisPoints)
+		p.PackFixedBytes(c.RoyaltyPayee[:])
+	}
+	if c.Soulbound || c.Expiry != 0 {
+		p.PackBool(c.Soulbound)
+	}
+	if c.Expiry != 0 {
+		p.PackInt64(c.Expiry)
+	}
+	v := p.Bytes()
+	if chunks, _ := keys.NumChunks(v); chunks > AssetChunks {
+		return nil, fmt.Errorf("%w: asset is %d bytes", ErrAssetMetadataTooLarge, len(v))
+	}
+	return v, nil
+}
+
+// ParseAsset decodes a value stored under [AssetKey].
+func ParseAsset(v []byte) (codec.Address, AssetMetadata, AssetControl, error) {
+	return unpackAsset(v)
+}
+
+func unpackAsset(v []byte) (codec.Address, AssetMetadata, AssetControl, error) {
+	var (
+		owner codec.Address
+		m     AssetMetadata
+		c     AssetControl
+	)
+	if len(v) == codec.AddressLen {
+		owner, err := codec.ToAddress(v)
+		return owner, m, AssetControl{Minter: owner}, err
+	}
+	p := codec.NewReader(v, maxAssetValueSize)
+	// [codec.Packer.UnpackAddress] rejects [codec.EmptyAddress], which is a
+	// valid owner.
+	ownerBytes := owner[:]
+	p.UnpackFixedBytes(codec.AddressLen, &ownerBytes)
+	m.Name = p.UnpackString(false)
+	m.Symbol = p.UnpackString(false)
+	m.Decimals = p.UnpackByte()
+	m.URI = p.UnpackString(false)
+	m.TotalSupply = p.UnpackUint64(false)
+	c.Minter = owner
+	if !p.Empty() {
+		minterBytes := c.Minter[:]
+		p.UnpackFixedBytes(codec.AddressLen, &minterBytes)
+		c.Frozen = p.UnpackBool()
+	}
+	if !p.Empty() {
+		c.RoyaltyBasisPoints = p.UnpackShort()
+		payeeBytes := c.RoyaltyPayee[:]
+		p.UnpackFixedBytes(codec.AddressLen, &payeeBytes)
+	}
+	if !p.Empty() {
+		c.Soulbound = p.UnpackBool()
+	}
+	if !p.Empty() {
+		c.Expiry = p.UnpackInt64(true)
+	}
+	if err := p.Err(); err != nil {
+		return owner, m, c, fmt.Errorf("%w: %w", ErrInvalidAsset, err)
+	}
+	if !p.Empty() {
+		return owner, m, c, fmt.Errorf("%w: trailing bytes", ErrInvalidAsset)
+	}
+	return owner, m, c, nil
+}
+
+// GetAssetMetadata returns the metadata of [assetID]. It returns
+// [ErrAssetNotFound] if the asset does not exist.
+func GetAssetMetadata(
+	ctx context.Context,
+	im state.Immutable,
+	assetID ids.ID,
+) (AssetMetadata, error) {
+	_, m, _, exists, err := innerGetAsset(getValue(ctx, im, AssetKey(assetID)))
+	if err == nil && !exists {
+		err = fmt.Errorf("%w: %s", ErrAssetNotFound, assetID)
+	}
+	return m, err
+}
+
+// GetAssetControl returns the issuer state of [assetID]. It returns
+// [ErrAssetNotFound] if the asset does not exist.
+func GetAssetControl(
+	ctx context.Context,
+	im state.Immutable,
+	assetID ids.ID,
+) (AssetControl, error) {
+	_, _, c, exists, err := innerGetAsset(getValue(ctx, im, AssetKey(assetID)))
+	if err == nil && !exists {
+		err = fmt.Errorf("%w: %s", ErrAssetNotFound, assetID)
+	}
+	return c, err
+}
+
+// Used to serve RPC queries
+func GetAssetFromState(
+	ctx context.Context,
+	f ReadState,
+	assetID ids.ID,
+) (codec.Address, AssetMetadata, AssetControl, error) {
+	values, errs := f(ctx, [][]byte{AssetKey(assetID)})
+	owner, m, c, exists, err := innerGetAsset(values[0], errs[0])
+	if err == nil && !exists {
+		err = fmt.Errorf("%w: %s", ErrAssetNotFound, assetID)
+	}
+	return owner, m, c, err
+}
+
+// SetAssetMetadata replaces the metadata of an existing asset.
+func SetAssetMetadata(
+	ctx context.Context,
+	mu state.Mutable,
+	assetID ids.ID,
+	m AssetMetadata,
+) error {
+	if err := m.Verify(); err != nil {
+		return err
+	}
+	key := AssetKey(assetID)
+	owner, _, c, exists, err := innerGetAsset(getValue(ctx, mu, key))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrAssetNotFound, assetID)
+	}
+	v, err := packAsset(owner, m, c)
+	if err != nil {
+		return err
+	}
+	return insertValue(ctx, mu, key, v)
+}
+
+// SetAssetFrozen sets whether [assetID] is frozen, keeping the rest of the
+// asset.
+func SetAssetFrozen(
+	ctx context.Context,
+	mu state.Mutable,
+	assetID ids.ID,
+	frozen bool,
+) error {
+	key := AssetKey(assetID)
+	owner, m, c, exists, err := innerGetAsset(getValue(ctx, mu, key))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrAssetNotFound, assetID)
+	}
+	c.Frozen = frozen
+	v, err := packAsset(owner, m, c)
+	if err != nil {
+		return err
+	}
+	return insertValue(ctx, mu, key, v)
+}
+
+// SetAssetRoyalty sets the royalty of [assetID], keeping the rest of the
+// asset. A [basisPoints] of zero removes the royalty.
+func SetAssetRoyalty(
+	ctx context.Context,
+	mu state.Mutable,
+	assetID ids.ID,
+	basisPoints uint16,
+	payee codec.Address,
+) error {
+	key := AssetKey(assetID)
+	owner, m, c, exists, err := innerGetAsset(getValue(ctx, mu, key))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrAssetNotFound, assetID)
+	}
+	c.RoyaltyBasisPoints, c.RoyaltyPayee = basisPoints, payee
+	if basisPoints == 0 {
+		c.RoyaltyPayee = codec.EmptyAddress
+	}
+	v, err := packAsset(owner, m, c)
+	if err != nil {
+		return err
+	}
+	return insertValue(ctx, mu, key, v)
+}
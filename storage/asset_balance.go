@@ -0,0 +1,142 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// Fungible asset balances mirror native balances in [BalanceKey], with the
+// asset ID appended to the address.
+
+// [assetBalancePrefix] + [address] + [assetID]
+func AssetBalanceKey(addr codec.Address, assetID ids.ID) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+ids.IDLen+consts.Uint16Len)
+	k[0] = assetBalancePrefix
+	copy(k[1:], addr[:])
+	copy(k[1+codec.AddressLen:], assetID[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen+ids.IDLen:], AssetBalanceChunks)
+	return
+}
+
+// ParseAssetBalanceKey is the inverse of [AssetBalanceKey].
+func ParseAssetBalanceKey(k []byte) (codec.Address, ids.ID, bool) {
+	if len(k) != 1+codec.AddressLen+ids.IDLen+consts.Uint16Len || k[0] != assetBalancePrefix {
+		return codec.EmptyAddress, ids.Empty, false
+	}
+	return codec.Address(k[1 : 1+codec.AddressLen]), ids.ID(k[1+codec.AddressLen : 1+codec.AddressLen+ids.IDLen]), true
+}
+
+func GetAssetBalance(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+	assetID ids.ID,
+) (uint64, error) {
+	_, bal, _, err := getAssetBalance(ctx, im, addr, assetID)
+	return bal, err
+}
+
+func getAssetBalance(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+	assetID ids.ID,
+) ([]byte, uint64, bool, error) {
+	k := AssetBalanceKey(addr, assetID)
+	bal, exists, err := innerGetBalance(getValue(ctx, im, k))
+	return k, bal, exists, err
+}
+
+// Used to serve RPC queries
+func GetAssetBalanceFromState(
+	ctx context.Context,
+	f ReadState,
+	addr codec.Address,
+	assetID ids.ID,
+) (uint64, error) {
+	values, errs := f(ctx, [][]byte{AssetBalanceKey(addr, assetID)})
+	bal, _, err := innerGetBalance(values[0], errs[0])
+	return bal, err
+}
+
+func SetAssetBalance(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	assetID ids.ID,
+	balance uint64,
+) error {
+	return setBalance(ctx, mu, AssetBalanceKey(addr, assetID), balance)
+}
+
+func AddAssetBalance(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	assetID ids.ID,
+	amount uint64,
+	create bool,
+) (uint64, error) {
+	key, bal, exists, err := getAssetBalance(ctx, mu, addr, assetID)
+	if err != nil {
+		return 0, err
+	}
+	if !exists && !create {
+		return 0, nil
+	}
+	nbal, err := smath.Add(bal, amount)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"%w: could not add asset balance (bal=%d, addr=%v, asset=%s, amount=%d)",
+			ErrInvalidBalance,
+			bal,
+			addr,
+			assetID,
+			amount,
+		)
+	}
+	return nbal, setBalance(ctx, mu, key, nbal)
+}
+
+func SubAssetBalance(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	assetID ids.ID,
+	amount uint64,
+) (uint64, error) {
+	key, bal, ok, err := getAssetBalance(ctx, mu, addr, assetID)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrInvalidAddress
+	}
+	nbal, err := smath.Sub(bal, amount)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"%w: could not subtract asset balance (bal=%d, addr=%v, asset=%s, amount=%d)",
+			ErrInvalidBalance,
+			bal,
+			addr,
+			assetID,
+			amount,
+		)
+	}
+	if nbal == 0 {
+		return 0, Delete(ctx, mu, key)
+	}
+	return nbal, setBalance(ctx, mu, key, nbal)
+}
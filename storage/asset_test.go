@@ -0,0 +1,52 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/keys"
+)
+
+func TestMaxSizeAsset(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	store := chaintest.NewInMemoryStore()
+	asset := ids.GenerateTestID()
+	owner := codectest.NewRandomAddress()
+	payee := codectest.NewRandomAddress()
+
+	// Every field at its largest still fits the chunks of the asset key.
+	m := AssetMetadata{
+		Name:        strings.Repeat("n", MaxAssetNameSize),
+		Symbol:      strings.Repeat("s", MaxAssetSymbolSize),
+		Decimals:    math.MaxUint8,
+		URI:         strings.Repeat("u", MaxAssetURISize),
+		TotalSupply: math.MaxUint64,
+	}
+	control := AssetControl{Minter: owner, Soulbound: true, Expiry: math.MaxInt64}
+	require.NoError(CreateAssetWithControl(ctx, store, asset, control, owner))
+	require.NoError(SetAssetMetadata(ctx, store, asset, m))
+	require.NoError(SetAssetRoyalty(ctx, store, asset, math.MaxUint16, payee))
+
+	v, err := store.GetValue(ctx, AssetKey(asset))
+	require.NoError(err)
+	require.Len(v, maxAssetValueSize)
+	require.True(keys.VerifyValue(AssetKey(asset), v))
+
+	gotOwner, gotMetadata, gotControl, err := ParseAsset(v)
+	require.NoError(err)
+	require.Equal(owner, gotOwner)
+	require.Equal(m, gotMetadata)
+	control.RoyaltyBasisPoints, control.RoyaltyPayee = math.MaxUint16, payee
+	require.Equal(control, gotControl)
+}
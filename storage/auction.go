@@ -0,0 +1,180 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// auctionAddressType is not an auth type, so no key can sign for an
+// [AuctionAddress].
+const auctionAddressType = 0xf8
+
+const auctionValueSize = 2*codec.AddressLen + 3*consts.Uint64Len
+
+// Auction is an English auction of the asset it is keyed by, which is held
+// at its [AuctionAddress] with the top bid until it is settled.
+type Auction struct {
+	Seller codec.Address `json:"seller"`
+	// End is the last timestamp, in milliseconds, at which bids are taken.
+	End int64 `json:"end"`
+	// MinBid is the least the first bid must offer.
+	MinBid uint64 `json:"minBid"`
+	// Bidder is [codec.EmptyAddress] until the first bid.
+	Bidder codec.Address `json:"bidder"`
+	Bid    uint64        `json:"bid"`
+}
+
+// AuctionAddress holds the asset and the top bid of the auction of
+// [assetID].
+func AuctionAddress(assetID ids.ID) codec.Address {
+	return codec.CreateAddress(auctionAddressType, assetID)
+}
+
+// IsAuctionAddress reports whether [addr] is the [AuctionAddress] of an
+// asset.
+func IsAuctionAddress(addr codec.Address) bool {
+	return addr[0] == auctionAddressType
+}
+
+// [auctionPrefix] + [assetID]
+//
+// Keys sort by asset ID, which is what [GetAuctions] scans.
+func AuctionKey(assetID ids.ID) (k []byte) {
+	k = make([]byte, 1+ids.IDLen+consts.Uint16Len)
+	k[0] = auctionPrefix
+	copy(k[1:], assetID[:])
+	binary.BigEndian.PutUint16(k[1+ids.IDLen:], AuctionChunks)
+	return
+}
+
+// GetAuction returns the auction of [assetID], if it is auctioned.
+func GetAuction(
+	ctx context.Context,
+	im state.Immutable,
+	assetID ids.ID,
+) (*Auction, bool, error) {
+	return innerGetAuction(getValue(ctx, im, AuctionKey(assetID)))
+}
+
+// Used to serve RPC queries
+func GetAuctionFromState(
+	ctx context.Context,
+	f ReadState,
+	assetID ids.ID,
+) (*Auction, bool, error) {
+	values, errs := f(ctx, [][]byte{AuctionKey(assetID)})
+	return innerGetAuction(values[0], errs[0])
+}
+
+func innerGetAuction(v []byte, err error) (*Auction, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	a, err := unpackAuction(v)
+	if err != nil {
+		return nil, false, err
+	}
+	return a, true, nil
+}
+
+func unpackAuction(v []byte) (*Auction, error) {
+	if len(v) != auctionValueSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidAuction, len(v))
+	}
+	a := &Auction{
+		End:    int64(binary.BigEndian.Uint64(v[codec.AddressLen:])),
+		MinBid: binary.BigEndian.Uint64(v[codec.AddressLen+consts.Uint64Len:]),
+		Bid:    binary.BigEndian.Uint64(v[2*codec.AddressLen+2*consts.Uint64Len:]),
+	}
+	copy(a.Seller[:], v)
+	copy(a.Bidder[:], v[codec.AddressLen+2*consts.Uint64Len:])
+	return a, nil
+}
+
+// SetAuction stores [a] as the auction of [assetID].
+func SetAuction(
+	ctx context.Context,
+	mu state.Mutable,
+	assetID ids.ID,
+	a *Auction,
+) error {
+	v := make([]byte, auctionValueSize)
+	copy(v, a.Seller[:])
+	binary.BigEndian.PutUint64(v[codec.AddressLen:], uint64(a.End))
+	binary.BigEndian.PutUint64(v[codec.AddressLen+consts.Uint64Len:], a.MinBid)
+	copy(v[codec.AddressLen+2*consts.Uint64Len:], a.Bidder[:])
+	binary.BigEndian.PutUint64(v[2*codec.AddressLen+2*consts.Uint64Len:], a.Bid)
+	return insertValue(ctx, mu, AuctionKey(assetID), v)
+}
+
+func DeleteAuction(
+	ctx context.Context,
+	mu state.Mutable,
+	assetID ids.ID,
+) error {
+	return Delete(ctx, mu, AuctionKey(assetID))
+}
+
+// Auctions iterates over unsettled auctions in asset ID order.
+type Auctions struct {
+	it      database.Iterator
+	auction *Auction
+	err     error
+}
+
+// GetAuctions returns an iterator over the unsettled auctions in [db],
+// starting at [start]. Pass [ids.Empty] to start from the first one.
+//
+// The iterator must be released once done.
+func GetAuctions(db database.Iteratee, start ids.ID) *Auctions {
+	prefix := []byte{auctionPrefix}
+	return &Auctions{
+		it: db.NewIteratorWithStartAndPrefix(append(prefix, start[:]...), prefix),
+	}
+}
+
+func (a *Auctions) Next() bool {
+	if a.err != nil || !a.it.Next() {
+		return false
+	}
+	a.auction, a.err = unpackAuction(a.it.Value())
+	return a.err == nil
+}
+
+// Asset returns the asset of the current auction. It is only valid after
+// Next returned true.
+func (a *Auctions) Asset() ids.ID {
+	return ids.ID(a.it.Key()[1:])
+}
+
+// Auction returns the current auction. It is only valid after Next
+// returned true.
+func (a *Auctions) Auction() *Auction {
+	return a.auction
+}
+
+func (a *Auctions) Error() error {
+	if a.err != nil {
+		return a.err
+	}
+	return a.it.Error()
+}
+
+func (a *Auctions) Release() {
+	a.it.Release()
+}
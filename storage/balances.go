@@ -0,0 +1,80 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// Balances iterates over the native balances of a state in address order.
+type Balances struct {
+	it      database.Iterator
+	addr    codec.Address
+	balance uint64
+	err     error
+}
+
+// IterateBalances returns an iterator over the native balances in [db],
+// starting at [start]. Pass [codec.EmptyAddress] to start from the first
+// one. Emptied balances, kept while they hold a governance snapshot, are
+// skipped.
+//
+// The iterator must be released once done.
+func IterateBalances(db database.Iteratee, start codec.Address) *Balances {
+	prefix := []byte{balancePrefix}
+	return &Balances{
+		it: db.NewIteratorWithStartAndPrefix(append(prefix, start[:]...), prefix),
+	}
+}
+
+func (b *Balances) Next() bool {
+	if b.err != nil {
+		return false
+	}
+	for b.it.Next() {
+		k := b.it.Key()
+		addr, ok := ParseBalanceKey(k)
+		if !ok {
+			b.err = fmt.Errorf("%w: %x", ErrInvalidBalance, k)
+			return false
+		}
+		balance, err := ParseBalance(b.it.Value())
+		if err != nil {
+			b.err = err
+			return false
+		}
+		if balance == 0 {
+			continue
+		}
+		b.addr, b.balance = addr, balance
+		return true
+	}
+	return false
+}
+
+// Address returns the current address. It is only valid after Next
+// returned true.
+func (b *Balances) Address() codec.Address {
+	return b.addr
+}
+
+// Balance returns the native balance of [Address].
+func (b *Balances) Balance() uint64 {
+	return b.balance
+}
+
+func (b *Balances) Error() error {
+	if b.err != nil {
+		return b.err
+	}
+	return b.it.Error()
+}
+
+func (b *Balances) Release() {
+	b.it.Release()
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const (
+	MaxClaimKeySize   = 64
+	MaxClaimValueSize = 256
+)
+
+// Claim is a value an address stores under a key of its own choosing, until
+// [Expiry].
+type Claim struct {
+	Value codec.Bytes `json:"value"`
+	// Expiry is the timestamp, in milliseconds, after which the claim reads
+	// as absent.
+	Expiry int64 `json:"expiry"`
+}
+
+// [claimPrefix] + [owner] + [key]
+func ClaimKey(owner codec.Address, key []byte) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+len(key)+consts.Uint16Len)
+	k[0] = claimPrefix
+	copy(k[1:], owner[:])
+	copy(k[1+codec.AddressLen:], key)
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen+len(key):], ClaimChunks)
+	return
+}
+
+// GetClaim returns the claim [owner] stored under [key], expired or not.
+func GetClaim(
+	ctx context.Context,
+	im state.Immutable,
+	owner codec.Address,
+	key []byte,
+) (*Claim, bool, error) {
+	return innerGetClaim(getValue(ctx, im, ClaimKey(owner, key)))
+}
+
+// Used to serve RPC queries
+func GetClaimFromState(
+	ctx context.Context,
+	f ReadState,
+	owner codec.Address,
+	key []byte,
+) (*Claim, bool, error) {
+	values, errs := f(ctx, [][]byte{ClaimKey(owner, key)})
+	return innerGetClaim(values[0], errs[0])
+}
+
+func innerGetClaim(v []byte, err error) (*Claim, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(v) < consts.Int64Len || len(v) > consts.Int64Len+MaxClaimValueSize {
+		return nil, false, fmt.Errorf("%w: %d bytes", ErrInvalidClaim, len(v))
+	}
+	return &Claim{
+		Expiry: int64(binary.BigEndian.Uint64(v)),
+		Value:  v[consts.Int64Len:],
+	}, true, nil
+}
+
+func SetClaim(
+	ctx context.Context,
+	mu state.Mutable,
+	owner codec.Address,
+	key []byte,
+	c *Claim,
+) error {
+	if len(key) == 0 || len(key) > MaxClaimKeySize {
+		return fmt.Errorf("%w: key is %d bytes", ErrInvalidClaim, len(key))
+	}
+	if len(c.Value) > MaxClaimValueSize {
+		return fmt.Errorf("%w: value is %d bytes", ErrInvalidClaim, len(c.Value))
+	}
+	v := make([]byte, consts.Int64Len, consts.Int64Len+len(c.Value))
+	binary.BigEndian.PutUint64(v, uint64(c.Expiry))
+	v = append(v, c.Value...)
+	return insertValue(ctx, mu, ClaimKey(owner, key), v)
+}
+
+func DeleteClaim(
+	ctx context.Context,
+	mu state.Mutable,
+	owner codec.Address,
+	key []byte,
+) error {
+	return Delete(ctx, mu, ClaimKey(owner, key))
+}
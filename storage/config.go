@@ -0,0 +1,109 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const (
+	MaxConfigKeySize   = 32
+	MaxConfigValueSize = 64
+)
+
+// [configPrefix] + [key]
+func ConfigKey(key string) (k []byte) {
+	k = make([]byte, 1+len(key)+consts.Uint16Len)
+	k[0] = configPrefix
+	copy(k[1:], key)
+	binary.BigEndian.PutUint16(k[1+len(key):], ConfigChunks)
+	return
+}
+
+// GetConfig returns the config value governance set for [key], if any.
+// Values are encoded by the registry of the actions package.
+func GetConfig(
+	ctx context.Context,
+	im state.Immutable,
+	key string,
+) ([]byte, bool, error) {
+	return innerGetConfig(getValue(ctx, im, ConfigKey(key)))
+}
+
+// Used to serve RPC queries
+func GetConfigFromState(
+	ctx context.Context,
+	f ReadState,
+	key string,
+) ([]byte, bool, error) {
+	values, errs := f(ctx, [][]byte{ConfigKey(key)})
+	return innerGetConfig(values[0], errs[0])
+}
+
+func innerGetConfig(v []byte, err error) ([]byte, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return v, true, nil
+}
+
+// SetConfigValue sets the config value of [key]. It is the one update path
+// of the registry, taken when a governance proposal passes.
+func SetConfigValue(
+	ctx context.Context,
+	mu state.Mutable,
+	key string,
+	value []byte,
+) error {
+	switch {
+	case len(key) == 0 || len(key) > MaxConfigKeySize:
+		return fmt.Errorf("%w: key is %d bytes", ErrInvalidConfig, len(key))
+	case len(value) == 0 || len(value) > MaxConfigValueSize:
+		return fmt.Errorf("%w: value is %d bytes", ErrInvalidConfig, len(value))
+	}
+	return insertValue(ctx, mu, ConfigKey(key), value)
+}
+
+// GetConfigUint64 returns the config value of [key] as a uint64, if set.
+func GetConfigUint64(
+	ctx context.Context,
+	im state.Immutable,
+	key string,
+) (uint64, bool, error) {
+	v, exists, err := GetConfig(ctx, im, key)
+	if err != nil || !exists {
+		return 0, false, err
+	}
+	n, err := DecodeConfigUint64(v)
+	return n, err == nil, err
+}
+
+// SetConfigUint64 sets the config value of [key] to [value].
+func SetConfigUint64(
+	ctx context.Context,
+	mu state.Mutable,
+	key string,
+	value uint64,
+) error {
+	return SetConfigValue(ctx, mu, key, binary.BigEndian.AppendUint64(nil, value))
+}
+
+// DecodeConfigUint64 decodes a config value set by [SetConfigUint64].
+func DecodeConfigUint64(v []byte) (uint64, error) {
+	if len(v) != consts.Uint64Len {
+		return 0, fmt.Errorf("%w: %d bytes is not a uint64", ErrInvalidConfig, len(v))
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
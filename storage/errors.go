@@ -6,6 +6,39 @@ package storage
 import "errors"
 
 var (
-	ErrInvalidAddress = errors.New("invalid address")
-	ErrInvalidBalance = errors.New("invalid balance")
+	ErrInvalidAddress            = errors.New("invalid address")
+	ErrInvalidBalance            = errors.New("invalid balance")
+	ErrStaleState                = errors.New("state is behind requested height")
+	ErrAssetExists               = errors.New("asset already exists")
+	ErrAssetBurned               = errors.New("asset was burned")
+	ErrAssetNotFound             = errors.New("asset not found")
+	ErrAssetMetadataTooLarge     = errors.New("asset metadata is too large")
+	ErrInvalidAsset              = errors.New("invalid asset")
+	ErrInvalidNotificationPrefs  = errors.New("invalid notification prefs")
+	ErrInsufficientAllowance     = errors.New("insufficient allowance")
+	ErrInvalidCouncil            = errors.New("invalid council")
+	ErrInvalidTreasuryProposal   = errors.New("invalid treasury proposal")
+	ErrInvalidTransferHook       = errors.New("invalid transfer hook")
+	ErrInvalidSwap               = errors.New("invalid swap")
+	ErrInvalidVesting            = errors.New("invalid vesting")
+	ErrInvalidEscrow             = errors.New("invalid escrow")
+	ErrInvalidOrder              = errors.New("invalid order")
+	ErrInvalidPool               = errors.New("invalid pool")
+	ErrInvalidStake              = errors.New("invalid stake")
+	ErrInvalidGovernanceProposal = errors.New("invalid governance proposal")
+	ErrInvalidMultisig           = errors.New("invalid multisig")
+	ErrInvalidClaim              = errors.New("invalid claim")
+	ErrInvalidSession            = errors.New("invalid session")
+	ErrInvalidReceipt            = errors.New("invalid receipt")
+	ErrInvalidStablecoin         = errors.New("invalid stablecoin")
+	ErrInvalidHalt               = errors.New("invalid halt")
+	ErrInvalidConfig             = errors.New("invalid config value")
+	ErrInvalidSmartAccount       = errors.New("invalid smart account")
+	ErrInvalidLeaderboard        = errors.New("invalid leaderboard")
+	ErrInvalidLocker             = errors.New("invalid locker")
+	ErrInvalidAuction            = errors.New("invalid auction")
+	ErrChainHalted               = errors.New("chain is halted")
+	ErrSequenceOverflow          = errors.New("sequence overflow")
+	ErrInvalidKey                = errors.New("invalid key")
+	ErrNotArchived               = errors.New("height not archived")
 )
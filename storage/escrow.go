@@ -0,0 +1,110 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+const escrowValueSize = 2*codec.AddressLen + consts.Uint64Len + consts.Int64Len
+
+// Escrow is an amount of native tokens locked by [Payer] until it releases
+// them to [Counterparty] or the deadline passes.
+type Escrow struct {
+	Payer        codec.Address `json:"payer"`
+	Counterparty codec.Address `json:"counterparty"`
+	Amount       uint64        `json:"amount"`
+	// Deadline is the last timestamp, in milliseconds, at which the payer
+	// can release the escrow.
+	Deadline int64 `json:"deadline"`
+}
+
+// EscrowID is the ID of the escrow [payer] opens with [nonce].
+func EscrowID(payer codec.Address, nonce uint64) ids.ID {
+	b := make([]byte, codec.AddressLen+consts.Uint64Len)
+	copy(b, payer[:])
+	binary.BigEndian.PutUint64(b[codec.AddressLen:], nonce)
+	return utils.ToID(b)
+}
+
+// [escrowPrefix] + [escrowID]
+func EscrowKey(escrowID ids.ID) (k []byte) {
+	k = make([]byte, 1+ids.IDLen+consts.Uint16Len)
+	k[0] = escrowPrefix
+	copy(k[1:], escrowID[:])
+	binary.BigEndian.PutUint16(k[1+ids.IDLen:], EscrowChunks)
+	return
+}
+
+// GetEscrow returns the escrow stored under [escrowID], if any.
+func GetEscrow(
+	ctx context.Context,
+	im state.Immutable,
+	escrowID ids.ID,
+) (*Escrow, bool, error) {
+	return innerGetEscrow(getValue(ctx, im, EscrowKey(escrowID)))
+}
+
+// Used to serve RPC queries
+func GetEscrowFromState(
+	ctx context.Context,
+	f ReadState,
+	escrowID ids.ID,
+) (*Escrow, bool, error) {
+	values, errs := f(ctx, [][]byte{EscrowKey(escrowID)})
+	return innerGetEscrow(values[0], errs[0])
+}
+
+func innerGetEscrow(v []byte, err error) (*Escrow, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(v) != escrowValueSize {
+		return nil, false, fmt.Errorf("%w: %d bytes", ErrInvalidEscrow, len(v))
+	}
+	e := &Escrow{
+		Amount:   binary.BigEndian.Uint64(v[2*codec.AddressLen:]),
+		Deadline: int64(binary.BigEndian.Uint64(v[2*codec.AddressLen+consts.Uint64Len:])),
+	}
+	copy(e.Payer[:], v)
+	copy(e.Counterparty[:], v[codec.AddressLen:])
+	return e, true, nil
+}
+
+// SetEscrow stores [e] under [escrowID].
+func SetEscrow(
+	ctx context.Context,
+	mu state.Mutable,
+	escrowID ids.ID,
+	e *Escrow,
+) error {
+	v := make([]byte, escrowValueSize)
+	copy(v, e.Payer[:])
+	copy(v[codec.AddressLen:], e.Counterparty[:])
+	binary.BigEndian.PutUint64(v[2*codec.AddressLen:], e.Amount)
+	binary.BigEndian.PutUint64(v[2*codec.AddressLen+consts.Uint64Len:], uint64(e.Deadline))
+	return insertValue(ctx, mu, EscrowKey(escrowID), v)
+}
+
+func DeleteEscrow(
+	ctx context.Context,
+	mu state.Mutable,
+	escrowID ids.ID,
+) error {
+	return Delete(ctx, mu, EscrowKey(escrowID))
+}
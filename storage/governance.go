@@ -0,0 +1,358 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const (
+	MaxProposalTextSize  = 256
+	MaxParameterChanges  = 4
+	MaxParameterNameSize = MaxConfigKeySize
+)
+
+const (
+	governanceActive   = 0x0
+	governanceProposal = 0x1
+	governanceVote     = 0x2
+
+	activeProposalValueSize = ids.IDLen + 2*consts.Uint64Len
+	governanceVoteValueSize = 1 + consts.Uint64Len
+)
+
+// ParameterChange sets a config value: [Value] for numeric parameters, or
+// [Address] for those that name an address.
+type ParameterChange struct {
+	Parameter string        `serialize:"true" json:"parameter"`
+	Value     uint64        `serialize:"true" json:"value"`
+	Address   codec.Address `serialize:"true" json:"address"`
+}
+
+// GovernanceProposal is a proposal voted on with native balances.
+type GovernanceProposal struct {
+	Proposer codec.Address `json:"proposer"`
+	// Deposit is the native balance the proposer bonded.
+	Deposit uint64 `json:"deposit"`
+	// SnapshotHeight is the height of the block that created the proposal,
+	// and votes are weighted by the balances held when it did.
+	SnapshotHeight uint64 `json:"snapshotHeight"`
+	// VotingEnd is the last height at which votes are accepted.
+	VotingEnd uint64            `json:"votingEnd"`
+	Yes       uint64            `json:"yes"`
+	No        uint64            `json:"no"`
+	Text      string            `json:"text"`
+	Changes   []ParameterChange `json:"changes"`
+	// Executed is set once the proposal is closed, and Passed if its changes
+	// were applied then.
+	Executed bool `json:"executed"`
+	Passed   bool `json:"passed"`
+}
+
+// ActiveProposal is the proposal whose balance snapshot is being kept.
+type ActiveProposal struct {
+	ProposalID     ids.ID `json:"proposalId"`
+	SnapshotHeight uint64 `json:"snapshotHeight"`
+	VotingEnd      uint64 `json:"votingEnd"`
+}
+
+// GovernanceVote is the vote cast by an address on a proposal.
+type GovernanceVote struct {
+	Support bool   `json:"support"`
+	Weight  uint64 `json:"weight"`
+}
+
+// [governancePrefix] + [governanceActive]
+func ActiveProposalKey() (k []byte) {
+	k = make([]byte, 2+consts.Uint16Len)
+	k[0] = governancePrefix
+	k[1] = governanceActive
+	binary.BigEndian.PutUint16(k[2:], ActiveProposalChunks)
+	return
+}
+
+// [governancePrefix] + [governanceProposal] + [proposalID]
+func GovernanceProposalKey(proposalID ids.ID) (k []byte) {
+	k = make([]byte, 2+ids.IDLen+consts.Uint16Len)
+	k[0] = governancePrefix
+	k[1] = governanceProposal
+	copy(k[2:], proposalID[:])
+	binary.BigEndian.PutUint16(k[2+ids.IDLen:], GovernanceProposalChunks)
+	return
+}
+
+// [governancePrefix] + [governanceVote] + [proposalID] + [voter]
+func GovernanceVoteKey(proposalID ids.ID, voter codec.Address) (k []byte) {
+	k = make([]byte, 2+ids.IDLen+codec.AddressLen+consts.Uint16Len)
+	k[0] = governancePrefix
+	k[1] = governanceVote
+	copy(k[2:], proposalID[:])
+	copy(k[2+ids.IDLen:], voter[:])
+	binary.BigEndian.PutUint16(k[2+ids.IDLen+codec.AddressLen:], GovernanceVoteChunks)
+	return
+}
+
+// GetActiveProposal returns the active proposal, if any. It stays active
+// after voting ends, until it is executed or replaced.
+func GetActiveProposal(
+	ctx context.Context,
+	im state.Immutable,
+) (*ActiveProposal, bool, error) {
+	return innerGetActiveProposal(getValue(ctx, im, ActiveProposalKey()))
+}
+
+// Used to serve RPC queries
+func GetActiveProposalFromState(
+	ctx context.Context,
+	f ReadState,
+) (*ActiveProposal, bool, error) {
+	values, errs := f(ctx, [][]byte{ActiveProposalKey()})
+	return innerGetActiveProposal(values[0], errs[0])
+}
+
+func innerGetActiveProposal(v []byte, err error) (*ActiveProposal, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(v) != activeProposalValueSize {
+		return nil, false, fmt.Errorf("%w: active proposal is %d bytes", ErrInvalidGovernanceProposal, len(v))
+	}
+	return &ActiveProposal{
+		ProposalID:     ids.ID(v[:ids.IDLen]),
+		SnapshotHeight: binary.BigEndian.Uint64(v[ids.IDLen:]),
+		VotingEnd:      binary.BigEndian.Uint64(v[ids.IDLen+consts.Uint64Len:]),
+	}, true, nil
+}
+
+// SetActiveProposal starts keeping the balance snapshot of [a].
+func SetActiveProposal(
+	ctx context.Context,
+	mu state.Mutable,
+	a *ActiveProposal,
+) error {
+	v := make([]byte, activeProposalValueSize)
+	copy(v, a.ProposalID[:])
+	binary.BigEndian.PutUint64(v[ids.IDLen:], a.SnapshotHeight)
+	binary.BigEndian.PutUint64(v[ids.IDLen+consts.Uint64Len:], a.VotingEnd)
+	return insertValue(ctx, mu, ActiveProposalKey(), v)
+}
+
+func DeleteActiveProposal(
+	ctx context.Context,
+	mu state.Mutable,
+) error {
+	return Delete(ctx, mu, ActiveProposalKey())
+}
+
+// GetGovernanceProposal returns the proposal stored under [proposalID], if
+// any.
+func GetGovernanceProposal(
+	ctx context.Context,
+	im state.Immutable,
+	proposalID ids.ID,
+) (*GovernanceProposal, bool, error) {
+	return innerGetGovernanceProposal(getValue(ctx, im, GovernanceProposalKey(proposalID)))
+}
+
+// Used to serve RPC queries
+func GetGovernanceProposalFromState(
+	ctx context.Context,
+	f ReadState,
+	proposalID ids.ID,
+) (*GovernanceProposal, bool, error) {
+	values, errs := f(ctx, [][]byte{GovernanceProposalKey(proposalID)})
+	return innerGetGovernanceProposal(values[0], errs[0])
+}
+
+func innerGetGovernanceProposal(v []byte, err error) (*GovernanceProposal, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	p := codec.NewReader(v, len(v))
+	g := &GovernanceProposal{}
+	proposer := g.Proposer[:]
+	p.UnpackFixedBytes(codec.AddressLen, &proposer)
+	g.Deposit = p.UnpackUint64(false)
+	g.SnapshotHeight = p.UnpackUint64(false)
+	g.VotingEnd = p.UnpackUint64(false)
+	g.Yes = p.UnpackUint64(false)
+	g.No = p.UnpackUint64(false)
+	g.Text = p.UnpackString(false)
+	g.Changes = make([]ParameterChange, p.UnpackByte())
+	for i := range g.Changes {
+		g.Changes[i].Parameter = p.UnpackString(true)
+		g.Changes[i].Value = p.UnpackUint64(false)
+	}
+	g.Executed = p.UnpackBool()
+	g.Passed = p.UnpackBool()
+	// Addresses follow only in proposals that change one.
+	if !p.Empty() {
+		for i := range g.Changes {
+			addr := g.Changes[i].Address[:]
+			p.UnpackFixedBytes(codec.AddressLen, &addr)
+		}
+	}
+	if err := p.Err(); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrInvalidGovernanceProposal, err)
+	}
+	if !p.Empty() {
+		return nil, false, fmt.Errorf("%w: trailing bytes", ErrInvalidGovernanceProposal)
+	}
+	return g, true, nil
+}
+
+// SetGovernanceProposal stores [g] under [proposalID].
+func SetGovernanceProposal(
+	ctx context.Context,
+	mu state.Mutable,
+	proposalID ids.ID,
+	g *GovernanceProposal,
+) error {
+	switch {
+	case len(g.Text) > MaxProposalTextSize:
+		return fmt.Errorf("%w: text is %d bytes", ErrInvalidGovernanceProposal, len(g.Text))
+	case len(g.Changes) > MaxParameterChanges:
+		return fmt.Errorf("%w: more than %d changes", ErrInvalidGovernanceProposal, MaxParameterChanges)
+	}
+	size := codec.AddressLen + 5*consts.Uint64Len + consts.Uint16Len + len(g.Text) + 3
+	for _, c := range g.Changes {
+		if len(c.Parameter) > MaxParameterNameSize {
+			return fmt.Errorf("%w: parameter name is %d bytes", ErrInvalidGovernanceProposal, len(c.Parameter))
+		}
+		size += consts.Uint16Len + len(c.Parameter) + consts.Uint64Len
+	}
+	addresses := slices.ContainsFunc(g.Changes, func(c ParameterChange) bool {
+		return c.Address != codec.EmptyAddress
+	})
+	if addresses {
+		size += len(g.Changes) * codec.AddressLen
+	}
+	p := codec.NewWriter(size, size)
+	p.PackAddress(g.Proposer)
+	p.PackUint64(g.Deposit)
+	p.PackUint64(g.SnapshotHeight)
+	p.PackUint64(g.VotingEnd)
+	p.PackUint64(g.Yes)
+	p.PackUint64(g.No)
+	p.PackString(g.Text)
+	p.PackByte(uint8(len(g.Changes)))
+	for _, c := range g.Changes {
+		p.PackString(c.Parameter)
+		p.PackUint64(c.Value)
+	}
+	p.PackBool(g.Executed)
+	p.PackBool(g.Passed)
+	if addresses {
+		for _, c := range g.Changes {
+			p.PackAddress(c.Address)
+		}
+	}
+	if err := p.Err(); err != nil {
+		return err
+	}
+	v := p.Bytes()
+	if chunks, _ := keys.NumChunks(v); chunks > GovernanceProposalChunks {
+		return fmt.Errorf("%w: proposal is %d bytes", ErrInvalidGovernanceProposal, len(v))
+	}
+	return insertValue(ctx, mu, GovernanceProposalKey(proposalID), v)
+}
+
+func DeleteGovernanceProposal(
+	ctx context.Context,
+	mu state.Mutable,
+	proposalID ids.ID,
+) error {
+	return Delete(ctx, mu, GovernanceProposalKey(proposalID))
+}
+
+// GetGovernanceVote returns the vote of [voter] on [proposalID], if any.
+func GetGovernanceVote(
+	ctx context.Context,
+	im state.Immutable,
+	proposalID ids.ID,
+	voter codec.Address,
+) (*GovernanceVote, bool, error) {
+	return innerGetGovernanceVote(getValue(ctx, im, GovernanceVoteKey(proposalID, voter)))
+}
+
+// Used to serve RPC queries
+func GetGovernanceVoteFromState(
+	ctx context.Context,
+	f ReadState,
+	proposalID ids.ID,
+	voter codec.Address,
+) (*GovernanceVote, bool, error) {
+	values, errs := f(ctx, [][]byte{GovernanceVoteKey(proposalID, voter)})
+	return innerGetGovernanceVote(values[0], errs[0])
+}
+
+func innerGetGovernanceVote(v []byte, err error) (*GovernanceVote, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(v) != governanceVoteValueSize {
+		return nil, false, fmt.Errorf("%w: vote is %d bytes", ErrInvalidGovernanceProposal, len(v))
+	}
+	return &GovernanceVote{
+		Support: v[0] == 1,
+		Weight:  binary.BigEndian.Uint64(v[1:]),
+	}, true, nil
+}
+
+func SetGovernanceVote(
+	ctx context.Context,
+	mu state.Mutable,
+	proposalID ids.ID,
+	voter codec.Address,
+	vote *GovernanceVote,
+) error {
+	v := make([]byte, governanceVoteValueSize)
+	if vote.Support {
+		v[0] = 1
+	}
+	binary.BigEndian.PutUint64(v[1:], vote.Weight)
+	return insertValue(ctx, mu, GovernanceVoteKey(proposalID, voter), v)
+}
+
+// GetSnapshotBalance returns the native balance [addr] held when the
+// proposal created at [snapshotHeight] was, provided that proposal has been
+// active since.
+//
+// Callers must declare [BalanceKey] of [addr] with [state.Read].
+func GetSnapshotBalance(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+	snapshotHeight uint64,
+) (uint64, error) {
+	_, bal, _, err := getBalance(ctx, im, addr)
+	if err != nil {
+		return 0, err
+	}
+	if bal.snapshotHeight == snapshotHeight {
+		return bal.snapshot, nil
+	}
+	return bal.balance, nil
+}
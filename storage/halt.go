@@ -0,0 +1,199 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const (
+	haltState    = 0x0
+	haltCouncil  = 0x1
+	haltProposal = 0x2
+
+	haltValueSize         = ids.IDLen + consts.Int64Len + consts.Uint16Len
+	haltProposalValueSize = consts.Uint16Len
+)
+
+// Halt is the state of a halted chain. While it is set, only governance
+// actions and those of the security council execute.
+type Halt struct {
+	// Incident identifies the incident the chain was halted for.
+	Incident ids.ID `json:"incident"`
+	// HaltedAt is the timestamp, in milliseconds, of the halt.
+	HaltedAt int64 `json:"haltedAt"`
+	// ResumeApprovals has bit i set once council member i approved resuming.
+	ResumeApprovals uint16 `json:"resumeApprovals"`
+}
+
+// [haltPrefix] + [haltState]
+func HaltKey() (k []byte) {
+	k = make([]byte, 2+consts.Uint16Len)
+	k[0] = haltPrefix
+	k[1] = haltState
+	binary.BigEndian.PutUint16(k[2:], HaltChunks)
+	return
+}
+
+// [haltPrefix] + [haltCouncil]
+func SecurityCouncilKey() (k []byte) {
+	k = make([]byte, 2+consts.Uint16Len)
+	k[0] = haltPrefix
+	k[1] = haltCouncil
+	binary.BigEndian.PutUint16(k[2:], SecurityCouncilChunks)
+	return
+}
+
+// [haltPrefix] + [haltProposal] + [incident]
+func HaltProposalKey(incident ids.ID) (k []byte) {
+	k = make([]byte, 2+ids.IDLen+consts.Uint16Len)
+	k[0] = haltPrefix
+	k[1] = haltProposal
+	copy(k[2:], incident[:])
+	binary.BigEndian.PutUint16(k[2+ids.IDLen:], HaltProposalChunks)
+	return
+}
+
+// GetHalt returns the halt of the chain, if it is halted.
+func GetHalt(
+	ctx context.Context,
+	im state.Immutable,
+) (*Halt, bool, error) {
+	return innerGetHalt(getValue(ctx, im, HaltKey()))
+}
+
+// Used to serve RPC queries
+func GetHaltFromState(
+	ctx context.Context,
+	f ReadState,
+) (*Halt, bool, error) {
+	values, errs := f(ctx, [][]byte{HaltKey()})
+	return innerGetHalt(values[0], errs[0])
+}
+
+func innerGetHalt(v []byte, err error) (*Halt, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(v) != haltValueSize {
+		return nil, false, fmt.Errorf("%w: %d bytes", ErrInvalidHalt, len(v))
+	}
+	return &Halt{
+		Incident:        ids.ID(v[:ids.IDLen]),
+		HaltedAt:        int64(binary.BigEndian.Uint64(v[ids.IDLen:])),
+		ResumeApprovals: binary.BigEndian.Uint16(v[ids.IDLen+consts.Int64Len:]),
+	}, true, nil
+}
+
+// SetHalt halts the chain, or updates the approvals to resume it.
+func SetHalt(
+	ctx context.Context,
+	mu state.Mutable,
+	h *Halt,
+) error {
+	v := make([]byte, 0, haltValueSize)
+	v = append(v, h.Incident[:]...)
+	v = binary.BigEndian.AppendUint64(v, uint64(h.HaltedAt))
+	v = binary.BigEndian.AppendUint16(v, h.ResumeApprovals)
+	return insertValue(ctx, mu, HaltKey(), v)
+}
+
+// DeleteHalt resumes the chain.
+func DeleteHalt(
+	ctx context.Context,
+	mu state.Mutable,
+) error {
+	return Delete(ctx, mu, HaltKey())
+}
+
+// CheckNotHalted returns [ErrChainHalted] if the chain is halted. Every
+// action but those of governance and the security council calls it first.
+//
+// Callers must declare [HaltKey] with [state.Read].
+func CheckNotHalted(
+	ctx context.Context,
+	im state.Immutable,
+) error {
+	halt, halted, err := GetHalt(ctx, im)
+	if err != nil || !halted {
+		return err
+	}
+	return fmt.Errorf("%w: incident %s", ErrChainHalted, halt.Incident)
+}
+
+// GetSecurityCouncil returns the security council, or nil if the chain has
+// none.
+func GetSecurityCouncil(
+	ctx context.Context,
+	im state.Immutable,
+) (*Council, error) {
+	return innerGetCouncil(getValue(ctx, im, SecurityCouncilKey()))
+}
+
+// Used to serve RPC queries
+func GetSecurityCouncilFromState(
+	ctx context.Context,
+	f ReadState,
+) (*Council, error) {
+	values, errs := f(ctx, [][]byte{SecurityCouncilKey()})
+	return innerGetCouncil(values[0], errs[0])
+}
+
+// SetSecurityCouncil sets the security council. It is only called from
+// genesis.
+func SetSecurityCouncil(
+	ctx context.Context,
+	mu state.Mutable,
+	c *Council,
+) error {
+	return setCouncil(ctx, mu, SecurityCouncilKey(), c)
+}
+
+// GetHaltProposal returns the approvals to halt the chain for [incident].
+func GetHaltProposal(
+	ctx context.Context,
+	im state.Immutable,
+	incident ids.ID,
+) (uint16, error) {
+	v, err := getValue(ctx, im, HaltProposalKey(incident))
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(v) != haltProposalValueSize {
+		return 0, fmt.Errorf("%w: %d bytes", ErrInvalidHalt, len(v))
+	}
+	return binary.BigEndian.Uint16(v), nil
+}
+
+func SetHaltProposal(
+	ctx context.Context,
+	mu state.Mutable,
+	incident ids.ID,
+	approvals uint16,
+) error {
+	return insertValue(ctx, mu, HaltProposalKey(incident), binary.BigEndian.AppendUint16(nil, approvals))
+}
+
+func DeleteHaltProposal(
+	ctx context.Context,
+	mu state.Mutable,
+	incident ids.ID,
+) error {
+	return Delete(ctx, mu, HaltProposalKey(incident))
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// Ordered index keys are laid out as
+//
+//	[prefix] + [order] + [id] + [chunks]
+//
+// where [order] is a big-endian uint64 (a timestamp or height). Fixed width
+// big-endian encoding makes lexicographic key order match numeric order, so
+// a range of orders maps onto a contiguous range of keys.
+
+// OrderedKey builds a secondary index key for [id] at [order] under [prefix].
+func OrderedKey(prefix []byte, order uint64, id []byte, chunks uint16) (k []byte) {
+	k = make([]byte, len(prefix)+consts.Uint64Len+len(id)+consts.Uint16Len)
+	copy(k, prefix)
+	binary.BigEndian.PutUint64(k[len(prefix):], order)
+	copy(k[len(prefix)+consts.Uint64Len:], id)
+	binary.BigEndian.PutUint16(k[len(k)-consts.Uint16Len:], chunks)
+	return
+}
+
+// ParseOrderedKey is the inverse of [OrderedKey].
+func ParseOrderedKey(prefix []byte, k []byte) (uint64, []byte, error) {
+	if len(k) < len(prefix)+consts.Uint64Len+consts.Uint16Len || !bytes.HasPrefix(k, prefix) {
+		return 0, nil, fmt.Errorf("%w: not an ordered key under %x", ErrInvalidKey, prefix)
+	}
+	order := binary.BigEndian.Uint64(k[len(prefix):])
+	id := k[len(prefix)+consts.Uint64Len : len(k)-consts.Uint16Len]
+	return order, id, nil
+}
+
+// ReverseOrder maps [order] so that larger values sort first. Indexing by
+// ReverseOrder(timestamp) lets a forward scan return the newest entries first.
+func ReverseOrder(order uint64) uint64 {
+	return math.MaxUint64 - order
+}
+
+// Bound is one end of an order range.
+type Bound struct {
+	Order     uint64
+	Exclusive bool
+}
+
+func Inclusive(order uint64) Bound { return Bound{Order: order} }
+
+func Exclusive(order uint64) Bound { return Bound{Order: order, Exclusive: true} }
+
+// KeyRange is a half-open key range: [Start] is inclusive and [End] is
+// exclusive. A nil [End] is unbounded.
+type KeyRange struct {
+	Start []byte
+	End   []byte
+}
+
+// Empty reports whether no key can fall in [r].
+func (r KeyRange) Empty() bool {
+	return r.End != nil && bytes.Compare(r.Start, r.End) >= 0
+}
+
+func (r KeyRange) Contains(k []byte) bool {
+	return bytes.Compare(k, r.Start) >= 0 && (r.End == nil || bytes.Compare(k, r.End) < 0)
+}
+
+// OrderedRange returns the keys under [prefix] whose order lies between
+// [from] and [to].
+func OrderedRange(prefix []byte, from Bound, to Bound) KeyRange {
+	var r KeyRange
+	switch {
+	case !from.Exclusive:
+		r.Start = orderPrefix(prefix, from.Order)
+	case from.Order == math.MaxUint64:
+		// Nothing sorts after the last order under [prefix].
+		r.Start = prefixEnd(prefix)
+	default:
+		r.Start = orderPrefix(prefix, from.Order+1)
+	}
+	switch {
+	case to.Exclusive:
+		r.End = orderPrefix(prefix, to.Order)
+	case to.Order == math.MaxUint64:
+		r.End = prefixEnd(prefix)
+	default:
+		r.End = orderPrefix(prefix, to.Order+1)
+	}
+	if r.Start == nil {
+		// [prefix] is all 0xff: there is no key after it.
+		r.Start, r.End = []byte{}, []byte{}
+	}
+	return r
+}
+
+func orderPrefix(prefix []byte, order uint64) []byte {
+	return binary.BigEndian.AppendUint64(bytes.Clone(prefix), order)
+}
+
+// prefixEnd returns the smallest key greater than every key starting with
+// [prefix], or nil if there is none.
+func prefixEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
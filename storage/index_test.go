@@ -0,0 +1,133 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedKey(t *testing.T) {
+	require := require.New(t)
+	prefix := []byte{0x1, 0x2}
+	id := []byte("id")
+
+	k := OrderedKey(prefix, 7, id, 3)
+	require.Equal([]byte{0x1, 0x2, 0, 0, 0, 0, 0, 0, 0, 7, 'i', 'd', 0, 3}, k)
+	order, parsed, err := ParseOrderedKey(prefix, k)
+	require.NoError(err)
+	require.Equal(uint64(7), order)
+	require.Equal(id, parsed)
+
+	// Keys sort by order, whatever their IDs.
+	require.Negative(bytes.Compare(OrderedKey(prefix, 255, []byte{0xff}, 0), OrderedKey(prefix, 256, nil, 0)))
+
+	_, _, err = ParseOrderedKey([]byte{0x3}, k)
+	require.ErrorIs(err, ErrInvalidKey)
+	_, _, err = ParseOrderedKey(prefix, k[:len(prefix)+4])
+	require.ErrorIs(err, ErrInvalidKey)
+}
+
+func TestOrderedRange(t *testing.T) {
+	prefix := []byte{0x1}
+	key := func(order uint64) []byte {
+		return OrderedKey(prefix, order, []byte("id"), 0)
+	}
+
+	tests := []struct {
+		name     string
+		from, to Bound
+		in       []uint64
+		out      []uint64
+		empty    bool
+	}{
+		{
+			name: "Inclusive",
+			from: Inclusive(2),
+			to:   Inclusive(4),
+			in:   []uint64{2, 3, 4},
+			out:  []uint64{0, 1, 5},
+		},
+		{
+			name: "Exclusive",
+			from: Exclusive(2),
+			to:   Exclusive(4),
+			in:   []uint64{3},
+			out:  []uint64{2, 4},
+		},
+		{
+			name:  "ExclusiveSameOrder",
+			from:  Inclusive(2),
+			to:    Exclusive(2),
+			out:   []uint64{2},
+			empty: true,
+		},
+		{
+			name: "InclusiveMax",
+			from: Inclusive(math.MaxUint64 - 1),
+			to:   Inclusive(math.MaxUint64),
+			in:   []uint64{math.MaxUint64 - 1, math.MaxUint64},
+			out:  []uint64{math.MaxUint64 - 2},
+		},
+		{
+			name:  "ExclusiveMax",
+			from:  Exclusive(math.MaxUint64),
+			to:    Inclusive(math.MaxUint64),
+			out:   []uint64{math.MaxUint64},
+			empty: true,
+		},
+		{
+			name: "All",
+			from: Inclusive(0),
+			to:   Inclusive(math.MaxUint64),
+			in:   []uint64{0, math.MaxUint64},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			r := OrderedRange(prefix, tt.from, tt.to)
+			require.Equal(tt.empty, r.Empty())
+			for _, order := range tt.in {
+				require.True(r.Contains(key(order)), "order %d", order)
+			}
+			for _, order := range tt.out {
+				require.False(r.Contains(key(order)), "order %d", order)
+			}
+			// Nothing under another prefix is in range.
+			require.False(r.Contains([]byte{0x2}))
+		})
+	}
+}
+
+func TestPrefixEnd(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   []byte
+		expected []byte
+	}{
+		{name: "Increment", prefix: []byte{0x1, 0x2}, expected: []byte{0x1, 0x3}},
+		{name: "Carry", prefix: []byte{0x1, 0xff}, expected: []byte{0x2}},
+		{name: "AllFF", prefix: []byte{0xff, 0xff}},
+		{name: "Empty", prefix: []byte{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, prefixEnd(tt.prefix))
+		})
+	}
+
+	// Under an all-0xff prefix, a range starting after the last order is
+	// empty rather than unbounded, and one ending at it has no end.
+	last := OrderedKey([]byte{0xff}, math.MaxUint64, nil, 0)
+	r := OrderedRange([]byte{0xff}, Exclusive(math.MaxUint64), Inclusive(math.MaxUint64))
+	require.True(t, r.Empty())
+	require.False(t, r.Contains(last))
+	r = OrderedRange([]byte{0xff}, Inclusive(0), Inclusive(math.MaxUint64))
+	require.Nil(t, r.End)
+	require.True(t, r.Contains(last))
+}
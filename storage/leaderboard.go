@@ -0,0 +1,187 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// MaxLeaderboardSize bounds the entries a leaderboard keeps.
+const MaxLeaderboardSize = 16
+
+const (
+	leaderboardHeaderSize = codec.AddressLen + consts.ByteLen + consts.Int64Len + consts.ByteLen
+	leaderboardEntrySize  = codec.AddressLen + consts.Uint64Len + consts.Int64Len
+)
+
+// Leaderboard keeps the best [Size] scores submitted until [Expiry], one
+// per player.
+type Leaderboard struct {
+	Creator codec.Address `json:"creator"`
+	Size    uint8         `json:"size"`
+	// Expiry is the last timestamp, in milliseconds, at which scores can be
+	// submitted.
+	Expiry int64 `json:"expiry"`
+	// Entries are ranked best first.
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+// LeaderboardEntry is the best score of [Player] on a leaderboard.
+type LeaderboardEntry struct {
+	Player codec.Address `json:"player"`
+	Score  uint64        `json:"score"`
+	// Timestamp is when [Score] was submitted, in milliseconds.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// compareEntries orders entries by rank. Higher scores rank first, then
+// the score submitted first, then the lower player address, so every node
+// ranks ties alike.
+func compareEntries(a, b LeaderboardEntry) int {
+	switch {
+	case a.Score != b.Score:
+		if a.Score > b.Score {
+			return -1
+		}
+		return 1
+	case a.Timestamp != b.Timestamp:
+		if a.Timestamp < b.Timestamp {
+			return -1
+		}
+		return 1
+	default:
+		return bytes.Compare(a.Player[:], b.Player[:])
+	}
+}
+
+// Submit records [score] by [player] at [timestamp] and returns the rank
+// of the player's entry, from 1, or 0 if it is not on the board. A score
+// no higher than the player's entry, or ranking below every entry of a
+// full board, leaves the board unchanged; [changed] reports whether it
+// changed.
+func (l *Leaderboard) Submit(player codec.Address, score uint64, timestamp int64) (rank int, changed bool) {
+	current := slices.IndexFunc(l.Entries, func(e LeaderboardEntry) bool {
+		return e.Player == player
+	})
+	if current >= 0 {
+		if score <= l.Entries[current].Score {
+			return current + 1, false
+		}
+		l.Entries = slices.Delete(l.Entries, current, current+1)
+	}
+	entry := LeaderboardEntry{Player: player, Score: score, Timestamp: timestamp}
+	i, _ := slices.BinarySearchFunc(l.Entries, entry, compareEntries)
+	if i >= int(l.Size) {
+		return 0, false
+	}
+	l.Entries = slices.Insert(l.Entries, i, entry)
+	if len(l.Entries) > int(l.Size) {
+		l.Entries = l.Entries[:l.Size]
+	}
+	return i + 1, true
+}
+
+// LeaderboardID is the ID of the leaderboard [creator] creates with
+// [nonce].
+func LeaderboardID(creator codec.Address, nonce uint64) ids.ID {
+	b := make([]byte, codec.AddressLen+consts.Uint64Len)
+	copy(b, creator[:])
+	binary.BigEndian.PutUint64(b[codec.AddressLen:], nonce)
+	return utils.ToID(b)
+}
+
+// [leaderboardPrefix] + [boardID]
+func LeaderboardKey(boardID ids.ID) (k []byte) {
+	k = make([]byte, 1+ids.IDLen+consts.Uint16Len)
+	k[0] = leaderboardPrefix
+	copy(k[1:], boardID[:])
+	binary.BigEndian.PutUint16(k[1+ids.IDLen:], LeaderboardChunks)
+	return
+}
+
+// GetLeaderboard returns the leaderboard stored under [boardID], if any.
+func GetLeaderboard(
+	ctx context.Context,
+	im state.Immutable,
+	boardID ids.ID,
+) (*Leaderboard, bool, error) {
+	return innerGetLeaderboard(getValue(ctx, im, LeaderboardKey(boardID)))
+}
+
+// Used to serve RPC queries
+func GetLeaderboardFromState(
+	ctx context.Context,
+	f ReadState,
+	boardID ids.ID,
+) (*Leaderboard, bool, error) {
+	values, errs := f(ctx, [][]byte{LeaderboardKey(boardID)})
+	return innerGetLeaderboard(values[0], errs[0])
+}
+
+func innerGetLeaderboard(v []byte, err error) (*Leaderboard, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(v) < leaderboardHeaderSize {
+		return nil, false, fmt.Errorf("%w: %d bytes", ErrInvalidLeaderboard, len(v))
+	}
+	l := &Leaderboard{
+		Creator: codec.Address(v),
+		Size:    v[codec.AddressLen],
+		Expiry:  int64(binary.BigEndian.Uint64(v[codec.AddressLen+consts.ByteLen:])),
+		Entries: make([]LeaderboardEntry, v[leaderboardHeaderSize-consts.ByteLen]),
+	}
+	if len(v) != leaderboardHeaderSize+len(l.Entries)*leaderboardEntrySize {
+		return nil, false, fmt.Errorf("%w: %d bytes", ErrInvalidLeaderboard, len(v))
+	}
+	offset := leaderboardHeaderSize
+	for i := range l.Entries {
+		l.Entries[i] = LeaderboardEntry{
+			Player:    codec.Address(v[offset:]),
+			Score:     binary.BigEndian.Uint64(v[offset+codec.AddressLen:]),
+			Timestamp: int64(binary.BigEndian.Uint64(v[offset+codec.AddressLen+consts.Uint64Len:])),
+		}
+		offset += leaderboardEntrySize
+	}
+	return l, true, nil
+}
+
+// SetLeaderboard stores [l] under [boardID].
+func SetLeaderboard(
+	ctx context.Context,
+	mu state.Mutable,
+	boardID ids.ID,
+	l *Leaderboard,
+) error {
+	if l.Size > MaxLeaderboardSize || len(l.Entries) > int(l.Size) {
+		return fmt.Errorf("%w: %d of %d entries", ErrInvalidLeaderboard, len(l.Entries), l.Size)
+	}
+	v := make([]byte, 0, leaderboardHeaderSize+len(l.Entries)*leaderboardEntrySize)
+	v = append(v, l.Creator[:]...)
+	v = append(v, l.Size)
+	v = binary.BigEndian.AppendUint64(v, uint64(l.Expiry))
+	v = append(v, byte(len(l.Entries)))
+	for _, e := range l.Entries {
+		v = append(v, e.Player[:]...)
+		v = binary.BigEndian.AppendUint64(v, e.Score)
+		v = binary.BigEndian.AppendUint64(v, uint64(e.Timestamp))
+	}
+	return insertValue(ctx, mu, LeaderboardKey(boardID), v)
+}
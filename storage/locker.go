@@ -0,0 +1,153 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+const (
+	// MaxLockerLegs bounds the native and fungible balances of a locker.
+	MaxLockerLegs = 8
+	// MaxLockerItems bounds the non-fungible assets of a locker.
+	MaxLockerItems = 8
+)
+
+// lockerAddressType is not an auth type, so no key can sign for a
+// [LockerAddress].
+const lockerAddressType = 0xfa
+
+// Locker is the content of a locker asset, held at its [LockerAddress]
+// until its holder unbundles it.
+type Locker struct {
+	// Legs are balances of distinct assets, [NativeAsset] included.
+	Legs []SwapLeg `json:"legs"`
+	// Items are the non-fungible assets the locker owns.
+	Items []ids.ID `json:"items"`
+}
+
+// LockerID is the ID of the locker [creator] creates with [nonce]. Like
+// [AssetID] it is tagged with its prefix, so it differs from the asset
+// [creator] creates with the same nonce.
+func LockerID(creator codec.Address, nonce uint64) ids.ID {
+	b := make([]byte, 1+codec.AddressLen+consts.Uint64Len)
+	b[0] = lockerPrefix
+	copy(b[1:], creator[:])
+	binary.BigEndian.PutUint64(b[1+codec.AddressLen:], nonce)
+	return utils.ToID(b)
+}
+
+// LockerAddress holds the content of [lockerID].
+func LockerAddress(lockerID ids.ID) codec.Address {
+	return codec.CreateAddress(lockerAddressType, lockerID)
+}
+
+// IsLockerAddress reports whether [addr] is the [LockerAddress] of a locker.
+func IsLockerAddress(addr codec.Address) bool {
+	return addr[0] == lockerAddressType
+}
+
+// [lockerPrefix] + [lockerID]
+func LockerKey(lockerID ids.ID) (k []byte) {
+	k = make([]byte, 1+ids.IDLen+consts.Uint16Len)
+	k[0] = lockerPrefix
+	copy(k[1:], lockerID[:])
+	binary.BigEndian.PutUint16(k[1+ids.IDLen:], LockerChunks)
+	return
+}
+
+// GetLocker returns the content of [lockerID], if it is a locker.
+func GetLocker(
+	ctx context.Context,
+	im state.Immutable,
+	lockerID ids.ID,
+) (*Locker, bool, error) {
+	return innerGetLocker(getValue(ctx, im, LockerKey(lockerID)))
+}
+
+// GetLockerFromState returns the content of [lockerID] with its holder,
+// read at one height. Used to serve RPC queries.
+func GetLockerFromState(
+	ctx context.Context,
+	f ReadState,
+	lockerID ids.ID,
+) (*Locker, codec.Address, bool, error) {
+	values, errs := f(ctx, [][]byte{LockerKey(lockerID), AssetKey(lockerID)})
+	l, exists, err := innerGetLocker(values[0], errs[0])
+	if err != nil || !exists {
+		return nil, codec.EmptyAddress, false, err
+	}
+	if errs[1] != nil {
+		return nil, codec.EmptyAddress, false, errs[1]
+	}
+	holder, _, _, err := unpackAsset(values[1])
+	return l, holder, true, err
+}
+
+func innerGetLocker(v []byte, err error) (*Locker, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	p := codec.NewReader(v, len(v))
+	l := &Locker{Legs: unpackLegs(p), Items: make([]ids.ID, p.UnpackByte())}
+	for i := range l.Items {
+		p.UnpackID(true, &l.Items[i])
+	}
+	if err := p.Err(); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrInvalidLocker, err)
+	}
+	if !p.Empty() {
+		return nil, false, fmt.Errorf("%w: trailing bytes", ErrInvalidLocker)
+	}
+	return l, true, nil
+}
+
+// SetLocker stores [l] as the content of [lockerID].
+func SetLocker(
+	ctx context.Context,
+	mu state.Mutable,
+	lockerID ids.ID,
+	l *Locker,
+) error {
+	if len(l.Legs) > MaxLockerLegs || len(l.Items) > MaxLockerItems {
+		return fmt.Errorf("%w: %d legs and %d items", ErrInvalidLocker, len(l.Legs), len(l.Items))
+	}
+	size := 2 + len(l.Legs)*(ids.IDLen+consts.Uint64Len) + len(l.Items)*ids.IDLen
+	p := codec.NewWriter(size, size)
+	p.PackByte(uint8(len(l.Legs)))
+	for _, leg := range l.Legs {
+		p.PackID(leg.Asset)
+		p.PackUint64(leg.Amount)
+	}
+	p.PackByte(uint8(len(l.Items)))
+	for _, item := range l.Items {
+		p.PackID(item)
+	}
+	if err := p.Err(); err != nil {
+		return err
+	}
+	return insertValue(ctx, mu, LockerKey(lockerID), p.Bytes())
+}
+
+func DeleteLocker(
+	ctx context.Context,
+	mu state.Mutable,
+	lockerID ids.ID,
+) error {
+	return Delete(ctx, mu, LockerKey(lockerID))
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const maintenanceCompaction = 0x0
+
+// [maintenancePrefix] + [maintenanceCompaction]
+func CompactionKey() (k []byte) {
+	k = make([]byte, 2+consts.Uint16Len)
+	k[0] = maintenancePrefix
+	k[1] = maintenanceCompaction
+	binary.BigEndian.PutUint16(k[2:], CompactionChunks)
+	return
+}
+
+// GetCompactionHeight returns the height of the last compaction marker.
+// Indexes may drop history below it.
+func GetCompactionHeight(
+	ctx context.Context,
+	im state.Immutable,
+) (uint64, error) {
+	height, _, err := innerGetBalance(getValue(ctx, im, CompactionKey()))
+	return height, err
+}
+
+// Used to serve RPC queries
+func GetCompactionHeightFromState(
+	ctx context.Context,
+	f ReadState,
+) (uint64, error) {
+	values, errs := f(ctx, [][]byte{CompactionKey()})
+	height, _, err := innerGetBalance(values[0], errs[0])
+	return height, err
+}
+
+func SetCompactionHeight(
+	ctx context.Context,
+	mu state.Mutable,
+	height uint64,
+) error {
+	return setBalance(ctx, mu, CompactionKey(), height)
+}
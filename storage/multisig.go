@@ -0,0 +1,249 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// MaxMultisigSigners bounds a signer set so approvals fit in a uint16 bitmap.
+const MaxMultisigSigners = 16
+
+const (
+	multisigSigners  = 0x0
+	multisigProposal = 0x1
+
+	// multisigAddressType is not an auth type, so no key can sign for a
+	// [MultisigAddress].
+	multisigAddressType = 0xfd
+
+	multisigProposalValueSize = consts.Int64Len + consts.Uint16Len + codec.AddressLen + ids.IDLen + consts.Uint64Len
+)
+
+// Multisig is the signer set of a [MultisigAddress].
+type Multisig struct {
+	Signers []codec.Address `json:"signers"`
+	// Threshold is how many signers must approve a transaction.
+	Threshold uint8 `json:"threshold"`
+}
+
+func (m *Multisig) Verify() error {
+	if len(m.Signers) == 0 || len(m.Signers) > MaxMultisigSigners {
+		return fmt.Errorf("%w: %d signers", ErrInvalidMultisig, len(m.Signers))
+	}
+	if m.Threshold == 0 || int(m.Threshold) > len(m.Signers) {
+		return fmt.Errorf("%w: threshold %d of %d", ErrInvalidMultisig, m.Threshold, len(m.Signers))
+	}
+	for i, s := range m.Signers {
+		if s == codec.EmptyAddress {
+			return fmt.Errorf("%w: signer %d is empty", ErrInvalidMultisig, i)
+		}
+		if m.Index(s) != i {
+			return fmt.Errorf("%w: %s listed twice", ErrInvalidMultisig, s)
+		}
+	}
+	return nil
+}
+
+// Index returns the position of [addr] in the signer set, or -1.
+func (m *Multisig) Index(addr codec.Address) int {
+	for i, s := range m.Signers {
+		if s == addr {
+			return i
+		}
+	}
+	return -1
+}
+
+// MultisigTx is a transfer out of a multisig: [Value] native tokens to [To]
+// if [Asset] is [NativeAsset], or else [Asset] itself.
+type MultisigTx struct {
+	To    codec.Address `serialize:"true" json:"to"`
+	Asset ids.ID        `serialize:"true" json:"asset"`
+	Value uint64        `serialize:"true" json:"value"`
+}
+
+// MultisigProposal is a pending transaction of a multisig.
+type MultisigProposal struct {
+	Tx MultisigTx `json:"tx"`
+	// Expiry is the timestamp, in milliseconds, after which the proposal can
+	// no longer be approved.
+	Expiry int64 `json:"expiry"`
+	// Approvals has bit i set once signer i approved.
+	Approvals uint16 `json:"approvals"`
+}
+
+func (p *MultisigProposal) ApprovalCount() int {
+	return bits.OnesCount16(p.Approvals)
+}
+
+// MultisigAddress holds the funds of the multisig [creator] registers with
+// [nonce]. Funds only leave it through approved transactions.
+func MultisigAddress(creator codec.Address, nonce uint64) codec.Address {
+	b := make([]byte, codec.AddressLen+consts.Uint64Len)
+	copy(b, creator[:])
+	binary.BigEndian.PutUint64(b[codec.AddressLen:], nonce)
+	return codec.CreateAddress(multisigAddressType, utils.ToID(b))
+}
+
+// [multisigPrefix] + [multisigSigners] + [multisig]
+func MultisigKey(multisig codec.Address) (k []byte) {
+	k = make([]byte, 2+codec.AddressLen+consts.Uint16Len)
+	k[0] = multisigPrefix
+	k[1] = multisigSigners
+	copy(k[2:], multisig[:])
+	binary.BigEndian.PutUint16(k[2+codec.AddressLen:], MultisigChunks)
+	return
+}
+
+// [multisigPrefix] + [multisigProposal] + [multisig] + [proposalID]
+func MultisigProposalKey(multisig codec.Address, proposalID ids.ID) (k []byte) {
+	k = make([]byte, 2+codec.AddressLen+ids.IDLen+consts.Uint16Len)
+	k[0] = multisigPrefix
+	k[1] = multisigProposal
+	copy(k[2:], multisig[:])
+	copy(k[2+codec.AddressLen:], proposalID[:])
+	binary.BigEndian.PutUint16(k[2+codec.AddressLen+ids.IDLen:], MultisigProposalChunks)
+	return
+}
+
+// GetMultisig returns the signer set of [multisig], if it is one.
+func GetMultisig(
+	ctx context.Context,
+	im state.Immutable,
+	multisig codec.Address,
+) (*Multisig, bool, error) {
+	return innerGetMultisig(getValue(ctx, im, MultisigKey(multisig)))
+}
+
+// Used to serve RPC queries
+func GetMultisigFromState(
+	ctx context.Context,
+	f ReadState,
+	multisig codec.Address,
+) (*Multisig, bool, error) {
+	values, errs := f(ctx, [][]byte{MultisigKey(multisig)})
+	return innerGetMultisig(values[0], errs[0])
+}
+
+func innerGetMultisig(v []byte, err error) (*Multisig, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(v) < 2 || len(v) != 2+int(v[1])*codec.AddressLen {
+		return nil, false, fmt.Errorf("%w: %d bytes", ErrInvalidMultisig, len(v))
+	}
+	m := &Multisig{
+		Threshold: v[0],
+		Signers:   make([]codec.Address, v[1]),
+	}
+	for i := range m.Signers {
+		m.Signers[i] = codec.Address(v[2+i*codec.AddressLen:])
+	}
+	return m, true, nil
+}
+
+// SetMultisig stores [m] as the signer set of [multisig].
+func SetMultisig(
+	ctx context.Context,
+	mu state.Mutable,
+	multisig codec.Address,
+	m *Multisig,
+) error {
+	if err := m.Verify(); err != nil {
+		return err
+	}
+	v := make([]byte, 2, 2+len(m.Signers)*codec.AddressLen)
+	v[0] = m.Threshold
+	v[1] = byte(len(m.Signers))
+	for _, s := range m.Signers {
+		v = append(v, s[:]...)
+	}
+	return insertValue(ctx, mu, MultisigKey(multisig), v)
+}
+
+// GetMultisigProposal returns the proposal of [multisig] stored under
+// [proposalID], if any.
+func GetMultisigProposal(
+	ctx context.Context,
+	im state.Immutable,
+	multisig codec.Address,
+	proposalID ids.ID,
+) (*MultisigProposal, bool, error) {
+	return innerGetMultisigProposal(getValue(ctx, im, MultisigProposalKey(multisig, proposalID)))
+}
+
+// Used to serve RPC queries
+func GetMultisigProposalFromState(
+	ctx context.Context,
+	f ReadState,
+	multisig codec.Address,
+	proposalID ids.ID,
+) (*MultisigProposal, bool, error) {
+	values, errs := f(ctx, [][]byte{MultisigProposalKey(multisig, proposalID)})
+	return innerGetMultisigProposal(values[0], errs[0])
+}
+
+func innerGetMultisigProposal(v []byte, err error) (*MultisigProposal, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(v) != multisigProposalValueSize {
+		return nil, false, fmt.Errorf("%w: proposal is %d bytes", ErrInvalidMultisig, len(v))
+	}
+	offset := consts.Int64Len + consts.Uint16Len
+	return &MultisigProposal{
+		Expiry:    int64(binary.BigEndian.Uint64(v)),
+		Approvals: binary.BigEndian.Uint16(v[consts.Int64Len:]),
+		Tx: MultisigTx{
+			To:    codec.Address(v[offset:]),
+			Asset: ids.ID(v[offset+codec.AddressLen:]),
+			Value: binary.BigEndian.Uint64(v[offset+codec.AddressLen+ids.IDLen:]),
+		},
+	}, true, nil
+}
+
+func SetMultisigProposal(
+	ctx context.Context,
+	mu state.Mutable,
+	multisig codec.Address,
+	proposalID ids.ID,
+	p *MultisigProposal,
+) error {
+	v := make([]byte, multisigProposalValueSize)
+	binary.BigEndian.PutUint64(v, uint64(p.Expiry))
+	binary.BigEndian.PutUint16(v[consts.Int64Len:], p.Approvals)
+	offset := consts.Int64Len + consts.Uint16Len
+	copy(v[offset:], p.Tx.To[:])
+	copy(v[offset+codec.AddressLen:], p.Tx.Asset[:])
+	binary.BigEndian.PutUint64(v[offset+codec.AddressLen+ids.IDLen:], p.Tx.Value)
+	return insertValue(ctx, mu, MultisigProposalKey(multisig, proposalID), v)
+}
+
+func DeleteMultisigProposal(
+	ctx context.Context,
+	mu state.Mutable,
+	multisig codec.Address,
+	proposalID ids.ID,
+) error {
+	return Delete(ctx, mu, MultisigProposalKey(multisig, proposalID))
+}
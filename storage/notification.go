@@ -0,0 +1,104 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// Event kinds an address can opt into with [NotificationPrefs.EventMask].
+const (
+	NotifyTransfer uint64 = 1 << iota
+	NotifyAssetTransfer
+	NotifyMint
+	NotifyBurn
+
+	NotifyAll = NotifyTransfer | NotifyAssetTransfer | NotifyMint | NotifyBurn
+)
+
+// NotificationPrefs tell notifiers, such as the node's event stream, which
+// events of an address to push, and where.
+type NotificationPrefs struct {
+	// Target identifies the destination: the hash of a webhook URL or the
+	// public key of a subscriber.
+	Target    ids.ID `json:"target"`
+	EventMask uint64 `json:"eventMask"`
+}
+
+// Wants reports whether events of [kind] should be pushed.
+func (p NotificationPrefs) Wants(kind uint64) bool {
+	return p.EventMask&kind != 0
+}
+
+// [notificationPrefix] + [address]
+func NotificationKey(addr codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+consts.Uint16Len)
+	k[0] = notificationPrefix
+	copy(k[1:], addr[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen:], NotificationChunks)
+	return
+}
+
+// GetNotificationPrefs returns the preferences of [addr]. Addresses that never
+// opted in have an empty mask.
+func GetNotificationPrefs(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+) (NotificationPrefs, error) {
+	return innerGetNotificationPrefs(getValue(ctx, im, NotificationKey(addr)))
+}
+
+// Used to serve RPC queries
+func GetNotificationPrefsFromState(
+	ctx context.Context,
+	f ReadState,
+	addr codec.Address,
+) (NotificationPrefs, error) {
+	values, errs := f(ctx, [][]byte{NotificationKey(addr)})
+	return innerGetNotificationPrefs(values[0], errs[0])
+}
+
+func innerGetNotificationPrefs(v []byte, err error) (NotificationPrefs, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return NotificationPrefs{}, nil
+	}
+	if err != nil {
+		return NotificationPrefs{}, err
+	}
+	if len(v) != ids.IDLen+consts.Uint64Len {
+		return NotificationPrefs{}, fmt.Errorf("%w: %d bytes", ErrInvalidNotificationPrefs, len(v))
+	}
+	return NotificationPrefs{
+		Target:    ids.ID(v[:ids.IDLen]),
+		EventMask: binary.BigEndian.Uint64(v[ids.IDLen:]),
+	}, nil
+}
+
+// SetNotificationPrefs stores [prefs] for [addr]. An empty mask removes them.
+func SetNotificationPrefs(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	prefs NotificationPrefs,
+) error {
+	k := NotificationKey(addr)
+	if prefs.EventMask == 0 {
+		return Delete(ctx, mu, k)
+	}
+	v := make([]byte, ids.IDLen+consts.Uint64Len)
+	copy(v, prefs.Target[:])
+	binary.BigEndian.PutUint64(v[ids.IDLen:], prefs.EventMask)
+	return insertValue(ctx, mu, k, v)
+}
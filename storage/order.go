@@ -0,0 +1,197 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+const orderValueSize = codec.AddressLen + 3*consts.Uint64Len
+
+// Order is a standing offer by [Maker] to sell [SellAmount] of one asset for
+// [BuyAmount] of another, held in escrow by the order itself. The assets
+// are part of the key. Orders can be filled in parts at the same price.
+type Order struct {
+	Maker      codec.Address `json:"maker"`
+	SellAmount uint64        `json:"sellAmount"`
+	BuyAmount  uint64        `json:"buyAmount"`
+	// Remaining is the part of [SellAmount] that is still escrowed.
+	Remaining uint64 `json:"remaining"`
+}
+
+// [orderBookPrefix] + [sellAsset] + [buyAsset] + [orderID]
+//
+// Keys of one pair share the prefix [orderBookPrefix] + [sellAsset] + [buyAsset]
+// and sort by order ID, which is what [GetOrdersByPair] scans.
+func OrderKey(sellAsset ids.ID, buyAsset ids.ID, orderID ids.ID) (k []byte) {
+	k = make([]byte, 1+3*ids.IDLen+consts.Uint16Len)
+	k[0] = orderBookPrefix
+	copy(k[1:], sellAsset[:])
+	copy(k[1+ids.IDLen:], buyAsset[:])
+	copy(k[1+2*ids.IDLen:], orderID[:])
+	binary.BigEndian.PutUint16(k[1+3*ids.IDLen:], OrderChunks)
+	return
+}
+
+// OrderSequenceKey is the counter of the orders [maker] has opened, from
+// which [OrderID] derives their IDs.
+func OrderSequenceKey(maker codec.Address) []byte {
+	return SequenceKey(append([]byte("order"), maker[:]...))
+}
+
+// OrderID is the ID of the order [maker] opens with [sequence]. Sequences
+// are issued once, so order IDs are never reused.
+func OrderID(maker codec.Address, sequence uint64) ids.ID {
+	b := make([]byte, codec.AddressLen+consts.Uint64Len)
+	copy(b, maker[:])
+	binary.BigEndian.PutUint64(b[codec.AddressLen:], sequence)
+	return utils.ToID(b)
+}
+
+func ordersPrefix(sellAsset ids.ID, buyAsset ids.ID) []byte {
+	k := make([]byte, 1+2*ids.IDLen)
+	k[0] = orderBookPrefix
+	copy(k[1:], sellAsset[:])
+	copy(k[1+ids.IDLen:], buyAsset[:])
+	return k
+}
+
+// GetOrder returns the order [orderID] of the pair, if any.
+func GetOrder(
+	ctx context.Context,
+	im state.Immutable,
+	sellAsset ids.ID,
+	buyAsset ids.ID,
+	orderID ids.ID,
+) (*Order, bool, error) {
+	return innerGetOrder(getValue(ctx, im, OrderKey(sellAsset, buyAsset, orderID)))
+}
+
+// Used to serve RPC queries
+func GetOrderFromState(
+	ctx context.Context,
+	f ReadState,
+	sellAsset ids.ID,
+	buyAsset ids.ID,
+	orderID ids.ID,
+) (*Order, bool, error) {
+	values, errs := f(ctx, [][]byte{OrderKey(sellAsset, buyAsset, orderID)})
+	return innerGetOrder(values[0], errs[0])
+}
+
+func innerGetOrder(v []byte, err error) (*Order, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	order, err := unpackOrder(v)
+	if err != nil {
+		return nil, false, err
+	}
+	return order, true, nil
+}
+
+func unpackOrder(v []byte) (*Order, error) {
+	if len(v) != orderValueSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidOrder, len(v))
+	}
+	order := &Order{
+		SellAmount: binary.BigEndian.Uint64(v[codec.AddressLen:]),
+		BuyAmount:  binary.BigEndian.Uint64(v[codec.AddressLen+consts.Uint64Len:]),
+		Remaining:  binary.BigEndian.Uint64(v[codec.AddressLen+2*consts.Uint64Len:]),
+	}
+	copy(order.Maker[:], v)
+	return order, nil
+}
+
+// SetOrder stores [order] under [orderID] of the pair.
+func SetOrder(
+	ctx context.Context,
+	mu state.Mutable,
+	sellAsset ids.ID,
+	buyAsset ids.ID,
+	orderID ids.ID,
+	order *Order,
+) error {
+	v := make([]byte, orderValueSize)
+	copy(v, order.Maker[:])
+	binary.BigEndian.PutUint64(v[codec.AddressLen:], order.SellAmount)
+	binary.BigEndian.PutUint64(v[codec.AddressLen+consts.Uint64Len:], order.BuyAmount)
+	binary.BigEndian.PutUint64(v[codec.AddressLen+2*consts.Uint64Len:], order.Remaining)
+	return insertValue(ctx, mu, OrderKey(sellAsset, buyAsset, orderID), v)
+}
+
+func DeleteOrder(
+	ctx context.Context,
+	mu state.Mutable,
+	sellAsset ids.ID,
+	buyAsset ids.ID,
+	orderID ids.ID,
+) error {
+	return Delete(ctx, mu, OrderKey(sellAsset, buyAsset, orderID))
+}
+
+// Orders iterates over the open orders of one pair in ID order.
+type Orders struct {
+	it    database.Iterator
+	order *Order
+	err   error
+}
+
+// GetOrdersByPair returns an iterator over the orders selling [sellAsset]
+// for [buyAsset] in [db], starting at [start]. Pass [ids.Empty] to start
+// from the first one.
+//
+// The iterator must be released once done.
+func GetOrdersByPair(db database.Iteratee, sellAsset ids.ID, buyAsset ids.ID, start ids.ID) *Orders {
+	prefix := ordersPrefix(sellAsset, buyAsset)
+	return &Orders{
+		it: db.NewIteratorWithStartAndPrefix(append(prefix, start[:]...), prefix),
+	}
+}
+
+func (o *Orders) Next() bool {
+	if o.err != nil || !o.it.Next() {
+		return false
+	}
+	o.order, o.err = unpackOrder(o.it.Value())
+	return o.err == nil
+}
+
+// ID returns the current order ID. It is only valid after Next returned
+// true.
+func (o *Orders) ID() ids.ID {
+	k := o.it.Key()
+	return ids.ID(k[1+2*ids.IDLen:])
+}
+
+// Order returns the current order. It is only valid after Next returned
+// true.
+func (o *Orders) Order() *Order {
+	return o.order
+}
+
+func (o *Orders) Error() error {
+	if o.err != nil {
+		return o.err
+	}
+	return o.it.Error()
+}
+
+func (o *Orders) Release() {
+	o.it.Release()
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// ownedAssetValue marks an entry of the owner index. The key carries all
+// the information.
+var ownedAssetValue = []byte{1}
+
+// [ownedAssetPrefix] + [owner] + [assetID]
+//
+// Keys of one owner share the prefix [ownedAssetPrefix] + [owner] and sort
+// by asset ID, which is what [GetAssetsByOwner] scans.
+func OwnedAssetKey(owner codec.Address, assetID ids.ID) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+ids.IDLen+consts.Uint16Len)
+	k[0] = ownedAssetPrefix
+	copy(k[1:], owner[:])
+	copy(k[1+codec.AddressLen:], assetID[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen+ids.IDLen:], OwnedAssetChunks)
+	return
+}
+
+func ownedAssetsPrefix(owner codec.Address) []byte {
+	k := make([]byte, 1+codec.AddressLen)
+	k[0] = ownedAssetPrefix
+	copy(k[1:], owner[:])
+	return k
+}
+
+func addOwnedAsset(ctx context.Context, mu state.Mutable, owner codec.Address, assetID ids.ID) error {
+	return insertValue(ctx, mu, OwnedAssetKey(owner, assetID), ownedAssetValue)
+}
+
+func removeOwnedAsset(ctx context.Context, mu state.Mutable, owner codec.Address, assetID ids.ID) error {
+	return Delete(ctx, mu, OwnedAssetKey(owner, assetID))
+}
+
+// OwnedAssets iterates over the assets of one owner in ID order.
+type OwnedAssets struct {
+	it database.Iterator
+}
+
+// GetAssetsByOwner returns an iterator over the assets [owner] holds in
+// [db], starting at [start]. Pass [ids.Empty] to start from the first one.
+//
+// The iterator must be released once done.
+func GetAssetsByOwner(db database.Iteratee, owner codec.Address, start ids.ID) *OwnedAssets {
+	prefix := ownedAssetsPrefix(owner)
+	return &OwnedAssets{
+		it: db.NewIteratorWithStartAndPrefix(append(prefix, start[:]...), prefix),
+	}
+}
+
+func (o *OwnedAssets) Next() bool {
+	return o.it.Next()
+}
+
+// Asset returns the current asset. It is only valid after Next returned true.
+func (o *OwnedAssets) Asset() ids.ID {
+	k := o.it.Key()
+	return ids.ID(k[1+codec.AddressLen:])
+}
+
+func (o *OwnedAssets) Error() error {
+	return o.it.Error()
+}
+
+func (o *OwnedAssets) Release() {
+	o.it.Release()
+}
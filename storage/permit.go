@@ -0,0 +1,70 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// permitUsedValue marks a permit nonce as spent. The key carries all the
+// information.
+var permitUsedValue = []byte{1}
+
+// [permitPrefix] + [owner] + [nonce]
+func PermitKey(owner codec.Address, nonce uint64) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+consts.Uint64Len+consts.Uint16Len)
+	k[0] = permitPrefix
+	copy(k[1:], owner[:])
+	binary.BigEndian.PutUint64(k[1+codec.AddressLen:], nonce)
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen+consts.Uint64Len:], PermitChunks)
+	return
+}
+
+// IsPermitUsed returns whether a permit of [owner] with [nonce] has been
+// used.
+func IsPermitUsed(
+	ctx context.Context,
+	im state.Immutable,
+	owner codec.Address,
+	nonce uint64,
+) (bool, error) {
+	return innerIsPermitUsed(getValue(ctx, im, PermitKey(owner, nonce)))
+}
+
+// Used to serve RPC queries
+func IsPermitUsedFromState(
+	ctx context.Context,
+	f ReadState,
+	owner codec.Address,
+	nonce uint64,
+) (bool, error) {
+	values, errs := f(ctx, [][]byte{PermitKey(owner, nonce)})
+	return innerIsPermitUsed(values[0], errs[0])
+}
+
+func innerIsPermitUsed(_ []byte, err error) (bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// UsePermit marks the permit of [owner] with [nonce] as used. Used nonces
+// are never released, so a permit cannot be replayed.
+func UsePermit(
+	ctx context.Context,
+	mu state.Mutable,
+	owner codec.Address,
+	nonce uint64,
+) error {
+	return insertValue(ctx, mu, PermitKey(owner, nonce), permitUsedValue)
+}
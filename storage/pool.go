@@ -0,0 +1,193 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+const (
+	poolReserves = 0x0
+	poolShares   = 0x1
+
+	poolValueSize = 3 * consts.Uint64Len
+)
+
+// Pool is a constant-product pool of two assets. The assets are part of the
+// key, in ascending order, so each pair has a single pool.
+type Pool struct {
+	ReserveA uint64 `json:"reserveA"`
+	ReserveB uint64 `json:"reserveB"`
+	// Shares is the number of LP shares issued, including the ones locked
+	// when the pool was created.
+	Shares uint64 `json:"shares"`
+}
+
+// SortedPair reports whether [assetA] and [assetB] are distinct and in the
+// order pools are keyed by.
+func SortedPair(assetA ids.ID, assetB ids.ID) bool {
+	return bytes.Compare(assetA[:], assetB[:]) < 0
+}
+
+// [poolPrefix] + [poolReserves] + [assetA] + [assetB]
+func PoolKey(assetA ids.ID, assetB ids.ID) (k []byte) {
+	k = make([]byte, 2+2*ids.IDLen+consts.Uint16Len)
+	k[0] = poolPrefix
+	k[1] = poolReserves
+	copy(k[2:], assetA[:])
+	copy(k[2+ids.IDLen:], assetB[:])
+	binary.BigEndian.PutUint16(k[2+2*ids.IDLen:], PoolChunks)
+	return
+}
+
+// [poolPrefix] + [poolShares] + [assetA] + [assetB] + [provider]
+func PoolSharesKey(assetA ids.ID, assetB ids.ID, provider codec.Address) (k []byte) {
+	k = make([]byte, 2+2*ids.IDLen+codec.AddressLen+consts.Uint16Len)
+	k[0] = poolPrefix
+	k[1] = poolShares
+	copy(k[2:], assetA[:])
+	copy(k[2+ids.IDLen:], assetB[:])
+	copy(k[2+2*ids.IDLen:], provider[:])
+	binary.BigEndian.PutUint16(k[2+2*ids.IDLen+codec.AddressLen:], PoolSharesChunks)
+	return
+}
+
+// GetPool returns the pool of the pair, if any.
+func GetPool(
+	ctx context.Context,
+	im state.Immutable,
+	assetA ids.ID,
+	assetB ids.ID,
+) (*Pool, bool, error) {
+	return innerGetPool(getValue(ctx, im, PoolKey(assetA, assetB)))
+}
+
+// Used to serve RPC queries
+func GetPoolFromState(
+	ctx context.Context,
+	f ReadState,
+	assetA ids.ID,
+	assetB ids.ID,
+) (*Pool, bool, error) {
+	values, errs := f(ctx, [][]byte{PoolKey(assetA, assetB)})
+	return innerGetPool(values[0], errs[0])
+}
+
+func innerGetPool(v []byte, err error) (*Pool, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(v) != poolValueSize {
+		return nil, false, fmt.Errorf("%w: %d bytes", ErrInvalidPool, len(v))
+	}
+	return &Pool{
+		ReserveA: binary.BigEndian.Uint64(v),
+		ReserveB: binary.BigEndian.Uint64(v[consts.Uint64Len:]),
+		Shares:   binary.BigEndian.Uint64(v[2*consts.Uint64Len:]),
+	}, true, nil
+}
+
+// SetPool stores [pool] as the pool of the pair.
+func SetPool(
+	ctx context.Context,
+	mu state.Mutable,
+	assetA ids.ID,
+	assetB ids.ID,
+	pool *Pool,
+) error {
+	v := make([]byte, poolValueSize)
+	binary.BigEndian.PutUint64(v, pool.ReserveA)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len:], pool.ReserveB)
+	binary.BigEndian.PutUint64(v[2*consts.Uint64Len:], pool.Shares)
+	return insertValue(ctx, mu, PoolKey(assetA, assetB), v)
+}
+
+// GetPoolShares returns the LP shares [provider] holds in the pool of the
+// pair.
+func GetPoolShares(
+	ctx context.Context,
+	im state.Immutable,
+	assetA ids.ID,
+	assetB ids.ID,
+	provider codec.Address,
+) (uint64, error) {
+	shares, _, err := innerGetBalance(getValue(ctx, im, PoolSharesKey(assetA, assetB, provider)))
+	return shares, err
+}
+
+// Used to serve RPC queries
+func GetPoolSharesFromState(
+	ctx context.Context,
+	f ReadState,
+	assetA ids.ID,
+	assetB ids.ID,
+	provider codec.Address,
+) (uint64, error) {
+	values, errs := f(ctx, [][]byte{PoolSharesKey(assetA, assetB, provider)})
+	shares, _, err := innerGetBalance(values[0], errs[0])
+	return shares, err
+}
+
+// AddPoolShares credits [amount] LP shares to [provider], returning its new
+// share count.
+func AddPoolShares(
+	ctx context.Context,
+	mu state.Mutable,
+	assetA ids.ID,
+	assetB ids.ID,
+	provider codec.Address,
+	amount uint64,
+) (uint64, error) {
+	key := PoolSharesKey(assetA, assetB, provider)
+	shares, _, err := innerGetBalance(getValue(ctx, mu, key))
+	if err != nil {
+		return 0, err
+	}
+	nshares, err := smath.Add(shares, amount)
+	if err != nil {
+		return 0, fmt.Errorf("%w: could not add pool shares (shares=%d, provider=%v, amount=%d)", ErrInvalidBalance, shares, provider, amount)
+	}
+	return nshares, setBalance(ctx, mu, key, nshares)
+}
+
+// SubPoolShares debits [amount] LP shares from [provider], returning its new
+// share count. The record is removed once it reaches zero.
+func SubPoolShares(
+	ctx context.Context,
+	mu state.Mutable,
+	assetA ids.ID,
+	assetB ids.ID,
+	provider codec.Address,
+	amount uint64,
+) (uint64, error) {
+	key := PoolSharesKey(assetA, assetB, provider)
+	shares, _, err := innerGetBalance(getValue(ctx, mu, key))
+	if err != nil {
+		return 0, err
+	}
+	nshares, err := smath.Sub(shares, amount)
+	if err != nil {
+		return 0, fmt.Errorf("%w: could not subtract pool shares (shares=%d, provider=%v, amount=%d)", ErrInvalidBalance, shares, provider, amount)
+	}
+	if nshares == 0 {
+		return 0, Delete(ctx, mu, key)
+	}
+	return nshares, setBalance(ctx, mu, key, nshares)
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/ava-labs/avalanchego/trace"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// ReadRecorder observes the records read by this package, to show which
+// record types are outgrowing their chunk budget.
+type ReadRecorder interface {
+	// RecordRead is called for every read that finds a value. [chunks] is
+	// the size of the value and [maxChunks] the budget declared by [key].
+	RecordRead(key []byte, chunks uint16, maxChunks uint16)
+}
+
+type recorderHolder struct {
+	ReadRecorder
+}
+
+var readRecorder atomic.Pointer[recorderHolder]
+
+// LatencyRecorder observes how long the state accesses of this package
+// take.
+type LatencyRecorder interface {
+	// RecordLatency is called for every read, insert and removal of [key].
+	RecordLatency(key []byte, write bool, d time.Duration)
+}
+
+type latencyHolder struct {
+	LatencyRecorder
+}
+
+var latencyRecorder atomic.Pointer[latencyHolder]
+
+type tracerHolder struct {
+	trace.Tracer
+}
+
+var tracer atomic.Pointer[tracerHolder]
+
+// SetReadRecorder sends every read made through this package to [r]. It is
+// process-wide; nil stops recording.
+func SetReadRecorder(r ReadRecorder) {
+	if r == nil {
+		readRecorder.Store(nil)
+		return
+	}
+	readRecorder.Store(&recorderHolder{r})
+}
+
+// SetLatencyRecorder sends the latency of every state access made through
+// this package to [r]. It is process-wide; nil stops recording.
+func SetLatencyRecorder(r LatencyRecorder) {
+	if r == nil {
+		latencyRecorder.Store(nil)
+		return
+	}
+	latencyRecorder.Store(&latencyHolder{r})
+}
+
+// SetTracer traces every state access made through this package with [t],
+// as a child of the span of the caller's context. It is process-wide; nil
+// stops tracing.
+func SetTracer(t trace.Tracer) {
+	if t == nil {
+		tracer.Store(nil)
+		return
+	}
+	tracer.Store(&tracerHolder{t})
+}
+
+// startSpan starts the span of an access to [key] named [name]. The caller
+// must end it.
+func startSpan(ctx context.Context, name string, key []byte) (context.Context, oteltrace.Span) {
+	t := trace.Noop
+	if h := tracer.Load(); h != nil {
+		t = h.Tracer
+	}
+	ctx, span := t.Start(ctx, name)
+	if span.IsRecording() {
+		span.SetAttributes(attribute.String("record", PrefixName(key)))
+	}
+	return ctx, span
+}
+
+// timeAccess reports the latency of an access to [key] that started at
+// [start], if a recorder is set.
+func timeAccess(key []byte, write bool, start time.Time) {
+	if r := latencyRecorder.Load(); r != nil {
+		r.RecordLatency(key, write, time.Since(start))
+	}
+}
+
+// getValue reads [key] from [im], reporting the read to the recorders.
+func getValue(ctx context.Context, im state.Immutable, key []byte) ([]byte, error) {
+	ctx, span := startSpan(ctx, "Storage.GetValue", key)
+	defer span.End()
+
+	start := time.Now()
+	v, err := im.GetValue(ctx, key)
+	timeAccess(key, false, start)
+	if err != nil {
+		return v, err
+	}
+	span.SetAttributes(attribute.Int("size", len(v)))
+	if r := readRecorder.Load(); r != nil {
+		chunks, _ := keys.NumChunks(v)
+		maxChunks, _ := keys.MaxChunks(key)
+		r.RecordRead(key, chunks, maxChunks)
+	}
+	return v, nil
+}
+
+// insertValue writes [value] under [key] in [mu], reporting its latency.
+func insertValue(ctx context.Context, mu state.Mutable, key []byte, value []byte) error {
+	ctx, span := startSpan(ctx, "Storage.Insert", key)
+	defer span.End()
+	span.SetAttributes(attribute.Int("size", len(value)))
+
+	start := time.Now()
+	err := mu.Insert(ctx, key, value)
+	timeAccess(key, true, start)
+	return err
+}
+
+// removeValue removes [key] from [mu], reporting its latency.
+func removeValue(ctx context.Context, mu state.Mutable, key []byte) error {
+	ctx, span := startSpan(ctx, "Storage.Remove", key)
+	defer span.End()
+
+	start := time.Now()
+	err := mu.Remove(ctx, key)
+	timeAccess(key, true, start)
+	return err
+}
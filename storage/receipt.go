@@ -0,0 +1,88 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const receiptSize = ids.IDLen + consts.ByteLen + consts.Int64Len
+
+// Receipt records the action a receipt asset commemorates.
+type Receipt struct {
+	// ActionID is the ID of the action within its transaction, as computed
+	// by chain.CreateActionID from the txID and the action's index.
+	ActionID ids.ID `json:"actionID"`
+	// TypeID is the type of the action.
+	TypeID uint8 `json:"typeID"`
+	// Timestamp is the time of the block, in milliseconds.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// [receiptPrefix] + [assetID]
+func ReceiptKey(assetID ids.ID) (k []byte) {
+	k = make([]byte, 1+ids.IDLen+consts.Uint16Len)
+	k[0] = receiptPrefix
+	copy(k[1:], assetID[:])
+	binary.BigEndian.PutUint16(k[1+ids.IDLen:], ReceiptChunks)
+	return
+}
+
+func GetReceipt(
+	ctx context.Context,
+	im state.Immutable,
+	assetID ids.ID,
+) (*Receipt, bool, error) {
+	return innerGetReceipt(getValue(ctx, im, ReceiptKey(assetID)))
+}
+
+// Used to serve RPC queries
+func GetReceiptFromState(
+	ctx context.Context,
+	f ReadState,
+	assetID ids.ID,
+) (*Receipt, bool, error) {
+	values, errs := f(ctx, [][]byte{ReceiptKey(assetID)})
+	return innerGetReceipt(values[0], errs[0])
+}
+
+func innerGetReceipt(v []byte, err error) (*Receipt, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(v) != receiptSize {
+		return nil, false, fmt.Errorf("%w: %d bytes", ErrInvalidReceipt, len(v))
+	}
+	return &Receipt{
+		ActionID:  ids.ID(v[:ids.IDLen]),
+		TypeID:    v[ids.IDLen],
+		Timestamp: int64(binary.BigEndian.Uint64(v[ids.IDLen+consts.ByteLen:])),
+	}, true, nil
+}
+
+// SetReceipt stores [receipt] for the receipt asset [assetID].
+func SetReceipt(
+	ctx context.Context,
+	mu state.Mutable,
+	assetID ids.ID,
+	receipt *Receipt,
+) error {
+	v := make([]byte, receiptSize)
+	copy(v, receipt.ActionID[:])
+	v[ids.IDLen] = receipt.TypeID
+	binary.BigEndian.PutUint64(v[ids.IDLen+consts.ByteLen:], uint64(receipt.Timestamp))
+	return insertValue(ctx, mu, ReceiptKey(assetID), v)
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// OverflowPolicy controls what [NextSequenceWithPolicy] does once a counter
+// has issued [math.MaxUint64].
+type OverflowPolicy uint8
+
+const (
+	// OverflowError fails with [ErrSequenceOverflow] instead of issuing
+	// [math.MaxUint64]. Use it for IDs that must never repeat.
+	OverflowError OverflowPolicy = iota
+	// OverflowWrap issues [math.MaxUint64] and restarts the counter at 0.
+	OverflowWrap
+)
+
+// [sequencePrefix] + [name]
+func SequenceKey(name []byte) (k []byte) {
+	k = make([]byte, 1+len(name)+consts.Uint16Len)
+	k[0] = sequencePrefix
+	copy(k[1:], name)
+	binary.BigEndian.PutUint16(k[1+len(name):], SequenceChunks)
+	return
+}
+
+// NextSequence returns the next value of the counter stored at [counterKey]
+// and advances it, failing with [ErrSequenceOverflow] once it is exhausted.
+// A counter that has never been used starts at 0.
+func NextSequence(
+	ctx context.Context,
+	mu state.Mutable,
+	counterKey []byte,
+) (uint64, error) {
+	return NextSequenceWithPolicy(ctx, mu, counterKey, OverflowError)
+}
+
+// NextSequenceWithPolicy is [NextSequence] with a caller-chosen [OverflowPolicy].
+//
+// [counterKey] may live under any prefix but must carry a chunk suffix large
+// enough for a uint64 value, as produced by [SequenceKey].
+func NextSequenceWithPolicy(
+	ctx context.Context,
+	mu state.Mutable,
+	counterKey []byte,
+	policy OverflowPolicy,
+) (uint64, error) {
+	if chunks, ok := keys.MaxChunks(counterKey); !ok || chunks < SequenceChunks {
+		return 0, fmt.Errorf("%w: counter key needs %d chunk(s)", ErrInvalidKey, SequenceChunks)
+	}
+	next, err := getSequence(ctx, mu, counterKey)
+	if err != nil {
+		return 0, err
+	}
+	if next == math.MaxUint64 && policy == OverflowError {
+		return 0, fmt.Errorf("%w: key=%x", ErrSequenceOverflow, counterKey)
+	}
+	// Adding 1 to [math.MaxUint64] wraps to 0, which is what [OverflowWrap] wants.
+	return next, insertValue(ctx, mu, counterKey, binary.BigEndian.AppendUint64(nil, next+1))
+}
+
+// PeekSequence returns the value the next call to [NextSequence] on
+// [counterKey] would issue, without advancing it.
+func PeekSequence(
+	ctx context.Context,
+	im state.Immutable,
+	counterKey []byte,
+) (uint64, error) {
+	return getSequence(ctx, im, counterKey)
+}
+
+// Used to serve RPC queries
+func PeekSequenceFromState(
+	ctx context.Context,
+	f ReadState,
+	counterKey []byte,
+) (uint64, error) {
+	values, errs := f(ctx, [][]byte{counterKey})
+	return innerGetSequence(values[0], errs[0])
+}
+
+func getSequence(
+	ctx context.Context,
+	im state.Immutable,
+	counterKey []byte,
+) (uint64, error) {
+	return innerGetSequence(getValue(ctx, im, counterKey))
+}
+
+func innerGetSequence(v []byte, err error) (uint64, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return database.ParseUInt64(v)
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+)
+
+func TestNextSequence(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	store := chaintest.NewInMemoryStore()
+	counter := SequenceKey([]byte("counter"))
+
+	next, err := PeekSequence(ctx, store, counter)
+	require.NoError(err)
+	require.Zero(next)
+	for i := uint64(0); i < 3; i++ {
+		seq, err := NextSequence(ctx, store, counter)
+		require.NoError(err)
+		require.Equal(i, seq)
+	}
+	next, err = PeekSequence(ctx, store, counter)
+	require.NoError(err)
+	require.Equal(uint64(3), next)
+
+	// Counters are independent.
+	seq, err := NextSequence(ctx, store, SequenceKey([]byte("other")))
+	require.NoError(err)
+	require.Zero(seq)
+
+	// A counter key must be able to hold the value.
+	_, err = NextSequence(ctx, store, []byte{sequencePrefix, 0, 0})
+	require.ErrorIs(err, ErrInvalidKey)
+}
+
+func TestSequenceOverflow(t *testing.T) {
+	ctx := context.Background()
+	counter := SequenceKey([]byte("counter"))
+	exhausted := func() *chaintest.InMemoryStore {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, store.Insert(ctx, counter, binary.BigEndian.AppendUint64(nil, math.MaxUint64-1)))
+		return store
+	}
+
+	tests := []struct {
+		name   string
+		policy OverflowPolicy
+		// last is the value issued after [math.MaxUint64], if it is issued.
+		last      uint64
+		expectErr error
+	}{
+		{name: "Error", policy: OverflowError, expectErr: ErrSequenceOverflow},
+		{name: "Wrap", policy: OverflowWrap, last: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			store := exhausted()
+			seq, err := NextSequenceWithPolicy(ctx, store, counter, tt.policy)
+			require.NoError(err)
+			require.Equal(uint64(math.MaxUint64-1), seq)
+
+			if tt.policy == OverflowWrap {
+				seq, err = NextSequenceWithPolicy(ctx, store, counter, tt.policy)
+				require.NoError(err)
+				require.Equal(uint64(math.MaxUint64), seq)
+			}
+			seq, err = NextSequenceWithPolicy(ctx, store, counter, tt.policy)
+			require.ErrorIs(err, tt.expectErr)
+			if tt.expectErr != nil {
+				// The counter stays exhausted.
+				_, err = NextSequenceWithPolicy(ctx, store, counter, tt.policy)
+				require.ErrorIs(err, tt.expectErr)
+				return
+			}
+			require.Equal(tt.last, seq)
+		})
+	}
+}
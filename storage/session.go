@@ -0,0 +1,183 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// MaxSessionActionTypes bounds the action types a session can allow.
+const MaxSessionActionTypes = 16
+
+const sessionFixedSize = codec.AddressLen + consts.Int64Len + consts.Uint64Len + consts.ByteLen
+
+// Session is what an owner has authorized a dapp's session key to do on
+// its behalf.
+type Session struct {
+	// Key is the address of the session key.
+	Key codec.Address `json:"key"`
+	// Expiry is the timestamp, in milliseconds, after which the session is
+	// no longer active.
+	Expiry int64 `json:"expiry"`
+	// SpendCap is the most native tokens the session may spend.
+	SpendCap uint64 `json:"spendCap"`
+	// ActionTypes are the type IDs of the actions the session may send.
+	ActionTypes []uint8 `json:"actionTypes"`
+}
+
+// [sessionPrefix] + [owner] + [sessionID]
+func SessionKey(owner codec.Address, sessionID ids.ID) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+ids.IDLen+consts.Uint16Len)
+	k[0] = sessionPrefix
+	copy(k[1:], owner[:])
+	copy(k[1+codec.AddressLen:], sessionID[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen+ids.IDLen:], SessionChunks)
+	return
+}
+
+func sessionsPrefix(owner codec.Address) []byte {
+	k := make([]byte, 1+codec.AddressLen)
+	k[0] = sessionPrefix
+	copy(k[1:], owner[:])
+	return k
+}
+
+// GetSession returns the session [sessionID] of [owner], expired or not.
+func GetSession(
+	ctx context.Context,
+	im state.Immutable,
+	owner codec.Address,
+	sessionID ids.ID,
+) (*Session, bool, error) {
+	return innerGetSession(getValue(ctx, im, SessionKey(owner, sessionID)))
+}
+
+// Used to serve RPC queries
+func GetSessionFromState(
+	ctx context.Context,
+	f ReadState,
+	owner codec.Address,
+	sessionID ids.ID,
+) (*Session, bool, error) {
+	values, errs := f(ctx, [][]byte{SessionKey(owner, sessionID)})
+	return innerGetSession(values[0], errs[0])
+}
+
+func innerGetSession(v []byte, err error) (*Session, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	session, err := unpackSession(v)
+	if err != nil {
+		return nil, false, err
+	}
+	return session, true, nil
+}
+
+func unpackSession(v []byte) (*Session, error) {
+	if len(v) < sessionFixedSize || len(v) != sessionFixedSize+int(v[sessionFixedSize-1]) {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidSession, len(v))
+	}
+	session := &Session{
+		Key:         codec.Address(v),
+		Expiry:      int64(binary.BigEndian.Uint64(v[codec.AddressLen:])),
+		SpendCap:    binary.BigEndian.Uint64(v[codec.AddressLen+consts.Int64Len:]),
+		ActionTypes: make([]uint8, v[sessionFixedSize-1]),
+	}
+	copy(session.ActionTypes, v[sessionFixedSize:])
+	return session, nil
+}
+
+// SetSession stores [session] for [owner] under [sessionID].
+func SetSession(
+	ctx context.Context,
+	mu state.Mutable,
+	owner codec.Address,
+	sessionID ids.ID,
+	session *Session,
+) error {
+	if len(session.ActionTypes) > MaxSessionActionTypes {
+		return fmt.Errorf("%w: %d action types", ErrInvalidSession, len(session.ActionTypes))
+	}
+	v := make([]byte, sessionFixedSize, sessionFixedSize+len(session.ActionTypes))
+	copy(v, session.Key[:])
+	binary.BigEndian.PutUint64(v[codec.AddressLen:], uint64(session.Expiry))
+	binary.BigEndian.PutUint64(v[codec.AddressLen+consts.Int64Len:], session.SpendCap)
+	v[sessionFixedSize-1] = byte(len(session.ActionTypes))
+	v = append(v, session.ActionTypes...)
+	return insertValue(ctx, mu, SessionKey(owner, sessionID), v)
+}
+
+func DeleteSession(
+	ctx context.Context,
+	mu state.Mutable,
+	owner codec.Address,
+	sessionID ids.ID,
+) error {
+	return Delete(ctx, mu, SessionKey(owner, sessionID))
+}
+
+// Sessions iterates over the sessions of one owner in ID order.
+type Sessions struct {
+	it      database.Iterator
+	session *Session
+	err     error
+}
+
+// GetSessionsByOwner returns an iterator over the sessions of [owner] in
+// [db], expired or not, starting at [start]. Pass [ids.Empty] to start from
+// the first one.
+//
+// The iterator must be released once done.
+func GetSessionsByOwner(db database.Iteratee, owner codec.Address, start ids.ID) *Sessions {
+	prefix := sessionsPrefix(owner)
+	return &Sessions{
+		it: db.NewIteratorWithStartAndPrefix(append(prefix, start[:]...), prefix),
+	}
+}
+
+func (s *Sessions) Next() bool {
+	if s.err != nil || !s.it.Next() {
+		return false
+	}
+	s.session, s.err = unpackSession(s.it.Value())
+	return s.err == nil
+}
+
+// ID returns the current session ID. It is only valid after Next returned
+// true.
+func (s *Sessions) ID() ids.ID {
+	k := s.it.Key()
+	return ids.ID(k[1+codec.AddressLen:])
+}
+
+// Session returns the current session. It is only valid after Next returned
+// true.
+func (s *Sessions) Session() *Session {
+	return s.session
+}
+
+func (s *Sessions) Error() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.it.Error()
+}
+
+func (s *Sessions) Release() {
+	s.it.Release()
+}
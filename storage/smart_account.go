@@ -0,0 +1,252 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// MaxSmartAccountSessions bounds the session keys of a policy.
+const MaxSmartAccountSessions = 4
+
+const (
+	// smartAccountAddressType is not an auth type, so no key can sign for a
+	// [SmartAccountAddress].
+	smartAccountAddressType = 0xfb
+
+	smartAccountSessionSize = codec.AddressLen + consts.Int64Len + consts.Uint64Len
+)
+
+// SmartAccountPolicy governs a [SmartAccountAddress]: [Threshold] of
+// [Owners] approve its transactions, and each of [Sessions] may make native
+// transfers on its own within its cap.
+type SmartAccountPolicy struct {
+	Owners []codec.Address `json:"owners"`
+	// Threshold is how many owners must approve a transaction.
+	Threshold uint8 `json:"threshold"`
+	// MaxSpend is the most native tokens one transaction of the account may
+	// move, or 0 for no limit.
+	MaxSpend uint64                `json:"maxSpend"`
+	Sessions []SmartAccountSession `json:"sessions"`
+}
+
+// SmartAccountSession is a key allowed to spend from a smart account
+// without the approval of its owners.
+type SmartAccountSession struct {
+	Key codec.Address `json:"key"`
+	// Expiry is the timestamp, in milliseconds, after which the key can no
+	// longer spend.
+	Expiry int64 `json:"expiry"`
+	// SpendCap is the most native tokens the key may spend in total.
+	SpendCap uint64 `json:"spendCap"`
+}
+
+func (p *SmartAccountPolicy) Verify() error {
+	// The owners and threshold follow the rules of a multisig, so pending
+	// transactions are approved the same way.
+	owners := &Multisig{Signers: p.Owners, Threshold: p.Threshold}
+	if err := owners.Verify(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSmartAccount, err)
+	}
+	if len(p.Sessions) > MaxSmartAccountSessions {
+		return fmt.Errorf("%w: %d sessions", ErrInvalidSmartAccount, len(p.Sessions))
+	}
+	for i, s := range p.Sessions {
+		if s.Key == codec.EmptyAddress {
+			return fmt.Errorf("%w: session %d is empty", ErrInvalidSmartAccount, i)
+		}
+		if owners.Index(s.Key) >= 0 || p.Session(s.Key) != i {
+			return fmt.Errorf("%w: %s listed twice", ErrInvalidSmartAccount, s.Key)
+		}
+	}
+	return nil
+}
+
+// Owner returns the position of [addr] in the owners, or -1.
+func (p *SmartAccountPolicy) Owner(addr codec.Address) int {
+	return (&Multisig{Signers: p.Owners}).Index(addr)
+}
+
+// Session returns the position of [addr] in the sessions, or -1.
+func (p *SmartAccountPolicy) Session(addr codec.Address) int {
+	for i, s := range p.Sessions {
+		if s.Key == addr {
+			return i
+		}
+	}
+	return -1
+}
+
+// Size is the length of [Bytes].
+func (p *SmartAccountPolicy) Size() int {
+	return 2 + len(p.Owners)*codec.AddressLen + consts.Uint64Len + consts.ByteLen + len(p.Sessions)*smartAccountSessionSize
+}
+
+// Bytes encodes the policy as DeploySmartAccount takes it:
+// threshold|ownerCount|owners|maxSpend|sessionCount|sessions, where each
+// session is key|expiry|spendCap.
+func (p *SmartAccountPolicy) Bytes() []byte {
+	v := make([]byte, 0, p.Size())
+	v = append(v, p.Threshold, byte(len(p.Owners)))
+	for _, o := range p.Owners {
+		v = append(v, o[:]...)
+	}
+	v = binary.BigEndian.AppendUint64(v, p.MaxSpend)
+	v = append(v, byte(len(p.Sessions)))
+	for _, s := range p.Sessions {
+		v = append(v, s.Key[:]...)
+		v = binary.BigEndian.AppendUint64(v, uint64(s.Expiry))
+		v = binary.BigEndian.AppendUint64(v, s.SpendCap)
+	}
+	return v
+}
+
+// ParseSmartAccountPolicy decodes a policy encoded by [SmartAccountPolicy.Bytes]
+// and verifies it.
+func ParseSmartAccountPolicy(b []byte) (*SmartAccountPolicy, error) {
+	p, n, err := parseSmartAccountPolicy(b)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(b) {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidSmartAccount, len(b)-n)
+	}
+	return p, p.Verify()
+}
+
+// parseSmartAccountPolicy decodes the policy at the start of [b] and
+// returns its length.
+func parseSmartAccountPolicy(b []byte) (*SmartAccountPolicy, int, error) {
+	if len(b) < 2 {
+		return nil, 0, fmt.Errorf("%w: %d bytes", ErrInvalidSmartAccount, len(b))
+	}
+	p := &SmartAccountPolicy{
+		Threshold: b[0],
+		Owners:    make([]codec.Address, b[1]),
+	}
+	offset := 2
+	if len(b) < offset+len(p.Owners)*codec.AddressLen+consts.Uint64Len+consts.ByteLen {
+		return nil, 0, fmt.Errorf("%w: %d bytes", ErrInvalidSmartAccount, len(b))
+	}
+	for i := range p.Owners {
+		p.Owners[i] = codec.Address(b[offset:])
+		offset += codec.AddressLen
+	}
+	p.MaxSpend = binary.BigEndian.Uint64(b[offset:])
+	offset += consts.Uint64Len
+	p.Sessions = make([]SmartAccountSession, b[offset])
+	offset += consts.ByteLen
+	if len(b) < offset+len(p.Sessions)*smartAccountSessionSize {
+		return nil, 0, fmt.Errorf("%w: %d bytes", ErrInvalidSmartAccount, len(b))
+	}
+	for i := range p.Sessions {
+		p.Sessions[i] = SmartAccountSession{
+			Key:      codec.Address(b[offset:]),
+			Expiry:   int64(binary.BigEndian.Uint64(b[offset+codec.AddressLen:])),
+			SpendCap: binary.BigEndian.Uint64(b[offset+codec.AddressLen+consts.Int64Len:]),
+		}
+		offset += smartAccountSessionSize
+	}
+	return p, offset, nil
+}
+
+// SmartAccount is the policy of a [SmartAccountAddress] and what each of
+// its sessions has spent.
+type SmartAccount struct {
+	Policy *SmartAccountPolicy `json:"policy"`
+	// Spent holds, for each session of the policy, the native tokens it
+	// has spent.
+	Spent []uint64 `json:"spent"`
+}
+
+// SmartAccountAddress holds the funds of the smart account [creator]
+// deploys with [nonce]. Funds only leave it as its policy allows.
+func SmartAccountAddress(creator codec.Address, nonce uint64) codec.Address {
+	b := make([]byte, codec.AddressLen+consts.Uint64Len)
+	copy(b, creator[:])
+	binary.BigEndian.PutUint64(b[codec.AddressLen:], nonce)
+	return codec.CreateAddress(smartAccountAddressType, utils.ToID(b))
+}
+
+// [smartAccountPrefix] + [account]
+func SmartAccountKey(account codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+consts.Uint16Len)
+	k[0] = smartAccountPrefix
+	copy(k[1:], account[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen:], SmartAccountChunks)
+	return
+}
+
+// GetSmartAccount returns the policy of [account], if it is a smart account.
+func GetSmartAccount(
+	ctx context.Context,
+	im state.Immutable,
+	account codec.Address,
+) (*SmartAccount, bool, error) {
+	return innerGetSmartAccount(getValue(ctx, im, SmartAccountKey(account)))
+}
+
+// Used to serve RPC queries
+func GetSmartAccountFromState(
+	ctx context.Context,
+	f ReadState,
+	account codec.Address,
+) (*SmartAccount, bool, error) {
+	values, errs := f(ctx, [][]byte{SmartAccountKey(account)})
+	return innerGetSmartAccount(values[0], errs[0])
+}
+
+func innerGetSmartAccount(v []byte, err error) (*SmartAccount, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	p, offset, err := parseSmartAccountPolicy(v)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(v) != offset+len(p.Sessions)*consts.Uint64Len {
+		return nil, false, fmt.Errorf("%w: %d bytes", ErrInvalidSmartAccount, len(v))
+	}
+	a := &SmartAccount{
+		Policy: p,
+		Spent:  make([]uint64, len(p.Sessions)),
+	}
+	for i := range a.Spent {
+		a.Spent[i] = binary.BigEndian.Uint64(v[offset+i*consts.Uint64Len:])
+	}
+	return a, true, nil
+}
+
+// SetSmartAccount stores [a] as the policy of [account].
+func SetSmartAccount(
+	ctx context.Context,
+	mu state.Mutable,
+	account codec.Address,
+	a *SmartAccount,
+) error {
+	if err := a.Policy.Verify(); err != nil {
+		return err
+	}
+	if len(a.Spent) != len(a.Policy.Sessions) {
+		return fmt.Errorf("%w: %d spent amounts for %d sessions", ErrInvalidSmartAccount, len(a.Spent), len(a.Policy.Sessions))
+	}
+	v := a.Policy.Bytes()
+	for _, spent := range a.Spent {
+		v = binary.BigEndian.AppendUint64(v, spent)
+	}
+	return insertValue(ctx, mu, SmartAccountKey(account), v)
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const (
+	stablecoinConfig = 0x0
+	stablecoinBlock  = 0x1
+
+	stablecoinValueSize = 3*codec.AddressLen + consts.BoolLen + consts.Uint64Len + consts.Int64Len + ids.IDLen
+)
+
+// stablecoinBlockedValue marks an account on a blocklist. The key carries
+// all the information.
+var stablecoinBlockedValue = []byte{1}
+
+// Stablecoin is the issuer state of a fungible asset backed by off-chain
+// reserves. Each role is held by one address.
+type Stablecoin struct {
+	// Issuer mints and burns the stablecoin.
+	Issuer codec.Address `json:"issuer"`
+	// Attestor reports the reserves backing the stablecoin.
+	Attestor codec.Address `json:"attestor"`
+	// Compliance maintains the blocklist.
+	Compliance codec.Address `json:"compliance"`
+
+	// Paused stops every transfer of the stablecoin, and minting.
+	Paused bool `json:"paused"`
+
+	// Reserves is the backing last attested, in base units of the
+	// stablecoin. Minting never takes the supply above it.
+	Reserves uint64 `json:"reserves"`
+	// AttestedAt is the timestamp, in milliseconds, of the last attestation.
+	AttestedAt int64 `json:"attestedAt"`
+	// Report is the hash of the attestor's off-chain report.
+	Report ids.ID `json:"report"`
+}
+
+// [stablecoinPrefix] + [stablecoinConfig] + [assetID]
+func StablecoinKey(assetID ids.ID) (k []byte) {
+	k = make([]byte, 2+ids.IDLen+consts.Uint16Len)
+	k[0] = stablecoinPrefix
+	k[1] = stablecoinConfig
+	copy(k[2:], assetID[:])
+	binary.BigEndian.PutUint16(k[2+ids.IDLen:], StablecoinChunks)
+	return
+}
+
+// [stablecoinPrefix] + [stablecoinBlock] + [assetID] + [account]
+func StablecoinBlockKey(assetID ids.ID, account codec.Address) (k []byte) {
+	k = make([]byte, 2+ids.IDLen+codec.AddressLen+consts.Uint16Len)
+	k[0] = stablecoinPrefix
+	k[1] = stablecoinBlock
+	copy(k[2:], assetID[:])
+	copy(k[2+ids.IDLen:], account[:])
+	binary.BigEndian.PutUint16(k[2+ids.IDLen+codec.AddressLen:], StablecoinBlockChunks)
+	return
+}
+
+// GetStablecoin returns the stablecoin state of [assetID], if it is one.
+func GetStablecoin(
+	ctx context.Context,
+	im state.Immutable,
+	assetID ids.ID,
+) (*Stablecoin, bool, error) {
+	return innerGetStablecoin(getValue(ctx, im, StablecoinKey(assetID)))
+}
+
+// Used to serve RPC queries
+func GetStablecoinFromState(
+	ctx context.Context,
+	f ReadState,
+	assetID ids.ID,
+) (*Stablecoin, bool, error) {
+	values, errs := f(ctx, [][]byte{StablecoinKey(assetID)})
+	return innerGetStablecoin(values[0], errs[0])
+}
+
+// GetStablecoinSupplyFromState returns the stablecoin state of [assetID]
+// and its supply, read in one pass so both are from the same height.
+func GetStablecoinSupplyFromState(
+	ctx context.Context,
+	f ReadState,
+	assetID ids.ID,
+) (*Stablecoin, uint64, bool, error) {
+	values, errs := f(ctx, [][]byte{StablecoinKey(assetID), AssetKey(assetID)})
+	s, exists, err := innerGetStablecoin(values[0], errs[0])
+	if err != nil || !exists {
+		return nil, 0, false, err
+	}
+	_, m, _, _, err := innerGetAsset(values[1], errs[1])
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return s, m.TotalSupply, true, nil
+}
+
+func innerGetStablecoin(v []byte, err error) (*Stablecoin, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(v) != stablecoinValueSize {
+		return nil, false, fmt.Errorf("%w: %d bytes", ErrInvalidStablecoin, len(v))
+	}
+	s := &Stablecoin{
+		Issuer:     codec.Address(v[:codec.AddressLen]),
+		Attestor:   codec.Address(v[codec.AddressLen : 2*codec.AddressLen]),
+		Compliance: codec.Address(v[2*codec.AddressLen : 3*codec.AddressLen]),
+	}
+	v = v[3*codec.AddressLen:]
+	s.Paused = v[0] == 1
+	v = v[consts.BoolLen:]
+	s.Reserves = binary.BigEndian.Uint64(v)
+	s.AttestedAt = int64(binary.BigEndian.Uint64(v[consts.Uint64Len:]))
+	s.Report = ids.ID(v[consts.Uint64Len+consts.Int64Len:])
+	return s, true, nil
+}
+
+func SetStablecoin(
+	ctx context.Context,
+	mu state.Mutable,
+	assetID ids.ID,
+	s *Stablecoin,
+) error {
+	v := make([]byte, 0, stablecoinValueSize)
+	v = append(v, s.Issuer[:]...)
+	v = append(v, s.Attestor[:]...)
+	v = append(v, s.Compliance[:]...)
+	if s.Paused {
+		v = append(v, 1)
+	} else {
+		v = append(v, 0)
+	}
+	v = binary.BigEndian.AppendUint64(v, s.Reserves)
+	v = binary.BigEndian.AppendUint64(v, uint64(s.AttestedAt))
+	v = append(v, s.Report[:]...)
+	return insertValue(ctx, mu, StablecoinKey(assetID), v)
+}
+
+// IsStablecoinBlocked returns whether [account] is on the blocklist of
+// [assetID].
+func IsStablecoinBlocked(
+	ctx context.Context,
+	im state.Immutable,
+	assetID ids.ID,
+	account codec.Address,
+) (bool, error) {
+	return innerIsStablecoinBlocked(getValue(ctx, im, StablecoinBlockKey(assetID, account)))
+}
+
+// Used to serve RPC queries
+func IsStablecoinBlockedFromState(
+	ctx context.Context,
+	f ReadState,
+	assetID ids.ID,
+	account codec.Address,
+) (bool, error) {
+	values, errs := f(ctx, [][]byte{StablecoinBlockKey(assetID, account)})
+	return innerIsStablecoinBlocked(values[0], errs[0])
+}
+
+func innerIsStablecoinBlocked(_ []byte, err error) (bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// SetStablecoinBlocked adds [account] to the blocklist of [assetID], or
+// removes it.
+func SetStablecoinBlocked(
+	ctx context.Context,
+	mu state.Mutable,
+	assetID ids.ID,
+	account codec.Address,
+	blocked bool,
+) error {
+	k := StablecoinBlockKey(assetID, account)
+	if !blocked {
+		return Delete(ctx, mu, k)
+	}
+	return insertValue(ctx, mu, k, stablecoinBlockedValue)
+}
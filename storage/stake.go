@@ -0,0 +1,124 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const stakeValueSize = 4 * consts.Uint64Len
+
+// Stake is the native balance an address has staked, held by the record
+// itself, and the rewards it has accrued.
+type Stake struct {
+	// Amount has been staked since [Epoch] began.
+	Amount uint64 `json:"amount"`
+	// Pending was staked during [Epoch], so it only earns from the epoch
+	// after it.
+	Pending uint64 `json:"pending"`
+	// Rewards have accrued up to [Epoch] and are not claimed yet.
+	Rewards uint64 `json:"rewards"`
+	Epoch   uint64 `json:"epoch"`
+}
+
+// Staked returns all the native balance held by the stake, earning or not.
+func (s *Stake) Staked() uint64 {
+	return s.Amount + s.Pending
+}
+
+// [stakePrefix] + [staker]
+func StakeKey(staker codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+consts.Uint16Len)
+	k[0] = stakePrefix
+	copy(k[1:], staker[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen:], StakeChunks)
+	return
+}
+
+// GetStake returns the stake of [staker], if any.
+func GetStake(
+	ctx context.Context,
+	im state.Immutable,
+	staker codec.Address,
+) (*Stake, bool, error) {
+	return innerGetStake(getValue(ctx, im, StakeKey(staker)))
+}
+
+// Used to serve RPC queries
+func GetStakeFromState(
+	ctx context.Context,
+	f ReadState,
+	staker codec.Address,
+) (*Stake, bool, error) {
+	values, errs := f(ctx, [][]byte{StakeKey(staker)})
+	return innerGetStake(values[0], errs[0])
+}
+
+func innerGetStake(v []byte, err error) (*Stake, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(v) != stakeValueSize {
+		return nil, false, fmt.Errorf("%w: %d bytes", ErrInvalidStake, len(v))
+	}
+	s := &Stake{
+		Amount:  binary.BigEndian.Uint64(v),
+		Pending: binary.BigEndian.Uint64(v[consts.Uint64Len:]),
+		Rewards: binary.BigEndian.Uint64(v[2*consts.Uint64Len:]),
+		Epoch:   binary.BigEndian.Uint64(v[3*consts.Uint64Len:]),
+	}
+	return s, true, nil
+}
+
+// SetStake stores [s] as the stake of [staker].
+func SetStake(
+	ctx context.Context,
+	mu state.Mutable,
+	staker codec.Address,
+	s *Stake,
+) error {
+	v := make([]byte, stakeValueSize)
+	binary.BigEndian.PutUint64(v, s.Amount)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len:], s.Pending)
+	binary.BigEndian.PutUint64(v[2*consts.Uint64Len:], s.Rewards)
+	binary.BigEndian.PutUint64(v[3*consts.Uint64Len:], s.Epoch)
+	return insertValue(ctx, mu, StakeKey(staker), v)
+}
+
+func DeleteStake(
+	ctx context.Context,
+	mu state.Mutable,
+	staker codec.Address,
+) error {
+	return Delete(ctx, mu, StakeKey(staker))
+}
+
+// GetHeight returns the height stored in state. While a block executes, that
+// is still the height of its parent, as the height key is only advanced
+// once all of its transactions have run.
+//
+// Callers must declare chain.HeightKey([HeightKey]) with [state.Read].
+func GetHeight(
+	ctx context.Context,
+	im state.Immutable,
+) (uint64, error) {
+	v, err := getValue(ctx, im, chain.HeightKey(HeightKey()))
+	if err != nil {
+		return 0, err
+	}
+	return database.ParseUInt64(v)
+}
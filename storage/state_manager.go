@@ -29,7 +29,8 @@ func (*StateManager) FeeKey() []byte {
 
 func (*StateManager) SponsorStateKeys(addr codec.Address) state.Keys {
 	return state.Keys{
-		string(BalanceKey(addr)): state.Read | state.Write,
+		string(BalanceKey(addr)):    state.Read | state.Write,
+		string(ActiveProposalKey()): state.Read,
 	}
 }
 
@@ -8,13 +8,16 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 
+	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
 
 	smath "github.com/ava-labs/avalanchego/utils/math"
 )
@@ -25,24 +28,211 @@ type ReadState func(context.Context, [][]byte) ([][]byte, []error)
 // / (height) => store in root
 //   -> [heightPrefix] => height
 // 0x0/ (balance)
-//   -> [owner] => balance
+//   -> [owner] => balance[|snapshotHeight|snapshot]
 // 0x1/ (hypersdk-height)
 // 0x2/ (hypersdk-timestamp)
 // 0x3/ (hypersdk-fee)
 // 0x4/ (hypersdk-asset)
-//   -> [assetID] => owner
+//   -> [assetID] => owner|metadata
+// 0x5/ (sequence)
+//   -> [name] => next value
+// 0x6/ (tombstone)
+//   -> [key] => deletion height
+// 0x7/ (asset balance)
+//   -> [owner] + [assetID] => balance
+// 0x8/ (notification prefs)
+//   -> [owner] => target|eventMask
+// 0x9/ (allowance)
+//   -> [owner] + [spender] => allowance
+// 0xa/ (treasury)
+//   -> 0x0 => threshold|members
+//   -> 0x1 + [proposalID] => to|amount|expiry|approvals
+// 0xb/ (owned assets)
+//   -> [owner] + [assetID] => 0x1
+// 0xc/ (transfer hooks)
+//   -> [assetID] => registrar|kind|target
+// 0xd/ (swaps)
+//   -> [swapID] => proposer|counterparty|expiry|offer|want
+// 0xe/ (vestings)
+//   -> [beneficiary] + [vestingID] => creator|amount|release
+// 0xf/ (escrows)
+//   -> [escrowID] => payer|counterparty|amount|deadline
+// 0x10/ (orders)
+//   -> [sellAsset] + [buyAsset] + [orderID] => maker|sellAmount|buyAmount|remaining
+// 0x11/ (pools)
+//   -> 0x0 + [assetA] + [assetB] => reserveA|reserveB|shares
+//   -> 0x1 + [assetA] + [assetB] + [provider] => shares
+// 0x12/ (stakes)
+//   -> [staker] => amount|rewards|epoch
+// 0x13/ (governance)
+//   -> 0x0 => proposalID|snapshotHeight|votingEnd
+//   -> 0x1 + [proposalID] => proposer|snapshotHeight|votingEnd|yes|no|text|changes
+//   -> 0x2 + [proposalID] + [voter] => support|weight
+// 0x14/ (multisigs)
+//   -> 0x0 + [multisig] => threshold|signers
+//   -> 0x1 + [multisig] + [proposalID] => expiry|approvals|to|asset|value
+//      (also the pending transactions of smart accounts)
+// 0x15/ (maintenance)
+//   -> 0x0 => compaction height
+// 0x16/ (claims)
+//   -> [owner] + [key] => expiry|value
+// 0x17/ (sessions)
+//   -> [owner] + [sessionID] => key|expiry|spendCap|actionTypes
+// 0x18/ (receipts)
+//   -> [assetID] => actionID|typeID|timestamp
+// 0x19/ (stablecoins)
+//   -> 0x0 + [assetID] => issuer|attestor|compliance|paused|reserves|attestedAt|report
+//   -> 0x1 + [assetID] + [account] => 0x1
+// 0x1a/ (chain halt)
+//   -> 0x0 => incident|haltedAt|resumeApprovals
+//   -> 0x1 => security council
+//   -> 0x2 + [incident] => approvals
+// 0x1b/ (config)
+//   -> [key] => value
+// 0x1c/ (smart accounts)
+//   -> [account] => policy|spent
+// 0x1d/ (leaderboards)
+//   -> [boardID] => creator|size|expiry|entries
+// 0x1e/ (lockers)
+//   -> [lockerID] => legs|items
+// 0x1f/ (used permits)
+//   -> [owner] + [nonce] => 0x1
+// 0x20/ (auctions)
+//   -> [assetID] => seller|end|minBid|bidder|bid
+// 0x21/ (apps)
+//   -> 0x0 + [appID] => admin
+//   -> 0x1 + [appID] + [creator] => 0x1
+//   -> 0x2 + [appID] + [assetID] => 0x1
 
 const (
 	// Active state
-	balancePrefix   = 0x0
-	heightPrefix    = 0x1
-	timestampPrefix = 0x2
-	feePrefix       = 0x3
-	assetPrefix     = 0x4
+	balancePrefix      = 0x0
+	heightPrefix       = 0x1
+	timestampPrefix    = 0x2
+	feePrefix          = 0x3
+	assetPrefix        = 0x4
+	sequencePrefix     = 0x5
+	tombstonePrefix    = 0x6
+	assetBalancePrefix = 0x7
+	notificationPrefix = 0x8
+	allowancePrefix    = 0x9
+	treasuryPrefix     = 0xa
+	ownedAssetPrefix   = 0xb
+	transferHookPrefix = 0xc
+	swapPrefix         = 0xd
+	vestingPrefix      = 0xe
+	escrowPrefix       = 0xf
+	orderBookPrefix    = 0x10
+	poolPrefix         = 0x11
+	stakePrefix        = 0x12
+	governancePrefix   = 0x13
+	multisigPrefix     = 0x14
+	maintenancePrefix  = 0x15
+	claimPrefix        = 0x16
+	sessionPrefix      = 0x17
+	receiptPrefix      = 0x18
+	stablecoinPrefix   = 0x19
+	haltPrefix         = 0x1a
+	configPrefix       = 0x1b
+	smartAccountPrefix = 0x1c
+	leaderboardPrefix  = 0x1d
+	lockerPrefix       = 0x1e
+	permitPrefix       = 0x1f
+	auctionPrefix      = 0x20
+	appPrefix          = 0x21
 )
 
+var prefixNames = map[byte]string{
+	balancePrefix:      "balance",
+	heightPrefix:       "height",
+	timestampPrefix:    "timestamp",
+	feePrefix:          "fee",
+	assetPrefix:        "asset",
+	sequencePrefix:     "sequence",
+	tombstonePrefix:    "tombstone",
+	assetBalancePrefix: "asset_balance",
+	notificationPrefix: "notification",
+	allowancePrefix:    "allowance",
+	treasuryPrefix:     "treasury",
+	ownedAssetPrefix:   "owned_asset",
+	transferHookPrefix: "transfer_hook",
+	swapPrefix:         "swap",
+	vestingPrefix:      "vesting",
+	escrowPrefix:       "escrow",
+	orderBookPrefix:    "order",
+	poolPrefix:         "pool",
+	stakePrefix:        "stake",
+	governancePrefix:   "governance",
+	multisigPrefix:     "multisig",
+	maintenancePrefix:  "maintenance",
+	claimPrefix:        "claim",
+	sessionPrefix:      "session",
+	receiptPrefix:      "receipt",
+	stablecoinPrefix:   "stablecoin",
+	haltPrefix:         "halt",
+	configPrefix:       "config",
+	smartAccountPrefix: "smart_account",
+	leaderboardPrefix:  "leaderboard",
+	lockerPrefix:       "locker",
+	permitPrefix:       "permit",
+	auctionPrefix:      "auction",
+	appPrefix:          "app",
+}
+
+// PrefixName names the record type of [key], or returns "unknown".
+func PrefixName(key []byte) string {
+	if len(key) > 0 {
+		if name, ok := prefixNames[key[0]]; ok {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// chunkSize is the size of a value chunk counted by keys.NumChunks.
+const chunkSize = 64
+
 const BalanceChunks uint16 = 1
-const AssetChunks uint16 = 1
+const AssetChunks uint16 = maxAssetValueSize/chunkSize + 1 // keys.NumChunks of maxAssetValueSize bytes
+const SequenceChunks uint16 = 1
+const TombstoneChunks uint16 = 1
+const AssetBalanceChunks uint16 = 1
+const NotificationChunks uint16 = 1
+const AllowanceChunks uint16 = 1
+const TreasuryCouncilChunks uint16 = 9 // MaxCouncilSize members
+const TreasuryProposalChunks uint16 = 1
+const OwnedAssetChunks uint16 = 1
+const TransferHookChunks uint16 = 2
+const SwapChunks uint16 = 7 // MaxSwapLegs legs per side
+const VestingChunks uint16 = 1
+const EscrowChunks uint16 = 2
+const OrderChunks uint16 = 1
+const PoolChunks uint16 = 1
+const PoolSharesChunks uint16 = 1
+const StakeChunks uint16 = 1
+const ActiveProposalChunks uint16 = 1
+const GovernanceProposalChunks uint16 = 8 // MaxProposalTextSize and MaxParameterChanges, without addresses
+const GovernanceVoteChunks uint16 = 1
+const MultisigChunks uint16 = 9 // MaxMultisigSigners signers
+const MultisigProposalChunks uint16 = 2
+const CompactionChunks uint16 = 1
+const ClaimChunks uint16 = 5   // MaxClaimValueSize bytes
+const SessionChunks uint16 = 2 // MaxSessionActionTypes action types
+const ReceiptChunks uint16 = 1
+const StablecoinChunks uint16 = 3
+const StablecoinBlockChunks uint16 = 1
+const HaltChunks uint16 = 1
+const SecurityCouncilChunks uint16 = 9 // MaxCouncilSize members
+const HaltProposalChunks uint16 = 1
+const ConfigChunks uint16 = 1        // MaxConfigValueSize
+const SmartAccountChunks uint16 = 12 // MaxMultisigSigners owners and MaxSmartAccountSessions sessions
+const LeaderboardChunks uint16 = 13  // MaxLeaderboardSize entries
+const LockerChunks uint16 = 10       // MaxLockerLegs legs and MaxLockerItems items
+const PermitChunks uint16 = 1
+const AuctionChunks uint16 = 2
+const AppChunks uint16 = 1
+const AppCreatorChunks uint16 = 1
+const AppAssetChunks uint16 = 1
 
 var (
 	heightKey    = []byte{heightPrefix}
@@ -61,6 +251,25 @@ func AssetKey(assetID ids.ID) (k []byte) {
 	return
 }
 
+// AssetID is the ID of the asset [creator] creates with [nonce]. It starts
+// with [assetPrefix], so it differs from the IDs other records derive from
+// the same creator and nonce.
+func AssetID(creator codec.Address, nonce uint64) ids.ID {
+	b := make([]byte, 1+codec.AddressLen+consts.Uint64Len)
+	b[0] = assetPrefix
+	copy(b[1:], creator[:])
+	binary.BigEndian.PutUint64(b[1+codec.AddressLen:], nonce)
+	return utils.ToID(b)
+}
+
+// ParseAssetKey returns the asset ID of a key built by [AssetKey].
+func ParseAssetKey(k []byte) (ids.ID, bool) {
+	if len(k) != 1+ids.IDLen+consts.Uint16Len || k[0] != assetPrefix {
+		return ids.Empty, false
+	}
+	return ids.ID(k[1 : 1+ids.IDLen]), true
+}
+
 func GetAssetOwner(
 	ctx context.Context,
 	im state.Immutable,
@@ -76,7 +285,7 @@ func getAssetOwner(
 	assetID ids.ID,
 ) ([]byte, codec.Address, bool, error) {
 	k := AssetKey(assetID)
-	owner, exists, err := innerGetAssetOwner(im.GetValue(ctx, k))
+	owner, exists, err := innerGetAssetOwner(getValue(ctx, im, k))
 	return k, owner, exists, err
 }
 
@@ -84,17 +293,25 @@ func innerGetAssetOwner(
 	v []byte,
 	err error,
 ) (codec.Address, bool, error) {
+	owner, _, _, exists, err := innerGetAsset(v, err)
+	return owner, exists, err
+}
+
+func innerGetAsset(
+	v []byte,
+	err error,
+) (codec.Address, AssetMetadata, AssetControl, bool, error) {
 	if errors.Is(err, database.ErrNotFound) {
-		return codec.EmptyAddress, false, nil
+		return codec.EmptyAddress, AssetMetadata{}, AssetControl{}, false, nil
 	}
 	if err != nil {
-		return codec.EmptyAddress, false, err
+		return codec.EmptyAddress, AssetMetadata{}, AssetControl{}, false, err
 	}
-	val, err := codec.ToAddress(v)
+	owner, metadata, control, err := unpackAsset(v)
 	if err != nil {
-		return codec.EmptyAddress, false, err
+		return codec.EmptyAddress, AssetMetadata{}, AssetControl{}, false, err
 	}
-	return val, true, nil
+	return owner, metadata, control, true, nil
 }
 
 func GetAssetOwnerFromState(
@@ -108,27 +325,156 @@ func GetAssetOwnerFromState(
 	return owner, err
 }
 
+// GetAssetOwnersFromState reads the owners of [assetIDs] in one read, so
+// they are all from the same height. Missing assets have an empty owner.
+func GetAssetOwnersFromState(
+	ctx context.Context,
+	f ReadState,
+	assetIDs []ids.ID,
+) ([]codec.Address, error) {
+	keys := make([][]byte, len(assetIDs))
+	for i, assetID := range assetIDs {
+		keys[i] = AssetKey(assetID)
+	}
+	values, errs := f(ctx, keys)
+	owners := make([]codec.Address, len(assetIDs))
+	for i := range assetIDs {
+		owner, _, err := innerGetAssetOwner(values[i], errs[i])
+		if err != nil {
+			return nil, err
+		}
+		owners[i] = owner
+	}
+	return owners, nil
+}
+
+// SetAssetOwner replaces the owner stored at [key], keeping any metadata.
 func SetAssetOwner(
 	ctx context.Context,
 	mu state.Mutable,
 	key []byte,
 	newowner codec.Address,
 ) error {
-	byteNewOwner, err := newowner.MarshalText()
+	v, err := getValue(ctx, mu, key)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return err
+	}
+	if len(v) <= codec.AddressLen {
+		return insertValue(ctx, mu, key, newowner[:])
+	}
+	v = slices.Clone(v)
+	copy(v, newowner[:])
+	return insertValue(ctx, mu, key, v)
+}
+
+// CreateAsset assigns [owner] to a new asset. It returns [ErrAssetExists] if
+// [assetID] is already in state.
+//
+// Callers must declare [OwnedAssetKey] of [owner] with [state.Allocate] and
+// [state.Write].
+func CreateAsset(
+	ctx context.Context,
+	mu state.Mutable,
+	assetID ids.ID,
+	owner codec.Address,
+) error {
+	return CreateAssetWithControl(ctx, mu, assetID, AssetControl{Minter: owner}, owner)
+}
+
+// CreateSoulboundAsset is [CreateAsset] for an asset that can never be
+// transferred.
+func CreateSoulboundAsset(
+	ctx context.Context,
+	mu state.Mutable,
+	assetID ids.ID,
+	owner codec.Address,
+) error {
+	return CreateAssetWithControl(ctx, mu, assetID, AssetControl{Minter: owner, Soulbound: true}, owner)
+}
+
+// CreateAssetWithControl is [CreateAsset] for an asset with the issuer state
+// [c], such as one that expires.
+//
+// An asset ID is never reused: creating an asset that was deleted fails with
+// [ErrAssetBurned]. Callers must declare the [TombstoneKey] of its
+// [AssetKey] with [state.Read].
+func CreateAssetWithControl(
+	ctx context.Context,
+	mu state.Mutable,
+	assetID ids.ID,
+	c AssetControl,
+	owner codec.Address,
+) error {
+	key, _, exists, err := getAssetOwner(ctx, mu, assetID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%w: %s", ErrAssetExists, assetID)
+	}
+	_, burned, err := GetTombstone(ctx, mu, key)
+	if err != nil {
+		return err
+	}
+	if burned {
+		return fmt.Errorf("%w: %s", ErrAssetBurned, assetID)
+	}
+	v, err := packAsset(owner, AssetMetadata{}, c)
+	if err != nil {
+		return err
+	}
+	if err := insertValue(ctx, mu, key, v); err != nil {
+		return err
+	}
+	return addOwnedAsset(ctx, mu, owner, assetID)
+}
+
+// DeleteAsset removes [assetID] from state, leaving a tombstone. It returns
+// [ErrAssetNotFound] if the asset does not exist.
+//
+// Callers must declare [OwnedAssetKey] of the owner with [state.Write].
+func DeleteAsset(
+	ctx context.Context,
+	mu state.Mutable,
+	assetID ids.ID,
+) error {
+	key, owner, exists, err := getAssetOwner(ctx, mu, assetID)
 	if err != nil {
-		return mu.Insert(ctx, key, byteNewOwner)
+		return err
 	}
-	return err
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrAssetNotFound, assetID)
+	}
+	if err := Delete(ctx, mu, key); err != nil {
+		return err
+	}
+	return removeOwnedAsset(ctx, mu, owner, assetID)
 }
 
+// ChangeAssetOwner hands [assetID] to [newOwner].
+//
+// Callers must declare [OwnedAssetKey] of the current owner with
+// [state.Write], and of [newOwner] with [state.Allocate] and [state.Write].
 func ChangeAssetOwner(
 	ctx context.Context,
 	mu state.Mutable,
 	assetID ids.ID,
 	newOwner codec.Address,
 ) error {
-	k := AssetKey(assetID)
-	return SetAssetOwner(ctx, mu, k, newOwner)
+	k, oldOwner, exists, err := getAssetOwner(ctx, mu, assetID)
+	if err != nil {
+		return err
+	}
+	if err := SetAssetOwner(ctx, mu, k, newOwner); err != nil {
+		return err
+	}
+	if !exists || oldOwner == newOwner {
+		return addOwnedAsset(ctx, mu, newOwner, assetID)
+	}
+	if err := removeOwnedAsset(ctx, mu, oldOwner, assetID); err != nil {
+		return err
+	}
+	return addOwnedAsset(ctx, mu, newOwner, assetID)
 }
 
 // [balancePrefix] + [address]
@@ -140,6 +486,14 @@ func BalanceKey(addr codec.Address) (k []byte) {
 	return
 }
 
+// ParseBalanceKey returns the address of a key built by [BalanceKey].
+func ParseBalanceKey(k []byte) (codec.Address, bool) {
+	if len(k) != 1+codec.AddressLen+consts.Uint16Len || k[0] != balancePrefix {
+		return codec.EmptyAddress, false
+	}
+	return codec.Address(k[1 : 1+codec.AddressLen]), true
+}
+
 // If locked is 0, then account does not exist
 func GetBalance(
 	ctx context.Context,
@@ -147,16 +501,34 @@ func GetBalance(
 	addr codec.Address,
 ) (uint64, error) {
 	_, bal, _, err := getBalance(ctx, im, addr)
-	return bal, err
+	return bal.balance, err
+}
+
+// nativeBalance is the value under [BalanceKey]. While a governance proposal
+// is active, the first change to a balance also records the balance it
+// replaced as [snapshot], tagged with the [snapshotHeight] of the proposal.
+type nativeBalance struct {
+	balance        uint64
+	snapshotHeight uint64
+	snapshot       uint64
+}
+
+func (b nativeBalance) bytes() []byte {
+	v := binary.BigEndian.AppendUint64(nil, b.balance)
+	if b.snapshotHeight == 0 {
+		return v
+	}
+	v = binary.BigEndian.AppendUint64(v, b.snapshotHeight)
+	return binary.BigEndian.AppendUint64(v, b.snapshot)
 }
 
 func getBalance(
 	ctx context.Context,
 	im state.Immutable,
 	addr codec.Address,
-) ([]byte, uint64, bool, error) {
+) ([]byte, nativeBalance, bool, error) {
 	k := BalanceKey(addr)
-	bal, exists, err := innerGetBalance(im.GetValue(ctx, k))
+	bal, exists, err := innerGetNativeBalance(getValue(ctx, im, k))
 	return k, bal, exists, err
 }
 
@@ -168,8 +540,61 @@ func GetBalanceFromState(
 ) (uint64, error) {
 	k := BalanceKey(addr)
 	values, errs := f(ctx, [][]byte{k})
-	bal, _, err := innerGetBalance(values[0], errs[0])
-	return bal, err
+	bal, _, err := innerGetNativeBalance(values[0], errs[0])
+	return bal.balance, err
+}
+
+// GetBalancesFromState reads the native balances of [addrs] in one read,
+// so they are all from the same height.
+func GetBalancesFromState(
+	ctx context.Context,
+	f ReadState,
+	addrs []codec.Address,
+) ([]uint64, error) {
+	keys := make([][]byte, len(addrs))
+	for i, addr := range addrs {
+		keys[i] = BalanceKey(addr)
+	}
+	values, errs := f(ctx, keys)
+	balances := make([]uint64, len(addrs))
+	for i := range addrs {
+		bal, _, err := innerGetNativeBalance(values[i], errs[i])
+		if err != nil {
+			return nil, err
+		}
+		balances[i] = bal.balance
+	}
+	return balances, nil
+}
+
+// ParseBalance decodes a value stored under [BalanceKey].
+func ParseBalance(v []byte) (uint64, error) {
+	bal, _, err := innerGetNativeBalance(v, nil)
+	return bal.balance, err
+}
+
+func innerGetNativeBalance(
+	v []byte,
+	err error,
+) (nativeBalance, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nativeBalance{}, false, nil
+	}
+	if err != nil {
+		return nativeBalance{}, false, err
+	}
+	switch len(v) {
+	case consts.Uint64Len:
+		return nativeBalance{balance: binary.BigEndian.Uint64(v)}, true, nil
+	case 3 * consts.Uint64Len:
+		return nativeBalance{
+			balance:        binary.BigEndian.Uint64(v),
+			snapshotHeight: binary.BigEndian.Uint64(v[consts.Uint64Len:]),
+			snapshot:       binary.BigEndian.Uint64(v[2*consts.Uint64Len:]),
+		}, true, nil
+	default:
+		return nativeBalance{}, false, fmt.Errorf("%w: %d bytes", ErrInvalidBalance, len(v))
+	}
 }
 
 func innerGetBalance(
@@ -195,8 +620,38 @@ func SetBalance(
 	addr codec.Address,
 	balance uint64,
 ) error {
-	k := BalanceKey(addr)
-	return setBalance(ctx, mu, k, balance)
+	key, bal, _, err := getBalance(ctx, mu, addr)
+	if err != nil {
+		return err
+	}
+	next, err := nextBalance(ctx, mu, bal, balance)
+	if err != nil {
+		return err
+	}
+	return insertValue(ctx, mu, key, next.bytes())
+}
+
+// nextBalance replaces [bal] with [balance], keeping the balance held when
+// the active proposal, if any, was created.
+func nextBalance(
+	ctx context.Context,
+	im state.Immutable,
+	bal nativeBalance,
+	balance uint64,
+) (nativeBalance, error) {
+	active, exists, err := GetActiveProposal(ctx, im)
+	if err != nil || !exists {
+		return nativeBalance{balance: balance}, err
+	}
+	next := nativeBalance{
+		balance:        balance,
+		snapshotHeight: active.SnapshotHeight,
+		snapshot:       bal.balance,
+	}
+	if bal.snapshotHeight == active.SnapshotHeight {
+		next.snapshot = bal.snapshot
+	}
+	return next, nil
 }
 
 func setBalance(
@@ -205,7 +660,7 @@ func setBalance(
 	key []byte,
 	balance uint64,
 ) error {
-	return mu.Insert(ctx, key, binary.BigEndian.AppendUint64(nil, balance))
+	return insertValue(ctx, mu, key, binary.BigEndian.AppendUint64(nil, balance))
 }
 
 func AddBalance(
@@ -224,17 +679,21 @@ func AddBalance(
 	if !exists && !create {
 		return 0, nil
 	}
-	nbal, err := smath.Add(bal, amount)
+	nbal, err := smath.Add(bal.balance, amount)
 	if err != nil {
 		return 0, fmt.Errorf(
 			"%w: could not add balance (bal=%d, addr=%v, amount=%d)",
 			ErrInvalidBalance,
-			bal,
+			bal.balance,
 			addr,
 			amount,
 		)
 	}
-	return nbal, setBalance(ctx, mu, key, nbal)
+	next, err := nextBalance(ctx, mu, bal, nbal)
+	if err != nil {
+		return 0, err
+	}
+	return nbal, insertValue(ctx, mu, key, next.bytes())
 }
 
 func SubBalance(
@@ -250,22 +709,86 @@ func SubBalance(
 	if err != nil {
 		return 0, err
 	}
-	nbal, err := smath.Sub(bal, amount)
+	nbal, err := smath.Sub(bal.balance, amount)
 	if err != nil {
 		return 0, fmt.Errorf(
 			"%w: could not subtract balance (bal=%d, addr=%v, amount=%d)",
 			ErrInvalidBalance,
-			bal,
+			bal.balance,
 			addr,
 			amount,
 		)
 	}
-	if nbal == 0 {
+	next, err := nextBalance(ctx, mu, bal, nbal)
+	if err != nil {
+		return 0, err
+	}
+	if nbal == 0 && next.snapshot == 0 {
 		// If there is no balance left, we should delete the record instead of
-		// setting it to 0.
-		return 0, mu.Remove(ctx, key)
+		// setting it to 0. A record still holding a snapshot is kept until
+		// the next proposal.
+		return 0, Delete(ctx, mu, key)
+	}
+	return nbal, insertValue(ctx, mu, key, next.bytes())
+}
+
+// ReadWithHeight reads [keys] together with the height of the state they were
+// read from, in a single atomic read.
+func ReadWithHeight(
+	ctx context.Context,
+	f ReadState,
+	keys [][]byte,
+) ([][]byte, []error, uint64, error) {
+	values, errs := f(ctx, append(keys[:len(keys):len(keys)], chain.HeightKey(HeightKey())))
+	last := len(keys)
+	if errs[last] != nil {
+		return values[:last], errs[:last], 0, errs[last]
+	}
+	height, err := database.ParseUInt64(values[last])
+	return values[:last], errs[:last], height, err
+}
+
+// WithMinHeight wraps [f] so that reads fail with [ErrStaleState] if the
+// state they are served from is below [minHeight]. On success, the height of
+// that state is written to [height].
+func WithMinHeight(f ReadState, minHeight uint64, height *uint64) ReadState {
+	return func(ctx context.Context, keys [][]byte) ([][]byte, []error) {
+		values, errs, h, err := ReadWithHeight(ctx, f, keys)
+		if err == nil && h < minHeight {
+			err = fmt.Errorf("%w: height=%d, minHeight=%d", ErrStaleState, h, minHeight)
+		}
+		if err != nil {
+			return values, utils.Repeat(err, len(keys))
+		}
+		*height = h
+		return values, errs
+	}
+}
+
+// Used to serve RPC queries
+func GetHeightFromState(
+	ctx context.Context,
+	f ReadState,
+) (uint64, error) {
+	values, errs := f(ctx, [][]byte{chain.HeightKey(HeightKey())})
+	if errs[0] != nil {
+		return 0, errs[0]
+	}
+	return database.ParseUInt64(values[0])
+}
+
+// GetTimestampFromState returns the timestamp, in milliseconds, of the block
+// that produced the state served by [f].
+func GetTimestampFromState(
+	ctx context.Context,
+	f ReadState,
+) (int64, error) {
+	values, errs := f(ctx, [][]byte{chain.TimestampKey(TimestampKey())})
+	if errs[0] != nil {
+		return 0, errs[0]
 	}
-	return nbal, setBalance(ctx, mu, key, nbal)
+	t, err := database.ParseUInt64(values[0])
+	return int64(t), err
 }
 
 func HeightKey() (k []byte) {
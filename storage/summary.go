@@ -0,0 +1,196 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// Summary condenses the native token economy of a state, so that two runs
+// of the same scenario can be compared without diffing all of state.
+type Summary struct {
+	Height uint64 `json:"height"`
+	// Accounts is the number of addresses with a native balance.
+	Accounts int `json:"accounts"`
+	// Circulating is the sum of all native balances, including those of the
+	// treasury and of swap escrows.
+	Circulating uint64 `json:"circulating"`
+	// Staked is the sum of all stakes, and StakingRewards the rewards they
+	// had accrued when they were last updated.
+	Staked         uint64 `json:"staked"`
+	StakingRewards uint64 `json:"stakingRewards"`
+	// Locked is held by vestings, escrows, open orders and pools.
+	Locked uint64 `json:"locked"`
+	// TotalSupply is Circulating, Staked and Locked together. Unclaimed
+	// rewards are not minted yet and are not part of it.
+	TotalSupply uint64 `json:"totalSupply"`
+	Treasury    uint64 `json:"treasury"`
+	// TopBalancesHash commits to the TopBalances largest native balances
+	// and their addresses.
+	TopBalances     int    `json:"topBalances"`
+	TopBalancesHash ids.ID `json:"topBalancesHash"`
+}
+
+type accountBalance struct {
+	addr    codec.Address
+	balance uint64
+}
+
+// Summarize returns the [Summary] of [db], hashing its [top] largest
+// balances.
+func Summarize(db database.Iteratee, top int) (*Summary, error) {
+	s := &Summary{}
+	var balances []accountBalance
+	if err := scan(db, []byte{balancePrefix}, func(k []byte, v []byte) error {
+		addr, ok := ParseBalanceKey(k)
+		if !ok {
+			return fmt.Errorf("%w: %x", ErrInvalidBalance, k)
+		}
+		balance, err := ParseBalance(v)
+		if err != nil {
+			return err
+		}
+		// An emptied balance is kept while it holds a governance snapshot.
+		if balance == 0 {
+			return nil
+		}
+		if addr == TreasuryAddress {
+			s.Treasury = balance
+		}
+		balances = append(balances, accountBalance{addr: addr, balance: balance})
+		return sum(&s.Circulating, balance)
+	}); err != nil {
+		return nil, err
+	}
+	s.Accounts = len(balances)
+
+	if err := scan(db, []byte{stakePrefix}, func(_ []byte, v []byte) error {
+		stake, _, err := innerGetStake(v, nil)
+		if err != nil {
+			return err
+		}
+		if err := sum(&s.Staked, stake.Staked()); err != nil {
+			return err
+		}
+		return sum(&s.StakingRewards, stake.Rewards)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := scanLocked(db, &s.Locked); err != nil {
+		return nil, err
+	}
+
+	heightKey := chain.HeightKey(HeightKey())
+	if err := scan(db, heightKey, func(k []byte, v []byte) error {
+		if !bytes.Equal(k, heightKey) {
+			return nil
+		}
+		var err error
+		s.Height, err = database.ParseUInt64(v)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	s.TotalSupply = s.Circulating
+	if err := sum(&s.TotalSupply, s.Staked); err != nil {
+		return nil, err
+	}
+	if err := sum(&s.TotalSupply, s.Locked); err != nil {
+		return nil, err
+	}
+	s.TopBalances, s.TopBalancesHash = hashTopBalances(balances, top)
+	return s, nil
+}
+
+// scanLocked adds the native tokens held by records rather than balances
+// to [locked].
+func scanLocked(db database.Iteratee, locked *uint64) error {
+	if err := scan(db, []byte{vestingPrefix}, func(_ []byte, v []byte) error {
+		vesting, err := unpackVesting(v)
+		if err != nil {
+			return err
+		}
+		return sum(locked, vesting.Amount)
+	}); err != nil {
+		return err
+	}
+	if err := scan(db, []byte{escrowPrefix}, func(_ []byte, v []byte) error {
+		escrow, _, err := innerGetEscrow(v, nil)
+		if err != nil {
+			return err
+		}
+		return sum(locked, escrow.Amount)
+	}); err != nil {
+		return err
+	}
+	if err := scan(db, append([]byte{orderBookPrefix}, NativeAsset[:]...), func(_ []byte, v []byte) error {
+		order, err := unpackOrder(v)
+		if err != nil {
+			return err
+		}
+		return sum(locked, order.Remaining)
+	}); err != nil {
+		return err
+	}
+	// The native asset sorts first, so it can only be asset A of a pool.
+	return scan(db, append([]byte{poolPrefix, poolReserves}, NativeAsset[:]...), func(_ []byte, v []byte) error {
+		pool, _, err := innerGetPool(v, nil)
+		if err != nil {
+			return err
+		}
+		return sum(locked, pool.ReserveA)
+	})
+}
+
+// hashTopBalances hashes the [top] largest [balances], largest first and
+// by address among equal balances.
+func hashTopBalances(balances []accountBalance, top int) (int, ids.ID) {
+	slices.SortFunc(balances, func(a accountBalance, b accountBalance) int {
+		if a.balance != b.balance {
+			if a.balance > b.balance {
+				return -1
+			}
+			return 1
+		}
+		return bytes.Compare(a.addr[:], b.addr[:])
+	})
+	n := max(min(top, len(balances)), 0)
+	h := sha256.New()
+	for _, b := range balances[:n] {
+		h.Write(b.addr[:])
+		h.Write(binary.BigEndian.AppendUint64(nil, b.balance))
+	}
+	return n, ids.ID(h.Sum(nil))
+}
+
+func scan(db database.Iteratee, prefix []byte, f func(k []byte, v []byte) error) error {
+	it := db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+	for it.Next() {
+		if err := f(it.Key(), it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+func sum(total *uint64, amount uint64) error {
+	var err error
+	*total, err = smath.Add(*total, amount)
+	return err
+}
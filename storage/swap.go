@@ -0,0 +1,201 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// MaxSwapLegs bounds each side of a swap.
+const MaxSwapLegs = 4
+
+// escrowAddressType is not an auth type, so no key can sign for an
+// [EscrowAddress].
+const escrowAddressType = 0xfe
+
+// NativeAsset identifies the native token in a [SwapLeg].
+var NativeAsset = ids.Empty
+
+// SwapLeg is an amount of one asset.
+type SwapLeg struct {
+	// Asset is a fungible asset, or [NativeAsset].
+	Asset  ids.ID `serialize:"true" json:"asset"`
+	Amount uint64 `serialize:"true" json:"amount"`
+}
+
+// Swap is an open offer from [Proposer] to exchange [Offer], held in escrow,
+// for [Want] from [Counterparty].
+type Swap struct {
+	Proposer     codec.Address `json:"proposer"`
+	Counterparty codec.Address `json:"counterparty"`
+	// Expiry is the last timestamp, in milliseconds, at which the swap can
+	// be accepted.
+	Expiry int64     `json:"expiry"`
+	Offer  []SwapLeg `json:"offer"`
+	Want   []SwapLeg `json:"want"`
+}
+
+// EscrowAddress holds the offer of [swapID] until it is accepted or
+// refunded.
+func EscrowAddress(swapID ids.ID) codec.Address {
+	return codec.CreateAddress(escrowAddressType, swapID)
+}
+
+// [swapPrefix] + [swapID]
+func SwapKey(swapID ids.ID) (k []byte) {
+	k = make([]byte, 1+ids.IDLen+consts.Uint16Len)
+	k[0] = swapPrefix
+	copy(k[1:], swapID[:])
+	binary.BigEndian.PutUint16(k[1+ids.IDLen:], SwapChunks)
+	return
+}
+
+// LegBalanceKey is the balance key [leg] moves from or to for [addr].
+func LegBalanceKey(addr codec.Address, leg SwapLeg) []byte {
+	if leg.Asset == NativeAsset {
+		return BalanceKey(addr)
+	}
+	return AssetBalanceKey(addr, leg.Asset)
+}
+
+// MoveLeg transfers [leg] from [from] to [to].
+func MoveLeg(
+	ctx context.Context,
+	mu state.Mutable,
+	from codec.Address,
+	to codec.Address,
+	leg SwapLeg,
+) error {
+	if _, err := SubLeg(ctx, mu, from, leg); err != nil {
+		return err
+	}
+	_, err := AddLeg(ctx, mu, to, leg)
+	return err
+}
+
+// SubLeg debits [leg] from [addr], returning the new balance of its asset.
+func SubLeg(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	leg SwapLeg,
+) (uint64, error) {
+	if leg.Asset == NativeAsset {
+		return SubBalance(ctx, mu, addr, leg.Amount)
+	}
+	return SubAssetBalance(ctx, mu, addr, leg.Asset, leg.Amount)
+}
+
+// AddLeg credits [leg] to [addr], returning the new balance of its asset.
+func AddLeg(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	leg SwapLeg,
+) (uint64, error) {
+	if leg.Asset == NativeAsset {
+		return AddBalance(ctx, mu, addr, leg.Amount, true)
+	}
+	return AddAssetBalance(ctx, mu, addr, leg.Asset, leg.Amount, true)
+}
+
+// GetSwap returns the swap stored under [swapID], if any.
+func GetSwap(
+	ctx context.Context,
+	im state.Immutable,
+	swapID ids.ID,
+) (*Swap, bool, error) {
+	return innerGetSwap(getValue(ctx, im, SwapKey(swapID)))
+}
+
+// Used to serve RPC queries
+func GetSwapFromState(
+	ctx context.Context,
+	f ReadState,
+	swapID ids.ID,
+) (*Swap, bool, error) {
+	values, errs := f(ctx, [][]byte{SwapKey(swapID)})
+	return innerGetSwap(values[0], errs[0])
+}
+
+func innerGetSwap(v []byte, err error) (*Swap, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	p := codec.NewReader(v, len(v))
+	s := &Swap{}
+	// [codec.Packer.UnpackAddress] rejects [codec.EmptyAddress], which is a
+	// valid counterparty.
+	proposer, counterparty := s.Proposer[:], s.Counterparty[:]
+	p.UnpackFixedBytes(codec.AddressLen, &proposer)
+	p.UnpackFixedBytes(codec.AddressLen, &counterparty)
+	s.Expiry = p.UnpackInt64(false)
+	s.Offer = unpackLegs(p)
+	s.Want = unpackLegs(p)
+	if err := p.Err(); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrInvalidSwap, err)
+	}
+	if !p.Empty() {
+		return nil, false, fmt.Errorf("%w: trailing bytes", ErrInvalidSwap)
+	}
+	return s, true, nil
+}
+
+func unpackLegs(p *codec.Packer) []SwapLeg {
+	legs := make([]SwapLeg, p.UnpackByte())
+	for i := range legs {
+		p.UnpackID(false, &legs[i].Asset)
+		legs[i].Amount = p.UnpackUint64(true)
+	}
+	return legs
+}
+
+// SetSwap stores [s] under [swapID].
+func SetSwap(
+	ctx context.Context,
+	mu state.Mutable,
+	swapID ids.ID,
+	s *Swap,
+) error {
+	if len(s.Offer) > MaxSwapLegs || len(s.Want) > MaxSwapLegs {
+		return fmt.Errorf("%w: more than %d legs", ErrInvalidSwap, MaxSwapLegs)
+	}
+	size := 2*codec.AddressLen + consts.Int64Len + 2 + (len(s.Offer)+len(s.Want))*(ids.IDLen+consts.Uint64Len)
+	p := codec.NewWriter(size, size)
+	p.PackAddress(s.Proposer)
+	p.PackAddress(s.Counterparty)
+	p.PackInt64(s.Expiry)
+	for _, legs := range [][]SwapLeg{s.Offer, s.Want} {
+		p.PackByte(uint8(len(legs)))
+		for _, leg := range legs {
+			p.PackID(leg.Asset)
+			p.PackUint64(leg.Amount)
+		}
+	}
+	if err := p.Err(); err != nil {
+		return err
+	}
+	return insertValue(ctx, mu, SwapKey(swapID), p.Bytes())
+}
+
+func DeleteSwap(
+	ctx context.Context,
+	mu state.Mutable,
+	swapID ids.ID,
+) error {
+	return Delete(ctx, mu, SwapKey(swapID))
+}
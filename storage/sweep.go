@@ -0,0 +1,19 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// sweepPoolAddressType is not an auth type, so no key can sign for
+// [SweepPoolAddress].
+const sweepPoolAddressType = 0xf9
+
+// SweepPoolAddress holds the bounties paid for sweeping expired assets in
+// the native balance map. Anyone can fund it with a transfer; funds only
+// leave as bounties.
+var SweepPoolAddress = codec.CreateAddress(sweepPoolAddressType, ids.Empty)
@@ -0,0 +1,100 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// tombstonedPrefixes lists the prefixes whose records leave a tombstone when
+// they are deleted, so "deleted" can be told apart from "never existed".
+//
+// This is part of the state transition: every node must agree on it. Actions
+// that delete records under a listed prefix must declare [DeletionStateKeys].
+var tombstonedPrefixes = set.Of[byte](assetPrefix, swapPrefix, orderBookPrefix)
+
+// [tombstonePrefix] + [key]
+func TombstoneKey(key []byte) (k []byte) {
+	k = make([]byte, 1+len(key)+consts.Uint16Len)
+	k[0] = tombstonePrefix
+	copy(k[1:], key)
+	binary.BigEndian.PutUint16(k[1+len(key):], TombstoneChunks)
+	return
+}
+
+func tombstoned(key []byte) bool {
+	return len(key) > 0 && tombstonedPrefixes.Contains(key[0])
+}
+
+// DeletionStateKeys returns the extra keys [Delete] touches when removing
+// [key]. It is empty unless [key] is under a tombstoned prefix.
+func DeletionStateKeys(key []byte) state.Keys {
+	keys := state.Keys{}
+	if tombstoned(key) {
+		keys.Add(string(chain.HeightKey(HeightKey())), state.Read)
+		keys.Add(string(TombstoneKey(key)), state.Allocate|state.Write)
+	}
+	return keys
+}
+
+// Delete removes [key]. Under a tombstoned prefix it also records the height
+// of the block performing the deletion.
+func Delete(ctx context.Context, mu state.Mutable, key []byte) error {
+	if !tombstoned(key) {
+		return removeValue(ctx, mu, key)
+	}
+	// The height key is only advanced once all transactions in a block have
+	// run, so it still holds the parent height here.
+	parent, err := GetHeight(ctx, mu)
+	if err != nil {
+		return err
+	}
+	if err := insertValue(ctx, mu, TombstoneKey(key), binary.BigEndian.AppendUint64(nil, parent+1)); err != nil {
+		return err
+	}
+	return removeValue(ctx, mu, key)
+}
+
+// GetTombstone returns the height at which [key] was deleted. It returns
+// false if [key] has no tombstone.
+func GetTombstone(
+	ctx context.Context,
+	im state.Immutable,
+	key []byte,
+) (uint64, bool, error) {
+	return innerGetTombstone(getValue(ctx, im, TombstoneKey(key)))
+}
+
+// Used to serve RPC queries
+func GetTombstoneFromState(
+	ctx context.Context,
+	f ReadState,
+	key []byte,
+) (uint64, bool, error) {
+	values, errs := f(ctx, [][]byte{TombstoneKey(key)})
+	return innerGetTombstone(values[0], errs[0])
+}
+
+func innerGetTombstone(v []byte, err error) (uint64, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	height, err := database.ParseUInt64(v)
+	if err != nil {
+		return 0, false, err
+	}
+	return height, true, nil
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestTombstone(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	store := chaintest.NewInMemoryStore()
+	require.NoError(store.Insert(ctx, chain.HeightKey(HeightKey()), binary.BigEndian.AppendUint64(nil, 41)))
+	read := func(ctx context.Context, keys [][]byte) ([][]byte, []error) {
+		values := make([][]byte, len(keys))
+		errs := make([]error, len(keys))
+		for i, k := range keys {
+			values[i], errs[i] = store.GetValue(ctx, k)
+		}
+		return values, errs
+	}
+
+	// A record that never existed has no tombstone.
+	asset := AssetKey(ids.GenerateTestID())
+	_, exists, err := GetTombstone(ctx, store, asset)
+	require.NoError(err)
+	require.False(exists)
+
+	// Deleting it records the height of the block that deleted it.
+	require.NoError(store.Insert(ctx, asset, []byte("asset")))
+	require.NoError(Delete(ctx, store, asset))
+	_, err = store.GetValue(ctx, asset)
+	require.ErrorIs(err, database.ErrNotFound)
+	height, exists, err := GetTombstone(ctx, store, asset)
+	require.NoError(err)
+	require.True(exists)
+	require.Equal(uint64(42), height)
+	height, exists, err = GetTombstoneFromState(ctx, read, asset)
+	require.NoError(err)
+	require.True(exists)
+	require.Equal(uint64(42), height)
+
+	// Records under other prefixes leave none.
+	balance := BalanceKey(codectest.NewRandomAddress())
+	require.NoError(store.Insert(ctx, balance, []byte("balance")))
+	require.NoError(Delete(ctx, store, balance))
+	_, err = store.GetValue(ctx, balance)
+	require.ErrorIs(err, database.ErrNotFound)
+	_, exists, err = GetTombstone(ctx, store, balance)
+	require.NoError(err)
+	require.False(exists)
+
+	// A tombstone that is not a height is rejected.
+	require.NoError(store.Insert(ctx, TombstoneKey(asset), []byte{1}))
+	_, _, err = GetTombstone(ctx, store, asset)
+	require.Error(err)
+}
+
+func TestDeletionStateKeys(t *testing.T) {
+	require := require.New(t)
+
+	asset := AssetKey(ids.GenerateTestID())
+	require.Equal(state.Keys{
+		string(chain.HeightKey(HeightKey())): state.Read,
+		string(TombstoneKey(asset)):          state.Allocate | state.Write,
+	}, DeletionStateKeys(asset))
+	require.Empty(DeletionStateKeys(BalanceKey(codectest.NewRandomAddress())))
+
+	// Deleting under a tombstoned prefix needs the height.
+	require.ErrorIs(Delete(context.Background(), chaintest.NewInMemoryStore(), asset), database.ErrNotFound)
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// Built-in policies a [TransferHook] can run on every transfer of an asset.
+const (
+	// HookNone removes the hook.
+	HookNone uint8 = iota
+	// HookNotify reports [TransferHook.Target] in the result of each
+	// transfer, for off-chain notifiers to pick up.
+	HookNotify
+	// HookLock rejects every transfer.
+	HookLock
+)
+
+const transferHookValueSize = 2*codec.AddressLen + 1
+
+// TransferHook is a policy registered on an asset.
+type TransferHook struct {
+	// Registrar set the hook and is the only address that may change it.
+	Registrar codec.Address `json:"registrar"`
+	Kind      uint8         `json:"kind"`
+	Target    codec.Address `json:"target"`
+}
+
+// [transferHookPrefix] + [assetID]
+func TransferHookKey(assetID ids.ID) (k []byte) {
+	k = make([]byte, 1+ids.IDLen+consts.Uint16Len)
+	k[0] = transferHookPrefix
+	copy(k[1:], assetID[:])
+	binary.BigEndian.PutUint16(k[1+ids.IDLen:], TransferHookChunks)
+	return
+}
+
+// GetTransferHook returns the hook of [assetID], if any.
+func GetTransferHook(
+	ctx context.Context,
+	im state.Immutable,
+	assetID ids.ID,
+) (*TransferHook, error) {
+	return innerGetTransferHook(getValue(ctx, im, TransferHookKey(assetID)))
+}
+
+// Used to serve RPC queries
+func GetTransferHookFromState(
+	ctx context.Context,
+	f ReadState,
+	assetID ids.ID,
+) (*TransferHook, error) {
+	values, errs := f(ctx, [][]byte{TransferHookKey(assetID)})
+	return innerGetTransferHook(values[0], errs[0])
+}
+
+func innerGetTransferHook(v []byte, err error) (*TransferHook, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(v) != transferHookValueSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidTransferHook, len(v))
+	}
+	return &TransferHook{
+		Registrar: codec.Address(v[:codec.AddressLen]),
+		Kind:      v[codec.AddressLen],
+		Target:    codec.Address(v[codec.AddressLen+1:]),
+	}, nil
+}
+
+// SetTransferHook stores [hook] for [assetID]. A [HookNone] hook removes it.
+func SetTransferHook(
+	ctx context.Context,
+	mu state.Mutable,
+	assetID ids.ID,
+	hook *TransferHook,
+) error {
+	k := TransferHookKey(assetID)
+	if hook.Kind == HookNone {
+		return Delete(ctx, mu, k)
+	}
+	v := make([]byte, transferHookValueSize)
+	copy(v, hook.Registrar[:])
+	v[codec.AddressLen] = hook.Kind
+	copy(v[codec.AddressLen+1:], hook.Target[:])
+	return insertValue(ctx, mu, k, v)
+}
@@ -0,0 +1,230 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// MaxCouncilSize bounds the council so approvals fit in a uint16 bitmap.
+const MaxCouncilSize = 16
+
+const (
+	treasuryCouncil  = 0x0
+	treasuryProposal = 0x1
+
+	// treasuryAddressType is not an auth type, so no key can sign for
+	// [TreasuryAddress].
+	treasuryAddressType = 0xff
+
+	proposalValueSize = codec.AddressLen + 2*consts.Uint64Len + consts.Uint16Len
+)
+
+// TreasuryAddress holds the treasury funds in the native balance map. Anyone
+// can deposit with a transfer; funds only leave through approved proposals.
+var TreasuryAddress = codec.CreateAddress(treasuryAddressType, ids.Empty)
+
+// Council is a set of addresses that act together once [Threshold] of them
+// approve, such as the treasury council.
+type Council struct {
+	Members []codec.Address `json:"members"`
+	// Threshold is how many members must approve a proposal.
+	Threshold uint8 `json:"threshold"`
+}
+
+func (c *Council) Verify() error {
+	if len(c.Members) == 0 || len(c.Members) > MaxCouncilSize {
+		return fmt.Errorf("%w: %d members", ErrInvalidCouncil, len(c.Members))
+	}
+	if c.Threshold == 0 || int(c.Threshold) > len(c.Members) {
+		return fmt.Errorf("%w: threshold %d of %d", ErrInvalidCouncil, c.Threshold, len(c.Members))
+	}
+	for i, m := range c.Members {
+		if m == codec.EmptyAddress {
+			return fmt.Errorf("%w: member %d is empty", ErrInvalidCouncil, i)
+		}
+		if c.Index(m) != i {
+			return fmt.Errorf("%w: %s listed twice", ErrInvalidCouncil, m)
+		}
+	}
+	return nil
+}
+
+// Index returns the position of [addr] in the council, or -1.
+func (c *Council) Index(addr codec.Address) int {
+	for i, m := range c.Members {
+		if m == addr {
+			return i
+		}
+	}
+	return -1
+}
+
+// TreasuryProposal is a pending spend of treasury funds.
+type TreasuryProposal struct {
+	To     codec.Address `json:"to"`
+	Amount uint64        `json:"amount"`
+	// Expiry is the timestamp, in milliseconds, after which the proposal can
+	// no longer be approved.
+	Expiry int64 `json:"expiry"`
+	// Approvals has bit i set once council member i approved.
+	Approvals uint16 `json:"approvals"`
+}
+
+func (p *TreasuryProposal) ApprovalCount() int {
+	return bits.OnesCount16(p.Approvals)
+}
+
+// [treasuryPrefix] + [treasuryCouncil]
+func TreasuryCouncilKey() (k []byte) {
+	k = make([]byte, 2+consts.Uint16Len)
+	k[0] = treasuryPrefix
+	k[1] = treasuryCouncil
+	binary.BigEndian.PutUint16(k[2:], TreasuryCouncilChunks)
+	return
+}
+
+// [treasuryPrefix] + [treasuryProposal] + [proposalID]
+func TreasuryProposalKey(proposalID ids.ID) (k []byte) {
+	k = make([]byte, 2+ids.IDLen+consts.Uint16Len)
+	k[0] = treasuryPrefix
+	k[1] = treasuryProposal
+	copy(k[2:], proposalID[:])
+	binary.BigEndian.PutUint16(k[2+ids.IDLen:], TreasuryProposalChunks)
+	return
+}
+
+// GetTreasuryCouncil returns the council, or nil if the chain has none.
+func GetTreasuryCouncil(
+	ctx context.Context,
+	im state.Immutable,
+) (*Council, error) {
+	return innerGetCouncil(getValue(ctx, im, TreasuryCouncilKey()))
+}
+
+// Used to serve RPC queries
+func GetTreasuryCouncilFromState(
+	ctx context.Context,
+	f ReadState,
+) (*Council, error) {
+	values, errs := f(ctx, [][]byte{TreasuryCouncilKey()})
+	return innerGetCouncil(values[0], errs[0])
+}
+
+func innerGetCouncil(v []byte, err error) (*Council, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(v) < 2 || len(v) != 2+int(v[1])*codec.AddressLen {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidCouncil, len(v))
+	}
+	c := &Council{
+		Threshold: v[0],
+		Members:   make([]codec.Address, v[1]),
+	}
+	for i := range c.Members {
+		c.Members[i] = codec.Address(v[2+i*codec.AddressLen:])
+	}
+	return c, nil
+}
+
+// SetTreasuryCouncil replaces the council. It is only called from genesis.
+func SetTreasuryCouncil(
+	ctx context.Context,
+	mu state.Mutable,
+	c *Council,
+) error {
+	return setCouncil(ctx, mu, TreasuryCouncilKey(), c)
+}
+
+func setCouncil(
+	ctx context.Context,
+	mu state.Mutable,
+	key []byte,
+	c *Council,
+) error {
+	if err := c.Verify(); err != nil {
+		return err
+	}
+	v := make([]byte, 2, 2+len(c.Members)*codec.AddressLen)
+	v[0] = c.Threshold
+	v[1] = byte(len(c.Members))
+	for _, m := range c.Members {
+		v = append(v, m[:]...)
+	}
+	return insertValue(ctx, mu, key, v)
+}
+
+// GetTreasuryProposal returns the proposal stored under [proposalID], if any.
+func GetTreasuryProposal(
+	ctx context.Context,
+	im state.Immutable,
+	proposalID ids.ID,
+) (*TreasuryProposal, bool, error) {
+	return innerGetTreasuryProposal(getValue(ctx, im, TreasuryProposalKey(proposalID)))
+}
+
+// Used to serve RPC queries
+func GetTreasuryProposalFromState(
+	ctx context.Context,
+	f ReadState,
+	proposalID ids.ID,
+) (*TreasuryProposal, bool, error) {
+	values, errs := f(ctx, [][]byte{TreasuryProposalKey(proposalID)})
+	return innerGetTreasuryProposal(values[0], errs[0])
+}
+
+func innerGetTreasuryProposal(v []byte, err error) (*TreasuryProposal, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(v) != proposalValueSize {
+		return nil, false, fmt.Errorf("%w: %d bytes", ErrInvalidTreasuryProposal, len(v))
+	}
+	return &TreasuryProposal{
+		To:        codec.Address(v[:codec.AddressLen]),
+		Amount:    binary.BigEndian.Uint64(v[codec.AddressLen:]),
+		Expiry:    int64(binary.BigEndian.Uint64(v[codec.AddressLen+consts.Uint64Len:])),
+		Approvals: binary.BigEndian.Uint16(v[codec.AddressLen+2*consts.Uint64Len:]),
+	}, true, nil
+}
+
+func SetTreasuryProposal(
+	ctx context.Context,
+	mu state.Mutable,
+	proposalID ids.ID,
+	p *TreasuryProposal,
+) error {
+	v := make([]byte, proposalValueSize)
+	copy(v, p.To[:])
+	binary.BigEndian.PutUint64(v[codec.AddressLen:], p.Amount)
+	binary.BigEndian.PutUint64(v[codec.AddressLen+consts.Uint64Len:], uint64(p.Expiry))
+	binary.BigEndian.PutUint16(v[codec.AddressLen+2*consts.Uint64Len:], p.Approvals)
+	return insertValue(ctx, mu, TreasuryProposalKey(proposalID), v)
+}
+
+func DeleteTreasuryProposal(
+	ctx context.Context,
+	mu state.Mutable,
+	proposalID ids.ID,
+) error {
+	return Delete(ctx, mu, TreasuryProposalKey(proposalID))
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const vestingValueSize = codec.AddressLen + consts.Uint64Len + consts.Int64Len
+
+// Vesting is an amount of native tokens locked by [Creator] until
+// [Release]. The beneficiary is part of the key.
+type Vesting struct {
+	Creator codec.Address `json:"creator"`
+	Amount  uint64        `json:"amount"`
+	// Release is the timestamp, in milliseconds, from which the beneficiary
+	// can claim [Amount].
+	Release int64 `json:"release"`
+}
+
+// [vestingPrefix] + [beneficiary] + [vestingID]
+//
+// Keys of one beneficiary share the prefix [vestingPrefix] + [beneficiary]
+// and sort by vesting ID, which is what [GetVestingsByBeneficiary] scans.
+func VestingKey(beneficiary codec.Address, vestingID ids.ID) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+ids.IDLen+consts.Uint16Len)
+	k[0] = vestingPrefix
+	copy(k[1:], beneficiary[:])
+	copy(k[1+codec.AddressLen:], vestingID[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen+ids.IDLen:], VestingChunks)
+	return
+}
+
+func vestingsPrefix(beneficiary codec.Address) []byte {
+	k := make([]byte, 1+codec.AddressLen)
+	k[0] = vestingPrefix
+	copy(k[1:], beneficiary[:])
+	return k
+}
+
+// GetVesting returns the vesting [vestingID] of [beneficiary], if any.
+func GetVesting(
+	ctx context.Context,
+	im state.Immutable,
+	beneficiary codec.Address,
+	vestingID ids.ID,
+) (*Vesting, bool, error) {
+	return innerGetVesting(getValue(ctx, im, VestingKey(beneficiary, vestingID)))
+}
+
+// Used to serve RPC queries
+func GetVestingFromState(
+	ctx context.Context,
+	f ReadState,
+	beneficiary codec.Address,
+	vestingID ids.ID,
+) (*Vesting, bool, error) {
+	values, errs := f(ctx, [][]byte{VestingKey(beneficiary, vestingID)})
+	return innerGetVesting(values[0], errs[0])
+}
+
+func innerGetVesting(v []byte, err error) (*Vesting, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	vesting, err := unpackVesting(v)
+	if err != nil {
+		return nil, false, err
+	}
+	return vesting, true, nil
+}
+
+func unpackVesting(v []byte) (*Vesting, error) {
+	if len(v) != vestingValueSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidVesting, len(v))
+	}
+	vesting := &Vesting{
+		Amount:  binary.BigEndian.Uint64(v[codec.AddressLen:]),
+		Release: int64(binary.BigEndian.Uint64(v[codec.AddressLen+consts.Uint64Len:])),
+	}
+	copy(vesting.Creator[:], v)
+	return vesting, nil
+}
+
+// SetVesting stores [vesting] for [beneficiary] under [vestingID].
+func SetVesting(
+	ctx context.Context,
+	mu state.Mutable,
+	beneficiary codec.Address,
+	vestingID ids.ID,
+	vesting *Vesting,
+) error {
+	v := make([]byte, vestingValueSize)
+	copy(v, vesting.Creator[:])
+	binary.BigEndian.PutUint64(v[codec.AddressLen:], vesting.Amount)
+	binary.BigEndian.PutUint64(v[codec.AddressLen+consts.Uint64Len:], uint64(vesting.Release))
+	return insertValue(ctx, mu, VestingKey(beneficiary, vestingID), v)
+}
+
+func DeleteVesting(
+	ctx context.Context,
+	mu state.Mutable,
+	beneficiary codec.Address,
+	vestingID ids.ID,
+) error {
+	return Delete(ctx, mu, VestingKey(beneficiary, vestingID))
+}
+
+// Vestings iterates over the vestings of one beneficiary in ID order.
+type Vestings struct {
+	it      database.Iterator
+	vesting *Vesting
+	err     error
+}
+
+// GetVestingsByBeneficiary returns an iterator over the vestings of
+// [beneficiary] in [db], starting at [start]. Pass [ids.Empty] to start from
+// the first one.
+//
+// The iterator must be released once done.
+func GetVestingsByBeneficiary(db database.Iteratee, beneficiary codec.Address, start ids.ID) *Vestings {
+	prefix := vestingsPrefix(beneficiary)
+	return &Vestings{
+		it: db.NewIteratorWithStartAndPrefix(append(prefix, start[:]...), prefix),
+	}
+}
+
+func (v *Vestings) Next() bool {
+	if v.err != nil || !v.it.Next() {
+		return false
+	}
+	v.vesting, v.err = unpackVesting(v.it.Value())
+	return v.err == nil
+}
+
+// ID returns the current vesting ID. It is only valid after Next returned
+// true.
+func (v *Vestings) ID() ids.ID {
+	k := v.it.Key()
+	return ids.ID(k[1+codec.AddressLen:])
+}
+
+// Vesting returns the current vesting. It is only valid after Next returned
+// true.
+func (v *Vestings) Vesting() *Vesting {
+	return v.vesting
+}
+
+func (v *Vestings) Error() error {
+	if v.err != nil {
+		return v.err
+	}
+	return v.it.Error()
+}
+
+func (v *Vestings) Release() {
+	v.it.Release()
+}
@@ -5,6 +5,8 @@ package e2e_test
 
 import (
 	"encoding/json"
+	"flag"
+	"os"
 	"testing"
 
 	"github.com/ava-labs/avalanchego/tests/fixture/e2e"
@@ -24,7 +26,10 @@ import (
 
 const owner = "morpheusvm-e2e-tests"
 
-var flagVars *e2e.FlagVars
+var (
+	flagVars *e2e.FlagVars
+	seedFile string
+)
 
 func TestE2e(t *testing.T) {
 	ginkgo.RunSpecs(t, "morpheusvm e2e test suites")
@@ -32,6 +37,12 @@ func TestE2e(t *testing.T) {
 
 func init() {
 	flagVars = e2e.RegisterFlags()
+	flag.StringVar(
+		&seedFile,
+		"seed-file",
+		"",
+		"[optional] seed file of demo state created at genesis",
+	)
 }
 
 // Construct tmpnet network with a single MorpheusVM Subnet
@@ -43,6 +54,12 @@ var _ = ginkgo.SynchronizedBeforeSuite(func() []byte {
 
 	genesisBytes, err := json.Marshal(gen)
 	require.NoError(err)
+	if seedFile != "" {
+		seedBytes, err := os.ReadFile(seedFile)
+		require.NoError(err)
+		genesisBytes, err = vm.WithSeed(genesisBytes, seedBytes)
+		require.NoError(err)
+	}
 
 	expectedABI, err := abi.NewABI(vm.ActionParser.GetRegisteredTypes(), vm.OutputParser.GetRegisteredTypes())
 	require.NoError(err)
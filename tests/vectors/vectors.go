@@ -0,0 +1,655 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package vectors produces serialization test vectors for the MorpheusVM
+// codec, so clients in other languages can check byte-for-byte
+// compatibility against testdata/vectors.json.
+package vectors
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk-starter-kit/vm"
+	"github.com/ava-labs/hypersdk/abi"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+	hed25519 "github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+// Vector pairs a value with its encoding.
+type Vector struct {
+	Name string `json:"name"`
+	// TypeID is the registered type of an action or output. Encodings of
+	// actions and outputs start with it, as they do in transactions and
+	// results.
+	TypeID *uint8          `json:"typeId,omitempty"`
+	Value  json.RawMessage `json:"value"`
+	Bytes  codec.Bytes     `json:"bytes"`
+}
+
+// TxVector is a transaction signed with an ed25519 key. Signatures are
+// deterministic, so a client that builds the transaction from its fields
+// must produce the same bytes and ID.
+type TxVector struct {
+	Name string `json:"name"`
+	// Seed is the 32-byte ed25519 private key seed of the signer.
+	Seed      codec.Bytes `json:"seed"`
+	Timestamp int64       `json:"timestamp"`
+	ChainID   ids.ID      `json:"chainId"`
+	MaxFee    uint64      `json:"maxFee"`
+	Actions   []Vector    `json:"actions"`
+	ID        ids.ID      `json:"id"`
+	Bytes     codec.Bytes `json:"bytes"`
+}
+
+// Vectors is the format of testdata/vectors.json.
+type Vectors struct {
+	// ABI is the ABI served by the VM, which clients marshal by.
+	ABI          abi.ABI    `json:"abi"`
+	Actions      []Vector   `json:"actions"`
+	Outputs      []Vector   `json:"outputs"`
+	Keys         []Vector   `json:"keys"`
+	Transactions []TxVector `json:"transactions"`
+	// Digests are the bytes signed off-chain for vouchers and permits, on
+	// the chain ID of the transactions.
+	Digests []Vector `json:"digests"`
+}
+
+// Fixed inputs, so every run produces the same vectors.
+var (
+	alice   = codec.CreateAddress(auth.ED25519ID, id("alice"))
+	bob     = codec.CreateAddress(auth.SECP256R1ID, id("bob"))
+	carol   = codec.CreateAddress(auth.BLSID, id("carol"))
+	asset   = id("asset")
+	chainID = id("chain")
+
+	voucher = actions.Voucher{
+		Asset:              asset,
+		TokenURI:           "ipfs://token",
+		Price:              100,
+		RoyaltyBasisPoints: 250,
+	}
+	permit = actions.Permit{
+		Asset:     asset,
+		Recipient: bob,
+		Deadline:  1_700_000_000_000,
+		Nonce:     7,
+	}
+)
+
+func id(seed string) ids.ID {
+	return hashing.ComputeHash256Array([]byte(seed))
+}
+
+type typedCase struct {
+	name  string
+	value codec.Typed
+}
+
+// actionCases holds the zero value of every registered action, followed by
+// populated values that reach the bounds of each field type.
+func actionCases() []typedCase {
+	var cases []typedCase
+	for _, t := range vm.ActionParser.GetRegisteredTypes() {
+		cases = append(cases, zeroCase(t))
+	}
+	return append(cases,
+		typedCase{"Transfer", &actions.Transfer{To: bob, Value: 1, Memo: []byte("hello")}},
+		typedCase{"Transfer/max", &actions.Transfer{To: bob, Value: math.MaxUint64, Memo: make([]byte, actions.MaxMemoSize)}},
+		typedCase{"Transfer/receipt", &actions.Transfer{To: bob, Value: 1, Receipt: id("receipt")}},
+		typedCase{"AssetTransfer", &actions.AssetTransfer{Recipient: bob, Asset: asset, Reason: "gift"}},
+		typedCase{"AssetTransferPriced", &actions.AssetTransfer{
+			Recipient:    bob,
+			Asset:        asset,
+			Price:        1_000,
+			RoyaltyPayee: carol,
+		}},
+		typedCase{"RedeemVoucher", &actions.RedeemVoucher{
+			Voucher:   voucher,
+			Creator:   hashing.ComputeHash256([]byte("creator")),
+			Signature: append(hashing.ComputeHash256([]byte("sig0")), hashing.ComputeHash256([]byte("sig1"))...),
+		}},
+		typedCase{"MintAsset", &actions.MintAsset{Asset: asset}},
+		typedCase{"BurnAsset", &actions.BurnAsset{Asset: asset}},
+		typedCase{"TransferAsset", &actions.TransferAsset{To: bob, Asset: asset, Value: math.MaxUint64, Memo: []byte("memo")}},
+		typedCase{"SetNotificationPrefs", &actions.SetNotificationPrefs{Target: id("webhook"), EventMask: storage.NotifyAll}},
+		typedCase{"Approve", &actions.Approve{Spender: bob, Value: 7}},
+		typedCase{"TransferFrom", &actions.TransferFrom{From: alice, To: carol, Value: 7}},
+		typedCase{"BatchTransfer", &actions.BatchTransfer{Transfers: []actions.BatchTransferEntry{
+			{To: bob, Value: 1},
+			{To: carol, Value: math.MaxUint64},
+		}}},
+		typedCase{"ProposeTreasurySpend", &actions.ProposeTreasurySpend{ProposalID: id("proposal"), To: bob, Amount: 10, Expiry: math.MaxInt64}},
+		typedCase{"ProposeTreasurySpend/negativeExpiry", &actions.ProposeTreasurySpend{ProposalID: id("proposal"), To: bob, Amount: 10, Expiry: -1}},
+		typedCase{"ApproveTreasurySpend", &actions.ApproveTreasurySpend{ProposalID: id("proposal"), To: bob}},
+		typedCase{"SetTransferHook", &actions.SetTransferHook{Asset: asset, Kind: storage.HookNotify, Target: carol}},
+		typedCase{"ProposeSwap", &actions.ProposeSwap{
+			SwapID:       id("swap"),
+			Counterparty: bob,
+			Offer:        []storage.SwapLeg{{Asset: storage.NativeAsset, Amount: 10}},
+			Want:         []storage.SwapLeg{{Asset: asset, Amount: math.MaxUint64}, {Asset: id("other"), Amount: 1}},
+			Expiry:       math.MaxInt64,
+		}},
+		typedCase{"AcceptSwap", &actions.AcceptSwap{
+			SwapID:   id("swap"),
+			Proposer: alice,
+			Offer:    []storage.SwapLeg{{Asset: storage.NativeAsset, Amount: 10}},
+			Want:     []storage.SwapLeg{{Asset: asset, Amount: 5}},
+		}},
+		typedCase{"RefundSwap", &actions.RefundSwap{
+			SwapID:   id("swap"),
+			Proposer: alice,
+			Offer:    []storage.SwapLeg{{Asset: asset, Amount: 5}},
+		}},
+		typedCase{"FreezeAsset", &actions.FreezeAsset{Asset: asset}},
+		typedCase{"UnfreezeAsset", &actions.UnfreezeAsset{Asset: asset}},
+		typedCase{"CreateVesting", &actions.CreateVesting{VestingID: id("vesting"), Beneficiary: bob, Amount: 100, Release: 1_700_000_000_000}},
+		typedCase{"ClaimVesting", &actions.ClaimVesting{VestingID: id("vesting")}},
+		typedCase{"OpenEscrow", &actions.OpenEscrow{Nonce: 7, Counterparty: bob, Amount: 100, Deadline: 1_700_000_000_000}},
+		typedCase{"ReleaseEscrow", &actions.ReleaseEscrow{EscrowID: storage.EscrowID(alice, 7), Counterparty: bob}},
+		typedCase{"RefundEscrow", &actions.RefundEscrow{EscrowID: storage.EscrowID(alice, 7), Payer: alice}},
+		typedCase{"CreateOrder", &actions.CreateOrder{
+			Sequence:   3,
+			SellAsset:  asset,
+			SellAmount: 10,
+			BuyAsset:   storage.NativeAsset,
+			BuyAmount:  250,
+		}},
+		typedCase{"FillOrder", &actions.FillOrder{
+			OrderID:   storage.OrderID(alice, 3),
+			SellAsset: asset,
+			BuyAsset:  storage.NativeAsset,
+			Maker:     alice,
+			Amount:    4,
+		}},
+		typedCase{"FillOrder/receipt", &actions.FillOrder{
+			OrderID:   storage.OrderID(alice, 3),
+			SellAsset: asset,
+			BuyAsset:  storage.NativeAsset,
+			Maker:     alice,
+			Amount:    4,
+			Receipt:   id("receipt"),
+		}},
+		typedCase{"CancelOrder", &actions.CancelOrder{OrderID: storage.OrderID(alice, 3), SellAsset: asset, BuyAsset: storage.NativeAsset}},
+		typedCase{"CreatePool", &actions.CreatePool{
+			AssetA:  storage.NativeAsset,
+			AssetB:  asset,
+			AmountA: 1_000_000,
+			AmountB: 4_000_000,
+		}},
+		typedCase{"AddLiquidity", &actions.AddLiquidity{
+			AssetA:     storage.NativeAsset,
+			AssetB:     asset,
+			MaxAmountA: 1_000,
+			MaxAmountB: 5_000,
+			MinShares:  1_900,
+		}},
+		typedCase{"RemoveLiquidity", &actions.RemoveLiquidity{
+			AssetA:     storage.NativeAsset,
+			AssetB:     asset,
+			Shares:     2_000,
+			MinAmountA: 900,
+			MinAmountB: 3_600,
+		}},
+		typedCase{"Swap", &actions.Swap{
+			AssetIn:      asset,
+			AssetOut:     storage.NativeAsset,
+			AmountIn:     40_000,
+			MinAmountOut: 9_000,
+		}},
+		typedCase{"Stake", &actions.Stake{Amount: 1_000_000}},
+		typedCase{"Unstake", &actions.Unstake{Amount: 400_000}},
+		typedCase{"ClaimRewards", &actions.ClaimRewards{}},
+		typedCase{"CreateProposal/text", &actions.CreateProposal{ProposalID: id("proposal"), Text: "ratify the charter"}},
+		typedCase{"CreateProposal", &actions.CreateProposal{
+			ProposalID: id("proposal"),
+			Text:       "raise the memo size",
+			Changes: []storage.ParameterChange{
+				{Parameter: actions.MaxMemoSizeRule, Value: 512},
+				{Parameter: actions.StakingRewardRateRule, Value: 20},
+			},
+		}},
+		typedCase{"Vote", &actions.Vote{ProposalID: id("proposal"), Support: true}},
+		typedCase{"ExecuteProposal", &actions.ExecuteProposal{ProposalID: id("proposal"), Proposer: alice}},
+		typedCase{"CreateMultisig", &actions.CreateMultisig{Nonce: 1, Signers: []codec.Address{alice, bob}, Threshold: 2}},
+		typedCase{"ProposeMultisigTx", &actions.ProposeMultisigTx{
+			Multisig:   storage.MultisigAddress(alice, 1),
+			ProposalID: id("multisig"),
+			Tx:         storage.MultisigTx{To: bob, Asset: storage.NativeAsset, Value: 250_000},
+			Expiry:     1_700_000_000_000,
+		}},
+		typedCase{"ApproveMultisigTx", &actions.ApproveMultisigTx{
+			Multisig:   storage.MultisigAddress(alice, 1),
+			ProposalID: id("multisig"),
+			Tx:         storage.MultisigTx{To: bob, Asset: asset},
+		}},
+		typedCase{"ProcessEpoch", &actions.ProcessEpoch{Stakers: []codec.Address{alice, bob}}},
+		typedCase{"MarkCompaction", &actions.MarkCompaction{Height: 4_096}},
+		typedCase{"SetClaim", &actions.SetClaim{
+			Key:    []byte("profile"),
+			Value:  []byte(`{"team":"morpheus"}`),
+			Expiry: 1_700_000_000_000,
+		}},
+		typedCase{"AuthorizeSessionKey", &actions.AuthorizeSessionKey{
+			SessionID:   id("session"),
+			Key:         bob,
+			ActionTypes: []uint8{mconsts.TransferID, mconsts.SwapID},
+			SpendCap:    1_000,
+			Expiry:      1_700_000_000_000,
+		}},
+		typedCase{"RevokeSessionKey", &actions.RevokeSessionKey{SessionID: id("session")}},
+		typedCase{"CreateStablecoin", &actions.CreateStablecoin{
+			Asset:      id("stablecoin"),
+			Name:       "Morpheus Dollar",
+			Symbol:     "MUSD",
+			Decimals:   6,
+			URI:        "https://example.com/musd",
+			Attestor:   bob,
+			Compliance: carol,
+		}},
+		typedCase{"AttestReserves", &actions.AttestReserves{Asset: id("stablecoin"), Reserves: 1_000_000, Report: id("report")}},
+		typedCase{"MintStablecoin", &actions.MintStablecoin{Asset: id("stablecoin"), To: bob, Value: 250_000}},
+		typedCase{"BurnStablecoin", &actions.BurnStablecoin{Asset: id("stablecoin"), Value: 50_000}},
+		typedCase{"SetStablecoinBlocked", &actions.SetStablecoinBlocked{Asset: id("stablecoin"), Account: bob, Blocked: true}},
+		typedCase{"SetStablecoinPaused", &actions.SetStablecoinPaused{Asset: id("stablecoin"), Paused: true}},
+		typedCase{"HaltChain", &actions.HaltChain{Incident: id("incident")}},
+		typedCase{"ResumeChain", &actions.ResumeChain{Incident: id("incident")}},
+		typedCase{"DeploySmartAccount", &actions.DeploySmartAccount{
+			Nonce: 1,
+			Policy: (&storage.SmartAccountPolicy{
+				Owners:    []codec.Address{alice, bob},
+				Threshold: 2,
+				MaxSpend:  1_000_000,
+				Sessions:  []storage.SmartAccountSession{{Key: carol, Expiry: 1_700_000_000_000, SpendCap: 10_000}},
+			}).Bytes(),
+		}},
+		typedCase{"ExecuteFromSmartAccount", &actions.ExecuteFromSmartAccount{
+			Account:   storage.SmartAccountAddress(alice, 1),
+			RequestID: id("request"),
+			Tx:        storage.MultisigTx{To: bob, Asset: storage.NativeAsset, Value: 250_000},
+			Expiry:    1_700_000_000_000,
+		}},
+		typedCase{"CreateLeaderboard", &actions.CreateLeaderboard{Nonce: 1, Size: 10, Expiry: 1_700_000_000_000}},
+		typedCase{"SubmitScore", &actions.SubmitScore{BoardID: storage.LeaderboardID(alice, 1), Score: 4_200}},
+		typedCase{"OnboardAccount", &actions.OnboardAccount{
+			PublicKey:   hashing.ComputeHash256([]byte("newcomer")),
+			Value:       1_000,
+			Assets:      []actions.OnboardAsset{{Asset: asset, Value: 25}},
+			Name:        []byte("carol"),
+			Profile:     []byte(`{"bio":"new here"}`),
+			ClaimExpiry: 1_700_000_000_000,
+		}},
+		typedCase{"CreateAsset", &actions.CreateAsset{Nonce: 1}},
+		typedCase{"CreateAsset/soulbound", &actions.CreateAsset{Nonce: 2, Soulbound: true}},
+		typedCase{"CreateAsset/expiring", &actions.CreateAsset{Nonce: 3, Expiry: 1_700_000_000_000}},
+		typedCase{"CreateAsset/app", &actions.CreateAsset{Nonce: 4, App: storage.AppID("team")}},
+		typedCase{"CreateLocker", &actions.CreateLocker{Nonce: 1}},
+		typedCase{"DepositToLocker", &actions.DepositToLocker{
+			Locker: storage.LockerID(alice, 1),
+			Legs:   []storage.SwapLeg{{Asset: storage.NativeAsset, Amount: 10}, {Asset: asset, Amount: 3}},
+			Items:  []ids.ID{id("item")},
+		}},
+		typedCase{"UnbundleLocker", &actions.UnbundleLocker{
+			Locker: storage.LockerID(alice, 1),
+			Legs:   []storage.SwapLeg{{Asset: storage.NativeAsset, Amount: 10}},
+			Items:  []ids.ID{id("item")},
+		}},
+		typedCase{"SweepExpiredAssets", &actions.SweepExpiredAssets{
+			Assets: []actions.ExpiredAsset{{Asset: storage.AssetID(alice, 3), Owner: alice}},
+		}},
+		typedCase{"PermitTransfer", &actions.PermitTransfer{
+			Permit:    permit,
+			Owner:     hashing.ComputeHash256([]byte("owner")),
+			Signature: append(hashing.ComputeHash256([]byte("sig0")), hashing.ComputeHash256([]byte("sig1"))...),
+		}},
+		typedCase{"CreateAuction", &actions.CreateAuction{Asset: asset, MinBid: 1_000, End: 1_700_000_000_000}},
+		typedCase{"PlaceBid", &actions.PlaceBid{Asset: asset, Amount: 1_500, PreviousBidder: alice}},
+		typedCase{"SettleAuction", &actions.SettleAuction{Asset: asset, Seller: alice, Bidder: bob, RoyaltyPayee: carol}},
+		typedCase{"RegisterApp", &actions.RegisterApp{Name: "team"}},
+		typedCase{"SetAppCreator", &actions.SetAppCreator{App: storage.AppID("team"), Creator: bob, Allowed: true}},
+	)
+}
+
+func outputCases() []typedCase {
+	var cases []typedCase
+	for _, t := range vm.OutputParser.GetRegisteredTypes() {
+		cases = append(cases, zeroCase(t))
+	}
+	return append(cases,
+		typedCase{"TransferResult", &actions.TransferResult{SenderBalance: 1, ReceiverBalance: math.MaxUint64}},
+		typedCase{"AssetTransferResult", &actions.AssetTransferResult{OldOwner: alice, NewOwner: bob, Notify: carol}},
+		typedCase{"AssetTransferResultRoyalty", &actions.AssetTransferResult{
+			OldOwner:     alice,
+			NewOwner:     bob,
+			Price:        1_000,
+			Royalty:      25,
+			RoyaltyPayee: carol,
+		}},
+		typedCase{"RedeemVoucherResult", &actions.RedeemVoucherResult{
+			Asset:              asset,
+			Creator:            alice,
+			Owner:              bob,
+			Price:              100,
+			TokenURI:           "ipfs://token",
+			RoyaltyBasisPoints: 250,
+		}},
+		typedCase{"MintAssetResult", &actions.MintAssetResult{Asset: asset, Owner: alice}},
+		typedCase{"BurnAssetResult", &actions.BurnAssetResult{Asset: asset, PreviousOwner: alice}},
+		typedCase{"TransferAssetResult", &actions.TransferAssetResult{SenderBalance: 1, ReceiverBalance: 2, Notify: carol}},
+		typedCase{"SetNotificationPrefsResult", &actions.SetNotificationPrefsResult{Target: id("webhook"), EventMask: storage.NotifyTransfer}},
+		typedCase{"ApproveResult", &actions.ApproveResult{Allowance: 7}},
+		typedCase{"TransferFromResult", &actions.TransferFromResult{SenderBalance: 3, ReceiverBalance: 7, Allowance: 0}},
+		typedCase{"BatchTransferResult", &actions.BatchTransferResult{SenderBalance: 5, ReceiverBalances: []uint64{1, math.MaxUint64}}},
+		typedCase{"ProposeTreasurySpendResult", &actions.ProposeTreasurySpendResult{Approvals: 1, Amount: 10, TreasuryBalance: 20}},
+		typedCase{"ApproveTreasurySpendResult", &actions.ApproveTreasurySpendResult{Approvals: 2, Executed: true, Amount: 10, TreasuryBalance: 10}},
+		typedCase{"SetTransferHookResult", &actions.SetTransferHookResult{Kind: storage.HookLock, Target: carol}},
+		typedCase{"ProposeSwapResult", &actions.ProposeSwapResult{Escrow: storage.EscrowAddress(id("swap")), Expiry: -1}},
+		typedCase{"AcceptSwapResult", &actions.AcceptSwapResult{Proposer: alice, Counterparty: bob}},
+		typedCase{"RefundSwapResult", &actions.RefundSwapResult{Proposer: alice}},
+		typedCase{"FreezeAssetResult", &actions.FreezeAssetResult{Asset: asset}},
+		typedCase{"UnfreezeAssetResult", &actions.UnfreezeAssetResult{Asset: asset}},
+		typedCase{"CreateVestingResult", &actions.CreateVestingResult{SenderBalance: 5, Release: 1_700_000_000_000}},
+		typedCase{"ClaimVestingResult", &actions.ClaimVestingResult{Creator: alice, Amount: 100, ReceiverBalance: math.MaxUint64}},
+		typedCase{"OpenEscrowResult", &actions.OpenEscrowResult{EscrowID: storage.EscrowID(alice, 7), SenderBalance: 5}},
+		typedCase{"ReleaseEscrowResult", &actions.ReleaseEscrowResult{Amount: 100, ReceiverBalance: 100}},
+		typedCase{"RefundEscrowResult", &actions.RefundEscrowResult{Amount: 100, ReceiverBalance: math.MaxUint64}},
+		typedCase{"CreateOrderResult", &actions.CreateOrderResult{OrderID: storage.OrderID(alice, 3), SellerBalance: 90}},
+		typedCase{"FillOrderResult", &actions.FillOrderResult{Maker: alice, Bought: 4, Paid: 100, Remaining: 6}},
+		typedCase{"CancelOrderResult", &actions.CancelOrderResult{Refunded: 6, SellerBalance: 96}},
+		typedCase{"CreatePoolResult", &actions.CreatePoolResult{Shares: 1_999_000}},
+		typedCase{"AddLiquidityResult", &actions.AddLiquidityResult{AmountA: 1_000, AmountB: 4_000, Shares: 2_000, TotalShares: 2_001_000}},
+		typedCase{"RemoveLiquidityResult", &actions.RemoveLiquidityResult{AmountA: 1_000, AmountB: 4_000, RemainingShares: 1_999_000}},
+		typedCase{"SwapResult", &actions.SwapResult{AmountOut: 9_871, ReserveIn: 4_040_000, ReserveOut: 990_129}},
+		typedCase{"StakeResult", &actions.StakeResult{Staked: 1_000_000, Balance: 500}},
+		typedCase{"UnstakeResult", &actions.UnstakeResult{Staked: 600_000, Rewards: 2_000, Balance: 400_500}},
+		typedCase{"ClaimRewardsResult", &actions.ClaimRewardsResult{Rewards: 2_000, Balance: 402_500}},
+		typedCase{"CreateProposalResult", &actions.CreateProposalResult{SnapshotHeight: 42, VotingEnd: 1_041}},
+		typedCase{"VoteResult", &actions.VoteResult{Weight: 400_000, Yes: 1_400_000, No: 200_000}},
+		typedCase{"ExecuteProposalResult", &actions.ExecuteProposalResult{Passed: true, Yes: 1_400_000, No: 200_000}},
+		typedCase{"CreateMultisigResult", &actions.CreateMultisigResult{Multisig: storage.MultisigAddress(alice, 1)}},
+		typedCase{"ProposeMultisigTxResult", &actions.ProposeMultisigTxResult{Approvals: 1}},
+		typedCase{"ApproveMultisigTxResult", &actions.ApproveMultisigTxResult{Approvals: 2, Executed: true}},
+		typedCase{"ProcessEpochResult", &actions.ProcessEpochResult{Epoch: 12, Settled: 2}},
+		typedCase{"MarkCompactionResult", &actions.MarkCompactionResult{Previous: 2_048, Height: 4_096}},
+		typedCase{"SetClaimResult", &actions.SetClaimResult{Expiry: 1_700_000_000_000, Rent: 624, Balance: 9_376}},
+		typedCase{"AuthorizeSessionKeyResult", &actions.AuthorizeSessionKeyResult{Expiry: 1_700_000_000_000}},
+		typedCase{"RevokeSessionKeyResult", &actions.RevokeSessionKeyResult{Key: bob}},
+		typedCase{"CreateStablecoinResult", &actions.CreateStablecoinResult{Asset: id("stablecoin"), Issuer: alice}},
+		typedCase{"AttestReservesResult", &actions.AttestReservesResult{Supply: 250_000, Reserves: 1_000_000}},
+		typedCase{"MintStablecoinResult", &actions.MintStablecoinResult{Supply: 250_000, Balance: 250_000}},
+		typedCase{"BurnStablecoinResult", &actions.BurnStablecoinResult{Supply: 200_000, Balance: 0}},
+		typedCase{"SetStablecoinBlockedResult", &actions.SetStablecoinBlockedResult{Account: bob, Blocked: true}},
+		typedCase{"SetStablecoinPausedResult", &actions.SetStablecoinPausedResult{Paused: true}},
+		typedCase{"HaltChainResult", &actions.HaltChainResult{Approvals: 2, Halted: true}},
+		typedCase{"ResumeChainResult", &actions.ResumeChainResult{Approvals: 1}},
+		typedCase{"DeploySmartAccountResult", &actions.DeploySmartAccountResult{Account: storage.SmartAccountAddress(alice, 1)}},
+		typedCase{"ExecuteFromSmartAccountResult", &actions.ExecuteFromSmartAccountResult{Approvals: 2, Executed: true}},
+		typedCase{"CreateLeaderboardResult", &actions.CreateLeaderboardResult{BoardID: storage.LeaderboardID(alice, 1)}},
+		typedCase{"SubmitScoreResult", &actions.SubmitScoreResult{Rank: 3, Recorded: true}},
+		typedCase{"OnboardAccountResult", &actions.OnboardAccountResult{Account: carol, Rent: 27, SponsorBalance: 98_973}},
+		typedCase{"CreateAssetResult", &actions.CreateAssetResult{Asset: storage.AssetID(alice, 1)}},
+		typedCase{"CreateLockerResult", &actions.CreateLockerResult{Locker: storage.LockerID(alice, 1), Custody: storage.LockerAddress(storage.LockerID(alice, 1))}},
+		typedCase{"DepositToLockerResult", &actions.DepositToLockerResult{Legs: 2, Items: 1}},
+		typedCase{"UnbundleLockerResult", &actions.UnbundleLockerResult{Custody: storage.LockerAddress(storage.LockerID(alice, 1))}},
+		typedCase{"SweepExpiredAssetsResult", &actions.SweepExpiredAssetsResult{Swept: 1, Bounty: 1_000}},
+		typedCase{"PermitTransferResult", &actions.PermitTransferResult{OldOwner: alice, NewOwner: bob}},
+		typedCase{"CreateAuctionResult", &actions.CreateAuctionResult{Custody: storage.AuctionAddress(asset)}},
+		typedCase{"PlaceBidResult", &actions.PlaceBidResult{Refunded: 1_000}},
+		typedCase{"SettleAuctionResult", &actions.SettleAuctionResult{Winner: bob, Paid: 1_500, Royalty: 37, RoyaltyPayee: carol}},
+		typedCase{"RegisterAppResult", &actions.RegisterAppResult{App: storage.AppID("team")}},
+		typedCase{"SetAppCreatorResult", &actions.SetAppCreatorResult{Creator: bob, Allowed: true}},
+	)
+}
+
+func zeroCase(t codec.Typed) typedCase {
+	v := reflect.New(reflect.TypeOf(t).Elem()).Interface().(codec.Typed)
+	return typedCase{reflect.TypeOf(t).Elem().Name() + "/zero", v}
+}
+
+type keyCase struct {
+	name string
+	key  []byte
+	args any
+}
+
+func keyCases() []keyCase {
+	return []keyCase{
+		{"BalanceKey", storage.BalanceKey(alice), map[string]any{"address": alice}},
+		{"HeightKey", storage.HeightKey(), nil},
+		{"TimestampKey", storage.TimestampKey(), nil},
+		{"FeeKey", storage.FeeKey(), nil},
+		{"AssetKey", storage.AssetKey(asset), map[string]any{"asset": asset}},
+		{"AssetKey/derived", storage.AssetKey(storage.AssetID(alice, 1)), map[string]any{"creator": alice, "nonce": 1}},
+		{"AssetKey/app", storage.AssetKey(storage.AppAssetID(storage.AppID("team"), alice, 1)), map[string]any{"appName": "team", "creator": alice, "nonce": 1}},
+		{"SequenceKey", storage.SequenceKey([]byte("counter")), map[string]any{"name": codec.Bytes("counter")}},
+		{"OrderSequenceKey", storage.OrderSequenceKey(alice), map[string]any{"maker": alice}},
+		{"TombstoneKey", storage.TombstoneKey(storage.AssetKey(asset)), map[string]any{"key": codec.Bytes(storage.AssetKey(asset))}},
+		{"AssetBalanceKey", storage.AssetBalanceKey(alice, asset), map[string]any{"address": alice, "asset": asset}},
+		{"NotificationKey", storage.NotificationKey(alice), map[string]any{"address": alice}},
+		{"AllowanceKey", storage.AllowanceKey(alice, bob), map[string]any{"owner": alice, "spender": bob}},
+		{"TreasuryCouncilKey", storage.TreasuryCouncilKey(), nil},
+		{"TreasuryProposalKey", storage.TreasuryProposalKey(id("proposal")), map[string]any{"proposalId": id("proposal")}},
+		{"OwnedAssetKey", storage.OwnedAssetKey(alice, asset), map[string]any{"owner": alice, "asset": asset}},
+		{"TransferHookKey", storage.TransferHookKey(asset), map[string]any{"asset": asset}},
+		{"SwapKey", storage.SwapKey(id("swap")), map[string]any{"swapId": id("swap")}},
+		{"VestingKey", storage.VestingKey(bob, id("vesting")), map[string]any{"beneficiary": bob, "vestingId": id("vesting")}},
+		{"EscrowKey", storage.EscrowKey(storage.EscrowID(alice, 7)), map[string]any{"payer": alice, "nonce": 7}},
+		{"OrderKey", storage.OrderKey(asset, storage.NativeAsset, storage.OrderID(alice, 3)), map[string]any{
+			"sellAsset": asset,
+			"buyAsset":  storage.NativeAsset,
+			"orderId":   storage.OrderID(alice, 3),
+		}},
+		{"PoolKey", storage.PoolKey(storage.NativeAsset, asset), map[string]any{"assetA": storage.NativeAsset, "assetB": asset}},
+		{"PoolSharesKey", storage.PoolSharesKey(storage.NativeAsset, asset, alice), map[string]any{
+			"assetA":   storage.NativeAsset,
+			"assetB":   asset,
+			"provider": alice,
+		}},
+		{"StakeKey", storage.StakeKey(alice), map[string]any{"staker": alice}},
+		{"ActiveProposalKey", storage.ActiveProposalKey(), nil},
+		{"GovernanceProposalKey", storage.GovernanceProposalKey(id("proposal")), map[string]any{"proposalId": id("proposal")}},
+		{"GovernanceVoteKey", storage.GovernanceVoteKey(id("proposal"), alice), map[string]any{
+			"proposalId": id("proposal"),
+			"voter":      alice,
+		}},
+		{"ConfigKey", storage.ConfigKey(actions.MaxMemoSizeRule), map[string]any{"key": actions.MaxMemoSizeRule}},
+		{"MultisigKey", storage.MultisigKey(storage.MultisigAddress(alice, 1)), map[string]any{"multisig": storage.MultisigAddress(alice, 1)}},
+		{"MultisigProposalKey", storage.MultisigProposalKey(storage.MultisigAddress(alice, 1), id("multisig")), map[string]any{
+			"multisig":   storage.MultisigAddress(alice, 1),
+			"proposalId": id("multisig"),
+		}},
+		{"CompactionKey", storage.CompactionKey(), nil},
+		{"ClaimKey", storage.ClaimKey(alice, []byte("profile")), map[string]any{"owner": alice, "key": codec.Bytes("profile")}},
+		{"SessionKey", storage.SessionKey(alice, id("session")), map[string]any{"owner": alice, "sessionId": id("session")}},
+		{"ReceiptKey", storage.ReceiptKey(asset), map[string]any{"asset": asset}},
+		{"StablecoinKey", storage.StablecoinKey(id("stablecoin")), map[string]any{"asset": id("stablecoin")}},
+		{"StablecoinBlockKey", storage.StablecoinBlockKey(id("stablecoin"), bob), map[string]any{"asset": id("stablecoin"), "account": bob}},
+		{"HaltKey", storage.HaltKey(), nil},
+		{"SecurityCouncilKey", storage.SecurityCouncilKey(), nil},
+		{"HaltProposalKey", storage.HaltProposalKey(id("incident")), map[string]any{"incident": id("incident")}},
+		{"LeaderboardKey", storage.LeaderboardKey(storage.LeaderboardID(alice, 1)), map[string]any{"creator": alice, "nonce": 1}},
+		{"LockerKey", storage.LockerKey(storage.LockerID(alice, 1)), map[string]any{"creator": alice, "nonce": 1}},
+		{"PermitKey", storage.PermitKey(alice, 7), map[string]any{"owner": alice, "nonce": 7}},
+		{"AuctionKey", storage.AuctionKey(asset), map[string]any{"asset": asset}},
+		{"AppKey", storage.AppKey(storage.AppID("team")), map[string]any{"appName": "team"}},
+		{"AppCreatorKey", storage.AppCreatorKey(storage.AppID("team"), alice), map[string]any{"appName": "team", "creator": alice}},
+		{"AppAssetKey", storage.AppAssetKey(storage.AppID("team"), asset), map[string]any{"appName": "team", "asset": asset}},
+	}
+}
+
+type txCase struct {
+	name    string
+	seed    string
+	base    *chain.Base
+	actions []chain.Action
+}
+
+// txCases holds transactions with one and with several actions, the latter
+// producing one result per action.
+func txCases() []txCase {
+	base := &chain.Base{Timestamp: 1_700_000_000_000, ChainID: chainID, MaxFee: 1_000_000}
+	return []txCase{
+		{"Transfer", "alice", base, []chain.Action{
+			&actions.Transfer{To: bob, Value: 1, Memo: []byte("hello")},
+		}},
+		{"MultiAction", "alice", base, []chain.Action{
+			&actions.Transfer{To: bob, Value: 1_000},
+			&actions.Approve{Spender: carol, Value: 500},
+			&actions.TransferAsset{To: carol, Asset: asset, Value: 7, Memo: []byte("memo")},
+		}},
+	}
+}
+
+// digester is an off-chain message signed over its digest.
+type digester interface {
+	Digest(chainID ids.ID) ([]byte, error)
+}
+
+type digestCase struct {
+	name  string
+	value digester
+}
+
+func digestCases() []digestCase {
+	return []digestCase{
+		{"Voucher", &voucher},
+		{"Permit", &permit},
+	}
+}
+
+// SeedKey returns the ed25519 key derived from [seed], as [TxVector.Seed]
+// holds it.
+func SeedKey(seed []byte) hed25519.PrivateKey {
+	return hed25519.PrivateKey(ed25519.NewKeyFromSeed(seed))
+}
+
+func txVector(c txCase) (TxVector, error) {
+	seed := id(c.seed)
+	tx, err := chain.NewTx(c.base, c.actions).Sign(auth.NewED25519Factory(SeedKey(seed[:])), vm.ActionParser, vm.AuthParser)
+	if err != nil {
+		return TxVector{}, fmt.Errorf("%s: %w", c.name, err)
+	}
+	vec := TxVector{
+		Name:      c.name,
+		Seed:      seed[:],
+		Timestamp: c.base.Timestamp,
+		ChainID:   c.base.ChainID,
+		MaxFee:    c.base.MaxFee,
+		ID:        tx.ID(),
+		Bytes:     tx.Bytes(),
+	}
+	for i, action := range c.actions {
+		actionVec, err := typedVector(typedCase{fmt.Sprintf("%s/%d", c.name, i), action}, func(p *codec.Packer) (codec.Typed, error) {
+			return vm.ActionParser.Unmarshal(p)
+		})
+		if err != nil {
+			return TxVector{}, err
+		}
+		vec.Actions = append(vec.Actions, actionVec)
+	}
+	return vec, nil
+}
+
+// Generate builds the vectors from the current codec.
+func Generate() (*Vectors, error) {
+	vmABI, err := abi.NewABI(vm.ActionParser.GetRegisteredTypes(), vm.OutputParser.GetRegisteredTypes())
+	if err != nil {
+		return nil, err
+	}
+	v := &Vectors{ABI: vmABI}
+	for _, c := range actionCases() {
+		vec, err := typedVector(c, func(p *codec.Packer) (codec.Typed, error) {
+			return vm.ActionParser.Unmarshal(p)
+		})
+		if err != nil {
+			return nil, err
+		}
+		v.Actions = append(v.Actions, vec)
+	}
+	for _, c := range outputCases() {
+		vec, err := typedVector(c, vm.OutputParser.Unmarshal)
+		if err != nil {
+			return nil, err
+		}
+		v.Outputs = append(v.Outputs, vec)
+	}
+	for _, c := range keyCases() {
+		value, err := json.Marshal(c.args)
+		if err != nil {
+			return nil, err
+		}
+		v.Keys = append(v.Keys, Vector{
+			Name:  c.name,
+			Value: value,
+			Bytes: c.key,
+		})
+	}
+	for _, c := range txCases() {
+		vec, err := txVector(c)
+		if err != nil {
+			return nil, err
+		}
+		v.Transactions = append(v.Transactions, vec)
+	}
+	for _, c := range digestCases() {
+		value, err := json.Marshal(c.value)
+		if err != nil {
+			return nil, err
+		}
+		digest, err := c.value.Digest(chainID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.name, err)
+		}
+		v.Digests = append(v.Digests, Vector{
+			Name:  c.name,
+			Value: value,
+			Bytes: digest,
+		})
+	}
+	return v, nil
+}
+
+// typedVector encodes [c]. The JSON value is taken from the decoded bytes,
+// so it matches what a client gets back, e.g. empty rather than null slices.
+func typedVector(c typedCase, decode func(*codec.Packer) (codec.Typed, error)) (Vector, error) {
+	b, err := chain.MarshalTyped(c.value)
+	if err != nil {
+		return Vector{}, fmt.Errorf("%s: %w", c.name, err)
+	}
+	decoded, err := decode(codec.NewReader(b, len(b)))
+	if err != nil {
+		return Vector{}, fmt.Errorf("%s: %w", c.name, err)
+	}
+	value, err := json.Marshal(decoded)
+	if err != nil {
+		return Vector{}, fmt.Errorf("%s: %w", c.name, err)
+	}
+	typeID := c.value.GetTypeID()
+	return Vector{
+		Name:   c.name,
+		TypeID: &typeID,
+		Value:  value,
+		Bytes:  b,
+	}, nil
+}
+
+// Marshal encodes [v] as it is stored in testdata/vectors.json.
+func Marshal(v *Vectors) ([]byte, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
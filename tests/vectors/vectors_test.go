@@ -0,0 +1,138 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vectors
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/vm"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+var fixture = filepath.Join("testdata", "vectors.json")
+
+// TestVectorsUpToDate fails when the codec no longer produces the committed
+// vectors. Changes that are intended must regenerate them with
+// cmd/test-vectors, so other clients pick them up.
+func TestVectorsUpToDate(t *testing.T) {
+	require := require.New(t)
+
+	v, err := Generate()
+	require.NoError(err)
+	want, err := Marshal(v)
+	require.NoError(err)
+	got, err := os.ReadFile(fixture)
+	require.NoError(err)
+	require.Equal(string(want), string(got), "run go run ./cmd/test-vectors")
+}
+
+// TestVectorsConformance decodes the committed vectors the way any client
+// must: bytes decode to the JSON value and re-encode to the same bytes.
+func TestVectorsConformance(t *testing.T) {
+	b, err := os.ReadFile(fixture)
+	require.NoError(t, err)
+	var v Vectors
+	require.NoError(t, json.Unmarshal(b, &v))
+
+	for _, vec := range v.Actions {
+		t.Run("actions/"+vec.Name, func(t *testing.T) {
+			p := codec.NewReader(vec.Bytes, len(vec.Bytes))
+			action, err := vm.ActionParser.Unmarshal(p)
+			require.NoError(t, err)
+			require.True(t, p.Empty())
+			checkTyped(t, vec, action)
+		})
+	}
+	for _, vec := range v.Outputs {
+		t.Run("outputs/"+vec.Name, func(t *testing.T) {
+			p := codec.NewReader(vec.Bytes, len(vec.Bytes))
+			output, err := vm.OutputParser.Unmarshal(p)
+			require.NoError(t, err)
+			require.True(t, p.Empty())
+			checkTyped(t, vec, output)
+		})
+	}
+	for _, vec := range v.Transactions {
+		t.Run("transactions/"+vec.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			p := codec.NewReader(vec.Bytes, len(vec.Bytes))
+			tx, err := chain.UnmarshalTx(p, vm.ActionParser, vm.AuthParser)
+			require.NoError(err)
+			require.True(p.Empty())
+			require.Equal(vec.ID, tx.ID())
+			require.Equal(vec.Timestamp, tx.Base.Timestamp)
+			require.Equal(vec.ChainID, tx.Base.ChainID)
+			require.Equal(vec.MaxFee, tx.Base.MaxFee)
+			require.Len(tx.Actions, len(vec.Actions))
+			for i, action := range tx.Actions {
+				checkTyped(t, vec.Actions[i], action)
+			}
+			require.Equal(auth.NewED25519Address(SeedKey(vec.Seed).PublicKey()), tx.Auth.Actor())
+		})
+	}
+	for _, vec := range v.Digests {
+		t.Run("digests/"+vec.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			var value digester
+			switch vec.Name {
+			case "Voucher":
+				value = &actions.Voucher{}
+			case "Permit":
+				value = &actions.Permit{}
+			default:
+				require.FailNow("unknown digest", vec.Name)
+			}
+			require.NoError(json.Unmarshal(vec.Value, value))
+			digest, err := value.Digest(v.Transactions[0].ChainID)
+			require.NoError(err)
+			require.Equal([]byte(vec.Bytes), digest)
+		})
+	}
+}
+
+func checkTyped(t *testing.T, vec Vector, value codec.Typed) {
+	require := require.New(t)
+
+	require.NotNil(vec.TypeID)
+	require.Equal(*vec.TypeID, value.GetTypeID())
+	b, err := chain.MarshalTyped(value)
+	require.NoError(err)
+	require.Equal([]byte(vec.Bytes), b)
+	j, err := json.Marshal(value)
+	require.NoError(err)
+	require.JSONEq(string(vec.Value), string(j))
+}
+
+// TestVectorsCoverRegisteredTypes requires a populated vector, besides the
+// zero value, for every registered action and output.
+func TestVectorsCoverRegisteredTypes(t *testing.T) {
+	v, err := Generate()
+	require.NoError(t, err)
+
+	for name, group := range map[string]struct {
+		vectors    []Vector
+		registered []codec.Typed
+	}{
+		"actions": {v.Actions, vm.ActionParser.GetRegisteredTypes()},
+		"outputs": {v.Outputs, vm.OutputParser.GetRegisteredTypes()},
+	} {
+		counts := map[uint8]int{}
+		for _, vec := range group.vectors {
+			counts[*vec.TypeID]++
+		}
+		for _, typ := range group.registered {
+			require.GreaterOrEqual(t, counts[typ.GetTypeID()], 2, "%s: type %d has no populated vector", name, typ.GetTypeID())
+		}
+	}
+}
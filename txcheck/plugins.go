@@ -0,0 +1,166 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ava-labs/avalanchego/cache"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// The plugins below ship with the node. They double as examples of the
+// plugin API.
+func init() {
+	Register("size", NewSizeCheck)
+	Register("addressPolicy", NewAddressPolicy)
+	Register("honeypot", NewHoneypot)
+}
+
+var (
+	ErrTooLarge        = errors.New("transaction too large")
+	ErrTooManyActions  = errors.New("too many actions")
+	ErrAddressType     = errors.New("address type not allowed")
+	ErrEmptyAddress    = errors.New("empty address")
+	ErrInvalidAuthType = errors.New("invalid auth type")
+	ErrHoneypot        = errors.New("transaction names a honeypot address")
+	ErrBanned          = errors.New("actor banned after a honeypot hit")
+)
+
+type SizeConfig struct {
+	// MaxBytes bounds the encoded size of a transaction. Zero means no
+	// bound beyond the network's.
+	MaxBytes int `json:"maxBytes"`
+
+	// MaxActions bounds the number of actions of a transaction. Zero means
+	// no bound beyond the chain's.
+	MaxActions int `json:"maxActions"`
+}
+
+// NewSizeCheck rejects transactions above the sizes of its [SizeConfig],
+// for nodes that want to admit less than the chain allows.
+func NewSizeCheck(config json.RawMessage) (Check, error) {
+	var c SizeConfig
+	if err := DecodeConfig(config, &c); err != nil {
+		return nil, err
+	}
+	return CheckFunc(func(_ context.Context, tx *chain.Transaction) error {
+		if c.MaxBytes > 0 && tx.Size() > c.MaxBytes {
+			return fmt.Errorf("%w: %d bytes > %d", ErrTooLarge, tx.Size(), c.MaxBytes)
+		}
+		if c.MaxActions > 0 && len(tx.Actions) > c.MaxActions {
+			return fmt.Errorf("%w: %d > %d", ErrTooManyActions, len(tx.Actions), c.MaxActions)
+		}
+		return nil
+	}), nil
+}
+
+type AddressPolicyConfig struct {
+	// AuthTypes are the auth type IDs, the first byte of an address,
+	// actions may name. Empty allows all of them.
+	AuthTypes []int `json:"authTypes"`
+
+	// AllowEmpty admits actions naming the empty address, which no key
+	// controls, so funds sent to it are lost.
+	AllowEmpty bool `json:"allowEmpty"`
+}
+
+// NewAddressPolicy rejects transactions whose actions name addresses outside
+// the policy of its [AddressPolicyConfig].
+func NewAddressPolicy(config json.RawMessage) (Check, error) {
+	var c AddressPolicyConfig
+	if err := DecodeConfig(config, &c); err != nil {
+		return nil, err
+	}
+	allowed := make([]byte, 0, len(c.AuthTypes))
+	for _, t := range c.AuthTypes {
+		if t < 0 || t > 255 {
+			return nil, fmt.Errorf("%w: %d", ErrInvalidAuthType, t)
+		}
+		allowed = append(allowed, byte(t))
+	}
+	return CheckFunc(func(_ context.Context, tx *chain.Transaction) error {
+		for i, action := range tx.Actions {
+			for _, addr := range Addresses(action) {
+				if addr == codec.EmptyAddress {
+					if c.AllowEmpty {
+						continue
+					}
+					return fmt.Errorf("%w in action %d", ErrEmptyAddress, i)
+				}
+				if len(allowed) > 0 && !slices.Contains(allowed, addr[0]) {
+					return fmt.Errorf("%w: %s in action %d", ErrAddressType, addr, i)
+				}
+			}
+		}
+		return nil
+	}), nil
+}
+
+type HoneypotConfig struct {
+	// Addresses are decoys no legitimate user has a reason to name.
+	Addresses []codec.Address `json:"addresses"`
+
+	// BanFor is how long the actor of a transaction naming a decoy has its
+	// later transactions rejected too. Zero rejects only the transaction.
+	BanFor time.Duration `json:"banFor"`
+
+	// BanCacheSize bounds how many banned actors are remembered.
+	BanCacheSize int `json:"banCacheSize"`
+}
+
+// Honeypot rejects transactions naming a decoy address, and bans their
+// actors, so that bots probing the node are turned away.
+type Honeypot struct {
+	config HoneypotConfig
+	traps  map[codec.Address]struct{}
+	banned *cache.LRU[codec.Address, time.Time]
+	now    func() time.Time
+}
+
+func NewHoneypot(config json.RawMessage) (Check, error) {
+	c := HoneypotConfig{BanCacheSize: 65_536}
+	if err := DecodeConfig(config, &c); err != nil {
+		return nil, err
+	}
+	h := &Honeypot{
+		config: c,
+		traps:  make(map[codec.Address]struct{}, len(c.Addresses)),
+		banned: &cache.LRU[codec.Address, time.Time]{Size: c.BanCacheSize},
+		now:    time.Now,
+	}
+	for _, addr := range c.Addresses {
+		h.traps[addr] = struct{}{}
+	}
+	return h, nil
+}
+
+func (h *Honeypot) Check(_ context.Context, tx *chain.Transaction) error {
+	actor := tx.Auth.Actor()
+	if until, ok := h.banned.Get(actor); ok {
+		if h.now().Before(until) {
+			return fmt.Errorf("%w: %s", ErrBanned, actor)
+		}
+		h.banned.Evict(actor)
+	}
+	for i, action := range tx.Actions {
+		for _, addr := range Addresses(action) {
+			if _, ok := h.traps[addr]; !ok {
+				continue
+			}
+			if h.config.BanFor > 0 {
+				h.banned.Put(actor, h.now().Add(h.config.BanFor))
+			}
+			return fmt.Errorf("%w: %s in action %d", ErrHoneypot, addr, i)
+		}
+	}
+	return nil
+}
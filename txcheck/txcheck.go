@@ -0,0 +1,200 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package txcheck runs stateless checks on decoded transactions before the
+// node admits them to the mempool.
+//
+// A plugin is a [Factory] registered under a name, usually from the init
+// function of its package, which the node binary then imports:
+//
+//	func init() {
+//		txcheck.Register("noMemo", func(json.RawMessage) (txcheck.Check, error) {
+//			return txcheck.CheckFunc(func(_ context.Context, tx *chain.Transaction) error {
+//				...
+//			}), nil
+//		})
+//	}
+//
+// Plugins are compiled into the node rather than loaded with the plugin
+// package, which only loads objects built with the exact same toolchain and
+// dependencies. Operators enable plugins, in the order they run, in the
+// txcheck section of the VM config:
+//
+//	{"txcheck": {"plugins": [{"name": "size", "config": {"maxActions": 4}}]}}
+package txcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+const Namespace = "txcheck"
+
+var (
+	ErrRejected      = errors.New("rejected by transaction check")
+	ErrUnknownPlugin = errors.New("unknown transaction check plugin")
+)
+
+// Check inspects a transaction before mempool admission and returns an
+// error to reject it. Checks see the decoded transaction only, not state,
+// and run for every submission, so they must be cheap and safe to call
+// concurrently.
+type Check interface {
+	Check(ctx context.Context, tx *chain.Transaction) error
+}
+
+// CheckFunc is a [Check] of a single function.
+type CheckFunc func(ctx context.Context, tx *chain.Transaction) error
+
+func (f CheckFunc) Check(ctx context.Context, tx *chain.Transaction) error {
+	return f(ctx, tx)
+}
+
+// Factory returns the [Check] of a plugin from the operator's config for
+// it, which is empty when the operator set none.
+type Factory func(config json.RawMessage) (Check, error)
+
+var (
+	lock      sync.RWMutex
+	factories = make(map[string]Factory)
+)
+
+// Register makes a plugin available under [name]. It panics if [name] is
+// already registered, since that is a programming error.
+func Register(name string, factory Factory) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if factory == nil {
+		panic("txcheck: nil factory for " + name)
+	}
+	if _, ok := factories[name]; ok {
+		panic("txcheck: duplicate plugin " + name)
+	}
+	factories[name] = factory
+}
+
+// Plugins returns the names of the registered plugins, sorted.
+func Plugins() []string {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+type PluginConfig struct {
+	Name   string          `json:"name"`
+	Config json.RawMessage `json:"config"`
+}
+
+type Config struct {
+	// Plugins are the checks to run, in order. The first rejection wins.
+	Plugins []PluginConfig `json:"plugins"`
+}
+
+func NewDefaultConfig() Config {
+	return Config{}
+}
+
+// Checks runs the plugins of a [Config].
+type Checks struct {
+	names  []string
+	checks []Check
+}
+
+func New(config Config) (*Checks, error) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	c := &Checks{}
+	for _, plugin := range config.Plugins {
+		factory, ok := factories[plugin.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownPlugin, plugin.Name)
+		}
+		check, err := factory(plugin.Config)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", plugin.Name, err)
+		}
+		c.names = append(c.names, plugin.Name)
+		c.checks = append(c.checks, check)
+	}
+	return c, nil
+}
+
+func (c *Checks) Len() int {
+	return len(c.checks)
+}
+
+// Screen runs the checks on [tx], so [Checks] screens submissions like a
+// screening.Screener.
+func (c *Checks) Screen(ctx context.Context, tx *chain.Transaction) error {
+	for i, check := range c.checks {
+		if err := check.Check(ctx, tx); err != nil {
+			return fmt.Errorf("%w %s: %w", ErrRejected, c.names[i], err)
+		}
+	}
+	return nil
+}
+
+// DecodeConfig unmarshals a plugin's [config] into [v], leaving [v] as is
+// when the operator set none.
+func DecodeConfig(config json.RawMessage, v any) error {
+	if len(config) == 0 {
+		return nil
+	}
+	return json.Unmarshal(config, v)
+}
+
+var addressType = reflect.TypeOf(codec.Address{})
+
+// Addresses returns the addresses in the fields of [action], including those
+// of nested structs and slices, in field order.
+func Addresses(action chain.Action) []codec.Address {
+	var addrs []codec.Address
+	collectAddresses(reflect.ValueOf(action), &addrs)
+	return addrs
+}
+
+func collectAddresses(v reflect.Value, addrs *[]codec.Address) {
+	if !v.IsValid() {
+		return
+	}
+	if v.Type() == addressType {
+		*addrs = append(*addrs, v.Interface().(codec.Address))
+		return
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			collectAddresses(v.Elem(), addrs)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				collectAddresses(v.Field(i), addrs)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			collectAddresses(v.Index(i), addrs)
+		}
+	default:
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+func newTx(t *testing.T, acts ...chain.Action) *chain.Transaction {
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(t, err)
+	return &chain.Transaction{
+		Actions: acts,
+		Auth:    &auth.ED25519{Signer: priv.PublicKey()},
+	}
+}
+
+// signedTx returns a transaction with its encoded size set.
+func signedTx(t *testing.T, acts ...chain.Action) *chain.Transaction {
+	require := require.New(t)
+	actionParser := codec.NewTypeParser[chain.Action]()
+	require.NoError(actionParser.Register(&actions.Transfer{}, nil))
+	authParser := codec.NewTypeParser[chain.Auth]()
+	require.NoError(authParser.Register(&auth.ED25519{}, auth.UnmarshalED25519))
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	tx, err := chain.NewTx(&chain.Base{Timestamp: 1_000, ChainID: [32]byte{1}, MaxFee: 1}, acts).
+		Sign(auth.NewED25519Factory(priv), actionParser, authParser)
+	require.NoError(err)
+	return tx
+}
+
+func newChecks(t *testing.T, plugins ...PluginConfig) *Checks {
+	c, err := New(Config{Plugins: plugins})
+	require.NoError(t, err)
+	return c
+}
+
+func TestNew(t *testing.T) {
+	require := require.New(t)
+
+	_, err := New(Config{Plugins: []PluginConfig{{Name: "missing"}}})
+	require.ErrorIs(err, ErrUnknownPlugin)
+	_, err = New(Config{Plugins: []PluginConfig{{Name: "addressPolicy", Config: json.RawMessage(`{"authTypes":[256]}`)}}})
+	require.ErrorIs(err, ErrInvalidAuthType)
+
+	c := newChecks(t)
+	require.Zero(c.Len())
+	require.NoError(c.Screen(context.Background(), newTx(t)))
+
+	require.Subset(Plugins(), []string{"addressPolicy", "honeypot", "size"})
+	require.Panics(func() { Register("size", NewSizeCheck) })
+}
+
+func TestAddresses(t *testing.T) {
+	a, b := codectest.NewRandomAddress(), codectest.NewRandomAddress()
+	require.Equal(t, []codec.Address{a, b}, Addresses(&actions.BatchTransfer{
+		Transfers: []actions.BatchTransferEntry{{To: a, Value: 1}, {To: b, Value: 2}},
+	}))
+	require.Empty(t, Addresses(nil))
+}
+
+func TestSizeCheck(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	transfer := &actions.Transfer{To: codectest.NewRandomAddress(), Value: 1}
+
+	c := newChecks(t, PluginConfig{Name: "size", Config: json.RawMessage(`{"maxActions":1}`)})
+	require.NoError(c.Screen(ctx, newTx(t, transfer)))
+	err := c.Screen(ctx, newTx(t, transfer, transfer))
+	require.ErrorIs(err, ErrRejected)
+	require.ErrorIs(err, ErrTooManyActions)
+
+	tx := signedTx(t, transfer)
+	c = newChecks(t, PluginConfig{Name: "size", Config: json.RawMessage(fmt.Sprintf(`{"maxBytes":%d}`, tx.Size()))})
+	require.NoError(c.Screen(ctx, tx))
+	big := signedTx(t, &actions.Transfer{To: transfer.To, Value: 1, Memo: []byte("a longer memo")})
+	require.ErrorIs(c.Screen(ctx, big), ErrTooLarge)
+}
+
+func TestAddressPolicy(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	ed25519Addr := auth.NewED25519Address(ed25519.PublicKey{1})
+	blsAddr := codec.CreateAddress(auth.BLSID, [32]byte{1})
+
+	c := newChecks(t, PluginConfig{Name: "addressPolicy", Config: json.RawMessage(`{"authTypes":[0]}`)})
+	require.NoError(c.Screen(ctx, newTx(t, &actions.Transfer{To: ed25519Addr, Value: 1})))
+	require.ErrorIs(c.Screen(ctx, newTx(t, &actions.Transfer{To: blsAddr, Value: 1})), ErrAddressType)
+	require.ErrorIs(c.Screen(ctx, newTx(t, &actions.Transfer{To: codec.EmptyAddress, Value: 1})), ErrEmptyAddress)
+
+	c = newChecks(t, PluginConfig{Name: "addressPolicy", Config: json.RawMessage(`{"allowEmpty":true}`)})
+	require.NoError(c.Screen(ctx, newTx(t, &actions.Transfer{To: blsAddr, Value: 1})))
+	require.NoError(c.Screen(ctx, newTx(t, &actions.Transfer{To: codec.EmptyAddress, Value: 1})))
+}
+
+func TestHoneypot(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	trap := codectest.NewRandomAddress()
+
+	check, err := NewHoneypot(json.RawMessage(fmt.Sprintf(`{"addresses":[%q],"banFor":%d}`, trap, time.Minute)))
+	require.NoError(err)
+	h := check.(*Honeypot)
+	now := time.Unix(0, 0)
+	h.now = func() time.Time { return now }
+
+	bot := newTx(t, &actions.Transfer{To: trap, Value: 1})
+	require.ErrorIs(h.Check(ctx, bot), ErrHoneypot)
+
+	// Later transactions of the bot are rejected until the ban ends.
+	later := &chain.Transaction{
+		Actions: []chain.Action{&actions.Transfer{To: codectest.NewRandomAddress(), Value: 1}},
+		Auth:    bot.Auth,
+	}
+	require.ErrorIs(h.Check(ctx, later), ErrBanned)
+	require.NoError(h.Check(ctx, newTx(t, later.Actions...)))
+	now = now.Add(time.Minute)
+	require.NoError(h.Check(ctx, later))
+}
+
+var errMemo = errors.New("memos are not accepted")
+
+// A plugin is registered from an init function and enabled by name in the
+// txcheck config.
+func ExampleRegister() {
+	Register("noMemo", func(json.RawMessage) (Check, error) {
+		return CheckFunc(func(_ context.Context, tx *chain.Transaction) error {
+			for _, action := range tx.Actions {
+				if transfer, ok := action.(*actions.Transfer); ok && len(transfer.Memo) > 0 {
+					return errMemo
+				}
+			}
+			return nil
+		}), nil
+	})
+
+	checks, err := New(Config{Plugins: []PluginConfig{{Name: "noMemo"}}})
+	if err != nil {
+		panic(err)
+	}
+	tx := &chain.Transaction{Actions: []chain.Action{&actions.Transfer{Memo: []byte("hi")}}}
+	fmt.Println(checks.Screen(context.Background(), tx))
+	// Output: rejected by transaction check noMemo: memos are not accepted
+}
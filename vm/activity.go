@@ -0,0 +1,259 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/pebbledb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/event"
+)
+
+// MaxTxsByAddressPage bounds the transactions returned by one
+// GetTxsByAddress call.
+const MaxTxsByAddressPage = 100
+
+var (
+	ErrActivityUnavailable = errors.New("address activity unavailable")
+	ErrTxNotFound          = errors.New("transaction not found")
+)
+
+var (
+	_ event.SubscriptionFactory[*chain.ExecutedBlock] = (*activity)(nil)
+	_ event.Subscription[*chain.ExecutedBlock]        = (*activity)(nil)
+)
+
+// Keys of the activity index:
+//
+// 0x0 => last indexed height
+// 0x1 + [txID] => transaction
+// 0x2 + [address] + [reverse height] + [reverse index] => txID
+//
+// Reversing the height and block index of the address entries makes a
+// forward scan return the newest transactions first.
+const (
+	activityHeightPrefix  = 0x0
+	activityTxPrefix      = 0x1
+	activityAddressPrefix = 0x2
+)
+
+var activityHeightKey = []byte{activityHeightPrefix}
+
+// IndexedTx is an accepted transaction with its actions and outputs
+// decoded.
+type IndexedTx struct {
+	StreamTx
+	// Index is the position of the transaction in its block.
+	Index     uint32        `json:"index"`
+	Timestamp int64         `json:"timestamp"`
+	Sponsor   codec.Address `json:"sponsor"`
+	Actions   []TxAction    `json:"actions"`
+}
+
+// TxAction is an action decoded with [ActionParser].
+type TxAction struct {
+	TypeID uint8 `json:"typeId"`
+	// Action is the JSON form of the registered action type.
+	Action json.RawMessage `json:"action"`
+}
+
+// activity indexes the transactions of accepted blocks by the addresses
+// they touched: the actor, the sponsor and the owners of every balance in
+// the transaction's state keys.
+type activity struct {
+	db database.Database
+}
+
+func newActivity(path string, log logging.Logger) (*activity, error) {
+	db, err := pebbledb.New(path, nil, log, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &activity{db: db}, nil
+}
+
+func activityPath(dataDir string) string {
+	return filepath.Join(dataDir, Namespace, "activity")
+}
+
+func activityTxKey(txID ids.ID) []byte {
+	return append([]byte{activityTxPrefix}, txID[:]...)
+}
+
+func activityAddressPrefixKey(addr codec.Address) []byte {
+	return append([]byte{activityAddressPrefix}, addr[:]...)
+}
+
+func activityAddressKey(addr codec.Address, height uint64, index uint32) []byte {
+	k := binary.BigEndian.AppendUint64(activityAddressPrefixKey(addr), storage.ReverseOrder(height))
+	return binary.BigEndian.AppendUint32(k, math.MaxUint32-index)
+}
+
+func (a *activity) New() (event.Subscription[*chain.ExecutedBlock], error) {
+	return a, nil
+}
+
+func (a *activity) Accept(blk *chain.ExecutedBlock) error {
+	height := blk.Block.Hght
+	last, err := getUint64(a.db, activityHeightKey)
+	if err != nil {
+		return err
+	}
+	if height <= last && height != 0 {
+		// Already indexed before a restart.
+		return nil
+	}
+	batch := a.db.NewBatch()
+	for i, tx := range blk.Block.Txs {
+		indexed := indexTx(height, blk.Block.Tmstmp, uint32(i), tx, blk.Results[i])
+		b, err := json.Marshal(indexed)
+		if err != nil {
+			return err
+		}
+		txID := tx.ID()
+		if err := batch.Put(activityTxKey(txID), b); err != nil {
+			return err
+		}
+		addrs, err := touchedAddresses(tx)
+		if err != nil {
+			return err
+		}
+		for addr := range addrs {
+			if err := batch.Put(activityAddressKey(addr, height, uint32(i)), txID[:]); err != nil {
+				return err
+			}
+		}
+	}
+	if err := batch.Put(activityHeightKey, binary.BigEndian.AppendUint64(nil, height)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+func indexTx(height uint64, timestamp int64, index uint32, tx *chain.Transaction, result *chain.Result) *IndexedTx {
+	indexed := &IndexedTx{
+		StreamTx:  *streamTx(height, tx, result),
+		Index:     index,
+		Timestamp: timestamp,
+		Sponsor:   tx.Sponsor(),
+		Actions:   make([]TxAction, len(tx.Actions)),
+	}
+	for i, action := range tx.Actions {
+		indexed.Actions[i].TypeID = action.GetTypeID()
+		indexed.Actions[i].Action, _ = json.Marshal(action)
+	}
+	return indexed
+}
+
+// touchedAddresses returns the actor and sponsor of [tx] and the owners of
+// the balances it could read or write.
+func touchedAddresses(tx *chain.Transaction) (set.Set[codec.Address], error) {
+	stateKeys, err := tx.StateKeys(&storage.StateManager{})
+	if err != nil {
+		return nil, err
+	}
+	addrs := set.Of(tx.Auth.Actor(), tx.Sponsor())
+	for k := range stateKeys {
+		if owner, ok := balanceKeyOwner([]byte(k)); ok {
+			addrs.Add(owner.Address)
+		}
+	}
+	return addrs, nil
+}
+
+// Tx returns the accepted transaction [txID].
+func (a *activity) Tx(txID ids.ID) (*IndexedTx, error) {
+	b, err := a.db.Get(activityTxKey(txID))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrTxNotFound, txID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var tx IndexedTx
+	return &tx, json.Unmarshal(b, &tx)
+}
+
+// addressTxs iterates over the transactions that touched one address,
+// newest first.
+type addressTxs struct {
+	a   *activity
+	it  database.Iterator
+	tx  *IndexedTx
+	err error
+}
+
+// ByAddress returns an iterator over the transactions that touched [addr],
+// starting at [start]. Pass [ids.Empty] to start from the newest one.
+//
+// The iterator must be released once done.
+func (a *activity) ByAddress(addr codec.Address, start ids.ID) (*addressTxs, error) {
+	prefix := activityAddressPrefixKey(addr)
+	startKey := prefix
+	if start != ids.Empty {
+		tx, err := a.Tx(start)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+		startKey = activityAddressKey(addr, tx.Height, tx.Index)
+	}
+	return &addressTxs{
+		a:  a,
+		it: a.db.NewIteratorWithStartAndPrefix(startKey, prefix),
+	}, nil
+}
+
+func (t *addressTxs) Next() bool {
+	if t.err != nil || !t.it.Next() {
+		return false
+	}
+	v := t.it.Value()
+	if len(v) != ids.IDLen || len(t.it.Key()) != 1+codec.AddressLen+consts.Uint64Len+consts.Uint32Len {
+		t.err = fmt.Errorf("%w: corrupt activity entry", ErrActivityUnavailable)
+		return false
+	}
+	t.tx, t.err = t.a.Tx(ids.ID(v))
+	return t.err == nil
+}
+
+// ID returns the current transaction ID. It is only valid after Next
+// returned true.
+func (t *addressTxs) ID() ids.ID {
+	return t.tx.TxID
+}
+
+// Tx returns the current transaction. It is only valid after Next returned
+// true.
+func (t *addressTxs) Tx() *IndexedTx {
+	return t.tx
+}
+
+func (t *addressTxs) Error() error {
+	if t.err != nil {
+		return t.err
+	}
+	return t.it.Error()
+}
+
+func (t *addressTxs) Release() {
+	t.it.Release()
+}
+
+func (a *activity) Close() error {
+	return a.db.Close()
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+func TestActivity(t *testing.T) {
+	require := require.New(t)
+	a, err := newActivity(t.TempDir(), logging.NoLog{})
+	require.NoError(err)
+	defer a.Close()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	to := codectest.NewRandomAddress()
+	other := codectest.NewRandomAddress()
+	transfer := func(to codec.Address) *chain.Transaction {
+		tx, err := (&chain.Transaction{
+			Base:    &chain.Base{Timestamp: 1_000, ChainID: ids.GenerateTestID(), MaxFee: 1},
+			Actions: []chain.Action{&actions.Transfer{To: to, Value: 5}},
+		}).Sign(auth.NewED25519Factory(priv), ActionParser, AuthParser)
+		require.NoError(err)
+		return tx
+	}
+	block := func(height uint64, tx *chain.Transaction, success bool) *chain.ExecutedBlock {
+		return &chain.ExecutedBlock{
+			Block:   &chain.StatelessBlock{Hght: height, Tmstmp: int64(height) * 1_000, Txs: []*chain.Transaction{tx}},
+			Results: []*chain.Result{{Success: success, Fee: 2}},
+		}
+	}
+	first, second := transfer(to), transfer(other)
+	require.NoError(a.Accept(block(1, first, true)))
+	// Failed transactions are indexed too.
+	require.NoError(a.Accept(block(2, second, false)))
+
+	tx, err := a.Tx(second.ID())
+	require.NoError(err)
+	require.Equal(uint64(2), tx.Height)
+	require.Equal(int64(2_000), tx.Timestamp)
+	require.Equal(sender, tx.Actor)
+	require.False(tx.Success)
+	require.Len(tx.Actions, 1)
+	require.Equal(mconsts.TransferID, tx.Actions[0].TypeID)
+	_, err = a.Tx(ids.GenerateTestID())
+	require.ErrorIs(err, ErrTxNotFound)
+
+	// txIDs lists the transactions that touched [addr], newest first.
+	txIDs := func(addr codec.Address, start ids.ID) []ids.ID {
+		it, err := a.ByAddress(addr, start)
+		require.NoError(err)
+		defer it.Release()
+		var listed []ids.ID
+		for it.Next() {
+			listed = append(listed, it.ID())
+		}
+		require.NoError(it.Error())
+		return listed
+	}
+	require.Equal([]ids.ID{second.ID(), first.ID()}, txIDs(sender, ids.Empty))
+	require.Equal([]ids.ID{first.ID()}, txIDs(to, ids.Empty))
+	require.Equal([]ids.ID{second.ID()}, txIDs(other, ids.Empty))
+	require.Empty(txIDs(codectest.NewRandomAddress(), ids.Empty))
+
+	// Listing resumes from a transaction.
+	require.Equal([]ids.ID{first.ID()}, txIDs(sender, first.ID()))
+	_, err = a.ByAddress(sender, ids.GenerateTestID())
+	require.ErrorIs(err, ErrInvalidCursor)
+
+	// Blocks indexed before a restart are not indexed twice.
+	require.NoError(a.Accept(block(1, transfer(other), true)))
+	require.Equal([]ids.ID{second.ID()}, txIDs(other, ids.Empty))
+}
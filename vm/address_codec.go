@@ -0,0 +1,219 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/hypersdk-starter-kit/evm"
+	"github.com/ava-labs/hypersdk/codec"
+
+	ajson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+var (
+	addressType         = reflect.TypeOf(codec.Address{})
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// newJSONRPCHandler serves [service] like api.NewJSONRPCHandler, except that
+// the args of its methods take addresses in EVM form too.
+func newJSONRPCHandler(name string, service any) (http.Handler, error) {
+	server := rpc.NewServer()
+	c := &addressCodec{Codec: ajson.NewCodec(), service: reflect.TypeOf(service)}
+	server.RegisterCodec(c, "application/json")
+	server.RegisterCodec(c, "application/json;charset=UTF-8")
+	return server, server.RegisterService(service, name)
+}
+
+// addressCodec rewrites the EVM addresses in the args of a request to the
+// form [codec.Address] decodes before passing the request on. Without it,
+// an EVM address would decode into the first 20 bytes of an address.
+type addressCodec struct {
+	rpc.Codec
+	service reflect.Type
+}
+
+func (c *addressCodec) NewRequest(r *http.Request) rpc.CodecRequest {
+	body, err := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	if err == nil {
+		body, err = c.rewriteRequest(body)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	req := c.Codec.NewRequest(r)
+	if err != nil {
+		return &failedRequest{CodecRequest: req, err: err}
+	}
+	return req
+}
+
+// rewriteRequest returns [body] with the EVM addresses in its params
+// rewritten. Requests it cannot make sense of are returned as they are, for
+// the inner codec to reject.
+func (c *addressCodec) rewriteRequest(body []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return body, nil
+	}
+	var method string
+	if err := json.Unmarshal(fields["method"], &method); err != nil {
+		return body, nil
+	}
+	args, ok := c.argsType(method)
+	if !ok || fields["params"] == nil {
+		return body, nil
+	}
+	// Params are either the args or an array of them.
+	params := fields["params"]
+	var list []json.RawMessage
+	if json.Unmarshal(params, &list) == nil {
+		args = reflect.SliceOf(args)
+	}
+	rewritten, err := rewriteEVMAddresses(params, args)
+	if err != nil || bytes.Equal(rewritten, params) {
+		return body, err
+	}
+	fields["params"] = rewritten
+	return json.Marshal(fields)
+}
+
+// argsType returns the type of the args of [method], given as Service.method.
+func (c *addressCodec) argsType(method string) (reflect.Type, bool) {
+	_, name, ok := strings.Cut(method, ".")
+	if !ok {
+		return nil, false
+	}
+	first, size := utf8.DecodeRuneInString(name)
+	m, ok := c.service.MethodByName(string(unicode.ToUpper(first)) + name[size:])
+	// Methods take the receiver, the request, the args and the reply.
+	if !ok || m.Type.NumIn() != 4 || m.Type.In(2).Kind() != reflect.Pointer {
+		return nil, false
+	}
+	return m.Type.In(2).Elem(), true
+}
+
+// rewriteEVMAddresses returns [raw], the JSON of a value of type [t], with
+// the EVM addresses in the [codec.Address] values within it rewritten.
+func rewriteEVMAddresses(raw json.RawMessage, t reflect.Type) (json.RawMessage, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == addressType {
+		var s string
+		if json.Unmarshal(raw, &s) != nil || !evm.IsEVMString(s) {
+			return raw, nil
+		}
+		a, err := evm.ParseEVMAddress(s)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(a.ToAddress())
+	}
+	// Values that decode themselves are left to do so.
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return raw, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(raw, &fields) != nil {
+			return raw, nil
+		}
+		changed := false
+		for name, v := range fields {
+			ft, ok := fieldType(t, name)
+			if !ok {
+				continue
+			}
+			rewritten, err := rewriteEVMAddresses(v, ft)
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(rewritten, v) {
+				fields[name] = rewritten
+				changed = true
+			}
+		}
+		if !changed {
+			return raw, nil
+		}
+		return json.Marshal(fields)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return raw, nil
+		}
+		var elems []json.RawMessage
+		if json.Unmarshal(raw, &elems) != nil {
+			return raw, nil
+		}
+		changed := false
+		for i, v := range elems {
+			rewritten, err := rewriteEVMAddresses(v, t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(rewritten, v) {
+				elems[i] = rewritten
+				changed = true
+			}
+		}
+		if !changed {
+			return raw, nil
+		}
+		return json.Marshal(elems)
+	default:
+		return raw, nil
+	}
+}
+
+// fieldType returns the type of the field of struct [t] that encoding/json
+// decodes the key [name] into, looking through embedded structs.
+func fieldType(t reflect.Type, name string) (reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+			if ft, ok := fieldType(f.Type, name); ok {
+				return ft, true
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+		if strings.EqualFold(tag, name) {
+			return f.Type, true
+		}
+	}
+	return nil, false
+}
+
+// failedRequest is a request whose args could not be rewritten.
+type failedRequest struct {
+	rpc.CodecRequest
+	err error
+}
+
+func (r *failedRequest) ReadRequest(any) error {
+	return r.err
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/evm"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+)
+
+type EchoArgs struct {
+	Address codec.Address   `json:"address"`
+	Voter   *codec.Address  `json:"voter,omitempty"`
+	Signers []codec.Address `json:"signers"`
+	Memo    codec.Bytes     `json:"memo"`
+	ReadOptions
+}
+
+type EchoService struct{}
+
+func (*EchoService) Echo(_ *http.Request, args *EchoArgs, reply *EchoArgs) error {
+	*reply = *args
+	return nil
+}
+
+func TestAddressCodec(t *testing.T) {
+	handler, err := newJSONRPCHandler("test", &EchoService{})
+	require.NoError(t, err)
+
+	const evmAddr = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	a, err := evm.ParseEVMAddress(evmAddr)
+	require.NoError(t, err)
+	mapped := a.ToAddress()
+	native := codectest.NewRandomAddress()
+	// A 20-byte memo is not an address.
+	memo := strings.TrimPrefix(evmAddr, "0x")
+
+	call := func(params string) (*EchoArgs, string) {
+		body := `{"jsonrpc":"2.0","id":1,"method":"test.echo","params":` + params + `}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		var resp struct {
+			Result *EchoArgs `json:"result"`
+			Error  *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		if resp.Error != nil {
+			return nil, resp.Error.Message
+		}
+		return resp.Result, ""
+	}
+
+	t.Run("EVM", func(t *testing.T) {
+		require := require.New(t)
+		params := `{"address":"` + evmAddr + `","voter":"` + strings.ToLower(evmAddr) + `","signers":["` + evmAddr + `","0x` + native.String() + `"],"memo":"` + memo + `","minHeight":3}`
+		reply, errMsg := call(params)
+		require.Empty(errMsg)
+		require.Equal(mapped, reply.Address)
+		require.Equal(&mapped, reply.Voter)
+		require.Equal([]codec.Address{mapped, native}, reply.Signers)
+		require.Equal(codec.Bytes(a[:]), reply.Memo)
+		require.Equal(uint64(3), reply.MinHeight)
+	})
+
+	t.Run("ArrayParams", func(t *testing.T) {
+		reply, errMsg := call(`[{"address":"` + evmAddr + `"}]`)
+		require.Empty(t, errMsg)
+		require.Equal(t, mapped, reply.Address)
+	})
+
+	t.Run("Native", func(t *testing.T) {
+		reply, errMsg := call(`{"address":"0x` + native.String() + `"}`)
+		require.Empty(t, errMsg)
+		require.Equal(t, native, reply.Address)
+	})
+
+	t.Run("BadChecksum", func(t *testing.T) {
+		_, errMsg := call(`{"address":"0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"}`)
+		require.Contains(t, errMsg, evm.ErrBadChecksum.Error())
+	})
+}
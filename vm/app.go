@@ -0,0 +1,40 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+)
+
+// existingAssets skips the assets of a [storage.GetAssetsByApp] iterator
+// that were burned, as burning leaves the app index untouched.
+type existingAssets struct {
+	*storage.AppEntries
+	db  *pinnedState
+	err error
+}
+
+func (e *existingAssets) Next() bool {
+	for e.err == nil && e.AppEntries.Next() {
+		_, err := e.db.get(storage.AssetKey(e.Asset()))
+		switch {
+		case err == nil:
+			return true
+		case !errors.Is(err, database.ErrNotFound):
+			e.err = err
+		}
+	}
+	return false
+}
+
+func (e *existingAssets) Error() error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.AppEntries.Error()
+}
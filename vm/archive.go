@@ -0,0 +1,235 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/pebbledb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/event"
+)
+
+// Modes of [Config.StateMode].
+const (
+	PrunedStateMode   = "pruned"
+	ArchivalStateMode = "archival"
+)
+
+// archiveClearBatchSize bounds the deletions written at once when the
+// archive is cleared.
+const archiveClearBatchSize = units.MiB
+
+var ErrUnknownStateMode = errors.New("unknown state mode")
+
+var (
+	_ event.SubscriptionFactory[*chain.ExecutedBlock] = (*archive)(nil)
+	_ event.Subscription[*chain.ExecutedBlock]        = (*archive)(nil)
+)
+
+// archive keeps every version of state in archival mode, so that reads
+// pinned to any height since it started are served once the merkledb
+// history has dropped it.
+//
+// Like the [journal], it runs one block behind the chain. The first block
+// it sees is preceded by a copy of all of state, so that keys that never
+// change afterwards are also covered.
+type archive struct {
+	db      database.Database
+	history historicalState
+	log     logging.Logger
+}
+
+func newArchive(path string, history historicalState, log logging.Logger) (*archive, error) {
+	db, err := pebbledb.New(path, nil, log, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &archive{
+		db:      db,
+		history: history,
+		log:     log,
+	}, nil
+}
+
+func archivePath(dataDir string) string {
+	return filepath.Join(dataDir, Namespace, "archive")
+}
+
+func (a *archive) New() (event.Subscription[*chain.ExecutedBlock], error) {
+	return a, nil
+}
+
+func (a *archive) Accept(blk *chain.ExecutedBlock) error {
+	height := blk.Block.Hght
+	if height == 0 {
+		return nil
+	}
+	oldest, newest, ok, err := storage.GetArchiveRange(a.db)
+	if err != nil {
+		return err
+	}
+	if ok && height-1 <= newest {
+		return nil
+	}
+	ctx := context.Background()
+	parent, err := a.history.GetDiskBlock(ctx, height-1)
+	if err != nil {
+		return err
+	}
+	db, err := a.history.State()
+	if err != nil {
+		return err
+	}
+
+	batch := a.db.NewBatch()
+	if !ok || height-1 != newest+1 {
+		if ok {
+			// Versions from before the gap would be served for heights
+			// after it.
+			a.log.Warn("restarting state archive",
+				zap.Uint64("newest", newest),
+				zap.Uint64("height", height-1),
+			)
+			if err := a.clear(); err != nil {
+				return err
+			}
+		}
+		// The parent carries the root of the state after the block before
+		// it, or after genesis.
+		oldest = max(height, 2) - 2
+		err = a.copyState(ctx, db, parent.StateRoot, oldest, batch)
+	}
+	if err == nil {
+		err = a.putChanges(ctx, db, parent.StateRoot, blk.Block.StateRoot, height-1, batch)
+	}
+	if errors.Is(err, merkledb.ErrInsufficientHistory) {
+		// Expected right after state sync. The archive starts over from the
+		// next block whose parent state is still in history.
+		a.log.Warn("skipping state archive",
+			zap.Uint64("height", height-1),
+			zap.Error(err),
+		)
+		if ok {
+			return a.clear()
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := storage.SetArchiveRange(batch, oldest, height-1); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// copyState archives every key of the state at [root] as of [height].
+func (a *archive) copyState(ctx context.Context, db merkledb.MerkleDB, root ids.ID, height uint64, w database.KeyValueWriter) error {
+	start := maybe.Nothing[[]byte]()
+	for {
+		proof, err := db.GetRangeProofAtRoot(ctx, root, start, maybe.Nothing[[]byte](), journalPageSize)
+		if errors.Is(err, merkledb.ErrEmptyProof) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, kv := range proof.KeyValues {
+			if err := storage.PutArchived(w, kv.Key, height, kv.Value, false); err != nil {
+				return err
+			}
+		}
+		if len(proof.KeyValues) < journalPageSize {
+			return nil
+		}
+		last := proof.KeyValues[len(proof.KeyValues)-1].Key
+		start = maybe.Some(append(last[:len(last):len(last)], 0))
+	}
+}
+
+// putChanges archives the keys changed from [startRoot] to [endRoot] as of
+// [height].
+func (a *archive) putChanges(ctx context.Context, db merkledb.MerkleDB, startRoot, endRoot ids.ID, height uint64, w database.KeyValueWriter) error {
+	if startRoot == endRoot {
+		return nil
+	}
+	start := maybe.Nothing[[]byte]()
+	for {
+		proof, err := db.GetChangeProof(ctx, startRoot, endRoot, start, maybe.Nothing[[]byte](), journalPageSize)
+		if err != nil {
+			return err
+		}
+		for _, change := range proof.KeyChanges {
+			if err := storage.PutArchived(w, change.Key, height, change.Value.Value(), change.Value.IsNothing()); err != nil {
+				return err
+			}
+		}
+		if len(proof.KeyChanges) < journalPageSize {
+			return nil
+		}
+		last := proof.KeyChanges[len(proof.KeyChanges)-1].Key
+		start = maybe.Some(append(last[:len(last):len(last)], 0))
+	}
+}
+
+// clear drops every version, leaving the archive empty.
+func (a *archive) clear() error {
+	it := a.db.NewIterator()
+	defer it.Release()
+
+	batch := a.db.NewBatch()
+	for it.Next() {
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+		if batch.Size() < archiveClearBatchSize {
+			continue
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// Oldest returns the oldest height the archive serves, if it serves any.
+func (a *archive) Oldest() (uint64, bool, error) {
+	oldest, _, ok, err := storage.GetArchiveRange(a.db)
+	return oldest, ok, err
+}
+
+// ReadState serves values as they were after the block at [height].
+func (a *archive) ReadState(height uint64) storage.ReadState {
+	read := storage.ReadArchived(a.db, height)
+	return func(ctx context.Context, keys [][]byte) ([][]byte, []error) {
+		values, errs := read(ctx, keys)
+		for i, err := range errs {
+			if errors.Is(err, storage.ErrNotArchived) {
+				errs[i] = fmt.Errorf("%w: %w", ErrHistoryUnavailable, err)
+			}
+		}
+		return values, errs
+	}
+}
+
+func (a *archive) Close() error {
+	return a.db.Close()
+}
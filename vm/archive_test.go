@@ -0,0 +1,95 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+func TestArchive(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	_, db := newTestLists(t)
+	history := rootHistory{merkleHistory: merkleHistory{db}, roots: make(map[uint64]ids.ID)}
+	commit := func(height uint64, puts map[string]string, deletes ...string) {
+		batch := db.NewBatch()
+		for k, v := range puts {
+			require.NoError(batch.Put([]byte(k), []byte(v)))
+		}
+		for _, k := range deletes {
+			require.NoError(batch.Delete([]byte(k)))
+		}
+		require.NoError(batch.Write())
+		root, err := db.GetMerkleRoot(ctx)
+		require.NoError(err)
+		history.roots[height] = root
+	}
+	accept := func(a *archive, height uint64) {
+		require.NoError(a.Accept(&chain.ExecutedBlock{
+			Block: &chain.StatelessBlock{Hght: height, StateRoot: history.roots[height-1]},
+		}))
+	}
+	// read returns the value of [key] after the block at [height].
+	read := func(a *archive, height uint64, key string) ([]byte, error) {
+		values, errs := a.ReadState(height)(ctx, [][]byte{[]byte(key)})
+		return values[0], errs[0]
+	}
+
+	commit(0, map[string]string{"a": "1", "b": "2"})
+	commit(1, map[string]string{"a": "3"}, "b")
+	commit(2, map[string]string{"c": "4"})
+
+	a, err := newArchive(t.TempDir(), history, logging.NoLog{})
+	require.NoError(err)
+	defer a.Close()
+
+	// The first block archives a copy of state as of its grandparent, then
+	// the changes of its parent.
+	accept(a, 2)
+	oldest, ok, err := a.Oldest()
+	require.NoError(err)
+	require.True(ok)
+	require.Zero(oldest)
+	v, err := read(a, 0, "b")
+	require.NoError(err)
+	require.Equal([]byte("2"), v)
+	v, err = read(a, 1, "a")
+	require.NoError(err)
+	require.Equal([]byte("3"), v)
+	_, err = read(a, 1, "b")
+	require.ErrorIs(err, database.ErrNotFound)
+	_, err = read(a, 2, "c")
+	require.ErrorIs(err, ErrHistoryUnavailable)
+
+	// Keys a block leaves alone keep their older version.
+	accept(a, 3)
+	v, err = read(a, 2, "c")
+	require.NoError(err)
+	require.Equal([]byte("4"), v)
+	v, err = read(a, 2, "a")
+	require.NoError(err)
+	require.Equal([]byte("3"), v)
+
+	// A gap in the heights starts the archive over.
+	commit(3, nil)
+	commit(4, map[string]string{"a": "5"})
+	commit(5, nil)
+	accept(a, 6)
+	oldest, _, err = a.Oldest()
+	require.NoError(err)
+	require.Equal(uint64(4), oldest)
+	_, err = read(a, 2, "a")
+	require.ErrorIs(err, ErrHistoryUnavailable)
+	v, err = read(a, 5, "a")
+	require.NoError(err)
+	require.Equal([]byte("5"), v)
+}
@@ -0,0 +1,204 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/pebbledb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/event"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// MaxAssetHistoryPage bounds the transfers returned by one AssetHistory
+// call.
+const MaxAssetHistoryPage = 100
+
+var ErrAssetHistoryUnavailable = errors.New("asset history unavailable")
+
+var (
+	_ event.SubscriptionFactory[*chain.ExecutedBlock] = (*assetHistory)(nil)
+	_ event.Subscription[*chain.ExecutedBlock]        = (*assetHistory)(nil)
+)
+
+// Keys of the asset history index:
+//
+// 0x0 => last indexed height
+// 0x1 + [assetID] + [chunks] => transfer count, a [storage.NextSequence]
+// counter
+// 0x2 + [assetID] + [sequence] => transfer
+const (
+	assetHistoryHeightPrefix = 0x0
+	assetHistoryCountPrefix  = 0x1
+	assetTransferPrefix      = 0x2
+)
+
+var assetHistoryHeightKey = []byte{assetHistoryHeightPrefix}
+
+// AssetOwnerChange is one change of the owner of an asset. [From] is empty
+// when the asset was minted and [To] when it was burned.
+type AssetOwnerChange struct {
+	// Sequence numbers the transfers of an asset from zero, in the order
+	// they were accepted.
+	Sequence uint64        `json:"sequence"`
+	Height   uint64        `json:"height"`
+	TxID     ids.ID        `json:"txId"`
+	From     codec.Address `json:"from"`
+	To       codec.Address `json:"to"`
+	Reason   string        `json:"reason"`
+}
+
+// assetHistory indexes the [actions.OwnershipEvent]s of accepted blocks, so
+// it follows every owner change, including those made by multisig and smart
+// account transactions, permits, lockers and auctions.
+type assetHistory struct {
+	db database.Database
+}
+
+func newAssetHistory(path string, log logging.Logger) (*assetHistory, error) {
+	db, err := pebbledb.New(path, nil, log, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &assetHistory{db: db}, nil
+}
+
+func assetHistoryPath(dataDir string) string {
+	return filepath.Join(dataDir, Namespace, "asset_history")
+}
+
+func assetHistoryCountKey(assetID ids.ID) []byte {
+	k := append([]byte{assetHistoryCountPrefix}, assetID[:]...)
+	return binary.BigEndian.AppendUint16(k, storage.SequenceChunks)
+}
+
+func assetTransferKey(assetID ids.ID, sequence uint64) []byte {
+	k := append([]byte{assetTransferPrefix}, assetID[:]...)
+	return binary.BigEndian.AppendUint64(k, sequence)
+}
+
+func (a *assetHistory) New() (event.Subscription[*chain.ExecutedBlock], error) {
+	return a, nil
+}
+
+func (a *assetHistory) Accept(blk *chain.ExecutedBlock) error {
+	height := blk.Block.Hght
+	last, err := getUint64(a.db, assetHistoryHeightKey)
+	if err != nil {
+		return err
+	}
+	if height <= last && height != 0 {
+		// Already indexed before a restart.
+		return nil
+	}
+	batch := newBatchState(a.db)
+	err = blockEvents(blk, func(tx *chain.Transaction, _ int, e actions.Event) error {
+		o, ok := e.(*actions.OwnershipEvent)
+		if !ok {
+			return nil
+		}
+		sequence, err := storage.NextSequence(context.Background(), batch, assetHistoryCountKey(o.Asset))
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(&AssetOwnerChange{
+			Sequence: sequence,
+			Height:   height,
+			TxID:     tx.ID(),
+			From:     o.From,
+			To:       o.To,
+			Reason:   o.Reason,
+		})
+		if err != nil {
+			return err
+		}
+		return batch.Put(assetTransferKey(o.Asset, sequence), b)
+	})
+	if err != nil {
+		return err
+	}
+	if err := batch.Put(assetHistoryHeightKey, binary.BigEndian.AppendUint64(nil, height)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// Page returns up to [limit] transfers of [assetID] with a sequence below
+// [cursor], newest first, and the cursor of the next page. A zero [cursor]
+// starts from the newest transfer; a zero next cursor means there are no
+// older transfers.
+func (a *assetHistory) Page(assetID ids.ID, cursor uint64, limit int) ([]*AssetOwnerChange, uint64, error) {
+	count, err := getUint64(a.db, assetHistoryCountKey(assetID))
+	if err != nil {
+		return nil, 0, err
+	}
+	if cursor == 0 || cursor > count {
+		cursor = count
+	}
+	changes := make([]*AssetOwnerChange, 0, min(uint64(limit), cursor))
+	for ; cursor > 0 && len(changes) < limit; cursor-- {
+		b, err := a.db.Get(assetTransferKey(assetID, cursor-1))
+		if err != nil {
+			return nil, 0, err
+		}
+		var c AssetOwnerChange
+		if err := json.Unmarshal(b, &c); err != nil {
+			return nil, 0, err
+		}
+		changes = append(changes, &c)
+	}
+	return changes, cursor, nil
+}
+
+func (a *assetHistory) Close() error {
+	return a.db.Close()
+}
+
+var _ state.Mutable = (*batchState)(nil)
+
+// batchState is a [database.Batch] of [db] that reads its own writes, so the
+// storage helpers can update an index within one batch.
+type batchState struct {
+	database.Batch
+	db database.Database
+	// pending holds the values written to the batch. A nil value was
+	// removed.
+	pending map[string][]byte
+}
+
+func newBatchState(db database.Database) *batchState {
+	return &batchState{Batch: db.NewBatch(), db: db, pending: map[string][]byte{}}
+}
+
+func (b *batchState) GetValue(_ context.Context, key []byte) ([]byte, error) {
+	if v, ok := b.pending[string(key)]; ok {
+		if v == nil {
+			return nil, database.ErrNotFound
+		}
+		return v, nil
+	}
+	return b.db.Get(key)
+}
+
+func (b *batchState) Insert(_ context.Context, key []byte, value []byte) error {
+	b.pending[string(key)] = value
+	return b.Batch.Put(key, value)
+}
+
+func (b *batchState) Remove(_ context.Context, key []byte) error {
+	b.pending[string(key)] = nil
+	return b.Batch.Delete(key)
+}
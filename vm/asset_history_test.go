@@ -0,0 +1,110 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+func TestAssetHistory(t *testing.T) {
+	require := require.New(t)
+	h, err := newAssetHistory(t.TempDir(), logging.NoLog{})
+	require.NoError(err)
+	defer h.Close()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	multisig := storage.MultisigAddress(sender, 0)
+	buyer := codectest.NewRandomAddress()
+	bidder := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+
+	// block has one transaction of [action] with [output], which failed
+	// unless [success].
+	block := func(height uint64, action chain.Action, output codec.Typed, success bool) *chain.ExecutedBlock {
+		b, err := chain.MarshalTyped(output)
+		require.NoError(err)
+		return &chain.ExecutedBlock{
+			Block: &chain.StatelessBlock{
+				Hght: height,
+				Txs: []*chain.Transaction{{
+					Actions: []chain.Action{action},
+					Auth:    &auth.ED25519{Signer: priv.PublicKey()},
+				}},
+			},
+			Results: []*chain.Result{{Success: success, Outputs: [][]byte{b}}},
+		}
+	}
+	approve := block(2,
+		&actions.ApproveMultisigTx{Multisig: multisig, Tx: storage.MultisigTx{To: buyer, Asset: asset}},
+		&actions.ApproveMultisigTxResult{Approvals: 2, Executed: true},
+		true,
+	)
+	require.NoError(h.Accept(block(1, &actions.MintAsset{Asset: asset}, &actions.MintAssetResult{Asset: asset, Owner: sender}, true)))
+	require.NoError(h.Accept(approve))
+	require.NoError(h.Accept(block(3,
+		&actions.PermitTransfer{Permit: actions.Permit{Asset: asset, Recipient: storage.AuctionAddress(asset)}},
+		&actions.PermitTransferResult{OldOwner: buyer, NewOwner: storage.AuctionAddress(asset)},
+		true,
+	)))
+	require.NoError(h.Accept(block(4,
+		&actions.AssetTransfer{Recipient: sender, Asset: asset, Reason: "failed"},
+		&actions.AssetTransferResult{OldOwner: storage.AuctionAddress(asset), NewOwner: sender},
+		false,
+	)))
+	require.NoError(h.Accept(block(5,
+		&actions.SettleAuction{Asset: asset, Seller: buyer, Bidder: bidder},
+		&actions.SettleAuctionResult{Winner: bidder, Paid: 10},
+		true,
+	)))
+	require.NoError(h.Accept(block(6,
+		&actions.AssetTransfer{Recipient: sender, Asset: asset, Reason: "gift"},
+		&actions.AssetTransferResult{OldOwner: bidder, NewOwner: sender},
+		true,
+	)))
+	// A block is indexed once, however often it is delivered.
+	require.NoError(h.Accept(approve))
+
+	changes, next, err := h.Page(asset, 0, MaxAssetHistoryPage)
+	require.NoError(err)
+	require.Zero(next)
+	type change struct {
+		height   uint64
+		from, to codec.Address
+		reason   string
+	}
+	var got []change
+	for i, c := range changes {
+		require.Equal(uint64(len(changes)-1-i), c.Sequence)
+		got = append(got, change{c.Height, c.From, c.To, c.Reason})
+	}
+	require.Equal([]change{
+		{6, bidder, sender, "gift"},
+		{5, storage.AuctionAddress(asset), bidder, ""},
+		{3, buyer, storage.AuctionAddress(asset), ""},
+		{2, multisig, buyer, ""},
+		{1, codec.EmptyAddress, sender, ""},
+	}, got)
+
+	// Pages walk back from the cursor.
+	changes, next, err = h.Page(asset, 3, 2)
+	require.NoError(err)
+	require.Equal(uint64(1), next)
+	require.Len(changes, 2)
+	require.Equal(uint64(2), changes[0].Sequence)
+	require.Equal(uint64(1), changes[1].Sequence)
+}
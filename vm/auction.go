@@ -0,0 +1,23 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import "github.com/ava-labs/hypersdk-starter-kit/storage"
+
+// liveAuctions skips the auctions of a [storage.Auctions] iterator that
+// stopped taking bids before [now], unless [includeEnded] is set.
+type liveAuctions struct {
+	*storage.Auctions
+	now          int64
+	includeEnded bool
+}
+
+func (l *liveAuctions) Next() bool {
+	for l.Auctions.Next() {
+		if l.includeEnded || l.Auction().End >= l.now {
+			return true
+		}
+	}
+	return false
+}
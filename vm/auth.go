@@ -0,0 +1,119 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"crypto/rand"
+	"sync/atomic"
+
+	blst "github.com/supranational/blst/bindings/go"
+
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto"
+	"github.com/ava-labs/hypersdk/vm"
+)
+
+const (
+	// blsMinBatchSize is the fewest BLS signatures verified together while
+	// more are pending.
+	blsMinBatchSize = 16
+
+	// blsRandBits is the size of the random weight of every signature in a
+	// batch. An invalid batch verifies with probability 2^-blsRandBits.
+	blsRandBits = 64
+)
+
+// blsSignatureDST must match the ciphersuite [auth.BLS] signs with.
+var blsSignatureDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+var (
+	_ vm.AuthEngine           = (*blsAuthEngine)(nil)
+	_ chain.AuthBatchVerifier = (*blsBatch)(nil)
+)
+
+// authEngines returns the batch verifiers of the auth types that support
+// one: ed25519 batches and BLS weighted aggregates. SECP256R1 signatures
+// are verified one by one.
+func authEngines() map[uint8]vm.AuthEngine {
+	engines := auth.Engines()
+	engines[auth.BLSID] = &blsAuthEngine{}
+	return engines
+}
+
+type blsAuthEngine struct{}
+
+func (*blsAuthEngine) GetBatchVerifier(cores int, count int) chain.AuthBatchVerifier {
+	return &blsBatch{batchSize: max(count/cores, blsMinBatchSize)}
+}
+
+func (*blsAuthEngine) Cache(chain.Auth) {}
+
+// blsBatch verifies BLS signatures over distinct messages with one
+// multi-pairing per batch. Every signature and key is weighted by a fresh
+// random scalar, so signatures that are invalid on their own cannot cancel
+// out in the aggregate, even for keys without a proof of possession.
+type blsBatch struct {
+	batchSize int
+
+	sigs []*blst.P2Affine
+	pks  []*blst.P1Affine
+	msgs []blst.Message
+}
+
+func (b *blsBatch) Add(msg []byte, rauth chain.Auth) func() error {
+	a := rauth.(*auth.BLS)
+	b.sigs = append(b.sigs, a.Signature)
+	b.pks = append(b.pks, a.Signer)
+	b.msgs = append(b.msgs, msg)
+	if len(b.sigs) < b.batchSize {
+		return nil
+	}
+	return b.flush()
+}
+
+func (b *blsBatch) Done() []func() error {
+	if len(b.sigs) == 0 {
+		return nil
+	}
+	return []func() error{b.flush()}
+}
+
+// flush returns the verification of the pending signatures and starts a
+// new batch.
+func (b *blsBatch) flush() func() error {
+	sigs, pks, msgs := b.sigs, b.pks, b.msgs
+	b.sigs, b.pks, b.msgs = nil, nil, nil
+	return func() error {
+		return verifyBLSBatch(sigs, pks, msgs)
+	}
+}
+
+func verifyBLSBatch(sigs []*blst.P2Affine, pks []*blst.P1Affine, msgs []blst.Message) error {
+	if len(sigs) == 1 {
+		// Weighting a lone signature only adds work.
+		if !sigs[0].Verify(false, pks[0], false, msgs[0], blsSignatureDST) {
+			return crypto.ErrInvalidSignature
+		}
+		return nil
+	}
+	// The weights are drawn up front because blst asks for them from
+	// several goroutines and cannot be told that drawing one failed.
+	weights := make([][blst.BLST_SCALAR_BYTES]byte, len(sigs))
+	for i := range weights {
+		if _, err := rand.Read(weights[i][:]); err != nil {
+			return err
+		}
+	}
+	var next atomic.Uint32
+	weight := func(s *blst.Scalar) {
+		i := next.Add(1) - 1
+		s.FromBEndian(weights[i][:])
+	}
+	// Signatures and keys were validated when the transaction was parsed.
+	if !new(blst.P2Affine).MultipleAggregateVerify(sigs, false, pks, false, msgs, blsSignatureDST, weight, blsRandBits) {
+		return crypto.ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto"
+	"github.com/ava-labs/hypersdk/crypto/bls"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/crypto/secp256r1"
+)
+
+// signedDigests returns [n] distinct transaction digests, each signed by a
+// new key of [keyType].
+func signedDigests(tb testing.TB, keyType string, n int) ([][]byte, []chain.Auth) {
+	require := require.New(tb)
+	digests := make([][]byte, n)
+	auths := make([]chain.Auth, n)
+	for i := range digests {
+		var factory chain.AuthFactory
+		switch keyType {
+		case auth.ED25519Key:
+			priv, err := ed25519.GeneratePrivateKey()
+			require.NoError(err)
+			factory = auth.NewED25519Factory(priv)
+		case auth.Secp256r1Key:
+			priv, err := secp256r1.GeneratePrivateKey()
+			require.NoError(err)
+			factory = auth.NewSECP256R1Factory(priv)
+		case auth.BLSKey:
+			priv, err := bls.GeneratePrivateKey()
+			require.NoError(err)
+			factory = auth.NewBLSFactory(priv)
+		}
+		digests[i] = hashing.ComputeHash256([]byte(fmt.Sprintf("tx %d", i)))
+		var err error
+		auths[i], err = factory.Sign(digests[i])
+		require.NoError(err)
+	}
+	return digests, auths
+}
+
+// batchVerify verifies [auths] the way a block does, with [cores] workers.
+func batchVerify(typeID uint8, cores int, digests [][]byte, auths []chain.Auth) error {
+	bv := authEngines()[typeID].GetBatchVerifier(cores, len(auths))
+	var jobs []func() error
+	for i, a := range auths {
+		if job := bv.Add(digests[i], a); job != nil {
+			jobs = append(jobs, job)
+		}
+	}
+	jobs = append(jobs, bv.Done()...)
+	for _, job := range jobs {
+		if err := job(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestBLSBatch(t *testing.T) {
+	digests, auths := signedDigests(t, auth.BLSKey, 40)
+
+	for _, cores := range []int{1, 4} {
+		require.NoError(t, batchVerify(auth.BLSID, cores, digests, auths))
+	}
+
+	// A signature over another digest fails the batch.
+	wrong := append([][]byte{}, digests...)
+	wrong[7] = digests[8]
+	require.ErrorIs(t, batchVerify(auth.BLSID, 1, wrong, auths), crypto.ErrInvalidSignature)
+
+	// Swapping two signatures leaves their sum unchanged, which would pass
+	// an unweighted aggregate.
+	swapped := append([]chain.Auth{}, auths...)
+	a, b := *auths[3].(*auth.BLS), *auths[4].(*auth.BLS)
+	a.Signature, b.Signature = b.Signature, a.Signature
+	swapped[3], swapped[4] = &a, &b
+	require.ErrorIs(t, batchVerify(auth.BLSID, 1, digests, swapped), crypto.ErrInvalidSignature)
+
+	// A lone signature is verified on its own.
+	require.NoError(t, batchVerify(auth.BLSID, 1, digests[:1], auths[:1]))
+	require.ErrorIs(t, batchVerify(auth.BLSID, 1, wrong[7:8], auths[7:8]), crypto.ErrInvalidSignature)
+}
+
+func BenchmarkVerifyAuth(b *testing.B) {
+	for _, keyType := range []string{auth.ED25519Key, auth.Secp256r1Key, auth.BLSKey} {
+		digests, auths := signedDigests(b, keyType, 1)
+		b.Run(keyType, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := auths[0].Verify(context.Background(), digests[0]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkBatchVerifyAuth verifies a block of transfers signed by one
+// auth type, one signature at a time and with the VM's batch verifier.
+func BenchmarkBatchVerifyAuth(b *testing.B) {
+	const txs = 256
+	for _, tt := range []struct {
+		keyType string
+		typeID  uint8
+	}{
+		{auth.ED25519Key, auth.ED25519ID},
+		{auth.BLSKey, auth.BLSID},
+	} {
+		digests, auths := signedDigests(b, tt.keyType, txs)
+		b.Run(fmt.Sprintf("%s/single/%d", tt.keyType, txs), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for j, a := range auths {
+					if err := a.Verify(context.Background(), digests[j]); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("%s/batch/%d", tt.keyType, txs), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := batchVerify(tt.typeID, 1, digests, auths); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -6,35 +6,117 @@ package vm
 import (
 	"context"
 	"encoding/json"
+	"reflect"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/ava-labs/avalanchego/ids"
+
 	"github.com/ava-labs/hypersdk-starter-kit/consts"
 	"github.com/ava-labs/hypersdk-starter-kit/storage"
 	"github.com/ava-labs/hypersdk/api/jsonrpc"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
-	"github.com/ava-labs/hypersdk/genesis"
 	"github.com/ava-labs/hypersdk/requester"
 	"github.com/ava-labs/hypersdk/utils"
 )
 
-const balanceCheckInterval = 500 * time.Millisecond
+const (
+	balanceCheckInterval = 500 * time.Millisecond
+	staleReadInterval    = 100 * time.Millisecond
+)
 
 type JSONRPCClient struct {
 	requester *requester.EndpointRequester
-	g         *genesis.DefaultGenesis
+	g         *Genesis
+
+	readYourWrites bool
+	minHeight      atomic.Uint64
+	height         *uint64
+}
+
+type ClientOption func(*JSONRPCClient)
+
+// WithReadYourWrites makes the client refuse to read state older than the
+// highest height it has observed, either in a previous reply or through
+// [JSONRPCClient.ObserveHeight]. Reads against a node that is still behind
+// are retried until it catches up or the context is done.
+func WithReadYourWrites() ClientOption {
+	return func(cli *JSONRPCClient) {
+		cli.readYourWrites = true
+	}
 }
 
 // NewJSONRPCClient creates a new client object.
-func NewJSONRPCClient(uri string) *JSONRPCClient {
+func NewJSONRPCClient(uri string, opts ...ClientOption) *JSONRPCClient {
 	uri = strings.TrimSuffix(uri, "/")
 	uri += JSONRPCEndpoint
 	req := requester.New(uri, consts.Name)
-	return &JSONRPCClient{req, nil}
+	cli := &JSONRPCClient{requester: req}
+	for _, opt := range opts {
+		opt(cli)
+	}
+	return cli
+}
+
+// ObserveHeight raises the minimum height served to this client. Call it with
+// the acceptance height of a submitted transaction so that subsequent reads
+// reflect its effects.
+func (cli *JSONRPCClient) ObserveHeight(height uint64) {
+	for {
+		current := cli.minHeight.Load()
+		if height <= current || cli.minHeight.CompareAndSwap(current, height) {
+			return
+		}
+	}
+}
+
+// AtHeight returns a client whose reads are served from the state after the
+// block at [height] was accepted.
+func (cli *JSONRPCClient) AtHeight(height uint64) *JSONRPCClient {
+	return &JSONRPCClient{
+		requester: cli.requester,
+		g:         cli.g,
+		height:    &height,
+	}
 }
 
-func (cli *JSONRPCClient) Genesis(ctx context.Context) (*genesis.DefaultGenesis, error) {
+func (cli *JSONRPCClient) readOptions() ReadOptions {
+	opts := ReadOptions{Height: cli.height}
+	if cli.readYourWrites {
+		opts.MinHeight = cli.minHeight.Load()
+	}
+	return opts
+}
+
+// sendRead issues a state read. In read-your-writes mode, reads rejected as
+// stale are retried and the height of the served state is tracked.
+func (cli *JSONRPCClient) sendRead(
+	ctx context.Context,
+	method string,
+	args interface{},
+	reply interface{},
+	height *uint64,
+) error {
+	if !cli.readYourWrites {
+		return cli.requester.SendRequest(ctx, method, args, reply)
+	}
+	err := jsonrpc.Wait(ctx, staleReadInterval, func(ctx context.Context) (bool, error) {
+		err := cli.requester.SendRequest(ctx, method, args, reply)
+		if err != nil && strings.Contains(err.Error(), storage.ErrStaleState.Error()) {
+			return false, nil
+		}
+		return true, err
+	})
+	if err != nil {
+		return err
+	}
+	cli.ObserveHeight(*height)
+	return nil
+}
+
+func (cli *JSONRPCClient) Genesis(ctx context.Context) (*Genesis, error) {
 	if cli.g != nil {
 		return cli.g, nil
 	}
@@ -55,17 +137,1042 @@ func (cli *JSONRPCClient) Genesis(ctx context.Context) (*genesis.DefaultGenesis,
 
 func (cli *JSONRPCClient) Balance(ctx context.Context, addr codec.Address) (uint64, error) {
 	resp := new(BalanceReply)
-	err := cli.requester.SendRequest(
+	err := cli.sendRead(
 		ctx,
 		"balance",
 		&BalanceArgs{
-			Address: addr,
+			Address:     addr,
+			ReadOptions: cli.readOptions(),
 		},
 		resp,
+		&resp.Height,
 	)
 	return resp.Amount, err
 }
 
+// Balances returns the native balances of [addrs], in order, all read at
+// the same height.
+func (cli *JSONRPCClient) Balances(ctx context.Context, addrs []codec.Address) ([]uint64, error) {
+	resp := new(BalancesReply)
+	err := cli.sendRead(
+		ctx,
+		"balances",
+		&BalancesArgs{
+			Addresses:   addrs,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Amounts, err
+}
+
+func (cli *JSONRPCClient) AssetBalance(ctx context.Context, addr codec.Address, asset ids.ID) (uint64, error) {
+	resp := new(BalanceReply)
+	err := cli.sendRead(
+		ctx,
+		"assetBalance",
+		&AssetBalanceArgs{
+			Address:     addr,
+			Asset:       asset,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Amount, err
+}
+
+func (cli *JSONRPCClient) Allowance(ctx context.Context, owner codec.Address, spender codec.Address) (uint64, error) {
+	resp := new(BalanceReply)
+	err := cli.sendRead(
+		ctx,
+		"allowance",
+		&AllowanceArgs{
+			Owner:       owner,
+			Spender:     spender,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Amount, err
+}
+
+func (cli *JSONRPCClient) NotificationPrefs(ctx context.Context, addr codec.Address) (storage.NotificationPrefs, error) {
+	resp := new(NotificationPrefsReply)
+	err := cli.sendRead(
+		ctx,
+		"notificationPrefs",
+		&BalanceArgs{
+			Address:     addr,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Prefs, err
+}
+
+func (cli *JSONRPCClient) Height(ctx context.Context) (uint64, error) {
+	resp := new(HeightReply)
+	opts := cli.readOptions()
+	err := cli.sendRead(
+		ctx,
+		"height",
+		&opts,
+		resp,
+		&resp.Height,
+	)
+	return resp.Height, err
+}
+
+// Timestamp returns the timestamp, in milliseconds, of the block reads are
+// served from.
+func (cli *JSONRPCClient) Timestamp(ctx context.Context) (int64, error) {
+	resp := new(TimestampReply)
+	opts := cli.readOptions()
+	err := cli.sendRead(
+		ctx,
+		"timestamp",
+		&opts,
+		resp,
+		&resp.Height,
+	)
+	return resp.Timestamp, err
+}
+
+func (cli *JSONRPCClient) AssetOwner(ctx context.Context, asset ids.ID) (codec.Address, error) {
+	resp := new(AssetOwnerReply)
+	err := cli.sendRead(
+		ctx,
+		"assetOwner",
+		&AssetOwnerArgs{
+			Asset:       asset,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Owner, err
+}
+
+// AssetOwners returns the owners of [assets], in order, all read at the
+// same height. Missing assets have an empty owner.
+func (cli *JSONRPCClient) AssetOwners(ctx context.Context, assets []ids.ID) ([]codec.Address, error) {
+	resp := new(AssetOwnersReply)
+	err := cli.sendRead(
+		ctx,
+		"assetOwners",
+		&AssetOwnersArgs{
+			Assets:      assets,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Owners, err
+}
+
+// VerifyOwnership reports which of [assets] [addr] owns. If [attest], the
+// reply is signed by the node; check it with [VerifyOwnershipAttestation].
+func (cli *JSONRPCClient) VerifyOwnership(ctx context.Context, addr codec.Address, assets []ids.ID, attest bool) (*VerifyOwnershipReply, error) {
+	resp := new(VerifyOwnershipReply)
+	err := cli.sendRead(
+		ctx,
+		"verifyOwnership",
+		&VerifyOwnershipArgs{
+			Address:     addr,
+			Assets:      assets,
+			Attest:      attest,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) AssetMetadata(ctx context.Context, asset ids.ID) (*AssetMetadataReply, error) {
+	resp := new(AssetMetadataReply)
+	err := cli.sendRead(
+		ctx,
+		"assetMetadata",
+		&AssetOwnerArgs{
+			Asset:       asset,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp, err
+}
+
+// TransferHook returns the hook registered on [asset], or nil.
+func (cli *JSONRPCClient) TransferHook(ctx context.Context, asset ids.ID) (*storage.TransferHook, error) {
+	resp := new(TransferHookReply)
+	err := cli.sendRead(
+		ctx,
+		"transferHook",
+		&AssetOwnerArgs{
+			Asset:       asset,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Hook, err
+}
+
+// Receipt returns the action the receipt asset [asset] records, or nil if
+// [asset] is not a receipt.
+func (cli *JSONRPCClient) Receipt(ctx context.Context, asset ids.ID) (*storage.Receipt, error) {
+	resp := new(ReceiptReply)
+	err := cli.sendRead(
+		ctx,
+		"receipt",
+		&AssetOwnerArgs{
+			Asset:       asset,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Receipt, err
+}
+
+// Stablecoin returns the roles and state of the stablecoin [asset], and its
+// supply.
+func (cli *JSONRPCClient) Stablecoin(ctx context.Context, asset ids.ID) (*StablecoinReply, error) {
+	resp := new(StablecoinReply)
+	err := cli.sendRead(
+		ctx,
+		"stablecoin",
+		&AssetOwnerArgs{
+			Asset:       asset,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp, err
+}
+
+// StablecoinSupplyProof returns the supply of the stablecoin [asset] and its
+// attested reserves, as of one height.
+func (cli *JSONRPCClient) StablecoinSupplyProof(ctx context.Context, asset ids.ID) (*StablecoinSupplyProofReply, error) {
+	resp := new(StablecoinSupplyProofReply)
+	err := cli.sendRead(
+		ctx,
+		"stablecoinSupplyProof",
+		&AssetOwnerArgs{
+			Asset:       asset,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp, err
+}
+
+// StablecoinBlocked returns whether [account] is on the blocklist of the
+// stablecoin [asset].
+func (cli *JSONRPCClient) StablecoinBlocked(ctx context.Context, asset ids.ID, account codec.Address) (bool, error) {
+	resp := new(StablecoinBlockedReply)
+	err := cli.sendRead(
+		ctx,
+		"stablecoinBlocked",
+		&StablecoinBlockedArgs{
+			Asset:       asset,
+			Account:     account,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Blocked, err
+}
+
+// PermitUsed returns whether the permit of [owner] with [nonce] has been
+// used.
+func (cli *JSONRPCClient) PermitUsed(ctx context.Context, owner codec.Address, nonce uint64) (bool, error) {
+	resp := new(PermitUsedReply)
+	err := cli.sendRead(
+		ctx,
+		"permitUsed",
+		&PermitUsedArgs{
+			Owner:       owner,
+			Nonce:       nonce,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Used, err
+}
+
+// Swap returns the open swap [swapID], with the terms AcceptSwap and
+// RefundSwap must repeat.
+func (cli *JSONRPCClient) Swap(ctx context.Context, swapID ids.ID) (*storage.Swap, error) {
+	resp := new(SwapReply)
+	err := cli.sendRead(
+		ctx,
+		"swap",
+		&SwapArgs{
+			SwapID:      swapID,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Swap, err
+}
+
+// Escrow returns the open escrow [escrowID], with the counterparty and payer
+// ReleaseEscrow and RefundEscrow must repeat.
+func (cli *JSONRPCClient) Escrow(ctx context.Context, escrowID ids.ID) (*storage.Escrow, error) {
+	resp := new(EscrowReply)
+	err := cli.sendRead(
+		ctx,
+		"escrow",
+		&EscrowArgs{
+			EscrowID:    escrowID,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Escrow, err
+}
+
+// Vesting returns the pending vesting [vestingID] of [beneficiary].
+func (cli *JSONRPCClient) Vesting(ctx context.Context, beneficiary codec.Address, vestingID ids.ID) (*storage.Vesting, error) {
+	resp := new(VestingReply)
+	err := cli.sendRead(
+		ctx,
+		"vesting",
+		&VestingArgs{
+			Beneficiary: beneficiary,
+			VestingID:   vestingID,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Vesting, err
+}
+
+// Vestings returns a page of the pending vestings of [beneficiary].
+func (cli *JSONRPCClient) Vestings(ctx context.Context, beneficiary codec.Address, page PageArgs) ([]PendingVesting, Page, error) {
+	resp := new(VestingsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"vestings",
+		&VestingsArgs{
+			Beneficiary: beneficiary,
+			PageArgs:    page,
+		},
+		resp,
+	)
+	return resp.Vestings, resp.Page, err
+}
+
+// Orders returns a page of the open orders selling [sellAsset] for
+// [buyAsset].
+func (cli *JSONRPCClient) Orders(ctx context.Context, sellAsset ids.ID, buyAsset ids.ID, page PageArgs) ([]OpenOrder, Page, error) {
+	resp := new(OrdersReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"orders",
+		&OrdersArgs{
+			SellAsset: sellAsset,
+			BuyAsset:  buyAsset,
+			PageArgs:  page,
+		},
+		resp,
+	)
+	return resp.Orders, resp.Page, err
+}
+
+// NextOrder returns the sequence the next CreateOrder of [maker] must set,
+// and the ID that order will have.
+func (cli *JSONRPCClient) NextOrder(ctx context.Context, maker codec.Address) (uint64, ids.ID, error) {
+	resp := new(NextOrderReply)
+	err := cli.sendRead(
+		ctx,
+		"nextOrder",
+		&NextOrderArgs{
+			Maker:       maker,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Sequence, resp.OrderID, err
+}
+
+// LiveAuctions returns a page of the auctions that still take bids, and of
+// those awaiting settlement if [includeEnded] is set.
+func (cli *JSONRPCClient) LiveAuctions(ctx context.Context, includeEnded bool, page PageArgs) ([]LiveAuction, Page, error) {
+	resp := new(LiveAuctionsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"liveAuctions",
+		&LiveAuctionsArgs{
+			IncludeEnded: includeEnded,
+			PageArgs:     page,
+		},
+		resp,
+	)
+	return resp.Auctions, resp.Page, err
+}
+
+// App returns the ID and admin of the app registered under [name].
+func (cli *JSONRPCClient) App(ctx context.Context, name string) (ids.ID, codec.Address, error) {
+	resp := new(AppReply)
+	err := cli.sendRead(
+		ctx,
+		"app",
+		&AppArgs{
+			Name:        name,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.App, resp.Admin, err
+}
+
+// AppAssets returns a page of the assets created in [app] that were not
+// burned.
+func (cli *JSONRPCClient) AppAssets(ctx context.Context, app ids.ID, page PageArgs) ([]ids.ID, Page, error) {
+	resp := new(AppAssetsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"appAssets",
+		&AppAssetsArgs{
+			App:      app,
+			PageArgs: page,
+		},
+		resp,
+	)
+	return resp.Assets, resp.Page, err
+}
+
+// AppCreators returns a page of the creators the admin of [app] allowed.
+func (cli *JSONRPCClient) AppCreators(ctx context.Context, app ids.ID, page PageArgs) ([]codec.Address, Page, error) {
+	resp := new(AppCreatorsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"appCreators",
+		&AppCreatorsArgs{
+			App:      app,
+			PageArgs: page,
+		},
+		resp,
+	)
+	return resp.Creators, resp.Page, err
+}
+
+// Pool returns the pool of [assetA] and [assetB], which may be given in
+// either order. The reserves follow the returned pool order.
+func (cli *JSONRPCClient) Pool(ctx context.Context, assetA ids.ID, assetB ids.ID) (*PoolReply, error) {
+	resp := new(PoolReply)
+	err := cli.sendRead(
+		ctx,
+		"pool",
+		&PoolArgs{
+			AssetA:      assetA,
+			AssetB:      assetB,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) PoolShares(ctx context.Context, assetA ids.ID, assetB ids.ID, provider codec.Address) (uint64, error) {
+	resp := new(PoolSharesReply)
+	err := cli.sendRead(
+		ctx,
+		"poolShares",
+		&PoolSharesArgs{
+			AssetA:      assetA,
+			AssetB:      assetB,
+			Provider:    provider,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Shares, err
+}
+
+// SpotPrice returns the price of [base] in units of [quote], as a decimal
+// string.
+func (cli *JSONRPCClient) SpotPrice(ctx context.Context, base ids.ID, quote ids.ID) (string, error) {
+	resp := new(SpotPriceReply)
+	err := cli.sendRead(
+		ctx,
+		"spotPrice",
+		&SpotPriceArgs{
+			Base:        base,
+			Quote:       quote,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Price, err
+}
+
+// Stake returns the stake of [staker] and the rewards it could claim.
+func (cli *JSONRPCClient) Stake(ctx context.Context, staker codec.Address) (*StakeReply, error) {
+	resp := new(StakeReply)
+	err := cli.sendRead(
+		ctx,
+		"stake",
+		&StakeArgs{
+			Staker:      staker,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp, err
+}
+
+// GovernanceProposal returns a governance proposal, and the vote of [voter]
+// on it if [voter] is not nil.
+func (cli *JSONRPCClient) GovernanceProposal(
+	ctx context.Context,
+	proposalID ids.ID,
+	voter *codec.Address,
+) (*GovernanceProposalReply, error) {
+	resp := new(GovernanceProposalReply)
+	err := cli.sendRead(
+		ctx,
+		"governanceProposal",
+		&GovernanceProposalArgs{
+			ProposalID:  proposalID,
+			Voter:       voter,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp, err
+}
+
+// GovernanceParameters returns the values of the config registry in effect
+// and the active proposal, if any.
+func (cli *JSONRPCClient) GovernanceParameters(ctx context.Context) (*GovernanceParametersReply, error) {
+	resp := new(GovernanceParametersReply)
+	opts := cli.readOptions()
+	err := cli.sendRead(
+		ctx,
+		"governanceParameters",
+		&opts,
+		resp,
+		&resp.Height,
+	)
+	return resp, err
+}
+
+// Multisig returns a multisig, and its pending proposal [proposalID] if
+// that is not nil.
+func (cli *JSONRPCClient) Multisig(
+	ctx context.Context,
+	multisig codec.Address,
+	proposalID *ids.ID,
+) (*MultisigReply, error) {
+	resp := new(MultisigReply)
+	err := cli.sendRead(
+		ctx,
+		"multisig",
+		&MultisigArgs{
+			Multisig:    multisig,
+			ProposalID:  proposalID,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp, err
+}
+
+// SmartAccount returns a smart account, and its pending request
+// [requestID] if that is not nil.
+func (cli *JSONRPCClient) SmartAccount(
+	ctx context.Context,
+	account codec.Address,
+	requestID *ids.ID,
+) (*SmartAccountReply, error) {
+	resp := new(SmartAccountReply)
+	err := cli.sendRead(
+		ctx,
+		"smartAccount",
+		&SmartAccountArgs{
+			Account:     account,
+			RequestID:   requestID,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp, err
+}
+
+// Claim returns the claim [owner] stored under [key], unless it expired.
+func (cli *JSONRPCClient) Claim(ctx context.Context, owner codec.Address, key []byte) (*storage.Claim, error) {
+	resp := new(ClaimReply)
+	err := cli.sendRead(
+		ctx,
+		"claim",
+		&ClaimArgs{
+			Owner:       owner,
+			Key:         key,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Claim, err
+}
+
+// RequestSession asks [owner] to authorize [key] to send [actionTypes] on
+// its behalf until [expiry], spending at most [spendCap]. It returns the
+// session ID the owner's wallet must authorize.
+func (cli *JSONRPCClient) RequestSession(
+	ctx context.Context,
+	owner codec.Address,
+	key codec.Address,
+	actionTypes []uint8,
+	spendCap uint64,
+	expiry int64,
+) (ids.ID, error) {
+	resp := new(RequestSessionReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"requestSession",
+		&RequestSessionArgs{
+			Owner:       owner,
+			Key:         key,
+			ActionTypes: actionTypes,
+			SpendCap:    spendCap,
+			Expiry:      expiry,
+		},
+		resp,
+	)
+	return resp.SessionID, err
+}
+
+// SessionRequests returns the pending session requests of [owner].
+func (cli *JSONRPCClient) SessionRequests(ctx context.Context, owner codec.Address) ([]*SessionRequest, error) {
+	resp := new(SessionRequestsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"sessionRequests",
+		&SessionRequestsArgs{Owner: owner},
+		resp,
+	)
+	return resp.Requests, err
+}
+
+// ActiveSessions returns a page of the active sessions of [owner].
+func (cli *JSONRPCClient) ActiveSessions(ctx context.Context, owner codec.Address, page PageArgs) ([]ActiveSession, Page, error) {
+	resp := new(ActiveSessionsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"activeSessions",
+		&ActiveSessionsArgs{
+			Owner:    owner,
+			PageArgs: page,
+		},
+		resp,
+	)
+	return resp.Sessions, resp.Page, err
+}
+
+// EconomicSummary summarizes the native token economy of the last accepted
+// state, hashing its [top] largest balances.
+func (cli *JSONRPCClient) EconomicSummary(ctx context.Context, top int, minHeight uint64) (*storage.Summary, error) {
+	resp := new(storage.Summary)
+	err := cli.requester.SendRequest(
+		ctx,
+		"economicSummary",
+		&EconomicSummaryArgs{Top: top, MinHeight: minHeight},
+		resp,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) StateRetention(ctx context.Context) (*StateRetentionReply, error) {
+	resp := new(StateRetentionReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"stateRetention",
+		nil,
+		resp,
+	)
+	return resp, err
+}
+
+// GetProof returns a Merkle proof of the value of the state key [key]
+// against the state root after the block at [height], or after the newest
+// block committed to if [height] is nil. Check it with [proof.Verify].
+func (cli *JSONRPCClient) GetProof(ctx context.Context, key []byte, height *uint64) (*GetProofReply, error) {
+	resp := new(GetProofReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"getProof",
+		&GetProofArgs{
+			Key:    key,
+			Height: height,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+// AssetsByOwner returns a page of the assets [owner] holds.
+func (cli *JSONRPCClient) AssetsByOwner(ctx context.Context, owner codec.Address, page PageArgs) ([]ids.ID, Page, error) {
+	resp := new(AssetsByOwnerReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"assetsByOwner",
+		&AssetsByOwnerArgs{
+			Owner:    owner,
+			PageArgs: page,
+		},
+		resp,
+	)
+	return resp.Assets, resp.Page, err
+}
+
+// AllAssetsByOwner pages through every asset [owner] holds.
+func (cli *JSONRPCClient) AllAssetsByOwner(ctx context.Context, owner codec.Address) ([]ids.ID, error) {
+	var (
+		assets []ids.ID
+		args   PageArgs
+	)
+	for {
+		items, page, err := cli.AssetsByOwner(ctx, owner, args)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, items...)
+		if !page.HasMore {
+			return assets, nil
+		}
+		args.Cursor = page.Cursor
+	}
+}
+
+// ExportBalances returns a page of the non-zero native balances.
+func (cli *JSONRPCClient) ExportBalances(ctx context.Context, page PageArgs) ([]AccountBalance, Page, error) {
+	resp := new(ExportBalancesReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"exportBalances",
+		&ExportBalancesArgs{PageArgs: page},
+		resp,
+	)
+	return resp.Balances, resp.Page, err
+}
+
+// AllBalances pages through every non-zero native balance.
+func (cli *JSONRPCClient) AllBalances(ctx context.Context) ([]AccountBalance, error) {
+	var (
+		balances []AccountBalance
+		args     PageArgs
+	)
+	for {
+		items, page, err := cli.ExportBalances(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, items...)
+		if !page.HasMore {
+			return balances, nil
+		}
+		args.Cursor = page.Cursor
+	}
+}
+
+func (cli *JSONRPCClient) Treasury(ctx context.Context) (*TreasuryReply, error) {
+	resp := new(TreasuryReply)
+	opts := cli.readOptions()
+	err := cli.sendRead(
+		ctx,
+		"treasury",
+		&opts,
+		resp,
+		&resp.Height,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) TreasuryProposal(ctx context.Context, proposalID ids.ID) (*storage.TreasuryProposal, error) {
+	resp := new(TreasuryProposalReply)
+	err := cli.sendRead(
+		ctx,
+		"treasuryProposal",
+		&TreasuryProposalArgs{
+			ProposalID:  proposalID,
+			ReadOptions: cli.readOptions(),
+		},
+		resp,
+		&resp.Height,
+	)
+	return resp.Proposal, err
+}
+
+// TreasuryHistory returns a page of treasury movements, newest first.
+func (cli *JSONRPCClient) TreasuryHistory(ctx context.Context, page PageArgs) ([]*TreasuryMovement, Page, error) {
+	resp := new(TreasuryHistoryReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"treasuryHistory",
+		&TreasuryHistoryArgs{
+			PageArgs: page,
+		},
+		resp,
+	)
+	return resp.Movements, resp.Page, err
+}
+
+func (cli *JSONRPCClient) HaltStatus(ctx context.Context) (*HaltStatusReply, error) {
+	resp := new(HaltStatusReply)
+	opts := cli.readOptions()
+	err := cli.sendRead(
+		ctx,
+		"haltStatus",
+		&opts,
+		resp,
+		&resp.Height,
+	)
+	return resp, err
+}
+
+// AssetHistory returns a page of the transfers of [assetID], newest first.
+func (cli *JSONRPCClient) AssetHistory(ctx context.Context, assetID ids.ID, page PageArgs) ([]*AssetOwnerChange, Page, error) {
+	resp := new(AssetHistoryReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"assetHistory",
+		&AssetHistoryArgs{
+			AssetID:  assetID,
+			PageArgs: page,
+		},
+		resp,
+	)
+	return resp.Transfers, resp.Page, err
+}
+
+// GetTx returns the accepted transaction [txID].
+func (cli *JSONRPCClient) GetTx(ctx context.Context, txID ids.ID) (*IndexedTx, error) {
+	resp := new(IndexedTx)
+	err := cli.requester.SendRequest(
+		ctx,
+		"getTx",
+		&TxArgs{TxID: txID},
+		resp,
+	)
+	return resp, err
+}
+
+// GetTxsByAddress returns a page of the transactions that touched [addr],
+// newest first.
+func (cli *JSONRPCClient) GetTxsByAddress(ctx context.Context, addr codec.Address, page PageArgs) ([]*IndexedTx, Page, error) {
+	resp := new(TxsByAddressReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"getTxsByAddress",
+		&TxsByAddressArgs{
+			Address:  addr,
+			PageArgs: page,
+		},
+		resp,
+	)
+	return resp.Txs, resp.Page, err
+}
+
+// GetLogs returns the logs matching [filter] from [from] to [to], and the
+// last height scanned. A zero [to] is the last indexed height.
+func (cli *JSONRPCClient) GetLogs(ctx context.Context, from, to uint64, filter LogFilter) ([]*Log, uint64, error) {
+	resp := new(LogsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"getLogs",
+		&LogsArgs{
+			FromHeight: from,
+			ToHeight:   to,
+			LogFilter:  filter,
+		},
+		resp,
+	)
+	return resp.Logs, resp.Last, err
+}
+
+// Maintenance returns the maintenance address and the last compaction
+// marker.
+func (cli *JSONRPCClient) Maintenance(ctx context.Context) (*MaintenanceReply, error) {
+	resp := new(MaintenanceReply)
+	opts := cli.readOptions()
+	err := cli.sendRead(
+		ctx,
+		"maintenance",
+		&opts,
+		resp,
+		&resp.Height,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) StateJournal(ctx context.Context, height uint64) (*BlockJournal, error) {
+	resp := new(BlockJournal)
+	err := cli.requester.SendRequest(
+		ctx,
+		"stateJournal",
+		&StateJournalArgs{
+			Height: height,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+// GetBlockUsage returns the usage report of the block at [height].
+func (cli *JSONRPCClient) GetBlockUsage(ctx context.Context, height uint64) (*BlockUsage, error) {
+	resp := new(BlockUsage)
+	err := cli.requester.SendRequest(
+		ctx,
+		"blockUsage",
+		&BlockUsageArgs{
+			Height: height,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+// StateHeatMap returns the state key accesses counted by the node.
+func (cli *JSONRPCClient) StateHeatMap(ctx context.Context) (*StateHeatMapReply, error) {
+	resp := new(StateHeatMapReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"stateHeatMap",
+		nil,
+		resp,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) Upgrades(ctx context.Context) (*UpgradesReply, error) {
+	resp := new(UpgradesReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"upgrades",
+		nil,
+		resp,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) ChainMetadata(ctx context.Context) (*ChainMetadataReply, error) {
+	resp := new(ChainMetadataReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"chainMetadata",
+		nil,
+		resp,
+	)
+	return resp, err
+}
+
+// AddressFormats returns [addr], a MorpheusVM or EVM address, in both
+// formats.
+func (cli *JSONRPCClient) AddressFormats(ctx context.Context, addr string) (*AddressFormatsReply, error) {
+	resp := new(AddressFormatsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"addressFormats",
+		&AddressFormatsArgs{Address: addr},
+		resp,
+	)
+	return resp, err
+}
+
+// SimulateCosts runs [acts] as [actor] against the current state, without
+// submitting them, and returns the cost of each.
+func (cli *JSONRPCClient) SimulateCosts(ctx context.Context, acts []chain.Action, actor codec.Address) ([]SimulateCostResult, error) {
+	args := &SimulateCostsArgs{Actor: actor}
+	for _, action := range acts {
+		b, err := chain.MarshalTyped(action)
+		if err != nil {
+			return nil, err
+		}
+		args.Actions = append(args.Actions, b)
+	}
+	resp := new(SimulateCostsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"simulateCosts",
+		args,
+		resp,
+	)
+	return resp.ActionResults, err
+}
+
+// SimulateAction executes [action] as [actor] against the latest state
+// without submitting it. An action that fails is reported in the reply.
+func (cli *JSONRPCClient) SimulateAction(ctx context.Context, action chain.Action, actor codec.Address) (*SimulateActionReply, error) {
+	b, err := json.Marshal(action)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(SimulateActionReply)
+	err = cli.requester.SendRequest(
+		ctx,
+		"simulateAction",
+		&SimulateActionArgs{
+			Type:   reflect.TypeOf(action).Elem().Name(),
+			Action: b,
+			Actor:  actor,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+// DiagnoseTx reports why the VM would not admit [tx], without submitting
+// it. Problems is empty if it would be admitted.
+func (cli *JSONRPCClient) DiagnoseTx(ctx context.Context, tx *chain.Transaction) (*DiagnoseTxReply, error) {
+	resp := new(DiagnoseTxReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"diagnoseTx",
+		&DiagnoseTxArgs{Tx: tx.Bytes()},
+		resp,
+	)
+	return resp, err
+}
+
+// SuggestFee suggests unit prices from recent blocks and, if [args] name an
+// action, a max fee for it.
+func (cli *JSONRPCClient) SuggestFee(ctx context.Context, args *SuggestFeeArgs) (*SuggestFeeReply, error) {
+	resp := new(SuggestFeeReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"suggestFee",
+		args,
+		resp,
+	)
+	return resp, err
+}
+
 func (cli *JSONRPCClient) WaitForBalance(
 	ctx context.Context,
 	addr codec.Address,
@@ -99,7 +1206,7 @@ func (cli *JSONRPCClient) Parser(ctx context.Context) (chain.Parser, error) {
 var _ chain.Parser = (*Parser)(nil)
 
 type Parser struct {
-	genesis *genesis.DefaultGenesis
+	genesis *Genesis
 }
 
 func (p *Parser) Rules(_ int64) chain.Rules {
@@ -122,15 +1229,71 @@ func (*Parser) StateManager() chain.StateManager {
 	return &storage.StateManager{}
 }
 
-func NewParser(genesis *genesis.DefaultGenesis) chain.Parser {
+func NewParser(genesis *Genesis) chain.Parser {
 	return &Parser{genesis: genesis}
 }
 
 // Used as a lambda function for creating ExternalSubscriberServer parser
 func CreateParser(genesisBytes []byte) (chain.Parser, error) {
-	var genesis genesis.DefaultGenesis
-	if err := json.Unmarshal(genesisBytes, &genesis); err != nil {
+	g, err := parseGenesis(genesisBytes)
+	if err != nil {
+		return nil, err
+	}
+	return NewParser(g), nil
+}
+
+// Leaderboard returns the leaderboard [boardID] with its entries, best first.
+func (cli *JSONRPCClient) Leaderboard(ctx context.Context, boardID ids.ID) (*storage.Leaderboard, error) {
+	resp := new(LeaderboardReply)
+	err := cli.sendRead(
+		ctx,
+		"leaderboard",
+		&LeaderboardArgs{BoardID: boardID, ReadOptions: cli.readOptions()},
+		resp,
+		&resp.Height,
+	)
+	if err != nil {
 		return nil, err
 	}
-	return NewParser(&genesis), nil
+	return resp.Leaderboard, nil
+}
+
+// AccountStatement returns the statement of [asset] for [addr] from [from]
+// to [to], signed if [attest] is set. It may end before [to]; continue from
+// the height after its ToHeight. Check it with [VerifyAccountStatement].
+func (cli *JSONRPCClient) AccountStatement(
+	ctx context.Context,
+	addr codec.Address,
+	asset ids.ID,
+	from uint64,
+	to uint64,
+	attest bool,
+) (*AccountStatementReply, error) {
+	resp := new(AccountStatementReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"accountStatement",
+		&AccountStatementArgs{
+			Address:    addr,
+			Asset:      asset,
+			FromHeight: from,
+			ToHeight:   to,
+			Attest:     attest,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+// Locker returns the content, holder and custody address of [locker].
+func (cli *JSONRPCClient) Locker(ctx context.Context, locker ids.ID) (*LockerReply, error) {
+	resp := new(LockerReply)
+	err := cli.sendRead(
+		ctx,
+		"locker",
+		&LockerArgs{Locker: locker, ReadOptions: cli.readOptions()},
+		resp,
+		&resp.Height,
+	)
+	return resp, err
 }
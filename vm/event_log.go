@@ -0,0 +1,349 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/pebbledb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/bloom"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/event"
+)
+
+const (
+	// MaxLogsRange bounds the heights one GetLogs call scans.
+	MaxLogsRange = 1024
+	// MaxLogs bounds the logs one GetLogs call returns. The call stops after
+	// the block that reaches it.
+	MaxLogs = 1000
+	// MaxLogFilterEntries bounds the addresses and assets of a [LogFilter].
+	MaxLogFilterEntries = 64
+
+	// logsFalsePositiveRate is the false positive rate of block blooms.
+	logsFalsePositiveRate = 0.01
+)
+
+var (
+	ErrLogsUnavailable     = errors.New("event logs unavailable")
+	ErrInvalidLogRange     = errors.New("invalid log range")
+	ErrTooManyFilterValues = fmt.Errorf("cannot filter by more than %d addresses and assets", MaxLogFilterEntries)
+)
+
+var (
+	_ event.SubscriptionFactory[*chain.ExecutedBlock] = (*eventLog)(nil)
+	_ event.Subscription[*chain.ExecutedBlock]        = (*eventLog)(nil)
+)
+
+// Keys of the event log:
+//
+// 0x0 => last indexed height
+// 0x1 + [height] => bloom of the addresses and assets of the block's logs
+// 0x2 + [height] => logs of the block
+//
+// Entries by height are [storage.OrderedKey]s, so a range of heights is a
+// range of keys.
+//
+// Blocks without logs have no entries, so a scan only visits blocks that
+// emitted events.
+const (
+	eventLogHeightPrefix = 0x0
+	eventLogBloomPrefix  = 0x1
+	eventLogBlockPrefix  = 0x2
+)
+
+var eventLogHeightKey = []byte{eventLogHeightPrefix}
+
+// Log is an event emitted by an action of an accepted block.
+type Log struct {
+	Height uint64 `json:"height"`
+	TxID   ids.ID `json:"txId"`
+	// Action is the index of the emitting action in its transaction.
+	Action int    `json:"action"`
+	Kind   string `json:"kind"`
+	// Event is the JSON form of the event, such as an
+	// [actions.TransferEvent].
+	Event     json.RawMessage `json:"event"`
+	Addresses []codec.Address `json:"addresses"`
+	Assets    []ids.ID        `json:"assets"`
+}
+
+// LogFilter selects logs. A log matches when it has one of [Addresses], one
+// of [Assets] and one of [Kinds]; an empty list matches every log.
+type LogFilter struct {
+	Addresses []codec.Address `json:"addresses"`
+	Assets    []ids.ID        `json:"assets"`
+	Kinds     []string        `json:"kinds"`
+}
+
+func (f *LogFilter) verify() error {
+	if len(f.Addresses)+len(f.Assets) > MaxLogFilterEntries {
+		return ErrTooManyFilterValues
+	}
+	return nil
+}
+
+func (f *LogFilter) match(l *Log) bool {
+	return matchAny(f.Kinds, []string{l.Kind}) &&
+		matchAny(f.Addresses, l.Addresses) &&
+		matchAny(f.Assets, l.Assets)
+}
+
+// mayMatch reports whether a block with bloom [b] may have a log matching
+// [f].
+func (f *LogFilter) mayMatch(b bloom.Checker) bool {
+	addrs := len(f.Addresses) == 0
+	for _, addr := range f.Addresses {
+		addrs = addrs || bloom.Contains(b, addr[:], nil)
+	}
+	assets := len(f.Assets) == 0
+	for _, asset := range f.Assets {
+		assets = assets || bloom.Contains(b, asset[:], nil)
+	}
+	return addrs && assets
+}
+
+func matchAny[T comparable](want []T, have []T) bool {
+	if len(want) == 0 {
+		return true
+	}
+	wanted := set.Of(want...)
+	for _, v := range have {
+		if wanted.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// blockEvents calls [f] with the events of the successful actions of
+// [blk], in execution order, along with the transaction and the index of
+// the action that emitted them.
+func blockEvents(blk *chain.ExecutedBlock, f func(tx *chain.Transaction, action int, e actions.Event) error) error {
+	for i, tx := range blk.Block.Txs {
+		result := blk.Results[i]
+		if !result.Success {
+			continue
+		}
+		for j, action := range tx.Actions {
+			source, ok := action.(actions.EventSource)
+			if !ok {
+				continue
+			}
+			output := result.Outputs[j]
+			typed, err := OutputParser.Unmarshal(codec.NewReader(output, len(output)))
+			if err != nil {
+				return err
+			}
+			var events actions.Events
+			source.EmitEvents(tx.Auth.Actor(), typed, &events)
+			for _, e := range events {
+				if err := f(tx, j, e); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// blockLogs returns the events of the successful actions of [blk], in
+// execution order.
+func blockLogs(blk *chain.ExecutedBlock) ([]*Log, error) {
+	var logs []*Log
+	err := blockEvents(blk, func(tx *chain.Transaction, action int, e actions.Event) error {
+		l, err := newLog(blk.Block.Hght, tx, action, e)
+		if err != nil {
+			return err
+		}
+		logs = append(logs, l)
+		return nil
+	})
+	return logs, err
+}
+
+// newLog returns the log of [e], emitted by the action at index [action] of
+// [tx] in the block at [height].
+func newLog(height uint64, tx *chain.Transaction, action int, e actions.Event) (*Log, error) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return &Log{
+		Height:    height,
+		TxID:      tx.ID(),
+		Action:    action,
+		Kind:      e.Kind(),
+		Event:     b,
+		Addresses: e.Addresses(),
+		Assets:    e.Assets(),
+	}, nil
+}
+
+// logsBloom returns a bloom of the addresses and assets of [logs]. Addresses
+// and asset IDs differ in length, so their entries cannot collide.
+func logsBloom(logs []*Log) (*bloom.Filter, error) {
+	var count int
+	for _, l := range logs {
+		count += len(l.Addresses) + len(l.Assets)
+	}
+	f, err := bloom.New(bloom.OptimalParameters(count, logsFalsePositiveRate))
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range logs {
+		for _, addr := range l.Addresses {
+			bloom.Add(f, addr[:], nil)
+		}
+		for _, asset := range l.Assets {
+			bloom.Add(f, asset[:], nil)
+		}
+	}
+	return f, nil
+}
+
+// eventLog stores the events of accepted blocks with a bloom per block, so
+// filters skip the blocks that cannot match.
+type eventLog struct {
+	db database.Database
+}
+
+func newEventLog(path string, log logging.Logger) (*eventLog, error) {
+	db, err := pebbledb.New(path, nil, log, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &eventLog{db: db}, nil
+}
+
+func eventLogPath(dataDir string) string {
+	return filepath.Join(dataDir, Namespace, "event_log")
+}
+
+func eventLogKey(prefix byte, height uint64) []byte {
+	// The index is not part of state, so its keys carry no chunks.
+	return storage.OrderedKey([]byte{prefix}, height, nil, 0)
+}
+
+func (e *eventLog) New() (event.Subscription[*chain.ExecutedBlock], error) {
+	return e, nil
+}
+
+func (e *eventLog) Accept(blk *chain.ExecutedBlock) error {
+	height := blk.Block.Hght
+	last, err := getUint64(e.db, eventLogHeightKey)
+	if err != nil {
+		return err
+	}
+	if height <= last && height != 0 {
+		// Already indexed before a restart.
+		return nil
+	}
+	batch := e.db.NewBatch()
+	logs, err := blockLogs(blk)
+	if err != nil {
+		return err
+	}
+	if len(logs) > 0 {
+		f, err := logsBloom(logs)
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(logs)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(eventLogKey(eventLogBloomPrefix, height), f.Marshal()); err != nil {
+			return err
+		}
+		if err := batch.Put(eventLogKey(eventLogBlockPrefix, height), b); err != nil {
+			return err
+		}
+	}
+	if err := batch.Put(eventLogHeightKey, binary.BigEndian.AppendUint64(nil, height)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// Logs returns the logs matching [filter] from the blocks from [from] to
+// [to], oldest first, and the last height scanned. A zero [to] is the last
+// indexed height. At most [MaxLogsRange] heights are scanned, and the scan
+// stops after the block that brings the logs to [MaxLogs]: continue from
+// the height after the one returned until it reaches [to].
+func (e *eventLog) Logs(from, to uint64, filter *LogFilter) ([]*Log, uint64, error) {
+	if err := filter.verify(); err != nil {
+		return nil, 0, err
+	}
+	last, err := getUint64(e.db, eventLogHeightKey)
+	if err != nil {
+		return nil, 0, err
+	}
+	if to == 0 || to > last {
+		to = last
+	}
+	if from > to {
+		return nil, 0, fmt.Errorf("%w: from %d above %d", ErrInvalidLogRange, from, to)
+	}
+	if to-from >= MaxLogsRange {
+		to = from + MaxLogsRange - 1
+	}
+
+	prefix := []byte{eventLogBloomPrefix}
+	heights := storage.OrderedRange(prefix, storage.Inclusive(from), storage.Inclusive(to))
+	it := e.db.NewIteratorWithStartAndPrefix(heights.Start, prefix)
+	defer it.Release()
+
+	logs := []*Log{}
+	for it.Next() {
+		k := it.Key()
+		if !heights.Contains(k) {
+			break
+		}
+		height, id, err := storage.ParseOrderedKey(prefix, k)
+		if err != nil || len(id) != 0 {
+			return nil, 0, fmt.Errorf("%w: corrupt bloom entry", ErrLogsUnavailable)
+		}
+		f, err := bloom.Parse(it.Value())
+		if err != nil {
+			return nil, 0, err
+		}
+		if !filter.mayMatch(f) {
+			continue
+		}
+		b, err := e.db.Get(eventLogKey(eventLogBlockPrefix, height))
+		if err != nil {
+			return nil, 0, err
+		}
+		var blockLogs []*Log
+		if err := json.Unmarshal(b, &blockLogs); err != nil {
+			return nil, 0, err
+		}
+		for _, l := range blockLogs {
+			if filter.match(l) {
+				logs = append(logs, l)
+			}
+		}
+		if len(logs) >= MaxLogs {
+			return logs, height, nil
+		}
+	}
+	return logs, to, it.Error()
+}
+
+func (e *eventLog) Close() error {
+	return e.db.Close()
+}
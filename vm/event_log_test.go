@@ -0,0 +1,87 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+func TestEventLog(t *testing.T) {
+	require := require.New(t)
+	el, err := newEventLog(t.TempDir(), logging.NoLog{})
+	require.NoError(err)
+	defer el.Close()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	to := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+
+	// block has one transaction of [action] with [output], which failed
+	// unless [success].
+	block := func(height uint64, action chain.Action, output codec.Typed, success bool) *chain.ExecutedBlock {
+		b, err := chain.MarshalTyped(output)
+		require.NoError(err)
+		return &chain.ExecutedBlock{
+			Block: &chain.StatelessBlock{
+				Hght: height,
+				Txs: []*chain.Transaction{{
+					Actions: []chain.Action{action},
+					Auth:    &auth.ED25519{Signer: priv.PublicKey()},
+				}},
+			},
+			Results: []*chain.Result{{Success: success, Outputs: [][]byte{b}}},
+		}
+	}
+	require.NoError(el.Accept(block(1, &actions.Transfer{To: to, Value: 5}, &actions.TransferResult{}, true)))
+	require.NoError(el.Accept(block(2, &actions.MintAsset{Asset: asset}, &actions.MintAssetResult{Asset: asset, Owner: sender}, true)))
+	require.NoError(el.Accept(block(3, &actions.Transfer{To: to, Value: 7}, &actions.TransferResult{}, false)))
+	// A block is logged once, however often it is delivered.
+	require.NoError(el.Accept(block(2, &actions.MintAsset{Asset: asset}, &actions.MintAssetResult{Asset: asset, Owner: sender}, true)))
+
+	heights := func(filter LogFilter) []uint64 {
+		logs, last, err := el.Logs(0, 0, &filter)
+		require.NoError(err)
+		require.Equal(uint64(3), last)
+		var hs []uint64
+		for _, l := range logs {
+			hs = append(hs, l.Height)
+		}
+		return hs
+	}
+	require.Equal([]uint64{1, 2}, heights(LogFilter{}))
+	require.Equal([]uint64{1, 2}, heights(LogFilter{Addresses: []codec.Address{sender}}))
+	require.Equal([]uint64{1}, heights(LogFilter{Addresses: []codec.Address{to}}))
+	require.Equal([]uint64{2}, heights(LogFilter{Assets: []ids.ID{asset}}))
+	require.Equal([]uint64{2}, heights(LogFilter{Kinds: []string{actions.OwnershipEventKind}}))
+	require.Empty(heights(LogFilter{Addresses: []codec.Address{to}, Assets: []ids.ID{asset}}))
+	require.Empty(heights(LogFilter{Addresses: []codec.Address{codectest.NewRandomAddress()}}))
+
+	logs, last, err := el.Logs(1, 1, &LogFilter{})
+	require.NoError(err)
+	require.Equal(uint64(1), last)
+	require.Len(logs, 1)
+	var transfer actions.TransferEvent
+	require.NoError(json.Unmarshal(logs[0].Event, &transfer))
+	require.Equal(actions.TransferEvent{From: sender, To: to, Asset: storage.NativeAsset, Amount: 5}, transfer)
+
+	_, _, err = el.Logs(3, 2, &LogFilter{})
+	require.ErrorIs(err, ErrInvalidLogRange)
+	_, _, err = el.Logs(0, 0, &LogFilter{Assets: make([]ids.ID, MaxLogFilterEntries+1)})
+	require.ErrorIs(err, ErrTooManyFilterValues)
+}
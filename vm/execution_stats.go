@@ -0,0 +1,102 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/event"
+)
+
+const (
+	// maxErrorKinds bounds the error kinds action errors are labeled with.
+	// Kinds seen after that are counted as [otherErrorKind].
+	maxErrorKinds  = 64
+	otherErrorKind = "other"
+)
+
+var (
+	_ storage.LatencyRecorder                         = (*executionStats)(nil)
+	_ event.SubscriptionFactory[*chain.ExecutedBlock] = (*executionStats)(nil)
+	_ event.Subscription[*chain.ExecutedBlock]        = (*executionStats)(nil)
+)
+
+// executionStats turns the actions of accepted blocks and the state
+// accesses of storage into metrics.
+type executionStats struct {
+	metrics *metrics
+
+	lock  sync.Mutex
+	kinds set.Set[string]
+}
+
+func newExecutionStats(m *metrics) *executionStats {
+	return &executionStats{
+		metrics: m,
+		kinds:   set.Set[string]{},
+	}
+}
+
+func (e *executionStats) RecordLatency(key []byte, write bool, d time.Duration) {
+	h := e.metrics.storageReadSeconds
+	if write {
+		h = e.metrics.storageWriteSeconds
+	}
+	h.WithLabelValues(storage.PrefixName(key)).Observe(d.Seconds())
+}
+
+func (e *executionStats) New() (event.Subscription[*chain.ExecutedBlock], error) {
+	return e, nil
+}
+
+// Accept counts the actions of [blk] that ran. The actions of a failed
+// transaction before the failing one succeeded, and those after it did not
+// run.
+func (e *executionStats) Accept(blk *chain.ExecutedBlock) error {
+	for i, tx := range blk.Block.Txs {
+		result := blk.Results[i]
+		for j, action := range tx.Actions {
+			name := reflect.TypeOf(action).Elem().Name()
+			if j < len(result.Outputs) {
+				e.metrics.actionExecutions.WithLabelValues(name, "success").Inc()
+				continue
+			}
+			if !result.Success {
+				e.metrics.actionExecutions.WithLabelValues(name, "failure").Inc()
+				e.metrics.actionErrors.WithLabelValues(name, e.errorKind(string(result.Error))).Inc()
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// errorKind returns the message of the sentinel error [msg] wraps, which
+// the actions put before any detail.
+func (e *executionStats) errorKind(msg string) string {
+	if i := strings.Index(msg, ": "); i >= 0 {
+		msg = msg[:i]
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if !e.kinds.Contains(msg) {
+		if e.kinds.Len() >= maxErrorKinds {
+			return otherErrorKind
+		}
+		e.kinds.Add(msg)
+	}
+	return msg
+}
+
+func (*executionStats) Close() error {
+	return nil
+}
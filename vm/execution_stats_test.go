@@ -0,0 +1,56 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+)
+
+func TestExecutionStats(t *testing.T) {
+	require := require.New(t)
+	m, err := newMetrics()
+	require.NoError(err)
+	es := newExecutionStats(m)
+
+	transfer := &actions.Transfer{To: codectest.NewRandomAddress(), Value: 1}
+	mint := &actions.MintAsset{}
+	blk := &chain.ExecutedBlock{
+		Block: &chain.StatelessBlock{
+			Txs: []*chain.Transaction{
+				{Actions: []chain.Action{transfer}},
+				// The mint fails, so the transfer after it does not run.
+				{Actions: []chain.Action{transfer, mint, transfer}},
+			},
+		},
+		Results: []*chain.Result{
+			{Success: true, Outputs: [][]byte{{}}},
+			{Error: []byte(fmt.Sprintf("%s: %s", storage.ErrAssetExists, "detail")), Outputs: [][]byte{{}}},
+		},
+	}
+	require.NoError(es.Accept(blk))
+
+	require.Equal(2.0, testutil.ToFloat64(m.actionExecutions.WithLabelValues("Transfer", "success")))
+	require.Equal(1.0, testutil.ToFloat64(m.actionExecutions.WithLabelValues("MintAsset", "failure")))
+	require.Equal(0.0, testutil.ToFloat64(m.actionExecutions.WithLabelValues("Transfer", "failure")))
+	require.Equal(1.0, testutil.ToFloat64(m.actionErrors.WithLabelValues("MintAsset", storage.ErrAssetExists.Error())))
+
+	for i := 0; i < maxErrorKinds+1; i++ {
+		es.errorKind(fmt.Sprintf("error %d", i))
+	}
+	require.Equal(otherErrorKind, es.errorKind("unseen"))
+	require.Equal(storage.ErrAssetExists.Error(), es.errorKind(storage.ErrAssetExists.Error()))
+
+	es.RecordLatency(storage.BalanceKey(codectest.NewRandomAddress()), true, time.Millisecond)
+	require.Equal(1, testutil.CollectAndCount(m.storageWriteSeconds))
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/event"
+)
+
+// maxTrackedExpiries bounds the submitted transactions awaiting inclusion
+// that the [expiryTracker] follows. Later ones are not counted.
+const maxTrackedExpiries = 1 << 16
+
+var (
+	_ event.SubscriptionFactory[*chain.ExecutedBlock] = (*expiryTracker)(nil)
+	_ event.Subscription[*chain.ExecutedBlock]        = (*expiryTracker)(nil)
+)
+
+// expiryTracker follows the transactions submitted to this node until a
+// block includes them or passes their expiry. The hypersdk mempool drops
+// expired transactions without telling anyone, and its builder packs them
+// in arrival order, so the tracker can only count and log them: blocks
+// still cannot be built with the transactions closest to expiry first.
+//
+// Transactions gossiped from other nodes are not followed.
+type expiryTracker struct {
+	metrics *metrics
+	log     logging.Logger
+
+	lock    sync.Mutex
+	pending map[ids.ID]int64
+}
+
+func newExpiryTracker() *expiryTracker {
+	return &expiryTracker{pending: map[ids.ID]int64{}}
+}
+
+// start enables the tracker. It is left disabled if the MorpheusVM API is.
+func (e *expiryTracker) start(m *metrics, log logging.Logger) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.metrics, e.log = m, log
+}
+
+func (e *expiryTracker) enabled() bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.metrics != nil
+}
+
+// track follows the transactions of [txs] that [errs] reports as admitted.
+func (e *expiryTracker) track(txs []*chain.Transaction, errs []error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.metrics == nil {
+		return
+	}
+	for i, tx := range txs {
+		if errs[i] != nil || len(e.pending) >= maxTrackedExpiries {
+			continue
+		}
+		e.pending[tx.ID()] = tx.Expiry()
+	}
+	e.metrics.txsAwaitingInclusion.Set(float64(len(e.pending)))
+}
+
+func (e *expiryTracker) New() (event.Subscription[*chain.ExecutedBlock], error) {
+	return e, nil
+}
+
+func (e *expiryTracker) Accept(blk *chain.ExecutedBlock) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	now := blk.Block.Tmstmp
+	for _, tx := range blk.Block.Txs {
+		expiry, ok := e.pending[tx.ID()]
+		if !ok {
+			continue
+		}
+		delete(e.pending, tx.ID())
+		e.metrics.txExpiryMargin.Observe((time.Duration(expiry-now) * time.Millisecond).Seconds())
+	}
+	// The mempool drops transactions that expire before the block time.
+	for txID, expiry := range e.pending {
+		if expiry >= now {
+			continue
+		}
+		delete(e.pending, txID)
+		e.metrics.expiredTxs.Inc()
+		e.log.Info("transaction expired before inclusion",
+			zap.Stringer("txID", txID),
+			zap.Int64("expiry", expiry),
+			zap.Uint64("height", blk.Block.Hght),
+		)
+	}
+	e.metrics.txsAwaitingInclusion.Set(float64(len(e.pending)))
+	return nil
+}
+
+func (*expiryTracker) Close() error {
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+func TestExpiryTracker(t *testing.T) {
+	require := require.New(t)
+	m, err := newMetrics()
+	require.NoError(err)
+	e := newExpiryTracker()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	chainID := ids.GenerateTestID()
+	newTx := func(expiry int64) *chain.Transaction {
+		tx, err := (&chain.Transaction{
+			Base:    &chain.Base{Timestamp: expiry, ChainID: chainID, MaxFee: 1},
+			Actions: []chain.Action{&actions.Transfer{To: codectest.NewRandomAddress(), Value: 1}},
+		}).Sign(auth.NewED25519Factory(priv), ActionParser, AuthParser)
+		require.NoError(err)
+		return tx
+	}
+	included, expiring, later, rejected := newTx(20_000), newTx(11_000), newTx(30_000), newTx(11_000)
+
+	// Nothing is followed until the MorpheusVM API starts the tracker.
+	require.False(e.enabled())
+	e.track([]*chain.Transaction{included}, []error{nil})
+	e.start(m, logging.NoLog{})
+	require.True(e.enabled())
+	e.track(
+		[]*chain.Transaction{included, expiring, later, rejected},
+		[]error{nil, nil, nil, errors.New("rejected")},
+	)
+	require.Equal(3.0, testutil.ToFloat64(m.txsAwaitingInclusion))
+
+	require.NoError(e.Accept(&chain.ExecutedBlock{
+		Block: &chain.StatelessBlock{Hght: 1, Tmstmp: 12_000, Txs: []*chain.Transaction{included}},
+	}))
+	require.Equal(1.0, testutil.ToFloat64(m.expiredTxs))
+	require.Equal(1.0, testutil.ToFloat64(m.txsAwaitingInclusion))
+	require.Equal(1, testutil.CollectAndCount(m.txExpiryMargin))
+}
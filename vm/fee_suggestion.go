@@ -0,0 +1,94 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/fees"
+)
+
+const (
+	// DefaultFeeBlocks is how many recent blocks SuggestFee samples by
+	// default.
+	DefaultFeeBlocks = 20
+	// DefaultFeePercentile is the percentile of the sampled unit prices
+	// SuggestFee suggests by default.
+	DefaultFeePercentile = 60
+)
+
+var (
+	ErrInvalidPercentile = errors.New("percentile must be at most 100")
+	ErrUnknownAuth       = errors.New("unknown auth type")
+	errEstimateOnly      = errors.New("factory only estimates units")
+)
+
+var _ chain.AuthFactory = (*unitsFactory)(nil)
+
+// unitsFactory stands in for the signer of a transaction whose units are
+// estimated.
+type unitsFactory struct {
+	actor     codec.Address
+	bandwidth uint64
+	compute   uint64
+}
+
+// newUnitsFactory returns a stand-in for a signer of key type [key], such
+// as "ed25519".
+func newUnitsFactory(key string, actor codec.Address) (*unitsFactory, error) {
+	f := &unitsFactory{actor: actor}
+	switch key {
+	case auth.ED25519Key, "":
+		f.bandwidth, f.compute = auth.ED25519Size, auth.ED25519ComputeUnits
+	case auth.Secp256r1Key:
+		f.bandwidth, f.compute = auth.SECP256R1Size, auth.SECP256R1ComputeUnits
+	case auth.BLSKey:
+		f.bandwidth, f.compute = auth.BLSSize, auth.BLSComputeUnits
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAuth, key)
+	}
+	return f, nil
+}
+
+func (*unitsFactory) Sign([]byte) (chain.Auth, error) {
+	return nil, errEstimateOnly
+}
+
+func (f *unitsFactory) MaxUnits() (uint64, uint64) {
+	return f.bandwidth, f.compute
+}
+
+func (f *unitsFactory) Address() codec.Address {
+	return f.actor
+}
+
+// suggestUnitPrices returns, for each dimension, the [percentile] of the
+// unit prices of [history], raised to [current] if lower so that a max fee
+// at the suggested prices covers the fee at the current ones.
+func suggestUnitPrices(history []fees.Dimensions, current fees.Dimensions, percentile uint64) fees.Dimensions {
+	suggested := current
+	if len(history) == 0 {
+		return suggested
+	}
+	prices := make([]uint64, len(history))
+	for d := range suggested {
+		for i, h := range history {
+			prices[i] = h[d]
+		}
+		slices.Sort(prices)
+		// The nearest-rank percentile, so 0 is the lowest price and 100
+		// the highest.
+		rank := (percentile*uint64(len(prices)) + 99) / 100
+		if rank > 0 {
+			rank--
+		}
+		suggested[d] = max(prices[rank], current[d])
+	}
+	return suggested
+}
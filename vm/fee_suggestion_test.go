@@ -0,0 +1,55 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/fees"
+	"github.com/ava-labs/hypersdk/genesis"
+)
+
+func TestSuggestUnitPrices(t *testing.T) {
+	require := require.New(t)
+
+	current := fees.Dimensions{100, 100, 100, 100, 100}
+	require.Equal(current, suggestUnitPrices(nil, current, 60))
+
+	var history []fees.Dimensions
+	for i := uint64(1); i <= 10; i++ {
+		history = append(history, fees.Dimensions{100 * i, 100, 50, 100 * (11 - i), 100})
+	}
+	require.Equal(fees.Dimensions{600, 100, 100, 600, 100}, suggestUnitPrices(history, current, 60))
+	require.Equal(fees.Dimensions{1000, 100, 100, 1000, 100}, suggestUnitPrices(history, current, 100))
+	// The lowest sampled price is never below the current one.
+	require.Equal(fees.Dimensions{100, 100, 100, 100, 100}, suggestUnitPrices(history, current, 1))
+}
+
+func TestUnitsFactory(t *testing.T) {
+	require := require.New(t)
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	signer := auth.NewED25519Factory(priv)
+	estimator, err := newUnitsFactory(auth.ED25519Key, signer.Address())
+	require.NoError(err)
+
+	r := genesis.NewDefaultRules()
+	acts := []chain.Action{&actions.Transfer{To: codectest.NewRandomAddress(), Value: 1}}
+	want, err := chain.EstimateUnits(r, acts, signer)
+	require.NoError(err)
+	got, err := chain.EstimateUnits(r, acts, estimator)
+	require.NoError(err)
+	require.Equal(want, got)
+
+	_, err = newUnitsFactory("rsa", signer.Address())
+	require.ErrorIs(err, ErrUnknownAuth)
+}
@@ -0,0 +1,215 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ genesis.Genesis = (*Genesis)(nil)
+
+var ErrInvalidGenesis = errors.New("invalid genesis")
+
+// Genesis extends the default genesis with MorpheusVM state. Its JSON is a
+// superset of [genesis.DefaultGenesis], so default genesis files still load.
+type Genesis struct {
+	*genesis.DefaultGenesis
+
+	// Rules shadows DefaultGenesis.Rules, which points at its hypersdk
+	// part.
+	Rules *Rules `json:"initialRules"`
+
+	// Treasury is the council allowed to spend from
+	// [storage.TreasuryAddress]. Without one, treasury funds are locked.
+	Treasury *storage.Council `json:"treasury,omitempty"`
+
+	// Security is the council allowed to halt and resume the chain with
+	// the HaltChain and ResumeChain actions. Without one, the chain cannot
+	// be halted.
+	Security *storage.Council `json:"security,omitempty"`
+
+	// Assets are pre-minted after the allocations, each holding its total
+	// supply in its owner's balance.
+	Assets []*GenesisAsset `json:"assets,omitempty"`
+
+	// Seed is demo state created after the allocations and treasury.
+	Seed *Seed `json:"seed,omitempty"`
+}
+
+// GenesisAsset is an asset created at genesis.
+type GenesisAsset struct {
+	ID       ids.ID                `json:"id"`
+	Owner    codec.Address         `json:"owner"`
+	Metadata storage.AssetMetadata `json:"metadata"`
+}
+
+// WithAssets returns [genesisBytes] with the assets in [assetsBytes], a JSON
+// list of [GenesisAsset].
+func WithAssets(genesisBytes []byte, assetsBytes []byte) ([]byte, error) {
+	return withGenesisField(genesisBytes, "assets", assetsBytes)
+}
+
+// withGenesisField returns [genesisBytes] with [key] set to [value],
+// checking that the result still parses.
+func withGenesisField(genesisBytes []byte, key string, value []byte) ([]byte, error) {
+	var g map[string]json.RawMessage
+	if err := json.Unmarshal(genesisBytes, &g); err != nil {
+		return nil, err
+	}
+	g[key] = value
+	b, err := json.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parseGenesis(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func parseGenesis(b []byte) (*Genesis, error) {
+	g := &Genesis{Rules: newRules()}
+	if err := json.Unmarshal(b, g); err != nil {
+		return nil, err
+	}
+	if g.DefaultGenesis == nil || g.Rules == nil || g.Rules.Rules == nil {
+		return nil, fmt.Errorf("%w: missing initialRules", ErrInvalidGenesis)
+	}
+	if err := g.Rules.verify(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidGenesis, err)
+	}
+	g.DefaultGenesis.Rules = g.Rules.Rules
+	if err := g.verifyAllocations(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidGenesis, err)
+	}
+	if err := g.verifyAssets(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidGenesis, err)
+	}
+	if g.Treasury != nil {
+		if err := g.Treasury.Verify(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidGenesis, err)
+		}
+	}
+	if g.Security != nil {
+		if err := g.Security.Verify(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidGenesis, err)
+		}
+	}
+	if g.Seed != nil {
+		if err := g.Seed.decode(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidGenesis, err)
+		}
+	}
+	return g, nil
+}
+
+// verifyAllocations rejects repeated allocation addresses and native
+// supply, including the seed accounts, that overflows a uint64.
+func (g *Genesis) verifyAllocations() error {
+	var (
+		addrs  set.Set[codec.Address]
+		supply uint64
+		err    error
+	)
+	for _, alloc := range g.CustomAllocation {
+		if alloc == nil {
+			return errors.New("empty allocation")
+		}
+		if addrs.Contains(alloc.Address) {
+			return fmt.Errorf("duplicate allocation for %s", alloc.Address)
+		}
+		addrs.Add(alloc.Address)
+		supply, err = smath.Add(supply, alloc.Balance)
+		if err != nil {
+			return fmt.Errorf("total supply: %w", err)
+		}
+	}
+	if g.Seed == nil {
+		return nil
+	}
+	for _, account := range g.Seed.Accounts {
+		if account == nil {
+			return errors.New("empty seed account")
+		}
+		supply, err = smath.Add(supply, account.Balance)
+		if err != nil {
+			return fmt.Errorf("total supply: %w", err)
+		}
+	}
+	return nil
+}
+
+func (g *Genesis) verifyAssets() error {
+	var assets set.Set[ids.ID]
+	for i, asset := range g.Assets {
+		if asset == nil {
+			return fmt.Errorf("asset %d is empty", i)
+		}
+		if asset.ID == ids.Empty {
+			return fmt.Errorf("asset %d has no id", i)
+		}
+		if assets.Contains(asset.ID) {
+			return fmt.Errorf("%w: %s", storage.ErrAssetExists, asset.ID)
+		}
+		assets.Add(asset.ID)
+		if err := asset.Metadata.Verify(); err != nil {
+			return fmt.Errorf("asset %s: %w", asset.ID, err)
+		}
+	}
+	return nil
+}
+
+func (g *Genesis) InitializeState(ctx context.Context, tracer trace.Tracer, mu state.Mutable, balanceHandler chain.BalanceHandler) error {
+	if err := g.DefaultGenesis.InitializeState(ctx, tracer, mu, balanceHandler); err != nil {
+		return err
+	}
+	if g.Treasury != nil {
+		if err := storage.SetTreasuryCouncil(ctx, mu, g.Treasury); err != nil {
+			return err
+		}
+	}
+	if g.Security != nil {
+		if err := storage.SetSecurityCouncil(ctx, mu, g.Security); err != nil {
+			return err
+		}
+	}
+	for _, asset := range g.Assets {
+		if err := asset.initializeState(ctx, mu); err != nil {
+			return err
+		}
+	}
+	if g.Seed == nil {
+		return nil
+	}
+	return g.Seed.initializeState(ctx, g.Rules, mu, balanceHandler)
+}
+
+func (a *GenesisAsset) initializeState(ctx context.Context, mu state.Mutable) error {
+	if err := storage.CreateAsset(ctx, mu, a.ID, a.Owner); err != nil {
+		return err
+	}
+	if err := storage.SetAssetMetadata(ctx, mu, a.ID, a.Metadata); err != nil {
+		return err
+	}
+	if a.Metadata.TotalSupply == 0 {
+		return nil
+	}
+	_, err := storage.AddAssetBalance(ctx, mu, a.Owner, a.ID, a.Metadata.TotalSupply, true)
+	return err
+}
@@ -0,0 +1,240 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/genesis"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+// genesisBytes returns a default genesis with [allocs].
+func genesisBytes(t *testing.T, allocs ...*genesis.CustomAllocation) []byte {
+	b, err := json.Marshal(genesis.NewDefaultGenesis(allocs))
+	require.NoError(t, err)
+	return b
+}
+
+func TestParseGenesisAllocations(t *testing.T) {
+	addr := codectest.NewRandomAddress()
+	other := codectest.NewRandomAddress()
+
+	tests := []struct {
+		name   string
+		allocs []*genesis.CustomAllocation
+		seed   string
+		err    string
+	}{
+		{
+			name:   "Valid",
+			allocs: []*genesis.CustomAllocation{{Address: addr, Balance: 10}, {Address: other, Balance: math.MaxUint64 - 10}},
+		},
+		{
+			name:   "DuplicateAddress",
+			allocs: []*genesis.CustomAllocation{{Address: addr, Balance: 10}, {Address: other, Balance: 10}, {Address: addr, Balance: 5}},
+			err:    "duplicate allocation",
+		},
+		{
+			name:   "SupplyOverflow",
+			allocs: []*genesis.CustomAllocation{{Address: addr, Balance: math.MaxUint64}, {Address: other, Balance: 1}},
+			err:    "total supply",
+		},
+		{
+			name:   "SeedSupplyOverflow",
+			allocs: []*genesis.CustomAllocation{{Address: addr, Balance: math.MaxUint64}},
+			seed:   seedJSON(t, &genesis.CustomAllocation{Address: other, Balance: 1}),
+			err:    "total supply",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			b := genesisBytes(t, tt.allocs...)
+			if tt.seed != "" {
+				var err error
+				b, err = withGenesisField(b, "seed", []byte(tt.seed))
+				if tt.err != "" {
+					require.ErrorIs(err, ErrInvalidGenesis)
+					require.ErrorContains(err, tt.err)
+					return
+				}
+				require.NoError(err)
+			}
+			_, err := parseGenesis(b)
+			if tt.err == "" {
+				require.NoError(err)
+				return
+			}
+			require.ErrorIs(err, ErrInvalidGenesis)
+			require.ErrorContains(err, tt.err)
+		})
+	}
+}
+
+func seedJSON(t *testing.T, accounts ...*genesis.CustomAllocation) string {
+	b, err := json.Marshal(&Seed{Accounts: accounts})
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestParseGenesisMalformed(t *testing.T) {
+	base := genesisBytes(t)
+	owner := codectest.NewRandomAddress()
+	assetID := ids.GenerateTestID()
+
+	tests := []struct {
+		name   string
+		assets string
+		err    error
+	}{
+		{
+			name:   "NotJSON",
+			assets: `[{"id": }]`,
+		},
+		{
+			name:   "BadID",
+			assets: `[{"id": "not an id"}]`,
+		},
+		{
+			name:   "BadOwner",
+			assets: `[{"id": "` + assetID.String() + `", "owner": "nope"}]`,
+		},
+		{
+			name:   "NegativeSupply",
+			assets: `[{"id": "` + assetID.String() + `", "metadata": {"totalSupply": -1}}]`,
+		},
+		{
+			name:   "Empty",
+			assets: `[null]`,
+			err:    ErrInvalidGenesis,
+		},
+		{
+			name:   "NoID",
+			assets: `[{"metadata": {"name": "Gold"}}]`,
+			err:    ErrInvalidGenesis,
+		},
+		{
+			name:   "DuplicateAsset",
+			assets: assetsJSON(t, &GenesisAsset{ID: assetID, Owner: owner}, &GenesisAsset{ID: assetID, Owner: owner}),
+			err:    storage.ErrAssetExists,
+		},
+		{
+			name: "MetadataTooLarge",
+			assets: assetsJSON(t, &GenesisAsset{
+				ID:       assetID,
+				Owner:    owner,
+				Metadata: storage.AssetMetadata{Name: strings.Repeat("a", storage.MaxAssetNameSize+1)},
+			}),
+			err: storage.ErrAssetMetadataTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := WithAssets(base, []byte(tt.assets))
+			require.Error(t, err)
+			require.Nil(t, b)
+			if tt.err != nil {
+				require.ErrorIs(t, err, ErrInvalidGenesis)
+				require.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+
+	_, err := parseGenesis([]byte(`{"initialRules": null}`))
+	require.ErrorIs(t, err, ErrInvalidGenesis)
+}
+
+func assetsJSON(t *testing.T, assets ...*GenesisAsset) string {
+	b, err := json.Marshal(assets)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestGenesisAssets(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	owner := codectest.NewRandomAddress()
+	gold, badge := ids.GenerateTestID(), ids.GenerateTestID()
+	metadata := storage.AssetMetadata{Name: "Gold", Symbol: "GLD", Decimals: 9, TotalSupply: 1_000_000}
+
+	b, err := WithAssets(
+		genesisBytes(t, &genesis.CustomAllocation{Address: owner, Balance: 500}),
+		[]byte(assetsJSON(t, &GenesisAsset{ID: gold, Owner: owner, Metadata: metadata}, &GenesisAsset{ID: badge, Owner: owner})),
+	)
+	require.NoError(err)
+	g, err := parseGenesis(b)
+	require.NoError(err)
+
+	store := chaintest.NewInMemoryStore()
+	require.NoError(g.InitializeState(ctx, trace.Noop, store, &storage.StateManager{}))
+
+	balance, err := storage.GetBalance(ctx, store, owner)
+	require.NoError(err)
+	require.Equal(uint64(500), balance)
+
+	gotOwner, err := storage.GetAssetOwner(ctx, store, gold)
+	require.NoError(err)
+	require.Equal(owner, gotOwner)
+	gotMetadata, err := storage.GetAssetMetadata(ctx, store, gold)
+	require.NoError(err)
+	require.Equal(metadata, gotMetadata)
+	supply, err := storage.GetAssetBalance(ctx, store, owner, gold)
+	require.NoError(err)
+	require.Equal(metadata.TotalSupply, supply)
+
+	supply, err = storage.GetAssetBalance(ctx, store, owner, badge)
+	require.NoError(err)
+	require.Zero(supply)
+}
+
+func TestParseGenesisComputeUnits(t *testing.T) {
+	require := require.New(t)
+
+	// withRules returns a default genesis with [rules] merged into its
+	// initialRules.
+	withRules := func(rules string) []byte {
+		var g map[string]json.RawMessage
+		require.NoError(json.Unmarshal(genesisBytes(t), &g))
+		var r map[string]json.RawMessage
+		require.NoError(json.Unmarshal(g["initialRules"], &r))
+		require.NoError(json.Unmarshal([]byte(rules), &r))
+		var err error
+		g["initialRules"], err = json.Marshal(r)
+		require.NoError(err)
+		b, err := json.Marshal(g)
+		require.NoError(err)
+		return b
+	}
+
+	g, err := parseGenesis(withRules(`{"computeUnits": {"Transfer": 7, "MintAsset": 3}, "maxReasonSize": 16}`))
+	require.NoError(err)
+	v, ok := g.Rules.FetchCustom(actions.ComputeUnitsRule)
+	require.True(ok)
+	require.Equal(map[uint8]uint64{mconsts.TransferID: 7, mconsts.MintAssetID: 3}, v)
+	require.Equal(uint64(7), (&actions.Transfer{Value: 1}).ComputeUnits(g.Rules))
+	require.Equal(16, actions.ReasonRules(g.Rules))
+
+	for _, rules := range []string{
+		`{"computeUnits": {"Teleport": 1}}`,
+		`{"computeUnits": {"Transfer": 0}}`,
+		`{"maxReasonSize": -1}`,
+	} {
+		_, err := parseGenesis(withRules(rules))
+		require.ErrorIs(err, ErrInvalidRules, rules)
+	}
+}
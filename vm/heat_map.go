@@ -0,0 +1,140 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"cmp"
+	"errors"
+	"hash/fnv"
+	"slices"
+	"sync"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/event"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// HeatMapBuckets is how many buckets the keys of each record type are
+// hashed into. A bucket much hotter than its siblings points at a few hot
+// keys rather than a hot record type.
+const HeatMapBuckets = 16
+
+var ErrHeatMapUnavailable = errors.New("state heat map unavailable")
+
+var (
+	_ event.SubscriptionFactory[*chain.ExecutedBlock] = (*heatMap)(nil)
+	_ event.Subscription[*chain.ExecutedBlock]        = (*heatMap)(nil)
+)
+
+// HeatMapCell counts the accesses transactions declared to one bucket of
+// keys of a record type.
+type HeatMapCell struct {
+	// Prefix names the record type, as in [storage.PrefixName].
+	Prefix    string `json:"prefix"`
+	Bucket    uint32 `json:"bucket"`
+	Reads     uint64 `json:"reads"`
+	Allocates uint64 `json:"allocates"`
+	Writes    uint64 `json:"writes"`
+}
+
+type heatMapKey struct {
+	prefix string
+	bucket uint32
+}
+
+// heatMap counts the state keys of accepted transactions by record type
+// and bucket, with the permissions each transaction declared for them.
+// Declared keys include keys an action ended up not touching, but they are
+// what the chain charges and locks for. Counts start over when the node
+// restarts.
+type heatMap struct {
+	metrics *metrics
+
+	lock   sync.Mutex
+	height uint64
+	cells  map[heatMapKey]*HeatMapCell
+}
+
+func newHeatMap(m *metrics) *heatMap {
+	return &heatMap{
+		metrics: m,
+		cells:   make(map[heatMapKey]*HeatMapCell),
+	}
+}
+
+// heatMapBucket hashes [key], without its chunk suffix, into a bucket.
+func heatMapBucket(key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key[:max(len(key)-consts.Uint16Len, 0)]))
+	return h.Sum32() % HeatMapBuckets
+}
+
+func (h *heatMap) New() (event.Subscription[*chain.ExecutedBlock], error) {
+	return h, nil
+}
+
+func (h *heatMap) Accept(blk *chain.ExecutedBlock) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	height := blk.Block.Hght
+	if height <= h.height && height != 0 {
+		return nil
+	}
+	h.height = height
+	for _, tx := range blk.Block.Txs {
+		stateKeys, err := tx.StateKeys(&storage.StateManager{})
+		if err != nil {
+			return err
+		}
+		for k, permissions := range stateKeys {
+			prefix := storage.PrefixName([]byte(k))
+			key := heatMapKey{prefix: prefix, bucket: heatMapBucket(k)}
+			cell, ok := h.cells[key]
+			if !ok {
+				cell = &HeatMapCell{Prefix: prefix, Bucket: key.bucket}
+				h.cells[key] = cell
+			}
+			if permissions.Has(state.Read) {
+				cell.Reads++
+				h.metrics.stateKeyAccesses.WithLabelValues(prefix, "read").Inc()
+			}
+			if permissions.Has(state.Allocate) {
+				cell.Allocates++
+				h.metrics.stateKeyAccesses.WithLabelValues(prefix, "allocate").Inc()
+			}
+			if permissions.Has(state.Write) {
+				cell.Writes++
+				h.metrics.stateKeyAccesses.WithLabelValues(prefix, "write").Inc()
+			}
+		}
+	}
+	return nil
+}
+
+// Cells returns a copy of the heat map ordered by prefix and bucket, and
+// the last height it counted.
+func (h *heatMap) Cells() ([]*HeatMapCell, uint64) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	cells := make([]*HeatMapCell, 0, len(h.cells))
+	for _, cell := range h.cells {
+		c := *cell
+		cells = append(cells, &c)
+	}
+	slices.SortFunc(cells, func(a, b *HeatMapCell) int {
+		if c := cmp.Compare(a.Prefix, b.Prefix); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Bucket, b.Bucket)
+	})
+	return cells, h.height
+}
+
+func (*heatMap) Close() error {
+	return nil
+}
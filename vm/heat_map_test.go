@@ -0,0 +1,59 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+func TestHeatMap(t *testing.T) {
+	require := require.New(t)
+	m, err := newMetrics()
+	require.NoError(err)
+	hm := newHeatMap(m)
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	blk := &chain.ExecutedBlock{
+		Block: &chain.StatelessBlock{
+			Hght: 1,
+			Txs: []*chain.Transaction{{
+				Actions: []chain.Action{&actions.Transfer{To: codectest.NewRandomAddress(), Value: 1}},
+				Auth:    &auth.ED25519{Signer: priv.PublicKey()},
+			}},
+		},
+	}
+	require.NoError(hm.Accept(blk))
+	// A block is counted once, however often it is delivered.
+	require.NoError(hm.Accept(blk))
+
+	cells, height := hm.Cells()
+	require.Equal(uint64(1), height)
+	var balance HeatMapCell
+	for i, cell := range cells {
+		require.Less(cell.Bucket, uint32(HeatMapBuckets))
+		if i > 0 {
+			prev := cells[i-1]
+			require.True(prev.Prefix < cell.Prefix || prev.Prefix == cell.Prefix && prev.Bucket < cell.Bucket)
+		}
+		if cell.Prefix == "balance" {
+			balance.Reads += cell.Reads
+			balance.Allocates += cell.Allocates
+			balance.Writes += cell.Writes
+		}
+	}
+	// The sender's balance is read and written, the recipient's may also be
+	// allocated.
+	require.Equal(uint64(2), balance.Reads)
+	require.Equal(uint64(1), balance.Allocates)
+	require.Equal(uint64(2), balance.Writes)
+}
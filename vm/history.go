@@ -0,0 +1,129 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/ava-labs/hypersdk/vm"
+)
+
+var _ historicalState = (*vm.VM)(nil)
+
+var (
+	ErrHistoryUnavailable = errors.New("historical state unavailable")
+	ErrHeightNotAccepted  = errors.New("height not accepted")
+)
+
+// historicalState is implemented by the hypersdk VM. It is not part of
+// [api.VM], so height-pinned reads are only available when the server is
+// mounted on a full VM.
+type historicalState interface {
+	State() (merkledb.MerkleDB, error)
+	GetDiskBlock(ctx context.Context, height uint64) (*chain.StatefulBlock, error)
+}
+
+// readStateAt returns a [storage.ReadState] serving values as they were after
+// the block at [height] was accepted.
+//
+// The last accepted height is served from current state. Older heights within
+// the configured history window are served from the merkledb change history,
+// using the state root committed to by the following block. In archival
+// mode, heights beyond it are served from the archive.
+func (j *JSONRPCServer) readStateAt(height uint64) storage.ReadState {
+	return func(ctx context.Context, keys [][]byte) ([][]byte, []error) {
+		values, errs, currentHeight, err := storage.ReadWithHeight(ctx, j.vm.ReadState, keys)
+		switch {
+		case err != nil:
+			return values, utils.Repeat(err, len(keys))
+		case currentHeight == height:
+			return values, errs
+		case currentHeight < height:
+			err := fmt.Errorf("%w: height=%d, lastAccepted=%d", ErrHeightNotAccepted, height, currentHeight)
+			return values, utils.Repeat(err, len(keys))
+		case j.history != nil && currentHeight-height <= j.config.HistoryWindow:
+			j.metrics.historicalReads.Inc()
+			return readHistorical(ctx, j.history, height, keys)
+		case j.archive != nil:
+			j.metrics.historicalReads.Inc()
+			return j.archive.ReadState(height)(ctx, keys)
+		default:
+			j.metrics.historicalReadsRejected.Inc()
+			err := fmt.Errorf("%w: height=%d, window=%d", ErrHistoryUnavailable, height, j.config.HistoryWindow)
+			return values, utils.Repeat(err, len(keys))
+		}
+	}
+}
+
+func readHistorical(
+	ctx context.Context,
+	history historicalState,
+	height uint64,
+	keys [][]byte,
+) ([][]byte, []error) {
+	values := make([][]byte, len(keys))
+	errs := make([]error, len(keys))
+
+	db, err := history.State()
+	if err != nil {
+		return values, utils.Repeat(err, len(keys))
+	}
+	// Roots are deferred by one block: the root of the state after [height]
+	// is carried by the block at [height+1].
+	next, err := history.GetDiskBlock(ctx, height+1)
+	if err != nil {
+		return values, utils.Repeat[error](fmt.Errorf("%w: %w", ErrHistoryUnavailable, err), len(keys))
+	}
+	for i, key := range keys {
+		proof, err := db.GetRangeProofAtRoot(ctx, next.StateRoot, maybe.Some(key), maybe.Some(key), 1)
+		if errors.Is(err, merkledb.ErrInsufficientHistory) {
+			errs[i] = fmt.Errorf("%w: %w", ErrHistoryUnavailable, err)
+			continue
+		}
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		errs[i] = database.ErrNotFound
+		for _, kv := range proof.KeyValues {
+			if string(kv.Key) == string(key) {
+				values[i], errs[i] = kv.Value, nil
+				break
+			}
+		}
+	}
+	return values, errs
+}
+
+// proveHistorical returns a range proof of [key] alone in the state after
+// [height], with the block that commits to its root.
+func proveHistorical(
+	ctx context.Context,
+	history historicalState,
+	height uint64,
+	key []byte,
+) (*chain.StatefulBlock, *merkledb.RangeProof, error) {
+	db, err := history.State()
+	if err != nil {
+		return nil, nil, err
+	}
+	next, err := history.GetDiskBlock(ctx, height+1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrHistoryUnavailable, err)
+	}
+	proof, err := db.GetRangeProofAtRoot(ctx, next.StateRoot, maybe.Some(key), maybe.Some(key), 1)
+	if errors.Is(err, merkledb.ErrInsufficientHistory) {
+		return nil, nil, fmt.Errorf("%w: %w", ErrHistoryUnavailable, err)
+	}
+	return next, proof, err
+}
@@ -0,0 +1,161 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/api"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+// rootHistory serves a merkledb as [historicalState], with a block at each
+// height committing to the root left by the one before.
+type rootHistory struct {
+	merkleHistory
+	roots map[uint64]ids.ID
+}
+
+func (h rootHistory) GetDiskBlock(_ context.Context, height uint64) (*chain.StatefulBlock, error) {
+	root, ok := h.roots[height-1]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return &chain.StatefulBlock{StatelessBlock: &chain.StatelessBlock{Hght: height, StateRoot: root}}, nil
+}
+
+// stateVM serves the current state of a merkledb as an [api.VM].
+type stateVM struct {
+	api.VM
+	history rootHistory
+}
+
+func (v stateVM) ReadState(ctx context.Context, keys [][]byte) ([][]byte, []error) {
+	values := make([][]byte, len(keys))
+	errs := make([]error, len(keys))
+	for i, k := range keys {
+		values[i], errs[i] = v.history.GetValue(ctx, k)
+	}
+	return values, errs
+}
+
+func TestReadStateAt(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	_, db := newTestLists(t)
+	m, err := newMetrics()
+	require.NoError(err)
+
+	// Each height commits its height and sets [key] to it. The merkledb
+	// keeps the roots of its last 8 commits.
+	key := []byte("key")
+	history := rootHistory{merkleHistory: merkleHistory{db}, roots: make(map[uint64]ids.ID)}
+	for height := uint64(1); height <= 12; height++ {
+		value := binary.BigEndian.AppendUint64(nil, height)
+		batch := db.NewBatch()
+		require.NoError(batch.Put(chain.HeightKey(storage.HeightKey()), value))
+		require.NoError(batch.Put(key, value))
+		require.NoError(batch.Write())
+		root, err := db.GetMerkleRoot(ctx)
+		require.NoError(err)
+		history.roots[height] = root
+	}
+	j := &JSONRPCServer{
+		vm:      stateVM{history: history},
+		config:  Config{HistoryWindow: 3},
+		metrics: m,
+		history: history,
+	}
+	read := func(height uint64) (uint64, error) {
+		values, errs := j.readStateAt(height)(ctx, [][]byte{key})
+		if errs[0] != nil {
+			return 0, errs[0]
+		}
+		return database.ParseUInt64(values[0])
+	}
+
+	// The last accepted height is read from current state, and retained
+	// heights from the history.
+	for _, height := range []uint64{12, 11, 9} {
+		value, err := read(height)
+		require.NoError(err)
+		require.Equal(height, value)
+	}
+	require.Equal(2.0, testutil.ToFloat64(m.historicalReads))
+
+	_, err = read(13)
+	require.ErrorIs(err, ErrHeightNotAccepted)
+
+	// Heights beyond the window are pruned, whether or not the merkledb
+	// still has them.
+	_, err = read(8)
+	require.ErrorIs(err, ErrHistoryUnavailable)
+	require.Equal(1.0, testutil.ToFloat64(m.historicalReadsRejected))
+
+	// Within the window, heights the merkledb dropped are unavailable too.
+	j.config.HistoryWindow = 100
+	value, err := read(8)
+	require.NoError(err)
+	require.Equal(uint64(8), value)
+	_, err = read(2)
+	require.ErrorIs(err, ErrHistoryUnavailable)
+
+	// Reads that do not pin a height only accept state at or above their
+	// minimum height.
+	var height uint64
+	_, errs := j.stateReader(ReadOptions{MinHeight: 12}, &height)(ctx, [][]byte{key})
+	require.NoError(errs[0])
+	require.Equal(uint64(12), height)
+	_, errs = j.stateReader(ReadOptions{MinHeight: 13}, &height)(ctx, [][]byte{key})
+	require.ErrorIs(errs[0], storage.ErrStaleState)
+}
+
+func TestHistoryUsage(t *testing.T) {
+	require := require.New(t)
+	m, err := newMetrics()
+	require.NoError(err)
+	hu := newHistoryUsage(2, m)
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	signer := &auth.ED25519{Signer: priv.PublicKey()}
+	to := codectest.NewRandomAddress()
+	transfer := &chain.Transaction{
+		Actions: []chain.Action{&actions.Transfer{To: to, Value: 1}},
+		Auth:    signer,
+	}
+	block := func(height uint64, txs ...*chain.Transaction) *chain.ExecutedBlock {
+		return &chain.ExecutedBlock{Block: &chain.StatelessBlock{Hght: height, Txs: txs}}
+	}
+
+	// A transfer changes the balances of its sender and recipient, and
+	// merkledb keeps both their values before and after the block.
+	balance := uint64(len(storage.BalanceKey(to))) + 2*uint64(storage.BalanceChunks)*stateChunkSize
+	require.NoError(hu.Accept(block(1, transfer)))
+	require.Equal(2*balance, hu.Bytes())
+	require.Equal(float64(2*balance), testutil.ToFloat64(m.historyBytes))
+
+	// Keys changed by several transactions of a block count once.
+	require.NoError(hu.Accept(block(2, transfer, transfer)))
+	require.Equal(4*balance, hu.Bytes())
+
+	// Blocks leave the estimate as they leave the window.
+	require.NoError(hu.Accept(block(3)))
+	require.Equal(2*balance, hu.Bytes())
+	require.NoError(hu.Accept(block(4)))
+	require.Zero(hu.Bytes())
+	require.Zero(testutil.ToFloat64(m.historyBytes))
+}
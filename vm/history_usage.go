@@ -0,0 +1,109 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"sync"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/event"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// stateChunkSize is the size of a state value chunk, as charged by the
+// hypersdk.
+const stateChunkSize = 64
+
+var (
+	_ event.SubscriptionFactory[*chain.ExecutedBlock] = (*historyUsage)(nil)
+	_ event.Subscription[*chain.ExecutedBlock]        = (*historyUsage)(nil)
+)
+
+// historyUsage estimates the memory retained by the merkledb change history
+// for the blocks within the history window.
+//
+// merkledb keeps the value of each key a block changed from before and
+// after the block, and does not report the size of its history. A block
+// can only change the keys its transactions declare as written or
+// allocated, each up to the chunks its key declares, so the estimate is an
+// upper bound. It covers the blocks accepted since the node started.
+type historyUsage struct {
+	metrics *metrics
+
+	lock sync.Mutex
+	// blocks holds the estimate of each block in the window, by height
+	// modulo the window.
+	blocks []uint64
+	bytes  uint64
+}
+
+func newHistoryUsage(window uint64, m *metrics) *historyUsage {
+	return &historyUsage{
+		metrics: m,
+		blocks:  make([]uint64, window),
+	}
+}
+
+func (h *historyUsage) New() (event.Subscription[*chain.ExecutedBlock], error) {
+	return h, nil
+}
+
+func (h *historyUsage) Accept(blk *chain.ExecutedBlock) error {
+	if len(h.blocks) == 0 {
+		return nil
+	}
+	bytes, err := changeBytes(blk.Block.Txs)
+	if err != nil {
+		return err
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	i := blk.Block.Hght % uint64(len(h.blocks))
+	h.bytes = h.bytes - h.blocks[i] + bytes
+	h.blocks[i] = bytes
+	h.metrics.historyBytes.Set(float64(h.bytes))
+	return nil
+}
+
+// Bytes returns the estimated bytes retained for the window.
+func (h *historyUsage) Bytes() uint64 {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return h.bytes
+}
+
+func (*historyUsage) Close() error {
+	return nil
+}
+
+// changeBytes bounds the bytes merkledb retains for the changes of [txs]:
+// each key written or allocated, with a value before and after, counted
+// once however many transactions declare it.
+func changeBytes(txs []*chain.Transaction) (uint64, error) {
+	changed := make(map[string]struct{})
+	var total uint64
+	for _, tx := range txs {
+		stateKeys, err := tx.StateKeys(&storage.StateManager{})
+		if err != nil {
+			return 0, err
+		}
+		for k, permissions := range stateKeys {
+			if !permissions.Has(state.Write) && !permissions.Has(state.Allocate) {
+				continue
+			}
+			if _, ok := changed[k]; ok {
+				continue
+			}
+			changed[k] = struct{}{}
+			chunks, _ := keys.MaxChunks([]byte(k))
+			total += uint64(len(k)) + 2*uint64(chunks)*stateChunkSize
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,188 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/pebbledb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/event"
+)
+
+// journalPageSize bounds the number of changes fetched per change proof.
+const journalPageSize = 1024
+
+var ErrJournalUnavailable = errors.New("state journal unavailable")
+
+var (
+	_ event.SubscriptionFactory[*chain.ExecutedBlock] = (*journal)(nil)
+	_ event.Subscription[*chain.ExecutedBlock]        = (*journal)(nil)
+)
+
+// JournalEntry is a single state mutation.
+type JournalEntry struct {
+	Key codec.Bytes `json:"key"`
+	// PrevHash is the sha256 of the value before the block, or [ids.Empty]
+	// if the key did not exist.
+	PrevHash ids.ID      `json:"prevHash"`
+	Value    codec.Bytes `json:"value"`
+	Deleted  bool        `json:"deleted"`
+}
+
+// BlockJournal lists every key a block changed, in key order. Applying the
+// entries of consecutive blocks to a copy of state reproduces it exactly.
+type BlockJournal struct {
+	Height  uint64         `json:"height"`
+	BlockID ids.ID         `json:"blockId"`
+	Changes []JournalEntry `json:"changes"`
+}
+
+// journal records the mutations of each accepted block.
+//
+// Blocks commit to the state root of their parent, so the changes made by a
+// block are only known once its child is accepted: the journal runs one
+// block behind the chain.
+type journal struct {
+	db      database.Database
+	history historicalState
+	log     logging.Logger
+	window  uint64
+}
+
+func newJournal(path string, history historicalState, log logging.Logger, window uint64) (*journal, error) {
+	db, err := pebbledb.New(path, nil, log, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &journal{
+		db:      db,
+		history: history,
+		log:     log,
+		window:  window,
+	}, nil
+}
+
+func journalPath(dataDir string) string {
+	return filepath.Join(dataDir, Namespace, "journal")
+}
+
+func (j *journal) New() (event.Subscription[*chain.ExecutedBlock], error) {
+	return j, nil
+}
+
+func (j *journal) Accept(blk *chain.ExecutedBlock) error {
+	height := blk.Block.Hght
+	if height == 0 {
+		return nil
+	}
+	ctx := context.Background()
+	parent, err := j.history.GetDiskBlock(ctx, height-1)
+	if err != nil {
+		return err
+	}
+	changes, err := j.changes(ctx, parent.StateRoot, blk.Block.StateRoot)
+	if errors.Is(err, merkledb.ErrInsufficientHistory) {
+		// Expected right after state sync. Mirrors must resync from a
+		// snapshot when a height is missing.
+		j.log.Warn("skipping state journal",
+			zap.Uint64("height", height-1),
+			zap.Error(err),
+		)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(&BlockJournal{
+		Height:  height - 1,
+		BlockID: parent.ID(),
+		Changes: changes,
+	})
+	if err != nil {
+		return err
+	}
+	if err := j.db.Put(binary.BigEndian.AppendUint64(nil, height-1), b); err != nil {
+		return err
+	}
+	if height-1 < j.window {
+		return nil
+	}
+	return j.db.Delete(binary.BigEndian.AppendUint64(nil, height-1-j.window))
+}
+
+func (j *journal) changes(ctx context.Context, startRoot, endRoot ids.ID) ([]JournalEntry, error) {
+	if startRoot == endRoot {
+		return nil, nil
+	}
+	db, err := j.history.State()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		entries []JournalEntry
+		start   = maybe.Nothing[[]byte]()
+	)
+	for {
+		proof, err := db.GetChangeProof(ctx, startRoot, endRoot, start, maybe.Nothing[[]byte](), journalPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range proof.KeyChanges {
+			prev, err := db.GetRangeProofAtRoot(ctx, startRoot, maybe.Some(change.Key), maybe.Some(change.Key), 1)
+			if err != nil && !errors.Is(err, merkledb.ErrEmptyProof) {
+				return nil, err
+			}
+			entry := JournalEntry{
+				Key:     change.Key,
+				Value:   change.Value.Value(),
+				Deleted: change.Value.IsNothing(),
+			}
+			if prev != nil {
+				for _, kv := range prev.KeyValues {
+					if string(kv.Key) == string(change.Key) {
+						entry.PrevHash = hashing.ComputeHash256Array(kv.Value)
+					}
+				}
+			}
+			entries = append(entries, entry)
+		}
+		if len(proof.KeyChanges) < journalPageSize {
+			return entries, nil
+		}
+		// Resume just after the last key returned.
+		last := proof.KeyChanges[len(proof.KeyChanges)-1].Key
+		start = maybe.Some(append(last[:len(last):len(last)], 0))
+	}
+}
+
+func (j *journal) Get(height uint64) (*BlockJournal, error) {
+	b, err := j.db.Get(binary.BigEndian.AppendUint64(nil, height))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, fmt.Errorf("%w: height=%d", ErrJournalUnavailable, height)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var bj BlockJournal
+	return &bj, json.Unmarshal(b, &bj)
+}
+
+func (j *journal) Close() error {
+	return j.db.Close()
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+func TestJournal(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	_, db := newTestLists(t)
+	history := rootHistory{merkleHistory: merkleHistory{db}, roots: make(map[uint64]ids.ID)}
+	commit := func(height uint64, puts map[string]string, deletes ...string) {
+		batch := db.NewBatch()
+		for k, v := range puts {
+			require.NoError(batch.Put([]byte(k), []byte(v)))
+		}
+		for _, k := range deletes {
+			require.NoError(batch.Delete([]byte(k)))
+		}
+		require.NoError(batch.Write())
+		root, err := db.GetMerkleRoot(ctx)
+		require.NoError(err)
+		history.roots[height] = root
+	}
+	// accept accepts the block at [height], which commits to the root left
+	// by its parent.
+	accept := func(j *journal, height uint64) {
+		require.NoError(j.Accept(&chain.ExecutedBlock{
+			Block: &chain.StatelessBlock{Hght: height, StateRoot: history.roots[height-1]},
+		}))
+	}
+
+	commit(0, nil)
+	commit(1, map[string]string{"a": "1", "b": "2"})
+	commit(2, map[string]string{"a": "3", "c": "4"}, "b")
+	commit(3, nil)
+	commit(4, map[string]string{"d": "5"})
+
+	j, err := newJournal(t.TempDir(), history, logging.NoLog{}, 2)
+	require.NoError(err)
+	defer j.Close()
+
+	// A block is journaled once its child is accepted.
+	accept(j, 2)
+	_, err = j.Get(2)
+	require.ErrorIs(err, ErrJournalUnavailable)
+	accept(j, 3)
+	bj, err := j.Get(2)
+	require.NoError(err)
+	require.Equal(uint64(2), bj.Height)
+
+	// Changes are in key order, with the hash of the value they replace.
+	require.Equal([]JournalEntry{
+		{Key: codec.Bytes("a"), PrevHash: hashing.ComputeHash256Array([]byte("1")), Value: codec.Bytes("3")},
+		{Key: codec.Bytes("b"), PrevHash: hashing.ComputeHash256Array([]byte("2")), Value: codec.Bytes{}, Deleted: true},
+		{Key: codec.Bytes("c"), Value: codec.Bytes("4")},
+	}, bj.Changes)
+	bj, err = j.Get(1)
+	require.NoError(err)
+	require.Len(bj.Changes, 2)
+
+	// A block that changes nothing has an empty journal.
+	accept(j, 4)
+	bj, err = j.Get(3)
+	require.NoError(err)
+	require.Empty(bj.Changes)
+
+	// Journals leave the window.
+	accept(j, 5)
+	_, err = j.Get(4)
+	require.NoError(err)
+	_, err = j.Get(2)
+	require.ErrorIs(err, ErrJournalUnavailable)
+}
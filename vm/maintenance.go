@@ -0,0 +1,175 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/event"
+	"github.com/ava-labs/hypersdk/fees"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/ava-labs/hypersdk/vm"
+)
+
+// maintenanceSubmitTimeout bounds the signing and submission of the
+// maintenance transactions due after a block.
+const maintenanceSubmitTimeout = 10 * time.Second
+
+var (
+	_ event.SubscriptionFactory[*chain.ExecutedBlock] = (*maintenance)(nil)
+	_ event.Subscription[*chain.ExecutedBlock]        = (*maintenance)(nil)
+)
+
+// maintenance is the internal transaction source of a node holding the
+// maintenance key. It signs maintenance actions as they fall due and hands
+// them to the VM directly, so they skip the screened public APIs. They are
+// verified by every node like any other transaction.
+//
+// The hypersdk VM has no hook into block building, so the transactions
+// still go through the node's mempool.
+type maintenance struct {
+	vm      *vm.VM
+	log     logging.Logger
+	factory chain.AuthFactory
+	address codec.Address
+
+	compactionInterval uint64
+	historyWindow      uint64
+
+	// stakers are the actors of the stakes seen since the node started.
+	stakers set.Set[codec.Address]
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newMaintenance(v *vm.VM, config Config) (*maintenance, error) {
+	b, err := hex.DecodeString(config.MaintenanceKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maintenanceKey: %w", err)
+	}
+	if len(b) != ed25519.PrivateKeyLen {
+		return nil, fmt.Errorf("invalid maintenanceKey: %d bytes", len(b))
+	}
+	priv := ed25519.PrivateKey(b)
+	ctx, cancel := context.WithCancel(context.Background())
+	return &maintenance{
+		vm:                 v,
+		log:                v.Logger(),
+		factory:            auth.NewED25519Factory(priv),
+		address:            auth.NewED25519Address(priv.PublicKey()),
+		compactionInterval: config.CompactionInterval,
+		historyWindow:      config.HistoryWindow,
+		stakers:            set.Set[codec.Address]{},
+		ctx:                ctx,
+		cancel:             cancel,
+	}, nil
+}
+
+func (m *maintenance) New() (event.Subscription[*chain.ExecutedBlock], error) {
+	return m, nil
+}
+
+func (m *maintenance) Accept(blk *chain.ExecutedBlock) error {
+	r := m.vm.Rules(blk.Block.Tmstmp)
+	if actions.MaintenanceRules(r) != m.address {
+		// The key is not the maintenance address of these rules.
+		return nil
+	}
+	for i, tx := range blk.Block.Txs {
+		if !blk.Results[i].Success {
+			continue
+		}
+		for _, action := range tx.Actions {
+			if _, ok := action.(*actions.Stake); ok {
+				m.stakers.Add(tx.Auth.Actor())
+			}
+		}
+	}
+	if due := m.due(r, blk.Block.Hght); len(due) > 0 {
+		go m.submit(due)
+	}
+	return nil
+}
+
+// due returns the maintenance actions to run after the block at [height].
+func (m *maintenance) due(r chain.Rules, height uint64) []chain.Action {
+	var due []chain.Action
+	if actions.StakingEpoch(r, height+1) > actions.StakingEpoch(r, height) {
+		stakers := m.stakers.List()
+		for len(stakers) > 0 {
+			n := min(len(stakers), actions.MaxEpochStakers)
+			due = append(due, &actions.ProcessEpoch{Stakers: stakers[:n]})
+			stakers = stakers[n:]
+		}
+	}
+	if m.compactionInterval > 0 && height%m.compactionInterval == 0 && height > m.historyWindow {
+		due = append(due, &actions.MarkCompaction{Height: height - m.historyWindow})
+	}
+	return due
+}
+
+// submit signs a transaction for each of [due] and submits them to the VM.
+func (m *maintenance) submit(due []chain.Action) {
+	ctx, cancel := context.WithTimeout(m.ctx, maintenanceSubmitTimeout)
+	defer cancel()
+
+	txs := make([]*chain.Transaction, 0, len(due))
+	for _, action := range due {
+		tx, err := m.sign(ctx, action)
+		if err != nil {
+			m.log.Warn("failed to sign maintenance transaction", zap.Error(err))
+			return
+		}
+		txs = append(txs, tx)
+	}
+	for i, err := range m.vm.Submit(ctx, true, txs) {
+		if err != nil {
+			m.log.Warn("failed to submit maintenance transaction",
+				zap.Stringer("txID", txs[i].ID()),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func (m *maintenance) sign(ctx context.Context, action chain.Action) (*chain.Transaction, error) {
+	unitPrices, err := m.vm.UnitPrices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixMilli()
+	r := m.vm.Rules(now)
+	units, err := chain.EstimateUnits(r, []chain.Action{action}, m.factory)
+	if err != nil {
+		return nil, err
+	}
+	maxFee, err := fees.MulSum(unitPrices, units)
+	if err != nil {
+		return nil, err
+	}
+	tx := chain.NewTx(&chain.Base{
+		Timestamp: utils.UnixRMilli(now, r.GetValidityWindow()),
+		ChainID:   r.GetChainID(),
+		MaxFee:    maxFee,
+	}, []chain.Action{action})
+	return tx.Sign(m.factory, m.vm.ActionCodec(), m.vm.AuthCodec())
+}
+
+func (m *maintenance) Close() error {
+	m.cancel()
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/consts"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/abi"
+	"github.com/ava-labs/hypersdk/api"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/fees"
+	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// metadataVM serves the chain metadata of an in-memory state.
+type metadataVM struct {
+	api.VM
+	chainID ids.ID
+	rules   *Rules
+	store   *chaintest.InMemoryStore
+}
+
+func (v metadataVM) ChainID() ids.ID                            { return v.chainID }
+func (metadataVM) NetworkID() uint32                            { return 5 }
+func (metadataVM) SubnetID() ids.ID                             { return ids.Empty }
+func (metadataVM) Tracer() trace.Tracer                         { return trace.Noop }
+func (metadataVM) ActionCodec() *codec.TypeParser[chain.Action] { return ActionParser }
+func (metadataVM) OutputCodec() *codec.TypeParser[codec.Typed]  { return OutputParser }
+func (metadataVM) AuthCodec() *codec.TypeParser[chain.Auth]     { return AuthParser }
+func (v metadataVM) Rules(int64) chain.Rules                    { return v.rules }
+func (metadataVM) UnitPrices(context.Context) (fees.Dimensions, error) {
+	return fees.Dimensions{1, 2, 3, 4, 5}, nil
+}
+
+func (v metadataVM) ImmutableState(context.Context) (state.Immutable, error) {
+	return v.store, nil
+}
+
+func (v metadataVM) ReadState(ctx context.Context, keys [][]byte) ([][]byte, []error) {
+	values := make([][]byte, len(keys))
+	errs := make([]error, len(keys))
+	for i, k := range keys {
+		values[i], errs[i] = v.store.GetValue(ctx, k)
+	}
+	return values, errs
+}
+
+func TestChainMetadata(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	rules := newRules()
+	rules.Rules = genesis.NewDefaultRules()
+	v := metadataVM{chainID: ids.GenerateTestID(), rules: rules, store: chaintest.NewInMemoryStore()}
+	j := &JSONRPCServer{vm: v}
+	req := httptest.NewRequest("POST", "/", nil)
+
+	reply := new(ChainMetadataReply)
+	require.NoError(j.ChainMetadata(req, nil, reply))
+	require.Equal(uint32(5), reply.NetworkID)
+	require.Equal(v.chainID, reply.ChainID)
+	require.Equal(consts.HRP, reply.HRP)
+	require.Equal(consts.Symbol, reply.Symbol)
+	require.Equal(uint8(consts.Decimals), reply.Decimals)
+	require.Equal(consts.Version.String(), reply.Version)
+
+	// Every registered action is described, so wallets can encode them.
+	require.Len(reply.ABI.Actions, len(ActionParser.GetRegisteredTypes()))
+	require.True(slices.ContainsFunc(reply.ABI.Actions, func(a abi.TypedStruct) bool {
+		return a.Name == "Transfer" && a.ID == consts.TransferID
+	}))
+
+	require.Equal(fees.Dimensions{1, 2, 3, 4, 5}, reply.Fees.UnitPrices)
+	require.Equal(rules.GetValidityWindow(), reply.Fees.ValidityWindow)
+	require.Equal(int(actions.MaxMemoSize), reply.Fees.MaxMemoSize)
+	require.Equal(uint64(actions.MemoBytesPerComputeUnit), reply.Fees.MemoBytesPerComputeUnit)
+	require.Equal(JSONRPCEndpoint, reply.Capabilities.Endpoints[consts.Name])
+	require.Contains(reply.Capabilities.Methods, "chainMetadata")
+	require.Contains(reply.Capabilities.Methods, "balance")
+
+	// The memo size is the one governance set, if it set one.
+	require.NoError(storage.SetConfigUint64(ctx, v.store, actions.MaxMemoSizeRule, 512))
+	reply = new(ChainMetadataReply)
+	require.NoError(j.ChainMetadata(req, nil, reply))
+	require.Equal(512, reply.Fees.MaxMemoSize)
+}
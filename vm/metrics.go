@@ -0,0 +1,149 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/ava-labs/hypersdk/api"
+)
+
+const MetricsEndpoint = "/morpheusmetrics"
+
+var _ api.HandlerFactory[api.VM] = (*metricsHandlerFactory)(nil)
+
+type metrics struct {
+	registry *prometheus.Registry
+
+	historyWindow           prometheus.Gauge
+	historyBytes            prometheus.Gauge
+	historicalReads         prometheus.Counter
+	historicalReadsRejected prometheus.Counter
+
+	readChunks *prometheus.HistogramVec
+	slowReads  *prometheus.CounterVec
+
+	stateKeyAccesses *prometheus.CounterVec
+
+	actionExecutions    *prometheus.CounterVec
+	actionErrors        *prometheus.CounterVec
+	storageReadSeconds  *prometheus.HistogramVec
+	storageWriteSeconds *prometheus.HistogramVec
+
+	txsAwaitingInclusion prometheus.Gauge
+	expiredTxs           prometheus.Counter
+	txExpiryMargin       prometheus.Histogram
+}
+
+func newMetrics() (*metrics, error) {
+	r := prometheus.NewRegistry()
+	m := &metrics{
+		registry: r,
+		historyWindow: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "history_window",
+			Help:      "number of recent heights served by height-pinned reads",
+		}),
+		historyBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "history_bytes",
+			Help:      "upper bound of the bytes of state changes retained for the history window",
+		}),
+		historicalReads: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "historical_reads",
+			Help:      "number of height-pinned reads served from state history",
+		}),
+		historicalReadsRejected: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "historical_reads_rejected",
+			Help:      "number of height-pinned reads outside of the history window",
+		}),
+		readChunks: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "storage_read_chunks",
+			Help:      "chunks read by each storage get, by record type",
+			Buckets:   []float64{1, 2, 4, 8, 16, 32, 64},
+		}, []string{"prefix"}),
+		slowReads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "storage_slow_reads",
+			Help:      "number of storage gets at or above the slow read threshold, by record type",
+		}, []string{"prefix"}),
+		stateKeyAccesses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "state_key_accesses",
+			Help:      "number of state keys declared by accepted transactions, by record type and permission",
+		}, []string{"prefix", "access"}),
+		actionExecutions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "action_executions",
+			Help:      "number of actions run by accepted transactions, by action type and outcome",
+		}, []string{"action", "outcome"}),
+		actionErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "action_errors",
+			Help:      "number of failed actions in accepted transactions, by action type and error kind",
+		}, []string{"action", "error"}),
+		storageReadSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "storage_read_seconds",
+			Help:      "latency of each storage get, by record type",
+			Buckets:   prometheus.ExponentialBuckets(1e-6, 4, 10),
+		}, []string{"prefix"}),
+		storageWriteSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "storage_write_seconds",
+			Help:      "latency of each storage insert or removal, by record type",
+			Buckets:   prometheus.ExponentialBuckets(1e-6, 4, 10),
+		}, []string{"prefix"}),
+		txsAwaitingInclusion: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "txs_awaiting_inclusion",
+			Help:      "number of transactions submitted to this node that no block has included yet",
+		}),
+		expiredTxs: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "expired_txs",
+			Help:      "number of transactions submitted to this node that expired before a block included them",
+		}),
+		txExpiryMargin: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "tx_expiry_margin_seconds",
+			Help:      "seconds left before expiry when a block included a transaction submitted to this node",
+			Buckets:   []float64{1, 2, 5, 10, 20, 30, 60},
+		}),
+	}
+	errs := wrappers.Errs{}
+	errs.Add(
+		r.Register(m.historyWindow),
+		r.Register(m.historyBytes),
+		r.Register(m.historicalReads),
+		r.Register(m.historicalReadsRejected),
+		r.Register(m.readChunks),
+		r.Register(m.slowReads),
+		r.Register(m.stateKeyAccesses),
+		r.Register(m.actionExecutions),
+		r.Register(m.actionErrors),
+		r.Register(m.storageReadSeconds),
+		r.Register(m.storageWriteSeconds),
+		r.Register(m.txsAwaitingInclusion),
+		r.Register(m.expiredTxs),
+		r.Register(m.txExpiryMargin),
+	)
+	return m, errs.Err
+}
+
+type metricsHandlerFactory struct {
+	metrics *metrics
+}
+
+func (f metricsHandlerFactory) New(api.VM) (api.Handler, error) {
+	return api.Handler{
+		Path:    MetricsEndpoint,
+		Handler: promhttp.HandlerFor(f.metrics.registry, promhttp.HandlerOpts{}),
+	}, nil
+}
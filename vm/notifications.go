@@ -0,0 +1,108 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// Notification is an event of [Address] that it opted into pushing to
+// [Target] with its [storage.NotificationPrefs].
+type Notification struct {
+	Address codec.Address `json:"address"`
+	Target  ids.ID        `json:"target"`
+	// Kind is the storage.Notify* kind of the event.
+	Kind uint64 `json:"kind"`
+	Log  *Log   `json:"log"`
+}
+
+// notificationKind returns the storage.Notify* kind of [e], or zero if no
+// preference covers it.
+func notificationKind(e actions.Event) uint64 {
+	switch e := e.(type) {
+	case *actions.TransferEvent:
+		if e.Asset == storage.NativeAsset {
+			return storage.NotifyTransfer
+		}
+		return storage.NotifyAssetTransfer
+	case *actions.OwnershipEvent:
+		switch {
+		case e.From == codec.EmptyAddress:
+			return storage.NotifyMint
+		case e.To == codec.EmptyAddress:
+			return storage.NotifyBurn
+		default:
+			return storage.NotifyAssetTransfer
+		}
+	default:
+		return 0
+	}
+}
+
+// notifications returns the events of [blk] that an address they involve
+// opted into pushing to one of [targets], under its preferences after the
+// block.
+func (s *stream) notifications(
+	ctx context.Context,
+	blk *chain.ExecutedBlock,
+	targets set.Set[ids.ID],
+) ([]*Notification, error) {
+	if targets.Len() == 0 {
+		return nil, nil
+	}
+	var (
+		read          = s.stateAt(blk.Block.Hght)
+		prefs         = map[codec.Address]storage.NotificationPrefs{}
+		notifications []*Notification
+	)
+	err := blockEvents(blk, func(tx *chain.Transaction, action int, e actions.Event) error {
+		kind := notificationKind(e)
+		if kind == 0 {
+			return nil
+		}
+		var l *Log
+		notified := set.Set[codec.Address]{}
+		for _, addr := range e.Addresses() {
+			if notified.Contains(addr) {
+				continue
+			}
+			p, ok := prefs[addr]
+			if !ok {
+				var err error
+				p, err = storage.GetNotificationPrefsFromState(ctx, read, addr)
+				if err != nil {
+					return err
+				}
+				prefs[addr] = p
+			}
+			if !p.Wants(kind) || !targets.Contains(p.Target) {
+				continue
+			}
+			if l == nil {
+				var err error
+				l, err = newLog(blk.Block.Hght, tx, action, e)
+				if err != nil {
+					return err
+				}
+			}
+			notified.Add(addr)
+			notifications = append(notifications, &Notification{
+				Address: addr,
+				Target:  p.Target,
+				Kind:    kind,
+				Log:     l,
+			})
+		}
+		return nil
+	})
+	return notifications, err
+}
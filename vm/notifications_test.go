@@ -0,0 +1,120 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+func TestStreamNotifications(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	signer := &auth.ED25519{Signer: priv.PublicKey()}
+	sender := signer.Actor()
+	to := codectest.NewRandomAddress()
+	other := codectest.NewRandomAddress()
+	webhook := ids.GenerateTestID()
+	pubkey := ids.GenerateTestID()
+	asset := ids.GenerateTestID()
+
+	// The sender wants its native transfers and mints pushed to [webhook],
+	// the recipient its asset transfers to [pubkey].
+	store := chaintest.NewInMemoryStore()
+	require.NoError(store.Insert(ctx, chain.HeightKey(storage.HeightKey()), []byte{0, 0, 0, 0, 0, 0, 0, 1}))
+	require.NoError(storage.SetNotificationPrefs(ctx, store, sender, storage.NotificationPrefs{
+		Target:    webhook,
+		EventMask: storage.NotifyTransfer | storage.NotifyMint,
+	}))
+	require.NoError(storage.SetNotificationPrefs(ctx, store, to, storage.NotificationPrefs{
+		Target:    pubkey,
+		EventMask: storage.NotifyAssetTransfer,
+	}))
+	s := &stream{readState: func(ctx context.Context, keys [][]byte) ([][]byte, []error) {
+		values := make([][]byte, len(keys))
+		errs := make([]error, len(keys))
+		for i, k := range keys {
+			values[i], errs[i] = store.GetValue(ctx, k)
+		}
+		return values, errs
+	}}
+
+	tx := func(action chain.Action) *chain.Transaction {
+		return &chain.Transaction{Actions: []chain.Action{action}, Auth: signer}
+	}
+	result := func(output codec.Typed, success bool) *chain.Result {
+		b, err := chain.MarshalTyped(output)
+		require.NoError(err)
+		return &chain.Result{Success: success, Outputs: [][]byte{b}}
+	}
+	blk := &chain.ExecutedBlock{
+		Block: &chain.StatelessBlock{
+			Hght: 1,
+			Txs: []*chain.Transaction{
+				tx(&actions.Transfer{To: to, Value: 5}),
+				tx(&actions.MintAsset{Asset: asset}),
+				tx(&actions.AssetTransfer{Recipient: to, Asset: asset}),
+				tx(&actions.Transfer{To: other, Value: 7}),
+				tx(&actions.BurnAsset{Asset: asset}),
+			},
+		},
+		Results: []*chain.Result{
+			result(&actions.TransferResult{}, true),
+			result(&actions.MintAssetResult{Asset: asset, Owner: sender}, true),
+			result(&actions.AssetTransferResult{OldOwner: sender, NewOwner: to}, true),
+			// Failed actions push nothing.
+			result(&actions.TransferResult{}, false),
+			// Nobody opted into burns.
+			result(&actions.BurnAssetResult{PreviousOwner: to}, true),
+		},
+	}
+
+	type notification struct {
+		address codec.Address
+		target  ids.ID
+		kind    uint64
+		tx      ids.ID
+	}
+	notified := func(targets ...ids.ID) []notification {
+		ns, err := s.notifications(ctx, blk, set.Of(targets...))
+		require.NoError(err)
+		var got []notification
+		for _, n := range ns {
+			got = append(got, notification{n.Address, n.Target, n.Kind, n.Log.TxID})
+		}
+		return got
+	}
+	require.Equal([]notification{
+		{sender, webhook, storage.NotifyTransfer, blk.Block.Txs[0].ID()},
+		{sender, webhook, storage.NotifyMint, blk.Block.Txs[1].ID()},
+		{to, pubkey, storage.NotifyAssetTransfer, blk.Block.Txs[2].ID()},
+	}, notified(webhook, pubkey))
+
+	// Only subscribed targets are notified.
+	require.Equal([]notification{
+		{to, pubkey, storage.NotifyAssetTransfer, blk.Block.Txs[2].ID()},
+	}, notified(pubkey))
+	require.Empty(notified(ids.GenerateTestID()))
+	require.Empty(notified())
+
+	// Opting out stops the pushes.
+	require.NoError(storage.SetNotificationPrefs(ctx, store, to, storage.NotificationPrefs{}))
+	require.Empty(notified(pubkey))
+}
@@ -3,26 +3,250 @@
 
 package vm
 
-import "github.com/ava-labs/hypersdk/vm"
+import (
+	"fmt"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/vm"
+)
 
 const Namespace = "controller"
 
 type Config struct {
 	Enabled bool `json:"enabled"`
+
+	// HistoryWindow is how many recent heights can be read with a pinned
+	// height. It should not exceed the VM's stateHistoryLength, which bounds
+	// how many state roots are retained in memory. The memory the window
+	// retains is estimated by the history_bytes metric and the
+	// StateRetention method.
+	HistoryWindow uint64 `json:"historyWindow"`
+
+	// StateMode is "pruned", the default, to serve reads pinned to the
+	// last [HistoryWindow] heights only, or "archival" to also keep every
+	// version of state from when it was enabled, so that any later height
+	// can be read. The archive grows with every block.
+	StateMode string `json:"stateMode"`
+
+	// JournalWindow is how many recent blocks keep a journal of their state
+	// mutations, served by the StateJournal method. Zero disables the journal.
+	JournalWindow uint64 `json:"journalWindow"`
+
+	// UsageWindow is how many recent blocks keep a usage report, served by
+	// the BlockUsage method and sampled by SuggestFee. Zero disables the
+	// reports.
+	UsageWindow uint64 `json:"usageWindow"`
+
+	// TreasuryHistory indexes treasury deposits and spends, served by the
+	// TreasuryHistory method.
+	TreasuryHistory bool `json:"treasuryHistory"`
+
+	// AssetHistory indexes the transfers of every asset, served by the
+	// AssetHistory method.
+	AssetHistory bool `json:"assetHistory"`
+
+	// Activity indexes accepted transactions by the addresses they touched,
+	// served by the GetTx and GetTxsByAddress methods.
+	Activity bool `json:"activity"`
+
+	// EventLog records the events of accepted actions with a bloom per
+	// block, served by the GetLogs method.
+	EventLog bool `json:"eventLog"`
+
+	// HeatMap counts the state keys of accepted transactions by record type
+	// and key bucket, served by the StateHeatMap method and as metrics.
+	HeatMap bool `json:"heatMap"`
+
+	// Stream serves accepted blocks, transaction results and balance
+	// changes over WebSocket at [StreamEndpoint].
+	Stream bool `json:"stream"`
+
+	// BalanceExport serves every native balance through the ExportBalances
+	// method. It reads the whole balance range of state, so it is meant for
+	// the operator's own node and is off by default.
+	BalanceExport bool `json:"balanceExport"`
+
+	// ReadStats records the chunks read by each storage get.
+	ReadStats bool `json:"readStats"`
+
+	// ExecutionStats counts the actions of accepted blocks by type, outcome
+	// and error kind, and times storage reads and writes, as metrics.
+	ExecutionStats bool `json:"executionStats"`
+
+	// Reads of at least SlowReadChunks chunks are counted as slow. A key is
+	// logged each time it has had another SlowReadReports slow reads. Zero
+	// SlowReadChunks disables slow key reporting.
+	SlowReadChunks  uint16 `json:"slowReadChunks"`
+	SlowReadReports int    `json:"slowReadReports"`
+
+	// MaintenanceKey is the hex ed25519 private key of the maintenance
+	// address in the rules. A node holding it submits maintenance
+	// transactions as they fall due. Empty disables maintenance.
+	MaintenanceKey string `json:"maintenanceKey"`
+
+	// AttestationKey is the hex ed25519 private key the node signs
+	// VerifyOwnership attestations with. Empty disables attestations.
+	AttestationKey string `json:"attestationKey"`
+
+	// CompactionInterval is how many blocks apart the maintenance node
+	// marks history older than [HistoryWindow] as compactable. Zero
+	// disables the markers.
+	CompactionInterval uint64 `json:"compactionInterval"`
+
+	// CursorKey is the hex key, of at least 32 bytes, list cursors are
+	// signed with. Nodes behind one load balancer should share it. Empty
+	// picks a random key, so cursors do not survive a restart.
+	CursorKey string `json:"cursorKey"`
 }
 
 func NewDefaultConfig() Config {
 	return Config{
-		Enabled: true,
+		Enabled:         true,
+		HistoryWindow:   256,
+		StateMode:       PrunedStateMode,
+		UsageWindow:     1024,
+		TreasuryHistory: true,
+		AssetHistory:    true,
+		Activity:        true,
+		EventLog:        true,
+		HeatMap:         true,
+		Stream:          true,
+		ReadStats:       true,
+		ExecutionStats:  true,
+		SlowReadChunks:  4,
+		SlowReadReports: 16,
 	}
 }
 
-func With() vm.Option {
+// stateMode returns [StateMode], defaulting to [PrunedStateMode].
+func (c Config) stateMode() string {
+	if c.StateMode == "" {
+		return PrunedStateMode
+	}
+	return c.StateMode
+}
+
+// With registers the MorpheusVM APIs. [upgrades] must be the rule factory
+// the VM was created with, and [expiry] must follow its submissions.
+func With(upgrades *UpgradeFactory, expiry *expiryTracker) vm.Option {
 	return vm.NewOption(Namespace, NewDefaultConfig(), func(v *vm.VM, config Config) error {
 		if !config.Enabled {
 			return nil
 		}
-		vm.WithVMAPIs(jsonRPCServerFactory{})(v)
+		m, err := newMetrics()
+		if err != nil {
+			return err
+		}
+		m.historyWindow.Set(float64(config.HistoryWindow))
+		hu := newHistoryUsage(config.HistoryWindow, m)
+		vm.WithBlockSubscriptions(hu)(v)
+		expiry.start(m, v.Logger())
+		vm.WithBlockSubscriptions(expiry)(v)
+		var a *archive
+		switch config.stateMode() {
+		case PrunedStateMode:
+		case ArchivalStateMode:
+			a, err = newArchive(archivePath(v.DataDir), v, v.Logger())
+			if err != nil {
+				return err
+			}
+			vm.WithBlockSubscriptions(a)(v)
+		default:
+			return fmt.Errorf("%w: %q", ErrUnknownStateMode, config.StateMode)
+		}
+		// Action and storage spans follow the hypersdk trace config, so
+		// they record nothing unless tracing is enabled.
+		actions.SetTracer(v.Tracer())
+		storage.SetTracer(v.Tracer())
+		if config.ReadStats {
+			storage.SetReadRecorder(newReadStats(m, v.Logger(), config))
+		}
+		if config.ExecutionStats {
+			es := newExecutionStats(m)
+			storage.SetLatencyRecorder(es)
+			vm.WithBlockSubscriptions(es)(v)
+		}
+		var j *journal
+		if config.JournalWindow > 0 {
+			j, err = newJournal(journalPath(v.DataDir), v, v.Logger(), config.JournalWindow)
+			if err != nil {
+				return err
+			}
+			vm.WithBlockSubscriptions(j)(v)
+		}
+		var u *usage
+		if config.UsageWindow > 0 {
+			u, err = newUsage(usagePath(v.DataDir), v, v.Logger(), config.UsageWindow)
+			if err != nil {
+				return err
+			}
+			vm.WithBlockSubscriptions(u)(v)
+		}
+		var th *treasuryHistory
+		if config.TreasuryHistory {
+			th, err = newTreasuryHistory(treasuryHistoryPath(v.DataDir), v.Logger())
+			if err != nil {
+				return err
+			}
+			vm.WithBlockSubscriptions(th)(v)
+		}
+		var ah *assetHistory
+		if config.AssetHistory {
+			ah, err = newAssetHistory(assetHistoryPath(v.DataDir), v.Logger())
+			if err != nil {
+				return err
+			}
+			vm.WithBlockSubscriptions(ah)(v)
+		}
+		var act *activity
+		if config.Activity {
+			act, err = newActivity(activityPath(v.DataDir), v.Logger())
+			if err != nil {
+				return err
+			}
+			vm.WithBlockSubscriptions(act)(v)
+		}
+		var el *eventLog
+		if config.EventLog {
+			el, err = newEventLog(eventLogPath(v.DataDir), v.Logger())
+			if err != nil {
+				return err
+			}
+			vm.WithBlockSubscriptions(el)(v)
+		}
+		var at *attester
+		if config.AttestationKey != "" {
+			at, err = newAttester(config.AttestationKey)
+			if err != nil {
+				return err
+			}
+		}
+		cursors, err := newCursorSigner(config.CursorKey)
+		if err != nil {
+			return err
+		}
+		var hm *heatMap
+		if config.HeatMap {
+			hm = newHeatMap(m)
+			vm.WithBlockSubscriptions(hm)(v)
+		}
+		vm.WithVMAPIs(
+			jsonRPCServerFactory{config: config, metrics: m, retained: hu, journal: j, archive: a, usage: u, treasury: th, assets: ah, activity: act, logs: el, heatMap: hm, attester: at, upgrades: upgrades, sessions: newSessionRequests(), cursors: cursors},
+			metricsHandlerFactory{metrics: m},
+		)(v)
+		if config.MaintenanceKey != "" {
+			mt, err := newMaintenance(v, config)
+			if err != nil {
+				return err
+			}
+			vm.WithBlockSubscriptions(mt)(v)
+		}
+		if config.Stream {
+			s := newStream(v, v.Logger(), th, ah, act, cursors, config.BalanceExport)
+			vm.WithBlockSubscriptions(s)(v)
+			vm.WithVMAPIs(streamHandlerFactory{stream: s})(v)
+		}
 		return nil
 	})
 }
@@ -0,0 +1,106 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+// MaxOwnershipAssets bounds the assets one VerifyOwnership call checks.
+const MaxOwnershipAssets = 256
+
+// ownershipDomain starts every signed ownership message, so attestations
+// cannot be replayed as other signed data.
+const ownershipDomain = "morpheusvm ownership attestation"
+
+var (
+	ErrTooManyOwnershipAssets = fmt.Errorf("cannot verify more than %d assets", MaxOwnershipAssets)
+	ErrAttestationUnavailable = errors.New("ownership attestations unavailable")
+	ErrInvalidAttestation     = errors.New("invalid ownership attestation")
+)
+
+// OwnershipAttestation is the node's signature over a [VerifyOwnershipReply]
+// or an [AccountStatement] with its attestation key.
+type OwnershipAttestation struct {
+	Signer    codec.Bytes `json:"signer"`
+	Signature codec.Bytes `json:"signature"`
+}
+
+// OwnershipMessage is the message an [OwnershipAttestation] signs: that at
+// [height] of [chainID], [addr] owned the assets of [assets] whose [owned]
+// entry is true.
+func OwnershipMessage(chainID ids.ID, height uint64, addr codec.Address, assets []ids.ID, owned []bool) ([]byte, error) {
+	if len(assets) != len(owned) {
+		return nil, fmt.Errorf("%w: %d assets and %d results", ErrInvalidAttestation, len(assets), len(owned))
+	}
+	size := len(ownershipDomain) + ids.IDLen + consts.Uint64Len + codec.AddressLen + consts.Uint32Len + len(assets)*(ids.IDLen+consts.BoolLen)
+	p := codec.NewWriter(size, size)
+	p.PackFixedBytes([]byte(ownershipDomain))
+	p.PackID(chainID)
+	p.PackUint64(height)
+	p.PackAddress(addr)
+	p.PackInt(uint32(len(assets)))
+	for i, asset := range assets {
+		p.PackID(asset)
+		p.PackBool(owned[i])
+	}
+	return p.Bytes(), p.Err()
+}
+
+// VerifyOwnershipAttestation checks that [reply] to a VerifyOwnership call
+// for [addr] and [assets] on [chainID] is signed by [signer].
+func VerifyOwnershipAttestation(
+	chainID ids.ID,
+	addr codec.Address,
+	assets []ids.ID,
+	reply *VerifyOwnershipReply,
+	signer ed25519.PublicKey,
+) error {
+	a := reply.Attestation
+	if a == nil || len(a.Signer) != ed25519.PublicKeyLen || len(a.Signature) != ed25519.SignatureLen {
+		return fmt.Errorf("%w: missing or malformed", ErrInvalidAttestation)
+	}
+	if ed25519.PublicKey(a.Signer) != signer {
+		return fmt.Errorf("%w: signed by another key", ErrInvalidAttestation)
+	}
+	msg, err := OwnershipMessage(chainID, reply.Height, addr, assets, reply.Owned)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(msg, signer, ed25519.Signature(a.Signature)) {
+		return fmt.Errorf("%w: bad signature", ErrInvalidAttestation)
+	}
+	return nil
+}
+
+// attester signs ownership attestations with the node's attestation key.
+type attester struct {
+	priv ed25519.PrivateKey
+	pub  ed25519.PublicKey
+}
+
+func newAttester(key string) (*attester, error) {
+	b, err := hex.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attestationKey: %w", err)
+	}
+	if len(b) != ed25519.PrivateKeyLen {
+		return nil, fmt.Errorf("invalid attestationKey: %d bytes", len(b))
+	}
+	priv := ed25519.PrivateKey(b)
+	return &attester{priv: priv, pub: priv.PublicKey()}, nil
+}
+
+func (a *attester) attest(msg []byte) *OwnershipAttestation {
+	sig := ed25519.Sign(msg, a.priv)
+	return &OwnershipAttestation{Signer: a.pub[:], Signature: sig[:]}
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+func TestOwnershipAttestation(t *testing.T) {
+	require := require.New(t)
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	a, err := newAttester(hex.EncodeToString(priv[:]))
+	require.NoError(err)
+
+	chainID := ids.GenerateTestID()
+	addr := codectest.NewRandomAddress()
+	assets := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+	reply := &VerifyOwnershipReply{Owned: []bool{true, false}, Height: 7}
+	msg, err := OwnershipMessage(chainID, reply.Height, addr, assets, reply.Owned)
+	require.NoError(err)
+	reply.Attestation = a.attest(msg)
+
+	require.NoError(VerifyOwnershipAttestation(chainID, addr, assets, reply, priv.PublicKey()))
+	require.ErrorIs(VerifyOwnershipAttestation(ids.GenerateTestID(), addr, assets, reply, priv.PublicKey()), ErrInvalidAttestation)
+	require.ErrorIs(VerifyOwnershipAttestation(chainID, addr, assets[:1], reply, priv.PublicKey()), ErrInvalidAttestation)
+
+	other, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	require.ErrorIs(VerifyOwnershipAttestation(chainID, addr, assets, reply, other.PublicKey()), ErrInvalidAttestation)
+
+	reply.Owned[1] = true
+	require.ErrorIs(VerifyOwnershipAttestation(chainID, addr, assets, reply, priv.PublicKey()), ErrInvalidAttestation)
+
+	_, err = newAttester("00")
+	require.ErrorContains(err, "invalid attestationKey")
+}
@@ -0,0 +1,633 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+var (
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrCursorExpired     = errors.New("cursor expired")
+	ErrUnknownListMethod = errors.New("unknown list method")
+)
+
+const (
+	// cursorVersion starts every encoded cursor, so the format can change.
+	cursorVersion = 0
+	// cursorKeyLen is the size of generated cursor keys.
+	cursorKeyLen = 32
+	// cursorFixedSize is the size of a cursor without its start.
+	cursorFixedSize = 1 + consts.Uint64Len + ids.IDLen + sha256.Size
+)
+
+// PageArgs selects one page of a list method.
+type PageArgs struct {
+	// Cursor is the [Page.Cursor] of the previous reply, or empty for the
+	// first page. Cursors are opaque and signed by the node. They are only
+	// valid for the method and filter that returned them.
+	Cursor string `json:"cursor"`
+	// Limit is the page size. Zero, or a value above the method's maximum,
+	// selects the maximum.
+	Limit int `json:"limit"`
+}
+
+// Page is the pagination envelope of every list reply.
+type Page struct {
+	// Cursor fetches the following page. It is empty when [HasMore] is
+	// false.
+	Cursor string `json:"cursor"`
+	// Limit is the page size that was applied.
+	Limit   int  `json:"limit"`
+	HasMore bool `json:"hasMore"`
+	// Height is the state every page of a state listing is read at, which
+	// is the last accepted one when its first page was served. Writes
+	// accepted since do not move items between pages. It is zero for
+	// listings of local indexes, which are append-only.
+	Height uint64 `json:"height"`
+}
+
+func (a PageArgs) limit(maxLimit int) int {
+	if a.Limit <= 0 || a.Limit > maxLimit {
+		return maxLimit
+	}
+	return a.Limit
+}
+
+// cursor is the decoded form of [Page.Cursor].
+type cursor struct {
+	// start is the position of the first item of the page within the
+	// listing, such as an asset ID.
+	start []byte
+	// height and root pin the state of state listings. Both are zero for
+	// listings of local indexes.
+	height uint64
+	root   ids.ID
+}
+
+// cursorSigner signs cursors with a key of the node, so clients cannot
+// start state scans at positions the node did not hand out.
+type cursorSigner struct {
+	key []byte
+}
+
+// newCursorSigner returns a signer with the hex [key], or with a random key
+// if it is empty.
+func newCursorSigner(key string) (*cursorSigner, error) {
+	if key == "" {
+		b := make([]byte, cursorKeyLen)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		return &cursorSigner{key: b}, nil
+	}
+	b, err := hex.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursorKey: %w", err)
+	}
+	if len(b) < cursorKeyLen {
+		return nil, fmt.Errorf("invalid cursorKey: %d bytes", len(b))
+	}
+	return &cursorSigner{key: b}, nil
+}
+
+// listScope binds cursors to the list [method] and its [filter], such as
+// the owner of assetsByOwner.
+func listScope(method string, filter ...[]byte) []byte {
+	scope := append([]byte(method), 0)
+	for _, f := range filter {
+		scope = append(scope, f...)
+	}
+	return scope
+}
+
+func (s *cursorSigner) mac(scope []byte, payload []byte) []byte {
+	h := hmac.New(sha256.New, s.key)
+	_, _ = h.Write(scope)
+	_, _ = h.Write(payload)
+	return h.Sum(nil)
+}
+
+// encode returns the cursor of [c] for listings of [scope], laid out as
+//
+//	[version] + [height] + [root] + [start] + [mac]
+func (s *cursorSigner) encode(scope []byte, c cursor) string {
+	payload := make([]byte, 0, cursorFixedSize+len(c.start))
+	payload = append(payload, cursorVersion)
+	payload = binary.BigEndian.AppendUint64(payload, c.height)
+	payload = append(payload, c.root[:]...)
+	payload = append(payload, c.start...)
+	return base64.RawURLEncoding.EncodeToString(append(payload, s.mac(scope, payload)...))
+}
+
+// decode returns the cursor [encoded] if it was signed for [scope]. An empty
+// cursor decodes to the zero cursor.
+func (s *cursorSigner) decode(scope []byte, encoded string) (cursor, error) {
+	if encoded == "" {
+		return cursor{}, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if len(b) < cursorFixedSize || b[0] != cursorVersion {
+		return cursor{}, ErrInvalidCursor
+	}
+	payload, mac := b[:len(b)-sha256.Size], b[len(b)-sha256.Size:]
+	if !hmac.Equal(mac, s.mac(scope, payload)) {
+		return cursor{}, fmt.Errorf("%w: bad signature", ErrInvalidCursor)
+	}
+	return cursor{
+		height: binary.BigEndian.Uint64(payload[1:]),
+		root:   ids.ID(payload[1+consts.Uint64Len:]),
+		start:  payload[1+consts.Uint64Len+ids.IDLen:],
+	}, nil
+}
+
+func idStart(c cursor) (ids.ID, error) {
+	switch len(c.start) {
+	case 0:
+		return ids.Empty, nil
+	case ids.IDLen:
+		return ids.ID(c.start), nil
+	default:
+		return ids.Empty, ErrInvalidCursor
+	}
+}
+
+func addressStart(c cursor) (codec.Address, error) {
+	switch len(c.start) {
+	case 0:
+		return codec.EmptyAddress, nil
+	case codec.AddressLen:
+		return codec.Address(c.start), nil
+	default:
+		return codec.EmptyAddress, ErrInvalidCursor
+	}
+}
+
+func sequenceStart(c cursor) (uint64, error) {
+	switch len(c.start) {
+	case 0:
+		return 0, nil
+	case consts.Uint64Len:
+		if n := binary.BigEndian.Uint64(c.start); n != 0 {
+			return n, nil
+		}
+	}
+	return 0, ErrInvalidCursor
+}
+
+// lists serves the list methods, for both JSON-RPC and [StreamQuery].
+type lists struct {
+	history  historicalState
+	treasury *treasuryHistory
+	assets   *assetHistory
+	activity *activity
+	cursors  *cursorSigner
+	// balanceExport serves exportBalances.
+	balanceExport bool
+}
+
+// idIterator is the shape of the state iterators listed by ID or address.
+type idIterator interface {
+	Next() bool
+	Error() error
+	Release()
+}
+
+// idPage collects up to [limit] items from [it]. When more follow, it also
+// returns the position of the next one, taken from [next].
+func idPage[T any](it idIterator, limit int, item func() T, next func() []byte) ([]T, []byte, error) {
+	defer it.Release()
+
+	items := []T{}
+	for it.Next() {
+		if len(items) == limit {
+			return items, next(), it.Error()
+		}
+		items = append(items, item())
+	}
+	return items, nil, it.Error()
+}
+
+// pin returns the state listings of [scope] read for [args], with the
+// cursor it carries. The first page pins the last accepted state.
+func (l *lists) pin(ctx context.Context, scope []byte, args PageArgs, limit int) (*pinnedState, cursor, error) {
+	if l.history == nil {
+		return nil, cursor{}, ErrStateIterationUnavailable
+	}
+	c, err := l.cursors.decode(scope, args.Cursor)
+	if err != nil {
+		return nil, cursor{}, err
+	}
+	db, err := l.history.State()
+	if err != nil {
+		return nil, cursor{}, err
+	}
+	// Each read fetches one page and the item after it, unless a listing
+	// skips items.
+	p := &pinnedState{ctx: ctx, db: db, root: c.root, chunk: limit + 1}
+	if args.Cursor != "" {
+		return p, c, nil
+	}
+	p.root, err = db.GetMerkleRoot(ctx)
+	if err != nil {
+		return nil, cursor{}, err
+	}
+	v, err := p.get(chain.HeightKey(storage.HeightKey()))
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+	case err != nil:
+		return nil, cursor{}, err
+	default:
+		if c.height, err = database.ParseUInt64(v); err != nil {
+			return nil, cursor{}, err
+		}
+	}
+	c.root = p.root
+	return p, c, nil
+}
+
+// statePage is the page of a state listing pinned by [c], with the cursor of
+// the item at [next], if any.
+func (l *lists) statePage(scope []byte, c cursor, limit int, next []byte) Page {
+	page := Page{Limit: limit, Height: c.height}
+	if next != nil {
+		c.start = next
+		page.Cursor, page.HasMore = l.cursors.encode(scope, c), true
+	}
+	return page
+}
+
+// indexPage is the page of an index listing, with the cursor of the item at
+// [next], if any.
+func (l *lists) indexPage(scope []byte, limit int, next []byte) Page {
+	page := Page{Limit: limit}
+	if next != nil {
+		page.Cursor, page.HasMore = l.cursors.encode(scope, cursor{start: next}), true
+	}
+	return page
+}
+
+func (l *lists) assetsByOwner(ctx context.Context, owner codec.Address, args PageArgs) ([]ids.ID, Page, error) {
+	scope := listScope("assetsByOwner", owner[:])
+	limit := args.limit(MaxAssetsByOwnerPage)
+	db, c, err := l.pin(ctx, scope, args, limit)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	start, err := idStart(c)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	it := storage.GetAssetsByOwner(db, owner, start)
+	items, next, err := idPage(it, limit, it.Asset, func() []byte {
+		id := it.Asset()
+		return id[:]
+	})
+	return items, l.statePage(scope, c, limit, next), err
+}
+
+func (l *lists) vestings(ctx context.Context, beneficiary codec.Address, args PageArgs) ([]PendingVesting, Page, error) {
+	scope := listScope("vestings", beneficiary[:])
+	limit := args.limit(MaxVestingsPage)
+	db, c, err := l.pin(ctx, scope, args, limit)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	start, err := idStart(c)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	it := storage.GetVestingsByBeneficiary(db, beneficiary, start)
+	items, next, err := idPage(it, limit, func() PendingVesting {
+		return PendingVesting{ID: it.ID(), Vesting: *it.Vesting()}
+	}, func() []byte {
+		id := it.ID()
+		return id[:]
+	})
+	return items, l.statePage(scope, c, limit, next), err
+}
+
+func (l *lists) activeSessions(ctx context.Context, owner codec.Address, args PageArgs) ([]ActiveSession, Page, error) {
+	scope := listScope("activeSessions", owner[:])
+	limit := args.limit(MaxSessionsPage)
+	db, c, err := l.pin(ctx, scope, args, limit)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	start, err := idStart(c)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	it := &activeSessions{Sessions: storage.GetSessionsByOwner(db, owner, start), now: time.Now().UnixMilli()}
+	items, next, err := idPage(it, limit, func() ActiveSession {
+		return ActiveSession{ID: it.ID(), Session: *it.Session()}
+	}, func() []byte {
+		id := it.ID()
+		return id[:]
+	})
+	return items, l.statePage(scope, c, limit, next), err
+}
+
+func (l *lists) orders(ctx context.Context, sellAsset ids.ID, buyAsset ids.ID, args PageArgs) ([]OpenOrder, Page, error) {
+	scope := listScope("orders", sellAsset[:], buyAsset[:])
+	limit := args.limit(MaxOrdersPage)
+	db, c, err := l.pin(ctx, scope, args, limit)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	start, err := idStart(c)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	it := storage.GetOrdersByPair(db, sellAsset, buyAsset, start)
+	items, next, err := idPage(it, limit, func() OpenOrder {
+		return OpenOrder{ID: it.ID(), Order: *it.Order()}
+	}, func() []byte {
+		id := it.ID()
+		return id[:]
+	})
+	return items, l.statePage(scope, c, limit, next), err
+}
+
+func (l *lists) liveAuctions(ctx context.Context, includeEnded bool, args PageArgs) ([]LiveAuction, Page, error) {
+	scope := listScope("liveAuctions")
+	limit := args.limit(MaxAuctionsPage)
+	db, c, err := l.pin(ctx, scope, args, limit)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	start, err := idStart(c)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	it := &liveAuctions{Auctions: storage.GetAuctions(db, start), now: time.Now().UnixMilli(), includeEnded: includeEnded}
+	items, next, err := idPage(it, limit, func() LiveAuction {
+		return LiveAuction{Asset: it.Asset(), Auction: *it.Auction()}
+	}, func() []byte {
+		id := it.Asset()
+		return id[:]
+	})
+	return items, l.statePage(scope, c, limit, next), err
+}
+
+func (l *lists) appAssets(ctx context.Context, app ids.ID, args PageArgs) ([]ids.ID, Page, error) {
+	scope := listScope("appAssets", app[:])
+	limit := args.limit(MaxAppAssetsPage)
+	db, c, err := l.pin(ctx, scope, args, limit)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	start, err := idStart(c)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	it := &existingAssets{AppEntries: storage.GetAssetsByApp(db, app, start), db: db}
+	items, next, err := idPage(it, limit, it.Asset, func() []byte {
+		id := it.Asset()
+		return id[:]
+	})
+	return items, l.statePage(scope, c, limit, next), err
+}
+
+func (l *lists) appCreators(ctx context.Context, app ids.ID, args PageArgs) ([]codec.Address, Page, error) {
+	scope := listScope("appCreators", app[:])
+	limit := args.limit(MaxAppCreatorsPage)
+	db, c, err := l.pin(ctx, scope, args, limit)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	start, err := addressStart(c)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	it := storage.GetAppCreators(db, app, start)
+	items, next, err := idPage(it, limit, it.Creator, func() []byte {
+		addr := it.Creator()
+		return addr[:]
+	})
+	return items, l.statePage(scope, c, limit, next), err
+}
+
+func (l *lists) exportBalances(ctx context.Context, args PageArgs) ([]AccountBalance, Page, error) {
+	if !l.balanceExport {
+		return nil, Page{}, fmt.Errorf("%w: disabled", ErrBalanceExportUnavailable)
+	}
+	scope := listScope("exportBalances")
+	limit := args.limit(MaxExportBalancesPage)
+	db, c, err := l.pin(ctx, scope, args, limit)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	start, err := addressStart(c)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	it := storage.IterateBalances(db, start)
+	items, next, err := idPage(it, limit, func() AccountBalance {
+		return AccountBalance{Address: it.Address(), Balance: it.Balance()}
+	}, func() []byte {
+		addr := it.Address()
+		return addr[:]
+	})
+	return items, l.statePage(scope, c, limit, next), err
+}
+
+func (l *lists) treasuryHistory(args PageArgs) ([]*TreasuryMovement, Page, error) {
+	if l.treasury == nil {
+		return nil, Page{}, fmt.Errorf("%w: index disabled", ErrTreasuryHistoryUnavailable)
+	}
+	scope := listScope("treasuryHistory")
+	c, err := l.cursors.decode(scope, args.Cursor)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	start, err := sequenceStart(c)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	limit := args.limit(MaxTreasuryHistoryPage)
+	movements, next, err := l.treasury.Page(start, limit)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	return movements, l.indexPage(scope, limit, sequenceNext(next)), nil
+}
+
+func (l *lists) assetHistory(assetID ids.ID, args PageArgs) ([]*AssetOwnerChange, Page, error) {
+	if l.assets == nil {
+		return nil, Page{}, fmt.Errorf("%w: index disabled", ErrAssetHistoryUnavailable)
+	}
+	scope := listScope("assetHistory", assetID[:])
+	c, err := l.cursors.decode(scope, args.Cursor)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	start, err := sequenceStart(c)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	limit := args.limit(MaxAssetHistoryPage)
+	changes, next, err := l.assets.Page(assetID, start, limit)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	return changes, l.indexPage(scope, limit, sequenceNext(next)), nil
+}
+
+// sequenceNext is the position of the next page of an index listing whose
+// page returned [next], or nil if it was the last.
+func sequenceNext(next uint64) []byte {
+	if next == 0 {
+		return nil
+	}
+	return binary.BigEndian.AppendUint64(nil, next)
+}
+
+func (l *lists) txsByAddress(addr codec.Address, args PageArgs) ([]*IndexedTx, Page, error) {
+	if l.activity == nil {
+		return nil, Page{}, fmt.Errorf("%w: index disabled", ErrActivityUnavailable)
+	}
+	scope := listScope("getTxsByAddress", addr[:])
+	c, err := l.cursors.decode(scope, args.Cursor)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	start, err := idStart(c)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	it, err := l.activity.ByAddress(addr, start)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	limit := args.limit(MaxTxsByAddressPage)
+	items, next, err := idPage(it, limit, it.Tx, func() []byte {
+		id := it.ID()
+		return id[:]
+	})
+	return items, l.indexPage(scope, limit, next), err
+}
+
+// list runs the list method [method] with its JSON-RPC [params], returning
+// one page of items.
+func (l *lists) list(ctx context.Context, method string, params json.RawMessage) (any, Page, error) {
+	switch method {
+	case "assetsByOwner":
+		var args AssetsByOwnerArgs
+		if err := decodeParams(params, &args); err != nil {
+			return nil, Page{}, err
+		}
+		return wrapList(l.assetsByOwner(ctx, args.Owner, args.PageArgs))
+	case "vestings":
+		var args VestingsArgs
+		if err := decodeParams(params, &args); err != nil {
+			return nil, Page{}, err
+		}
+		return wrapList(l.vestings(ctx, args.Beneficiary, args.PageArgs))
+	case "activeSessions":
+		var args ActiveSessionsArgs
+		if err := decodeParams(params, &args); err != nil {
+			return nil, Page{}, err
+		}
+		return wrapList(l.activeSessions(ctx, args.Owner, args.PageArgs))
+	case "orders":
+		var args OrdersArgs
+		if err := decodeParams(params, &args); err != nil {
+			return nil, Page{}, err
+		}
+		return wrapList(l.orders(ctx, args.SellAsset, args.BuyAsset, args.PageArgs))
+	case "liveAuctions":
+		var args LiveAuctionsArgs
+		if err := decodeParams(params, &args); err != nil {
+			return nil, Page{}, err
+		}
+		return wrapList(l.liveAuctions(ctx, args.IncludeEnded, args.PageArgs))
+	case "appAssets":
+		var args AppAssetsArgs
+		if err := decodeParams(params, &args); err != nil {
+			return nil, Page{}, err
+		}
+		return wrapList(l.appAssets(ctx, args.App, args.PageArgs))
+	case "appCreators":
+		var args AppCreatorsArgs
+		if err := decodeParams(params, &args); err != nil {
+			return nil, Page{}, err
+		}
+		return wrapList(l.appCreators(ctx, args.App, args.PageArgs))
+	case "exportBalances":
+		var args ExportBalancesArgs
+		if err := decodeParams(params, &args); err != nil {
+			return nil, Page{}, err
+		}
+		return wrapList(l.exportBalances(ctx, args.PageArgs))
+	case "treasuryHistory":
+		var args TreasuryHistoryArgs
+		if err := decodeParams(params, &args); err != nil {
+			return nil, Page{}, err
+		}
+		return wrapList(l.treasuryHistory(args.PageArgs))
+	case "assetHistory":
+		var args AssetHistoryArgs
+		if err := decodeParams(params, &args); err != nil {
+			return nil, Page{}, err
+		}
+		return wrapList(l.assetHistory(args.AssetID, args.PageArgs))
+	case "getTxsByAddress":
+		var args TxsByAddressArgs
+		if err := decodeParams(params, &args); err != nil {
+			return nil, Page{}, err
+		}
+		return wrapList(l.txsByAddress(args.Address, args.PageArgs))
+	default:
+		return nil, Page{}, fmt.Errorf("%w: %q", ErrUnknownListMethod, method)
+	}
+}
+
+func decodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		return nil
+	}
+	return json.Unmarshal(params, v)
+}
+
+func wrapList[T any](items []T, page Page, err error) (any, Page, error) {
+	return items, page, err
+}
+
+// withCursor returns [params] with its cursor replaced by [cursor].
+func withCursor(params json.RawMessage, cursor string) (json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &fields); err != nil {
+			return nil, err
+		}
+	}
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return nil, err
+	}
+	fields["cursor"] = b
+	return json.Marshal(fields)
+}
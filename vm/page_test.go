@@ -0,0 +1,291 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+)
+
+// merkleHistory serves a bare merkledb as [historicalState].
+type merkleHistory struct {
+	merkledb.MerkleDB
+}
+
+func (h merkleHistory) State() (merkledb.MerkleDB, error) {
+	return h.MerkleDB, nil
+}
+
+func (merkleHistory) GetDiskBlock(context.Context, uint64) (*chain.StatefulBlock, error) {
+	return nil, errors.New("no blocks")
+}
+
+func TestCursorSigner(t *testing.T) {
+	require := require.New(t)
+	s, err := newCursorSigner("")
+	require.NoError(err)
+	owner := codectest.NewRandomAddress()
+	scope := listScope("assetsByOwner", owner[:])
+
+	c := cursor{start: []byte{1, 2, 3}, height: 9, root: ids.GenerateTestID()}
+	encoded := s.encode(scope, c)
+	decoded, err := s.decode(scope, encoded)
+	require.NoError(err)
+	require.Equal(c, decoded)
+
+	empty, err := s.decode(scope, "")
+	require.NoError(err)
+	require.Equal(cursor{}, empty)
+
+	// Cursors are bound to their method, their filter and the node's key.
+	other := codectest.NewRandomAddress()
+	_, err = s.decode(listScope("assetsByOwner", other[:]), encoded)
+	require.ErrorIs(err, ErrInvalidCursor)
+	_, err = s.decode(listScope("vestings", owner[:]), encoded)
+	require.ErrorIs(err, ErrInvalidCursor)
+	s2, err := newCursorSigner("")
+	require.NoError(err)
+	_, err = s2.decode(scope, encoded)
+	require.ErrorIs(err, ErrInvalidCursor)
+
+	// Any change to the start or the pin invalidates the cursor.
+	b, err := base64.RawURLEncoding.DecodeString(encoded)
+	require.NoError(err)
+	b[len(b)-sha256.Size-1]++
+	_, err = s.decode(scope, base64.RawURLEncoding.EncodeToString(b))
+	require.ErrorIs(err, ErrInvalidCursor)
+	_, err = s.decode(scope, "not a cursor")
+	require.ErrorIs(err, ErrInvalidCursor)
+}
+
+// newTestLists serves lists from an empty merkledb that keeps the roots of
+// its last 8 commits.
+func newTestLists(t *testing.T) (*lists, merkledb.MerkleDB) {
+	require := require.New(t)
+	db, err := merkledb.New(context.Background(), memdb.New(), merkledb.Config{
+		BranchFactor:                merkledb.BranchFactor16,
+		Hasher:                      merkledb.DefaultHasher,
+		HistoryLength:               8,
+		ValueNodeCacheSize:          units.MiB,
+		IntermediateNodeCacheSize:   units.MiB,
+		IntermediateWriteBufferSize: units.KiB,
+		IntermediateWriteBatchSize:  units.KiB,
+		Reg:                         prometheus.NewRegistry(),
+		TraceLevel:                  merkledb.InfoTrace,
+		Tracer:                      trace.Noop,
+	})
+	require.NoError(err)
+	require.NoError(db.Put(chain.HeightKey(storage.HeightKey()), []byte{0, 0, 0, 0, 0, 0, 0, 7}))
+	cursors, err := newCursorSigner("")
+	require.NoError(err)
+	return &lists{history: merkleHistory{db}, cursors: cursors}, db
+}
+
+func TestPinnedPages(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	l, db := newTestLists(t)
+
+	owner := codectest.NewRandomAddress()
+	assets := make([]ids.ID, 5)
+	for i := range assets {
+		assets[i] = ids.GenerateTestID()
+		require.NoError(db.Put(storage.OwnedAssetKey(owner, assets[i]), []byte{1}))
+	}
+	slices.SortFunc(assets, func(a, b ids.ID) int { return a.Compare(b) })
+
+	first, page, err := l.assetsByOwner(ctx, owner, PageArgs{Limit: 2})
+	require.NoError(err)
+	require.Equal(assets[:2], first)
+	require.True(page.HasMore)
+	require.Equal(uint64(7), page.Height)
+
+	// Writes after the first page do not shift the following ones.
+	require.NoError(db.Delete(storage.OwnedAssetKey(owner, assets[2])))
+	require.NoError(db.Put(storage.OwnedAssetKey(owner, ids.GenerateTestID()), []byte{1}))
+	rest, page, err := l.assetsByOwner(ctx, owner, PageArgs{Cursor: page.Cursor, Limit: 3})
+	require.NoError(err)
+	require.Equal(assets[2:], rest)
+	require.False(page.HasMore)
+	require.Empty(page.Cursor)
+	require.Equal(uint64(7), page.Height)
+
+	// A cursor cannot be used for another owner.
+	_, page, err = l.assetsByOwner(ctx, owner, PageArgs{Limit: 1})
+	require.NoError(err)
+	_, _, err = l.assetsByOwner(ctx, codectest.NewRandomAddress(), PageArgs{Cursor: page.Cursor})
+	require.ErrorIs(err, ErrInvalidCursor)
+
+	// Once the pinned state leaves the history, the listing must restart.
+	for i := 0; i < 10; i++ {
+		require.NoError(db.Put(storage.OwnedAssetKey(owner, ids.GenerateTestID()), []byte{1}))
+	}
+	_, _, err = l.assetsByOwner(ctx, owner, PageArgs{Cursor: page.Cursor})
+	require.ErrorIs(err, ErrCursorExpired)
+}
+
+func TestPageLimit(t *testing.T) {
+	require := require.New(t)
+	require.Equal(10, PageArgs{}.limit(10))
+	require.Equal(10, PageArgs{Limit: -1}.limit(10))
+	require.Equal(10, PageArgs{Limit: 11}.limit(10))
+	require.Equal(3, PageArgs{Limit: 3}.limit(10))
+}
+
+func TestListQuery(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	l, db := newTestLists(t)
+
+	owner := codectest.NewRandomAddress()
+	assets := make([]ids.ID, 5)
+	for i := range assets {
+		assets[i] = ids.GenerateTestID()
+		require.NoError(db.Put(storage.OwnedAssetKey(owner, assets[i]), []byte{1}))
+	}
+	slices.SortFunc(assets, func(a, b ids.ID) int { return a.Compare(b) })
+
+	// A query walks the pages of a list method by its JSON-RPC params, the
+	// way the stream serves it.
+	params, err := json.Marshal(&AssetsByOwnerArgs{Owner: owner, PageArgs: PageArgs{Limit: 2}})
+	require.NoError(err)
+	var (
+		listed []ids.ID
+		pages  int
+	)
+	for {
+		items, page, err := l.list(ctx, "assetsByOwner", params)
+		require.NoError(err)
+		require.Equal(2, page.Limit)
+		listed = append(listed, items.([]ids.ID)...)
+		pages++
+		if !page.HasMore {
+			require.Empty(page.Cursor)
+			break
+		}
+		params, err = withCursor(params, page.Cursor)
+		require.NoError(err)
+	}
+	require.Equal(assets, listed)
+	require.Equal(3, pages)
+
+	// Replacing the cursor keeps the other params.
+	var args AssetsByOwnerArgs
+	require.NoError(json.Unmarshal(params, &args))
+	require.Equal(owner, args.Owner)
+	require.Equal(2, args.Limit)
+
+	_, _, err = l.list(ctx, "balance", params)
+	require.ErrorIs(err, ErrUnknownListMethod)
+}
+
+func TestLiveAuctions(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	l, db := newTestLists(t)
+
+	now := time.Now().UnixMilli()
+	live := ids.GenerateTestID()
+	ended := ids.GenerateTestID()
+	store := chaintest.NewInMemoryStore()
+	require.NoError(storage.SetAuction(ctx, store, live, &storage.Auction{End: now + time.Hour.Milliseconds(), MinBid: 5}))
+	require.NoError(storage.SetAuction(ctx, store, ended, &storage.Auction{End: now - 1}))
+	for k, v := range store.Storage {
+		require.NoError(db.Put([]byte(k), v))
+	}
+
+	auctions, _, err := l.liveAuctions(ctx, false, PageArgs{})
+	require.NoError(err)
+	require.Len(auctions, 1)
+	require.Equal(live, auctions[0].Asset)
+	require.Equal(uint64(5), auctions[0].MinBid)
+
+	auctions, _, err = l.liveAuctions(ctx, true, PageArgs{})
+	require.NoError(err)
+	require.Len(auctions, 2)
+}
+
+func TestAppLists(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	l, db := newTestLists(t)
+
+	app := storage.AppID("team")
+	creator := codectest.NewRandomAddress()
+	kept := storage.AppAssetID(app, creator, 1)
+	burned := storage.AppAssetID(app, creator, 2)
+	store := chaintest.NewInMemoryStore()
+	require.NoError(storage.SetAppAdmin(ctx, store, app, codectest.NewRandomAddress()))
+	require.NoError(storage.SetAppCreator(ctx, store, app, creator, true))
+	require.NoError(storage.CreateAsset(ctx, store, kept, creator))
+	require.NoError(storage.AddAppAsset(ctx, store, app, kept))
+	require.NoError(storage.AddAppAsset(ctx, store, app, burned))
+	// Assets of other apps are not listed.
+	require.NoError(storage.AddAppAsset(ctx, store, storage.AppID("other"), ids.GenerateTestID()))
+	for k, v := range store.Storage {
+		require.NoError(db.Put([]byte(k), v))
+	}
+
+	// The burned asset is skipped.
+	assets, _, err := l.appAssets(ctx, app, PageArgs{})
+	require.NoError(err)
+	require.Equal([]ids.ID{kept}, assets)
+
+	creators, _, err := l.appCreators(ctx, app, PageArgs{})
+	require.NoError(err)
+	require.Equal([]codec.Address{creator}, creators)
+}
+
+func TestExportBalances(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	l, db := newTestLists(t)
+
+	store := chaintest.NewInMemoryStore()
+	balances := make([]AccountBalance, 3)
+	for i := range balances {
+		balances[i] = AccountBalance{Address: codectest.NewRandomAddress(), Balance: uint64(i + 1)}
+		require.NoError(storage.SetBalance(ctx, store, balances[i].Address, balances[i].Balance))
+	}
+	slices.SortFunc(balances, func(a, b AccountBalance) int { return bytes.Compare(a.Address[:], b.Address[:]) })
+	// Emptied balances are not exported.
+	require.NoError(storage.SetBalance(ctx, store, codectest.NewRandomAddress(), 0))
+	for k, v := range store.Storage {
+		require.NoError(db.Put([]byte(k), v))
+	}
+
+	_, _, err := l.exportBalances(ctx, PageArgs{})
+	require.ErrorIs(err, ErrBalanceExportUnavailable)
+
+	l.balanceExport = true
+	first, page, err := l.exportBalances(ctx, PageArgs{Limit: 2})
+	require.NoError(err)
+	require.Equal(balances[:2], first)
+	require.True(page.HasMore)
+	rest, page, err := l.exportBalances(ctx, PageArgs{Cursor: page.Cursor, Limit: 2})
+	require.NoError(err)
+	require.Equal(balances[2:], rest)
+	require.False(page.HasMore)
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+)
+
+var _ database.Iteratee = (*pinnedState)(nil)
+
+// pinnedState iterates state as it was when [db] had [root], reading it
+// through the merkledb change history in chunks of [chunk] keys. Once the
+// root falls out of the history, reads fail with [ErrCursorExpired].
+type pinnedState struct {
+	ctx   context.Context
+	db    merkledb.MerkleDB
+	root  ids.ID
+	chunk int
+}
+
+// get returns the value of [key] at the pinned root.
+func (p *pinnedState) get(key []byte) ([]byte, error) {
+	kvs, err := p.read(key, maybe.Some(key), 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(kvs) == 0 || !bytes.Equal(kvs[0].Key, key) {
+		return nil, database.ErrNotFound
+	}
+	return kvs[0].Value, nil
+}
+
+// read returns up to [limit] key-values from [start] to [end] at the pinned
+// root.
+func (p *pinnedState) read(start []byte, end maybe.Maybe[[]byte], limit int) ([]merkledb.KeyValue, error) {
+	from := maybe.Nothing[[]byte]()
+	if len(start) > 0 {
+		from = maybe.Some(start)
+	}
+	proof, err := p.db.GetRangeProofAtRoot(p.ctx, p.root, from, end, limit)
+	switch {
+	case errors.Is(err, merkledb.ErrEmptyProof):
+		// The state was empty.
+		return nil, nil
+	case errors.Is(err, merkledb.ErrInsufficientHistory):
+		return nil, fmt.Errorf("%w: %w", ErrCursorExpired, err)
+	case err != nil:
+		return nil, err
+	default:
+		return proof.KeyValues, nil
+	}
+}
+
+func (p *pinnedState) NewIterator() database.Iterator {
+	return p.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (p *pinnedState) NewIteratorWithStart(start []byte) database.Iterator {
+	return p.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (p *pinnedState) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return p.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (p *pinnedState) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	next := start
+	if bytes.Compare(start, prefix) < 0 {
+		next = prefix
+	}
+	return &pinnedIterator{state: p, next: slices.Clone(next), prefix: slices.Clone(prefix)}
+}
+
+type pinnedIterator struct {
+	state  *pinnedState
+	prefix []byte
+	// next is where the following chunk starts.
+	next []byte
+	kvs  []merkledb.KeyValue
+	// done is set once the last chunk has been read.
+	done  bool
+	key   []byte
+	value []byte
+	err   error
+}
+
+func (it *pinnedIterator) Next() bool {
+	for len(it.kvs) == 0 {
+		if it.done || it.err != nil {
+			it.key, it.value = nil, nil
+			return false
+		}
+		it.fetch()
+	}
+	kv := it.kvs[0]
+	if !bytes.HasPrefix(kv.Key, it.prefix) {
+		it.kvs, it.done = nil, true
+		it.key, it.value = nil, nil
+		return false
+	}
+	it.kvs = it.kvs[1:]
+	it.key, it.value = kv.Key, kv.Value
+	return true
+}
+
+func (it *pinnedIterator) fetch() {
+	kvs, err := it.state.read(it.next, maybe.Nothing[[]byte](), it.state.chunk)
+	if err != nil {
+		it.err = err
+		return
+	}
+	it.kvs = kvs
+	if len(kvs) < it.state.chunk {
+		it.done = true
+		return
+	}
+	// The smallest key after the last one read.
+	it.next = append(slices.Clone(kvs[len(kvs)-1].Key), 0)
+}
+
+func (it *pinnedIterator) Error() error {
+	return it.err
+}
+
+func (it *pinnedIterator) Key() []byte {
+	return it.key
+}
+
+func (it *pinnedIterator) Value() []byte {
+	return it.value
+}
+
+func (it *pinnedIterator) Release() {
+	it.kvs = nil
+}
@@ -0,0 +1,154 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/screening"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/vm"
+)
+
+const PreflightNamespace = "preflight"
+
+var ErrPreflight = errors.New("rejected by asset preflight")
+
+var _ screening.Screener = (*preflight)(nil)
+
+// PreflightConfig enables the asset preflight, which rejects submissions
+// whose asset actions would fail against the current state. It reads state
+// for every submitted transaction that moves non-fungible assets, and
+// rejects some transactions that would succeed after another pending one,
+// such as a transfer of an asset whose mint is not yet accepted, so it is
+// off by default.
+type PreflightConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// preflight checks the non-fungible assets an action moves: that they
+// exist, are held by the actor, or by the signer of a permit, and can move.
+// Expiry is checked against the local clock, which is enough since an
+// expired asset never becomes movable again. Assets an earlier action of
+// the same transaction writes are skipped, since that action may create or
+// hand them over.
+type preflight struct {
+	read storage.ReadState
+}
+
+// withPreflight must come after [submitScreen.withChecks], so stateless
+// checks run before state reads, and before [submitScreen.withScreening].
+func (s *submitScreen) withPreflight() vm.Option {
+	return vm.NewOption(PreflightNamespace, PreflightConfig{}, func(v *vm.VM, config PreflightConfig) error {
+		if config.Enabled {
+			s.screeners = append(s.screeners, &preflight{read: v.ReadState})
+		}
+		return nil
+	})
+}
+
+func (p *preflight) Screen(ctx context.Context, tx *chain.Transaction) error {
+	actor := tx.Auth.Actor()
+	written := map[string]bool{}
+	for i, action := range tx.Actions {
+		if err := p.check(ctx, action, actor, written); err != nil {
+			return fmt.Errorf("%w: action %d: %w", ErrPreflight, i, err)
+		}
+		for k, perm := range action.StateKeys(actor) {
+			if perm.Has(state.Write) {
+				written[k] = true
+			}
+		}
+	}
+	return nil
+}
+
+func (p *preflight) check(ctx context.Context, action chain.Action, actor codec.Address, written map[string]bool) error {
+	switch a := action.(type) {
+	case *actions.AssetTransfer:
+		return p.movable(ctx, a.Asset, actor, written)
+	case *actions.PermitTransfer:
+		return p.movable(ctx, a.Permit.Asset, a.OwnerAddress(), written)
+	case *actions.CreateAuction:
+		return p.movable(ctx, a.Asset, actor, written)
+	case *actions.BurnAsset:
+		if written[string(storage.AssetKey(a.Asset))] {
+			return nil
+		}
+		_, err := p.held(ctx, a.Asset, actor)
+		return err
+	case *actions.DepositToLocker:
+		if err := p.locker(ctx, a.Locker, actor, written); err != nil {
+			return err
+		}
+		for _, item := range a.Items {
+			if err := p.movable(ctx, item, actor, written); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *actions.UnbundleLocker:
+		return p.locker(ctx, a.Locker, actor, written)
+	default:
+		return nil
+	}
+}
+
+// held checks that [actor] holds [asset], returning its control.
+func (p *preflight) held(ctx context.Context, asset ids.ID, actor codec.Address) (storage.AssetControl, error) {
+	owner, _, control, err := storage.GetAssetFromState(ctx, p.read, asset)
+	if err != nil {
+		return control, err
+	}
+	if owner != actor {
+		return control, actions.ErrAssetNotOwned
+	}
+	return control, nil
+}
+
+// movable checks that [actor] holds [asset] and may transfer it.
+func (p *preflight) movable(ctx context.Context, asset ids.ID, actor codec.Address, written map[string]bool) error {
+	if written[string(storage.AssetKey(asset))] {
+		return nil
+	}
+	control, err := p.held(ctx, asset, actor)
+	switch {
+	case err != nil:
+		return err
+	case control.Frozen:
+		return actions.ErrAssetFrozen
+	case control.Soulbound:
+		return actions.ErrAssetSoulbound
+	case control.Expired(time.Now().UnixMilli()):
+		return actions.ErrAssetExpired
+	default:
+		return nil
+	}
+}
+
+func (p *preflight) locker(ctx context.Context, locker ids.ID, actor codec.Address, written map[string]bool) error {
+	if written[string(storage.AssetKey(locker))] || written[string(storage.LockerKey(locker))] {
+		return nil
+	}
+	_, holder, exists, err := storage.GetLockerFromState(ctx, p.read, locker)
+	switch {
+	case err != nil:
+		return err
+	case !exists:
+		return actions.ErrLockerNotFound
+	case holder != actor:
+		return actions.ErrNotLockerHolder
+	default:
+		return nil
+	}
+}
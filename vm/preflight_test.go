@@ -0,0 +1,104 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+func TestPreflight(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	signer := &auth.ED25519{Signer: priv.PublicKey()}
+	actor := signer.Actor()
+	other := codectest.NewRandomAddress()
+
+	store := chaintest.NewInMemoryStore()
+	held := ids.GenerateTestID()
+	foreign := ids.GenerateTestID()
+	frozen := ids.GenerateTestID()
+	badge := ids.GenerateTestID()
+	locker := storage.LockerID(actor, 1)
+	require.NoError(storage.CreateAsset(ctx, store, held, actor))
+	require.NoError(storage.CreateAsset(ctx, store, foreign, other))
+	require.NoError(storage.CreateAsset(ctx, store, frozen, actor))
+	require.NoError(storage.SetAssetFrozen(ctx, store, frozen, true))
+	require.NoError(storage.CreateSoulboundAsset(ctx, store, badge, actor))
+	require.NoError(storage.CreateAsset(ctx, store, locker, actor))
+	require.NoError(storage.SetLocker(ctx, store, locker, &storage.Locker{}))
+
+	p := &preflight{read: func(ctx context.Context, keys [][]byte) ([][]byte, []error) {
+		values := make([][]byte, len(keys))
+		errs := make([]error, len(keys))
+		for i, k := range keys {
+			values[i], errs[i] = store.GetValue(ctx, k)
+		}
+		return values, errs
+	}}
+	screen := func(as ...chain.Action) error {
+		return p.Screen(ctx, &chain.Transaction{
+			Actions: as,
+			Auth:    signer,
+		})
+	}
+	transfer := func(asset ids.ID) chain.Action {
+		return &actions.AssetTransfer{Recipient: other, Asset: asset}
+	}
+	permit := func(asset ids.ID) chain.Action {
+		pk := priv.PublicKey()
+		return &actions.PermitTransfer{Permit: actions.Permit{Asset: asset, Recipient: other}, Owner: pk[:]}
+	}
+
+	require.NoError(screen(transfer(held)))
+	require.NoError(screen(&actions.BurnAsset{Asset: badge}))
+	require.NoError(screen(&actions.DepositToLocker{Locker: locker, Items: []ids.ID{held}}))
+	require.NoError(screen(&actions.UnbundleLocker{Locker: locker}))
+	require.NoError(screen(permit(held)))
+	require.NoError(screen(&actions.CreateAuction{Asset: held}))
+
+	for name, tt := range map[string]struct {
+		actions []chain.Action
+		err     error
+	}{
+		"missing":       {[]chain.Action{transfer(ids.GenerateTestID())}, storage.ErrAssetNotFound},
+		"not owned":     {[]chain.Action{transfer(foreign)}, actions.ErrAssetNotOwned},
+		"frozen":        {[]chain.Action{transfer(frozen)}, actions.ErrAssetFrozen},
+		"soulbound":     {[]chain.Action{transfer(badge)}, actions.ErrAssetSoulbound},
+		"burn not ours": {[]chain.Action{&actions.BurnAsset{Asset: foreign}}, actions.ErrAssetNotOwned},
+		"no locker":     {[]chain.Action{&actions.UnbundleLocker{Locker: ids.GenerateTestID()}}, actions.ErrLockerNotFound},
+		"locker item":   {[]chain.Action{&actions.DepositToLocker{Locker: locker, Items: []ids.ID{badge}}}, actions.ErrAssetSoulbound},
+		"later action":  {[]chain.Action{transfer(held), transfer(foreign)}, actions.ErrAssetNotOwned},
+		"permit":        {[]chain.Action{permit(foreign)}, actions.ErrAssetNotOwned},
+		"auction":       {[]chain.Action{&actions.CreateAuction{Asset: frozen}}, actions.ErrAssetFrozen},
+	} {
+		err := screen(tt.actions...)
+		require.ErrorIs(err, ErrPreflight, name)
+		require.ErrorIs(err, tt.err, name)
+	}
+
+	// Assets an earlier action writes are left to execution.
+	minted := ids.GenerateTestID()
+	require.NoError(screen(&actions.MintAsset{Asset: minted}, transfer(minted)))
+	created := storage.AssetID(actor, 7)
+	require.NoError(screen(&actions.CreateAsset{Nonce: 7}, transfer(created)))
+	require.NoError(screen(&actions.CreateLocker{Nonce: 2}, &actions.UnbundleLocker{Locker: storage.LockerID(actor, 2)}))
+
+	// A locker held by someone else cannot be unbundled.
+	require.NoError(storage.SetAssetOwner(ctx, store, storage.AssetKey(locker), other))
+	require.ErrorIs(screen(&actions.UnbundleLocker{Locker: locker}), actions.ErrNotLockerHolder)
+}
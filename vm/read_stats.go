@@ -0,0 +1,69 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"sync"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// slowKeyCacheSize bounds how many keys have their slow reads counted.
+const slowKeyCacheSize = 1024
+
+var _ storage.ReadRecorder = (*readStats)(nil)
+
+// readStats turns storage reads into metrics and logs the keys that keep
+// reading at least [Config.SlowReadChunks] chunks, which are candidates
+// for a larger chunk budget or a new layout.
+type readStats struct {
+	metrics *metrics
+	log     logging.Logger
+
+	threshold uint16
+	reports   int
+
+	lock sync.Mutex
+	slow *cache.LRU[string, int]
+}
+
+func newReadStats(m *metrics, log logging.Logger, config Config) *readStats {
+	return &readStats{
+		metrics:   m,
+		log:       log,
+		threshold: config.SlowReadChunks,
+		reports:   max(config.SlowReadReports, 1),
+		slow:      &cache.LRU[string, int]{Size: slowKeyCacheSize},
+	}
+}
+
+func (r *readStats) RecordRead(key []byte, chunks uint16, maxChunks uint16) {
+	prefix := storage.PrefixName(key)
+	r.metrics.readChunks.WithLabelValues(prefix).Observe(float64(chunks))
+	if r.threshold == 0 || chunks < r.threshold {
+		return
+	}
+	r.metrics.slowReads.WithLabelValues(prefix).Inc()
+
+	r.lock.Lock()
+	count, _ := r.slow.Get(string(key))
+	count++
+	r.slow.Put(string(key), count)
+	r.lock.Unlock()
+
+	if count%r.reports == 0 {
+		r.log.Warn("slow storage key",
+			zap.String("prefix", prefix),
+			zap.Stringer("key", codec.Bytes(key)),
+			zap.Uint16("chunks", chunks),
+			zap.Uint16("maxChunks", maxChunks),
+			zap.Int("slowReads", count),
+		)
+	}
+}
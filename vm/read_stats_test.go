@@ -0,0 +1,61 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+)
+
+func TestReadStats(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	m, err := newMetrics()
+	require.NoError(err)
+	r := newReadStats(m, logging.NoLog{}, Config{SlowReadChunks: 2, SlowReadReports: 3})
+	store := chaintest.NewInMemoryStore()
+	addr := codectest.NewRandomAddress()
+	require.NoError(storage.SetBalance(ctx, store, addr, 1))
+	asset := ids.GenerateTestID()
+	require.NoError(storage.CreateAsset(ctx, store, asset, addr))
+	require.NoError(storage.SetAssetMetadata(ctx, store, asset, storage.AssetMetadata{URI: string(make([]byte, 200))}))
+	balancePrefix := storage.PrefixName(storage.BalanceKey(addr))
+	assetPrefix := storage.PrefixName(storage.AssetKey(asset))
+	storage.SetReadRecorder(r)
+	t.Cleanup(func() { storage.SetReadRecorder(nil) })
+
+	// Every read that finds a value is observed by record type.
+	_, err = storage.GetBalance(ctx, store, addr)
+	require.NoError(err)
+	_, err = storage.GetBalance(ctx, store, codectest.NewRandomAddress())
+	require.NoError(err)
+	require.Equal(1, testutil.CollectAndCount(m.readChunks))
+
+	// Reads of at least the threshold are slow, counted per key.
+	for i := 0; i < 4; i++ {
+		_, err = storage.GetAssetMetadata(ctx, store, asset)
+		require.NoError(err)
+	}
+	require.Equal(2, testutil.CollectAndCount(m.readChunks))
+	require.Zero(testutil.ToFloat64(m.slowReads.WithLabelValues(balancePrefix)))
+	require.Equal(4.0, testutil.ToFloat64(m.slowReads.WithLabelValues(assetPrefix)))
+	count, ok := r.slow.Get(string(storage.AssetKey(asset)))
+	require.True(ok)
+	require.Equal(4, count)
+
+	// A zero threshold turns slow reporting off.
+	r.threshold = 0
+	_, err = storage.GetAssetMetadata(ctx, store, asset)
+	require.NoError(err)
+	require.Equal(4.0, testutil.ToFloat64(m.slowReads.WithLabelValues(assetPrefix)))
+}
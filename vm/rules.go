@@ -0,0 +1,184 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/genesis"
+)
+
+var _ chain.Rules = (*Rules)(nil)
+
+var ErrInvalidRules = errors.New("invalid rules")
+
+// Rules extends [genesis.Rules] with MorpheusVM parameters, which actions
+// read through FetchCustom. Its JSON is a superset of [genesis.Rules].
+type Rules struct {
+	*genesis.Rules
+
+	// MaxMemoSize bounds transfer memos, in bytes.
+	MaxMemoSize int `json:"maxMemoSize"`
+
+	// MemoBytesPerComputeUnit is how many memo bytes each extra compute
+	// unit of a transfer pays for.
+	MemoBytesPerComputeUnit uint64 `json:"memoBytesPerComputeUnit"`
+
+	// MaxReasonSize bounds asset transfer reasons, in bytes.
+	MaxReasonSize int `json:"maxReasonSize"`
+
+	// ComputeUnits replaces the base compute units of actions, by action
+	// name such as "Transfer". Actions left out keep their default cost.
+	// Fees are these units priced by the hypersdk unit price rules.
+	ComputeUnits map[string]uint64 `json:"computeUnits,omitempty"`
+
+	// StakingRewardRate is paid on every stake each epoch, in basis points
+	// of the stake.
+	StakingRewardRate uint64 `json:"stakingRewardRate"`
+
+	// StakingEpochLength is the number of blocks in a staking epoch.
+	StakingEpochLength uint64 `json:"stakingEpochLength"`
+
+	// GovernanceVotingPeriod is the number of blocks a governance proposal
+	// is open for votes, starting with the block that creates it.
+	GovernanceVotingPeriod uint64 `json:"governanceVotingPeriod"`
+
+	// GovernanceQuorum is the native balance that must vote on a proposal
+	// for it to pass.
+	GovernanceQuorum uint64 `json:"governanceQuorum"`
+
+	// GovernanceDeposit is the native balance a proposer bonds to create a
+	// proposal, refunded if it passes and burned otherwise.
+	GovernanceDeposit uint64 `json:"governanceDeposit"`
+
+	// ClaimRentPerByteHour is burned for every byte of a claim, for every
+	// started hour until it expires.
+	ClaimRentPerByteHour uint64 `json:"claimRentPerByteHour"`
+
+	// MaintenanceAddress is the system key allowed to send maintenance
+	// actions. Maintenance is disabled when it is empty.
+	MaintenanceAddress codec.Address `json:"maintenanceAddress"`
+
+	// DustThreshold is the smallest nonzero native balance a transfer may
+	// leave either party with. Zero disables the check.
+	DustThreshold uint64 `json:"dustThreshold"`
+
+	// DustPolicy is what happens to a sender a transfer would leave below
+	// [DustThreshold]: 0 rejects the transfer and 1 burns the remainder.
+	DustPolicy uint8 `json:"dustPolicy"`
+
+	// computeUnits is [ComputeUnits] by action type ID, set by verify.
+	computeUnits map[uint8]uint64
+}
+
+// newRules returns rules with the MorpheusVM parameters at their defaults.
+func newRules() *Rules {
+	return &Rules{
+		MaxMemoSize:             actions.MaxMemoSize,
+		MemoBytesPerComputeUnit: actions.MemoBytesPerComputeUnit,
+		MaxReasonSize:           actions.MaxReasonSize,
+		StakingRewardRate:       actions.StakingRewardRate,
+		StakingEpochLength:      actions.StakingEpochLength,
+		GovernanceVotingPeriod:  actions.GovernanceVotingPeriod,
+		GovernanceQuorum:        actions.GovernanceQuorum,
+		GovernanceDeposit:       actions.GovernanceDeposit,
+		ClaimRentPerByteHour:    actions.ClaimRentPerByteHour,
+	}
+}
+
+func (r *Rules) verify() error {
+	if r.Rules == nil {
+		return fmt.Errorf("%w: missing hypersdk rules", ErrInvalidRules)
+	}
+	if r.MaxMemoSize < 0 {
+		return fmt.Errorf("%w: negative maxMemoSize", ErrInvalidRules)
+	}
+	if r.MemoBytesPerComputeUnit == 0 {
+		return fmt.Errorf("%w: zero memoBytesPerComputeUnit", ErrInvalidRules)
+	}
+	if r.StakingEpochLength == 0 {
+		return fmt.Errorf("%w: zero stakingEpochLength", ErrInvalidRules)
+	}
+	if r.GovernanceVotingPeriod == 0 {
+		return fmt.Errorf("%w: zero governanceVotingPeriod", ErrInvalidRules)
+	}
+	if r.DustPolicy > actions.DustBurn {
+		return fmt.Errorf("%w: unknown dustPolicy %d", ErrInvalidRules, r.DustPolicy)
+	}
+	if r.MaxReasonSize < 0 {
+		return fmt.Errorf("%w: negative maxReasonSize", ErrInvalidRules)
+	}
+	return r.resolveComputeUnits()
+}
+
+// resolveComputeUnits keys [Rules.ComputeUnits] by action type ID.
+func (r *Rules) resolveComputeUnits() error {
+	r.computeUnits = nil
+	if len(r.ComputeUnits) == 0 {
+		return nil
+	}
+	types := actionTypes()
+	r.computeUnits = make(map[uint8]uint64, len(r.ComputeUnits))
+	for name, units := range r.ComputeUnits {
+		rt, ok := types[name]
+		if !ok {
+			return fmt.Errorf("%w: computeUnits of unknown action %q", ErrInvalidRules, name)
+		}
+		if units == 0 {
+			return fmt.Errorf("%w: zero computeUnits for %s", ErrInvalidRules, name)
+		}
+		r.computeUnits[reflect.New(rt).Interface().(chain.Action).GetTypeID()] = units
+	}
+	return nil
+}
+
+// copy returns rules that can be overridden without changing [r].
+func (r *Rules) copy() *Rules {
+	c := *r
+	inner := *r.Rules
+	c.Rules = &inner
+	// Overrides are unmarshaled into the copy, which would otherwise merge
+	// them into the map of [r].
+	c.ComputeUnits = maps.Clone(r.ComputeUnits)
+	return &c
+}
+
+func (r *Rules) FetchCustom(key string) (any, bool) {
+	switch key {
+	case actions.MaxMemoSizeRule:
+		return r.MaxMemoSize, true
+	case actions.MemoBytesPerComputeUnitRule:
+		return r.MemoBytesPerComputeUnit, true
+	case actions.MaxReasonSizeRule:
+		return r.MaxReasonSize, true
+	case actions.ComputeUnitsRule:
+		return r.computeUnits, true
+	case actions.StakingRewardRateRule:
+		return r.StakingRewardRate, true
+	case actions.StakingEpochLengthRule:
+		return r.StakingEpochLength, true
+	case actions.GovernanceVotingPeriodRule:
+		return r.GovernanceVotingPeriod, true
+	case actions.GovernanceQuorumRule:
+		return r.GovernanceQuorum, true
+	case actions.GovernanceDepositRule:
+		return r.GovernanceDeposit, true
+	case actions.ClaimRentPerByteHourRule:
+		return r.ClaimRentPerByteHour, true
+	case actions.MaintenanceAddressRule:
+		return r.MaintenanceAddress, true
+	case actions.DustThresholdRule:
+		return r.DustThreshold, true
+	case actions.DustPolicyRule:
+		return r.DustPolicy, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk-starter-kit/screening"
+	"github.com/ava-labs/hypersdk-starter-kit/txcheck"
+	"github.com/ava-labs/hypersdk/api"
+	"github.com/ava-labs/hypersdk/api/jsonrpc"
+	"github.com/ava-labs/hypersdk/api/ws"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/event"
+	"github.com/ava-labs/hypersdk/vm"
+)
+
+var (
+	_ api.VM                     = (*screenedVM)(nil)
+	_ api.HandlerFactory[api.VM] = (*screenedHandlerFactory)(nil)
+)
+
+// submitScreen holds the screeners set by the txcheck and screening
+// options, in the order they run. The hypersdk VM has no hook ahead of
+// mempool admission, so the core JSON-RPC and WebSocket APIs are registered
+// here, against a VM whose Submit screens first. Its Submit also hands the
+// admitted transactions to [expiry]. Transactions gossiped from other nodes
+// are not screened.
+type submitScreen struct {
+	screeners []screening.Screener
+	expiry    *expiryTracker
+}
+
+// withChecks runs the configured txcheck plugins. It must come before
+// [withScreening], so cheap stateless checks run before provider lookups.
+func (s *submitScreen) withChecks() vm.Option {
+	return vm.NewOption(txcheck.Namespace, txcheck.NewDefaultConfig(), func(_ *vm.VM, config txcheck.Config) error {
+		checks, err := txcheck.New(config)
+		if err != nil {
+			return err
+		}
+		if checks.Len() > 0 {
+			s.screeners = append(s.screeners, checks)
+		}
+		return nil
+	})
+}
+
+func (s *submitScreen) withScreening() vm.Option {
+	return vm.NewOption(screening.Namespace, screening.NewDefaultConfig(), func(_ *vm.VM, config screening.Config) error {
+		if !config.Enabled {
+			return nil
+		}
+		screener, err := screening.NewHTTPScreener(config)
+		if err != nil {
+			return err
+		}
+		s.screeners = append(s.screeners, screener)
+		return nil
+	})
+}
+
+// withJSONRPC replaces [jsonrpc.With].
+func (s *submitScreen) withJSONRPC() vm.Option {
+	return vm.NewOption(jsonrpc.Namespace, jsonrpc.NewDefaultConfig(), func(v *vm.VM, config jsonrpc.Config) error {
+		if !config.Enabled {
+			return nil
+		}
+		vm.WithVMAPIs(screenedHandlerFactory{screen: s, factory: jsonrpc.JSONRPCServerFactory{}})(v)
+		return nil
+	})
+}
+
+// withWebSocket replaces [ws.With]. It must come after [withChecks] and
+// [withScreening].
+func (s *submitScreen) withWebSocket() vm.Option {
+	return vm.NewOption(ws.Namespace, ws.NewDefaultConfig(), func(v *vm.VM, config ws.Config) error {
+		if !config.Enabled {
+			return nil
+		}
+		server, handler := ws.NewWebSocketServer(
+			s.wrap(v),
+			v.Logger(),
+			v.Tracer(),
+			v.ActionCodec(),
+			v.AuthCodec(),
+			config.MaxPendingMessages,
+		)
+		vm.WithBlockSubscriptions(event.SubscriptionFuncFactory[*chain.ExecutedBlock]{
+			AcceptF: server.AcceptBlock,
+		})(v)
+		vm.WithTxRemovedSubscriptions(event.SubscriptionFuncFactory[vm.TxRemovedEvent]{
+			AcceptF: func(e vm.TxRemovedEvent) error {
+				return server.RemoveTx(e.TxID, e.Err)
+			},
+		})(v)
+		vm.WithVMAPIs(ws.NewWebSocketServerFactory(handler))(v)
+		return nil
+	})
+}
+
+// wrap returns [v] with a screened Submit, or [v] if nothing screens or
+// tracks expiries.
+func (s *submitScreen) wrap(v api.VM) api.VM {
+	if len(s.screeners) == 0 && !s.expiry.enabled() {
+		return v
+	}
+	return &screenedVM{VM: v, screeners: s.screeners, expiry: s.expiry}
+}
+
+type screenedHandlerFactory struct {
+	screen  *submitScreen
+	factory api.HandlerFactory[api.VM]
+}
+
+func (f screenedHandlerFactory) New(v api.VM) (api.Handler, error) {
+	return f.factory.New(f.screen.wrap(v))
+}
+
+type screenedVM struct {
+	api.VM
+	screeners []screening.Screener
+	expiry    *expiryTracker
+}
+
+// Submit passes the transactions that clear screening to the VM. Errors are
+// returned in the order of [txs].
+func (v *screenedVM) Submit(ctx context.Context, verifyAuth bool, txs []*chain.Transaction) []error {
+	var (
+		errs    = make([]error, len(txs))
+		cleared = make([]*chain.Transaction, 0, len(txs))
+		indices = make([]int, 0, len(txs))
+	)
+	for i, tx := range txs {
+		if err := v.screen(ctx, tx); err != nil {
+			v.Logger().Info("rejected screened transaction",
+				zap.Stringer("txID", tx.ID()),
+				zap.Error(err),
+			)
+			errs[i] = err
+			continue
+		}
+		cleared = append(cleared, tx)
+		indices = append(indices, i)
+	}
+	if len(cleared) == 0 {
+		return errs
+	}
+	submitErrs := v.VM.Submit(ctx, verifyAuth, cleared)
+	for i, index := range indices {
+		// Submit returns a single error when it fails before looking at
+		// individual transactions.
+		if len(submitErrs) == len(cleared) {
+			errs[index] = submitErrs[i]
+		} else if len(submitErrs) > 0 {
+			errs[index] = submitErrs[0]
+		}
+	}
+	v.expiry.track(txs, errs)
+	return errs
+}
+
+func (v *screenedVM) screen(ctx context.Context, tx *chain.Transaction) error {
+	for _, screener := range v.screeners {
+		if err := screener.Screen(ctx, tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
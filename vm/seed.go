@@ -0,0 +1,96 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var ErrInvalidSeed = errors.New("invalid seed")
+
+// Seed is demo state created at genesis, so every devnet built from the same
+// seed starts out identical.
+type Seed struct {
+	// Accounts are funded before any action runs.
+	Accounts []*genesis.CustomAllocation `json:"accounts"`
+
+	// Actions run in order, as if each were the only action of a
+	// transaction from its actor at timestamp 0.
+	Actions []SeedAction `json:"actions"`
+
+	actions []chain.Action
+}
+
+type SeedAction struct {
+	Actor codec.Address `json:"actor"`
+
+	// Type is the name of the action, such as "MintAsset".
+	Type string `json:"type"`
+
+	// Action is the JSON of the action.
+	Action json.RawMessage `json:"action"`
+}
+
+// WithSeed returns [genesisBytes] with [seedBytes] as its seed.
+func WithSeed(genesisBytes []byte, seedBytes []byte) ([]byte, error) {
+	return withGenesisField(genesisBytes, "seed", seedBytes)
+}
+
+// actionTypes maps the names of the actions registered in [ActionParser],
+// such as "MintAsset", to their types.
+func actionTypes() map[string]reflect.Type {
+	types := make(map[string]reflect.Type)
+	for _, t := range ActionParser.GetRegisteredTypes() {
+		rt := reflect.TypeOf(t).Elem()
+		types[rt.Name()] = rt
+	}
+	return types
+}
+
+// decode parses the seed actions with the types registered in
+// [ActionParser].
+func (s *Seed) decode() error {
+	types := actionTypes()
+	s.actions = make([]chain.Action, len(s.Actions))
+	for i, a := range s.Actions {
+		rt, ok := types[a.Type]
+		if !ok {
+			return fmt.Errorf("%w: action %d has unknown type %q", ErrInvalidSeed, i, a.Type)
+		}
+		action := reflect.New(rt).Interface().(chain.Action)
+		if len(a.Action) > 0 {
+			if err := json.Unmarshal(a.Action, action); err != nil {
+				return fmt.Errorf("%w: action %d: %w", ErrInvalidSeed, i, err)
+			}
+		}
+		s.actions[i] = action
+	}
+	return nil
+}
+
+func (s *Seed) initializeState(ctx context.Context, r chain.Rules, mu state.Mutable, balanceHandler chain.BalanceHandler) error {
+	for _, account := range s.Accounts {
+		if err := balanceHandler.AddBalance(ctx, account.Address, mu, account.Balance, true); err != nil {
+			return fmt.Errorf("%w: account %s: %w", ErrInvalidSeed, account.Address, err)
+		}
+	}
+	for i, action := range s.actions {
+		actionID := utils.ToID(binary.BigEndian.AppendUint32([]byte("seed"), uint32(i)))
+		if _, err := action.Execute(ctx, r, mu, 0, s.Actions[i].Actor, actionID); err != nil {
+			return fmt.Errorf("%w: action %d (%s): %w", ErrInvalidSeed, i, s.Actions[i].Type, err)
+		}
+	}
+	return nil
+}
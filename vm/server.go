@@ -4,23 +4,118 @@
 package vm
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
 	"net/http"
+	"reflect"
+	"strings"
+	"time"
 
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
 	"github.com/ava-labs/hypersdk-starter-kit/consts"
+	"github.com/ava-labs/hypersdk-starter-kit/cost"
+	"github.com/ava-labs/hypersdk-starter-kit/evm"
+	"github.com/ava-labs/hypersdk-starter-kit/proof"
 	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/abi"
 	"github.com/ava-labs/hypersdk/api"
+	"github.com/ava-labs/hypersdk/api/indexer"
+	"github.com/ava-labs/hypersdk/api/jsonrpc"
+	"github.com/ava-labs/hypersdk/api/ws"
+	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
-	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/fees"
+	"github.com/ava-labs/hypersdk/state"
+
+	staterpc "github.com/ava-labs/hypersdk/api/state"
 )
 
 const JSONRPCEndpoint = "/morpheusapi"
 
+// MaxAssetsByOwnerPage bounds the assets returned by one AssetsByOwner call.
+const MaxAssetsByOwnerPage = 256
+
+// MaxAppAssetsPage bounds the assets returned by one AppAssets call.
+const MaxAppAssetsPage = 256
+
+// MaxAppCreatorsPage bounds the creators returned by one AppCreators call.
+const MaxAppCreatorsPage = 256
+
+// MaxVestingsPage bounds the vestings returned by one Vestings call.
+const MaxVestingsPage = 256
+
+// MaxSessionsPage bounds the sessions returned by one ActiveSessions call.
+const MaxSessionsPage = 256
+
+// MaxOrdersPage bounds the orders returned by one Orders call.
+const MaxOrdersPage = 256
+
+// MaxAuctionsPage bounds the auctions returned by one LiveAuctions call.
+const MaxAuctionsPage = 256
+
+// MaxExportBalancesPage bounds the balances returned by one ExportBalances
+// call.
+const MaxExportBalancesPage = 1024
+
+// MaxBulkReads bounds the keys read by one Balances or AssetOwners call.
+const MaxBulkReads = 256
+
+// DefaultSummaryTop and MaxSummaryTop bound the balances hashed by one
+// EconomicSummary call.
+const (
+	DefaultSummaryTop = 100
+	MaxSummaryTop     = 10_000
+)
+
+var (
+	ErrStateIterationUnavailable = errors.New("state iteration unavailable")
+	ErrBalanceExportUnavailable  = errors.New("balance export unavailable")
+	ErrNoActions                 = errors.New("no actions to simulate")
+	ErrActionExtraBytes          = errors.New("action has extra bytes")
+	ErrUnknownAction             = errors.New("unknown action type")
+	ErrTooManyReads              = fmt.Errorf("cannot read more than %d keys", MaxBulkReads)
+)
+
+// apiEndpoints are the handlers registered on every MorpheusVM chain,
+// relative to the chain's base URI.
+var apiEndpoints = map[string]string{
+	consts.Name: JSONRPCEndpoint,
+	"core":      jsonrpc.Endpoint,
+	"indexer":   indexer.Endpoint,
+	"ws":        ws.Endpoint,
+	"state":     staterpc.Endpoint,
+	"metrics":   MetricsEndpoint,
+	"stream":    StreamEndpoint,
+}
+
 var _ api.HandlerFactory[api.VM] = (*jsonRPCServerFactory)(nil)
 
-type jsonRPCServerFactory struct{}
+type jsonRPCServerFactory struct {
+	config   Config
+	metrics  *metrics
+	retained *historyUsage
+	journal  *journal
+	archive  *archive
+	usage    *usage
+	treasury *treasuryHistory
+	assets   *assetHistory
+	activity *activity
+	logs     *eventLog
+	heatMap  *heatMap
+	attester *attester
+	upgrades *UpgradeFactory
+	sessions *sessionRequests
+	cursors  *cursorSigner
+}
 
-func (jsonRPCServerFactory) New(vm api.VM) (api.Handler, error) {
-	handler, err := api.NewJSONRPCHandler(consts.Name, NewJSONRPCServer(vm))
+func (f jsonRPCServerFactory) New(vm api.VM) (api.Handler, error) {
+	handler, err := newJSONRPCHandler(consts.Name, NewJSONRPCServer(vm, f.config, f.metrics, f.retained, f.journal, f.archive, f.usage, f.treasury, f.assets, f.activity, f.logs, f.heatMap, f.attester, f.upgrades, f.sessions, f.cursors))
 	return api.Handler{
 		Path:    JSONRPCEndpoint,
 		Handler: handler,
@@ -28,38 +123,2203 @@ func (jsonRPCServerFactory) New(vm api.VM) (api.Handler, error) {
 }
 
 type JSONRPCServer struct {
-	vm api.VM
+	vm       api.VM
+	config   Config
+	metrics  *metrics
+	history  historicalState
+	retained *historyUsage
+	journal  *journal
+	archive  *archive
+	usage    *usage
+	treasury *treasuryHistory
+	assets   *assetHistory
+	activity *activity
+	logs     *eventLog
+	heatMap  *heatMap
+	attester *attester
+	lists    *lists
+	upgrades *UpgradeFactory
+	sessions *sessionRequests
+}
+
+func NewJSONRPCServer(
+	vm api.VM,
+	config Config,
+	metrics *metrics,
+	retained *historyUsage,
+	journal *journal,
+	archive *archive,
+	usage *usage,
+	treasury *treasuryHistory,
+	assets *assetHistory,
+	activity *activity,
+	logs *eventLog,
+	heatMap *heatMap,
+	attester *attester,
+	upgrades *UpgradeFactory,
+	sessions *sessionRequests,
+	cursors *cursorSigner,
+) *JSONRPCServer {
+	history, _ := vm.(historicalState)
+	return &JSONRPCServer{
+		vm:       vm,
+		config:   config,
+		metrics:  metrics,
+		history:  history,
+		retained: retained,
+		journal:  journal,
+		archive:  archive,
+		usage:    usage,
+		treasury: treasury,
+		assets:   assets,
+		activity: activity,
+		logs:     logs,
+		heatMap:  heatMap,
+		attester: attester,
+		lists:    &lists{history: history, treasury: treasury, assets: assets, activity: activity, cursors: cursors, balanceExport: config.BalanceExport},
+		upgrades: upgrades,
+		sessions: sessions,
+	}
 }
 
-func NewJSONRPCServer(vm api.VM) *JSONRPCServer {
-	return &JSONRPCServer{vm: vm}
+// stateReader returns the [storage.ReadState] that serves [opts]. The height
+// of the state actually read is written to [height].
+func (j *JSONRPCServer) stateReader(opts ReadOptions, height *uint64) storage.ReadState {
+	if opts.Height == nil {
+		return storage.WithMinHeight(j.vm.ReadState, opts.MinHeight, height)
+	}
+	*height = *opts.Height
+	return j.readStateAt(*opts.Height)
 }
 
 type GenesisReply struct {
-	Genesis *genesis.DefaultGenesis `json:"genesis"`
+	Genesis *Genesis `json:"genesis"`
 }
 
 func (j *JSONRPCServer) Genesis(_ *http.Request, _ *struct{}, reply *GenesisReply) (err error) {
-	reply.Genesis = j.vm.Genesis().(*genesis.DefaultGenesis)
+	reply.Genesis = j.vm.Genesis().(*Genesis)
 	return nil
 }
 
+// ReadOptions are accepted by every method that reads chain state.
+type ReadOptions struct {
+	// MinHeight makes the read fail with [storage.ErrStaleState] instead of
+	// serving state older than this height.
+	MinHeight uint64 `json:"minHeight,omitempty"`
+
+	// Height pins the read to the state after the block at this height was
+	// accepted. Only recent heights are retained, unless the node runs in
+	// archival mode.
+	Height *uint64 `json:"height,omitempty"`
+}
+
 type BalanceArgs struct {
 	Address codec.Address `json:"address"`
+	ReadOptions
 }
 
 type BalanceReply struct {
 	Amount uint64 `json:"amount"`
+	Height uint64 `json:"height"`
 }
 
 func (j *JSONRPCServer) Balance(req *http.Request, args *BalanceArgs, reply *BalanceReply) error {
 	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Balance")
 	defer span.End()
 
-	balance, err := storage.GetBalanceFromState(ctx, j.vm.ReadState, args.Address)
+	balance, err := storage.GetBalanceFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Address)
+	if err != nil {
+		return err
+	}
+	reply.Amount = balance
+	return err
+}
+
+type BalancesArgs struct {
+	Addresses []codec.Address `json:"addresses"`
+	ReadOptions
+}
+
+type BalancesReply struct {
+	// Amounts are the native balances of the addresses, in order.
+	Amounts []uint64 `json:"amounts"`
+	Height  uint64   `json:"height"`
+}
+
+// Balances returns the native balances of a list of addresses, all read at
+// one height in a single state read.
+func (j *JSONRPCServer) Balances(req *http.Request, args *BalancesArgs, reply *BalancesReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Balances")
+	defer span.End()
+
+	if len(args.Addresses) > MaxBulkReads {
+		return ErrTooManyReads
+	}
+	balances, err := storage.GetBalancesFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Addresses)
+	if err != nil {
+		return err
+	}
+	reply.Amounts = balances
+	return nil
+}
+
+type AssetBalanceArgs struct {
+	Address codec.Address `json:"address"`
+	Asset   ids.ID        `json:"asset"`
+	ReadOptions
+}
+
+func (j *JSONRPCServer) AssetBalance(req *http.Request, args *AssetBalanceArgs, reply *BalanceReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.AssetBalance")
+	defer span.End()
+
+	balance, err := storage.GetAssetBalanceFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Address, args.Asset)
 	if err != nil {
 		return err
 	}
 	reply.Amount = balance
+	return nil
+}
+
+type AllowanceArgs struct {
+	Owner   codec.Address `json:"owner"`
+	Spender codec.Address `json:"spender"`
+	ReadOptions
+}
+
+func (j *JSONRPCServer) Allowance(req *http.Request, args *AllowanceArgs, reply *BalanceReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Allowance")
+	defer span.End()
+
+	allowance, err := storage.GetAllowanceFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Owner, args.Spender)
+	if err != nil {
+		return err
+	}
+	reply.Amount = allowance
+	return nil
+}
+
+type NotificationPrefsReply struct {
+	Prefs  storage.NotificationPrefs `json:"prefs"`
+	Height uint64                    `json:"height"`
+}
+
+// NotificationPrefs returns which events [Address] wants pushed. The event
+// stream pushes them to subscribers of their target; off-chain notifiers
+// can apply them the same way.
+func (j *JSONRPCServer) NotificationPrefs(req *http.Request, args *BalanceArgs, reply *NotificationPrefsReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.NotificationPrefs")
+	defer span.End()
+
+	prefs, err := storage.GetNotificationPrefsFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Address)
+	if err != nil {
+		return err
+	}
+	reply.Prefs = prefs
+	return nil
+}
+
+type AssetsByOwnerArgs struct {
+	Owner codec.Address `json:"owner"`
+	PageArgs
+}
+
+type AssetsByOwnerReply struct {
+	Assets []ids.ID `json:"assets"`
+	Page   Page     `json:"page"`
+}
+
+// AssetsByOwner lists the assets [Owner] holds in the last accepted state,
+// in asset ID order.
+func (j *JSONRPCServer) AssetsByOwner(req *http.Request, args *AssetsByOwnerArgs, reply *AssetsByOwnerReply) (err error) {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.AssetsByOwner")
+	defer span.End()
+
+	reply.Assets, reply.Page, err = j.lists.assetsByOwner(ctx, args.Owner, args.PageArgs)
+	return err
+}
+
+type ExportBalancesArgs struct {
+	PageArgs
+}
+
+// AccountBalance is the native balance of one address.
+type AccountBalance struct {
+	Address codec.Address `json:"address"`
+	Balance uint64        `json:"balance"`
+}
+
+type ExportBalancesReply struct {
+	Balances []AccountBalance `json:"balances"`
+	Page     Page             `json:"page"`
+}
+
+// ExportBalances lists the non-zero native balances of the last accepted
+// state, in address order. It is served only with the BalanceExport config
+// set.
+func (j *JSONRPCServer) ExportBalances(req *http.Request, args *ExportBalancesArgs, reply *ExportBalancesReply) (err error) {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.ExportBalances")
+	defer span.End()
+
+	reply.Balances, reply.Page, err = j.lists.exportBalances(ctx, args.PageArgs)
+	return err
+}
+
+type TreasuryReply struct {
+	Address codec.Address `json:"address"`
+	Balance uint64        `json:"balance"`
+	// Council is nil if the chain has no treasury council.
+	Council *storage.Council `json:"council"`
+	Height  uint64           `json:"height"`
+}
+
+func (j *JSONRPCServer) Treasury(req *http.Request, args *ReadOptions, reply *TreasuryReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Treasury")
+	defer span.End()
+
+	f := j.stateReader(*args, &reply.Height)
+	balance, err := storage.GetBalanceFromState(ctx, f, storage.TreasuryAddress)
+	if err != nil {
+		return err
+	}
+	council, err := storage.GetTreasuryCouncilFromState(ctx, f)
+	if err != nil {
+		return err
+	}
+	reply.Address = storage.TreasuryAddress
+	reply.Balance = balance
+	reply.Council = council
+	return nil
+}
+
+type TreasuryProposalArgs struct {
+	ProposalID ids.ID `json:"proposalId"`
+	ReadOptions
+}
+
+type TreasuryProposalReply struct {
+	Proposal *storage.TreasuryProposal `json:"proposal"`
+	Height   uint64                    `json:"height"`
+}
+
+// TreasuryProposal returns a pending proposal. Executed proposals are
+// removed from state and only appear in TreasuryHistory.
+func (j *JSONRPCServer) TreasuryProposal(req *http.Request, args *TreasuryProposalArgs, reply *TreasuryProposalReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.TreasuryProposal")
+	defer span.End()
+
+	proposal, exists, err := storage.GetTreasuryProposalFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.ProposalID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrProposalNotFound
+	}
+	reply.Proposal = proposal
+	return nil
+}
+
+type TreasuryHistoryArgs struct {
+	PageArgs
+}
+
+type TreasuryHistoryReply struct {
+	Movements []*TreasuryMovement `json:"movements"`
+	Page      Page                `json:"page"`
+}
+
+// TreasuryHistory returns treasury deposits and spends, newest first.
+func (j *JSONRPCServer) TreasuryHistory(_ *http.Request, args *TreasuryHistoryArgs, reply *TreasuryHistoryReply) (err error) {
+	reply.Movements, reply.Page, err = j.lists.treasuryHistory(args.PageArgs)
+	return err
+}
+
+type HaltStatusReply struct {
+	Halted bool `json:"halted"`
+	// Halt is nil unless the chain is halted.
+	Halt *storage.Halt `json:"halt"`
+	// Council is nil if the chain has no security council.
+	Council *storage.Council `json:"council"`
+	Height  uint64           `json:"height"`
+}
+
+// HaltStatus returns whether the chain is halted and its security council.
+func (j *JSONRPCServer) HaltStatus(req *http.Request, args *ReadOptions, reply *HaltStatusReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.HaltStatus")
+	defer span.End()
+
+	f := j.stateReader(*args, &reply.Height)
+	halt, halted, err := storage.GetHaltFromState(ctx, f)
+	if err != nil {
+		return err
+	}
+	council, err := storage.GetSecurityCouncilFromState(ctx, f)
+	if err != nil {
+		return err
+	}
+	reply.Halted = halted
+	reply.Halt = halt
+	reply.Council = council
+	return nil
+}
+
+type AssetHistoryArgs struct {
+	AssetID ids.ID `json:"assetId"`
+	PageArgs
+}
+
+type AssetHistoryReply struct {
+	Transfers []*AssetOwnerChange `json:"transfers"`
+	Page      Page                `json:"page"`
+}
+
+// AssetHistory returns the owner changes of an asset, including mints and
+// burns, newest first.
+func (j *JSONRPCServer) AssetHistory(_ *http.Request, args *AssetHistoryArgs, reply *AssetHistoryReply) (err error) {
+	reply.Transfers, reply.Page, err = j.lists.assetHistory(args.AssetID, args.PageArgs)
+	return err
+}
+
+type TxArgs struct {
+	TxID ids.ID `json:"txId"`
+}
+
+// GetTx returns an accepted transaction with its actions and outputs
+// decoded.
+func (j *JSONRPCServer) GetTx(_ *http.Request, args *TxArgs, reply *IndexedTx) error {
+	if j.activity == nil {
+		return fmt.Errorf("%w: index disabled", ErrActivityUnavailable)
+	}
+	tx, err := j.activity.Tx(args.TxID)
+	if err != nil {
+		return err
+	}
+	*reply = *tx
+	return nil
+}
+
+type TxsByAddressArgs struct {
+	Address codec.Address `json:"address"`
+	PageArgs
+}
+
+type TxsByAddressReply struct {
+	Txs  []*IndexedTx `json:"txs"`
+	Page Page         `json:"page"`
+}
+
+// GetTxsByAddress returns the accepted transactions that touched [Address]
+// as actor, sponsor or balance owner, newest first.
+func (j *JSONRPCServer) GetTxsByAddress(_ *http.Request, args *TxsByAddressArgs, reply *TxsByAddressReply) (err error) {
+	reply.Txs, reply.Page, err = j.lists.txsByAddress(args.Address, args.PageArgs)
+	return err
+}
+
+type LogsArgs struct {
+	FromHeight uint64 `json:"fromHeight"`
+	// ToHeight is included. Zero is the last indexed height.
+	ToHeight uint64 `json:"toHeight"`
+	LogFilter
+}
+
+type LogsReply struct {
+	Logs []*Log `json:"logs"`
+	// Last is the last height scanned. While it is below the requested
+	// ToHeight, continue from the height after it.
+	Last uint64 `json:"last"`
+}
+
+// GetLogs returns the events of accepted actions matching a filter, oldest
+// first.
+func (j *JSONRPCServer) GetLogs(_ *http.Request, args *LogsArgs, reply *LogsReply) (err error) {
+	if j.logs == nil {
+		return fmt.Errorf("%w: index disabled", ErrLogsUnavailable)
+	}
+	reply.Logs, reply.Last, err = j.logs.Logs(args.FromHeight, args.ToHeight, &args.LogFilter)
 	return err
 }
+
+type HeightReply struct {
+	Height uint64 `json:"height"`
+}
+
+// Height returns the height of the state reads are served from.
+func (j *JSONRPCServer) Height(req *http.Request, args *ReadOptions, reply *HeightReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Height")
+	defer span.End()
+
+	var served uint64
+	height, err := storage.GetHeightFromState(ctx, j.stateReader(*args, &served))
+	if err != nil {
+		return err
+	}
+	reply.Height = height
+	return nil
+}
+
+type TimestampReply struct {
+	// Timestamp is in milliseconds.
+	Timestamp int64  `json:"timestamp"`
+	Height    uint64 `json:"height"`
+}
+
+// Timestamp returns the timestamp of the block at the height reads are
+// served from.
+func (j *JSONRPCServer) Timestamp(req *http.Request, args *ReadOptions, reply *TimestampReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Timestamp")
+	defer span.End()
+
+	timestamp, err := storage.GetTimestampFromState(ctx, j.stateReader(*args, &reply.Height))
+	if err != nil {
+		return err
+	}
+	reply.Timestamp = timestamp
+	return nil
+}
+
+type AssetOwnerArgs struct {
+	Asset ids.ID `json:"asset"`
+	ReadOptions
+}
+
+type AssetOwnerReply struct {
+	Owner  codec.Address `json:"owner"`
+	Height uint64        `json:"height"`
+}
+
+func (j *JSONRPCServer) AssetOwner(req *http.Request, args *AssetOwnerArgs, reply *AssetOwnerReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.AssetOwner")
+	defer span.End()
+
+	owner, err := storage.GetAssetOwnerFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Asset)
+	if err != nil {
+		return err
+	}
+	reply.Owner = owner
+	return nil
+}
+
+type AssetOwnersArgs struct {
+	Assets []ids.ID `json:"assets"`
+	ReadOptions
+}
+
+type AssetOwnersReply struct {
+	// Owners are the owners of the assets, in order, empty for missing
+	// assets.
+	Owners []codec.Address `json:"owners"`
+	Height uint64          `json:"height"`
+}
+
+// AssetOwners returns the owners of a list of assets, all read at one
+// height in a single state read.
+func (j *JSONRPCServer) AssetOwners(req *http.Request, args *AssetOwnersArgs, reply *AssetOwnersReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.AssetOwners")
+	defer span.End()
+
+	if len(args.Assets) > MaxBulkReads {
+		return ErrTooManyReads
+	}
+	owners, err := storage.GetAssetOwnersFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Assets)
+	if err != nil {
+		return err
+	}
+	reply.Owners = owners
+	return nil
+}
+
+type VerifyOwnershipArgs struct {
+	Address codec.Address `json:"address"`
+	Assets  []ids.ID      `json:"assets"`
+	// Attest asks for the reply to be signed with the node's attestation
+	// key.
+	Attest bool `json:"attest"`
+	ReadOptions
+}
+
+type VerifyOwnershipReply struct {
+	// Owned reports, for each of the assets, whether the address owns it.
+	Owned       []bool                `json:"owned"`
+	Height      uint64                `json:"height"`
+	Attestation *OwnershipAttestation `json:"attestation,omitempty"`
+}
+
+// VerifyOwnership reports which of a list of assets an address owns, all
+// read at one height. Check attestations with [VerifyOwnershipAttestation].
+func (j *JSONRPCServer) VerifyOwnership(req *http.Request, args *VerifyOwnershipArgs, reply *VerifyOwnershipReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.VerifyOwnership")
+	defer span.End()
+
+	if len(args.Assets) > MaxOwnershipAssets {
+		return ErrTooManyOwnershipAssets
+	}
+	if args.Attest && j.attester == nil {
+		return fmt.Errorf("%w: no attestation key", ErrAttestationUnavailable)
+	}
+	owners, err := storage.GetAssetOwnersFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Assets)
+	if err != nil {
+		return err
+	}
+	reply.Owned = make([]bool, len(owners))
+	for i, owner := range owners {
+		reply.Owned[i] = owner == args.Address
+	}
+	if !args.Attest {
+		return nil
+	}
+	msg, err := OwnershipMessage(j.vm.ChainID(), reply.Height, args.Address, args.Assets, reply.Owned)
+	if err != nil {
+		return err
+	}
+	reply.Attestation = j.attester.attest(msg)
+	return nil
+}
+
+type AssetMetadataReply struct {
+	Owner    codec.Address         `json:"owner"`
+	Metadata storage.AssetMetadata `json:"metadata"`
+	Control  storage.AssetControl  `json:"control"`
+	Height   uint64                `json:"height"`
+}
+
+func (j *JSONRPCServer) AssetMetadata(req *http.Request, args *AssetOwnerArgs, reply *AssetMetadataReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.AssetMetadata")
+	defer span.End()
+
+	owner, metadata, control, err := storage.GetAssetFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Asset)
+	if err != nil {
+		return err
+	}
+	reply.Owner = owner
+	reply.Metadata = metadata
+	reply.Control = control
+	return nil
+}
+
+type TransferHookReply struct {
+	// Hook is nil if the asset has none.
+	Hook   *storage.TransferHook `json:"hook"`
+	Height uint64                `json:"height"`
+}
+
+func (j *JSONRPCServer) TransferHook(req *http.Request, args *AssetOwnerArgs, reply *TransferHookReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.TransferHook")
+	defer span.End()
+
+	hook, err := storage.GetTransferHookFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Asset)
+	if err != nil {
+		return err
+	}
+	reply.Hook = hook
+	return nil
+}
+
+type ReceiptReply struct {
+	// Receipt is nil if the asset is not a receipt.
+	Receipt *storage.Receipt `json:"receipt"`
+	Height  uint64           `json:"height"`
+}
+
+func (j *JSONRPCServer) Receipt(req *http.Request, args *AssetOwnerArgs, reply *ReceiptReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Receipt")
+	defer span.End()
+
+	receipt, _, err := storage.GetReceiptFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Asset)
+	if err != nil {
+		return err
+	}
+	reply.Receipt = receipt
+	return nil
+}
+
+type StablecoinReply struct {
+	Stablecoin *storage.Stablecoin `json:"stablecoin"`
+	Supply     uint64              `json:"supply"`
+	Height     uint64              `json:"height"`
+}
+
+func (j *JSONRPCServer) Stablecoin(req *http.Request, args *AssetOwnerArgs, reply *StablecoinReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Stablecoin")
+	defer span.End()
+
+	s, supply, exists, err := storage.GetStablecoinSupplyFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Asset)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrNotStablecoin
+	}
+	reply.Stablecoin = s
+	reply.Supply = supply
+	return nil
+}
+
+type StablecoinSupplyProofReply struct {
+	Supply   uint64 `json:"supply"`
+	Reserves uint64 `json:"reserves"`
+	// Backed is whether [Reserves] cover [Supply].
+	Backed     bool   `json:"backed"`
+	Report     ids.ID `json:"report"`
+	AttestedAt int64  `json:"attestedAt"`
+	// Height is the height both [Supply] and [Reserves] were read at.
+	Height uint64 `json:"height"`
+}
+
+// StablecoinSupplyProof returns the supply of a stablecoin next to its last
+// attested reserves, for auditors to check against the attestor's report.
+func (j *JSONRPCServer) StablecoinSupplyProof(req *http.Request, args *AssetOwnerArgs, reply *StablecoinSupplyProofReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.StablecoinSupplyProof")
+	defer span.End()
+
+	s, supply, exists, err := storage.GetStablecoinSupplyFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Asset)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrNotStablecoin
+	}
+	reply.Supply = supply
+	reply.Reserves = s.Reserves
+	reply.Backed = supply <= s.Reserves
+	reply.Report = s.Report
+	reply.AttestedAt = s.AttestedAt
+	return nil
+}
+
+type StablecoinBlockedArgs struct {
+	Asset   ids.ID        `json:"asset"`
+	Account codec.Address `json:"account"`
+	ReadOptions
+}
+
+type StablecoinBlockedReply struct {
+	Blocked bool   `json:"blocked"`
+	Height  uint64 `json:"height"`
+}
+
+func (j *JSONRPCServer) StablecoinBlocked(req *http.Request, args *StablecoinBlockedArgs, reply *StablecoinBlockedReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.StablecoinBlocked")
+	defer span.End()
+
+	blocked, err := storage.IsStablecoinBlockedFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Asset, args.Account)
+	if err != nil {
+		return err
+	}
+	reply.Blocked = blocked
+	return nil
+}
+
+type PermitUsedArgs struct {
+	Owner codec.Address `json:"owner"`
+	Nonce uint64        `json:"nonce"`
+	ReadOptions
+}
+
+type PermitUsedReply struct {
+	Used   bool   `json:"used"`
+	Height uint64 `json:"height"`
+}
+
+// PermitUsed returns whether a permit of an owner with a nonce has been
+// used, so marketplaces can drop listings that can no longer settle.
+func (j *JSONRPCServer) PermitUsed(req *http.Request, args *PermitUsedArgs, reply *PermitUsedReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.PermitUsed")
+	defer span.End()
+
+	used, err := storage.IsPermitUsedFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Owner, args.Nonce)
+	if err != nil {
+		return err
+	}
+	reply.Used = used
+	return nil
+}
+
+type SwapArgs struct {
+	SwapID ids.ID `json:"swapId"`
+	ReadOptions
+}
+
+type SwapReply struct {
+	Swap   *storage.Swap `json:"swap"`
+	Height uint64        `json:"height"`
+}
+
+// Swap returns an open swap. Accepted and refunded swaps are removed from
+// state.
+func (j *JSONRPCServer) Swap(req *http.Request, args *SwapArgs, reply *SwapReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Swap")
+	defer span.End()
+
+	swap, exists, err := storage.GetSwapFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.SwapID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrSwapNotFound
+	}
+	reply.Swap = swap
+	return nil
+}
+
+type EscrowArgs struct {
+	EscrowID ids.ID `json:"escrowId"`
+	ReadOptions
+}
+
+type EscrowReply struct {
+	Escrow *storage.Escrow `json:"escrow"`
+	Height uint64          `json:"height"`
+}
+
+// Escrow returns an open escrow. Released and refunded escrows are removed
+// from state.
+func (j *JSONRPCServer) Escrow(req *http.Request, args *EscrowArgs, reply *EscrowReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Escrow")
+	defer span.End()
+
+	escrow, exists, err := storage.GetEscrowFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.EscrowID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrEscrowNotFound
+	}
+	reply.Escrow = escrow
+	return nil
+}
+
+type VestingArgs struct {
+	Beneficiary codec.Address `json:"beneficiary"`
+	VestingID   ids.ID        `json:"vestingId"`
+	ReadOptions
+}
+
+type VestingReply struct {
+	Vesting *storage.Vesting `json:"vesting"`
+	Height  uint64           `json:"height"`
+}
+
+// Vesting returns a pending vesting. Claimed vestings are removed from state.
+func (j *JSONRPCServer) Vesting(req *http.Request, args *VestingArgs, reply *VestingReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Vesting")
+	defer span.End()
+
+	vesting, exists, err := storage.GetVestingFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Beneficiary, args.VestingID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrVestingNotFound
+	}
+	reply.Vesting = vesting
+	return nil
+}
+
+type VestingsArgs struct {
+	Beneficiary codec.Address `json:"beneficiary"`
+	PageArgs
+}
+
+type PendingVesting struct {
+	ID ids.ID `json:"id"`
+	storage.Vesting
+}
+
+type VestingsReply struct {
+	Vestings []PendingVesting `json:"vestings"`
+	Page     Page             `json:"page"`
+}
+
+// Vestings lists the pending vestings of [Beneficiary] in the last accepted
+// state, in vesting ID order.
+func (j *JSONRPCServer) Vestings(req *http.Request, args *VestingsArgs, reply *VestingsReply) (err error) {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Vestings")
+	defer span.End()
+
+	reply.Vestings, reply.Page, err = j.lists.vestings(ctx, args.Beneficiary, args.PageArgs)
+	return err
+}
+
+type OrdersArgs struct {
+	// SellAsset and BuyAsset select the pair. Use [storage.NativeAsset] for
+	// the native token.
+	SellAsset ids.ID `json:"sellAsset"`
+	BuyAsset  ids.ID `json:"buyAsset"`
+	PageArgs
+}
+
+type OpenOrder struct {
+	ID ids.ID `json:"id"`
+	storage.Order
+}
+
+type OrdersReply struct {
+	Orders []OpenOrder `json:"orders"`
+	Page   Page        `json:"page"`
+}
+
+// Orders lists the open orders selling [SellAsset] for [BuyAsset] in the
+// last accepted state, in order ID order.
+func (j *JSONRPCServer) Orders(req *http.Request, args *OrdersArgs, reply *OrdersReply) (err error) {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Orders")
+	defer span.End()
+
+	reply.Orders, reply.Page, err = j.lists.orders(ctx, args.SellAsset, args.BuyAsset, args.PageArgs)
+	return err
+}
+
+type NextOrderArgs struct {
+	Maker codec.Address `json:"maker"`
+	ReadOptions
+}
+
+type NextOrderReply struct {
+	// Sequence is what CreateOrder must set for the next order of the maker,
+	// which will have the ID [OrderID].
+	Sequence uint64 `json:"sequence"`
+	OrderID  ids.ID `json:"orderId"`
+	Height   uint64 `json:"height"`
+}
+
+// NextOrder returns the sequence and ID of the next order of a maker.
+func (j *JSONRPCServer) NextOrder(req *http.Request, args *NextOrderArgs, reply *NextOrderReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.NextOrder")
+	defer span.End()
+
+	sequence, err := storage.PeekSequenceFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), storage.OrderSequenceKey(args.Maker))
+	if err != nil {
+		return err
+	}
+	reply.Sequence = sequence
+	reply.OrderID = storage.OrderID(args.Maker, sequence)
+	return nil
+}
+
+type LiveAuctionsArgs struct {
+	// IncludeEnded also lists auctions that stopped taking bids but are not
+	// settled yet.
+	IncludeEnded bool `json:"includeEnded"`
+	PageArgs
+}
+
+type LiveAuction struct {
+	Asset ids.ID `json:"asset"`
+	storage.Auction
+}
+
+type LiveAuctionsReply struct {
+	Auctions []LiveAuction `json:"auctions"`
+	Page     Page          `json:"page"`
+}
+
+// LiveAuctions lists the auctions that still take bids in the last
+// accepted state, in asset ID order. Ended is judged by the node's clock.
+func (j *JSONRPCServer) LiveAuctions(req *http.Request, args *LiveAuctionsArgs, reply *LiveAuctionsReply) (err error) {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.LiveAuctions")
+	defer span.End()
+
+	reply.Auctions, reply.Page, err = j.lists.liveAuctions(ctx, args.IncludeEnded, args.PageArgs)
+	return err
+}
+
+type AppArgs struct {
+	// Name is the name the app was registered under.
+	Name string `json:"name"`
+	ReadOptions
+}
+
+type AppReply struct {
+	App    ids.ID        `json:"app"`
+	Admin  codec.Address `json:"admin"`
+	Height uint64        `json:"height"`
+}
+
+// App returns the ID and admin of the app registered under a name.
+func (j *JSONRPCServer) App(req *http.Request, args *AppArgs, reply *AppReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.App")
+	defer span.End()
+
+	reply.App = storage.AppID(args.Name)
+	admin, exists, err := storage.GetAppAdminFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), reply.App)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrAppNotFound
+	}
+	reply.Admin = admin
+	return nil
+}
+
+type AppAssetsArgs struct {
+	App ids.ID `json:"app"`
+	PageArgs
+}
+
+type AppAssetsReply struct {
+	Assets []ids.ID `json:"assets"`
+	Page   Page     `json:"page"`
+}
+
+// AppAssets lists the assets created in [App] that exist in the last
+// accepted state, in asset ID order.
+func (j *JSONRPCServer) AppAssets(req *http.Request, args *AppAssetsArgs, reply *AppAssetsReply) (err error) {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.AppAssets")
+	defer span.End()
+
+	reply.Assets, reply.Page, err = j.lists.appAssets(ctx, args.App, args.PageArgs)
+	return err
+}
+
+type AppCreatorsArgs struct {
+	App ids.ID `json:"app"`
+	PageArgs
+}
+
+type AppCreatorsReply struct {
+	Creators []codec.Address `json:"creators"`
+	Page     Page            `json:"page"`
+}
+
+// AppCreators lists the creators the admin of [App] allowed in the last
+// accepted state, in address order. The admin is not listed.
+func (j *JSONRPCServer) AppCreators(req *http.Request, args *AppCreatorsArgs, reply *AppCreatorsReply) (err error) {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.AppCreators")
+	defer span.End()
+
+	reply.Creators, reply.Page, err = j.lists.appCreators(ctx, args.App, args.PageArgs)
+	return err
+}
+
+type PoolArgs struct {
+	// AssetA and AssetB select the pool, in either order. Use
+	// [storage.NativeAsset] for the native token.
+	AssetA ids.ID `json:"assetA"`
+	AssetB ids.ID `json:"assetB"`
+	ReadOptions
+}
+
+type PoolReply struct {
+	// AssetA and AssetB are the pool assets in pool order, which
+	// [storage.Pool] reserves follow.
+	AssetA ids.ID        `json:"assetA"`
+	AssetB ids.ID        `json:"assetB"`
+	Pool   *storage.Pool `json:"pool"`
+	Height uint64        `json:"height"`
+}
+
+// Pool returns the reserves and issued shares of the pool of a pair.
+func (j *JSONRPCServer) Pool(req *http.Request, args *PoolArgs, reply *PoolReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Pool")
+	defer span.End()
+
+	reply.AssetA, reply.AssetB = sortPair(args.AssetA, args.AssetB)
+	pool, exists, err := storage.GetPoolFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), reply.AssetA, reply.AssetB)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrPoolNotFound
+	}
+	reply.Pool = pool
+	return nil
+}
+
+type PoolSharesArgs struct {
+	AssetA   ids.ID        `json:"assetA"`
+	AssetB   ids.ID        `json:"assetB"`
+	Provider codec.Address `json:"provider"`
+	ReadOptions
+}
+
+type PoolSharesReply struct {
+	Shares uint64 `json:"shares"`
+	Height uint64 `json:"height"`
+}
+
+// PoolShares returns the LP shares [Provider] holds in the pool of a pair.
+func (j *JSONRPCServer) PoolShares(req *http.Request, args *PoolSharesArgs, reply *PoolSharesReply) (err error) {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.PoolShares")
+	defer span.End()
+
+	assetA, assetB := sortPair(args.AssetA, args.AssetB)
+	reply.Shares, err = storage.GetPoolSharesFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), assetA, assetB, args.Provider)
+	return err
+}
+
+// spotPriceDecimals is the precision of [SpotPriceReply.Price].
+const spotPriceDecimals = 18
+
+type SpotPriceArgs struct {
+	// Base is priced in units of Quote.
+	Base  ids.ID `json:"base"`
+	Quote ids.ID `json:"quote"`
+	ReadOptions
+}
+
+type SpotPriceReply struct {
+	// Price is how many base units of [Quote] one base unit of [Base] is
+	// worth at the current reserves, before fees and price impact, as a
+	// decimal string.
+	Price  string `json:"price"`
+	Height uint64 `json:"height"`
+}
+
+// SpotPrice returns the marginal price of [Base] in the pool it shares with
+// [Quote].
+func (j *JSONRPCServer) SpotPrice(req *http.Request, args *SpotPriceArgs, reply *SpotPriceReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.SpotPrice")
+	defer span.End()
+
+	if args.Base == args.Quote {
+		return actions.ErrSameAsset
+	}
+	assetA, assetB := sortPair(args.Base, args.Quote)
+	pool, exists, err := storage.GetPoolFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), assetA, assetB)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrPoolNotFound
+	}
+	base, quote := pool.ReserveA, pool.ReserveB
+	if args.Base != assetA {
+		base, quote = quote, base
+	}
+	price := new(big.Rat).SetFrac(new(big.Int).SetUint64(quote), new(big.Int).SetUint64(base))
+	reply.Price = price.FloatString(spotPriceDecimals)
+	return nil
+}
+
+type StakeArgs struct {
+	Staker codec.Address `json:"staker"`
+	ReadOptions
+}
+
+type StakeReply struct {
+	Staked uint64 `json:"staked"`
+	// PendingRewards are what ClaimRewards would pay in the next block,
+	// under the rules in effect now.
+	PendingRewards uint64 `json:"pendingRewards"`
+	// Epoch is the staking epoch of the next block.
+	Epoch  uint64 `json:"epoch"`
+	Height uint64 `json:"height"`
+}
+
+// Stake returns the native tokens [Staker] has staked and the rewards it
+// could claim.
+func (j *JSONRPCServer) Stake(req *http.Request, args *StakeArgs, reply *StakeReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Stake")
+	defer span.End()
+
+	f := j.stateReader(args.ReadOptions, &reply.Height)
+	stake, exists, err := storage.GetStakeFromState(ctx, f, args.Staker)
+	if err != nil {
+		return err
+	}
+	r := j.vm.Rules(time.Now().UnixMilli())
+	reply.Epoch = actions.StakingEpoch(r, reply.Height+1)
+	if !exists {
+		return nil
+	}
+	rate, err := configUint64(ctx, r, f, actions.StakingRewardRateRule)
+	if err != nil {
+		return err
+	}
+	reply.Staked = stake.Staked()
+	reply.PendingRewards, err = actions.PendingRewards(rate, stake, reply.Epoch)
+	return err
+}
+
+type GovernanceProposalArgs struct {
+	ProposalID ids.ID `json:"proposalId"`
+	// Voter, if set, also returns the vote it cast on the proposal.
+	Voter *codec.Address `json:"voter,omitempty"`
+	ReadOptions
+}
+
+type GovernanceProposalReply struct {
+	Proposal *storage.GovernanceProposal `json:"proposal"`
+	Vote     *storage.GovernanceVote     `json:"vote,omitempty"`
+	Height   uint64                      `json:"height"`
+}
+
+// GovernanceProposal returns a governance proposal and its tally so far.
+func (j *JSONRPCServer) GovernanceProposal(req *http.Request, args *GovernanceProposalArgs, reply *GovernanceProposalReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.GovernanceProposal")
+	defer span.End()
+
+	f := j.stateReader(args.ReadOptions, &reply.Height)
+	proposal, exists, err := storage.GetGovernanceProposalFromState(ctx, f, args.ProposalID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrProposalNotFound
+	}
+	reply.Proposal = proposal
+	if args.Voter == nil {
+		return nil
+	}
+	reply.Vote, _, err = storage.GetGovernanceVoteFromState(ctx, f, args.ProposalID, *args.Voter)
+	return err
+}
+
+type GovernanceParametersReply struct {
+	// Parameters maps each numeric entry of the config registry to the
+	// value in effect: the one set by governance, or else the one in the
+	// rules. Addresses does the same for the entries that name an address.
+	Parameters map[string]uint64        `json:"parameters"`
+	Addresses  map[string]codec.Address `json:"addresses"`
+	// Active is the proposal open for votes or awaiting execution, if any.
+	Active *storage.ActiveProposal `json:"active,omitempty"`
+	Height uint64                  `json:"height"`
+}
+
+// GovernanceParameters returns the entries of the config registry, which
+// governance can change.
+func (j *JSONRPCServer) GovernanceParameters(req *http.Request, args *ReadOptions, reply *GovernanceParametersReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.GovernanceParameters")
+	defer span.End()
+
+	f := j.stateReader(*args, &reply.Height)
+	r := j.vm.Rules(time.Now().UnixMilli())
+	reply.Parameters = map[string]uint64{}
+	reply.Addresses = map[string]codec.Address{}
+	for key, entry := range actions.Config {
+		v, err := configValue(ctx, r, f, key)
+		if err != nil {
+			return err
+		}
+		switch entry.Kind {
+		case actions.ConfigAddress:
+			reply.Addresses[key], err = actions.DecodeConfigAddress(v)
+		default:
+			reply.Parameters[key], err = storage.DecodeConfigUint64(v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	var err error
+	reply.Active, _, err = storage.GetActiveProposalFromState(ctx, f)
+	return err
+}
+
+type MultisigArgs struct {
+	Multisig codec.Address `json:"multisig"`
+	// ProposalID, if set, also returns that pending proposal.
+	ProposalID *ids.ID `json:"proposalId,omitempty"`
+	ReadOptions
+}
+
+type MultisigReply struct {
+	Multisig *storage.Multisig         `json:"multisig"`
+	Balance  uint64                    `json:"balance"`
+	Proposal *storage.MultisigProposal `json:"proposal,omitempty"`
+	Height   uint64                    `json:"height"`
+}
+
+// Multisig returns the signer set and native balance of a multisig.
+// Executed proposals are removed from state.
+func (j *JSONRPCServer) Multisig(req *http.Request, args *MultisigArgs, reply *MultisigReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Multisig")
+	defer span.End()
+
+	f := j.stateReader(args.ReadOptions, &reply.Height)
+	multisig, exists, err := storage.GetMultisigFromState(ctx, f, args.Multisig)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrMultisigNotFound
+	}
+	balance, err := storage.GetBalanceFromState(ctx, f, args.Multisig)
+	if err != nil {
+		return err
+	}
+	reply.Multisig = multisig
+	reply.Balance = balance
+	if args.ProposalID == nil {
+		return nil
+	}
+	proposal, exists, err := storage.GetMultisigProposalFromState(ctx, f, args.Multisig, *args.ProposalID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrProposalNotFound
+	}
+	reply.Proposal = proposal
+	return nil
+}
+
+type SmartAccountArgs struct {
+	Account codec.Address `json:"account"`
+	// RequestID, if set, also returns that pending request.
+	RequestID *ids.ID `json:"requestId,omitempty"`
+	ReadOptions
+}
+
+type SmartAccountReply struct {
+	Account *storage.SmartAccount     `json:"account"`
+	Balance uint64                    `json:"balance"`
+	Request *storage.MultisigProposal `json:"request,omitempty"`
+	Height  uint64                    `json:"height"`
+}
+
+// SmartAccount returns the policy, session spending and native balance of
+// a smart account. Executed requests are removed from state.
+func (j *JSONRPCServer) SmartAccount(req *http.Request, args *SmartAccountArgs, reply *SmartAccountReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.SmartAccount")
+	defer span.End()
+
+	f := j.stateReader(args.ReadOptions, &reply.Height)
+	account, exists, err := storage.GetSmartAccountFromState(ctx, f, args.Account)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrSmartAccountNotFound
+	}
+	balance, err := storage.GetBalanceFromState(ctx, f, args.Account)
+	if err != nil {
+		return err
+	}
+	reply.Account = account
+	reply.Balance = balance
+	if args.RequestID == nil {
+		return nil
+	}
+	request, exists, err := storage.GetMultisigProposalFromState(ctx, f, args.Account, *args.RequestID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrProposalNotFound
+	}
+	reply.Request = request
+	return nil
+}
+
+type ClaimArgs struct {
+	Owner codec.Address `json:"owner"`
+	Key   codec.Bytes   `json:"key"`
+	ReadOptions
+}
+
+type ClaimReply struct {
+	Claim  *storage.Claim `json:"claim"`
+	Height uint64         `json:"height"`
+}
+
+// Claim returns a claim that has not expired.
+func (j *JSONRPCServer) Claim(req *http.Request, args *ClaimArgs, reply *ClaimReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Claim")
+	defer span.End()
+
+	claim, exists, err := storage.GetClaimFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Owner, args.Key)
+	if err != nil {
+		return err
+	}
+	if !exists || claim.Expiry < time.Now().UnixMilli() {
+		return actions.ErrClaimNotFound
+	}
+	reply.Claim = claim
+	return nil
+}
+
+type RequestSessionArgs struct {
+	// Owner is the address the dapp asks to act for.
+	Owner       codec.Address `json:"owner"`
+	Key         codec.Address `json:"key"`
+	ActionTypes []uint8       `json:"actionTypes"`
+	SpendCap    uint64        `json:"spendCap"`
+	Expiry      int64         `json:"expiry"`
+}
+
+type RequestSessionReply struct {
+	SessionID ids.ID `json:"sessionId"`
+}
+
+// RequestSession records a dapp's request for a session of [Owner], for
+// the owner's wallet to read with SessionRequests. The session is active
+// once the wallet submits the matching AuthorizeSessionKey action.
+func (j *JSONRPCServer) RequestSession(req *http.Request, args *RequestSessionArgs, reply *RequestSessionReply) error {
+	_, span := j.vm.Tracer().Start(req.Context(), "Server.RequestSession")
+	defer span.End()
+
+	if args.Key == codec.EmptyAddress || args.Key == args.Owner {
+		return actions.ErrInvalidSessionKey
+	}
+	if err := actions.VerifySessionActionTypes(args.ActionTypes); err != nil {
+		return err
+	}
+	now := time.Now()
+	if args.Expiry <= now.UnixMilli() {
+		return actions.ErrSessionExpired
+	}
+	request := &SessionRequest{
+		Key:         args.Key,
+		ActionTypes: args.ActionTypes,
+		SpendCap:    args.SpendCap,
+		Expiry:      args.Expiry,
+	}
+	if err := j.sessions.add(args.Owner, request, now); err != nil {
+		return err
+	}
+	reply.SessionID = request.ID
+	return nil
+}
+
+type SessionRequestsArgs struct {
+	Owner codec.Address `json:"owner"`
+}
+
+type SessionRequestsReply struct {
+	Requests []*SessionRequest `json:"requests"`
+}
+
+// SessionRequests returns the session requests of [Owner] that this node
+// received in the last ten minutes, oldest first. Requests that were
+// already authorized are left out.
+func (j *JSONRPCServer) SessionRequests(req *http.Request, args *SessionRequestsArgs, reply *SessionRequestsReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.SessionRequests")
+	defer span.End()
+
+	reply.Requests = []*SessionRequest{}
+	for _, request := range j.sessions.get(args.Owner, time.Now()) {
+		_, exists, err := storage.GetSessionFromState(ctx, j.vm.ReadState, args.Owner, request.ID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			reply.Requests = append(reply.Requests, request)
+		}
+	}
+	return nil
+}
+
+type ActiveSessionsArgs struct {
+	Owner codec.Address `json:"owner"`
+	PageArgs
+}
+
+type ActiveSession struct {
+	ID ids.ID `json:"id"`
+	storage.Session
+}
+
+type ActiveSessionsReply struct {
+	Sessions []ActiveSession `json:"sessions"`
+	Page     Page            `json:"page"`
+}
+
+// ActiveSessions lists the sessions [Owner] authorized that have not
+// expired or been revoked in the last accepted state, in session ID order.
+func (j *JSONRPCServer) ActiveSessions(req *http.Request, args *ActiveSessionsArgs, reply *ActiveSessionsReply) (err error) {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.ActiveSessions")
+	defer span.End()
+
+	reply.Sessions, reply.Page, err = j.lists.activeSessions(ctx, args.Owner, args.PageArgs)
+	return err
+}
+
+// configValue returns the value of config entry [key] like
+// actions.ConfigValue, reading state with [f].
+func configValue(ctx context.Context, r chain.Rules, f storage.ReadState, key string) ([]byte, error) {
+	v, exists, err := storage.GetConfigFromState(ctx, f, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return actions.Config[key].Default(r), nil
+	}
+	return v, nil
+}
+
+func configUint64(ctx context.Context, r chain.Rules, f storage.ReadState, key string) (uint64, error) {
+	v, err := configValue(ctx, r, f, key)
+	if err != nil {
+		return 0, err
+	}
+	return storage.DecodeConfigUint64(v)
+}
+
+// sortPair returns [a] and [b] in pool order.
+func sortPair(a ids.ID, b ids.ID) (ids.ID, ids.ID) {
+	if storage.SortedPair(a, b) {
+		return a, b
+	}
+	return b, a
+}
+
+type EconomicSummaryArgs struct {
+	// Top is the number of largest balances hashed. Zero selects
+	// [DefaultSummaryTop], and values above [MaxSummaryTop] are capped.
+	Top int `json:"top"`
+	// MinHeight makes the call fail with [storage.ErrStaleState] instead of
+	// summarizing state older than this height.
+	MinHeight uint64 `json:"minHeight,omitempty"`
+}
+
+// EconomicSummary condenses the native token economy of the last accepted
+// state. It scans every balance, so it is meant for devnets and CI rather
+// than production nodes.
+func (j *JSONRPCServer) EconomicSummary(req *http.Request, args *EconomicSummaryArgs, reply *storage.Summary) error {
+	_, span := j.vm.Tracer().Start(req.Context(), "Server.EconomicSummary")
+	defer span.End()
+
+	if j.history == nil {
+		return ErrStateIterationUnavailable
+	}
+	db, err := j.history.State()
+	if err != nil {
+		return err
+	}
+	top := args.Top
+	switch {
+	case top <= 0:
+		top = DefaultSummaryTop
+	case top > MaxSummaryTop:
+		top = MaxSummaryTop
+	}
+	summary, err := storage.Summarize(db, top)
+	if err != nil {
+		return err
+	}
+	if summary.Height < args.MinHeight {
+		return fmt.Errorf("%w: height=%d, minHeight=%d", storage.ErrStaleState, summary.Height, args.MinHeight)
+	}
+	*reply = *summary
+	return nil
+}
+
+type StateRetentionReply struct {
+	// Mode is the state mode of the node, "pruned" or "archival".
+	Mode string `json:"mode"`
+	// HistoryWindow is the number of heights retained behind [LastAccepted]
+	// in memory. In archival mode, older heights are read from the archive.
+	HistoryWindow uint64 `json:"historyWindow"`
+	LastAccepted  uint64 `json:"lastAccepted"`
+	// OldestHeight is the oldest height currently readable with a pinned
+	// height.
+	OldestHeight uint64 `json:"oldestHeight"`
+	// HistoryBytes is an upper bound of the memory the state changes of the
+	// blocks within [HistoryWindow] retain, counting the blocks accepted
+	// since the node started.
+	HistoryBytes uint64 `json:"historyBytes"`
+}
+
+func (j *JSONRPCServer) StateRetention(_ *http.Request, _ *struct{}, reply *StateRetentionReply) error {
+	lastAccepted := j.vm.LastAcceptedBlock().Hght
+	reply.Mode = j.config.stateMode()
+	reply.HistoryWindow = j.config.HistoryWindow
+	reply.LastAccepted = lastAccepted
+	reply.OldestHeight = lastAccepted
+	if j.history != nil {
+		reply.OldestHeight -= min(lastAccepted, j.config.HistoryWindow)
+	}
+	if j.retained != nil {
+		reply.HistoryBytes = j.retained.Bytes()
+	}
+	if j.archive != nil {
+		oldest, ok, err := j.archive.Oldest()
+		if err != nil {
+			return err
+		}
+		if ok {
+			reply.OldestHeight = min(reply.OldestHeight, oldest)
+		}
+	}
+	return nil
+}
+
+type GetProofArgs struct {
+	// Key is a state key, such as a [storage.BalanceKey] or a
+	// [storage.AssetKey].
+	Key codec.Bytes `json:"key"`
+	// Height selects the state after the block at this height. It
+	// defaults to the newest height whose root a block has committed to,
+	// the one before the last accepted.
+	Height *uint64 `json:"height,omitempty"`
+}
+
+type GetProofReply struct {
+	Height uint64 `json:"height"`
+	// BlockID is the block after [Height], whose StateRoot is [StateRoot].
+	BlockID   ids.ID `json:"blockId"`
+	StateRoot ids.ID `json:"stateRoot"`
+	// BranchFactor is the branch factor of the state trie, from the
+	// genesis.
+	BranchFactor merkledb.BranchFactor `json:"branchFactor"`
+	Value        codec.Bytes           `json:"value,omitempty"`
+	Exists       bool                  `json:"exists"`
+	// Proof proves [Value] or, if it does not exist, the absence of
+	// [Key]. Check it with [proof.Verify].
+	Proof codec.Bytes `json:"proof"`
+}
+
+// GetProof returns a Merkle proof of the value of a state key against the
+// state root of a block. Heights are readable as far back as the merkledb
+// history goes, even in archival mode.
+func (j *JSONRPCServer) GetProof(req *http.Request, args *GetProofArgs, reply *GetProofReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.GetProof")
+	defer span.End()
+
+	lastAccepted := j.vm.LastAcceptedBlock().Hght
+	if lastAccepted == 0 {
+		return fmt.Errorf("%w: no block has committed to a state root", ErrHistoryUnavailable)
+	}
+	height := lastAccepted - 1
+	if args.Height != nil {
+		height = *args.Height
+	}
+	if height >= lastAccepted {
+		return fmt.Errorf("%w: height=%d, lastAccepted=%d", ErrHeightNotAccepted, height, lastAccepted)
+	}
+	if j.history == nil {
+		return ErrHistoryUnavailable
+	}
+	next, p, err := proveHistorical(ctx, j.history, height, args.Key)
+	if err != nil {
+		return err
+	}
+	reply.Proof, err = proof.Marshal(p)
+	if err != nil {
+		return err
+	}
+	reply.Height = height
+	reply.BlockID = next.ID()
+	reply.StateRoot = next.StateRoot
+	reply.BranchFactor = j.vm.Genesis().GetStateBranchFactor()
+	for _, kv := range p.KeyValues {
+		if string(kv.Key) == string(args.Key) {
+			reply.Value, reply.Exists = kv.Value, true
+		}
+	}
+	return nil
+}
+
+type MaintenanceReply struct {
+	// Address is the maintenance address of the current rules, or empty if
+	// maintenance is disabled.
+	Address codec.Address `json:"address"`
+	// CompactionHeight is the last compaction marker. Indexes may drop
+	// history below it.
+	CompactionHeight uint64 `json:"compactionHeight"`
+	Height           uint64 `json:"height"`
+}
+
+func (j *JSONRPCServer) Maintenance(req *http.Request, args *ReadOptions, reply *MaintenanceReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Maintenance")
+	defer span.End()
+
+	compaction, err := storage.GetCompactionHeightFromState(ctx, j.stateReader(*args, &reply.Height))
+	if err != nil {
+		return err
+	}
+	reply.Address = actions.MaintenanceRules(j.vm.Rules(time.Now().UnixMilli()))
+	reply.CompactionHeight = compaction
+	return nil
+}
+
+type StateJournalArgs struct {
+	Height uint64 `json:"height"`
+}
+
+// StateJournal returns the state mutations made by the block at [Height].
+// The last accepted block is not journaled until its child is accepted.
+func (j *JSONRPCServer) StateJournal(_ *http.Request, args *StateJournalArgs, reply *BlockJournal) error {
+	if j.journal == nil {
+		return fmt.Errorf("%w: journal disabled", ErrJournalUnavailable)
+	}
+	bj, err := j.journal.Get(args.Height)
+	if err != nil {
+		return err
+	}
+	*reply = *bj
+	return nil
+}
+
+type BlockUsageArgs struct {
+	Height uint64 `json:"height"`
+}
+
+// BlockUsage returns the units consumed by the block at [Height], per
+// dimension and per action type, and the unit price changes that followed.
+func (j *JSONRPCServer) BlockUsage(_ *http.Request, args *BlockUsageArgs, reply *BlockUsage) error {
+	if j.usage == nil {
+		return fmt.Errorf("%w: usage reports disabled", ErrBlockUsageUnavailable)
+	}
+	bu, err := j.usage.Get(args.Height)
+	if err != nil {
+		return err
+	}
+	*reply = *bu
+	return nil
+}
+
+type SuggestFeeArgs struct {
+	// Blocks is how many recent blocks to sample, [DefaultFeeBlocks] if
+	// zero. At most the usage window is sampled.
+	Blocks uint64 `json:"blocks,omitempty"`
+	// Percentile of the sampled unit prices to suggest in each dimension,
+	// [DefaultFeePercentile] if zero.
+	Percentile uint64 `json:"percentile,omitempty"`
+
+	// Type is the name of the action to suggest a max fee for, such as
+	// "Transfer", and Action its JSON. Without a type, only unit prices are
+	// suggested.
+	Type   string          `json:"type,omitempty"`
+	Action json.RawMessage `json:"action,omitempty"`
+	Actor  codec.Address   `json:"actor"`
+	// Auth is the key type of the signer, "ed25519" if empty.
+	Auth string `json:"auth,omitempty"`
+}
+
+type SuggestFeeReply struct {
+	// Blocks is the number of blocks sampled.
+	Blocks int `json:"blocks"`
+	// UnitPrices are the current unit prices and SuggestedUnitPrices the
+	// percentile of the sampled ones, never below the current ones.
+	UnitPrices          fees.Dimensions `json:"unitPrices"`
+	SuggestedUnitPrices fees.Dimensions `json:"suggestedUnitPrices"`
+	// Units and MaxFee are set if the args name an action. Units are
+	// estimated like those of transactions built by the CLI, and MaxFee
+	// prices them at the suggested unit prices.
+	Units  *fees.Dimensions `json:"units,omitempty"`
+	MaxFee uint64           `json:"maxFee"`
+}
+
+// SuggestFee suggests unit prices from the prices of recent blocks, and a
+// max fee for a transaction of one action at them.
+func (j *JSONRPCServer) SuggestFee(req *http.Request, args *SuggestFeeArgs, reply *SuggestFeeReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.SuggestFee")
+	defer span.End()
+
+	if j.usage == nil {
+		return fmt.Errorf("%w: usage reports disabled", ErrBlockUsageUnavailable)
+	}
+	percentile := args.Percentile
+	if percentile == 0 {
+		percentile = DefaultFeePercentile
+	}
+	if percentile > 100 {
+		return ErrInvalidPercentile
+	}
+	blocks := args.Blocks
+	if blocks == 0 {
+		blocks = DefaultFeeBlocks
+	}
+	blocks = min(blocks, j.config.UsageWindow)
+
+	unitPrices, err := j.vm.UnitPrices(ctx)
+	if err != nil {
+		return err
+	}
+	var history []fees.Dimensions
+	for height := j.vm.LastAcceptedBlock().Hght; uint64(len(history)) < blocks && height > 0; height-- {
+		bu, err := j.usage.Get(height)
+		if errors.Is(err, ErrBlockUsageUnavailable) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		history = append(history, bu.UnitPrices)
+	}
+	reply.Blocks = len(history)
+	reply.UnitPrices = unitPrices
+	reply.SuggestedUnitPrices = suggestUnitPrices(history, unitPrices, percentile)
+	if args.Type == "" {
+		return nil
+	}
+
+	rt, ok := actionTypes()[args.Type]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownAction, args.Type)
+	}
+	action := reflect.New(rt).Interface().(chain.Action)
+	if len(args.Action) > 0 {
+		if err := json.Unmarshal(args.Action, action); err != nil {
+			return err
+		}
+	}
+	factory, err := newUnitsFactory(args.Auth, args.Actor)
+	if err != nil {
+		return err
+	}
+	units, err := chain.EstimateUnits(j.vm.Rules(time.Now().UnixMilli()), []chain.Action{action}, factory)
+	if err != nil {
+		return err
+	}
+	reply.Units = &units
+	reply.MaxFee, err = fees.MulSum(reply.SuggestedUnitPrices, units)
+	return err
+}
+
+type StateHeatMapReply struct {
+	// Height is the last block counted.
+	Height uint64         `json:"height"`
+	Cells  []*HeatMapCell `json:"cells"`
+}
+
+// StateHeatMap returns the state key accesses of the transactions accepted
+// since the node started, by record type and key bucket.
+func (j *JSONRPCServer) StateHeatMap(_ *http.Request, _ *struct{}, reply *StateHeatMapReply) error {
+	if j.heatMap == nil {
+		return fmt.Errorf("%w: heat map disabled", ErrHeatMapUnavailable)
+	}
+	reply.Cells, reply.Height = j.heatMap.Cells()
+	return nil
+}
+
+type UpgradesReply struct {
+	Upgrades []UpgradeStatus `json:"upgrades"`
+	// Ready is false if any scheduled upgrade is not supported by this node.
+	Ready bool `json:"ready"`
+}
+
+// Upgrades reports the scheduled rule upgrades and whether this node can run
+// past each of them.
+func (j *JSONRPCServer) Upgrades(_ *http.Request, _ *struct{}, reply *UpgradesReply) error {
+	reply.Ready = true
+	if j.upgrades == nil {
+		return nil
+	}
+	reply.Upgrades = j.upgrades.Status(time.Now().UnixMilli())
+	for _, u := range reply.Upgrades {
+		reply.Ready = reply.Ready && u.Supported
+	}
+	return nil
+}
+
+type FeeMetadata struct {
+	UnitPrices                 fees.Dimensions `json:"unitPrices"`
+	MinUnitPrice               fees.Dimensions `json:"minUnitPrice"`
+	UnitPriceChangeDenominator fees.Dimensions `json:"unitPriceChangeDenominator"`
+	WindowTargetUnits          fees.Dimensions `json:"windowTargetUnits"`
+	MaxBlockUnits              fees.Dimensions `json:"maxBlockUnits"`
+	BaseComputeUnits           uint64          `json:"baseComputeUnits"`
+	StorageKeyReadUnits        uint64          `json:"storageKeyReadUnits"`
+	StorageValueReadUnits      uint64          `json:"storageValueReadUnits"`
+	StorageKeyAllocateUnits    uint64          `json:"storageKeyAllocateUnits"`
+	StorageValueAllocateUnits  uint64          `json:"storageValueAllocateUnits"`
+	StorageKeyWriteUnits       uint64          `json:"storageKeyWriteUnits"`
+	StorageValueWriteUnits     uint64          `json:"storageValueWriteUnits"`
+	ValidityWindow             int64           `json:"validityWindow"`
+	MaxActionsPerTx            uint8           `json:"maxActionsPerTx"`
+	MaxMemoSize                int             `json:"maxMemoSize"`
+	MemoBytesPerComputeUnit    uint64          `json:"memoBytesPerComputeUnit"`
+}
+
+type Capabilities struct {
+	// Endpoints maps each API name to its path relative to the chain URI.
+	Endpoints map[string]string `json:"endpoints"`
+	// Methods lists the methods served under [JSONRPCEndpoint].
+	Methods []string `json:"methods"`
+}
+
+type ChainMetadataReply struct {
+	NetworkID    uint32       `json:"networkId"`
+	SubnetID     ids.ID       `json:"subnetId"`
+	ChainID      ids.ID       `json:"chainId"`
+	Name         string       `json:"name"`
+	Version      string       `json:"version"`
+	HRP          string       `json:"hrp"`
+	Symbol       string       `json:"symbol"`
+	Decimals     uint8        `json:"decimals"`
+	ABI          abi.ABI      `json:"abi"`
+	Fees         FeeMetadata  `json:"fees"`
+	Capabilities Capabilities `json:"capabilities"`
+}
+
+// ChainMetadata returns everything a wallet or SDK needs to configure itself
+// against this chain in a single call.
+func (j *JSONRPCServer) ChainMetadata(req *http.Request, _ *struct{}, reply *ChainMetadataReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.ChainMetadata")
+	defer span.End()
+
+	vmABI, err := abi.NewABI(j.vm.ActionCodec().GetRegisteredTypes(), j.vm.OutputCodec().GetRegisteredTypes())
+	if err != nil {
+		return err
+	}
+	unitPrices, err := j.vm.UnitPrices(ctx)
+	if err != nil {
+		return err
+	}
+	r := j.vm.Rules(time.Now().UnixMilli())
+	_, memoBytesPerUnit := actions.MemoRules(r)
+	governedMemoSize, err := configUint64(ctx, r, j.vm.ReadState, actions.MaxMemoSizeRule)
+	if err != nil {
+		return err
+	}
+
+	reply.NetworkID = j.vm.NetworkID()
+	reply.SubnetID = j.vm.SubnetID()
+	reply.ChainID = j.vm.ChainID()
+	reply.Name = consts.Name
+	reply.Version = consts.Version.String()
+	reply.HRP = consts.HRP
+	reply.Symbol = consts.Symbol
+	reply.Decimals = consts.Decimals
+	reply.ABI = vmABI
+	reply.Fees = FeeMetadata{
+		UnitPrices:                 unitPrices,
+		MinUnitPrice:               r.GetMinUnitPrice(),
+		UnitPriceChangeDenominator: r.GetUnitPriceChangeDenominator(),
+		WindowTargetUnits:          r.GetWindowTargetUnits(),
+		MaxBlockUnits:              r.GetMaxBlockUnits(),
+		BaseComputeUnits:           r.GetBaseComputeUnits(),
+		StorageKeyReadUnits:        r.GetStorageKeyReadUnits(),
+		StorageValueReadUnits:      r.GetStorageValueReadUnits(),
+		StorageKeyAllocateUnits:    r.GetStorageKeyAllocateUnits(),
+		StorageValueAllocateUnits:  r.GetStorageValueAllocateUnits(),
+		StorageKeyWriteUnits:       r.GetStorageKeyWriteUnits(),
+		StorageValueWriteUnits:     r.GetStorageValueWriteUnits(),
+		ValidityWindow:             r.GetValidityWindow(),
+		MaxActionsPerTx:            r.GetMaxActionsPerTx(),
+		MaxMemoSize:                int(governedMemoSize),
+		MemoBytesPerComputeUnit:    memoBytesPerUnit,
+	}
+	reply.Capabilities = Capabilities{
+		Endpoints: apiEndpoints,
+		Methods:   serverMethods(),
+	}
+	return nil
+}
+
+type AddressFormatsArgs struct {
+	// Address is a MorpheusVM address or an EVM address.
+	Address string `json:"address"`
+}
+
+type AddressFormatsReply struct {
+	Address codec.Address `json:"address"`
+	// EVM is the EIP-55 form of [Address], if it maps an EVM address.
+	EVM *evm.Address `json:"evm,omitempty"`
+}
+
+// AddressFormats returns an address in both of the formats the API accepts,
+// for wallets that show EVM addresses to their users.
+func (*JSONRPCServer) AddressFormats(_ *http.Request, args *AddressFormatsArgs, reply *AddressFormatsReply) error {
+	addr, err := evm.ParseAddress(args.Address)
+	if err != nil {
+		return err
+	}
+	reply.Address = addr
+	if a, ok := evm.FromAddress(addr); ok {
+		reply.EVM = &a
+	}
+	return nil
+}
+
+type SimulateCostsArgs struct {
+	Actions []codec.Bytes `json:"actions"`
+	Actor   codec.Address `json:"actor"`
+}
+
+type SimulateCostResult struct {
+	Output    codec.Bytes  `json:"output"`
+	StateKeys state.Keys   `json:"stateKeys"`
+	Cost      *cost.Report `json:"cost"`
+	// Fee is the fee of [Cost] at the current unit prices.
+	Fee uint64 `json:"fee"`
+}
+
+type SimulateCostsReply struct {
+	ActionResults []SimulateCostResult `json:"actionResults"`
+}
+
+// SimulateCosts runs actions like the core simulateActions method and adds
+// the cost of each, broken down by fee dimension and state key.
+func (j *JSONRPCServer) SimulateCosts(req *http.Request, args *SimulateCostsArgs, reply *SimulateCostsReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.SimulateCosts")
+	defer span.End()
+
+	var acts []chain.Action
+	for _, b := range args.Actions {
+		r := codec.NewReader(b, len(b))
+		action, err := j.vm.ActionCodec().Unmarshal(r)
+		if err != nil {
+			return err
+		}
+		if !r.Empty() {
+			return ErrActionExtraBytes
+		}
+		acts = append(acts, action)
+	}
+	if len(acts) == 0 {
+		return ErrNoActions
+	}
+	unitPrices, err := j.vm.UnitPrices(ctx)
+	if err != nil {
+		return err
+	}
+	im, err := j.vm.ImmutableState(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	rules := j.vm.Rules(now)
+	for _, action := range acts {
+		recorder := state.NewRecorder(im)
+		output, err := action.Execute(ctx, rules, recorder, now, args.Actor, ids.Empty)
+		if err != nil {
+			return err
+		}
+		result := SimulateCostResult{Output: []byte{}, StateKeys: recorder.GetStateKeys()}
+		if output != nil {
+			result.Output, err = chain.MarshalTyped(output)
+			if err != nil {
+				return err
+			}
+		}
+		result.Cost, err = cost.New(ctx, rules, action, args.Actor, result.StateKeys, recorder)
+		if err != nil {
+			return err
+		}
+		result.Fee, err = fees.MulSum(unitPrices, result.Cost.Units())
+		if err != nil {
+			return err
+		}
+		reply.ActionResults = append(reply.ActionResults, result)
+		im = recorder
+	}
+	return nil
+}
+
+type SimulateActionArgs struct {
+	// Type is the name of the action, such as "Transfer".
+	Type string `json:"type"`
+	// Action is the JSON of the action.
+	Action json.RawMessage `json:"action"`
+	Actor  codec.Address   `json:"actor"`
+}
+
+type SimulateActionReply struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	// Output is set if the action succeeded.
+	Output *StreamOutput `json:"output,omitempty"`
+	// ComputeUnits are those of the action. Units and Fee add its state
+	// keys and bandwidth, charged whether or not it succeeds, but not the
+	// costs of the transaction around it: its base units, auth and sponsor.
+	ComputeUnits uint64          `json:"computeUnits"`
+	Units        fees.Dimensions `json:"units"`
+	Fee          uint64          `json:"fee"`
+}
+
+// SimulateAction executes an action against the latest state in a view that
+// is thrown away, so frontends can check an action before signing it. An
+// action that fails is reported in the reply rather than as an error.
+func (j *JSONRPCServer) SimulateAction(req *http.Request, args *SimulateActionArgs, reply *SimulateActionReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.SimulateAction")
+	defer span.End()
+
+	rt, ok := actionTypes()[args.Type]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownAction, args.Type)
+	}
+	action := reflect.New(rt).Interface().(chain.Action)
+	if len(args.Action) > 0 {
+		if err := json.Unmarshal(args.Action, action); err != nil {
+			return err
+		}
+	}
+	unitPrices, err := j.vm.UnitPrices(ctx)
+	if err != nil {
+		return err
+	}
+	im, err := j.vm.ImmutableState(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	rules := j.vm.Rules(now)
+	view := state.NewRecorder(im)
+	output, execErr := action.Execute(ctx, rules, view, now, args.Actor, ids.Empty)
+	if execErr != nil {
+		reply.Error = execErr.Error()
+	} else {
+		reply.Success = true
+		reply.Output = &StreamOutput{}
+		if output != nil {
+			reply.Output.TypeID = output.GetTypeID()
+			reply.Output.Bytes, err = chain.MarshalTyped(output)
+			if err != nil {
+				return err
+			}
+			reply.Output.Output, err = json.Marshal(output)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	report, err := cost.New(ctx, rules, action, args.Actor, view.GetStateKeys(), view)
+	if err != nil {
+		return err
+	}
+	reply.ComputeUnits = report.Compute
+	reply.Units = report.Units()
+	reply.Fee, err = fees.MulSum(unitPrices, reply.Units)
+	return err
+}
+
+type DiagnoseTxArgs struct {
+	// Tx is a signed transaction, as it is submitted.
+	Tx codec.Bytes `json:"tx"`
+}
+
+type DiagnoseTxReply struct {
+	// Problems lists every reason the transaction would be rejected now,
+	// and is empty if it would be admitted.
+	Problems []TxProblem `json:"problems"`
+	// The fields below are set if the transaction decodes.
+	TxID    ids.ID        `json:"txId"`
+	Expiry  int64         `json:"expiry"`
+	Actor   codec.Address `json:"actor"`
+	Sponsor codec.Address `json:"sponsor"`
+	Actions []TxAction    `json:"actions"`
+	// Fee is the fee of the transaction at the current unit prices, and
+	// Balance the native balance of its sponsor that must cover it.
+	Fee     uint64 `json:"fee"`
+	Balance uint64 `json:"balance"`
+}
+
+// DiagnoseTx reports why a signed transaction would not be admitted, such as
+// its expiry, being a duplicate, its sponsor's balance or a malformed action,
+// without submitting it. It helps find out why a transaction was dropped.
+func (j *JSONRPCServer) DiagnoseTx(req *http.Request, args *DiagnoseTxArgs, reply *DiagnoseTxReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.DiagnoseTx")
+	defer span.End()
+
+	reply.Problems = []TxProblem{}
+	return j.diagnoseTx(ctx, args.Tx, reply)
+}
+
+// serverMethods returns the names of all methods exposed by [JSONRPCServer],
+// formatted the way clients address them.
+func serverMethods() []string {
+	var (
+		t       = reflect.TypeOf((*JSONRPCServer)(nil))
+		methods = make([]string, 0, t.NumMethod())
+	)
+	for i := 0; i < t.NumMethod(); i++ {
+		name := t.Method(i).Name
+		methods = append(methods, strings.ToLower(name[:1])+name[1:])
+	}
+	return methods
+}
+
+type LeaderboardArgs struct {
+	BoardID ids.ID `json:"boardId"`
+	ReadOptions
+}
+
+type LeaderboardReply struct {
+	Leaderboard *storage.Leaderboard `json:"leaderboard"`
+	Height      uint64               `json:"height"`
+}
+
+// Leaderboard returns a leaderboard with its entries, best first.
+func (j *JSONRPCServer) Leaderboard(req *http.Request, args *LeaderboardArgs, reply *LeaderboardReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Leaderboard")
+	defer span.End()
+
+	board, exists, err := storage.GetLeaderboardFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.BoardID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrLeaderboardNotFound
+	}
+	reply.Leaderboard = board
+	return nil
+}
+
+type AccountStatementArgs struct {
+	Address codec.Address `json:"address"`
+	// Asset defaults to the native token.
+	Asset ids.ID `json:"asset"`
+	// FromHeight is at least 1, since the opening balance is proven after
+	// the block before it.
+	FromHeight uint64 `json:"fromHeight"`
+	// ToHeight is included. Zero is the newest height whose root a block
+	// has committed to.
+	ToHeight uint64 `json:"toHeight"`
+	// Attest asks for the statement to be signed with the node's
+	// attestation key.
+	Attest bool `json:"attest"`
+}
+
+type AccountStatementReply struct {
+	Statement *AccountStatement `json:"statement"`
+	// BranchFactor is the branch factor of the state trie, from the
+	// genesis.
+	BranchFactor merkledb.BranchFactor `json:"branchFactor"`
+}
+
+// AccountStatement returns the transfers of an asset to and from an address
+// over a range of heights, with proven opening and closing balances. It needs
+// the event log and the merkledb history of the whole range. While the
+// statement's ToHeight is below the requested one, continue from the height
+// after it. Check statements with [VerifyAccountStatement] and attestations
+// with [VerifyStatementAttestation].
+func (j *JSONRPCServer) AccountStatement(req *http.Request, args *AccountStatementArgs, reply *AccountStatementReply) (err error) {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.AccountStatement")
+	defer span.End()
+
+	if args.Attest && j.attester == nil {
+		return fmt.Errorf("%w: no attestation key", ErrAttestationUnavailable)
+	}
+	reply.Statement, err = j.accountStatement(ctx, args.Address, args.Asset, args.FromHeight, args.ToHeight)
+	if err != nil {
+		return err
+	}
+	reply.BranchFactor = j.vm.Genesis().GetStateBranchFactor()
+	if !args.Attest {
+		return nil
+	}
+	msg, err := StatementMessage(j.vm.ChainID(), reply.Statement)
+	if err != nil {
+		return err
+	}
+	reply.Statement.Attestation = j.attester.attest(msg)
+	return nil
+}
+
+type LockerArgs struct {
+	Locker ids.ID `json:"locker"`
+	ReadOptions
+}
+
+type LockerReply struct {
+	Locker *storage.Locker `json:"locker"`
+	Holder codec.Address   `json:"holder"`
+	// Custody is the address holding the content. Its items are also listed
+	// by assetsByOwner.
+	Custody codec.Address `json:"custody"`
+	Height  uint64        `json:"height"`
+}
+
+// Locker returns the content and holder of a locker, read at one height.
+func (j *JSONRPCServer) Locker(req *http.Request, args *LockerArgs, reply *LockerReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Locker")
+	defer span.End()
+
+	locker, holder, exists, err := storage.GetLockerFromState(ctx, j.stateReader(args.ReadOptions, &reply.Height), args.Locker)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return actions.ErrLockerNotFound
+	}
+	reply.Locker = locker
+	reply.Holder = holder
+	reply.Custody = storage.LockerAddress(args.Locker)
+	return nil
+}
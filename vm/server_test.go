@@ -0,0 +1,165 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/fees"
+	"github.com/ava-labs/hypersdk/genesis"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+func TestSimulateAction(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	rules := newRules()
+	rules.Rules = genesis.NewDefaultRules()
+	v := metadataVM{chainID: ids.GenerateTestID(), rules: rules, store: chaintest.NewInMemoryStore()}
+	j := &JSONRPCServer{vm: v}
+	req := httptest.NewRequest("POST", "/", nil)
+	actor := codectest.NewRandomAddress()
+	require.NoError(storage.SetBalance(ctx, v.store, actor, 100))
+
+	simulate := func(action *actions.Transfer) *SimulateActionReply {
+		b, err := json.Marshal(action)
+		require.NoError(err)
+		reply := new(SimulateActionReply)
+		require.NoError(j.SimulateAction(req, &SimulateActionArgs{Type: "Transfer", Action: b, Actor: actor}, reply))
+		return reply
+	}
+
+	// The output is decoded and the costs priced, but state is untouched.
+	reply := simulate(&actions.Transfer{To: codectest.NewRandomAddress(), Value: 5})
+	require.True(reply.Success)
+	require.Empty(reply.Error)
+	require.Equal(mconsts.TransferID, reply.Output.TypeID)
+	var result actions.TransferResult
+	require.NoError(json.Unmarshal(reply.Output.Output, &result))
+	require.Equal(uint64(95), result.SenderBalance)
+	require.Positive(reply.ComputeUnits)
+	fee, err := fees.MulSum(fees.Dimensions{1, 2, 3, 4, 5}, reply.Units)
+	require.NoError(err)
+	require.Equal(fee, reply.Fee)
+	balance, err := storage.GetBalance(ctx, v.store, actor)
+	require.NoError(err)
+	require.Equal(uint64(100), balance)
+
+	// A failing action is reported in the reply, still with its costs.
+	reply = simulate(&actions.Transfer{To: codectest.NewRandomAddress(), Value: 500})
+	require.False(reply.Success)
+	require.NotEmpty(reply.Error)
+	require.Nil(reply.Output)
+	require.Positive(reply.Fee)
+
+	// Unknown actions fail the call.
+	err = j.SimulateAction(req, &SimulateActionArgs{Type: "Mint", Actor: actor}, new(SimulateActionReply))
+	require.ErrorIs(err, ErrUnknownAction)
+}
+
+func TestDiagnoseTx(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	rules := newRules()
+	rules.Rules = genesis.NewDefaultRules()
+	v := metadataVM{chainID: ids.GenerateTestID(), rules: rules, store: chaintest.NewInMemoryStore()}
+	rules.ChainID = v.chainID
+	j := &JSONRPCServer{vm: v}
+	req := httptest.NewRequest("POST", "/", nil)
+
+	funded, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	empty, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	require.NoError(storage.SetBalance(ctx, v.store, auth.NewED25519Address(funded.PublicKey()), 1_000_000))
+	next := (time.Now().UnixMilli()/1_000 + 10) * 1_000
+	sign := func(priv ed25519.PrivateKey, timestamp int64) []byte {
+		tx, err := (&chain.Transaction{
+			Base:    &chain.Base{Timestamp: timestamp, ChainID: v.chainID, MaxFee: 1_000_000},
+			Actions: []chain.Action{&actions.Transfer{To: codectest.NewRandomAddress(), Value: 5}},
+		}).Sign(auth.NewED25519Factory(priv), ActionParser, AuthParser)
+		require.NoError(err)
+		return tx.Bytes()
+	}
+	diagnose := func(b []byte) *DiagnoseTxReply {
+		reply := new(DiagnoseTxReply)
+		require.NoError(j.DiagnoseTx(req, &DiagnoseTxArgs{Tx: b}, reply))
+		return reply
+	}
+	reasons := func(reply *DiagnoseTxReply) []string {
+		var reasons []string
+		for _, p := range reply.Problems {
+			reasons = append(reasons, p.Reason)
+		}
+		return reasons
+	}
+
+	// A transaction that would be admitted has no problems.
+	reply := diagnose(sign(funded, next))
+	require.Empty(reply.Problems)
+	require.Equal(auth.NewED25519Address(funded.PublicKey()), reply.Sponsor)
+	require.Len(reply.Actions, 1)
+	require.Equal(mconsts.TransferID, reply.Actions[0].TypeID)
+	require.Positive(reply.Fee)
+	require.Equal(uint64(1_000_000), reply.Balance)
+
+	// Every problem is listed.
+	require.Equal([]string{TxExpiry, TxInsufficientBalance}, reasons(diagnose(sign(empty, 1_000))))
+	b := sign(funded, next)
+	b[len(b)-1]++
+	require.Equal([]string{TxBadSignature}, reasons(diagnose(b)))
+	require.Equal([]string{TxMalformed}, reasons(diagnose([]byte{1, 2, 3})))
+
+	// A transfer is not executed while the chain is halted.
+	require.NoError(storage.SetHalt(ctx, v.store, &storage.Halt{Incident: ids.GenerateTestID()}))
+	require.Equal([]string{TxHalted}, reasons(diagnose(sign(funded, next))))
+}
+
+func TestBulkReads(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	v := metadataVM{store: chaintest.NewInMemoryStore()}
+	j := &JSONRPCServer{vm: v}
+	req := httptest.NewRequest("POST", "/", nil)
+	require.NoError(v.store.Insert(ctx, chain.HeightKey(storage.HeightKey()), []byte{0, 0, 0, 0, 0, 0, 0, 7}))
+
+	funded := codectest.NewRandomAddress()
+	owner := codectest.NewRandomAddress()
+	asset := ids.GenerateTestID()
+	require.NoError(storage.SetBalance(ctx, v.store, funded, 40))
+	require.NoError(storage.CreateAsset(ctx, v.store, asset, owner))
+
+	// Values are aligned with the keys, zero for missing ones, and read at
+	// one height.
+	balances := new(BalancesReply)
+	require.NoError(j.Balances(req, &BalancesArgs{Addresses: []codec.Address{codectest.NewRandomAddress(), funded}}, balances))
+	require.Equal([]uint64{0, 40}, balances.Amounts)
+	require.Equal(uint64(7), balances.Height)
+
+	owners := new(AssetOwnersReply)
+	require.NoError(j.AssetOwners(req, &AssetOwnersArgs{Assets: []ids.ID{asset, ids.GenerateTestID()}}, owners))
+	require.Equal([]codec.Address{owner, codec.EmptyAddress}, owners.Owners)
+	require.Equal(uint64(7), owners.Height)
+
+	err := j.Balances(req, &BalancesArgs{Addresses: make([]codec.Address, MaxBulkReads+1)}, new(BalancesReply))
+	require.ErrorIs(err, ErrTooManyReads)
+	err = j.AssetOwners(req, &AssetOwnersArgs{Assets: make([]ids.ID, MaxBulkReads+1)}, new(AssetOwnersReply))
+	require.ErrorIs(err, ErrTooManyReads)
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"crypto/rand"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+const (
+	// sessionRequestTTL is how long a wallet has to approve a session
+	// request before the node forgets it.
+	sessionRequestTTL = 10 * time.Minute
+
+	// MaxSessionRequests bounds the pending requests kept for one owner.
+	// The oldest request is dropped to make room for a new one.
+	MaxSessionRequests = 8
+
+	// sessionOwnerCacheSize bounds how many owners have pending requests.
+	sessionOwnerCacheSize = 1024
+)
+
+// SessionRequest is a session a dapp asked an owner to authorize. The
+// wallet approves it by submitting [actions.AuthorizeSessionKey] with the
+// same terms.
+type SessionRequest struct {
+	ID          ids.ID        `json:"id"`
+	Key         codec.Address `json:"key"`
+	ActionTypes []uint8       `json:"actionTypes"`
+	SpendCap    uint64        `json:"spendCap"`
+	Expiry      int64         `json:"expiry"`
+	// RequestedAt is the node time, in milliseconds, the request was made.
+	RequestedAt int64 `json:"requestedAt"`
+}
+
+// sessionRequests holds the pending session requests of each owner in
+// memory. They are not gossiped, so the dapp and the wallet must use the
+// same node.
+type sessionRequests struct {
+	lock     sync.Mutex
+	requests *cache.LRU[codec.Address, []*SessionRequest]
+}
+
+func newSessionRequests() *sessionRequests {
+	return &sessionRequests{
+		requests: &cache.LRU[codec.Address, []*SessionRequest]{Size: sessionOwnerCacheSize},
+	}
+}
+
+// add records [request] for [owner], assigning its ID and request time.
+func (s *sessionRequests) add(owner codec.Address, request *SessionRequest, now time.Time) error {
+	if _, err := rand.Read(request.ID[:]); err != nil {
+		return err
+	}
+	request.RequestedAt = now.UnixMilli()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	pending := s.pending(owner, now)
+	if len(pending) == MaxSessionRequests {
+		pending = pending[1:]
+	}
+	s.requests.Put(owner, append(pending, request))
+	return nil
+}
+
+// get returns the requests of [owner] made within [sessionRequestTTL] of
+// [now], oldest first.
+func (s *sessionRequests) get(owner codec.Address, now time.Time) []*SessionRequest {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.pending(owner, now)
+}
+
+func (s *sessionRequests) pending(owner codec.Address, now time.Time) []*SessionRequest {
+	requests, _ := s.requests.Get(owner)
+	cutoff := now.Add(-sessionRequestTTL).UnixMilli()
+	pending := make([]*SessionRequest, 0, len(requests)+1)
+	for _, request := range requests {
+		if request.RequestedAt >= cutoff {
+			pending = append(pending, request)
+		}
+	}
+	return pending
+}
+
+// activeSessions skips the sessions of a [storage.Sessions] iterator that
+// expired before [now].
+type activeSessions struct {
+	*storage.Sessions
+	now int64
+}
+
+func (a *activeSessions) Next() bool {
+	for a.Sessions.Next() {
+		if a.Session().Expiry > a.now {
+			return true
+		}
+	}
+	return false
+}
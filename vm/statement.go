@@ -0,0 +1,356 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/proof"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// MaxStatementBlocks bounds the blocks with entries one statement lists.
+// A longer range is cut after the last block that fits.
+const MaxStatementBlocks = 256
+
+// statementDomain starts every signed statement message, so attestations
+// cannot be replayed as other signed data.
+const statementDomain = "morpheusvm account statement"
+
+const (
+	anchorLen         = 2*consts.Uint64Len + 2*ids.IDLen
+	statementBlockLen = 4*consts.Uint64Len + consts.Uint32Len
+	statementEntryLen = ids.IDLen + consts.Uint32Len + codec.AddressLen + 2*consts.Uint64Len
+)
+
+var ErrInvalidStatement = errors.New("invalid account statement")
+
+// BalanceAnchor is a balance proven against the state root of a block.
+type BalanceAnchor struct {
+	// Height is the height the balance is read after.
+	Height uint64 `json:"height"`
+	// BlockID is the block after [Height], whose StateRoot is [StateRoot].
+	BlockID   ids.ID `json:"blockId"`
+	StateRoot ids.ID `json:"stateRoot"`
+	Balance   uint64 `json:"balance"`
+	// Proof proves the balance record, or its absence for a zero balance.
+	Proof codec.Bytes `json:"proof"`
+}
+
+// StatementEntry is a transfer to or from the address of a statement.
+type StatementEntry struct {
+	TxID ids.ID `json:"txId"`
+	// Action is the index of the transferring action in its transaction.
+	Action       int           `json:"action"`
+	Counterparty codec.Address `json:"counterparty"`
+	Credit       uint64        `json:"credit"`
+	Debit        uint64        `json:"debit"`
+}
+
+// StatementBlock is the activity of the address of a statement in one
+// block, and its balance after it.
+type StatementBlock struct {
+	Height  uint64           `json:"height"`
+	Entries []StatementEntry `json:"entries"`
+	// OtherCredit and OtherDebit are the net change of the balance since
+	// the previous block of the statement that its entries do not explain,
+	// such as fees, rent or staking. At most one of them is set.
+	OtherCredit uint64 `json:"otherCredit"`
+	OtherDebit  uint64 `json:"otherDebit"`
+	Balance     uint64 `json:"balance"`
+}
+
+// AccountStatement lists the transfers of one asset to and from an address
+// over a range of heights, with its balance after every block that moved
+// it. The opening and closing balances are proven against state roots, so
+// a holder of the statement only has to trust the blocks that carry them.
+type AccountStatement struct {
+	Address    codec.Address `json:"address"`
+	Asset      ids.ID        `json:"asset"`
+	FromHeight uint64        `json:"fromHeight"`
+	ToHeight   uint64        `json:"toHeight"`
+	// Opening is the balance before [FromHeight] and Closing the balance
+	// after [ToHeight].
+	Opening BalanceAnchor    `json:"opening"`
+	Closing BalanceAnchor    `json:"closing"`
+	Blocks  []StatementBlock `json:"blocks"`
+
+	Attestation *OwnershipAttestation `json:"attestation,omitempty"`
+}
+
+func statementBalanceKey(addr codec.Address, asset ids.ID) []byte {
+	if asset == storage.NativeAsset {
+		return storage.BalanceKey(addr)
+	}
+	return storage.AssetBalanceKey(addr, asset)
+}
+
+func parseStatementBalance(asset ids.ID, v []byte) (uint64, error) {
+	if asset == storage.NativeAsset {
+		return storage.ParseBalance(v)
+	}
+	return database.ParseUInt64(v)
+}
+
+// StatementMessage is the message an [AccountStatement] attestation signs.
+// It covers everything but the proofs, which the anchored roots stand for.
+func StatementMessage(chainID ids.ID, s *AccountStatement) ([]byte, error) {
+	size := len(statementDomain) + ids.IDLen + codec.AddressLen + ids.IDLen + 2*consts.Uint64Len + 2*anchorLen + consts.Uint32Len
+	for _, b := range s.Blocks {
+		size += statementBlockLen + len(b.Entries)*statementEntryLen
+	}
+	p := codec.NewWriter(size, size)
+	p.PackFixedBytes([]byte(statementDomain))
+	p.PackID(chainID)
+	p.PackAddress(s.Address)
+	p.PackID(s.Asset)
+	p.PackUint64(s.FromHeight)
+	p.PackUint64(s.ToHeight)
+	for _, a := range []*BalanceAnchor{&s.Opening, &s.Closing} {
+		p.PackUint64(a.Height)
+		p.PackID(a.BlockID)
+		p.PackID(a.StateRoot)
+		p.PackUint64(a.Balance)
+	}
+	p.PackInt(uint32(len(s.Blocks)))
+	for _, b := range s.Blocks {
+		p.PackUint64(b.Height)
+		p.PackUint64(b.OtherCredit)
+		p.PackUint64(b.OtherDebit)
+		p.PackUint64(b.Balance)
+		p.PackInt(uint32(len(b.Entries)))
+		for _, e := range b.Entries {
+			p.PackID(e.TxID)
+			p.PackInt(uint32(e.Action))
+			p.PackAddress(e.Counterparty)
+			p.PackUint64(e.Credit)
+			p.PackUint64(e.Debit)
+		}
+	}
+	return p.Bytes(), p.Err()
+}
+
+// VerifyAccountStatement checks the anchor proofs of [s] against their state
+// roots, in a trie of [branchFactor], and that its blocks add up from the
+// opening to the closing balance. Whether the anchoring blocks are canonical
+// is left to the caller.
+func VerifyAccountStatement(ctx context.Context, s *AccountStatement, branchFactor merkledb.BranchFactor) error {
+	if s.FromHeight == 0 || s.FromHeight > s.ToHeight || s.Opening.Height != s.FromHeight-1 || s.Closing.Height != s.ToHeight {
+		return fmt.Errorf("%w: anchors do not match heights %d to %d", ErrInvalidStatement, s.FromHeight, s.ToHeight)
+	}
+	key := statementBalanceKey(s.Address, s.Asset)
+	for _, a := range []*BalanceAnchor{&s.Opening, &s.Closing} {
+		value, exists, err := proof.Verify(ctx, a.StateRoot, key, a.Proof, branchFactor)
+		if err != nil {
+			return fmt.Errorf("%w: at height %d: %w", ErrInvalidStatement, a.Height, err)
+		}
+		var balance uint64
+		if exists {
+			if balance, err = parseStatementBalance(s.Asset, value); err != nil {
+				return fmt.Errorf("%w: at height %d: %w", ErrInvalidStatement, a.Height, err)
+			}
+		}
+		if balance != a.Balance {
+			return fmt.Errorf("%w: proven balance at height %d is %d, not %d", ErrInvalidStatement, a.Height, balance, a.Balance)
+		}
+	}
+
+	balance, height := s.Opening.Balance, s.Opening.Height
+	for _, b := range s.Blocks {
+		if b.Height <= height || b.Height > s.ToHeight {
+			return fmt.Errorf("%w: block %d out of order", ErrInvalidStatement, b.Height)
+		}
+		next, err := applyStatementBlock(balance, &b)
+		if err != nil || next != b.Balance {
+			return fmt.Errorf("%w: block %d does not add up", ErrInvalidStatement, b.Height)
+		}
+		balance, height = next, b.Height
+	}
+	if balance != s.Closing.Balance {
+		return fmt.Errorf("%w: blocks end at %d, not the closing %d", ErrInvalidStatement, balance, s.Closing.Balance)
+	}
+	return nil
+}
+
+// applyStatementBlock returns [balance] after the movements of [b]. Credits
+// are added before debits, since fees and other untracked movements within
+// a block are not ordered against its entries.
+func applyStatementBlock(balance uint64, b *StatementBlock) (uint64, error) {
+	balance, err := smath.Add(balance, b.OtherCredit)
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range b.Entries {
+		if balance, err = smath.Add(balance, e.Credit); err != nil {
+			return 0, err
+		}
+	}
+	for _, e := range b.Entries {
+		if balance, err = smath.Sub(balance, e.Debit); err != nil {
+			return 0, err
+		}
+	}
+	return smath.Sub(balance, b.OtherDebit)
+}
+
+// VerifyStatementAttestation checks that [s] on [chainID] is signed by
+// [signer].
+func VerifyStatementAttestation(chainID ids.ID, s *AccountStatement, signer ed25519.PublicKey) error {
+	a := s.Attestation
+	if a == nil || len(a.Signer) != ed25519.PublicKeyLen || len(a.Signature) != ed25519.SignatureLen {
+		return fmt.Errorf("%w: missing or malformed", ErrInvalidAttestation)
+	}
+	if ed25519.PublicKey(a.Signer) != signer {
+		return fmt.Errorf("%w: signed by another key", ErrInvalidAttestation)
+	}
+	msg, err := StatementMessage(chainID, s)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(msg, signer, ed25519.Signature(a.Signature)) {
+		return fmt.Errorf("%w: bad signature", ErrInvalidAttestation)
+	}
+	return nil
+}
+
+// balanceAnchor proves the balance of [key] after [height].
+func (j *JSONRPCServer) balanceAnchor(ctx context.Context, height uint64, key []byte, asset ids.ID) (BalanceAnchor, error) {
+	next, p, err := proveHistorical(ctx, j.history, height, key)
+	if err != nil {
+		return BalanceAnchor{}, err
+	}
+	a := BalanceAnchor{Height: height, BlockID: next.ID(), StateRoot: next.StateRoot}
+	if a.Proof, err = proof.Marshal(p); err != nil {
+		return BalanceAnchor{}, err
+	}
+	for _, kv := range p.KeyValues {
+		if string(kv.Key) == string(key) {
+			if a.Balance, err = parseStatementBalance(asset, kv.Value); err != nil {
+				return BalanceAnchor{}, err
+			}
+		}
+	}
+	return a, nil
+}
+
+// accountStatement builds the statement of [asset] for [addr] from [from] to
+// [to]. The range is cut short at the event log bounds and at
+// [MaxStatementBlocks], and the statement says where it ends.
+func (j *JSONRPCServer) accountStatement(ctx context.Context, addr codec.Address, asset ids.ID, from, to uint64) (*AccountStatement, error) {
+	if j.logs == nil {
+		return nil, fmt.Errorf("%w: index disabled", ErrLogsUnavailable)
+	}
+	if j.history == nil {
+		return nil, ErrHistoryUnavailable
+	}
+	lastAccepted := j.vm.LastAcceptedBlock().Hght
+	if to == 0 && lastAccepted > 0 {
+		to = lastAccepted - 1
+	}
+	switch {
+	case from == 0:
+		return nil, fmt.Errorf("%w: statements start after genesis", ErrInvalidLogRange)
+	case to >= lastAccepted:
+		return nil, fmt.Errorf("%w: height=%d, lastAccepted=%d", ErrHeightNotAccepted, to, lastAccepted)
+	}
+	logs, last, err := j.logs.Logs(from, to, &LogFilter{
+		Addresses: []codec.Address{addr},
+		Assets:    []ids.ID{asset},
+		Kinds:     []string{actions.TransferEventKind},
+	})
+	if err != nil {
+		return nil, err
+	}
+	to = min(to, last)
+
+	s := &AccountStatement{
+		Address:    addr,
+		Asset:      asset,
+		FromHeight: from,
+		ToHeight:   to,
+		Blocks:     []StatementBlock{},
+	}
+	for _, l := range logs {
+		if n := len(s.Blocks); n == 0 || s.Blocks[n-1].Height != l.Height {
+			if n == MaxStatementBlocks {
+				s.ToHeight = l.Height - 1
+				break
+			}
+			s.Blocks = append(s.Blocks, StatementBlock{Height: l.Height})
+		}
+		var e actions.TransferEvent
+		if err := json.Unmarshal(l.Event, &e); err != nil {
+			return nil, err
+		}
+		b := &s.Blocks[len(s.Blocks)-1]
+		if e.From == addr {
+			b.Entries = append(b.Entries, StatementEntry{TxID: l.TxID, Action: l.Action, Counterparty: e.To, Debit: e.Amount})
+		}
+		if e.To == addr {
+			b.Entries = append(b.Entries, StatementEntry{TxID: l.TxID, Action: l.Action, Counterparty: e.From, Credit: e.Amount})
+		}
+	}
+
+	key := statementBalanceKey(addr, asset)
+	if s.Opening, err = j.balanceAnchor(ctx, from-1, key, asset); err != nil {
+		return nil, err
+	}
+	if s.Closing, err = j.balanceAnchor(ctx, s.ToHeight, key, asset); err != nil {
+		return nil, err
+	}
+	if n := len(s.Blocks); n == 0 || s.Blocks[n-1].Height != s.ToHeight {
+		s.Blocks = append(s.Blocks, StatementBlock{Height: s.ToHeight})
+	}
+	balance := s.Opening.Balance
+	for i := range s.Blocks {
+		b := &s.Blocks[i]
+		if b.Balance, err = j.statementBalance(ctx, b.Height, addr, asset); err != nil {
+			return nil, err
+		}
+		b.OtherCredit, b.OtherDebit = reconcile(balance, b)
+		balance = b.Balance
+	}
+	// The closing block is only listed when something moved in it.
+	if last := s.Blocks[len(s.Blocks)-1]; len(last.Entries) == 0 && last.OtherCredit == 0 && last.OtherDebit == 0 {
+		s.Blocks = s.Blocks[:len(s.Blocks)-1]
+	}
+	return s, nil
+}
+
+func (j *JSONRPCServer) statementBalance(ctx context.Context, height uint64, addr codec.Address, asset ids.ID) (uint64, error) {
+	if asset == storage.NativeAsset {
+		return storage.GetBalanceFromState(ctx, j.readStateAt(height), addr)
+	}
+	return storage.GetAssetBalanceFromState(ctx, j.readStateAt(height), addr, asset)
+}
+
+// reconcile returns the untracked credit or debit that takes [balance]
+// through the entries of [b] to its balance.
+func reconcile(balance uint64, b *StatementBlock) (uint64, uint64) {
+	// Amounts are bounded by the supply of the asset, which fits in a
+	// uint64.
+	var credits, debits uint64
+	for _, e := range b.Entries {
+		credits += e.Credit
+		debits += e.Debit
+	}
+	in, out := balance+credits, debits+b.Balance
+	if in > out {
+		return 0, in - out
+	}
+	return out - in, 0
+}
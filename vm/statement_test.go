@@ -0,0 +1,124 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/proof"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+func TestAccountStatement(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	db, err := merkledb.New(ctx, memdb.New(), merkledb.Config{
+		BranchFactor:                merkledb.BranchFactor16,
+		Hasher:                      merkledb.DefaultHasher,
+		HistoryLength:               16,
+		ValueNodeCacheSize:          units.MiB,
+		IntermediateNodeCacheSize:   units.MiB,
+		IntermediateWriteBufferSize: units.KiB,
+		IntermediateWriteBatchSize:  units.KiB,
+		Reg:                         prometheus.NewRegistry(),
+		TraceLevel:                  merkledb.InfoTrace,
+		Tracer:                      trace.Noop,
+	})
+	require.NoError(err)
+
+	addr := codectest.NewRandomAddress()
+	other := codectest.NewRandomAddress()
+	key := storage.BalanceKey(addr)
+	anchor := func(height, balance uint64) BalanceAnchor {
+		require.NoError(db.Put(key, binary.BigEndian.AppendUint64(nil, balance)))
+		root, err := db.GetMerkleRoot(ctx)
+		require.NoError(err)
+		p, err := db.GetRangeProofAtRoot(ctx, root, maybe.Some(key), maybe.Some(key), 1)
+		require.NoError(err)
+		b, err := proof.Marshal(p)
+		require.NoError(err)
+		return BalanceAnchor{Height: height, BlockID: ids.GenerateTestID(), StateRoot: root, Balance: balance, Proof: b}
+	}
+
+	s := &AccountStatement{
+		Address:    addr,
+		Asset:      storage.NativeAsset,
+		FromHeight: 5,
+		ToHeight:   9,
+		Opening:    anchor(4, 100),
+		Blocks: []StatementBlock{
+			{
+				Height:     6,
+				Entries:    []StatementEntry{{TxID: ids.GenerateTestID(), Counterparty: other, Credit: 50}},
+				OtherDebit: 1,
+				Balance:    149,
+			},
+			{
+				Height: 8,
+				Entries: []StatementEntry{
+					{TxID: ids.GenerateTestID(), Counterparty: other, Debit: 140},
+					{TxID: ids.GenerateTestID(), Action: 1, Counterparty: other, Credit: 10},
+				},
+				OtherDebit: 2,
+				Balance:    17,
+			},
+		},
+		Closing: anchor(9, 17),
+	}
+	require.NoError(VerifyAccountStatement(ctx, s, merkledb.BranchFactor16))
+
+	// The entries of a block must add up to its balance.
+	s.Blocks[1].OtherDebit = 3
+	require.ErrorIs(VerifyAccountStatement(ctx, s, merkledb.BranchFactor16), ErrInvalidStatement)
+	s.Blocks[1].OtherDebit = 2
+
+	// Anchored balances must match their proofs.
+	s.Closing.Balance = 18
+	require.ErrorIs(VerifyAccountStatement(ctx, s, merkledb.BranchFactor16), ErrInvalidStatement)
+	s.Closing.Balance = 17
+	s.Closing.StateRoot = s.Opening.StateRoot
+	require.ErrorIs(VerifyAccountStatement(ctx, s, merkledb.BranchFactor16), ErrInvalidStatement)
+	s.Closing = anchor(9, 17)
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	a, err := newAttester(hex.EncodeToString(priv[:]))
+	require.NoError(err)
+	chainID := ids.GenerateTestID()
+	msg, err := StatementMessage(chainID, s)
+	require.NoError(err)
+	s.Attestation = a.attest(msg)
+	require.NoError(VerifyStatementAttestation(chainID, s, priv.PublicKey()))
+	require.ErrorIs(VerifyStatementAttestation(ids.GenerateTestID(), s, priv.PublicKey()), ErrInvalidAttestation)
+
+	s.Blocks[0].Entries[0].Counterparty = addr
+	require.ErrorIs(VerifyStatementAttestation(chainID, s, priv.PublicKey()), ErrInvalidAttestation)
+}
+
+func TestReconcile(t *testing.T) {
+	require := require.New(t)
+	b := &StatementBlock{Entries: []StatementEntry{{Credit: 10}, {Debit: 4}}, Balance: 105}
+	credit, debit := reconcile(100, b)
+	require.Zero(credit)
+	require.Equal(uint64(1), debit)
+
+	b.Balance = 110
+	credit, debit = reconcile(100, b)
+	require.Equal(uint64(4), credit)
+	require.Zero(debit)
+}
@@ -0,0 +1,523 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/api"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/event"
+	"github.com/ava-labs/hypersdk/pubsub"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// StreamEndpoint serves the event stream over WebSocket.
+//
+// Messages in both directions use the hypersdk pubsub framing: a uint32
+// count followed by that many uint32-length-prefixed messages, big endian.
+// Each message is JSON: a [StreamRequest] from the client, a [StreamEvent]
+// from the server.
+const StreamEndpoint = "/morpheusws"
+
+// MaxWatchedAddresses bounds the addresses one connection can watch.
+const MaxWatchedAddresses = 64
+
+// Kinds of [StreamEvent].
+const (
+	StreamBlockEvent   = "block"
+	StreamTxEvent      = "tx"
+	StreamBalanceEvent = "balance"
+	StreamLogEvent     = "log"
+	StreamPageEvent    = "page"
+	StreamErrorEvent   = "error"
+
+	StreamNotificationEvent = "notification"
+)
+
+var ErrTooManyWatchedAddresses = fmt.Errorf("cannot watch more than %d addresses", MaxWatchedAddresses)
+
+var (
+	_ event.SubscriptionFactory[*chain.ExecutedBlock] = (*stream)(nil)
+	_ event.Subscription[*chain.ExecutedBlock]        = (*stream)(nil)
+	_ api.HandlerFactory[api.VM]                      = (*streamHandlerFactory)(nil)
+)
+
+// StreamRequest replaces the subscriptions of the connection that sends it,
+// unless it carries a [Query].
+type StreamRequest struct {
+	Blocks bool `json:"blocks"`
+	Txs    bool `json:"txs"`
+	// Watch lists the addresses to report balance changes for.
+	Watch []codec.Address `json:"watch"`
+	// Logs reports the events of accepted actions that match it, as
+	// [StreamLogEvent]s.
+	Logs *LogFilter `json:"logs,omitempty"`
+	// Notify reports, as [StreamNotificationEvent]s, the events of every
+	// address whose notification prefs push them to this target. Which
+	// events are pushed is up to each address, not the node operator.
+	Notify *ids.ID `json:"notify,omitempty"`
+
+	// Query runs a list method instead, leaving subscriptions unchanged.
+	Query *StreamQuery `json:"query,omitempty"`
+}
+
+// StreamQuery pages through a JSON-RPC list method, pushing every page as a
+// [StreamPageEvent] until one has HasMore unset. If the connection falls
+// too far behind, the query stops; resume it from the last page's cursor.
+type StreamQuery struct {
+	// ID is echoed in the events of the query.
+	ID string `json:"id"`
+	// Method is "assetsByOwner", "vestings", "activeSessions", "orders",
+	// "liveAuctions", "appAssets", "appCreators", "exportBalances",
+	// "treasuryHistory", "assetHistory" or "getTxsByAddress".
+	Method string `json:"method"`
+	// Params are the JSON-RPC args of [Method]. Their cursor and limit pick
+	// the first page and the page size.
+	Params json.RawMessage `json:"params"`
+}
+
+// StreamEvent is one message pushed to subscribers. Exactly one of the
+// fields matching [Type] is set.
+type StreamEvent struct {
+	Type    string         `json:"type"`
+	Block   *StreamBlock   `json:"block,omitempty"`
+	Tx      *StreamTx      `json:"tx,omitempty"`
+	Balance *BalanceChange `json:"balance,omitempty"`
+	Log     *Log           `json:"log,omitempty"`
+	Page    *StreamPage    `json:"page,omitempty"`
+	Error   string         `json:"error,omitempty"`
+
+	Notification *Notification `json:"notification,omitempty"`
+	// Query is the [StreamQuery.ID] of page events, and of errors that
+	// ended a query.
+	Query string `json:"query,omitempty"`
+}
+
+// StreamPage is one page of a [StreamQuery].
+type StreamPage struct {
+	// Items is the list of the method's reply, such as its assets.
+	Items json.RawMessage `json:"items"`
+	Page
+}
+
+// StreamBlock is the header of an accepted block.
+type StreamBlock struct {
+	Height    uint64 `json:"height"`
+	BlockID   ids.ID `json:"blockId"`
+	Parent    ids.ID `json:"parent"`
+	Timestamp int64  `json:"timestamp"`
+	// StateRoot is the root of the state after the parent block.
+	StateRoot ids.ID `json:"stateRoot"`
+	TxCount   int    `json:"txCount"`
+}
+
+// StreamTx is the result of a transaction in an accepted block.
+type StreamTx struct {
+	Height  uint64         `json:"height"`
+	TxID    ids.ID         `json:"txId"`
+	Actor   codec.Address  `json:"actor"`
+	Success bool           `json:"success"`
+	Error   string         `json:"error,omitempty"`
+	Fee     uint64         `json:"fee"`
+	Outputs []StreamOutput `json:"outputs"`
+}
+
+// StreamOutput is an action output decoded with [OutputParser].
+type StreamOutput struct {
+	TypeID uint8 `json:"typeId"`
+	// Output is the JSON form of the registered output type.
+	Output json.RawMessage `json:"output"`
+	Bytes  codec.Bytes     `json:"bytes"`
+}
+
+// BalanceChange reports the balance of a watched address after a block that
+// changed it.
+type BalanceChange struct {
+	Height  uint64        `json:"height"`
+	Address codec.Address `json:"address"`
+	// Asset is [storage.NativeAsset] for the native token.
+	Asset    ids.ID `json:"asset"`
+	Previous uint64 `json:"previous"`
+	Balance  uint64 `json:"balance"`
+}
+
+type streamSubscription struct {
+	blocks bool
+	txs    bool
+	watch  set.Set[codec.Address]
+	logs   *LogFilter
+	notify *ids.ID
+}
+
+// stream pushes accepted blocks to WebSocket subscribers.
+type stream struct {
+	server    *pubsub.Server
+	readState storage.ReadState
+	history   historicalState
+	lists     *lists
+	log       logging.Logger
+
+	l    sync.Mutex
+	subs map[*pubsub.Connection]*streamSubscription
+}
+
+// streamState is the part of the VM the stream reads balances from.
+type streamState interface {
+	historicalState
+	ReadState(context.Context, [][]byte) ([][]byte, []error)
+}
+
+func newStream(v streamState, log logging.Logger, treasury *treasuryHistory, assets *assetHistory, activity *activity, cursors *cursorSigner, balanceExport bool) *stream {
+	s := &stream{
+		readState: v.ReadState,
+		history:   v,
+		lists:     &lists{history: v, treasury: treasury, assets: assets, activity: activity, cursors: cursors, balanceExport: balanceExport},
+		log:       log,
+		subs:      map[*pubsub.Connection]*streamSubscription{},
+	}
+	s.server = pubsub.New(log, pubsub.NewDefaultServerConfig(), s.handle)
+	return s
+}
+
+func (s *stream) New() (event.Subscription[*chain.ExecutedBlock], error) {
+	return s, nil
+}
+
+func (s *stream) handle(msg []byte, c *pubsub.Connection) {
+	var req StreamRequest
+	if err := json.Unmarshal(msg, &req); err != nil {
+		s.send(c, &StreamEvent{Type: StreamErrorEvent, Error: err.Error()})
+		return
+	}
+	if req.Query != nil {
+		s.query(c, req.Query)
+		return
+	}
+	if len(req.Watch) > MaxWatchedAddresses {
+		s.send(c, &StreamEvent{Type: StreamErrorEvent, Error: ErrTooManyWatchedAddresses.Error()})
+		return
+	}
+	if req.Logs != nil {
+		if err := req.Logs.verify(); err != nil {
+			s.send(c, &StreamEvent{Type: StreamErrorEvent, Error: err.Error()})
+			return
+		}
+	}
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	s.subs[c] = &streamSubscription{
+		blocks: req.Blocks,
+		txs:    req.Txs,
+		watch:  set.Of(req.Watch...),
+		logs:   req.Logs,
+		notify: req.Notify,
+	}
+}
+
+// query runs [q] on the reading goroutine of [c], so a connection runs one
+// query at a time.
+func (s *stream) query(c *pubsub.Connection, q *StreamQuery) {
+	if err := s.runQuery(c, q); err != nil {
+		s.send(c, &StreamEvent{Type: StreamErrorEvent, Error: err.Error(), Query: q.ID})
+	}
+}
+
+func (s *stream) runQuery(c *pubsub.Connection, q *StreamQuery) error {
+	params := q.Params
+	for {
+		items, page, err := s.lists.list(context.Background(), q.Method, params)
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(items)
+		if err != nil {
+			return err
+		}
+		if !s.send(c, &StreamEvent{
+			Type:  StreamPageEvent,
+			Page:  &StreamPage{Items: b, Page: page},
+			Query: q.ID,
+		}) || !page.HasMore {
+			return nil
+		}
+		params, err = withCursor(params, page.Cursor)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// send reports whether [e] was queued on [c].
+func (s *stream) send(c *pubsub.Connection, e *StreamEvent) bool {
+	b, err := json.Marshal(e)
+	if err != nil {
+		s.log.Warn("failed to marshal stream event", zap.Error(err))
+		return false
+	}
+	return c.Send(b)
+}
+
+// Accept never fails: a stream problem must not halt the VM.
+func (s *stream) Accept(blk *chain.ExecutedBlock) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	active := s.server.Connections()
+	watched := set.Set[codec.Address]{}
+	targets := set.Set[ids.ID]{}
+	wantLogs := false
+	for c, sub := range s.subs {
+		if !active.Has(c) {
+			delete(s.subs, c)
+			continue
+		}
+		watched.Union(sub.watch)
+		wantLogs = wantLogs || sub.logs != nil
+		if sub.notify != nil {
+			targets.Add(*sub.notify)
+		}
+	}
+	if len(s.subs) == 0 {
+		return nil
+	}
+
+	header := &StreamEvent{
+		Type: StreamBlockEvent,
+		Block: &StreamBlock{
+			Height:    blk.Block.Hght,
+			BlockID:   blk.BlockID,
+			Parent:    blk.Block.Prnt,
+			Timestamp: blk.Block.Tmstmp,
+			StateRoot: blk.Block.StateRoot,
+			TxCount:   len(blk.Block.Txs),
+		},
+	}
+	var txs []*StreamEvent
+	for i, tx := range blk.Block.Txs {
+		txs = append(txs, &StreamEvent{
+			Type: StreamTxEvent,
+			Tx:   streamTx(blk.Block.Hght, tx, blk.Results[i]),
+		})
+	}
+	changes, err := s.balanceChanges(context.Background(), blk, watched)
+	if err != nil {
+		s.log.Warn("skipping stream balance changes",
+			zap.Uint64("height", blk.Block.Hght),
+			zap.Error(err),
+		)
+	}
+	var logs []*Log
+	if wantLogs {
+		logs, err = blockLogs(blk)
+		if err != nil {
+			s.log.Warn("skipping stream logs",
+				zap.Uint64("height", blk.Block.Hght),
+				zap.Error(err),
+			)
+		}
+	}
+	notifications, err := s.notifications(context.Background(), blk, targets)
+	if err != nil {
+		s.log.Warn("skipping stream notifications",
+			zap.Uint64("height", blk.Block.Hght),
+			zap.Error(err),
+		)
+	}
+
+	for c, sub := range s.subs {
+		if sub.blocks {
+			s.send(c, header)
+		}
+		if sub.txs {
+			for _, e := range txs {
+				s.send(c, e)
+			}
+		}
+		for _, change := range changes {
+			if sub.watch.Contains(change.Address) {
+				s.send(c, &StreamEvent{Type: StreamBalanceEvent, Balance: change})
+			}
+		}
+		if sub.logs != nil {
+			for _, l := range logs {
+				if sub.logs.match(l) {
+					s.send(c, &StreamEvent{Type: StreamLogEvent, Log: l})
+				}
+			}
+		}
+		if sub.notify != nil {
+			for _, n := range notifications {
+				if n.Target == *sub.notify {
+					s.send(c, &StreamEvent{Type: StreamNotificationEvent, Notification: n})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func streamTx(height uint64, tx *chain.Transaction, result *chain.Result) *StreamTx {
+	t := &StreamTx{
+		Height:  height,
+		TxID:    tx.ID(),
+		Actor:   tx.Auth.Actor(),
+		Success: result.Success,
+		Error:   string(result.Error),
+		Fee:     result.Fee,
+		Outputs: make([]StreamOutput, len(result.Outputs)),
+	}
+	for i, b := range result.Outputs {
+		t.Outputs[i].Bytes = b
+		typed, err := OutputParser.Unmarshal(codec.NewReader(b, len(b)))
+		if err != nil {
+			continue
+		}
+		t.Outputs[i].TypeID = typed.GetTypeID()
+		t.Outputs[i].Output, _ = json.Marshal(typed)
+	}
+	return t
+}
+
+// balanceChanges returns the changed balances of [watched] addresses among
+// those the transactions of [blk] could write.
+func (s *stream) balanceChanges(
+	ctx context.Context,
+	blk *chain.ExecutedBlock,
+	watched set.Set[codec.Address],
+) ([]*BalanceChange, error) {
+	height := blk.Block.Hght
+	if watched.Len() == 0 || height == 0 {
+		return nil, nil
+	}
+	var (
+		keys    [][]byte
+		changes []*BalanceChange
+		seen    = set.Set[string]{}
+	)
+	for _, tx := range blk.Block.Txs {
+		stateKeys, err := tx.StateKeys(&storage.StateManager{})
+		if err != nil {
+			return nil, err
+		}
+		for k, perm := range stateKeys {
+			if !perm.Has(state.Write) || seen.Contains(k) {
+				continue
+			}
+			change, ok := balanceKeyOwner([]byte(k))
+			if !ok || !watched.Contains(change.Address) {
+				continue
+			}
+			seen.Add(k)
+			change.Height = height
+			keys = append(keys, []byte(k))
+			changes = append(changes, change)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	before, err := s.balancesAt(ctx, height-1, keys)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.balancesAt(ctx, height, keys)
+	if err != nil {
+		return nil, err
+	}
+	changed := changes[:0]
+	for i, change := range changes {
+		if before[i] != after[i] {
+			change.Previous, change.Balance = before[i], after[i]
+			changed = append(changed, change)
+		}
+	}
+	return changed, nil
+}
+
+func balanceKeyOwner(k []byte) (*BalanceChange, bool) {
+	if addr, ok := storage.ParseBalanceKey(k); ok {
+		return &BalanceChange{Address: addr, Asset: storage.NativeAsset}, true
+	}
+	if addr, asset, ok := storage.ParseAssetBalanceKey(k); ok {
+		return &BalanceChange{Address: addr, Asset: asset}, true
+	}
+	return nil, false
+}
+
+// parseBalance decodes the value under a key accepted by [balanceKeyOwner].
+func parseBalance(k []byte, v []byte) (uint64, error) {
+	if _, ok := storage.ParseBalanceKey(k); ok {
+		return storage.ParseBalance(v)
+	}
+	return database.ParseUInt64(v)
+}
+
+// stateAt returns a [storage.ReadState] serving values after the block at
+// [height]. The acceptor runs behind consensus, so state may already be
+// ahead of [height].
+func (s *stream) stateAt(height uint64) storage.ReadState {
+	return func(ctx context.Context, keys [][]byte) ([][]byte, []error) {
+		values, errs, currentHeight, err := storage.ReadWithHeight(ctx, s.readState, keys)
+		switch {
+		case err != nil:
+			return values, utils.Repeat(err, len(keys))
+		case currentHeight < height:
+			err := fmt.Errorf("%w: height=%d, lastAccepted=%d", ErrHeightNotAccepted, height, currentHeight)
+			return values, utils.Repeat(err, len(keys))
+		case currentHeight > height:
+			return readHistorical(ctx, s.history, height, keys)
+		default:
+			return values, errs
+		}
+	}
+}
+
+// balancesAt reads the balances under [keys] after the block at [height].
+func (s *stream) balancesAt(ctx context.Context, height uint64, keys [][]byte) ([]uint64, error) {
+	values, errs := s.stateAt(height)(ctx, keys)
+	balances := make([]uint64, len(keys))
+	var err error
+	for i := range keys {
+		if errs[i] != nil && !errors.Is(errs[i], database.ErrNotFound) {
+			return nil, errs[i]
+		}
+		if errs[i] == nil {
+			balances[i], err = parseBalance(keys[i], values[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return balances, nil
+}
+
+func (s *stream) Close() error {
+	return nil
+}
+
+type streamHandlerFactory struct {
+	stream *stream
+}
+
+func (f streamHandlerFactory) New(api.VM) (api.Handler, error) {
+	return api.Handler{
+		Path:    StreamEndpoint,
+		Handler: f.stream.server,
+	}, nil
+}
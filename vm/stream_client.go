@@ -0,0 +1,89 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ava-labs/hypersdk/api/ws"
+	"github.com/ava-labs/hypersdk/pubsub"
+)
+
+// StreamClient reads the event stream served at [StreamEndpoint].
+type StreamClient struct {
+	conn    *websocket.Conn
+	pending []*StreamEvent
+}
+
+// NewStreamClient dials the stream of the chain served at [uri].
+func NewStreamClient(ctx context.Context, uri string) (*StreamClient, error) {
+	uri = strings.Replace(uri, "http://", "ws://", 1)
+	uri = strings.Replace(uri, "https://", "wss://", 1)
+	if !strings.HasPrefix(uri, "ws") {
+		uri = "ws://" + uri
+	}
+	uri = strings.TrimSuffix(uri, "/") + StreamEndpoint
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: ws.DefaultHandshakeTimeout,
+	}
+	conn, resp, err := dialer.DialContext(ctx, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return &StreamClient{conn: conn}, nil
+}
+
+// Subscribe replaces the subscriptions of the connection.
+func (c *StreamClient) Subscribe(req *StreamRequest) error {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	msg, err := pubsub.CreateBatchMessage(pubsub.MaxReadMessageSize, [][]byte{b})
+	if err != nil {
+		return err
+	}
+	return c.conn.WriteMessage(websocket.BinaryMessage, msg)
+}
+
+// Query runs [q]. Its pages arrive through Next, interleaved with
+// subscribed events.
+func (c *StreamClient) Query(q *StreamQuery) error {
+	return c.Subscribe(&StreamRequest{Query: q})
+}
+
+// Next blocks until the next event arrives.
+func (c *StreamClient) Next() (*StreamEvent, error) {
+	for len(c.pending) == 0 {
+		_, msg, err := c.conn.ReadMessage()
+		if err != nil {
+			return nil, err
+		}
+		msgs, err := pubsub.ParseBatchMessage(pubsub.MaxWriteMessageSize, msg)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range msgs {
+			var e StreamEvent
+			if err := json.Unmarshal(m, &e); err != nil {
+				return nil, err
+			}
+			c.pending = append(c.pending, &e)
+		}
+	}
+	e := c.pending[0]
+	c.pending = c.pending[1:]
+	return e, nil
+}
+
+func (c *StreamClient) Close() error {
+	return c.conn.Close()
+}
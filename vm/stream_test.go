@@ -0,0 +1,123 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+func TestStreamBalanceChanges(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	_, db := newTestLists(t)
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	signer := &auth.ED25519{Signer: priv.PublicKey()}
+	sender := signer.Actor()
+	to := codectest.NewRandomAddress()
+	other := codectest.NewRandomAddress()
+
+	// commit writes [balances] at [height], encoded the way storage does.
+	history := rootHistory{merkleHistory: merkleHistory{db}, roots: make(map[uint64]ids.ID)}
+	commit := func(height uint64, balances map[codec.Address]uint64) {
+		encoded := chaintest.NewInMemoryStore()
+		batch := db.NewBatch()
+		for addr, balance := range balances {
+			require.NoError(storage.SetBalance(ctx, encoded, addr, balance))
+			v, err := encoded.GetValue(ctx, storage.BalanceKey(addr))
+			require.NoError(err)
+			require.NoError(batch.Put(storage.BalanceKey(addr), v))
+		}
+		require.NoError(batch.Put(chain.HeightKey(storage.HeightKey()), binary.BigEndian.AppendUint64(nil, height)))
+		require.NoError(batch.Write())
+		root, err := db.GetMerkleRoot(ctx)
+		require.NoError(err)
+		history.roots[height] = root
+	}
+	commit(1, map[codec.Address]uint64{sender: 100, other: 10})
+	commit(2, map[codec.Address]uint64{sender: 95, to: 5})
+	s := &stream{readState: stateVM{history: history}.ReadState, history: history}
+
+	transfer := func(to codec.Address) *chain.Transaction {
+		return &chain.Transaction{Actions: []chain.Action{&actions.Transfer{To: to, Value: 5}}, Auth: signer}
+	}
+	blk := &chain.ExecutedBlock{Block: &chain.StatelessBlock{
+		Hght: 2,
+		Txs:  []*chain.Transaction{transfer(to), transfer(to)},
+	}}
+
+	// Balances are compared before and after the block, once per key.
+	changes, err := s.balanceChanges(ctx, blk, set.Of(sender, to))
+	require.NoError(err)
+	require.ElementsMatch([]*BalanceChange{
+		{Height: 2, Address: sender, Asset: storage.NativeAsset, Previous: 100, Balance: 95},
+		{Height: 2, Address: to, Asset: storage.NativeAsset, Balance: 5},
+	}, changes)
+
+	// Only watched addresses are reported.
+	changes, err = s.balanceChanges(ctx, blk, set.Of(to))
+	require.NoError(err)
+	require.Len(changes, 1)
+	require.Equal(to, changes[0].Address)
+
+	// Balances the block could write but did not change are left out.
+	blk.Block.Txs = []*chain.Transaction{transfer(other)}
+	changes, err = s.balanceChanges(ctx, blk, set.Of(other))
+	require.NoError(err)
+	require.Empty(changes)
+
+	// Once state moved past the block, it is read from history.
+	commit(3, map[codec.Address]uint64{sender: 0})
+	blk.Block.Txs = []*chain.Transaction{transfer(to)}
+	changes, err = s.balanceChanges(ctx, blk, set.Of(sender))
+	require.NoError(err)
+	require.Equal([]*BalanceChange{
+		{Height: 2, Address: sender, Asset: storage.NativeAsset, Previous: 100, Balance: 95},
+	}, changes)
+}
+
+func TestStreamTx(t *testing.T) {
+	require := require.New(t)
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	signer := &auth.ED25519{Signer: priv.PublicKey()}
+	tx := &chain.Transaction{Actions: []chain.Action{&actions.Transfer{Value: 5}}, Auth: signer}
+	output, err := chain.MarshalTyped(&actions.TransferResult{SenderBalance: 95, ReceiverBalance: 5})
+	require.NoError(err)
+
+	// Outputs are decoded for the client, and kept as bytes too.
+	st := streamTx(7, tx, &chain.Result{Success: true, Fee: 3, Outputs: [][]byte{output, {0xff}}})
+	require.Equal(uint64(7), st.Height)
+	require.Equal(tx.ID(), st.TxID)
+	require.Equal(signer.Actor(), st.Actor)
+	require.Equal(uint64(3), st.Fee)
+	require.Len(st.Outputs, 2)
+	require.Equal(mconsts.TransferID, st.Outputs[0].TypeID)
+	var result actions.TransferResult
+	require.NoError(json.Unmarshal(st.Outputs[0].Output, &result))
+	require.Equal(uint64(95), result.SenderBalance)
+
+	// Outputs that do not decode are sent as bytes only.
+	require.Equal(codec.Bytes{0xff}, st.Outputs[1].Bytes)
+	require.Nil(st.Outputs[1].Output)
+}
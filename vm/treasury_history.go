@@ -0,0 +1,217 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/pebbledb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/event"
+)
+
+// MaxTreasuryHistoryPage bounds the movements returned by one
+// TreasuryHistory call.
+const MaxTreasuryHistoryPage = 100
+
+const (
+	TreasuryDeposit = "deposit"
+	TreasurySpend   = "spend"
+)
+
+var ErrTreasuryHistoryUnavailable = errors.New("treasury history unavailable")
+
+var (
+	_ event.SubscriptionFactory[*chain.ExecutedBlock] = (*treasuryHistory)(nil)
+	_ event.Subscription[*chain.ExecutedBlock]        = (*treasuryHistory)(nil)
+
+	// Movements are stored under their big-endian index, which never has
+	// the length of these keys.
+	treasuryCountKey  = []byte{0x0}
+	treasuryHeightKey = []byte{0x1}
+)
+
+// TreasuryMovement is a transfer into or out of [storage.TreasuryAddress].
+type TreasuryMovement struct {
+	Height uint64 `json:"height"`
+	TxID   ids.ID `json:"txId"`
+	Kind   string `json:"kind"`
+	// Account is the depositor of a deposit and the recipient of a spend.
+	Account codec.Address `json:"account"`
+	Amount  uint64        `json:"amount"`
+	// ProposalID is set on spends.
+	ProposalID ids.ID `json:"proposalId"`
+}
+
+// treasuryHistory indexes the treasury movements of accepted blocks.
+type treasuryHistory struct {
+	db database.Database
+}
+
+func newTreasuryHistory(path string, log logging.Logger) (*treasuryHistory, error) {
+	db, err := pebbledb.New(path, nil, log, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &treasuryHistory{db: db}, nil
+}
+
+func treasuryHistoryPath(dataDir string) string {
+	return filepath.Join(dataDir, Namespace, "treasury")
+}
+
+func (t *treasuryHistory) New() (event.Subscription[*chain.ExecutedBlock], error) {
+	return t, nil
+}
+
+func (t *treasuryHistory) Accept(blk *chain.ExecutedBlock) error {
+	height := blk.Block.Hght
+	last, err := getUint64(t.db, treasuryHeightKey)
+	if err != nil {
+		return err
+	}
+	if height <= last && height != 0 {
+		// Already indexed before a restart.
+		return nil
+	}
+	count, err := getUint64(t.db, treasuryCountKey)
+	if err != nil {
+		return err
+	}
+	batch := t.db.NewBatch()
+	for i, tx := range blk.Block.Txs {
+		result := blk.Results[i]
+		if !result.Success {
+			continue
+		}
+		for _, m := range treasuryMovements(tx, result) {
+			m.Height = height
+			b, err := json.Marshal(m)
+			if err != nil {
+				return err
+			}
+			if err := batch.Put(binary.BigEndian.AppendUint64(nil, count), b); err != nil {
+				return err
+			}
+			count++
+		}
+	}
+	if err := batch.Put(treasuryCountKey, binary.BigEndian.AppendUint64(nil, count)); err != nil {
+		return err
+	}
+	if err := batch.Put(treasuryHeightKey, binary.BigEndian.AppendUint64(nil, height)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// treasuryMovements returns the movements made by the actions of a
+// successful [tx].
+func treasuryMovements(tx *chain.Transaction, result *chain.Result) []*TreasuryMovement {
+	var (
+		movements []*TreasuryMovement
+		actor     = tx.Auth.Actor()
+		deposit   = func(from codec.Address, to codec.Address, amount uint64) {
+			if to == storage.TreasuryAddress && from != storage.TreasuryAddress {
+				movements = append(movements, &TreasuryMovement{
+					TxID:    tx.ID(),
+					Kind:    TreasuryDeposit,
+					Account: from,
+					Amount:  amount,
+				})
+			}
+		}
+		spend = func(proposalID ids.ID, to codec.Address, output []byte) {
+			typed, err := OutputParser.Unmarshal(codec.NewReader(output, len(output)))
+			if err != nil {
+				return
+			}
+			var executed bool
+			var amount uint64
+			switch r := typed.(type) {
+			case *actions.ProposeTreasurySpendResult:
+				executed, amount = r.Executed, r.Amount
+			case *actions.ApproveTreasurySpendResult:
+				executed, amount = r.Executed, r.Amount
+			}
+			if executed && to != storage.TreasuryAddress {
+				movements = append(movements, &TreasuryMovement{
+					TxID:       tx.ID(),
+					Kind:       TreasurySpend,
+					Account:    to,
+					Amount:     amount,
+					ProposalID: proposalID,
+				})
+			}
+		}
+	)
+	for i, action := range tx.Actions {
+		switch a := action.(type) {
+		case *actions.Transfer:
+			deposit(actor, a.To, a.Value)
+		case *actions.TransferFrom:
+			deposit(a.From, a.To, a.Value)
+		case *actions.BatchTransfer:
+			for _, e := range a.Transfers {
+				deposit(actor, e.To, e.Value)
+			}
+		case *actions.ProposeTreasurySpend:
+			spend(a.ProposalID, a.To, result.Outputs[i])
+		case *actions.ApproveTreasurySpend:
+			spend(a.ProposalID, a.To, result.Outputs[i])
+		}
+	}
+	return movements
+}
+
+// Page returns up to [limit] movements older than [cursor], newest first,
+// and the cursor of the next page. A zero [cursor] starts from the newest
+// movement; a zero next cursor means there are no older movements.
+func (t *treasuryHistory) Page(cursor uint64, limit int) ([]*TreasuryMovement, uint64, error) {
+	count, err := getUint64(t.db, treasuryCountKey)
+	if err != nil {
+		return nil, 0, err
+	}
+	if cursor == 0 || cursor > count {
+		cursor = count
+	}
+	movements := make([]*TreasuryMovement, 0, min(uint64(limit), cursor))
+	for ; cursor > 0 && len(movements) < limit; cursor-- {
+		b, err := t.db.Get(binary.BigEndian.AppendUint64(nil, cursor-1))
+		if err != nil {
+			return nil, 0, err
+		}
+		var m TreasuryMovement
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, 0, err
+		}
+		movements = append(movements, &m)
+	}
+	return movements, cursor, nil
+}
+
+func (t *treasuryHistory) Close() error {
+	return t.db.Close()
+}
+
+func getUint64(db database.KeyValueReader, k []byte) (uint64, error) {
+	v, err := db.Get(k)
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return database.ParseUInt64(v)
+}
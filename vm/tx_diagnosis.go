@@ -0,0 +1,168 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/fees"
+)
+
+// Reasons of a [TxProblem].
+const (
+	// TxMalformed is a transaction that does not decode, or whose actions
+	// or state keys the VM rejects.
+	TxMalformed = "malformed"
+	// TxBadSignature is a transaction whose auth does not verify.
+	TxBadSignature = "badSignature"
+	// TxExpiry is a transaction whose timestamp has passed, is beyond the
+	// validity window or is not a whole second, or that is for another
+	// chain.
+	TxExpiry = "expiry"
+	// TxDuplicate is a transaction already accepted within the validity
+	// window, or already in the mempool.
+	TxDuplicate = "duplicate"
+	// TxInsufficientBalance is a transaction whose sponsor cannot pay its
+	// fee at the current unit prices.
+	TxInsufficientBalance = "insufficientBalance"
+	// TxHalted is a transaction with an action other than governance that
+	// fails while the chain is halted.
+	TxHalted = "halted"
+)
+
+var (
+	ErrTxExtraBytes = errors.New("transaction has extra bytes")
+	ErrTxInMempool  = errors.New("transaction already in mempool")
+)
+
+// TxProblem is a reason the VM would not admit a transaction.
+type TxProblem struct {
+	Reason string `json:"reason"`
+	Error  string `json:"error"`
+}
+
+// txPool is implemented by the hypersdk VM, which the [JSONRPCServer] is
+// handed without screening.
+type txPool interface {
+	IsRepeat(ctx context.Context, txs []*chain.Transaction, marker set.Bits, stop bool) set.Bits
+	Mempool() chain.Mempool
+}
+
+type mempoolLookup interface {
+	Has(ctx context.Context, txID ids.ID) bool
+}
+
+// diagnoseTx runs the checks the VM makes when [b] is submitted, against
+// the last accepted state, and writes what it finds to [reply]. Checks that
+// need a decoded transaction are skipped if it is malformed.
+//
+// Screening and txcheck plugins are not run. The fee is computed at the
+// current unit prices, which may move by the next block.
+func (j *JSONRPCServer) diagnoseTx(ctx context.Context, b []byte, reply *DiagnoseTxReply) error {
+	problem := func(reason string, err error) {
+		reply.Problems = append(reply.Problems, TxProblem{Reason: reason, Error: err.Error()})
+	}
+
+	p := codec.NewReader(b, len(b))
+	tx, err := chain.UnmarshalTx(p, j.vm.ActionCodec(), j.vm.AuthCodec())
+	if err == nil && !p.Empty() {
+		err = ErrTxExtraBytes
+	}
+	if err != nil {
+		problem(TxMalformed, err)
+		return nil
+	}
+	reply.TxID = tx.ID()
+	reply.Expiry = tx.Expiry()
+	reply.Actor = tx.Auth.Actor()
+	reply.Sponsor = tx.Sponsor()
+	reply.Actions = make([]TxAction, len(tx.Actions))
+	for i, action := range tx.Actions {
+		reply.Actions[i].TypeID = action.GetTypeID()
+		reply.Actions[i].Action, _ = json.Marshal(action)
+	}
+
+	if err := tx.Verify(ctx); err != nil {
+		problem(TxBadSignature, err)
+	}
+	now := time.Now().UnixMilli()
+	rules := j.vm.Rules(now)
+	if err := tx.Base.Execute(rules, now); err != nil {
+		problem(TxExpiry, err)
+	}
+	if len(tx.Actions) > int(rules.GetMaxActionsPerTx()) {
+		problem(TxMalformed, chain.ErrTooManyActions)
+	}
+	for i, action := range tx.Actions {
+		start, end := action.ValidRange(rules)
+		if (start >= 0 && now < start) || (end >= 0 && now > end) {
+			problem(TxMalformed, fmt.Errorf("%w: action type %d at index %d", chain.ErrActionNotActivated, action.GetTypeID(), i))
+		}
+	}
+	sm := &storage.StateManager{}
+	if _, err := tx.StateKeys(sm); err != nil {
+		problem(TxMalformed, err)
+	}
+	if pool, ok := j.vm.(txPool); ok {
+		mempool, _ := pool.Mempool().(mempoolLookup)
+		switch {
+		case pool.IsRepeat(ctx, []*chain.Transaction{tx}, set.NewBits(), true).Contains(0):
+			problem(TxDuplicate, fmt.Errorf("%w: accepted within the validity window", chain.ErrDuplicateTx))
+		case mempool != nil && mempool.Has(ctx, tx.ID()):
+			problem(TxDuplicate, ErrTxInMempool)
+		}
+	}
+
+	units, err := tx.Units(sm, rules)
+	if err != nil {
+		problem(TxMalformed, err)
+		return nil
+	}
+	unitPrices, err := j.vm.UnitPrices(ctx)
+	if err != nil {
+		return err
+	}
+	reply.Fee, err = fees.MulSum(unitPrices, units)
+	if err != nil {
+		return err
+	}
+	im, err := j.vm.ImmutableState(ctx)
+	if err != nil {
+		return err
+	}
+	reply.Balance, err = storage.GetBalance(ctx, im, reply.Sponsor)
+	if err != nil {
+		return err
+	}
+	if err := storage.CheckNotHalted(ctx, im); err != nil {
+		if !errors.Is(err, storage.ErrChainHalted) {
+			return err
+		}
+		// Actions that stop while halted declare the halt key.
+		for i, action := range tx.Actions {
+			if _, ok := action.StateKeys(reply.Actor)[string(storage.HaltKey())]; ok {
+				problem(TxHalted, fmt.Errorf("%w: action type %d at index %d", err, action.GetTypeID(), i))
+				break
+			}
+		}
+	}
+	err = sm.CanDeduct(ctx, reply.Sponsor, im, reply.Fee)
+	switch {
+	case errors.Is(err, storage.ErrInvalidBalance):
+		problem(TxInsufficientBalance, fmt.Errorf("%w: fee %d above balance %d", err, reply.Fee, reply.Balance))
+	case err != nil:
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/genesis"
+)
+
+var (
+	_ genesis.GenesisAndRuleFactory = (*UpgradeFactory)(nil)
+	_ genesis.RuleFactory           = (*UpgradeFactory)(nil)
+)
+
+var (
+	ErrInvalidUpgrades    = errors.New("invalid upgrade schedule")
+	ErrUnsupportedUpgrade = errors.New("unsupported upgrade")
+)
+
+// supportedUpgrades lists the upgrades this binary implements. A release that
+// changes behaviour at an activation adds the upgrade name here.
+var supportedUpgrades = set.Set[string]{}
+
+// Upgrade schedules a change of rules.
+type Upgrade struct {
+	Name string `json:"name"`
+
+	// ActivationTime is the block timestamp, in milliseconds, from which the
+	// upgrade is active.
+	ActivationTime int64 `json:"activationTime"`
+
+	// Rules overrides fields of the rules in effect before the upgrade.
+	Rules json.RawMessage `json:"rules,omitempty"`
+}
+
+// UpgradeConfig is the format of the chain's upgrade bytes.
+type UpgradeConfig struct {
+	Upgrades []Upgrade `json:"upgrades"`
+}
+
+// UpgradeFactory loads the [Genesis] and applies the upgrade schedule
+// from the chain's upgrade bytes on top of its rules.
+//
+// Load refuses to start once an upgrade this binary does not support has
+// activated, rather than silently validating with stale rules.
+type UpgradeFactory struct {
+	upgrades []Upgrade
+	// rules[0] are the genesis rules, rules[i+1] apply from upgrades[i].
+	rules []*Rules
+}
+
+func (f *UpgradeFactory) Load(
+	genesisBytes []byte,
+	upgradeBytes []byte,
+	networkID uint32,
+	chainID ids.ID,
+) (genesis.Genesis, genesis.RuleFactory, error) {
+	g, err := parseGenesis(genesisBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	g.Rules.NetworkID = networkID
+	g.Rules.ChainID = chainID
+	var config UpgradeConfig
+	if len(upgradeBytes) > 0 {
+		if err := json.Unmarshal(upgradeBytes, &config); err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidUpgrades, err)
+		}
+	}
+
+	var (
+		now   = time.Now().UnixMilli()
+		names = set.NewSet[string](len(config.Upgrades))
+		rules = []*Rules{g.Rules}
+	)
+	for i, u := range config.Upgrades {
+		if names.Contains(u.Name) {
+			return nil, nil, fmt.Errorf("%w: %q scheduled twice", ErrInvalidUpgrades, u.Name)
+		}
+		names.Add(u.Name)
+		if i > 0 && u.ActivationTime <= config.Upgrades[i-1].ActivationTime {
+			return nil, nil, fmt.Errorf("%w: %q does not activate after %q", ErrInvalidUpgrades, u.Name, config.Upgrades[i-1].Name)
+		}
+		if !supportedUpgrades.Contains(u.Name) && u.ActivationTime <= now {
+			return nil, nil, fmt.Errorf("%w: %q activated at %d, update the node", ErrUnsupportedUpgrade, u.Name, u.ActivationTime)
+		}
+		next := rules[len(rules)-1].copy()
+		if len(u.Rules) > 0 {
+			if err := json.Unmarshal(u.Rules, next); err != nil {
+				return nil, nil, fmt.Errorf("%w: rules of %q: %w", ErrInvalidUpgrades, u.Name, err)
+			}
+		}
+		if err := next.verify(); err != nil {
+			return nil, nil, fmt.Errorf("%w: rules of %q: %w", ErrInvalidUpgrades, u.Name, err)
+		}
+		next.NetworkID = networkID
+		next.ChainID = chainID
+		rules = append(rules, next)
+	}
+	f.upgrades = config.Upgrades
+	f.rules = rules
+	return g, f, nil
+}
+
+func (f *UpgradeFactory) GetRules(t int64) chain.Rules {
+	return f.rules[f.activated(t)]
+}
+
+// activated returns how many upgrades are active at [t].
+func (f *UpgradeFactory) activated(t int64) int {
+	return sort.Search(len(f.upgrades), func(i int) bool {
+		return f.upgrades[i].ActivationTime > t
+	})
+}
+
+type UpgradeStatus struct {
+	Name           string `json:"name"`
+	ActivationTime int64  `json:"activationTime"`
+	Active         bool   `json:"active"`
+	// Supported is false if this binary must be updated before
+	// [ActivationTime].
+	Supported bool `json:"supported"`
+}
+
+// Status reports every scheduled upgrade as of [t].
+func (f *UpgradeFactory) Status(t int64) []UpgradeStatus {
+	active := f.activated(t)
+	statuses := make([]UpgradeStatus, len(f.upgrades))
+	for i, u := range f.upgrades {
+		statuses[i] = UpgradeStatus{
+			Name:           u.Name,
+			ActivationTime: u.ActivationTime,
+			Active:         i < active,
+			Supported:      supportedUpgrades.Contains(u.Name),
+		}
+	}
+	return statuses
+}
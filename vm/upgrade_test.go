@@ -0,0 +1,93 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+func upgradeBytes(t *testing.T, upgrades ...Upgrade) []byte {
+	b, err := json.Marshal(&UpgradeConfig{Upgrades: upgrades})
+	require.NoError(t, err)
+	return b
+}
+
+func TestUpgradeSchedule(t *testing.T) {
+	require := require.New(t)
+	supportedUpgrades.Add("past")
+	t.Cleanup(func() { supportedUpgrades.Remove("past") })
+
+	future := time.Now().Add(time.Hour).UnixMilli()
+	f := &UpgradeFactory{}
+	_, _, err := f.Load(genesisBytes(t), upgradeBytes(t,
+		Upgrade{Name: "past", ActivationTime: 1_000, Rules: json.RawMessage(`{"maxMemoSize": 300}`)},
+		Upgrade{Name: "future", ActivationTime: future, Rules: json.RawMessage(`{"maxMemoSize": 512}`)},
+	), 5, ids.GenerateTestID())
+	require.NoError(err)
+
+	// Each upgrade overrides the rules in effect before it.
+	memoSize := func(r chain.Rules) any {
+		v, _ := r.FetchCustom(actions.MaxMemoSizeRule)
+		return v
+	}
+	require.Equal(actions.MaxMemoSize, memoSize(f.GetRules(999)))
+	require.Equal(300, memoSize(f.GetRules(1_000)))
+	require.Equal(512, memoSize(f.GetRules(future)))
+	require.Equal(uint32(5), f.GetRules(future).GetNetworkID())
+
+	// A node can start before an upgrade it does not support activates,
+	// but reports it is not ready for it.
+	require.Equal([]UpgradeStatus{
+		{Name: "past", ActivationTime: 1_000, Active: true, Supported: true},
+		{Name: "future", ActivationTime: future},
+	}, f.Status(future-1))
+	reply := new(UpgradesReply)
+	require.NoError((&JSONRPCServer{upgrades: f}).Upgrades(nil, nil, reply))
+	require.False(reply.Ready)
+	require.Len(reply.Upgrades, 2)
+}
+
+func TestUpgradeScheduleErrors(t *testing.T) {
+	future := time.Now().Add(time.Hour).UnixMilli()
+	tests := []struct {
+		name      string
+		upgrades  []Upgrade
+		expectErr error
+	}{
+		{
+			name:      "UnsupportedActivated",
+			upgrades:  []Upgrade{{Name: "unknown", ActivationTime: 1_000}},
+			expectErr: ErrUnsupportedUpgrade,
+		},
+		{
+			name:      "ScheduledTwice",
+			upgrades:  []Upgrade{{Name: "next", ActivationTime: future}, {Name: "next", ActivationTime: future + 1}},
+			expectErr: ErrInvalidUpgrades,
+		},
+		{
+			name:      "OutOfOrder",
+			upgrades:  []Upgrade{{Name: "first", ActivationTime: future + 1}, {Name: "second", ActivationTime: future}},
+			expectErr: ErrInvalidUpgrades,
+		},
+		{
+			name:      "InvalidRules",
+			upgrades:  []Upgrade{{Name: "next", ActivationTime: future, Rules: json.RawMessage(`{"memoBytesPerComputeUnit": 0}`)}},
+			expectErr: ErrInvalidUpgrades,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := (&UpgradeFactory{}).Load(genesisBytes(t), upgradeBytes(t, tt.upgrades...), 5, ids.GenerateTestID())
+			require.ErrorIs(t, err, tt.expectErr)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/pebbledb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/event"
+	"github.com/ava-labs/hypersdk/fees"
+)
+
+var ErrBlockUsageUnavailable = errors.New("block usage unavailable")
+
+var (
+	_ event.SubscriptionFactory[*chain.ExecutedBlock] = (*usage)(nil)
+	_ event.Subscription[*chain.ExecutedBlock]        = (*usage)(nil)
+)
+
+// ActionUsage is the share of a block consumed by one action type.
+type ActionUsage struct {
+	Actions int `json:"actions"`
+	// Units splits the units of each transaction evenly between its
+	// actions, so the units of all action types add up to the block's.
+	Units fees.Dimensions `json:"units"`
+}
+
+// PriceChanges is the signed change of each unit price from the parent
+// block.
+type PriceChanges struct {
+	Bandwidth       int64 `json:"bandwidth"`
+	Compute         int64 `json:"compute"`
+	StorageRead     int64 `json:"storageRead"`
+	StorageAllocate int64 `json:"storageAllocate"`
+	StorageWrite    int64 `json:"storageWrite"`
+}
+
+// BlockUsage reports the units consumed by a block and how the fee market
+// responded.
+type BlockUsage struct {
+	Height    uint64 `json:"height"`
+	BlockID   ids.ID `json:"blockId"`
+	Timestamp int64  `json:"timestamp"`
+	Txs       int    `json:"txs"`
+	FailedTxs int    `json:"failedTxs"`
+	// Fees is the sum of the fees paid by the block's transactions.
+	Fees uint64 `json:"fees"`
+
+	// Units are consumed by all transactions, including failed ones.
+	Units fees.Dimensions `json:"units"`
+	// MaxUnits is the block limit and TargetUnits the per-window target
+	// that unit prices move towards, under the rules of the block.
+	MaxUnits    fees.Dimensions `json:"maxUnits"`
+	TargetUnits fees.Dimensions `json:"targetUnits"`
+
+	// Actions are keyed by action type, such as "Transfer".
+	Actions map[string]*ActionUsage `json:"actions"`
+
+	// UnitPrices are the prices the block's transactions paid.
+	UnitPrices fees.Dimensions `json:"unitPrices"`
+	// PriceChanges is unset if the parent block was not recorded.
+	PriceChanges *PriceChanges `json:"priceChanges,omitempty"`
+}
+
+// ruleSource returns the rules in effect at a timestamp.
+type ruleSource interface {
+	Rules(t int64) chain.Rules
+}
+
+// usage records the [BlockUsage] of each accepted block.
+type usage struct {
+	db     database.Database
+	rules  ruleSource
+	window uint64
+}
+
+func newUsage(path string, rules ruleSource, log logging.Logger, window uint64) (*usage, error) {
+	db, err := pebbledb.New(path, nil, log, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &usage{
+		db:     db,
+		rules:  rules,
+		window: window,
+	}, nil
+}
+
+func usagePath(dataDir string) string {
+	return filepath.Join(dataDir, Namespace, "usage")
+}
+
+func (u *usage) New() (event.Subscription[*chain.ExecutedBlock], error) {
+	return u, nil
+}
+
+func (u *usage) Accept(blk *chain.ExecutedBlock) error {
+	height := blk.Block.Hght
+	bu := blockUsage(blk, u.rules.Rules(blk.Block.Tmstmp))
+	if height > 0 {
+		parent, err := u.Get(height - 1)
+		switch {
+		case err == nil:
+			bu.PriceChanges = priceChanges(parent.UnitPrices, bu.UnitPrices)
+		case !errors.Is(err, ErrBlockUsageUnavailable):
+			return err
+		}
+	}
+	b, err := json.Marshal(bu)
+	if err != nil {
+		return err
+	}
+	if err := u.db.Put(binary.BigEndian.AppendUint64(nil, height), b); err != nil {
+		return err
+	}
+	if height < u.window {
+		return nil
+	}
+	return u.db.Delete(binary.BigEndian.AppendUint64(nil, height-u.window))
+}
+
+// blockUsage tallies the units of [blk], which ran under [r].
+func blockUsage(blk *chain.ExecutedBlock, r chain.Rules) *BlockUsage {
+	bu := &BlockUsage{
+		Height:      blk.Block.Hght,
+		BlockID:     blk.BlockID,
+		Timestamp:   blk.Block.Tmstmp,
+		Txs:         len(blk.Block.Txs),
+		MaxUnits:    r.GetMaxBlockUnits(),
+		TargetUnits: r.GetWindowTargetUnits(),
+		Actions:     make(map[string]*ActionUsage),
+		UnitPrices:  blk.UnitPrices,
+	}
+	for i, tx := range blk.Block.Txs {
+		result := blk.Results[i]
+		if !result.Success {
+			bu.FailedTxs++
+		}
+		bu.Fees += result.Fee
+		for d := range bu.Units {
+			bu.Units[d] += result.Units[d]
+		}
+		n := uint64(len(tx.Actions))
+		for j, action := range tx.Actions {
+			name := reflect.TypeOf(action).Elem().Name()
+			au, ok := bu.Actions[name]
+			if !ok {
+				au = &ActionUsage{}
+				bu.Actions[name] = au
+			}
+			au.Actions++
+			for d, units := range result.Units {
+				au.Units[d] += units / n
+				if j == 0 {
+					au.Units[d] += units % n
+				}
+			}
+		}
+	}
+	return bu
+}
+
+func priceChanges(parent fees.Dimensions, prices fees.Dimensions) *PriceChanges {
+	change := func(d fees.Dimension) int64 {
+		return int64(prices[d]) - int64(parent[d])
+	}
+	return &PriceChanges{
+		Bandwidth:       change(fees.Bandwidth),
+		Compute:         change(fees.Compute),
+		StorageRead:     change(fees.StorageRead),
+		StorageAllocate: change(fees.StorageAllocate),
+		StorageWrite:    change(fees.StorageWrite),
+	}
+}
+
+func (u *usage) Get(height uint64) (*BlockUsage, error) {
+	b, err := u.db.Get(binary.BigEndian.AppendUint64(nil, height))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, fmt.Errorf("%w: height=%d", ErrBlockUsageUnavailable, height)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var bu BlockUsage
+	return &bu, json.Unmarshal(b, &bu)
+}
+
+func (u *usage) Close() error {
+	return u.db.Close()
+}
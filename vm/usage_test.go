@@ -0,0 +1,81 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/fees"
+	"github.com/ava-labs/hypersdk/genesis"
+)
+
+// fixedRules serves the same rules at every timestamp.
+type fixedRules struct {
+	rules chain.Rules
+}
+
+func (r fixedRules) Rules(int64) chain.Rules {
+	return r.rules
+}
+
+func TestBlockUsage(t *testing.T) {
+	require := require.New(t)
+	rules := genesis.NewDefaultRules()
+	u, err := newUsage(t.TempDir(), fixedRules{rules}, logging.NoLog{}, 2)
+	require.NoError(err)
+	defer u.Close()
+
+	block := func(height uint64, prices fees.Dimensions) *chain.ExecutedBlock {
+		return &chain.ExecutedBlock{
+			Block: &chain.StatelessBlock{
+				Hght: height,
+				Txs: []*chain.Transaction{
+					{Actions: []chain.Action{&actions.Transfer{}, &actions.Transfer{}, &actions.AssetTransfer{}}},
+					{Actions: []chain.Action{&actions.AssetTransfer{}}},
+				},
+			},
+			Results: []*chain.Result{
+				{Success: true, Fee: 30, Units: fees.Dimensions{10, 7, 0, 0, 3}},
+				{Fee: 5, Units: fees.Dimensions{1, 1, 1, 1, 1}},
+			},
+			UnitPrices: prices,
+		}
+	}
+
+	require.NoError(u.Accept(block(1, fees.Dimensions{100, 100, 100, 100, 100})))
+	bu, err := u.Get(1)
+	require.NoError(err)
+	require.Equal(2, bu.Txs)
+	require.Equal(1, bu.FailedTxs)
+	require.Equal(uint64(35), bu.Fees)
+	require.Equal(fees.Dimensions{11, 8, 1, 1, 4}, bu.Units)
+	require.Equal(rules.GetMaxBlockUnits(), bu.MaxUnits)
+	require.Equal(rules.GetWindowTargetUnits(), bu.TargetUnits)
+	require.Nil(bu.PriceChanges)
+
+	// The units of a transaction are split between its actions, the
+	// remainder going to the first, so action types add up to the block.
+	require.Equal(map[string]*ActionUsage{
+		"Transfer":      {Actions: 2, Units: fees.Dimensions{7, 5, 0, 0, 2}},
+		"AssetTransfer": {Actions: 2, Units: fees.Dimensions{4, 3, 1, 1, 2}},
+	}, bu.Actions)
+
+	// Prices are compared with the parent block's.
+	require.NoError(u.Accept(block(2, fees.Dimensions{90, 100, 110, 100, 150})))
+	bu, err = u.Get(2)
+	require.NoError(err)
+	require.Equal(&PriceChanges{Bandwidth: -10, StorageRead: 10, StorageWrite: 50}, bu.PriceChanges)
+
+	// Reports leave the window.
+	require.NoError(u.Accept(block(3, fees.Dimensions{})))
+	_, err = u.Get(1)
+	require.ErrorIs(err, ErrBlockUsageUnavailable)
+	_, err = u.Get(3)
+	require.NoError(err)
+}
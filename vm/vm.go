@@ -9,12 +9,14 @@ import (
 	"github.com/ava-labs/hypersdk-starter-kit/actions"
 	"github.com/ava-labs/hypersdk-starter-kit/consts"
 	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/api/indexer"
 	"github.com/ava-labs/hypersdk/auth"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
-	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/extension/externalsubscriber"
 	"github.com/ava-labs/hypersdk/vm"
-	"github.com/ava-labs/hypersdk/vm/defaultvm"
+
+	staterpc "github.com/ava-labs/hypersdk/api/state"
 )
 
 var (
@@ -35,6 +37,72 @@ func init() {
 		// Pass nil as second argument if manual marshalling isn't needed (if in doubt, you probably don't)
 		ActionParser.Register(&actions.Transfer{}, nil),
 		ActionParser.Register(&actions.AssetTransfer{}, nil),
+		ActionParser.Register(&actions.RedeemVoucher{}, nil),
+		ActionParser.Register(&actions.MintAsset{}, nil),
+		ActionParser.Register(&actions.BurnAsset{}, nil),
+		ActionParser.Register(&actions.TransferAsset{}, nil),
+		ActionParser.Register(&actions.SetNotificationPrefs{}, nil),
+		ActionParser.Register(&actions.Approve{}, nil),
+		ActionParser.Register(&actions.TransferFrom{}, nil),
+		ActionParser.Register(&actions.BatchTransfer{}, nil),
+		ActionParser.Register(&actions.ProposeTreasurySpend{}, nil),
+		ActionParser.Register(&actions.ApproveTreasurySpend{}, nil),
+		ActionParser.Register(&actions.SetTransferHook{}, nil),
+		ActionParser.Register(&actions.ProposeSwap{}, nil),
+		ActionParser.Register(&actions.AcceptSwap{}, nil),
+		ActionParser.Register(&actions.RefundSwap{}, nil),
+		ActionParser.Register(&actions.FreezeAsset{}, nil),
+		ActionParser.Register(&actions.UnfreezeAsset{}, nil),
+		ActionParser.Register(&actions.CreateVesting{}, nil),
+		ActionParser.Register(&actions.ClaimVesting{}, nil),
+		ActionParser.Register(&actions.OpenEscrow{}, nil),
+		ActionParser.Register(&actions.ReleaseEscrow{}, nil),
+		ActionParser.Register(&actions.RefundEscrow{}, nil),
+		ActionParser.Register(&actions.CreateOrder{}, nil),
+		ActionParser.Register(&actions.FillOrder{}, nil),
+		ActionParser.Register(&actions.CancelOrder{}, nil),
+		ActionParser.Register(&actions.CreatePool{}, nil),
+		ActionParser.Register(&actions.AddLiquidity{}, nil),
+		ActionParser.Register(&actions.RemoveLiquidity{}, nil),
+		ActionParser.Register(&actions.Swap{}, nil),
+		ActionParser.Register(&actions.Stake{}, nil),
+		ActionParser.Register(&actions.Unstake{}, nil),
+		ActionParser.Register(&actions.ClaimRewards{}, nil),
+		ActionParser.Register(&actions.CreateProposal{}, nil),
+		ActionParser.Register(&actions.Vote{}, nil),
+		ActionParser.Register(&actions.ExecuteProposal{}, nil),
+		ActionParser.Register(&actions.CreateMultisig{}, nil),
+		ActionParser.Register(&actions.ProposeMultisigTx{}, nil),
+		ActionParser.Register(&actions.ApproveMultisigTx{}, nil),
+		ActionParser.Register(&actions.ProcessEpoch{}, nil),
+		ActionParser.Register(&actions.MarkCompaction{}, nil),
+		ActionParser.Register(&actions.SetClaim{}, nil),
+		ActionParser.Register(&actions.AuthorizeSessionKey{}, nil),
+		ActionParser.Register(&actions.RevokeSessionKey{}, nil),
+		ActionParser.Register(&actions.CreateStablecoin{}, nil),
+		ActionParser.Register(&actions.AttestReserves{}, nil),
+		ActionParser.Register(&actions.MintStablecoin{}, nil),
+		ActionParser.Register(&actions.BurnStablecoin{}, nil),
+		ActionParser.Register(&actions.SetStablecoinBlocked{}, nil),
+		ActionParser.Register(&actions.SetStablecoinPaused{}, nil),
+		ActionParser.Register(&actions.HaltChain{}, nil),
+		ActionParser.Register(&actions.ResumeChain{}, nil),
+		ActionParser.Register(&actions.DeploySmartAccount{}, nil),
+		ActionParser.Register(&actions.ExecuteFromSmartAccount{}, nil),
+		ActionParser.Register(&actions.CreateLeaderboard{}, nil),
+		ActionParser.Register(&actions.SubmitScore{}, nil),
+		ActionParser.Register(&actions.OnboardAccount{}, nil),
+		ActionParser.Register(&actions.CreateAsset{}, nil),
+		ActionParser.Register(&actions.CreateLocker{}, nil),
+		ActionParser.Register(&actions.DepositToLocker{}, nil),
+		ActionParser.Register(&actions.UnbundleLocker{}, nil),
+		ActionParser.Register(&actions.SweepExpiredAssets{}, nil),
+		ActionParser.Register(&actions.PermitTransfer{}, nil),
+		ActionParser.Register(&actions.CreateAuction{}, nil),
+		ActionParser.Register(&actions.PlaceBid{}, nil),
+		ActionParser.Register(&actions.SettleAuction{}, nil),
+		ActionParser.Register(&actions.RegisterApp{}, nil),
+		ActionParser.Register(&actions.SetAppCreator{}, nil),
 
 		// When registering new auth, ALWAYS make sure to append at the end.
 		AuthParser.Register(&auth.ED25519{}, auth.UnmarshalED25519),
@@ -43,6 +111,72 @@ func init() {
 
 		OutputParser.Register(&actions.TransferResult{}, nil),
 		OutputParser.Register(&actions.AssetTransferResult{}, nil),
+		OutputParser.Register(&actions.RedeemVoucherResult{}, nil),
+		OutputParser.Register(&actions.MintAssetResult{}, nil),
+		OutputParser.Register(&actions.BurnAssetResult{}, nil),
+		OutputParser.Register(&actions.TransferAssetResult{}, nil),
+		OutputParser.Register(&actions.SetNotificationPrefsResult{}, nil),
+		OutputParser.Register(&actions.ApproveResult{}, nil),
+		OutputParser.Register(&actions.TransferFromResult{}, nil),
+		OutputParser.Register(&actions.BatchTransferResult{}, nil),
+		OutputParser.Register(&actions.ProposeTreasurySpendResult{}, nil),
+		OutputParser.Register(&actions.ApproveTreasurySpendResult{}, nil),
+		OutputParser.Register(&actions.SetTransferHookResult{}, nil),
+		OutputParser.Register(&actions.ProposeSwapResult{}, nil),
+		OutputParser.Register(&actions.AcceptSwapResult{}, nil),
+		OutputParser.Register(&actions.RefundSwapResult{}, nil),
+		OutputParser.Register(&actions.FreezeAssetResult{}, nil),
+		OutputParser.Register(&actions.UnfreezeAssetResult{}, nil),
+		OutputParser.Register(&actions.CreateVestingResult{}, nil),
+		OutputParser.Register(&actions.ClaimVestingResult{}, nil),
+		OutputParser.Register(&actions.OpenEscrowResult{}, nil),
+		OutputParser.Register(&actions.ReleaseEscrowResult{}, nil),
+		OutputParser.Register(&actions.RefundEscrowResult{}, nil),
+		OutputParser.Register(&actions.CreateOrderResult{}, nil),
+		OutputParser.Register(&actions.FillOrderResult{}, nil),
+		OutputParser.Register(&actions.CancelOrderResult{}, nil),
+		OutputParser.Register(&actions.CreatePoolResult{}, nil),
+		OutputParser.Register(&actions.AddLiquidityResult{}, nil),
+		OutputParser.Register(&actions.RemoveLiquidityResult{}, nil),
+		OutputParser.Register(&actions.SwapResult{}, nil),
+		OutputParser.Register(&actions.StakeResult{}, nil),
+		OutputParser.Register(&actions.UnstakeResult{}, nil),
+		OutputParser.Register(&actions.ClaimRewardsResult{}, nil),
+		OutputParser.Register(&actions.CreateProposalResult{}, nil),
+		OutputParser.Register(&actions.VoteResult{}, nil),
+		OutputParser.Register(&actions.ExecuteProposalResult{}, nil),
+		OutputParser.Register(&actions.CreateMultisigResult{}, nil),
+		OutputParser.Register(&actions.ProposeMultisigTxResult{}, nil),
+		OutputParser.Register(&actions.ApproveMultisigTxResult{}, nil),
+		OutputParser.Register(&actions.ProcessEpochResult{}, nil),
+		OutputParser.Register(&actions.MarkCompactionResult{}, nil),
+		OutputParser.Register(&actions.SetClaimResult{}, nil),
+		OutputParser.Register(&actions.AuthorizeSessionKeyResult{}, nil),
+		OutputParser.Register(&actions.RevokeSessionKeyResult{}, nil),
+		OutputParser.Register(&actions.CreateStablecoinResult{}, nil),
+		OutputParser.Register(&actions.AttestReservesResult{}, nil),
+		OutputParser.Register(&actions.MintStablecoinResult{}, nil),
+		OutputParser.Register(&actions.BurnStablecoinResult{}, nil),
+		OutputParser.Register(&actions.SetStablecoinBlockedResult{}, nil),
+		OutputParser.Register(&actions.SetStablecoinPausedResult{}, nil),
+		OutputParser.Register(&actions.HaltChainResult{}, nil),
+		OutputParser.Register(&actions.ResumeChainResult{}, nil),
+		OutputParser.Register(&actions.DeploySmartAccountResult{}, nil),
+		OutputParser.Register(&actions.ExecuteFromSmartAccountResult{}, nil),
+		OutputParser.Register(&actions.CreateLeaderboardResult{}, nil),
+		OutputParser.Register(&actions.SubmitScoreResult{}, nil),
+		OutputParser.Register(&actions.OnboardAccountResult{}, nil),
+		OutputParser.Register(&actions.CreateAssetResult{}, nil),
+		OutputParser.Register(&actions.CreateLockerResult{}, nil),
+		OutputParser.Register(&actions.DepositToLockerResult{}, nil),
+		OutputParser.Register(&actions.UnbundleLockerResult{}, nil),
+		OutputParser.Register(&actions.SweepExpiredAssetsResult{}, nil),
+		OutputParser.Register(&actions.PermitTransferResult{}, nil),
+		OutputParser.Register(&actions.CreateAuctionResult{}, nil),
+		OutputParser.Register(&actions.PlaceBidResult{}, nil),
+		OutputParser.Register(&actions.SettleAuctionResult{}, nil),
+		OutputParser.Register(&actions.RegisterAppResult{}, nil),
+		OutputParser.Register(&actions.SetAppCreatorResult{}, nil),
 	)
 	if errs.Errored() {
 		panic(errs.Err)
@@ -51,15 +185,29 @@ func init() {
 
 // NewWithOptions returns a VM with the specified options
 func New(options ...vm.Option) (*vm.VM, error) {
-	options = append(options, With()) // Add MorpheusVM API
-	return defaultvm.New(
+	upgrades := &UpgradeFactory{}
+	screen := &submitScreen{expiry: newExpiryTracker()}
+	options = append(options,
+		With(upgrades, screen.expiry), // Add MorpheusVM API
+		// The default options, with submissions checked and screened by
+		// the core JSON-RPC and WebSocket APIs.
+		screen.withChecks(),
+		screen.withPreflight(),
+		screen.withScreening(),
+		screen.withJSONRPC(),
+		screen.withWebSocket(),
+		indexer.With(),
+		externalsubscriber.With(),
+		staterpc.With(),
+	)
+	return vm.New(
 		consts.Version,
-		genesis.DefaultGenesisFactory{},
+		upgrades,
 		&storage.StateManager{},
 		ActionParser,
 		AuthParser,
 		OutputParser,
-		auth.Engines(),
+		authEngines(),
 		options...,
 	)
 }